@@ -0,0 +1,60 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/catrutech/celeiro/internal/application"
+	"github.com/catrutech/celeiro/internal/application/accounts"
+	"github.com/spf13/cobra"
+)
+
+var exportOrganizationCmd = &cobra.Command{
+	Use:   "exportOrganization <file.zip>",
+	Short: "Export all of an organization's data to a ZIP archive",
+	Long: strings.TrimSpace(`
+Export all of an organization's data to a ZIP archive: accounts, transactions,
+categories, tags, patterns, budgets, planned entries and their statuses,
+savings goals, snapshots, closed months and the rest of its financial data.
+
+The archive can be loaded into a new or empty organization with
+restoreOrganization.
+`),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		RunExportOrganization(application.GetApplication(), cmd, args)
+	},
+}
+
+func init() {
+	AccountsRootCmd.AddCommand(exportOrganizationCmd)
+
+	exportOrganizationCmd.Flags().Int("organization-id", 0, "Organization to export")
+	exportOrganizationCmd.MarkFlagRequired("organization-id")
+}
+
+func RunExportOrganization(application *application.Application, cmd *cobra.Command, args []string) {
+	organizationID, _ := cmd.Flags().GetInt("organization-id")
+
+	output, err := application.AccountsService.ExportOrganization(context.Background(), accounts.ExportOrganizationInput{
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		fmt.Println("Export failed:", err)
+		return
+	}
+
+	if err := os.WriteFile(args[0], output.Data, 0o600); err != nil {
+		fmt.Println("Error writing file:", err)
+		return
+	}
+
+	fmt.Printf("Exported organization %d (%s) to %s\n", organizationID, output.Manifest.Organization.Name, args[0])
+	fmt.Printf("Schema version: %d\n", output.Manifest.SchemaVersion)
+	fmt.Println("Rows exported:")
+	for _, table := range output.Manifest.Tables {
+		fmt.Printf("- %s: %d\n", table.Name, table.Rows)
+	}
+}
@@ -0,0 +1,68 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/catrutech/celeiro/internal/application"
+	"github.com/catrutech/celeiro/internal/application/accounts"
+	"github.com/spf13/cobra"
+)
+
+var restoreOrganizationCmd = &cobra.Command{
+	Use:   "restoreOrganization <file.zip>",
+	Short: "Restore an exported organization archive",
+	Long: strings.TrimSpace(`
+Restore an archive written by exportOrganization. Every row gets a new ID and
+is attributed to the given user.
+
+By default a new organization is created, with the user as its admin. Pass
+--organization-id to restore into an existing organization instead; it must
+have no financial data yet and the user must belong to it.
+`),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		RunRestoreOrganization(application.GetApplication(), cmd, args)
+	},
+}
+
+func init() {
+	AccountsRootCmd.AddCommand(restoreOrganizationCmd)
+
+	restoreOrganizationCmd.Flags().String("email", "", "Email of the user the restored data belongs to")
+	restoreOrganizationCmd.MarkFlagRequired("email")
+
+	restoreOrganizationCmd.Flags().Int("organization-id", 0, "Empty organization to restore into; omitted creates a new organization")
+	restoreOrganizationCmd.Flags().String("organization-name", "", "Name of the new organization; defaults to the archived name")
+}
+
+func RunRestoreOrganization(application *application.Application, cmd *cobra.Command, args []string) {
+	email, _ := cmd.Flags().GetString("email")
+	organizationID, _ := cmd.Flags().GetInt("organization-id")
+	organizationName, _ := cmd.Flags().GetString("organization-name")
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Println("Error reading file:", err)
+		return
+	}
+
+	output, err := application.AccountsService.RestoreOrganization(context.Background(), accounts.RestoreOrganizationInput{
+		Data:             data,
+		UserEmail:        email,
+		OrganizationID:   organizationID,
+		OrganizationName: organizationName,
+	})
+	if err != nil {
+		fmt.Println("Restore failed:", err)
+		return
+	}
+
+	fmt.Printf("Restored into organization %d for %s (%d)\n", output.OrganizationID, output.User.Email, output.User.UserID)
+	fmt.Println("Rows restored:")
+	for _, table := range output.Tables {
+		fmt.Printf("- %s: %d\n", table.Name, table.Rows)
+	}
+}
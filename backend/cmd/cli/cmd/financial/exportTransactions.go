@@ -0,0 +1,102 @@
+package financial
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/catrutech/celeiro/internal/application"
+	financialApp "github.com/catrutech/celeiro/internal/application/financial"
+	"github.com/spf13/cobra"
+)
+
+var exportTransactionsCmd = &cobra.Command{
+	Use:   "exportTransactions <file>",
+	Short: "Export an organization's transactions to CSV, OFX or XLSX",
+	Long: strings.TrimSpace(`
+Export an organization's transactions, oldest first, with the same filters as
+the transaction list:
+
+  csv   one row per transaction; --decimal-separator "," separates fields
+        with ";" for spreadsheets in Brazilian Portuguese
+  ofx   one statement per account; a ZIP of statements when more than one
+        account is exported
+  xlsx  a spreadsheet with category and tag columns
+
+Transactions are written as they are read, so long periods can be exported
+without holding them in memory.
+`),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		RunExportTransactions(application.GetApplication(), cmd, args)
+	},
+}
+
+func init() {
+	FinancialRootCmd.AddCommand(exportTransactionsCmd)
+
+	exportTransactionsCmd.Flags().Int("organization-id", 0, "Organization whose transactions are exported")
+	exportTransactionsCmd.Flags().String("format", financialApp.TransactionExportCSV, "csv, ofx or xlsx")
+	exportTransactionsCmd.Flags().String("decimal-separator", ".", `Decimal separator for csv: "." or ","`)
+	exportTransactionsCmd.Flags().String("from", "", "First day to export, YYYY-MM-DD")
+	exportTransactionsCmd.Flags().String("to", "", "Last day to export, YYYY-MM-DD")
+	exportTransactionsCmd.Flags().IntSlice("account-ids", nil, "Only these accounts")
+	exportTransactionsCmd.Flags().IntSlice("category-ids", nil, "Only these categories")
+	exportTransactionsCmd.Flags().IntSlice("tag-ids", nil, "Only transactions with any of these tags")
+	exportTransactionsCmd.Flags().String("type", "", "Only debit or credit transactions")
+	exportTransactionsCmd.Flags().String("query", "", "Only transactions whose description or notes contain this text")
+	exportTransactionsCmd.MarkFlagRequired("organization-id")
+}
+
+func RunExportTransactions(application *application.Application, cmd *cobra.Command, args []string) {
+	organizationID, _ := cmd.Flags().GetInt("organization-id")
+	format, _ := cmd.Flags().GetString("format")
+	decimalSeparator, _ := cmd.Flags().GetString("decimal-separator")
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	accountIDs, _ := cmd.Flags().GetIntSlice("account-ids")
+	categoryIDs, _ := cmd.Flags().GetIntSlice("category-ids")
+	tagIDs, _ := cmd.Flags().GetIntSlice("tag-ids")
+	transactionType, _ := cmd.Flags().GetString("type")
+	query, _ := cmd.Flags().GetString("query")
+
+	export, err := application.FinancialService.ExportTransactions(context.Background(), financialApp.ExportTransactionsInput{
+		SearchTransactionsInput: financialApp.SearchTransactionsInput{
+			OrganizationID:  organizationID,
+			From:            from,
+			To:              to,
+			AccountIDs:      accountIDs,
+			CategoryIDs:     categoryIDs,
+			TagIDs:          tagIDs,
+			TransactionType: transactionType,
+			Query:           query,
+		},
+		Format:           format,
+		DecimalSeparator: decimalSeparator,
+	})
+	if err != nil {
+		fmt.Println("Export failed:", err)
+		return
+	}
+
+	file, err := os.Create(args[0])
+	if err != nil {
+		fmt.Println("Error creating file:", err)
+		return
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if err := export.Write(writer); err != nil {
+		fmt.Println("Export failed:", err)
+		return
+	}
+	if err := writer.Flush(); err != nil {
+		fmt.Println("Error writing file:", err)
+		return
+	}
+
+	fmt.Printf("Exported transactions of organization %d to %s\n", organizationID, args[0])
+}
@@ -0,0 +1,56 @@
+package financial
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/catrutech/celeiro/internal/application"
+	financialApp "github.com/catrutech/celeiro/internal/application/financial"
+	"github.com/spf13/cobra"
+)
+
+var importExchangeRatesCmd = &cobra.Command{
+	Use:   "importExchangeRates <file.csv>",
+	Short: "Import daily exchange rates for an organization",
+	Long: strings.TrimSpace(`
+Import daily exchange rates for an organization from a CSV file with one
+"date,currency,rate" line per rate: the date as YYYY-MM-DD, an ISO 4217 code,
+and the value of one unit of that currency in the organization's base
+currency. Rates already kept for the same currency and day are replaced.
+`),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		RunImportExchangeRates(application.GetApplication(), cmd, args)
+	},
+}
+
+func init() {
+	FinancialRootCmd.AddCommand(importExchangeRatesCmd)
+
+	importExchangeRatesCmd.Flags().Int("organization-id", 0, "Organization the rates belong to")
+	importExchangeRatesCmd.MarkFlagRequired("organization-id")
+}
+
+func RunImportExchangeRates(application *application.Application, cmd *cobra.Command, args []string) {
+	organizationID, _ := cmd.Flags().GetInt("organization-id")
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Println("Error reading file:", err)
+		return
+	}
+
+	result, err := application.FinancialService.ImportExchangeRates(context.Background(), financialApp.ImportExchangeRatesInput{
+		OrganizationID: organizationID,
+		CSVData:        data,
+		Source:         financialApp.ExchangeRateSourceCLI,
+	})
+	if err != nil {
+		fmt.Println("Error importing exchange rates:", err)
+		return
+	}
+
+	fmt.Printf("Imported %d rates for %s\n", result.Imported, strings.Join(result.Currencies, ", "))
+}
@@ -0,0 +1,20 @@
+package financial
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var FinancialRootCmd = &cobra.Command{
+	Use:   "financial",
+	Short: "Financial commands",
+	Long:  "Financial commands",
+}
+
+func Execute() {
+	err := FinancialRootCmd.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/catrutech/celeiro/internal/application"
+	"github.com/catrutech/celeiro/internal/application/jobs"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List background jobs",
+	Long:  "List background jobs, newest first",
+	Run: func(cmd *cobra.Command, args []string) {
+		RunList(application.GetApplication(), cmd, args)
+	},
+}
+
+func init() {
+	JobsRootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringP("status", "s", "", "Only jobs with this status (pending, running, succeeded, failed)")
+	listCmd.Flags().StringP("type", "t", "", "Only jobs of this type")
+	listCmd.Flags().IntP("limit", "l", 50, "Maximum number of jobs to list")
+}
+
+func RunList(application *application.Application, cmd *cobra.Command, args []string) {
+	status, _ := cmd.Flags().GetString("status")
+	jobType, _ := cmd.Flags().GetString("type")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	input := jobs.GetJobsInput{Limit: limit}
+	if status != "" {
+		input.Status = &status
+	}
+	if jobType != "" {
+		input.JobType = &jobType
+	}
+
+	result, err := application.JobsService.GetJobs(context.Background(), input)
+	if err != nil {
+		fmt.Println("Error listing jobs:", err)
+		return
+	}
+
+	if len(result) == 0 {
+		fmt.Println("No jobs found.")
+		return
+	}
+
+	for _, job := range result {
+		fmt.Printf("#%d %s [%s] attempts=%d/%d run_at=%s\n",
+			job.JobID, job.JobType, job.Status, job.Attempts, job.MaxAttempts, job.RunAt.Format(time.RFC3339))
+		if job.LastError != nil {
+			fmt.Printf("    last error: %s\n", *job.LastError)
+		}
+	}
+}
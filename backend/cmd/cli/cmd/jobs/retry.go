@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/catrutech/celeiro/internal/application"
+	"github.com/catrutech/celeiro/internal/application/jobs"
+	"github.com/spf13/cobra"
+)
+
+var retryCmd = &cobra.Command{
+	Use:   "retry <jobId>",
+	Short: "Requeue a failed job",
+	Long:  "Requeue a failed job with a fresh attempt budget. A running worker picks it up on its next poll.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		RunRetry(application.GetApplication(), cmd, args)
+	},
+}
+
+func init() {
+	JobsRootCmd.AddCommand(retryCmd)
+}
+
+func RunRetry(application *application.Application, cmd *cobra.Command, args []string) {
+	jobID, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Println("Invalid job ID: " + args[0])
+		return
+	}
+
+	job, err := application.JobsService.RetryJob(context.Background(), jobs.RetryJobInput{JobID: jobID})
+	if err != nil {
+		fmt.Println("Error retrying job:", err)
+		return
+	}
+
+	fmt.Printf("Job #%d %s requeued\n", job.JobID, job.JobType)
+}
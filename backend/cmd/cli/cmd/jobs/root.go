@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var JobsRootCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Background job commands",
+	Long:  "Background job commands",
+}
+
+func Execute() {
+	err := JobsRootCmd.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
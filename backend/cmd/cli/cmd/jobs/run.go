@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/catrutech/celeiro/internal/application"
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [jobId]",
+	Short: "Run background jobs now",
+	Long: `Run a single job by ID, ignoring its scheduled time. Without a job ID,
+enqueue the recurring jobs that are due and run everything due in the queue.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		RunRun(application.GetApplication(), cmd, args)
+	},
+}
+
+func init() {
+	JobsRootCmd.AddCommand(runCmd)
+
+	runCmd.Flags().IntP("max", "m", 100, "Maximum number of due jobs to run when no job ID is given")
+}
+
+func RunRun(application *application.Application, cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	if len(args) == 1 {
+		jobID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Println("Invalid job ID: " + args[0])
+			return
+		}
+
+		job, err := application.JobRunner.RunJob(ctx, jobID)
+		if err != nil {
+			fmt.Println("Error running job:", err)
+			return
+		}
+		fmt.Printf("Job #%d %s finished with status %s\n", job.JobID, job.JobType, job.Status)
+		if job.LastError != nil {
+			fmt.Printf("Last error: %s\n", *job.LastError)
+		}
+		return
+	}
+
+	maxJobs, _ := cmd.Flags().GetInt("max")
+
+	application.JobRunner.Schedule(ctx)
+	ran, err := application.JobRunner.RunDue(ctx, maxJobs)
+	if err != nil {
+		fmt.Println("Error running due jobs:", err)
+	}
+	fmt.Printf("Ran %d job(s)\n", ran)
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 
 	"github.com/catrutech/celeiro/cmd/cli/cmd/accounts"
+	"github.com/catrutech/celeiro/cmd/cli/cmd/financial"
+	"github.com/catrutech/celeiro/cmd/cli/cmd/jobs"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +21,8 @@ var rootCmd = &cobra.Command{
 
 func Execute() {
 	rootCmd.AddCommand(accounts.AccountsRootCmd)
+	rootCmd.AddCommand(jobs.JobsRootCmd)
+	rootCmd.AddCommand(financial.FinancialRootCmd)
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
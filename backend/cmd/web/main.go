@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/catrutech/celeiro/internal/application"
+	"github.com/catrutech/celeiro/internal/application/jobs"
 	"github.com/catrutech/celeiro/internal/config"
 	"github.com/catrutech/celeiro/internal/web"
 	"github.com/catrutech/celeiro/pkg/logging"
@@ -25,8 +26,9 @@ func main() {
 			provideLogger,
 			web.NewRouter,
 			web.NewHTTPServer,
+			jobs.NewWorker,
 		),
-		fx.Invoke(func(*http.Server) {}),
+		fx.Invoke(func(*http.Server, *jobs.Worker) {}),
 	)
 	go gracefulShutdown(app, done)
 
@@ -26,6 +26,7 @@ require (
 	go.opentelemetry.io/otel/sdk/log v0.13.0
 	go.opentelemetry.io/otel/sdk/metric v1.42.0
 	go.uber.org/fx v1.24.0
+	golang.org/x/text v0.34.0
 )
 
 require (
@@ -113,5 +114,4 @@ require (
 	go.opentelemetry.io/otel/trace v1.42.0
 	golang.org/x/crypto v0.48.0
 	golang.org/x/sync v0.19.0 // indirect
-	golang.org/x/text v0.34.0 // indirect
 )
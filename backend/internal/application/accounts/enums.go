@@ -7,13 +7,15 @@ const (
 	RoleAdmin          Role = "admin"
 	RoleRegularManager Role = "regular_manager"
 	RoleRegularUser    Role = "regular_user"
+	RoleViewer         Role = "viewer"
 )
 
 func (r Role) IsValid() bool {
 	return r == RoleSuperAdmin ||
 		r == RoleAdmin ||
 		r == RoleRegularManager ||
-		r == RoleRegularUser
+		r == RoleRegularUser ||
+		r == RoleViewer
 }
 
 func (r Role) IsSuperAdmin() bool {
@@ -35,6 +37,14 @@ const (
 	PermissionViewAllUsers         Permission = "view_all_users"
 	PermissionCreateSystemInvites  Permission = "create_system_invites"
 	PermissionViewAllOrganizations Permission = "view_all_organizations"
+	// Financial permissions (organization-wide). View covers every financial
+	// read; edit covers accounts, transactions, imports and tags.
+	PermissionViewTransactions Permission = "view_transactions"
+	PermissionEditTransactions Permission = "edit_transactions"
+	PermissionManageBudgets    Permission = "manage_budgets" // Categories, budgets and planned entries
+	PermissionCloseMonth       Permission = "close_month"
+	PermissionManagePatterns   Permission = "manage_patterns"
+	PermissionManageGoals      Permission = "manage_goals"
 )
 
 func (p Permission) IsValid() bool {
@@ -48,5 +58,11 @@ func (p Permission) IsValid() bool {
 		p == PermissionDeleteRegularUsers ||
 		p == PermissionViewAllUsers ||
 		p == PermissionCreateSystemInvites ||
-		p == PermissionViewAllOrganizations
+		p == PermissionViewAllOrganizations ||
+		p == PermissionViewTransactions ||
+		p == PermissionEditTransactions ||
+		p == PermissionManageBudgets ||
+		p == PermissionCloseMonth ||
+		p == PermissionManagePatterns ||
+		p == PermissionManageGoals
 }
@@ -0,0 +1,757 @@
+package accounts
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	database "github.com/catrutech/celeiro/pkg/database/persistent"
+	pkgerrors "github.com/catrutech/celeiro/pkg/errors"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/lib/pq"
+)
+
+// OrganizationArchiveFormatVersion is bumped whenever the archive layout
+// changes in a way older readers cannot follow.
+const OrganizationArchiveFormatVersion = 1
+
+const (
+	organizationArchiveManifestFile         = "manifest.json"
+	organizationArchiveSystemCategoriesFile = "system_categories.json"
+	organizationArchiveInsertBatchSize      = 1000
+)
+
+type ExportOrganizationInput struct {
+	OrganizationID int
+}
+
+type ExportOrganizationOutput struct {
+	Manifest OrganizationArchiveManifest
+	Data     []byte // ZIP archive
+}
+
+// RestoreOrganizationInput restores an archive into a new organization owned
+// by UserEmail, or into OrganizationID when it is given and still empty.
+type RestoreOrganizationInput struct {
+	Data             []byte
+	UserEmail        string
+	OrganizationID   int
+	OrganizationName string // Name of the new organization; defaults to the archived one
+}
+
+type RestoreOrganizationOutput struct {
+	OrganizationID int
+	User           User
+	Tables         []OrganizationArchiveTable
+}
+
+// OrganizationArchiveManifest describes an archive. SchemaVersion is the last
+// migration applied where it was exported; restoring needs a database at least
+// that recent.
+type OrganizationArchiveManifest struct {
+	FormatVersion int                        `json:"format_version"`
+	SchemaVersion int64                      `json:"schema_version"`
+	ExportedAt    time.Time                  `json:"exported_at"`
+	Organization  OrganizationArchiveInfo    `json:"organization"`
+	Tables        []OrganizationArchiveTable `json:"tables"`
+}
+
+type OrganizationArchiveInfo struct {
+	Name            string `db:"name" json:"name"`
+	BaseCurrency    string `db:"base_currency" json:"base_currency"`
+	AutoCloseMonths bool   `db:"auto_close_months" json:"auto_close_months"`
+}
+
+type OrganizationArchiveTable struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+}
+
+// organizationArchiveTable is a table holding organization data. Tables are
+// listed in the order they are restored in, so rows come after the rows they
+// reference; closed months go last since they lock their months against
+// changes.
+type organizationArchiveTable struct {
+	Name       string
+	Key        string // Serial primary key, given a new value on restore; empty for link tables
+	Scope      string // Condition selecting the rows of organization $1, with the table aliased as t
+	References []organizationArchiveReference
+	Prepare    func(row map[string]any) // Adjusts a row before it is inserted
+}
+
+type organizationArchiveReference struct {
+	Column string
+	Table  string
+	Loose  bool // Not a foreign key: IDs missing from the archive are cleared
+}
+
+// Pluggy links, jobs, invites, month reopenings and the audit log stay behind:
+// they belong to the original installation rather than to the organization's
+// finances.
+var organizationArchiveTables = []organizationArchiveTable{
+	{Name: "categories", Key: "category_id", Scope: "t.organization_id = $1 AND t.is_system = false"},
+	{Name: "tags", Key: "tag_id", Scope: "t.organization_id = $1"},
+	{Name: "savings_goals", Key: "savings_goal_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "category_id", Table: "categories"},
+	}},
+	{Name: "accounts", Key: "account_id", Scope: "t.organization_id = $1", Prepare: prepareArchivedAccount},
+	{Name: "patterns", Key: "pattern_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "target_category_id", Table: "categories"},
+	}},
+	{Name: "classification_rules", Key: "rule_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "category_id", Table: "categories"},
+	}},
+	{Name: "transactions", Key: "transaction_id", Scope: organizationArchiveTransactionScope, References: []organizationArchiveReference{
+		{Column: "account_id", Table: "accounts"},
+		{Column: "category_id", Table: "categories"},
+		{Column: "savings_goal_id", Table: "savings_goals"},
+		{Column: "classification_rule_id", Table: "classification_rules", Loose: true},
+		{Column: "pattern_id", Table: "patterns", Loose: true},
+	}},
+	{Name: "transaction_splits", Key: "split_id", Scope: "t.transaction_id IN (" + organizationArchiveTransactionIDs + ")", References: []organizationArchiveReference{
+		{Column: "transaction_id", Table: "transactions"},
+		{Column: "category_id", Table: "categories"},
+		{Column: "savings_goal_id", Table: "savings_goals"},
+	}},
+	{Name: "transaction_tags", Key: "transaction_tag_id", Scope: organizationArchiveTagScope, References: []organizationArchiveReference{
+		{Column: "transaction_id", Table: "transactions"},
+		{Column: "tag_id", Table: "tags"},
+	}},
+	{Name: "transaction_split_tags", Scope: organizationArchiveTagScope, References: []organizationArchiveReference{
+		{Column: "split_id", Table: "transaction_splits"},
+		{Column: "tag_id", Table: "tags"},
+	}},
+	{Name: "savings_goal_tags", Key: "savings_goal_tag_id", Scope: organizationArchiveTagScope, References: []organizationArchiveReference{
+		{Column: "savings_goal_id", Table: "savings_goals"},
+		{Column: "tag_id", Table: "tags"},
+	}},
+	{Name: "transfers", Key: "transfer_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "debit_transaction_id", Table: "transactions"},
+		{Column: "credit_transaction_id", Table: "transactions"},
+	}},
+	{Name: "budgets", Key: "budget_id", Scope: "t.organization_id = $1"},
+	{Name: "budget_items", Key: "budget_item_id", Scope: "t.budget_id IN (SELECT budget_id FROM budgets WHERE organization_id = $1)", References: []organizationArchiveReference{
+		{Column: "budget_id", Table: "budgets"},
+		{Column: "category_id", Table: "categories"},
+	}},
+	{Name: "category_budgets", Key: "category_budget_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "category_id", Table: "categories"},
+	}},
+	{Name: "planned_entries", Key: "planned_entry_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "category_id", Table: "categories"},
+		{Column: "parent_entry_id", Table: "planned_entries"},
+		{Column: "pattern_id", Table: "patterns"},
+		{Column: "savings_goal_id", Table: "savings_goals"},
+	}},
+	{Name: "planned_entry_statuses", Key: "status_id", Scope: "t.planned_entry_id IN (SELECT planned_entry_id FROM planned_entries WHERE organization_id = $1)", References: []organizationArchiveReference{
+		{Column: "planned_entry_id", Table: "planned_entries"},
+		{Column: "matched_transaction_id", Table: "transactions"},
+	}},
+	{Name: "planned_entry_tags", Key: "planned_entry_tag_id", Scope: organizationArchiveTagScope, References: []organizationArchiveReference{
+		{Column: "planned_entry_id", Table: "planned_entries"},
+		{Column: "tag_id", Table: "tags"},
+	}},
+	{Name: "monthly_snapshots", Key: "snapshot_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "category_id", Table: "categories"},
+	}},
+	{Name: "credit_card_invoices", Key: "credit_card_invoice_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "account_id", Table: "accounts"},
+		{Column: "payment_transaction_id", Table: "transactions"},
+	}},
+	{Name: "installment_plans", Key: "installment_plan_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "account_id", Table: "accounts"},
+		{Column: "category_id", Table: "categories"},
+	}},
+	{Name: "installment_parcels", Scope: "t.installment_plan_id IN (SELECT installment_plan_id FROM installment_plans WHERE organization_id = $1)", References: []organizationArchiveReference{
+		{Column: "installment_plan_id", Table: "installment_plans"},
+		{Column: "transaction_id", Table: "transactions"},
+		{Column: "planned_entry_id", Table: "planned_entries"},
+	}},
+	{Name: "exchange_rates", Scope: "t.organization_id = $1"},
+	{Name: "account_statement_balances", Key: "account_statement_balance_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "account_id", Table: "accounts"},
+	}},
+	{Name: "csv_import_profiles", Key: "csv_import_profile_id", Scope: "t.organization_id = $1", References: []organizationArchiveReference{
+		{Column: "account_id", Table: "accounts"},
+	}},
+	{Name: "closed_months", Key: "closed_month_id", Scope: "t.organization_id = $1"},
+}
+
+const (
+	organizationArchiveTransactionIDs   = "SELECT tr.transaction_id FROM transactions tr INNER JOIN accounts a ON a.account_id = tr.account_id WHERE a.organization_id = $1"
+	organizationArchiveTransactionScope = "t.account_id IN (SELECT account_id FROM accounts WHERE organization_id = $1)"
+	organizationArchiveTagScope         = "t.tag_id IN (SELECT tag_id FROM tags WHERE organization_id = $1)"
+)
+
+// prepareArchivedAccount starts the balance from the opening balance: the
+// balance trigger adds every restored transaction back on top of it.
+func prepareArchivedAccount(row map[string]any) {
+	if _, ok := row["opening_balance"]; !ok {
+		row["opening_balance"] = row["balance"]
+	}
+	row["balance"] = row["opening_balance"]
+}
+
+type organizationArchiveRepository struct {
+	db     database.Database
+	system *system.System
+}
+
+func (s *service) ExportOrganization(ctx context.Context, params ExportOrganizationInput) (ExportOrganizationOutput, error) {
+	return NewOrganizationArchiveRepository(s.db, s.system).Export(ctx, params)
+}
+
+func (s *service) RestoreOrganization(ctx context.Context, params RestoreOrganizationInput) (RestoreOrganizationOutput, error) {
+	return NewOrganizationArchiveRepository(s.db, s.system).Restore(ctx, params)
+}
+
+func NewOrganizationArchiveRepository(db database.Database, system *system.System) *organizationArchiveRepository {
+	return &organizationArchiveRepository{db: db, system: system}
+}
+
+// ============================================================================
+// Export
+// ============================================================================
+
+type organizationArchiveTableData struct {
+	Rows int    `db:"rows"`
+	Data string `db:"data"`
+}
+
+type organizationArchiveSystemCategory struct {
+	CategoryID int    `db:"category_id" json:"category_id"`
+	Name       string `db:"name" json:"name"`
+}
+
+const fetchOrganizationArchiveInfoQuery = `
+	-- accounts.fetchOrganizationArchiveInfoQuery
+	SELECT COALESCE(name, '') AS name, base_currency, auto_close_months
+	FROM organizations
+	WHERE organization_id = $1;
+`
+
+// Every export read sees the same snapshot, so rows written meanwhile cannot
+// leave references to rows missing from the archive
+const setOrganizationArchiveSnapshotQuery = `
+	-- accounts.setOrganizationArchiveSnapshotQuery
+	SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY;
+`
+
+const fetchOrganizationArchiveSchemaVersionQuery = `
+	-- accounts.fetchOrganizationArchiveSchemaVersionQuery
+	SELECT COALESCE(MAX(version_id), 0)
+	FROM goose_db_version
+	WHERE is_applied = true;
+`
+
+const fetchOrganizationArchiveSystemCategoriesQuery = `
+	-- accounts.fetchOrganizationArchiveSystemCategoriesQuery
+	SELECT category_id, name
+	FROM categories
+	WHERE is_system = true
+	ORDER BY category_id;
+`
+
+// organizationArchiveExportQuery selects a table's rows as one JSON array
+func organizationArchiveExportQuery(table organizationArchiveTable) string {
+	order := ""
+	if table.Key != "" {
+		order = " ORDER BY t." + table.Key
+	}
+	return fmt.Sprintf(`
+	-- accounts.organizationArchiveExportQuery
+	SELECT COUNT(*) AS rows, COALESCE(jsonb_agg(to_jsonb(t)%s), '[]'::jsonb)::text AS data
+	FROM %s t
+	WHERE %s;
+`, order, table.Name, table.Scope)
+}
+
+func organizationArchiveCountQuery(table organizationArchiveTable) string {
+	return fmt.Sprintf(`
+	-- accounts.organizationArchiveCountQuery
+	SELECT COUNT(*)
+	FROM %s t
+	WHERE %s;
+`, table.Name, table.Scope)
+}
+
+// Export writes every table of the organization into a ZIP archive: a
+// manifest, the system categories it may reference and one JSON array per
+// table. All of it is read in one read-only transaction.
+func (r *organizationArchiveRepository) Export(ctx context.Context, input ExportOrganizationInput) (ExportOrganizationOutput, error) {
+	var output ExportOrganizationOutput
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		if err := r.db.Run(ctx, setOrganizationArchiveSnapshotQuery); err != nil {
+			return pkgerrors.Wrap(err, "failed to start export snapshot")
+		}
+		var err error
+		output, err = r.export(ctx, input)
+		return err
+	})
+	if err != nil {
+		return ExportOrganizationOutput{}, err
+	}
+	return output, nil
+}
+
+func (r *organizationArchiveRepository) export(ctx context.Context, input ExportOrganizationInput) (ExportOrganizationOutput, error) {
+	var info OrganizationArchiveInfo
+	err := r.db.Query(ctx, &info, fetchOrganizationArchiveInfoQuery, input.OrganizationID)
+	if pkgerrors.Is(err, sql.ErrNoRows) {
+		return ExportOrganizationOutput{}, pkgerrors.New("organization %d not found", input.OrganizationID)
+	}
+	if err != nil {
+		return ExportOrganizationOutput{}, pkgerrors.Wrap(err, "failed to fetch organization")
+	}
+
+	manifest := OrganizationArchiveManifest{
+		FormatVersion: OrganizationArchiveFormatVersion,
+		ExportedAt:    r.system.Time.Now(),
+		Organization:  info,
+		Tables:        make([]OrganizationArchiveTable, 0, len(organizationArchiveTables)),
+	}
+	if err := r.db.Query(ctx, &manifest.SchemaVersion, fetchOrganizationArchiveSchemaVersionQuery); err != nil {
+		return ExportOrganizationOutput{}, pkgerrors.Wrap(err, "failed to fetch schema version")
+	}
+	var systemCategories []organizationArchiveSystemCategory
+	if err := r.db.Query(ctx, &systemCategories, fetchOrganizationArchiveSystemCategoriesQuery); err != nil {
+		return ExportOrganizationOutput{}, pkgerrors.Wrap(err, "failed to fetch system categories")
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for _, table := range organizationArchiveTables {
+		var data organizationArchiveTableData
+		if err := r.db.Query(ctx, &data, organizationArchiveExportQuery(table), input.OrganizationID); err != nil {
+			return ExportOrganizationOutput{}, pkgerrors.Wrap(err, "failed to export %s", table.Name)
+		}
+		if err := writeOrganizationArchiveFile(archive, table.Name+".json", []byte(data.Data)); err != nil {
+			return ExportOrganizationOutput{}, err
+		}
+		manifest.Tables = append(manifest.Tables, OrganizationArchiveTable{Name: table.Name, Rows: data.Rows})
+	}
+
+	systemCategoriesData, err := json.Marshal(systemCategories)
+	if err != nil {
+		return ExportOrganizationOutput{}, pkgerrors.Wrap(err, "failed to encode system categories")
+	}
+	if err := writeOrganizationArchiveFile(archive, organizationArchiveSystemCategoriesFile, systemCategoriesData); err != nil {
+		return ExportOrganizationOutput{}, err
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return ExportOrganizationOutput{}, pkgerrors.Wrap(err, "failed to encode manifest")
+	}
+	if err := writeOrganizationArchiveFile(archive, organizationArchiveManifestFile, manifestData); err != nil {
+		return ExportOrganizationOutput{}, err
+	}
+	if err := archive.Close(); err != nil {
+		return ExportOrganizationOutput{}, pkgerrors.Wrap(err, "failed to write archive")
+	}
+
+	return ExportOrganizationOutput{Manifest: manifest, Data: buffer.Bytes()}, nil
+}
+
+func writeOrganizationArchiveFile(archive *zip.Writer, name string, data []byte) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return pkgerrors.Wrap(err, "failed to add %s to archive", name)
+	}
+	if _, err := file.Write(data); err != nil {
+		return pkgerrors.Wrap(err, "failed to write %s to archive", name)
+	}
+	return nil
+}
+
+// ============================================================================
+// Restore
+// ============================================================================
+
+type organizationArchive struct {
+	Manifest         OrganizationArchiveManifest
+	SystemCategories []organizationArchiveSystemCategory
+	Tables           map[string][]map[string]any
+}
+
+type organizationArchiveUser struct {
+	UserID int    `db:"user_id"`
+	Name   string `db:"name"`
+	Email  string `db:"email"`
+}
+
+// organizationArchiveIDs maps each table's archived IDs to the restored ones
+type organizationArchiveIDs map[string]map[int64]int64
+
+const fetchOrganizationArchiveUserQuery = `
+	-- accounts.fetchOrganizationArchiveUserQuery
+	SELECT user_id, name, email
+	FROM users
+	WHERE LOWER(email) = LOWER($1);
+`
+
+const fetchOrganizationArchiveMembershipQuery = `
+	-- accounts.fetchOrganizationArchiveMembershipQuery
+	SELECT COUNT(*)
+	FROM user_organizations
+	WHERE user_id = $1
+		AND organization_id = $2;
+`
+
+const insertOrganizationArchiveOrganizationQuery = `
+	-- accounts.insertOrganizationArchiveOrganizationQuery
+	INSERT INTO organizations (name, base_currency, auto_close_months)
+	VALUES ($1, $2, $3)
+	RETURNING organization_id;
+`
+
+const insertOrganizationArchiveMembershipQuery = `
+	-- accounts.insertOrganizationArchiveMembershipQuery
+	INSERT INTO user_organizations (user_id, organization_id, user_role)
+	VALUES ($1, $2, $3);
+`
+
+const modifyOrganizationArchiveSettingsQuery = `
+	-- accounts.modifyOrganizationArchiveSettingsQuery
+	UPDATE organizations
+	SET base_currency = $2, auto_close_months = $3, updated_at = CURRENT_TIMESTAMP
+	WHERE organization_id = $1;
+`
+
+const fetchOrganizationArchiveColumnsQuery = `
+	-- accounts.fetchOrganizationArchiveColumnsQuery
+	SELECT column_name
+	FROM information_schema.columns
+	WHERE table_schema = current_schema()
+		AND table_name = $1
+		AND is_generated = 'NEVER';
+`
+
+const reserveOrganizationArchiveIDsQuery = `
+	-- accounts.reserveOrganizationArchiveIDsQuery
+	SELECT nextval(pg_get_serial_sequence($1, $2))
+	FROM generate_series(1, $3);
+`
+
+// Restore inserts the archived rows under new IDs in one transaction. Every
+// row is attributed to the restoring user, who becomes the admin of a new
+// organization.
+func (r *organizationArchiveRepository) Restore(ctx context.Context, input RestoreOrganizationInput) (RestoreOrganizationOutput, error) {
+	if strings.TrimSpace(input.UserEmail) == "" {
+		return RestoreOrganizationOutput{}, pkgerrors.New("user email is required")
+	}
+	archive, err := readOrganizationArchive(input.Data)
+	if err != nil {
+		return RestoreOrganizationOutput{}, err
+	}
+
+	var schemaVersion int64
+	if err := r.db.Query(ctx, &schemaVersion, fetchOrganizationArchiveSchemaVersionQuery); err != nil {
+		return RestoreOrganizationOutput{}, pkgerrors.Wrap(err, "failed to fetch schema version")
+	}
+	if archive.Manifest.SchemaVersion > schemaVersion {
+		return RestoreOrganizationOutput{}, pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive,
+			"archive needs schema version %d, database is at %d", archive.Manifest.SchemaVersion, schemaVersion)
+	}
+
+	var user organizationArchiveUser
+	err = r.db.Query(ctx, &user, fetchOrganizationArchiveUserQuery, strings.TrimSpace(input.UserEmail))
+	if pkgerrors.Is(err, sql.ErrNoRows) {
+		return RestoreOrganizationOutput{}, pkgerrors.New("user %s not found", input.UserEmail)
+	}
+	if err != nil {
+		return RestoreOrganizationOutput{}, pkgerrors.Wrap(err, "failed to fetch user")
+	}
+
+	output := RestoreOrganizationOutput{
+		User: User{UserID: user.UserID, Name: user.Name, Email: user.Email},
+	}
+	err = r.db.Tx(ctx, func(ctx context.Context) error {
+		organizationID, err := r.prepareRestoreTarget(ctx, input, archive.Manifest.Organization, user.UserID)
+		if err != nil {
+			return err
+		}
+		output.OrganizationID = organizationID
+
+		systemCategories, err := r.mapSystemCategories(ctx, archive.SystemCategories)
+		if err != nil {
+			return err
+		}
+		ids := organizationArchiveIDs{"categories": systemCategories}
+
+		for _, table := range organizationArchiveTables {
+			rows := archive.Tables[table.Name]
+			if len(rows) == 0 {
+				continue
+			}
+			if err := r.restoreTable(ctx, table, rows, ids, organizationID, user.UserID); err != nil {
+				return err
+			}
+			output.Tables = append(output.Tables, OrganizationArchiveTable{Name: table.Name, Rows: len(rows)})
+		}
+		return nil
+	})
+	if err != nil {
+		return RestoreOrganizationOutput{}, err
+	}
+
+	return output, nil
+}
+
+// prepareRestoreTarget creates the organization to restore into, or checks
+// that the requested one is empty and that the user belongs to it.
+func (r *organizationArchiveRepository) prepareRestoreTarget(ctx context.Context, input RestoreOrganizationInput, info OrganizationArchiveInfo, userID int) (int, error) {
+	if input.OrganizationID == 0 {
+		name := strings.TrimSpace(input.OrganizationName)
+		if name == "" {
+			name = info.Name
+		}
+		var organizationID int
+		if err := r.db.Query(ctx, &organizationID, insertOrganizationArchiveOrganizationQuery, name, info.BaseCurrency, info.AutoCloseMonths); err != nil {
+			return 0, pkgerrors.Wrap(err, "failed to create organization")
+		}
+		if err := r.db.Run(ctx, insertOrganizationArchiveMembershipQuery, userID, organizationID, RoleAdmin); err != nil {
+			return 0, pkgerrors.Wrap(err, "failed to add user to organization")
+		}
+		return organizationID, nil
+	}
+
+	var memberships int
+	if err := r.db.Query(ctx, &memberships, fetchOrganizationArchiveMembershipQuery, userID, input.OrganizationID); err != nil {
+		return 0, pkgerrors.Wrap(err, "failed to fetch membership")
+	}
+	if memberships == 0 {
+		return 0, pkgerrors.New("user does not belong to organization %d", input.OrganizationID)
+	}
+	for _, table := range organizationArchiveTables {
+		var rows int
+		if err := r.db.Query(ctx, &rows, organizationArchiveCountQuery(table), input.OrganizationID); err != nil {
+			return 0, pkgerrors.Wrap(err, "failed to check %s", table.Name)
+		}
+		if rows > 0 {
+			return 0, pkgerrors.Wrap(internalerrors.ErrOrganizationNotEmpty, "organization %d already has %s", input.OrganizationID, table.Name)
+		}
+	}
+	if err := r.db.Run(ctx, modifyOrganizationArchiveSettingsQuery, input.OrganizationID, info.BaseCurrency, info.AutoCloseMonths); err != nil {
+		return 0, pkgerrors.Wrap(err, "failed to set organization settings")
+	}
+	return input.OrganizationID, nil
+}
+
+// mapSystemCategories matches the archived system categories to this
+// database's by name.
+func (r *organizationArchiveRepository) mapSystemCategories(ctx context.Context, archived []organizationArchiveSystemCategory) (map[int64]int64, error) {
+	var local []organizationArchiveSystemCategory
+	if err := r.db.Query(ctx, &local, fetchOrganizationArchiveSystemCategoriesQuery); err != nil {
+		return nil, pkgerrors.Wrap(err, "failed to fetch system categories")
+	}
+	byName := make(map[string]int, len(local))
+	for _, category := range local {
+		byName[category.Name] = category.CategoryID
+	}
+
+	mapped := make(map[int64]int64, len(archived))
+	for _, category := range archived {
+		if categoryID, ok := byName[category.Name]; ok {
+			mapped[int64(category.CategoryID)] = int64(categoryID)
+		}
+	}
+	return mapped, nil
+}
+
+func (r *organizationArchiveRepository) restoreTable(ctx context.Context, table organizationArchiveTable, rows []map[string]any, ids organizationArchiveIDs, organizationID, userID int) error {
+	var newIDs []int64
+	if table.Key != "" {
+		if err := r.db.Query(ctx, &newIDs, reserveOrganizationArchiveIDsQuery, table.Name, table.Key, len(rows)); err != nil {
+			return pkgerrors.Wrap(err, "failed to reserve %s ids", table.Name)
+		}
+	}
+	if err := remapOrganizationArchiveRows(table, rows, newIDs, ids, organizationID, userID); err != nil {
+		return err
+	}
+
+	var existing []string
+	if err := r.db.Query(ctx, &existing, fetchOrganizationArchiveColumnsQuery, table.Name); err != nil {
+		return pkgerrors.Wrap(err, "failed to fetch %s columns", table.Name)
+	}
+	columns := organizationArchiveColumns(rows[0], existing)
+	query := organizationArchiveInsertQuery(table.Name, columns)
+
+	for start := 0; start < len(rows); start += organizationArchiveInsertBatchSize {
+		batch := rows[start:min(start+organizationArchiveInsertBatchSize, len(rows))]
+		data, err := json.Marshal(batch)
+		if err != nil {
+			return pkgerrors.Wrap(err, "failed to encode %s", table.Name)
+		}
+		if err := r.db.Run(ctx, query, string(data)); err != nil {
+			return pkgerrors.Wrap(err, "failed to restore %s", table.Name)
+		}
+	}
+	return nil
+}
+
+// remapOrganizationArchiveRows gives the rows their new keys, points their
+// references at the restored rows and moves them to the organization and
+// user they are restored for. newIDs holds a new key per row.
+func remapOrganizationArchiveRows(table organizationArchiveTable, rows []map[string]any, newIDs []int64, ids organizationArchiveIDs, organizationID, userID int) error {
+	if table.Key != "" {
+		if len(newIDs) != len(rows) {
+			return pkgerrors.New("got %d ids for %d %s", len(newIDs), len(rows), table.Name)
+		}
+		if ids[table.Name] == nil {
+			ids[table.Name] = make(map[int64]int64, len(rows))
+		}
+		for i, row := range rows {
+			oldID, err := organizationArchiveID(row[table.Key])
+			if err != nil || oldID == nil {
+				return pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive, "%s row without %s", table.Name, table.Key)
+			}
+			ids[table.Name][*oldID] = newIDs[i]
+			row[table.Key] = newIDs[i]
+		}
+	}
+
+	for _, row := range rows {
+		if _, ok := row["organization_id"]; ok {
+			row["organization_id"] = organizationID
+		}
+		if _, ok := row["user_id"]; ok {
+			row["user_id"] = userID
+		}
+		for _, reference := range table.References {
+			oldID, err := organizationArchiveID(row[reference.Column])
+			if err != nil {
+				return pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive, "%s.%s: %s", table.Name, reference.Column, err.Error())
+			}
+			if oldID == nil {
+				continue
+			}
+			newID, ok := ids[reference.Table][*oldID]
+			switch {
+			case ok:
+				row[reference.Column] = newID
+			case reference.Loose:
+				row[reference.Column] = nil
+			default:
+				return pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive,
+					"%s.%s references %s %d, which is not in the archive", table.Name, reference.Column, reference.Table, *oldID)
+			}
+		}
+		if table.Prepare != nil {
+			table.Prepare(row)
+		}
+	}
+	return nil
+}
+
+// organizationArchiveID reads an ID from a decoded row, nil when it is null
+func organizationArchiveID(value any) (*int64, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		id, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &id, nil
+	case int64:
+		return &v, nil
+	default:
+		return nil, fmt.Errorf("unexpected id %v", value)
+	}
+}
+
+// organizationArchiveColumns lists the row's columns the table still has.
+// Columns added after the archive was made take their defaults.
+func organizationArchiveColumns(row map[string]any, existing []string) []string {
+	exists := make(map[string]bool, len(existing))
+	for _, column := range existing {
+		exists[column] = true
+	}
+	var columns []string
+	for column := range row {
+		if exists[column] {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func organizationArchiveInsertQuery(table string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+	}
+	list := strings.Join(quoted, ", ")
+	return fmt.Sprintf(`
+	-- accounts.organizationArchiveInsertQuery
+	INSERT INTO %s (%s)
+	SELECT %s FROM jsonb_populate_recordset(NULL::%s, $1::jsonb);
+`, table, list, list, table)
+}
+
+// readOrganizationArchive unpacks an archive written by Export
+func readOrganizationArchive(data []byte) (organizationArchive, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return organizationArchive{}, pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive, "not a zip file")
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	archive := organizationArchive{Tables: make(map[string][]map[string]any)}
+	if err := readOrganizationArchiveFile(files, organizationArchiveManifestFile, &archive.Manifest); err != nil {
+		return organizationArchive{}, err
+	}
+	if archive.Manifest.FormatVersion != OrganizationArchiveFormatVersion {
+		return organizationArchive{}, pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive,
+			"unsupported format version %d", archive.Manifest.FormatVersion)
+	}
+	if err := readOrganizationArchiveFile(files, organizationArchiveSystemCategoriesFile, &archive.SystemCategories); err != nil {
+		return organizationArchive{}, err
+	}
+	for _, table := range archive.Manifest.Tables {
+		var rows []map[string]any
+		if err := readOrganizationArchiveFile(files, table.Name+".json", &rows); err != nil {
+			return organizationArchive{}, err
+		}
+		if len(rows) != table.Rows {
+			return organizationArchive{}, pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive,
+				"%s has %d rows, the manifest says %d", table.Name, len(rows), table.Rows)
+		}
+		archive.Tables[table.Name] = rows
+	}
+	return archive, nil
+}
+
+// readOrganizationArchiveFile decodes a JSON file of the archive, keeping
+// numbers as written so amounts and IDs survive unchanged.
+func readOrganizationArchiveFile(files map[string]*zip.File, name string, dest any) error {
+	file, ok := files[name]
+	if !ok {
+		return pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive, "missing %s", name)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive, "cannot open %s", name)
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	if err := decoder.Decode(dest); err != nil {
+		return pkgerrors.Wrap(internalerrors.ErrInvalidOrganizationArchive, "invalid %s: %s", name, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package accounts
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	database "github.com/catrutech/celeiro/pkg/database/persistent"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationArchiveRepository_Export_WritesManifestAndTables(t *testing.T) {
+	// ARRANGE
+	db := database.NewMemoryDatabase()
+	stub := system.NewStubSystem()
+	stub.Time.SetTimes(time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC))
+	repo := NewOrganizationArchiveRepository(db, stub.ToSystem())
+
+	db.ExpectQuery(fetchOrganizationArchiveInfoQuery, 100).
+		WillReturn(OrganizationArchiveInfo{Name: "Família", BaseCurrency: "BRL"})
+	db.ExpectQuery(fetchOrganizationArchiveSchemaVersionQuery).WillReturn(int64(70))
+	db.ExpectQuery(fetchOrganizationArchiveSystemCategoriesQuery).
+		WillReturn([]organizationArchiveSystemCategory{{CategoryID: 1, Name: "Alimentação"}})
+	for _, table := range organizationArchiveTables {
+		data := organizationArchiveTableData{Data: "[]"}
+		if table.Name == "accounts" {
+			data = organizationArchiveTableData{Rows: 1, Data: `[{"account_id": 7, "organization_id": 100, "balance": 1500.25}]`}
+		}
+		db.ExpectQuery(organizationArchiveExportQuery(table), 100).WillReturn(data)
+	}
+
+	// ACT
+	output, err := repo.Export(context.Background(), ExportOrganizationInput{OrganizationID: 100})
+
+	// ASSERT
+	require.NoError(t, err)
+	require.NoError(t, db.ExpectationsWereMet())
+	assert.Equal(t, OrganizationArchiveFormatVersion, output.Manifest.FormatVersion)
+	assert.Equal(t, int64(70), output.Manifest.SchemaVersion)
+	assert.Len(t, output.Manifest.Tables, len(organizationArchiveTables))
+
+	archive, err := readOrganizationArchive(output.Data)
+	require.NoError(t, err)
+	assert.Equal(t, "Família", archive.Manifest.Organization.Name)
+	assert.Equal(t, []organizationArchiveSystemCategory{{CategoryID: 1, Name: "Alimentação"}}, archive.SystemCategories)
+	require.Len(t, archive.Tables["accounts"], 1)
+	assert.Equal(t, json.Number("1500.25"), archive.Tables["accounts"][0]["balance"])
+}
+
+func TestOrganizationArchiveRepository_RoundTrip_KeepsOrganizationSettings(t *testing.T) {
+	// ARRANGE
+	db := database.NewMemoryDatabase()
+	repo := NewOrganizationArchiveRepository(db, system.NewStubSystem().ToSystem())
+
+	db.ExpectQuery(fetchOrganizationArchiveInfoQuery, 100).
+		WillReturn(OrganizationArchiveInfo{Name: "Família", BaseCurrency: "USD", AutoCloseMonths: true})
+	db.ExpectQuery(fetchOrganizationArchiveSchemaVersionQuery).WillReturn(int64(75))
+	db.ExpectQuery(fetchOrganizationArchiveSystemCategoriesQuery).WillReturn([]organizationArchiveSystemCategory{})
+	for _, table := range organizationArchiveTables {
+		db.ExpectQuery(organizationArchiveExportQuery(table), 100).WillReturn(organizationArchiveTableData{Data: "[]"})
+	}
+	exported, err := repo.Export(context.Background(), ExportOrganizationInput{OrganizationID: 100})
+	require.NoError(t, err)
+
+	db.ExpectQuery(fetchOrganizationArchiveSchemaVersionQuery).WillReturn(int64(75))
+	db.ExpectQuery(fetchOrganizationArchiveUserQuery, "lucas@example.com").
+		WillReturn(organizationArchiveUser{UserID: 10, Name: "Lucas", Email: "lucas@example.com"})
+	db.ExpectQuery(insertOrganizationArchiveOrganizationQuery, "Família", "USD", true).WillReturn(300)
+	db.ExpectQuery(fetchOrganizationArchiveSystemCategoriesQuery).WillReturn([]organizationArchiveSystemCategory{})
+
+	// ACT
+	restored, err := repo.Restore(context.Background(), RestoreOrganizationInput{Data: exported.Data, UserEmail: "lucas@example.com"})
+
+	// ASSERT
+	require.NoError(t, err)
+	require.NoError(t, db.ExpectationsWereMet())
+	assert.True(t, exported.Manifest.Organization.AutoCloseMonths)
+	assert.Equal(t, 300, restored.OrganizationID)
+}
+
+func TestRemapOrganizationArchiveRows(t *testing.T) {
+	ids := organizationArchiveIDs{"categories": {1: 1001}} // A system category
+
+	categories := []map[string]any{
+		{"category_id": json.Number("40"), "organization_id": json.Number("100"), "user_id": json.Number("20"), "name": "Pets"},
+	}
+	require.NoError(t, remapOrganizationArchiveRows(organizationArchiveTables[0], categories, []int64{900}, ids, 300, 30))
+	assert.Equal(t, int64(900), categories[0]["category_id"])
+	assert.Equal(t, 300, categories[0]["organization_id"])
+	assert.Equal(t, 30, categories[0]["user_id"])
+
+	plannedEntries := organizationArchiveTableNamed(t, "planned_entries")
+	rows := []map[string]any{
+		{"planned_entry_id": json.Number("5"), "category_id": json.Number("40"), "parent_entry_id": nil, "pattern_id": nil, "savings_goal_id": nil},
+		{"planned_entry_id": json.Number("6"), "category_id": json.Number("1"), "parent_entry_id": json.Number("5"), "pattern_id": nil, "savings_goal_id": nil},
+	}
+	require.NoError(t, remapOrganizationArchiveRows(plannedEntries, rows, []int64{50, 51}, ids, 300, 30))
+	assert.Equal(t, int64(900), rows[0]["category_id"])
+	assert.Equal(t, int64(1001), rows[1]["category_id"])
+	assert.Equal(t, int64(50), rows[1]["parent_entry_id"])
+
+	transactions := organizationArchiveTableNamed(t, "transactions")
+	ids["accounts"] = map[int64]int64{7: 70}
+	rows = []map[string]any{
+		{"transaction_id": json.Number("8"), "account_id": json.Number("7"), "category_id": nil, "savings_goal_id": nil, "classification_rule_id": json.Number("99")},
+	}
+	require.NoError(t, remapOrganizationArchiveRows(transactions, rows, []int64{80}, ids, 300, 30))
+	assert.Nil(t, rows[0]["classification_rule_id"])
+
+	rows = []map[string]any{
+		{"transaction_id": json.Number("9"), "account_id": json.Number("8"), "category_id": nil},
+	}
+	err := remapOrganizationArchiveRows(transactions, rows, []int64{81}, ids, 300, 30)
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidOrganizationArchive)
+}
+
+func TestPrepareArchivedAccount(t *testing.T) {
+	row := map[string]any{"balance": json.Number("1500.25"), "opening_balance": json.Number("200")}
+	prepareArchivedAccount(row)
+	assert.Equal(t, json.Number("200"), row["balance"])
+
+	// Archived before accounts had an opening balance
+	row = map[string]any{"balance": json.Number("1500.25")}
+	prepareArchivedAccount(row)
+	assert.Equal(t, json.Number("1500.25"), row["opening_balance"])
+}
+
+func TestOrganizationArchiveRepository_Restore_RejectsNewerSchema(t *testing.T) {
+	// ARRANGE
+	db := database.NewMemoryDatabase()
+	repo := NewOrganizationArchiveRepository(db, system.NewStubSystem().ToSystem())
+	data := buildTestOrganizationArchive(t, OrganizationArchiveManifest{FormatVersion: OrganizationArchiveFormatVersion, SchemaVersion: 71})
+
+	db.ExpectQuery(fetchOrganizationArchiveSchemaVersionQuery).WillReturn(int64(70))
+
+	// ACT
+	_, err := repo.Restore(context.Background(), RestoreOrganizationInput{Data: data, UserEmail: "lucas@example.com"})
+
+	// ASSERT
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidOrganizationArchive)
+	require.NoError(t, db.ExpectationsWereMet())
+}
+
+func TestOrganizationArchiveRepository_Restore_RequiresEmptyOrganization(t *testing.T) {
+	// ARRANGE
+	db := database.NewMemoryDatabase()
+	repo := NewOrganizationArchiveRepository(db, system.NewStubSystem().ToSystem())
+	data := buildTestOrganizationArchive(t, OrganizationArchiveManifest{FormatVersion: OrganizationArchiveFormatVersion, SchemaVersion: 70})
+
+	db.ExpectQuery(fetchOrganizationArchiveSchemaVersionQuery).WillReturn(int64(70))
+	db.ExpectQuery(fetchOrganizationArchiveUserQuery, "lucas@example.com").
+		WillReturn(organizationArchiveUser{UserID: 10, Name: "Lucas", Email: "lucas@example.com"})
+	db.ExpectQuery(fetchOrganizationArchiveMembershipQuery, 10, 200).WillReturn(1)
+	db.ExpectQuery(organizationArchiveCountQuery(organizationArchiveTables[0]), 200).WillReturn(3)
+
+	// ACT
+	_, err := repo.Restore(context.Background(), RestoreOrganizationInput{Data: data, UserEmail: "lucas@example.com", OrganizationID: 200})
+
+	// ASSERT
+	assert.ErrorIs(t, err, internalerrors.ErrOrganizationNotEmpty)
+	require.NoError(t, db.ExpectationsWereMet())
+}
+
+func TestReadOrganizationArchive_RejectsOtherFormats(t *testing.T) {
+	_, err := readOrganizationArchive([]byte("not a zip"))
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidOrganizationArchive)
+
+	_, err = readOrganizationArchive(buildTestOrganizationArchive(t, OrganizationArchiveManifest{FormatVersion: 2}))
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidOrganizationArchive)
+}
+
+func organizationArchiveTableNamed(t *testing.T, name string) organizationArchiveTable {
+	for _, table := range organizationArchiveTables {
+		if table.Name == name {
+			return table
+		}
+	}
+	t.Fatalf("no archive table %s", name)
+	return organizationArchiveTable{}
+}
+
+func buildTestOrganizationArchive(t *testing.T, manifest OrganizationArchiveManifest) []byte {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	manifestData, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, writeOrganizationArchiveFile(archive, organizationArchiveManifestFile, manifestData))
+	require.NoError(t, writeOrganizationArchiveFile(archive, organizationArchiveSystemCategoriesFile, []byte("[]")))
+	require.NoError(t, archive.Close())
+	return buffer.Bytes()
+}
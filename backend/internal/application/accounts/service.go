@@ -40,6 +40,10 @@ type Service interface {
 	CancelOrganizationInvite(ctx context.Context, params CancelOrganizationInviteInput) error
 	MergeOrganizations(ctx context.Context, params OrganizationMergeInput) (OrganizationMergeOutput, error)
 
+	// Organization Archives
+	ExportOrganization(ctx context.Context, params ExportOrganizationInput) (ExportOrganizationOutput, error)
+	RestoreOrganization(ctx context.Context, params RestoreOrganizationInput) (RestoreOrganizationOutput, error)
+
 	// Backoffice (System-wide)
 	GetAllUsers(ctx context.Context) ([]SystemUser, error)
 	CreateSystemInvite(ctx context.Context, params CreateSystemInviteInput) (SystemInvite, error)
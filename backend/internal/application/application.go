@@ -5,6 +5,7 @@ import (
 
 	"github.com/catrutech/celeiro/internal/application/accounts"
 	"github.com/catrutech/celeiro/internal/application/financial"
+	"github.com/catrutech/celeiro/internal/application/jobs"
 	"github.com/catrutech/celeiro/internal/config"
 	"github.com/catrutech/celeiro/internal/integrations/pluggy"
 	database "github.com/catrutech/celeiro/pkg/database/persistent"
@@ -21,6 +22,8 @@ import (
 type Application struct {
 	AccountsService  accounts.Service
 	FinancialService financial.Service
+	JobsService      jobs.Service
+	JobRunner        *jobs.Runner
 	Mailer           mailer.Mailer
 	PluggyClient     *pluggy.Client
 }
@@ -28,36 +31,47 @@ type Application struct {
 func NewApplication(
 	accountsService accounts.Service,
 	financialService financial.Service,
+	jobsService jobs.Service,
+	jobRunner *jobs.Runner,
 	m mailer.Mailer,
 	pluggyClient *pluggy.Client,
 ) *Application {
 	return &Application{
 		AccountsService:  accountsService,
 		FinancialService: financialService,
+		JobsService:      jobsService,
+		JobRunner:        jobRunner,
 		Mailer:           m,
 		PluggyClient:     pluggyClient,
 	}
 }
 
 func GetApplicationProvider() fx.Option {
-	return fx.Provide(
-		// Common
-		config.New,
-		database.New,
-		transientdb.NewRedisDB,
-		mailer.GetMailerType,
-		mailer.NewMailer,
-		system.NewSystem,
-		metrics.NewMetrics,
-		pluggy.New,
-		// Accounts
-		accounts.NewRepository,
-		accounts.New,
-		// Financial
-		financial.NewRepository,
-		financial.New,
-		// Application
-		NewApplication,
+	return fx.Options(
+		fx.Provide(
+			// Common
+			config.New,
+			database.New,
+			transientdb.NewRedisDB,
+			mailer.GetMailerType,
+			mailer.NewMailer,
+			system.NewSystem,
+			metrics.NewMetrics,
+			pluggy.New,
+			// Jobs
+			jobs.NewRepository,
+			jobs.New,
+			jobs.NewRunner,
+			// Accounts
+			accounts.NewRepository,
+			accounts.New,
+			// Financial
+			financial.NewRepository,
+			financial.New,
+			// Application
+			NewApplication,
+		),
+		fx.Invoke(financial.RegisterJobHandlers),
 	)
 }
 
@@ -0,0 +1,311 @@
+package financial
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Audited entity types
+const (
+	AuditEntityTransaction    = "transaction"
+	AuditEntityCategoryBudget = "category_budget"
+	AuditEntityPattern        = "pattern"
+	AuditEntityPlannedEntry   = "planned_entry"
+	AuditEntitySavingsGoal    = "savings_goal"
+	AuditEntityMonth          = "month" // Entity ID is YYYYMM
+)
+
+// Audited actions
+const (
+	AuditActionCreate   = "create"
+	AuditActionUpdate   = "update"
+	AuditActionDelete   = "delete"
+	AuditActionClose    = "close"
+	AuditActionReopen   = "reopen" // Months and savings goals
+	AuditActionComplete = "complete"
+)
+
+var auditEntityTypes = []string{
+	AuditEntityTransaction, AuditEntityCategoryBudget, AuditEntityPattern,
+	AuditEntityPlannedEntry, AuditEntitySavingsGoal, AuditEntityMonth,
+}
+
+var auditActions = []string{
+	AuditActionCreate, AuditActionUpdate, AuditActionDelete,
+	AuditActionClose, AuditActionReopen, AuditActionComplete,
+}
+
+// auditIgnoredFields change on every write, or are too bulky to repeat, and
+// say nothing about what the user did. Category budgets have no JSON tags, so
+// their timestamps keep the Go field names.
+var auditIgnoredFields = map[string]bool{
+	"created_at":   true,
+	"updated_at":   true,
+	"CreatedAt":    true,
+	"UpdatedAt":    true,
+	"raw_ofx_data": true,
+}
+
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 200
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetAuditLogInput struct {
+	OrganizationID int
+	EntityType     string
+	EntityID       *int // Requires EntityType
+	UserID         *int
+	Action         string
+	From           string // YYYY-MM-DD, inclusive
+	To             string // YYYY-MM-DD, inclusive
+	Cursor         string // NextCursor from the previous page
+	Limit          int
+}
+
+type AuditLogEntry struct {
+	AuditLogID int             `json:"audit_log_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UserID     *int            `json:"user_id"` // nil for scheduled jobs and deleted users
+	UserName   *string         `json:"user_name"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int             `json:"entity_id"`
+	Action     string          `json:"action"`
+	Changes    json.RawMessage `json:"changes"` // {"field": {"before": ..., "after": ...}}
+}
+
+type AuditLogPage struct {
+	Entries    []AuditLogEntry `json:"entries"`
+	NextCursor *string         `json:"next_cursor"` // nil on the last page
+}
+
+// AuditChange is a field's value before and after a write. Before is nil for
+// creations and After for deletions.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// auditRecord describes one write. Before is nil for creations and After for
+// deletions; both are DTOs, so fields are named as in the API.
+type auditRecord struct {
+	OrganizationID int
+	UserID         int // 0 for scheduled jobs
+	EntityType     string
+	EntityID       int
+	Action         string
+	Before         any
+	After          any
+}
+
+// auditMonthState is what the audit log records of a month closing or
+// reopening; the budgets and snapshots involved are not repeated.
+type auditMonthState struct {
+	Closed  bool             `json:"closed"`
+	Surplus *decimal.Decimal `json:"surplus,omitempty"`
+	Reason  string           `json:"reason,omitempty"`
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// GetAuditLog lists the organization's audit log, newest first. Filtering by
+// entity type and ID gives that entity's history.
+func (s *service) GetAuditLog(ctx context.Context, input GetAuditLogInput) (AuditLogPage, error) {
+	params := fetchAuditLogParams{
+		OrganizationID: input.OrganizationID,
+		EntityID:       input.EntityID,
+		UserID:         input.UserID,
+		Limit:          input.Limit,
+	}
+
+	if input.EntityType != "" {
+		if !slices.Contains(auditEntityTypes, input.EntityType) {
+			return AuditLogPage{}, errors.Wrap(internalerrors.ErrInvalidFormat, "invalid entity type %q", input.EntityType)
+		}
+		params.EntityType = &input.EntityType
+	} else if input.EntityID != nil {
+		return AuditLogPage{}, errors.Wrap(internalerrors.ErrMissingRequiredFields, "entity_id requires entity_type")
+	}
+	if input.Action != "" {
+		if !slices.Contains(auditActions, input.Action) {
+			return AuditLogPage{}, errors.Wrap(internalerrors.ErrInvalidFormat, "invalid action %q", input.Action)
+		}
+		params.Action = &input.Action
+	}
+
+	if input.From != "" {
+		from, err := parseTransactionDate(input.From)
+		if err != nil {
+			return AuditLogPage{}, internalerrors.NewInvalidTimeFormatError("from")
+		}
+		params.From = &from
+	}
+	if input.To != "" {
+		to, err := parseTransactionDate(input.To)
+		if err != nil {
+			return AuditLogPage{}, internalerrors.NewInvalidTimeFormatError("to")
+		}
+		to = to.AddDate(0, 0, 1)
+		params.To = &to
+	}
+
+	if input.Cursor != "" {
+		beforeID, err := strconv.Atoi(input.Cursor)
+		if err != nil || beforeID <= 0 {
+			return AuditLogPage{}, internalerrors.ErrInvalidCursor
+		}
+		params.BeforeID = &beforeID
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = defaultAuditLogLimit
+	}
+	if params.Limit > maxAuditLogLimit {
+		params.Limit = maxAuditLogLimit
+	}
+	pageSize := params.Limit
+	params.Limit++ // One extra row tells whether another page exists
+
+	models, err := s.Repository.FetchAuditLog(ctx, params)
+	if err != nil {
+		return AuditLogPage{}, errors.Wrap(err, "failed to fetch audit log")
+	}
+
+	page := AuditLogPage{Entries: make([]AuditLogEntry, 0, min(len(models), pageSize))}
+	if len(models) > pageSize {
+		models = models[:pageSize]
+		next := strconv.Itoa(models[pageSize-1].AuditLogID)
+		page.NextCursor = &next
+	}
+	for _, model := range models {
+		page.Entries = append(page.Entries, AuditLogEntry{}.FromModel(&model))
+	}
+
+	return page, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (e AuditLogEntry) FromModel(model *AuditLogModel) AuditLogEntry {
+	return AuditLogEntry{
+		AuditLogID: model.AuditLogID,
+		CreatedAt:  model.CreatedAt,
+		UserID:     model.UserID,
+		UserName:   model.UserName,
+		EntityType: model.EntityType,
+		EntityID:   model.EntityID,
+		Action:     model.Action,
+		Changes:    json.RawMessage(model.Changes),
+	}
+}
+
+// recordAudit appends a write to the audit log. Entries are best-effort: they
+// are not written in the same database transaction as the change, which has
+// already happened by the time it is recorded, so a failure here is logged
+// rather than returned. Updates that changed nothing are not recorded.
+func (s *service) recordAudit(ctx context.Context, record auditRecord) {
+	changes, err := auditChanges(record.Before, record.After)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to compute audit log changes",
+			"entity_type", record.EntityType,
+			"entity_id", record.EntityID,
+			"error", err.Error(),
+		)
+		return
+	}
+	if record.Action == AuditActionUpdate && len(changes) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(changes)
+	if err == nil {
+		var userID *int
+		if record.UserID != 0 {
+			userID = &record.UserID
+		}
+		err = s.Repository.InsertAuditLogEntry(ctx, insertAuditLogEntryParams{
+			OrganizationID: record.OrganizationID,
+			UserID:         userID,
+			EntityType:     record.EntityType,
+			EntityID:       record.EntityID,
+			Action:         record.Action,
+			Changes:        data,
+		})
+	}
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to record audit log entry",
+			"entity_type", record.EntityType,
+			"entity_id", record.EntityID,
+			"action", record.Action,
+			"error", err.Error(),
+		)
+	}
+}
+
+// auditChanges compares the JSON forms of before and after field by field.
+// Fields that are null on both sides, or unchanged, are left out.
+func auditChanges(before, after any) (map[string]AuditChange, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]AuditChange)
+	for name, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[name]) {
+			changes[name] = AuditChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok && value != nil {
+			changes[name] = AuditChange{After: value}
+		}
+	}
+	for name := range auditIgnoredFields {
+		delete(changes, name)
+	}
+	return changes, nil
+}
+
+func auditFields(value any) (map[string]any, error) {
+	fields := map[string]any{}
+	if value == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keeps amounts exact
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// auditMonthID identifies a month in the audit log as YYYYMM.
+func auditMonthID(month, year int) int {
+	return year*100 + month
+}
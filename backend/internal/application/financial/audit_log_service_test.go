@@ -0,0 +1,118 @@
+package financial
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateTransaction_RecordsChangedFieldsOnly(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, system: system.NewSystem()}
+	ctx := context.Background()
+	june := time.Date(2026, time.June, 18, 0, 0, 0, 0, time.UTC)
+	before := TransactionModel{
+		TransactionID: 4, TransactionDate: june, Description: "PADARIA", Amount: decimal.NewFromInt(20),
+	}
+	after := before
+	after.Description = "Padaria do bairro"
+	after.UpdatedAt = june.Add(time.Hour)
+
+	mockRepo.On("FetchTransactionByID", ctx, fetchTransactionByIDParams{TransactionID: 4, OrganizationID: 9}).Return(before, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, transactionMonthClosureParams{OrganizationID: 9, TransactionDate: june}).Return(false, nil)
+	mockRepo.On("ModifyTransaction", ctx, mock.Anything).Return(after, nil)
+
+	var recorded insertAuditLogEntryParams
+	mockRepo.On("InsertAuditLogEntry", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = args.Get(1).(insertAuditLogEntryParams)
+	}).Return(nil).Once()
+
+	description := "Padaria do bairro"
+	_, err := svc.UpdateTransaction(ctx, UpdateTransactionInput{
+		TransactionID: 4, UserID: 3, OrganizationID: 9, Description: &description,
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, recorded.UserID)
+	assert.Equal(t, 3, *recorded.UserID)
+	assert.Equal(t, AuditEntityTransaction, recorded.EntityType)
+	assert.Equal(t, AuditActionUpdate, recorded.Action)
+
+	var changes map[string]AuditChange
+	require.NoError(t, json.Unmarshal(recorded.Changes, &changes))
+	assert.Equal(t, map[string]AuditChange{
+		"description": {Before: "PADARIA", After: "Padaria do bairro"},
+	}, changes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTransaction_SkipsAuditWhenNothingChanged(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, system: system.NewSystem()}
+	ctx := context.Background()
+	tx := TransactionModel{TransactionID: 4, TransactionDate: time.Date(2026, time.June, 18, 0, 0, 0, 0, time.UTC)}
+
+	mockRepo.On("FetchTransactionByID", ctx, mock.Anything).Return(tx, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.Anything).Return(false, nil)
+	mockRepo.On("ModifyTransaction", ctx, mock.Anything).Return(tx, nil)
+
+	_, err := svc.UpdateTransaction(ctx, UpdateTransactionInput{TransactionID: 4, UserID: 3, OrganizationID: 9})
+
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "InsertAuditLogEntry", mock.Anything, mock.Anything)
+}
+
+func TestGetAuditLog_PagesWithCursor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+	entityType := AuditEntityPattern
+	entityID := 12
+
+	mockRepo.On("FetchAuditLog", ctx, mock.MatchedBy(func(params fetchAuditLogParams) bool {
+		return params.BeforeID == nil && params.Limit == 3 &&
+			*params.EntityType == entityType && *params.EntityID == entityID &&
+			params.To.Equal(time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC))
+	})).Return([]AuditLogModel{
+		{AuditLogID: 30, EntityType: entityType, EntityID: entityID, Action: AuditActionDelete, Changes: []byte(`{}`)},
+		{AuditLogID: 21, EntityType: entityType, EntityID: entityID, Action: AuditActionUpdate, Changes: []byte(`{}`)},
+		{AuditLogID: 8, EntityType: entityType, EntityID: entityID, Action: AuditActionCreate, Changes: []byte(`{}`)},
+	}, nil).Once()
+
+	page, err := svc.GetAuditLog(ctx, GetAuditLogInput{
+		OrganizationID: 1,
+		EntityType:     entityType,
+		EntityID:       &entityID,
+		To:             "2026-06-30",
+		Limit:          2,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	require.NotNil(t, page.NextCursor)
+	assert.Equal(t, "21", *page.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetAuditLog_RejectsInvalidFilters(t *testing.T) {
+	svc := &service{Repository: new(MockRepository)}
+	ctx := context.Background()
+	entityID := 12
+
+	_, err := svc.GetAuditLog(ctx, GetAuditLogInput{OrganizationID: 1, EntityID: &entityID})
+	assert.ErrorIs(t, err, internalerrors.ErrMissingRequiredFields)
+
+	_, err = svc.GetAuditLog(ctx, GetAuditLogInput{OrganizationID: 1, EntityType: "account"})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidFormat)
+
+	_, err = svc.GetAuditLog(ctx, GetAuditLogInput{OrganizationID: 1, Cursor: "abc"})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidCursor)
+}
@@ -44,6 +44,7 @@ type ControllableCategoryPacing struct {
 	DaysInMonth        int              `json:"days_in_month"`
 	ProgressPercentage float64          `json:"progress_percentage"` // % of month elapsed
 	Categories         []CategoryPacing `json:"categories"`
+	MissingRates       int              `json:"missing_rates"` // Foreign-currency spending left out for lack of an exchange rate
 }
 
 // GetControllableCategoryPacingInput contains params for getting pacing data
@@ -130,6 +131,7 @@ func (s *service) GetControllableCategoryPacing(ctx context.Context, input GetCo
 		OrganizationID: input.OrganizationID,
 		Month:          input.Month,
 		Year:           input.Year,
+		BudgetMonth:    true,
 	})
 	if err != nil {
 		return nil, err
@@ -145,9 +147,19 @@ func (s *service) GetControllableCategoryPacing(ctx context.Context, input GetCo
 	if err != nil {
 		return nil, err
 	}
-	matchedSet := make(map[int]struct{}, len(matchedIDs))
+	// Payments between our own accounts (e.g. a card bill paid from checking)
+	// are not spending either.
+	excludedSet, err := s.transferTransactionSet(ctx, input.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
 	for _, id := range matchedIDs {
-		matchedSet[id] = struct{}{}
+		excludedSet[id] = struct{}{}
+	}
+
+	splits, err := s.fetchSplitsByTransaction(ctx, input.OrganizationID, input.Month, input.Year)
+	if err != nil {
+		return nil, err
 	}
 
 	granularityByCategory := make(map[int]int)
@@ -157,15 +169,20 @@ func (s *service) GetControllableCategoryPacing(ctx context.Context, input GetCo
 			OrganizationID: input.OrganizationID,
 			Month:          previousMonth,
 			Year:           previousYear,
+			BudgetMonth:    true,
 		})
 		if err != nil {
 			return nil, err
 		}
-		granularityByCategory = countControlledTransactionsByCategory(previousTransactions, matchedSet)
+		previousSplits, err := s.fetchSplitsByTransaction(ctx, input.OrganizationID, previousMonth, previousYear)
+		if err != nil {
+			return nil, err
+		}
+		granularityByCategory = countControlledTransactionsByCategory(previousTransactions, previousSplits, excludedSet)
 	}
 
 	// Calculate unplanned spending by category
-	spendingByCategory := sumControlledSpendingByCategory(transactions, matchedSet)
+	spendingByCategory, missingRates := sumControlledSpendingByCategory(transactions, splits, excludedSet)
 
 	// Build pacing data for each controllable category
 	categoryPacingList := make([]CategoryPacing, 0, len(controllableCategories))
@@ -257,6 +274,7 @@ func (s *service) GetControllableCategoryPacing(ctx context.Context, input GetCo
 		DaysInMonth:        daysInMonth,
 		ProgressPercentage: progressPercentage,
 		Categories:         categoryPacingList,
+		MissingRates:       missingRates,
 	}, nil
 }
 
@@ -297,30 +315,85 @@ func expectedSpendForGranularity(budget decimal.Decimal, currentDay int, daysInM
 	return expected
 }
 
-func countControlledTransactionsByCategory(transactions []TransactionModel, matchedSet map[int]struct{}) map[int]int {
+// fetchSplitsByTransaction loads the month's transaction splits keyed by their
+// parent transaction.
+func (s *service) fetchSplitsByTransaction(ctx context.Context, organizationID, month, year int) (map[int][]TransactionSplitModel, error) {
+	models, err := s.Repository.FetchTransactionSplitsByMonth(ctx, fetchTransactionSplitsByMonthParams{
+		OrganizationID: organizationID,
+		Month:          month,
+		Year:           year,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	splits := make(map[int][]TransactionSplitModel)
+	for _, split := range models {
+		splits[split.TransactionID] = append(splits[split.TransactionID], split)
+	}
+	return splits, nil
+}
+
+// A split transaction counts once per split category, each split with its own
+// amount, instead of once for the parent's category.
+func countControlledTransactionsByCategory(transactions []TransactionModel, splits map[int][]TransactionSplitModel, excludedSet map[int]struct{}) map[int]int {
 	counts := make(map[int]int)
 	for _, tx := range transactions {
-		if _, isPlanned := matchedSet[tx.TransactionID]; isPlanned {
+		if _, isExcluded := excludedSet[tx.TransactionID]; isExcluded {
+			continue
+		}
+		if tx.TransactionType != TransactionTypeDebit || tx.IsIgnored {
+			continue
+		}
+		if txSplits, ok := splits[tx.TransactionID]; ok {
+			for _, split := range txSplits {
+				if split.CategoryID != nil {
+					counts[*split.CategoryID]++
+				}
+			}
 			continue
 		}
-		if tx.TransactionType == TransactionTypeDebit && tx.CategoryID != nil && !tx.IsIgnored {
+		if tx.CategoryID != nil {
 			counts[*tx.CategoryID]++
 		}
 	}
 	return counts
 }
 
-func sumControlledSpendingByCategory(transactions []TransactionModel, matchedSet map[int]struct{}) map[int]decimal.Decimal {
+// sumControlledSpendingByCategory also counts the transactions left out for
+// lack of an exchange rate.
+func sumControlledSpendingByCategory(transactions []TransactionModel, splits map[int][]TransactionSplitModel, excludedSet map[int]struct{}) (map[int]decimal.Decimal, int) {
 	spendingByCategory := make(map[int]decimal.Decimal)
+	missingRates := 0
 	for _, tx := range transactions {
-		if _, isPlanned := matchedSet[tx.TransactionID]; isPlanned {
+		if _, isExcluded := excludedSet[tx.TransactionID]; isExcluded {
 			continue
 		}
-		if tx.TransactionType == TransactionTypeDebit && tx.CategoryID != nil && !tx.IsIgnored {
+		if tx.TransactionType != TransactionTypeDebit || tx.IsIgnored {
+			continue
+		}
+		if txSplits, ok := splits[tx.TransactionID]; ok {
+			converted := true
+			for _, split := range txSplits {
+				if split.CategoryID != nil {
+					amount, ok := convertedAmount(&tx, split.Amount)
+					converted = converted && ok
+					spendingByCategory[*split.CategoryID] = spendingByCategory[*split.CategoryID].Add(amount)
+				}
+			}
+			if !converted {
+				missingRates++
+			}
+			continue
+		}
+		if tx.CategoryID != nil {
 			catID := *tx.CategoryID
-			current := spendingByCategory[catID]
-			spendingByCategory[catID] = current.Add(tx.Amount)
+			amount, ok := convertedAmount(&tx, tx.Amount)
+			if !ok {
+				missingRates++
+			}
+			spendingByCategory[catID] = spendingByCategory[catID].Add(amount)
 		}
 	}
-	return spendingByCategory
+	return spendingByCategory, missingRates
 }
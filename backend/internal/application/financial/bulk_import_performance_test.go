@@ -113,7 +113,7 @@ func TestBulkImportPerformance_1000Transactions(t *testing.T) {
 		}
 	}
 
-	mockRepo.On("IsMonthClosed", ctx, mock.Anything).Return(false, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.Anything).Return(false, nil)
 	mockRepo.On("BulkInsertTransactions", ctx, mock.Anything).Return(insertedModels, nil)
 
 	// Mock auto-matching (return empty patterns to skip matching)
@@ -124,6 +124,7 @@ func TestBulkImportPerformance_1000Transactions(t *testing.T) {
 	}, nil).Maybe()
 	mockRepo.On("FetchSavedPatterns", ctx, mock.Anything).Return([]PlannedEntryModel{}, nil).Maybe()
 	mockRepo.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{}, nil).Maybe()
+	mockRepo.On("FetchClassificationRules", ctx, mock.Anything).Return([]ClassificationRuleModel{}, nil).Maybe()
 
 	importStart := time.Now()
 	output, err := svc.ImportTransactionsFromOFX(ctx, ImportOFXInput{
@@ -191,7 +192,7 @@ func TestBulkImportPerformance_5000Transactions(t *testing.T) {
 		}
 	}
 
-	mockRepo.On("IsMonthClosed", ctx, mock.Anything).Return(false, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.Anything).Return(false, nil)
 	mockRepo.On("BulkInsertTransactions", ctx, mock.Anything).Return(insertedModels, nil)
 	mockRepo.On("FetchTransactionByID", ctx, mock.Anything).Return(TransactionModel{
 		TransactionID: 1,
@@ -199,6 +200,7 @@ func TestBulkImportPerformance_5000Transactions(t *testing.T) {
 	}, nil).Maybe()
 	mockRepo.On("FetchSavedPatterns", ctx, mock.Anything).Return([]PlannedEntryModel{}, nil).Maybe()
 	mockRepo.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{}, nil).Maybe()
+	mockRepo.On("FetchClassificationRules", ctx, mock.Anything).Return([]ClassificationRuleModel{}, nil).Maybe()
 
 	importStart := time.Now()
 	output, err := svc.ImportTransactionsFromOFX(ctx, ImportOFXInput{
@@ -265,7 +267,7 @@ func TestBulkImportPerformance_10000Transactions(t *testing.T) {
 		}
 	}
 
-	mockRepo.On("IsMonthClosed", ctx, mock.Anything).Return(false, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.Anything).Return(false, nil)
 	mockRepo.On("BulkInsertTransactions", ctx, mock.Anything).Return(insertedModels, nil)
 	mockRepo.On("FetchTransactionByID", ctx, mock.Anything).Return(TransactionModel{
 		TransactionID: 1,
@@ -273,6 +275,7 @@ func TestBulkImportPerformance_10000Transactions(t *testing.T) {
 	}, nil).Maybe()
 	mockRepo.On("FetchSavedPatterns", ctx, mock.Anything).Return([]PlannedEntryModel{}, nil).Maybe()
 	mockRepo.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{}, nil).Maybe()
+	mockRepo.On("FetchClassificationRules", ctx, mock.Anything).Return([]ClassificationRuleModel{}, nil).Maybe()
 
 	importStart := time.Now()
 	output, err := svc.ImportTransactionsFromOFX(ctx, ImportOFXInput{
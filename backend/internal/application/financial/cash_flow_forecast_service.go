@@ -0,0 +1,396 @@
+package financial
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultForecastMonths = 3
+	maxForecastMonths     = 24
+)
+
+// Where the money of a forecast item comes from
+const (
+	CashFlowSourcePlanned     = "planned"
+	CashFlowSourceSavingsGoal = "savings_goal"
+	CashFlowSourceInstallment = "installment"
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+// GetCashFlowForecastInput asks for daily balances from today through the end
+// of the Months-th month, counting the current one.
+type GetCashFlowForecastInput struct {
+	UserID         int
+	OrganizationID int
+	Months         int  // Defaults to 3
+	AccountID      *int // Account entries never matched before settle in; defaults to the checking account with the largest balance
+}
+
+// CashFlowForecastItem is a planned entry expected to move money on a day.
+type CashFlowForecastItem struct {
+	PlannedEntryID int             `json:"planned_entry_id"`
+	AccountID      int             `json:"account_id"`
+	Description    string          `json:"description"`
+	Amount         decimal.Decimal `json:"amount"` // Negative for expenses
+	Source         string          `json:"source"`
+}
+
+type CashFlowForecastDay struct {
+	Date    time.Time              `json:"date"`
+	Inflow  decimal.Decimal        `json:"inflow"`
+	Outflow decimal.Decimal        `json:"outflow"`
+	Balance decimal.Decimal        `json:"balance"`
+	Items   []CashFlowForecastItem `json:"items,omitempty"`
+}
+
+type AccountCashFlowForecast struct {
+	AccountID         int                   `json:"account_id"`
+	Name              string                `json:"name"`
+	AccountType       string                `json:"account_type"`
+	StartingBalance   decimal.Decimal       `json:"starting_balance"`
+	EndingBalance     decimal.Decimal       `json:"ending_balance"`
+	LowestBalance     decimal.Decimal       `json:"lowest_balance"`
+	LowestBalanceDate time.Time             `json:"lowest_balance_date"`
+	FirstNegativeDate *time.Time            `json:"first_negative_date,omitempty"` // Checking accounts only
+	Days              []CashFlowForecastDay `json:"days"`
+}
+
+// CashFlowForecast projects the balances of the organization's active accounts
+// in its base currency, credit cards aside, from their current balance and
+// the planned entries not yet matched or dismissed. Days hold the
+// organization's total.
+type CashFlowForecast struct {
+	From              time.Time                 `json:"from"`
+	To                time.Time                 `json:"to"`
+	Currency          string                    `json:"currency"`
+	StartingBalance   decimal.Decimal           `json:"starting_balance"`
+	EndingBalance     decimal.Decimal           `json:"ending_balance"`
+	LowestBalance     decimal.Decimal           `json:"lowest_balance"`
+	LowestBalanceDate time.Time                 `json:"lowest_balance_date"`
+	Accounts          []AccountCashFlowForecast `json:"accounts"`
+	Days              []CashFlowForecastDay     `json:"days"`
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// GetCashFlowForecast places every unmatched planned entry of the coming months
+// (recurring income and expenses, savings goal contributions and projected
+// installment parcels) on the day it is expected, and walks the balances
+// forward. An entry settles in the account of the transaction it was last
+// matched to, or in the default account. Entries already overdue are expected
+// today.
+func (s *service) GetCashFlowForecast(ctx context.Context, input GetCashFlowForecastInput) (CashFlowForecast, error) {
+	months := input.Months
+	if months == 0 {
+		months = defaultForecastMonths
+	}
+	if months < 1 || months > maxForecastMonths {
+		return CashFlowForecast{}, errors.Wrap(internalerrors.ErrInvalidForecast, "months must be between 1 and %d", maxForecastMonths)
+	}
+
+	baseCurrency, err := s.GetBaseCurrency(ctx, GetBaseCurrencyInput{OrganizationID: input.OrganizationID})
+	if err != nil {
+		return CashFlowForecast{}, err
+	}
+	accountsByID, err := s.fetchAccountsByID(ctx, input.OrganizationID)
+	if err != nil {
+		return CashFlowForecast{}, err
+	}
+
+	var accounts []AccountModel
+	for _, account := range accountsByID {
+		if account.IsActive && account.AccountType != AccountTypeCreditCard &&
+			(account.Currency == "" || account.Currency == baseCurrency) {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].AccountID < accounts[j].AccountID })
+
+	defaultAccountID, err := forecastDefaultAccount(accounts, input.AccountID)
+	if err != nil {
+		return CashFlowForecast{}, err
+	}
+
+	today := truncateToDay(s.system.Time.Now())
+	firstMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	forecast := CashFlowForecast{
+		From:     today,
+		To:       firstMonth.AddDate(0, months, -1),
+		Currency: baseCurrency,
+		Accounts: []AccountCashFlowForecast{},
+		Days:     []CashFlowForecastDay{},
+	}
+	if len(accounts) == 0 {
+		return forecast, nil
+	}
+
+	items, err := s.forecastPlannedItems(ctx, input, firstMonth, months, today, accounts, defaultAccountID, accountsByID)
+	if err != nil {
+		return CashFlowForecast{}, err
+	}
+
+	walkCashFlowForecast(&forecast, accounts, items)
+	return forecast, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// forecastPlannedItems returns the forecast items of each day, from the
+// planned entries of the months starting on firstMonth.
+func (s *service) forecastPlannedItems(ctx context.Context, input GetCashFlowForecastInput, firstMonth time.Time, months int, today time.Time, accounts []AccountModel, defaultAccountID int, accountsByID map[int]AccountModel) (map[time.Time][]CashFlowForecastItem, error) {
+	settledIn, err := s.Repository.FetchPlannedEntrySettlementAccounts(ctx, fetchPlannedEntrySettlementAccountsParams{
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch planned entry accounts")
+	}
+	forecasted := make(map[int]bool, len(accounts))
+	for _, account := range accounts {
+		forecasted[account.AccountID] = true
+	}
+
+	dueDays, err := s.installmentEntryDueDays(ctx, input.OrganizationID, accountsByID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Weekly entries fall on several days; their rule is on the model
+	isActive := true
+	models, err := s.Repository.FetchPlannedEntries(ctx, fetchPlannedEntriesParams{
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+		IsActive:       &isActive,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch planned entries")
+	}
+	modelsByID := make(map[int]PlannedEntryModel, len(models))
+	for _, model := range models {
+		modelsByID[model.PlannedEntryID] = model
+	}
+
+	items := make(map[time.Time][]CashFlowForecastItem)
+	for i := 0; i < months; i++ {
+		monthStart := firstMonth.AddDate(0, i, 0)
+		entries, err := s.GetPlannedEntriesForMonth(ctx, GetPlannedEntriesForMonthInput{
+			UserID:         input.UserID,
+			OrganizationID: input.OrganizationID,
+			Month:          int(monthStart.Month()),
+			Year:           monthStart.Year(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.Status == PlannedEntryStatusMatched || entry.Status == PlannedEntryStatusDismissed {
+				continue
+			}
+
+			item := CashFlowForecastItem{
+				PlannedEntryID: entry.PlannedEntryID,
+				AccountID:      defaultAccountID,
+				Description:    entry.Description,
+				Amount:         entry.Amount,
+				Source:         CashFlowSourcePlanned,
+			}
+			if accountID, ok := settledIn[entry.PlannedEntryID]; ok && forecasted[accountID] {
+				item.AccountID = accountID
+			}
+			if entry.EntryType != PlannedEntryTypeIncome {
+				item.Amount = item.Amount.Neg()
+			}
+			dueDay, isInstallment := dueDays[entry.PlannedEntryID]
+			switch {
+			case isInstallment:
+				item.Source = CashFlowSourceInstallment
+			case entry.SavingsGoalID != nil:
+				item.Source = CashFlowSourceSavingsGoal
+			}
+
+			var dates []time.Time
+			if model, ok := modelsByID[entry.PlannedEntryID]; ok && model.IsRecurrent && model.RecurrenceFrequency == RecurrenceFrequencyWeekly {
+				dates = weeklyOccurrenceDates(model, int(monthStart.Month()), monthStart.Year())
+			} else {
+				dates = []time.Time{clampedDate(monthStart, plannedEntryExpectedDay(entry.PlannedEntry, dueDay))}
+			}
+			for _, date := range dates {
+				if date.Before(today) {
+					date = today
+				}
+				items[date] = append(items[date], item)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// installmentEntryDueDays maps the planned entries of projected installment
+// parcels to the due day of their card's invoice, 0 when the card has none.
+func (s *service) installmentEntryDueDays(ctx context.Context, organizationID int, accountsByID map[int]AccountModel) (map[int]int, error) {
+	plans, err := s.Repository.FetchInstallmentPlans(ctx, fetchInstallmentPlansParams{OrganizationID: organizationID})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch installment plans")
+	}
+	parcels, err := s.Repository.FetchInstallmentParcels(ctx, fetchInstallmentParcelsParams{OrganizationID: organizationID})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch installment parcels")
+	}
+
+	planAccounts := make(map[int]int, len(plans))
+	for _, plan := range plans {
+		planAccounts[plan.InstallmentPlanID] = plan.AccountID
+	}
+
+	dueDays := make(map[int]int)
+	for _, parcel := range parcels {
+		if parcel.PlannedEntryID == nil {
+			continue
+		}
+		dueDay := 0
+		if card, ok := accountsByID[planAccounts[parcel.InstallmentPlanID]]; ok && card.CardDueDay != nil {
+			dueDay = *card.CardDueDay
+		}
+		dueDays[*parcel.PlannedEntryID] = dueDay
+	}
+	return dueDays, nil
+}
+
+// forecastDefaultAccount picks the account entries without a matching history
+// settle in: the one asked for, or the checking account with the largest
+// balance, or the first account when there is no checking account.
+func forecastDefaultAccount(accounts []AccountModel, accountID *int) (int, error) {
+	if accountID != nil {
+		for _, account := range accounts {
+			if account.AccountID == *accountID {
+				return account.AccountID, nil
+			}
+		}
+		return 0, errors.Wrap(internalerrors.ErrInvalidForecast, "account %d is not an active cash account", *accountID)
+	}
+
+	defaultAccount := -1
+	for i, account := range accounts {
+		if account.AccountType != AccountTypeChecking {
+			continue
+		}
+		if defaultAccount < 0 || account.Balance.GreaterThan(accounts[defaultAccount].Balance) {
+			defaultAccount = i
+		}
+	}
+	if defaultAccount < 0 {
+		if len(accounts) == 0 {
+			return 0, nil
+		}
+		defaultAccount = 0
+	}
+	return accounts[defaultAccount].AccountID, nil
+}
+
+// plannedEntryExpectedDay is the day of the month an entry is forecast on:
+// the card's due day for installment parcels, otherwise the start of its
+// expected range for expenses and the end for income, so the forecast errs on
+// the side of less money. Entries without a day are forecast at the start of
+// the month (expenses) or the end (income).
+func plannedEntryExpectedDay(entry PlannedEntry, dueDay int) int {
+	if dueDay > 0 {
+		return dueDay
+	}
+
+	candidates := []*int{entry.ExpectedDayStart, entry.ExpectedDay, entry.ExpectedDayEnd}
+	fallback := 1
+	if entry.EntryType == PlannedEntryTypeIncome {
+		candidates = []*int{entry.ExpectedDayEnd, entry.ExpectedDay, entry.ExpectedDayStart}
+		fallback = 31
+	}
+	for _, day := range candidates {
+		if day != nil {
+			return *day
+		}
+	}
+	return fallback
+}
+
+// walkCashFlowForecast fills the days of forecast, applying each day's items
+// to the balances of accounts.
+func walkCashFlowForecast(forecast *CashFlowForecast, accounts []AccountModel, items map[time.Time][]CashFlowForecastItem) {
+	balances := make(map[int]decimal.Decimal, len(accounts))
+	total := decimal.Zero
+	for _, account := range accounts {
+		balances[account.AccountID] = account.Balance
+		total = total.Add(account.Balance)
+		forecast.Accounts = append(forecast.Accounts, AccountCashFlowForecast{
+			AccountID:         account.AccountID,
+			Name:              account.Name,
+			AccountType:       account.AccountType,
+			StartingBalance:   account.Balance,
+			LowestBalance:     account.Balance,
+			LowestBalanceDate: forecast.From,
+			Days:              []CashFlowForecastDay{},
+		})
+	}
+	forecast.StartingBalance = total
+	forecast.LowestBalance = total
+	forecast.LowestBalanceDate = forecast.From
+
+	for day := forecast.From; !day.After(forecast.To); day = day.AddDate(0, 0, 1) {
+		dayItems := items[day]
+		sort.SliceStable(dayItems, func(i, j int) bool { return dayItems[i].AccountID < dayItems[j].AccountID })
+
+		orgDay := CashFlowForecastDay{Date: day, Inflow: decimal.Zero, Outflow: decimal.Zero, Items: dayItems}
+		for i := range forecast.Accounts {
+			accountForecast := &forecast.Accounts[i]
+			accountDay := CashFlowForecastDay{Date: day, Inflow: decimal.Zero, Outflow: decimal.Zero}
+			for _, item := range dayItems {
+				if item.AccountID != accountForecast.AccountID {
+					continue
+				}
+				if item.Amount.IsNegative() {
+					accountDay.Outflow = accountDay.Outflow.Add(item.Amount.Neg())
+				} else {
+					accountDay.Inflow = accountDay.Inflow.Add(item.Amount)
+				}
+			}
+
+			balance := balances[accountForecast.AccountID].Add(accountDay.Inflow).Sub(accountDay.Outflow)
+			balances[accountForecast.AccountID] = balance
+			accountDay.Balance = balance
+			accountForecast.Days = append(accountForecast.Days, accountDay)
+			accountForecast.EndingBalance = balance
+			if balance.LessThan(accountForecast.LowestBalance) {
+				accountForecast.LowestBalance = balance
+				accountForecast.LowestBalanceDate = day
+			}
+			if accountForecast.AccountType == AccountTypeChecking && accountForecast.FirstNegativeDate == nil && balance.IsNegative() {
+				date := day
+				accountForecast.FirstNegativeDate = &date
+			}
+
+			orgDay.Inflow = orgDay.Inflow.Add(accountDay.Inflow)
+			orgDay.Outflow = orgDay.Outflow.Add(accountDay.Outflow)
+		}
+
+		total = total.Add(orgDay.Inflow).Sub(orgDay.Outflow)
+		orgDay.Balance = total
+		forecast.Days = append(forecast.Days, orgDay)
+		if total.LessThan(forecast.LowestBalance) {
+			forecast.LowestBalance = total
+			forecast.LowestBalanceDate = day
+		}
+	}
+	forecast.EndingBalance = total
+}
@@ -0,0 +1,126 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCashFlowForecast(t *testing.T) {
+	mockRepo := new(MockRepository)
+	stub := system.NewStubSystem()
+	stub.Time.SetTimes(time.Date(2026, time.March, 20, 9, 0, 0, 0, time.UTC))
+	svc := &service{Repository: mockRepo, system: stub.ToSystem(), logger: &logging.TestLogger{}}
+	ctx := context.Background()
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	dueDay, closingDay := 5, 25
+	rentDay, salaryDay := 10, 5
+	april, year := 4, 2026
+
+	mockRepo.On("FetchBaseCurrency", ctx, fetchBaseCurrencyParams{OrganizationID: 1}).Return("BRL", nil)
+	mockRepo.On("FetchAccounts", ctx, fetchAccountsParams{OrganizationID: 1}).Return([]AccountModel{
+		{AccountID: 1, Name: "Conta corrente", AccountType: AccountTypeChecking, Currency: "BRL", Balance: decimal.NewFromInt(1000), IsActive: true},
+		{AccountID: 2, Name: "Poupança", AccountType: AccountTypeSavings, Currency: "BRL", Balance: decimal.NewFromInt(5000), IsActive: true},
+		{AccountID: 3, Name: "Cartão", AccountType: AccountTypeCreditCard, Currency: "BRL", Balance: decimal.NewFromInt(-800), IsActive: true,
+			CardClosingDay: &closingDay, CardDueDay: &dueDay},
+		{AccountID: 4, Name: "Conta em dólar", AccountType: AccountTypeChecking, Currency: "USD", Balance: decimal.NewFromInt(300), IsActive: true},
+	}, nil)
+	mockRepo.On("FetchPlannedEntrySettlementAccounts", ctx, fetchPlannedEntrySettlementAccountsParams{OrganizationID: 1}).
+		Return(map[int]int{10: 1, 11: 4}, nil)
+	mockRepo.On("FetchInstallmentPlans", ctx, fetchInstallmentPlansParams{OrganizationID: 1}).
+		Return([]InstallmentPlanModel{{InstallmentPlanID: 7, AccountID: 3}}, nil)
+	parcelEntryID := 20
+	mockRepo.On("FetchInstallmentParcels", ctx, fetchInstallmentParcelsParams{OrganizationID: 1}).
+		Return([]InstallmentParcelModel{{InstallmentPlanID: 7, InstallmentNumber: 3, Month: 4, Year: 2026, PlannedEntryID: &parcelEntryID}}, nil)
+
+	rent := PlannedEntryModel{PlannedEntryID: 10, Description: "Aluguel", Amount: decimal.NewFromInt(1500), EntryType: PlannedEntryTypeExpense,
+		ExpectedDayStart: &rentDay, IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 1,
+		CreatedAt: day(time.January, 5), IsActive: true}
+	salary := PlannedEntryModel{PlannedEntryID: 11, Description: "Salário", Amount: decimal.NewFromInt(3000), EntryType: PlannedEntryTypeIncome,
+		ExpectedDayEnd: &salaryDay, IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 1,
+		CreatedAt: day(time.January, 5), IsActive: true}
+	parcel := PlannedEntryModel{PlannedEntryID: 20, Description: "LOJA X (03/04)", Amount: decimal.NewFromInt(200), EntryType: PlannedEntryTypeExpense,
+		TargetMonth: &april, TargetYear: &year, IsActive: true}
+	mockRepo.On("FetchPlannedEntries", ctx, mock.MatchedBy(func(params fetchPlannedEntriesParams) bool { return params.IsRecurrent == nil })).
+		Return([]PlannedEntryModel{rent, salary, parcel}, nil)
+	mockRepo.On("FetchPlannedEntries", ctx, mock.MatchedBy(func(params fetchPlannedEntriesParams) bool {
+		return params.IsRecurrent != nil && *params.IsRecurrent
+	})).Return([]PlannedEntryModel{rent, salary}, nil)
+	mockRepo.On("FetchPlannedEntries", ctx, mock.MatchedBy(func(params fetchPlannedEntriesParams) bool {
+		return params.IsRecurrent != nil && !*params.IsRecurrent
+	})).Return([]PlannedEntryModel{parcel}, nil)
+	mockRepo.On("FetchSavingsGoals", ctx, mock.Anything).Return([]SavingsGoalModel{}, nil)
+	mockRepo.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{}, nil)
+	mockRepo.On("FetchTagsByPlannedEntryID", ctx, mock.Anything).Return([]TagModel{}, nil)
+	// March's salary already came in
+	mockRepo.On("FetchPlannedEntryStatusesByMonth", ctx, mock.MatchedBy(func(params fetchPlannedEntryStatusesByMonthParams) bool { return params.Month == 3 })).
+		Return([]PlannedEntryStatusModel{{PlannedEntryID: 11, Month: 3, Year: 2026, Status: PlannedEntryStatusMatched}}, nil)
+	mockRepo.On("FetchPlannedEntryStatusesByMonth", ctx, mock.MatchedBy(func(params fetchPlannedEntryStatusesByMonthParams) bool { return params.Month == 4 })).
+		Return([]PlannedEntryStatusModel{}, nil)
+
+	forecast, err := svc.GetCashFlowForecast(ctx, GetCashFlowForecastInput{UserID: 2, OrganizationID: 1, Months: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, day(time.March, 20), forecast.From)
+	assert.Equal(t, day(time.April, 30), forecast.To)
+	assert.Len(t, forecast.Days, 42)
+	assert.Equal(t, "6000", forecast.StartingBalance.String())
+	assert.Equal(t, "5800", forecast.EndingBalance.String())
+	assert.Equal(t, "4500", forecast.LowestBalance.String())
+	assert.Equal(t, day(time.March, 20), forecast.LowestBalanceDate)
+
+	// March's rent is overdue and expected today; April's salary and the card
+	// parcel land on the 5th, the rent on the 10th
+	require.Len(t, forecast.Accounts, 2)
+	checking := forecast.Accounts[0]
+	assert.Equal(t, "-500", checking.Days[0].Balance.String())
+	require.NotNil(t, checking.FirstNegativeDate)
+	assert.Equal(t, day(time.March, 20), *checking.FirstNegativeDate)
+	aprilFifth := checking.Days[16]
+	assert.Equal(t, day(time.April, 5), aprilFifth.Date)
+	assert.Equal(t, "3000", aprilFifth.Inflow.String())
+	assert.Equal(t, "200", aprilFifth.Outflow.String())
+	assert.Equal(t, "2300", aprilFifth.Balance.String())
+	assert.Equal(t, "800", checking.EndingBalance.String())
+	assert.Equal(t, "5000", forecast.Accounts[1].EndingBalance.String())
+	assert.Nil(t, forecast.Accounts[1].FirstNegativeDate)
+
+	require.Len(t, forecast.Days[16].Items, 2)
+	assert.ElementsMatch(t, []string{CashFlowSourcePlanned, CashFlowSourceInstallment},
+		[]string{forecast.Days[16].Items[0].Source, forecast.Days[16].Items[1].Source})
+}
+
+func TestGetCashFlowForecast_RejectsInvalidInput(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	_, err := svc.GetCashFlowForecast(ctx, GetCashFlowForecastInput{OrganizationID: 1, Months: 30})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidForecast)
+
+	mockRepo.On("FetchBaseCurrency", ctx, fetchBaseCurrencyParams{OrganizationID: 1}).Return("BRL", nil)
+	mockRepo.On("FetchAccounts", ctx, fetchAccountsParams{OrganizationID: 1}).Return([]AccountModel{
+		{AccountID: 3, AccountType: AccountTypeCreditCard, IsActive: true},
+	}, nil)
+	accountID := 3
+	_, err = svc.GetCashFlowForecast(ctx, GetCashFlowForecastInput{OrganizationID: 1, AccountID: &accountID})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidForecast)
+}
+
+func TestPlannedEntryExpectedDay(t *testing.T) {
+	start, end := 10, 15
+
+	assert.Equal(t, 10, plannedEntryExpectedDay(PlannedEntry{EntryType: PlannedEntryTypeExpense, ExpectedDayStart: &start, ExpectedDayEnd: &end}, 0))
+	assert.Equal(t, 15, plannedEntryExpectedDay(PlannedEntry{EntryType: PlannedEntryTypeIncome, ExpectedDayStart: &start, ExpectedDayEnd: &end}, 0))
+	assert.Equal(t, 1, plannedEntryExpectedDay(PlannedEntry{EntryType: PlannedEntryTypeExpense}, 0))
+	assert.Equal(t, 31, plannedEntryExpectedDay(PlannedEntry{EntryType: PlannedEntryTypeIncome}, 0))
+	assert.Equal(t, 7, plannedEntryExpectedDay(PlannedEntry{EntryType: PlannedEntryTypeExpense, ExpectedDayStart: &start}, 7))
+}
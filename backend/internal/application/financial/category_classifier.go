@@ -0,0 +1,149 @@
+package financial
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+const (
+	// Below these the classifier has too little to go on and predicts nothing
+	minCategoryClassifierTransactions = 10
+	minCategoryClassifierCategories   = 2
+
+	categorySuggestionCount = 3
+)
+
+// CategorySuggestion is a category predicted for a transaction. Confidences
+// of all the categories considered add up to 1.
+type CategorySuggestion struct {
+	CategoryID int     `json:"category_id"`
+	Confidence float64 `json:"confidence"`
+}
+
+// categoryClassifier is a multinomial naive Bayes model of an organization's
+// categorized transactions, over description words, amount bucket, weekday
+// and account. It is cheap to train, so it is built when needed rather than
+// stored.
+type categoryClassifier struct {
+	transactions  int
+	categoryCount map[int]int            // Training transactions per category
+	featureCounts map[int]map[string]int // Feature occurrences per category
+	featureTotals map[int]int            // All feature occurrences per category
+	vocabulary    map[string]bool
+}
+
+// trainCategoryClassifier learns from the categorized transactions. Ones that
+// still need review are left out so the model does not learn from its own
+// unconfirmed predictions.
+func trainCategoryClassifier(transactions []TransactionModel) *categoryClassifier {
+	classifier := &categoryClassifier{
+		categoryCount: make(map[int]int),
+		featureCounts: make(map[int]map[string]int),
+		featureTotals: make(map[int]int),
+		vocabulary:    make(map[string]bool),
+	}
+
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.CategoryID == nil || tx.NeedsReview || tx.IsIgnored {
+			continue
+		}
+		categoryID := *tx.CategoryID
+
+		classifier.transactions++
+		classifier.categoryCount[categoryID]++
+		if classifier.featureCounts[categoryID] == nil {
+			classifier.featureCounts[categoryID] = make(map[string]int)
+		}
+		for _, feature := range categoryFeatures(tx) {
+			classifier.featureCounts[categoryID][feature]++
+			classifier.featureTotals[categoryID]++
+			classifier.vocabulary[feature] = true
+		}
+	}
+
+	return classifier
+}
+
+func (c *categoryClassifier) ready() bool {
+	return c.transactions >= minCategoryClassifierTransactions && len(c.categoryCount) >= minCategoryClassifierCategories
+}
+
+// predict ranks the categories allowed for the transaction, most likely
+// first, and returns the top few. allowed may be nil to consider them all.
+func (c *categoryClassifier) predict(tx *TransactionModel, allowed func(categoryID int) bool) []CategorySuggestion {
+	if !c.ready() {
+		return nil
+	}
+
+	features := categoryFeatures(tx)
+	vocabularySize := float64(len(c.vocabulary))
+
+	type score struct {
+		categoryID int
+		logProb    float64
+	}
+	scores := make([]score, 0, len(c.categoryCount))
+	for categoryID, count := range c.categoryCount {
+		if allowed != nil && !allowed(categoryID) {
+			continue
+		}
+		// Laplace smoothing keeps unseen features from ruling a category out
+		logProb := math.Log(float64(count) / float64(c.transactions))
+		denominator := float64(c.featureTotals[categoryID]) + vocabularySize
+		for _, feature := range features {
+			logProb += math.Log((float64(c.featureCounts[categoryID][feature]) + 1) / denominator)
+		}
+		scores = append(scores, score{categoryID: categoryID, logProb: logProb})
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].logProb != scores[j].logProb {
+			return scores[i].logProb > scores[j].logProb
+		}
+		return scores[i].categoryID < scores[j].categoryID
+	})
+
+	// Normalize against the best score so the exponentials do not underflow
+	var sum float64
+	for _, s := range scores {
+		sum += math.Exp(s.logProb - scores[0].logProb)
+	}
+	suggestions := make([]CategorySuggestion, 0, categorySuggestionCount)
+	for _, s := range scores[:min(len(scores), categorySuggestionCount)] {
+		suggestions = append(suggestions, CategorySuggestion{
+			CategoryID: s.categoryID,
+			Confidence: math.Exp(s.logProb-scores[0].logProb) / sum,
+		})
+	}
+	return suggestions
+}
+
+// categoryFeatures describes a transaction for the classifier: the words of
+// the description patterns match against, the order of magnitude of the
+// amount by direction, the weekday and the account.
+func categoryFeatures(tx *TransactionModel) []string {
+	words := descriptionWords(patternMatchDescription(tx))
+	features := make([]string, 0, len(words)+3)
+
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			features = append(features, "word:"+word)
+		}
+	}
+
+	amount, _ := tx.Amount.Abs().Float64()
+	bucket := int(math.Log2(amount + 1))
+	features = append(features,
+		"amount:"+tx.TransactionType+":"+strconv.Itoa(bucket),
+		"weekday:"+strconv.Itoa(int(tx.TransactionDate.Weekday())),
+		"account:"+strconv.Itoa(tx.AccountID),
+	)
+	return features
+}
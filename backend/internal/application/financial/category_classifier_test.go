@@ -0,0 +1,69 @@
+package financial
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// categorizedHistory is a month of bakery and fuel purchases, five of each.
+func categorizedHistory(bakery, fuel int) []TransactionModel {
+	monday := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+	var history []TransactionModel
+	for i := 0; i < 5; i++ {
+		history = append(history,
+			TransactionModel{TransactionID: 100 + i, AccountID: 1, CategoryID: &bakery, Description: "PADARIA PAO QUENTE", Amount: decimal.NewFromInt(-12), TransactionType: "debit", TransactionDate: monday.AddDate(0, 0, 7*i)},
+			TransactionModel{TransactionID: 200 + i, AccountID: 2, CategoryID: &fuel, Description: "POSTO SHELL", Amount: decimal.NewFromInt(-250), TransactionType: "debit", TransactionDate: monday.AddDate(0, 0, 7*i+4)},
+		)
+	}
+	return history
+}
+
+func TestCategoryClassifier_RanksCategoriesByLikelihood(t *testing.T) {
+	bakery, fuel := 4, 9
+	classifier := trainCategoryClassifier(categorizedHistory(bakery, fuel))
+
+	suggestions := classifier.predict(&TransactionModel{
+		AccountID:       1,
+		Description:     "PADARIA PAO QUENTE 03/04",
+		Amount:          decimal.NewFromInt(-15),
+		TransactionType: "debit",
+		TransactionDate: time.Date(2026, time.April, 6, 0, 0, 0, 0, time.UTC),
+	}, nil)
+
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, bakery, suggestions[0].CategoryID)
+	assert.Greater(t, suggestions[0].Confidence, 0.99)
+	assert.Equal(t, fuel, suggestions[1].CategoryID)
+	assert.InDelta(t, 1, suggestions[0].Confidence+suggestions[1].Confidence, 1e-9)
+}
+
+func TestCategoryClassifier_SkipsDisallowedCategories(t *testing.T) {
+	bakery, fuel := 4, 9
+	classifier := trainCategoryClassifier(categorizedHistory(bakery, fuel))
+
+	suggestions := classifier.predict(&TransactionModel{Description: "PADARIA PAO QUENTE", TransactionType: "debit"}, func(categoryID int) bool {
+		return categoryID != bakery
+	})
+
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, fuel, suggestions[0].CategoryID)
+	assert.Equal(t, 1.0, suggestions[0].Confidence)
+}
+
+func TestCategoryClassifier_NeedsEnoughConfirmedHistory(t *testing.T) {
+	bakery, fuel := 4, 9
+	history := categorizedHistory(bakery, fuel)
+	// Predictions awaiting review are not learned from
+	for i := 1; i < len(history); i++ {
+		history[i].NeedsReview = true
+	}
+
+	classifier := trainCategoryClassifier(history)
+
+	assert.False(t, classifier.ready())
+	assert.Nil(t, classifier.predict(&TransactionModel{Description: "PADARIA"}, nil))
+}
@@ -0,0 +1,242 @@
+package financial
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+)
+
+const (
+	defaultAutoCategorizeMinConfidence = 0.9
+	categoryPredictorTTL               = 15 * time.Minute
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type AutoCategorizeTransactionsInput struct {
+	UserID         int
+	OrganizationID int
+	MinConfidence  float64 // In (0, 1]; defaults to 0.9
+}
+
+type AutoCategorizeTransactionsOutput struct {
+	CheckedCount   int   `json:"checked_count"`
+	AppliedCount   int   `json:"applied_count"`
+	SkippedCount   int   `json:"skipped_count"` // Predicted but not writable, e.g. in a closed month
+	TransactionIDs []int `json:"transaction_ids"`
+}
+
+// categoryPredictor ranks the categories that fit a transaction.
+type categoryPredictor func(tx *TransactionModel) []CategorySuggestion
+
+// categoryPredictorCache keeps each organization's trained predictor for
+// categoryPredictorTTL. Categorizations made in the meantime are picked up
+// on the next training.
+type categoryPredictorCache struct {
+	mu      sync.Mutex
+	entries map[int]categoryPredictorCacheEntry
+}
+
+type categoryPredictorCacheEntry struct {
+	predictor categoryPredictor
+	trainedAt time.Time
+}
+
+func newCategoryPredictorCache() *categoryPredictorCache {
+	return &categoryPredictorCache{entries: make(map[int]categoryPredictorCacheEntry)}
+}
+
+func (c *categoryPredictorCache) get(organizationID int, now time.Time) (categoryPredictor, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[organizationID]
+	if !ok || now.Sub(entry.trainedAt) >= categoryPredictorTTL {
+		return nil, false
+	}
+	return entry.predictor, true
+}
+
+func (c *categoryPredictorCache) put(organizationID int, predictor categoryPredictor, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, entry := range c.entries {
+		if now.Sub(entry.trainedAt) >= categoryPredictorTTL {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[organizationID] = categoryPredictorCacheEntry{predictor: predictor, trainedAt: now}
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// AutoCategorizeTransactions applies the predicted category to every
+// uncategorized transaction whose top suggestion reaches MinConfidence, and
+// flags them for review.
+func (s *service) AutoCategorizeTransactions(ctx context.Context, input AutoCategorizeTransactionsInput) (AutoCategorizeTransactionsOutput, error) {
+	minConfidence := input.MinConfidence
+	if minConfidence == 0 {
+		minConfidence = defaultAutoCategorizeMinConfidence
+	}
+	if minConfidence < 0 || minConfidence > 1 {
+		return AutoCategorizeTransactionsOutput{}, errors.Wrap(internalerrors.ErrInvalidPredictionThreshold, "min_confidence must be between 0 and 1")
+	}
+
+	uncategorized, err := s.Repository.FetchUncategorizedTransactions(ctx, fetchUncategorizedTransactionsParams{
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return AutoCategorizeTransactionsOutput{}, errors.Wrap(err, "failed to fetch uncategorized transactions")
+	}
+
+	output := AutoCategorizeTransactionsOutput{
+		CheckedCount:   len(uncategorized),
+		TransactionIDs: []int{},
+	}
+	if len(uncategorized) == 0 {
+		return output, nil
+	}
+
+	predictor, err := s.newCategoryPredictor(ctx, input.OrganizationID)
+	if err != nil {
+		return AutoCategorizeTransactionsOutput{}, err
+	}
+
+	needsReview := true
+	for i := range uncategorized {
+		tx := &uncategorized[i]
+		suggestions := predictor(tx)
+		if len(suggestions) == 0 || suggestions[0].Confidence < minConfidence {
+			continue
+		}
+
+		// Transactions in closed months cannot change; they stay for review
+		// by hand rather than failing the whole run
+		updated, err := s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
+			TransactionID:  tx.TransactionID,
+			OrganizationID: input.OrganizationID,
+			CategoryID:     &suggestions[0].CategoryID,
+			NeedsReview:    &needsReview,
+		})
+		if err != nil {
+			s.logger.Warn(ctx, "Failed to auto-categorize transaction",
+				"transaction_id", tx.TransactionID,
+				"error", err.Error(),
+			)
+			output.SkippedCount++
+			continue
+		}
+
+		s.recordAudit(ctx, auditRecord{
+			OrganizationID: input.OrganizationID,
+			UserID:         input.UserID,
+			EntityType:     AuditEntityTransaction,
+			EntityID:       tx.TransactionID,
+			Action:         AuditActionUpdate,
+			Before:         Transaction{}.FromModel(tx),
+			After:          Transaction{}.FromModel(&updated),
+		})
+		output.AppliedCount++
+		output.TransactionIDs = append(output.TransactionIDs, tx.TransactionID)
+	}
+
+	s.logger.Info(ctx, "Transactions auto-categorized",
+		"organization_id", input.OrganizationID,
+		"checked", output.CheckedCount,
+		"applied", output.AppliedCount,
+		"skipped", output.SkippedCount,
+		"min_confidence", minConfidence,
+	)
+
+	return output, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// newCategoryPredictor trains the organization's classifier and returns a
+// function ranking the categories whose type fits a transaction's direction.
+func (s *service) newCategoryPredictor(ctx context.Context, organizationID int) (categoryPredictor, error) {
+	transactions, err := s.Repository.FetchTransactionsForPatternMatching(ctx, fetchTransactionsForPatternMatchingParams{
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch training transactions: %w", err)
+	}
+	categories, err := s.Repository.FetchCategories(ctx, fetchCategoriesParams{
+		OrganizationID: &organizationID,
+		IncludeSystem:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+
+	categoryTypes := make(map[int]string, len(categories))
+	for _, category := range categories {
+		categoryTypes[category.CategoryID] = category.CategoryType
+	}
+	classifier := trainCategoryClassifier(transactions)
+
+	return func(tx *TransactionModel) []CategorySuggestion {
+		return classifier.predict(tx, func(categoryID int) bool {
+			// Same rule as validateCategoryTransactionType; deleted categories
+			// are no longer offered
+			categoryType, ok := categoryTypes[categoryID]
+			if !ok {
+				return false
+			}
+			return !(categoryType == "income" && tx.TransactionType == "debit") &&
+				!(categoryType == "expense" && tx.TransactionType == "credit")
+		})
+	}, nil
+}
+
+// cachedCategoryPredictor returns the organization's predictor, training it
+// again once it is older than categoryPredictorTTL, so listing uncategorized
+// transactions does not retrain on every request.
+func (s *service) cachedCategoryPredictor(ctx context.Context, organizationID int) (categoryPredictor, error) {
+	if s.categoryPredictors == nil {
+		return s.newCategoryPredictor(ctx, organizationID)
+	}
+
+	now := s.system.Time.Now()
+	if predictor, ok := s.categoryPredictors.get(organizationID, now); ok {
+		return predictor, nil
+	}
+	predictor, err := s.newCategoryPredictor(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	s.categoryPredictors.put(organizationID, predictor, now)
+	return predictor, nil
+}
+
+// attachCategorySuggestions fills in the predicted categories of
+// uncategorized transactions. Predictions are a convenience, so failing to
+// train is logged and the transactions are returned without them.
+func (s *service) attachCategorySuggestions(ctx context.Context, organizationID int, models []TransactionModel, transactions []Transaction) {
+	if len(models) == 0 {
+		return
+	}
+
+	predictor, err := s.cachedCategoryPredictor(ctx, organizationID)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to train category classifier",
+			"organization_id", organizationID,
+			"error", err.Error(),
+		)
+		return
+	}
+
+	for i := range models {
+		transactions[i].CategorySuggestions = predictor(&models[i])
+	}
+}
@@ -0,0 +1,138 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoCategorizeTransactions_AppliesConfidentPredictionsForReview(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockRepository{}
+	bakery, fuel := 4, 9
+	date := time.Date(2026, time.April, 6, 0, 0, 0, 0, time.UTC)
+
+	repository.On("FetchUncategorizedTransactions", ctx, mock.Anything).Return([]TransactionModel{
+		{TransactionID: 1, AccountID: 1, Description: "PADARIA PAO QUENTE", Amount: decimal.NewFromInt(-14), TransactionType: "debit", TransactionDate: date},
+		{TransactionID: 2, AccountID: 3, Description: "FARMACIA", Amount: decimal.NewFromInt(-40), TransactionType: "debit", TransactionDate: date},
+	}, nil).Once()
+	repository.On("FetchTransactionsForPatternMatching", ctx, mock.Anything).Return(categorizedHistory(bakery, fuel), nil).Once()
+	repository.On("FetchCategories", ctx, mock.Anything).Return([]CategoryModel{
+		{CategoryID: bakery, CategoryType: "expense"},
+		{CategoryID: fuel, CategoryType: "expense"},
+	}, nil).Once()
+	repository.On("ModifyTransaction", ctx, mock.MatchedBy(func(params modifyTransactionParams) bool {
+		return params.TransactionID == 1 &&
+			params.CategoryID != nil && *params.CategoryID == bakery &&
+			params.NeedsReview != nil && *params.NeedsReview
+	})).Return(TransactionModel{TransactionID: 1, CategoryID: &bakery, NeedsReview: true}, nil).Once()
+	repository.On("InsertAuditLogEntry", ctx, mock.Anything).Return(nil).Once()
+
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+	output, err := svc.AutoCategorizeTransactions(ctx, AutoCategorizeTransactionsInput{UserID: 3, OrganizationID: 7})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, output.CheckedCount)
+	assert.Equal(t, 1, output.AppliedCount)
+	assert.Equal(t, []int{1}, output.TransactionIDs)
+	repository.AssertExpectations(t)
+}
+
+func TestAutoCategorizeTransactions_RejectsInvalidThreshold(t *testing.T) {
+	repository := &MockRepository{}
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+
+	_, err := svc.AutoCategorizeTransactions(context.Background(), AutoCategorizeTransactionsInput{OrganizationID: 7, MinConfidence: 1.5})
+
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidPredictionThreshold)
+	repository.AssertNotCalled(t, "FetchUncategorizedTransactions", mock.Anything, mock.Anything)
+}
+
+func TestGetUncategorizedTransactions_AttachesCategorySuggestions(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockRepository{}
+	bakery, fuel := 4, 9
+
+	repository.On("FetchUncategorizedTransactions", ctx, mock.Anything).Return([]TransactionModel{
+		{TransactionID: 1, AccountID: 2, Description: "POSTO SHELL 123", Amount: decimal.NewFromInt(-230), TransactionType: "debit"},
+	}, nil).Once()
+	repository.On("FetchTransactionsForPatternMatching", ctx, mock.Anything).Return(categorizedHistory(bakery, fuel), nil).Once()
+	repository.On("FetchCategories", ctx, mock.Anything).Return([]CategoryModel{
+		{CategoryID: bakery, CategoryType: "expense"},
+		{CategoryID: fuel, CategoryType: "expense"},
+	}, nil).Once()
+
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+	transactions, err := svc.GetUncategorizedTransactions(ctx, GetUncategorizedTransactionsInput{OrganizationID: 7, Limit: 10})
+
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	require.NotEmpty(t, transactions[0].CategorySuggestions)
+	assert.Equal(t, fuel, transactions[0].CategorySuggestions[0].CategoryID)
+	repository.AssertExpectations(t)
+}
+
+func TestAutoCategorizeTransactions_SkipsTransactionsThatCannotChange(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockRepository{}
+	bakery, fuel := 4, 9
+	date := time.Date(2026, time.April, 6, 0, 0, 0, 0, time.UTC)
+
+	repository.On("FetchUncategorizedTransactions", ctx, mock.Anything).Return([]TransactionModel{
+		{TransactionID: 1, AccountID: 1, Description: "PADARIA PAO QUENTE", Amount: decimal.NewFromInt(-14), TransactionType: "debit", TransactionDate: date},
+		{TransactionID: 2, AccountID: 2, Description: "POSTO SHELL", Amount: decimal.NewFromInt(-240), TransactionType: "debit", TransactionDate: date},
+	}, nil).Once()
+	repository.On("FetchTransactionsForPatternMatching", ctx, mock.Anything).Return(categorizedHistory(bakery, fuel), nil).Once()
+	repository.On("FetchCategories", ctx, mock.Anything).Return([]CategoryModel{
+		{CategoryID: bakery, CategoryType: "expense"},
+		{CategoryID: fuel, CategoryType: "expense"},
+	}, nil).Once()
+	repository.On("ModifyTransaction", ctx, mock.MatchedBy(func(params modifyTransactionParams) bool {
+		return params.TransactionID == 1
+	})).Return(TransactionModel{}, internalerrors.ErrMonthClosed).Once()
+	repository.On("ModifyTransaction", ctx, mock.MatchedBy(func(params modifyTransactionParams) bool {
+		return params.TransactionID == 2
+	})).Return(TransactionModel{TransactionID: 2, CategoryID: &fuel, NeedsReview: true}, nil).Once()
+	repository.On("InsertAuditLogEntry", ctx, mock.Anything).Return(nil).Once()
+
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+	output, err := svc.AutoCategorizeTransactions(ctx, AutoCategorizeTransactionsInput{UserID: 3, OrganizationID: 7})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, output.AppliedCount)
+	assert.Equal(t, 1, output.SkippedCount)
+	assert.Equal(t, []int{2}, output.TransactionIDs)
+	repository.AssertExpectations(t)
+}
+
+func TestGetUncategorizedTransactions_ReusesTrainedPredictor(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockRepository{}
+	bakery, fuel := 4, 9
+
+	repository.On("FetchUncategorizedTransactions", ctx, mock.Anything).Return([]TransactionModel{
+		{TransactionID: 1, AccountID: 2, Description: "POSTO SHELL 123", Amount: decimal.NewFromInt(-230), TransactionType: "debit"},
+	}, nil).Twice()
+	repository.On("FetchTransactionsForPatternMatching", ctx, mock.Anything).Return(categorizedHistory(bakery, fuel), nil).Once()
+	repository.On("FetchCategories", ctx, mock.Anything).Return([]CategoryModel{
+		{CategoryID: bakery, CategoryType: "expense"},
+		{CategoryID: fuel, CategoryType: "expense"},
+	}, nil).Once()
+
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}, system: system.NewSystem(), categoryPredictors: newCategoryPredictorCache()}
+	for i := 0; i < 2; i++ {
+		transactions, err := svc.GetUncategorizedTransactions(ctx, GetUncategorizedTransactionsInput{OrganizationID: 7, Limit: 10})
+		require.NoError(t, err)
+		require.NotEmpty(t, transactions[0].CategorySuggestions)
+	}
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,169 @@
+package financial
+
+import (
+	"context"
+	"strings"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+)
+
+const defaultClassificationRulePriority = 100
+
+// validateClassificationRuleConditions rejects rules that would match every
+// transaction or can never match.
+func validateClassificationRuleConditions(rule ClassificationRuleModel) error {
+	hasDescription := rule.MatchDescription != nil && strings.TrimSpace(*rule.MatchDescription) != ""
+	if !hasDescription && rule.MatchAmountMin == nil && rule.MatchAmountMax == nil && rule.MatchTransactionType == nil {
+		return errors.Wrap(internalerrors.ErrInvalidClassificationRule, "at least one match condition is required")
+	}
+	if rule.MatchTransactionType != nil && *rule.MatchTransactionType != "debit" && *rule.MatchTransactionType != "credit" {
+		return errors.Wrap(internalerrors.ErrInvalidClassificationRule, "match_transaction_type must be debit or credit")
+	}
+	if rule.MatchAmountMin != nil && rule.MatchAmountMax != nil && rule.MatchAmountMin.GreaterThan(*rule.MatchAmountMax) {
+		return errors.Wrap(internalerrors.ErrInvalidClassificationRule, "match_amount_min is greater than match_amount_max")
+	}
+	return nil
+}
+
+// matchesClassificationRule reports whether every condition set on the rule
+// holds for the transaction. Descriptions match as case-insensitive substrings
+// of the original (imported) description; amounts are compared as absolute
+// values, inclusive.
+func matchesClassificationRule(tx *TransactionModel, rule *ClassificationRuleModel) bool {
+	if rule.MatchDescription != nil && *rule.MatchDescription != "" {
+		description := tx.Description
+		if tx.OriginalDescription != nil && *tx.OriginalDescription != "" {
+			description = *tx.OriginalDescription
+		}
+		if !strings.Contains(strings.ToLower(description), strings.ToLower(*rule.MatchDescription)) {
+			return false
+		}
+	}
+
+	amount := tx.Amount.Abs()
+	if rule.MatchAmountMin != nil && amount.LessThan(*rule.MatchAmountMin) {
+		return false
+	}
+	if rule.MatchAmountMax != nil && amount.GreaterThan(*rule.MatchAmountMax) {
+		return false
+	}
+
+	if rule.MatchTransactionType != nil && !strings.EqualFold(tx.TransactionType, *rule.MatchTransactionType) {
+		return false
+	}
+
+	return true
+}
+
+type classifyImportedTransactionsOutput struct {
+	PatternMatchCount int
+	RuleMatchCount    int
+}
+
+// classifyImportedTransactions is the classification step shared by every
+// ingestion path. Each transaction goes through the regex patterns first,
+// since they can also rename it, ignore it or match planned entries; only
+// transactions no pattern handled are offered to the classification rules.
+func (s *service) classifyImportedTransactions(ctx context.Context, transactions []TransactionModel, userID, organizationID int) classifyImportedTransactionsOutput {
+	var output classifyImportedTransactionsOutput
+	if len(transactions) == 0 {
+		return output
+	}
+
+	isActive := true
+	rules, err := s.Repository.FetchClassificationRules(ctx, fetchClassificationRulesParams{
+		OrganizationID: organizationID,
+		IsActive:       &isActive,
+	})
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to fetch classification rules, importing without them",
+			"organization_id", organizationID,
+			"error", err.Error(),
+		)
+	}
+
+	for i := range transactions {
+		tx := &transactions[i]
+
+		matched, err := s.AutoApplyPatterns(ctx, ApplyPatternsToTransactionInput{
+			TransactionID:  tx.TransactionID,
+			UserID:         userID,
+			OrganizationID: organizationID,
+		})
+		if err != nil {
+			s.logger.Warn(ctx, "Failed to apply advanced patterns to transaction",
+				"transaction_id", tx.TransactionID,
+				"error", err.Error(),
+			)
+		}
+		if matched {
+			output.PatternMatchCount++
+			continue
+		}
+
+		if len(rules) > 0 && tx.CategoryID == nil && s.applyClassificationRules(ctx, tx, rules, organizationID) {
+			output.RuleMatchCount++
+		}
+	}
+
+	return output
+}
+
+// applyClassificationRules assigns the category of the first matching rule,
+// in priority order. Rules whose category type conflicts with the transaction
+// type (e.g. an income category on a debit) are skipped.
+func (s *service) applyClassificationRules(ctx context.Context, tx *TransactionModel, rules []ClassificationRuleModel, organizationID int) bool {
+	start := s.system.Time.Now()
+	if s.metrics != nil && s.metrics.ClassificationRuleExecutions != nil {
+		s.metrics.ClassificationRuleExecutions.Add(ctx, 1)
+	}
+	defer func() {
+		if s.metrics != nil && s.metrics.ClassificationRuleDuration != nil {
+			s.metrics.ClassificationRuleDuration.Record(ctx, s.system.Time.Now().Sub(start).Seconds())
+		}
+	}()
+
+	for i := range rules {
+		rule := &rules[i]
+		if !matchesClassificationRule(tx, rule) {
+			continue
+		}
+		if err := s.validateCategoryTransactionType(ctx, rule.CategoryID, tx.TransactionType, organizationID); err != nil {
+			continue
+		}
+
+		updated, err := s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
+			TransactionID:        tx.TransactionID,
+			OrganizationID:       organizationID,
+			CategoryID:           &rule.CategoryID,
+			ClassificationRuleID: &rule.RuleID,
+		})
+		if err != nil {
+			s.logger.Warn(ctx, "Failed to apply classification rule to transaction",
+				"rule_id", rule.RuleID,
+				"transaction_id", tx.TransactionID,
+				"error", err.Error(),
+			)
+			return false
+		}
+		// Rules run on their own during imports, so nobody is named as the
+		// author; the change to classification_rule_id names the rule
+		s.recordAudit(ctx, auditRecord{
+			OrganizationID: organizationID,
+			EntityType:     AuditEntityTransaction,
+			EntityID:       tx.TransactionID,
+			Action:         AuditActionUpdate,
+			Before:         Transaction{}.FromModel(tx),
+			After:          Transaction{}.FromModel(&updated),
+		})
+		*tx = updated
+
+		if s.metrics != nil && s.metrics.ClassificationRuleMatches != nil {
+			s.metrics.ClassificationRuleMatches.Add(ctx, 1)
+		}
+		return true
+	}
+
+	return false
+}
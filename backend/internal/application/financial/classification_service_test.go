@@ -0,0 +1,118 @@
+package financial
+
+import (
+	"context"
+	"testing"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesClassificationRule(t *testing.T) {
+	original := "UBER *TRIP SAO PAULO"
+	tx := &TransactionModel{
+		Description:         "Uber",
+		OriginalDescription: &original,
+		Amount:              decimal.NewFromFloat(23.9),
+		TransactionType:     TransactionTypeDebit,
+	}
+	text := func(s string) *string { return &s }
+	amount := func(f float64) *decimal.Decimal { d := decimal.NewFromFloat(f); return &d }
+
+	tests := []struct {
+		name string
+		rule ClassificationRuleModel
+		want bool
+	}{
+		{"description substring ignores case", ClassificationRuleModel{MatchDescription: text("uber *trip")}, true},
+		{"description matches original, not edited", ClassificationRuleModel{MatchDescription: text("sao paulo")}, true},
+		{"description mismatch", ClassificationRuleModel{MatchDescription: text("99 taxi")}, false},
+		{"amount range inclusive", ClassificationRuleModel{MatchAmountMin: amount(23.9), MatchAmountMax: amount(30)}, true},
+		{"amount below min", ClassificationRuleModel{MatchAmountMin: amount(50)}, false},
+		{"amount above max", ClassificationRuleModel{MatchAmountMax: amount(20)}, false},
+		{"transaction type", ClassificationRuleModel{MatchTransactionType: text("debit")}, true},
+		{"all conditions must hold", ClassificationRuleModel{MatchDescription: text("uber"), MatchTransactionType: text("credit")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchesClassificationRule(tx, &tt.rule))
+		})
+	}
+}
+
+func TestClassifyImportedTransactions_RulesRunWhenNoPatternMatches(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	svc := &service{
+		Repository: mockRepo,
+		system:     system.NewStubSystem().ToSystem(),
+		logger:     &logging.TestLogger{},
+	}
+
+	description := "POSTO SHELL"
+	tx := TransactionModel{TransactionID: 1, Description: description, OriginalDescription: &description,
+		Amount: decimal.NewFromInt(200), TransactionType: TransactionTypeDebit}
+	incomeOnly := "salario"
+	fuel := "shell"
+
+	mockRepo.On("FetchClassificationRules", ctx, mock.Anything).Return([]ClassificationRuleModel{
+		{RuleID: 4, CategoryID: 20, Priority: 10, MatchDescription: &incomeOnly},
+		{RuleID: 5, CategoryID: 30, Priority: 20, MatchDescription: &fuel},
+		{RuleID: 6, CategoryID: 40, Priority: 30, MatchDescription: &fuel},
+	}, nil)
+	mockRepo.On("FetchTransactionByID", ctx, fetchTransactionByIDParams{TransactionID: 1, OrganizationID: 9}).Return(tx, nil)
+	mockRepo.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{}, nil)
+	mockRepo.On("FetchCategoryByID", ctx, fetchCategoryByIDParams{CategoryID: 30, OrganizationID: 9}).
+		Return(CategoryModel{CategoryID: 30, CategoryType: "expense"}, nil)
+	ruleID, categoryID := 5, 30
+	mockRepo.On("ModifyTransaction", ctx, modifyTransactionParams{
+		TransactionID:        1,
+		OrganizationID:       9,
+		CategoryID:           &categoryID,
+		ClassificationRuleID: &ruleID,
+	}).Return(TransactionModel{TransactionID: 1, CategoryID: &categoryID, ClassificationRuleID: &ruleID, IsClassified: true}, nil)
+	mockRepo.On("InsertAuditLogEntry", ctx, mock.MatchedBy(func(params insertAuditLogEntryParams) bool {
+		return params.UserID == nil && params.EntityType == AuditEntityTransaction && params.EntityID == 1 &&
+			params.Action == AuditActionUpdate
+	})).Return(nil).Once()
+
+	transactions := []TransactionModel{tx}
+	result := svc.classifyImportedTransactions(ctx, transactions, 3, 9)
+
+	assert.Equal(t, 0, result.PatternMatchCount)
+	assert.Equal(t, 1, result.RuleMatchCount)
+	assert.True(t, transactions[0].IsClassified)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "FetchCategoryByID", ctx, fetchCategoryByIDParams{CategoryID: 40, OrganizationID: 9})
+}
+
+func TestReorderClassificationRules_RequiresEveryRuleOnce(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, logger: &logging.TestLogger{}}
+
+	mockRepo.On("FetchClassificationRules", ctx, fetchClassificationRulesParams{OrganizationID: 9}).
+		Return([]ClassificationRuleModel{{RuleID: 1}, {RuleID: 2}, {RuleID: 3}}, nil)
+
+	for _, ruleIDs := range [][]int{{3, 1}, {3, 1, 1}, {3, 1, 7}} {
+		_, err := svc.ReorderClassificationRules(ctx, ReorderClassificationRulesInput{OrganizationID: 9, RuleIDs: ruleIDs})
+		assert.True(t, errors.Is(err, internalerrors.ErrInvalidClassificationRule), "rule_ids %v", ruleIDs)
+	}
+	mockRepo.AssertNotCalled(t, "ModifyClassificationRulePriorities", mock.Anything, mock.Anything)
+
+	mockRepo.On("ModifyClassificationRulePriorities", ctx, modifyClassificationRulePrioritiesParams{
+		OrganizationID: 9,
+		RuleIDs:        []int{3, 1, 2},
+	}).Return([]ClassificationRuleModel{}, nil)
+
+	_, err := svc.ReorderClassificationRules(ctx, ReorderClassificationRulesInput{OrganizationID: 9, RuleIDs: []int{3, 1, 2}})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
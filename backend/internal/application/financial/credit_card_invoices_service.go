@@ -0,0 +1,386 @@
+package financial
+
+import (
+	"context"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Credit card invoice statuses
+const (
+	InvoiceStatusOpen   = "open"   // Still taking purchases
+	InvoiceStatusClosed = "closed" // Past the closing date, not fully paid
+	InvoiceStatusPaid   = "paid"
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+// GetCreditCardInvoicesInput lists the invoices due in Year (the current year
+// when zero), up to the one currently open.
+type GetCreditCardInvoicesInput struct {
+	AccountID      int
+	UserID         int
+	OrganizationID int
+	Year           int
+}
+
+type GetCreditCardInvoiceInput struct {
+	AccountID      int
+	UserID         int
+	OrganizationID int
+	Month          int // Month the invoice is due
+	Year           int
+}
+
+// SetCreditCardInvoicePaymentInput links the payment of an invoice. A nil
+// PaymentTransactionID goes back to counting transfers into the card.
+type SetCreditCardInvoicePaymentInput struct {
+	AccountID            int
+	UserID               int
+	OrganizationID       int
+	Month                int
+	Year                 int
+	PaymentTransactionID *int
+}
+
+// CreditCardInvoice groups the card transactions of one billing cycle, from
+// the day after the previous closing through ClosingDate. Total is purchases
+// minus refunds; payments into the card are reported in PaidAmount instead.
+type CreditCardInvoice struct {
+	AccountID             int             `json:"account_id"`
+	Month                 int             `json:"month"`
+	Year                  int             `json:"year"`
+	PeriodStart           time.Time       `json:"period_start"`
+	ClosingDate           time.Time       `json:"closing_date"`
+	DueDate               time.Time       `json:"due_date"`
+	Status                string          `json:"status"`
+	Total                 decimal.Decimal `json:"total"`
+	PaidAmount            decimal.Decimal `json:"paid_amount"`
+	PaymentTransactionIDs []int           `json:"payment_transaction_ids"`
+	Transactions          []Transaction   `json:"transactions,omitempty"`
+}
+
+// creditCardCycle is the billing cycle of the invoice due in one month
+type creditCardCycle struct {
+	previousClosing time.Time // Exclusive
+	closing         time.Time // Inclusive
+	nextClosing     time.Time
+	due             time.Time
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+func (s *service) GetCreditCardInvoices(ctx context.Context, input GetCreditCardInvoicesInput) ([]CreditCardInvoice, error) {
+	account, err := s.fetchCreditCardAccount(ctx, input.AccountID, input.UserID, input.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := truncateToDay(s.system.Time.Now())
+	year := input.Year
+	if year == 0 {
+		year = today.Year()
+	}
+
+	cycles := make(map[int]creditCardCycle)
+	lastMonth := 0
+	for month := 1; month <= 12; month++ {
+		cycle := creditCardInvoiceCycle(month, year, *account.CardClosingDay, *account.CardDueDay)
+		if !cycle.previousClosing.Before(today) {
+			break // Not started yet
+		}
+		cycles[month] = cycle
+		lastMonth = month
+	}
+	if lastMonth == 0 {
+		return []CreditCardInvoice{}, nil
+	}
+
+	invoices, err := s.buildCreditCardInvoices(ctx, account, year, 1, lastMonth, cycles, today)
+	if err != nil {
+		return nil, err
+	}
+	for i := range invoices {
+		invoices[i].Transactions = nil
+	}
+	return invoices, nil
+}
+
+func (s *service) GetCreditCardInvoice(ctx context.Context, input GetCreditCardInvoiceInput) (CreditCardInvoice, error) {
+	if input.Month < 1 || input.Month > 12 {
+		return CreditCardInvoice{}, errors.Wrap(internalerrors.ErrInvalidFormat, "invalid month %d", input.Month)
+	}
+
+	account, err := s.fetchCreditCardAccount(ctx, input.AccountID, input.UserID, input.OrganizationID)
+	if err != nil {
+		return CreditCardInvoice{}, err
+	}
+
+	cycles := map[int]creditCardCycle{
+		input.Month: creditCardInvoiceCycle(input.Month, input.Year, *account.CardClosingDay, *account.CardDueDay),
+	}
+	invoices, err := s.buildCreditCardInvoices(ctx, account, input.Year, input.Month, input.Month, cycles, truncateToDay(s.system.Time.Now()))
+	if err != nil {
+		return CreditCardInvoice{}, err
+	}
+	return invoices[0], nil
+}
+
+// SetCreditCardInvoicePayment links the transaction that paid an invoice: the
+// debit in another of the organization's accounts, or the credit it made on
+// the card itself.
+func (s *service) SetCreditCardInvoicePayment(ctx context.Context, input SetCreditCardInvoicePaymentInput) (CreditCardInvoice, error) {
+	if input.Month < 1 || input.Month > 12 {
+		return CreditCardInvoice{}, errors.Wrap(internalerrors.ErrInvalidFormat, "invalid month %d", input.Month)
+	}
+
+	if _, err := s.fetchCreditCardAccount(ctx, input.AccountID, input.UserID, input.OrganizationID); err != nil {
+		return CreditCardInvoice{}, err
+	}
+
+	if input.PaymentTransactionID != nil {
+		payment, err := s.Repository.FetchTransactionByID(ctx, fetchTransactionByIDParams{
+			TransactionID:  *input.PaymentTransactionID,
+			OrganizationID: input.OrganizationID,
+		})
+		if err != nil {
+			return CreditCardInvoice{}, errors.Wrap(err, "failed to fetch payment transaction")
+		}
+		fromOtherAccount := payment.TransactionType == TransactionTypeDebit && payment.AccountID != input.AccountID
+		onCard := payment.TransactionType == TransactionTypeCredit && payment.AccountID == input.AccountID
+		if !fromOtherAccount && !onCard {
+			return CreditCardInvoice{}, errors.Wrap(internalerrors.ErrInvalidFormat,
+				"the payment must be a debit from another account or a credit on the card")
+		}
+	}
+
+	if _, err := s.Repository.UpsertCreditCardInvoicePayment(ctx, upsertCreditCardInvoicePaymentParams{
+		OrganizationID:       input.OrganizationID,
+		AccountID:            input.AccountID,
+		Month:                input.Month,
+		Year:                 input.Year,
+		PaymentTransactionID: input.PaymentTransactionID,
+	}); err != nil {
+		return CreditCardInvoice{}, errors.Wrap(err, "failed to save invoice payment")
+	}
+
+	return s.GetCreditCardInvoice(ctx, GetCreditCardInvoiceInput{
+		AccountID:      input.AccountID,
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+		Month:          input.Month,
+		Year:           input.Year,
+	})
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (s *service) fetchCreditCardAccount(ctx context.Context, accountID, userID, organizationID int) (AccountModel, error) {
+	account, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+		AccountID:      accountID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return AccountModel{}, errors.Wrap(err, "failed to fetch account")
+	}
+	if account.AccountType != AccountTypeCreditCard || account.CardClosingDay == nil || account.CardDueDay == nil {
+		return AccountModel{}, errors.Wrap(internalerrors.ErrInvalidBillingCycle,
+			"account %d is not a credit card with closing and due days", accountID)
+	}
+	return account, nil
+}
+
+// buildCreditCardInvoices builds the invoices due from firstMonth through
+// lastMonth of year, for the cycles given, with their transactions.
+func (s *service) buildCreditCardInvoices(ctx context.Context, account AccountModel, year, firstMonth, lastMonth int, cycles map[int]creditCardCycle, today time.Time) ([]CreditCardInvoice, error) {
+	after := cycles[firstMonth].previousClosing
+	transactions, err := s.Repository.FetchAccountTransactionsBetween(ctx, fetchAccountTransactionsBetweenParams{
+		AccountID:      account.AccountID,
+		OrganizationID: account.OrganizationID,
+		After:          &after,
+		Through:        cycles[lastMonth].closing,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch card transactions")
+	}
+
+	transfers, err := s.Repository.FetchTransfers(ctx, fetchTransfersParams{OrganizationID: account.OrganizationID})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transfers")
+	}
+	transferLegs := make(map[int]struct{}, len(transfers)*2)
+	for _, transfer := range transfers {
+		transferLegs[transfer.DebitTransactionID] = struct{}{}
+		transferLegs[transfer.CreditTransactionID] = struct{}{}
+	}
+
+	stored, err := s.Repository.FetchCreditCardInvoices(ctx, fetchCreditCardInvoicesParams{
+		AccountID:      account.AccountID,
+		OrganizationID: account.OrganizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch invoices")
+	}
+	storedByMonth := make(map[int]CreditCardInvoiceModel)
+	// A payment linked to an invoice lands in a later cycle; when it is a
+	// credit on the card it is not a refund of that cycle's purchases
+	payments := make(map[int]struct{}, len(stored))
+	for _, invoice := range stored {
+		if invoice.Year == year {
+			storedByMonth[invoice.Month] = invoice
+		}
+		if invoice.PaymentTransactionID != nil {
+			payments[*invoice.PaymentTransactionID] = struct{}{}
+		}
+	}
+
+	invoices := make([]CreditCardInvoice, 0, lastMonth-firstMonth+1)
+	for month := firstMonth; month <= lastMonth; month++ {
+		cycle := cycles[month]
+		invoice := CreditCardInvoice{
+			AccountID:             account.AccountID,
+			Month:                 month,
+			Year:                  year,
+			PeriodStart:           cycle.previousClosing.AddDate(0, 0, 1),
+			ClosingDate:           cycle.closing,
+			DueDate:               cycle.due,
+			Total:                 decimal.Zero,
+			PaidAmount:            decimal.Zero,
+			PaymentTransactionIDs: []int{},
+			Transactions:          []Transaction{},
+		}
+
+		for i := range transactions {
+			tx := &transactions[i]
+			if !tx.TransactionDate.After(cycle.previousClosing) || tx.TransactionDate.After(cycle.closing) {
+				continue
+			}
+			invoice.Transactions = append(invoice.Transactions, Transaction{}.FromModel(tx))
+			if _, isTransfer := transferLegs[tx.TransactionID]; isTransfer {
+				continue
+			}
+			if _, isPayment := payments[tx.TransactionID]; isPayment {
+				continue
+			}
+			if tx.TransactionType == TransactionTypeCredit {
+				invoice.Total = invoice.Total.Sub(tx.Amount.Abs())
+			} else {
+				invoice.Total = invoice.Total.Add(tx.Amount.Abs())
+			}
+		}
+
+		if linked, ok := storedByMonth[month]; ok && linked.PaymentTransactionID != nil && linked.PaymentAmount != nil {
+			invoice.PaidAmount = *linked.PaymentAmount
+			invoice.PaymentTransactionIDs = append(invoice.PaymentTransactionIDs, *linked.PaymentTransactionID)
+		} else {
+			// Bill payments land on the card after closing, usually by the due
+			// date, and at the latest before the next closing.
+			for _, transfer := range transfers {
+				if transfer.CreditAccountID != account.AccountID ||
+					!transfer.CreditDate.After(cycle.closing) || transfer.CreditDate.After(cycle.nextClosing) {
+					continue
+				}
+				invoice.PaidAmount = invoice.PaidAmount.Add(transfer.CreditAmount.Abs())
+				invoice.PaymentTransactionIDs = append(invoice.PaymentTransactionIDs, transfer.DebitTransactionID)
+			}
+		}
+
+		switch {
+		case !today.After(cycle.closing):
+			invoice.Status = InvoiceStatusOpen
+		case invoice.PaidAmount.GreaterThanOrEqual(invoice.Total):
+			invoice.Status = InvoiceStatusPaid
+		default:
+			invoice.Status = InvoiceStatusClosed
+		}
+
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, nil
+}
+
+// creditCardInvoiceCycle returns the billing cycle of the invoice due in
+// month/year. It mirrors card_invoice_due_date in the database, which places
+// each purchase in its invoice.
+func creditCardInvoiceCycle(month, year, closingDay, dueDay int) creditCardCycle {
+	dueMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	closingMonth := dueMonth
+	if dueDay <= closingDay {
+		closingMonth = dueMonth.AddDate(0, -1, 0)
+	}
+
+	return creditCardCycle{
+		previousClosing: clampedDate(closingMonth.AddDate(0, -1, 0), closingDay),
+		closing:         clampedDate(closingMonth, closingDay),
+		nextClosing:     clampedDate(closingMonth.AddDate(0, 1, 0), closingDay),
+		due:             clampedDate(dueMonth, dueDay),
+	}
+}
+
+// creditCardInvoiceDueDate returns the due date of the invoice a purchase on
+// date falls in, like card_invoice_due_date in the database.
+func creditCardInvoiceDueDate(date time.Time, closingDay, dueDay int) time.Time {
+	cycleMonth := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if truncateToDay(date).After(clampedDate(cycleMonth, closingDay)) {
+		cycleMonth = cycleMonth.AddDate(0, 1, 0)
+	}
+	if dueDay <= closingDay {
+		cycleMonth = cycleMonth.AddDate(0, 1, 0)
+	}
+	return clampedDate(cycleMonth, dueDay)
+}
+
+// transactionBudgetMonth returns the first day of the month a transaction on
+// date counts in for budgets, like transaction_budget_date in the database.
+func transactionBudgetMonth(account *AccountModel, date time.Time) time.Time {
+	if account.BudgetByInvoice && account.CardClosingDay != nil && account.CardDueDay != nil {
+		date = creditCardInvoiceDueDate(date, *account.CardClosingDay, *account.CardDueDay)
+	}
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// clampedDate returns day of the month starting at monthStart, or the month's
+// last day when it is shorter.
+func clampedDate(monthStart time.Time, day int) time.Time {
+	lastDay := monthStart.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(monthStart.Year(), monthStart.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// validateCardBillingCycle checks an account's card settings: closing and due
+// days come together, only on credit cards, and budgeting by invoice needs
+// them.
+func validateCardBillingCycle(accountType string, closingDay, dueDay *int, budgetByInvoice bool) error {
+	if (closingDay == nil) != (dueDay == nil) {
+		return errors.Wrap(internalerrors.ErrInvalidBillingCycle, "closing day and due day must be set together")
+	}
+	if closingDay != nil {
+		if accountType != AccountTypeCreditCard {
+			return errors.Wrap(internalerrors.ErrInvalidBillingCycle, "only credit cards have a billing cycle")
+		}
+		if *closingDay < 1 || *closingDay > 31 || *dueDay < 1 || *dueDay > 31 {
+			return errors.Wrap(internalerrors.ErrInvalidBillingCycle, "closing and due days must be between 1 and 31")
+		}
+	}
+	if budgetByInvoice && closingDay == nil {
+		return errors.Wrap(internalerrors.ErrInvalidBillingCycle, "budgeting by invoice needs closing and due days")
+	}
+	return nil
+}
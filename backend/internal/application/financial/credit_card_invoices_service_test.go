@@ -0,0 +1,192 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreditCardInvoiceCycle(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name                string
+		month, year         int
+		closingDay, dueDay  int
+		wantPreviousClosing time.Time
+		wantClosing         time.Time
+		wantDue             time.Time
+	}{
+		{
+			name: "due after closing in the same month", month: 3, year: 2026, closingDay: 3, dueDay: 10,
+			wantPreviousClosing: date(2026, time.February, 3), wantClosing: date(2026, time.March, 3), wantDue: date(2026, time.March, 10),
+		},
+		{
+			name: "due in the month after closing", month: 1, year: 2026, closingDay: 25, dueDay: 5,
+			wantPreviousClosing: date(2025, time.November, 25), wantClosing: date(2025, time.December, 25), wantDue: date(2026, time.January, 5),
+		},
+		{
+			name: "days past the end of a short month", month: 3, year: 2026, closingDay: 31, dueDay: 31,
+			wantPreviousClosing: date(2026, time.January, 31), wantClosing: date(2026, time.February, 28), wantDue: date(2026, time.March, 31),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cycle := creditCardInvoiceCycle(tt.month, tt.year, tt.closingDay, tt.dueDay)
+
+			assert.Equal(t, tt.wantPreviousClosing, cycle.previousClosing)
+			assert.Equal(t, tt.wantClosing, cycle.closing)
+			assert.Equal(t, tt.wantDue, cycle.due)
+		})
+	}
+}
+
+func TestGetCreditCardInvoices_TotalsAndStatus(t *testing.T) {
+	mockRepo := new(MockRepository)
+	stub := system.NewStubSystem()
+	stub.Time.SetTimes(time.Date(2026, time.March, 2, 12, 0, 0, 0, time.UTC))
+	svc := &service{Repository: mockRepo, system: stub.ToSystem()}
+	ctx := context.Background()
+	closingDay, dueDay := 25, 5
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{
+		AccountID:      3,
+		OrganizationID: 1,
+		AccountType:    AccountTypeCreditCard,
+		CardClosingDay: &closingDay,
+		CardDueDay:     &dueDay,
+	}, nil)
+	mockRepo.On("FetchAccountTransactionsBetween", ctx, mock.MatchedBy(func(params fetchAccountTransactionsBetweenParams) bool {
+		return params.After.Equal(time.Date(2025, time.November, 25, 0, 0, 0, 0, time.UTC)) &&
+			params.Through.Equal(day(time.March, 25))
+	})).Return([]TransactionModel{
+		{TransactionID: 1, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(300), TransactionDate: day(time.January, 10)},
+		{TransactionID: 2, TransactionType: TransactionTypeCredit, Amount: decimal.NewFromInt(50), TransactionDate: day(time.January, 20)},
+		{TransactionID: 3, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(400), TransactionDate: day(time.February, 10)},
+		// Payment of the January invoice, linked as a transfer from checking
+		{TransactionID: 4, TransactionType: TransactionTypeCredit, Amount: decimal.NewFromInt(250), TransactionDate: day(time.February, 5)},
+		{TransactionID: 5, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(80), TransactionDate: day(time.February, 28)},
+	}, nil)
+	mockRepo.On("FetchTransfers", ctx, fetchTransfersParams{OrganizationID: 1}).Return([]TransferModel{
+		{TransferID: 9, DebitTransactionID: 40, DebitAccountID: 7, CreditTransactionID: 4, CreditAccountID: 3,
+			CreditDate: day(time.February, 5), CreditAmount: decimal.NewFromInt(250)},
+	}, nil)
+	mockRepo.On("FetchCreditCardInvoices", ctx, mock.Anything).Return([]CreditCardInvoiceModel{}, nil)
+
+	invoices, err := svc.GetCreditCardInvoices(ctx, GetCreditCardInvoicesInput{AccountID: 3, OrganizationID: 1})
+
+	require.NoError(t, err)
+	require.Len(t, invoices, 4) // January through April; May's cycle starts after today
+
+	// Due January 5: purchases from Nov 26 through Dec 25
+	assert.Equal(t, "0", invoices[0].Total.String())
+	assert.Equal(t, InvoiceStatusPaid, invoices[0].Status)
+
+	// Due February 5: Jan 10 purchase minus Jan 20 refund, paid on Feb 5
+	assert.Equal(t, "250", invoices[1].Total.String())
+	assert.Equal(t, "250", invoices[1].PaidAmount.String())
+	assert.Equal(t, []int{40}, invoices[1].PaymentTransactionIDs)
+	assert.Equal(t, InvoiceStatusPaid, invoices[1].Status)
+
+	// Due March 5: the Feb 5 payment is not a purchase
+	assert.Equal(t, "400", invoices[2].Total.String())
+	assert.Equal(t, InvoiceStatusClosed, invoices[2].Status)
+
+	assert.Equal(t, "80", invoices[3].Total.String())
+	assert.Equal(t, InvoiceStatusOpen, invoices[3].Status)
+	assert.Nil(t, invoices[3].Transactions)
+}
+
+func TestValidateCardBillingCycle(t *testing.T) {
+	day := func(d int) *int { return &d }
+
+	assert.NoError(t, validateCardBillingCycle(AccountTypeCreditCard, day(25), day(5), true))
+	assert.NoError(t, validateCardBillingCycle(AccountTypeChecking, nil, nil, false))
+	assert.ErrorIs(t, validateCardBillingCycle(AccountTypeCreditCard, day(25), nil, false), internalerrors.ErrInvalidBillingCycle)
+	assert.ErrorIs(t, validateCardBillingCycle(AccountTypeChecking, day(25), day(5), false), internalerrors.ErrInvalidBillingCycle)
+	assert.ErrorIs(t, validateCardBillingCycle(AccountTypeCreditCard, day(0), day(5), false), internalerrors.ErrInvalidBillingCycle)
+	assert.ErrorIs(t, validateCardBillingCycle(AccountTypeCreditCard, nil, nil, true), internalerrors.ErrInvalidBillingCycle)
+}
+
+func TestUpdateAccount_RefusesBillingCycleMovingClosedMonths(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+	closingDay, dueDay := 25, 5
+	budgetByInvoice := true
+
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{
+		AccountID:      3,
+		OrganizationID: 1,
+		AccountType:    AccountTypeCreditCard,
+		CardClosingDay: &closingDay,
+		CardDueDay:     &dueDay,
+	}, nil)
+	mockRepo.On("CountBillingCycleChangesInClosedMonths", ctx, countBillingCycleChangesInClosedMonthsParams{
+		AccountID:       3,
+		OrganizationID:  1,
+		BudgetByInvoice: true,
+		CardClosingDay:  &closingDay,
+		CardDueDay:      &dueDay,
+	}).Return(2, nil)
+
+	_, err := svc.UpdateAccount(ctx, UpdateAccountInput{AccountID: 3, UserID: 10, OrganizationID: 1, BudgetByInvoice: &budgetByInvoice})
+
+	assert.ErrorIs(t, err, internalerrors.ErrMonthClosed)
+	mockRepo.AssertNotCalled(t, "ModifyAccount", mock.Anything, mock.Anything)
+}
+
+func TestGetCreditCardInvoices_LinkedPaymentOnCardIsNotARefund(t *testing.T) {
+	mockRepo := new(MockRepository)
+	stub := system.NewStubSystem()
+	stub.Time.SetTimes(time.Date(2026, time.February, 20, 12, 0, 0, 0, time.UTC))
+	svc := &service{Repository: mockRepo, system: stub.ToSystem()}
+	ctx := context.Background()
+	closingDay, dueDay := 25, 5
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	paymentID := 4
+	paymentAmount := decimal.NewFromInt(300)
+
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{
+		AccountID:      3,
+		OrganizationID: 1,
+		AccountType:    AccountTypeCreditCard,
+		CardClosingDay: &closingDay,
+		CardDueDay:     &dueDay,
+	}, nil)
+	mockRepo.On("FetchAccountTransactionsBetween", ctx, mock.Anything).Return([]TransactionModel{
+		{TransactionID: 1, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(300), TransactionDate: day(time.January, 10)},
+		{TransactionID: 3, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(120), TransactionDate: day(time.February, 1)},
+		// Payment of the February invoice, recorded as a plain credit on the card
+		{TransactionID: 4, TransactionType: TransactionTypeCredit, Amount: decimal.NewFromInt(300), TransactionDate: day(time.February, 5)},
+		{TransactionID: 5, TransactionType: TransactionTypeCredit, Amount: decimal.NewFromInt(20), TransactionDate: day(time.February, 8)},
+	}, nil)
+	mockRepo.On("FetchTransfers", ctx, fetchTransfersParams{OrganizationID: 1}).Return([]TransferModel{}, nil)
+	mockRepo.On("FetchCreditCardInvoices", ctx, mock.Anything).Return([]CreditCardInvoiceModel{
+		{AccountID: 3, Month: 2, Year: 2026, PaymentTransactionID: &paymentID, PaymentAmount: &paymentAmount},
+	}, nil)
+
+	invoices, err := svc.GetCreditCardInvoices(ctx, GetCreditCardInvoicesInput{AccountID: 3, OrganizationID: 1})
+
+	require.NoError(t, err)
+	require.Len(t, invoices, 3)
+
+	// Due February 5: the Jan 10 purchase, paid by the linked credit
+	assert.Equal(t, "300", invoices[1].Total.String())
+	assert.Equal(t, []int{4}, invoices[1].PaymentTransactionIDs)
+	assert.Equal(t, InvoiceStatusPaid, invoices[1].Status)
+
+	// Due March 5: only the Feb 8 refund comes off, not the payment
+	assert.Equal(t, "100", invoices[2].Total.String())
+}
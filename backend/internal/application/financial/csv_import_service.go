@@ -0,0 +1,424 @@
+package financial
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetCSVImportProfilesInput struct {
+	UserID         int
+	OrganizationID int
+	AccountID      int
+}
+
+type CreateCSVImportProfileInput struct {
+	UserID             int
+	OrganizationID     int
+	AccountID          int
+	Name               string
+	Delimiter          string // Defaults to ","
+	SkipRows           int
+	DateColumn         string
+	DateFormat         string // Defaults to DD/MM/YYYY
+	DescriptionColumns []string
+	IDColumn           *string
+	AmountColumn       *string
+	DebitColumn        *string
+	CreditColumn       *string
+	DecimalSeparator   string // Defaults to ","
+	SignConvention     string // Defaults to negative_debit
+}
+
+// UpdateCSVImportProfileInput updates the fields that are set. An empty string
+// clears the optional column fields (IDColumn, AmountColumn, DebitColumn,
+// CreditColumn).
+type UpdateCSVImportProfileInput struct {
+	CSVImportProfileID int
+	OrganizationID     int
+	AccountID          int
+	Name               *string
+	Delimiter          *string
+	SkipRows           *int
+	DateColumn         *string
+	DateFormat         *string
+	DescriptionColumns []string
+	IDColumn           *string
+	AmountColumn       *string
+	DebitColumn        *string
+	CreditColumn       *string
+	DecimalSeparator   *string
+	SignConvention     *string
+}
+
+type DeleteCSVImportProfileInput struct {
+	CSVImportProfileID int
+	OrganizationID     int
+	AccountID          int
+}
+
+type ImportCSVInput struct {
+	AccountID          int
+	UserID             int
+	OrganizationID     int
+	CSVImportProfileID int
+	CSVData            []byte
+}
+
+type PreviewCSVImportOutput struct {
+	Rows       []CSVRow `json:"rows"`
+	ValidCount int      `json:"valid_count"`
+	ErrorCount int      `json:"error_count"`
+}
+
+type ImportCSVOutput struct {
+	ImportedCount     int           `json:"imported_count"`
+	DuplicateCount    int           `json:"duplicate_count"`
+	ErrorCount        int           `json:"error_count"` // Rows skipped because they could not be mapped
+	PatternMatchCount int           `json:"pattern_match_count"`
+	RuleMatchCount    int           `json:"rule_match_count"`
+	Transactions      []Transaction `json:"transactions"`
+}
+
+// ============================================================================
+// CSV Import Profiles
+// ============================================================================
+
+func (s *service) GetCSVImportProfiles(ctx context.Context, input GetCSVImportProfilesInput) ([]CSVImportProfile, error) {
+	if _, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+		AccountID:      input.AccountID,
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+	}); err != nil {
+		return nil, errors.Wrap(err, "account not found or access denied")
+	}
+
+	profiles, err := s.Repository.FetchCSVImportProfiles(ctx, fetchCSVImportProfilesParams{
+		OrganizationID: input.OrganizationID,
+		AccountID:      input.AccountID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch csv import profiles")
+	}
+
+	return CSVImportProfiles{}.FromModel(profiles), nil
+}
+
+func (s *service) CreateCSVImportProfile(ctx context.Context, input CreateCSVImportProfileInput) (CSVImportProfile, error) {
+	if _, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+		AccountID:      input.AccountID,
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+	}); err != nil {
+		return CSVImportProfile{}, errors.Wrap(err, "account not found or access denied")
+	}
+
+	profile := CSVImportProfileModel{
+		Name:               strings.TrimSpace(input.Name),
+		Delimiter:          input.Delimiter,
+		SkipRows:           input.SkipRows,
+		DateColumn:         input.DateColumn,
+		DateFormat:         input.DateFormat,
+		DescriptionColumns: input.DescriptionColumns,
+		IDColumn:           input.IDColumn,
+		AmountColumn:       input.AmountColumn,
+		DebitColumn:        input.DebitColumn,
+		CreditColumn:       input.CreditColumn,
+		DecimalSeparator:   input.DecimalSeparator,
+		SignConvention:     input.SignConvention,
+	}
+	if profile.Delimiter == "" {
+		profile.Delimiter = ","
+	}
+	if profile.DateFormat == "" {
+		profile.DateFormat = "DD/MM/YYYY"
+	}
+	if profile.DecimalSeparator == "" {
+		profile.DecimalSeparator = ","
+	}
+	if profile.SignConvention == "" {
+		profile.SignConvention = CSVSignNegativeDebit
+	}
+	normalizeCSVImportProfile(&profile)
+
+	if err := validateCSVImportProfile(&profile); err != nil {
+		return CSVImportProfile{}, err
+	}
+
+	created, err := s.Repository.InsertCSVImportProfile(ctx, insertCSVImportProfileParams{
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+		AccountID:      input.AccountID,
+		Profile:        profile,
+	})
+	if err != nil {
+		if isCSVImportProfileNameConflict(err) {
+			return CSVImportProfile{}, internalerrors.ErrCSVImportProfileNameExists
+		}
+		return CSVImportProfile{}, errors.Wrap(err, "failed to create csv import profile")
+	}
+
+	return CSVImportProfile{}.FromModel(&created), nil
+}
+
+func (s *service) UpdateCSVImportProfile(ctx context.Context, input UpdateCSVImportProfileInput) (CSVImportProfile, error) {
+	profile, err := s.Repository.FetchCSVImportProfileByID(ctx, fetchCSVImportProfileByIDParams{
+		CSVImportProfileID: input.CSVImportProfileID,
+		OrganizationID:     input.OrganizationID,
+		AccountID:          input.AccountID,
+	})
+	if err != nil {
+		return CSVImportProfile{}, errors.Wrap(err, "failed to fetch csv import profile")
+	}
+
+	if input.Name != nil {
+		profile.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Delimiter != nil {
+		profile.Delimiter = *input.Delimiter
+	}
+	if input.SkipRows != nil {
+		profile.SkipRows = *input.SkipRows
+	}
+	if input.DateColumn != nil {
+		profile.DateColumn = *input.DateColumn
+	}
+	if input.DateFormat != nil {
+		profile.DateFormat = *input.DateFormat
+	}
+	if input.DescriptionColumns != nil {
+		profile.DescriptionColumns = input.DescriptionColumns
+	}
+	if input.IDColumn != nil {
+		profile.IDColumn = input.IDColumn
+	}
+	if input.AmountColumn != nil {
+		profile.AmountColumn = input.AmountColumn
+	}
+	if input.DebitColumn != nil {
+		profile.DebitColumn = input.DebitColumn
+	}
+	if input.CreditColumn != nil {
+		profile.CreditColumn = input.CreditColumn
+	}
+	if input.DecimalSeparator != nil {
+		profile.DecimalSeparator = *input.DecimalSeparator
+	}
+	if input.SignConvention != nil {
+		profile.SignConvention = *input.SignConvention
+	}
+	normalizeCSVImportProfile(&profile)
+
+	if err := validateCSVImportProfile(&profile); err != nil {
+		return CSVImportProfile{}, err
+	}
+
+	updated, err := s.Repository.ModifyCSVImportProfile(ctx, modifyCSVImportProfileParams{
+		CSVImportProfileID: input.CSVImportProfileID,
+		OrganizationID:     input.OrganizationID,
+		AccountID:          input.AccountID,
+		Profile:            profile,
+	})
+	if err != nil {
+		if isCSVImportProfileNameConflict(err) {
+			return CSVImportProfile{}, internalerrors.ErrCSVImportProfileNameExists
+		}
+		return CSVImportProfile{}, errors.Wrap(err, "failed to update csv import profile")
+	}
+
+	return CSVImportProfile{}.FromModel(&updated), nil
+}
+
+func (s *service) DeleteCSVImportProfile(ctx context.Context, input DeleteCSVImportProfileInput) error {
+	err := s.Repository.RemoveCSVImportProfile(ctx, removeCSVImportProfileParams{
+		CSVImportProfileID: input.CSVImportProfileID,
+		OrganizationID:     input.OrganizationID,
+		AccountID:          input.AccountID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete csv import profile")
+	}
+
+	return nil
+}
+
+// normalizeCSVImportProfile trims column names and turns empty optional
+// columns into NULLs.
+func normalizeCSVImportProfile(profile *CSVImportProfileModel) {
+	profile.DateColumn = strings.TrimSpace(profile.DateColumn)
+
+	columns := make([]string, 0, len(profile.DescriptionColumns))
+	for _, column := range profile.DescriptionColumns {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	profile.DescriptionColumns = columns
+
+	for _, column := range []**string{&profile.IDColumn, &profile.AmountColumn, &profile.DebitColumn, &profile.CreditColumn} {
+		if *column == nil {
+			continue
+		}
+		if trimmed := strings.TrimSpace(**column); trimmed != "" {
+			*column = &trimmed
+		} else {
+			*column = nil
+		}
+	}
+}
+
+func validateCSVImportProfile(profile *CSVImportProfileModel) error {
+	if profile.Name == "" || profile.DateColumn == "" || len(profile.DescriptionColumns) == 0 {
+		return internalerrors.ErrMissingRequiredFields
+	}
+	switch profile.Delimiter {
+	case ",", ";", "\t", "|":
+	default:
+		return errors.Wrap(internalerrors.ErrInvalidCSVImportProfile, "delimiter must be one of , ; | or tab")
+	}
+	if profile.SkipRows < 0 {
+		return errors.Wrap(internalerrors.ErrInvalidCSVImportProfile, "skip_rows cannot be negative")
+	}
+	if _, err := csvDateLayout(profile.DateFormat); err != nil {
+		return errors.Wrap(internalerrors.ErrInvalidCSVImportProfile, "%s", err.Error())
+	}
+	if profile.AmountColumn == nil && (profile.DebitColumn == nil || profile.CreditColumn == nil) {
+		return errors.Wrap(internalerrors.ErrInvalidCSVImportProfile, "amount_column or both debit_column and credit_column are required")
+	}
+	if profile.DecimalSeparator != "," && profile.DecimalSeparator != "." {
+		return errors.Wrap(internalerrors.ErrInvalidCSVImportProfile, "decimal_separator must be , or .")
+	}
+	if profile.SignConvention != CSVSignNegativeDebit && profile.SignConvention != CSVSignPositiveDebit {
+		return errors.Wrap(internalerrors.ErrInvalidCSVImportProfile, "sign_convention must be %s or %s", CSVSignNegativeDebit, CSVSignPositiveDebit)
+	}
+	return nil
+}
+
+func isCSVImportProfileNameConflict(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "csv_import_profiles_account_id_name_key")
+}
+
+// ============================================================================
+// CSV Import
+// ============================================================================
+
+// PreviewCSVImport parses a CSV statement with a saved profile without
+// importing it, so the mapping can be checked row by row.
+func (s *service) PreviewCSVImport(ctx context.Context, input ImportCSVInput) (PreviewCSVImportOutput, error) {
+	rows, err := s.parseCSVImport(ctx, input)
+	if err != nil {
+		return PreviewCSVImportOutput{}, err
+	}
+
+	output := PreviewCSVImportOutput{Rows: rows}
+	for _, row := range rows {
+		if row.Error != "" {
+			output.ErrorCount++
+		} else {
+			output.ValidCount++
+		}
+	}
+	if output.Rows == nil {
+		output.Rows = []CSVRow{}
+	}
+
+	return output, nil
+}
+
+// ImportTransactionsFromCSV imports a CSV statement with a saved profile. Rows
+// go through the same FITID dedupe, closed-month check and classification as
+// OFX imports; rows that cannot be mapped are skipped and counted.
+func (s *service) ImportTransactionsFromCSV(ctx context.Context, input ImportCSVInput) (ImportCSVOutput, error) {
+	rows, err := s.parseCSVImport(ctx, input)
+	if err != nil {
+		return ImportCSVOutput{}, err
+	}
+
+	var output ImportCSVOutput
+	insertParams := make([]insertTransactionParams, 0, len(rows))
+	checkedDays := make(map[time.Time]struct{})
+	for _, row := range rows {
+		if row.Error != "" {
+			output.ErrorCount++
+			s.logger.Warn(ctx, "Skipping unmappable CSV row",
+				"account_id", input.AccountID,
+				"line", row.Line,
+				"error", row.Error,
+			)
+			continue
+		}
+
+		day := truncateToDay(row.Date)
+		if _, checked := checkedDays[day]; !checked {
+			if err := s.ensureTransactionMonthOpen(ctx, transactionMonthClosureParams{
+				OrganizationID:  input.OrganizationID,
+				AccountID:       input.AccountID,
+				TransactionDate: day,
+			}); err != nil {
+				return ImportCSVOutput{}, err
+			}
+			checkedDays[day] = struct{}{}
+		}
+		insertParams = append(insertParams, csvRowToInsertParams(input.AccountID, row))
+	}
+
+	inserted, err := s.Repository.BulkInsertTransactions(ctx, bulkInsertTransactionsParams{
+		Transactions: insertParams,
+	})
+	if err != nil {
+		return ImportCSVOutput{}, errors.Wrap(err, "failed to insert transactions")
+	}
+	output.ImportedCount = len(inserted)
+	output.DuplicateCount = len(insertParams) - len(inserted)
+
+	classified := s.classifyImportedTransactions(ctx, inserted, input.UserID, input.OrganizationID)
+	output.PatternMatchCount = classified.PatternMatchCount
+	output.RuleMatchCount = classified.RuleMatchCount
+	output.Transactions = Transactions{}.FromModel(inserted)
+
+	s.logger.Info(ctx, "CSV import completed",
+		"account_id", input.AccountID,
+		"csv_import_profile_id", input.CSVImportProfileID,
+		"total_parsed", len(rows),
+		"imported", output.ImportedCount,
+		"duplicates", output.DuplicateCount,
+		"errors", output.ErrorCount,
+		"pattern_matched", output.PatternMatchCount,
+		"rule_matched", output.RuleMatchCount,
+	)
+
+	return output, nil
+}
+
+func (s *service) parseCSVImport(ctx context.Context, input ImportCSVInput) ([]CSVRow, error) {
+	if _, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+		AccountID:      input.AccountID,
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+	}); err != nil {
+		return nil, errors.Wrap(err, "account not found or access denied")
+	}
+
+	profile, err := s.Repository.FetchCSVImportProfileByID(ctx, fetchCSVImportProfileByIDParams{
+		CSVImportProfileID: input.CSVImportProfileID,
+		OrganizationID:     input.OrganizationID,
+		AccountID:          input.AccountID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch csv import profile")
+	}
+
+	rows, err := parseCSVStatement(input.CSVData, &profile)
+	if err != nil {
+		return nil, errors.Wrap(internalerrors.ErrInvalidCSVFile, "%s", err.Error())
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,121 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportTransactionsFromCSV_InsertsMappedRowsAndCountsErrors(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	svc := &service{
+		Repository: mockRepo,
+		system:     system.NewStubSystem().ToSystem(),
+		logger:     &logging.TestLogger{},
+	}
+
+	amountColumn := "amount"
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{AccountID: 3}, nil)
+	mockRepo.On("FetchCSVImportProfileByID", ctx, fetchCSVImportProfileByIDParams{
+		CSVImportProfileID: 8,
+		OrganizationID:     1,
+		AccountID:          3,
+	}).Return(CSVImportProfileModel{
+		CSVImportProfileID: 8,
+		Delimiter:          ",",
+		DateColumn:         "date",
+		DateFormat:         "YYYY-MM-DD",
+		DescriptionColumns: []string{"title"},
+		AmountColumn:       &amountColumn,
+		DecimalSeparator:   ".",
+		SignConvention:     CSVSignPositiveDebit,
+	}, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.MatchedBy(func(params transactionMonthClosureParams) bool {
+		return params.OrganizationID == 1 && params.AccountID == 3 && params.TransactionDate.Month() == time.October
+	})).Return(false, nil)
+
+	var inserted bulkInsertTransactionsParams
+	mockRepo.On("BulkInsertTransactions", ctx, mock.Anything).
+		Run(func(args mock.Arguments) { inserted = args.Get(1).(bulkInsertTransactionsParams) }).
+		Return([]TransactionModel{{TransactionID: 1, AccountID: 3}}, nil)
+	mockRepo.On("FetchClassificationRules", ctx, mock.Anything).Return([]ClassificationRuleModel{}, nil)
+	mockRepo.On("FetchTransactionByID", ctx, mock.Anything).Return(TransactionModel{TransactionID: 1}, nil)
+	mockRepo.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{}, nil)
+
+	output, err := svc.ImportTransactionsFromCSV(ctx, ImportCSVInput{
+		AccountID:          3,
+		UserID:             2,
+		OrganizationID:     1,
+		CSVImportProfileID: 8,
+		CSVData:            []byte("date,title,amount\n2025-10-05,Uber,23.90\n2025-10-06,Netflix,55.90\nontem,Spotify,21.90\n"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, output.ImportedCount)
+	assert.Equal(t, 1, output.DuplicateCount)
+	assert.Equal(t, 1, output.ErrorCount)
+
+	require.Len(t, inserted.Transactions, 2)
+	assert.Equal(t, 3, inserted.Transactions[0].AccountID)
+	assert.Equal(t, "Uber", inserted.Transactions[0].Description)
+	assert.Equal(t, TransactionTypeDebit, inserted.Transactions[0].TransactionType)
+	assert.Equal(t, "2025-10-05T00:00:00Z", inserted.Transactions[0].TransactionDate)
+	require.NotNil(t, inserted.Transactions[0].OFXFitID)
+	assert.Regexp(t, `^CSV-`, *inserted.Transactions[0].OFXFitID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateCSVImportProfile_ValidatesMapping(t *testing.T) {
+	ctx := context.Background()
+	amount := "Valor"
+
+	tests := []struct {
+		name  string
+		input CreateCSVImportProfileInput
+		want  error
+	}{
+		{
+			name:  "description columns required",
+			input: CreateCSVImportProfileInput{Name: "Itaú", DateColumn: "Data", AmountColumn: &amount},
+			want:  internalerrors.ErrMissingRequiredFields,
+		},
+		{
+			name:  "amount or debit and credit columns required",
+			input: CreateCSVImportProfileInput{Name: "Itaú", DateColumn: "Data", DescriptionColumns: []string{"Histórico"}},
+			want:  internalerrors.ErrInvalidCSVImportProfile,
+		},
+		{
+			name: "unsupported date format",
+			input: CreateCSVImportProfileInput{Name: "Itaú", DateColumn: "Data", DescriptionColumns: []string{"Histórico"},
+				AmountColumn: &amount, DateFormat: "%d/%m/%Y"},
+			want: internalerrors.ErrInvalidCSVImportProfile,
+		},
+		{
+			name: "unsupported delimiter",
+			input: CreateCSVImportProfileInput{Name: "Itaú", DateColumn: "Data", DescriptionColumns: []string{"Histórico"},
+				AmountColumn: &amount, Delimiter: ":"},
+			want: internalerrors.ErrInvalidCSVImportProfile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			svc := &service{Repository: mockRepo, logger: &logging.TestLogger{}}
+			mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{AccountID: 3}, nil)
+
+			_, err := svc.CreateCSVImportProfile(ctx, tt.input)
+			assert.True(t, errors.Is(err, tt.want), "got %v", err)
+			mockRepo.AssertNotCalled(t, "InsertCSVImportProfile", mock.Anything, mock.Anything)
+		})
+	}
+}
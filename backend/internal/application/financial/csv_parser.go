@@ -0,0 +1,341 @@
+package financial
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// CSVSignNegativeDebit: negative amounts are debits (bank account exports)
+	CSVSignNegativeDebit = "negative_debit"
+	// CSVSignPositiveDebit: positive amounts are debits (credit card exports,
+	// where purchases are positive and payments negative)
+	CSVSignPositiveDebit = "positive_debit"
+
+	csvFitIDPrefix = "CSV-"
+)
+
+// CSVRow is one data row of a CSV statement after applying an import profile.
+// Rows that cannot be mapped keep their line number and carry Error instead.
+type CSVRow struct {
+	Line            int             `json:"line"`
+	Date            time.Time       `json:"date"`
+	Description     string          `json:"description"`
+	Amount          decimal.Decimal `json:"amount"` // Absolute value
+	TransactionType string          `json:"transaction_type"`
+	FITID           string          `json:"fitid"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// csvColumns holds the header positions of a profile's columns; -1 when unset.
+type csvColumns struct {
+	date        int
+	description []int
+	id          int
+	amount      int
+	debit       int
+	credit      int
+}
+
+// parseCSVStatement reads a CSV statement with the given profile. Structural
+// problems (unreadable file, missing header columns) fail the whole file;
+// problems with a single row are reported on that row.
+func parseCSVStatement(data []byte, profile *CSVImportProfileModel) ([]CSVRow, error) {
+	layout, err := csvDateLayout(profile.DateFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimPrefix(decodeStatementText(data, ""), "\ufeff")
+	for i := 0; i < profile.SkipRows; i++ {
+		newline := strings.IndexByte(text, '\n')
+		if newline < 0 {
+			text = ""
+			break
+		}
+		text = text[newline+1:]
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = []rune(profile.Delimiter)[0]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("csv file has no header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns, err := resolveCSVColumns(header, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []CSVRow
+	occurrences := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("failed to read csv: %w", err)
+			}
+			rows = append(rows, CSVRow{Line: parseErr.Line + profile.SkipRows, Error: parseErr.Err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		line += profile.SkipRows
+		if isBlankCSVRecord(record) {
+			continue
+		}
+
+		row := CSVRow{Line: line}
+		if err := mapCSVRecord(&row, record, columns, layout, profile); err != nil {
+			row.Error = err.Error()
+			rows = append(rows, row)
+			continue
+		}
+
+		if columns.id >= 0 && csvCell(record, columns.id) != "" {
+			row.FITID = csvFitIDPrefix + csvCell(record, columns.id)
+		} else {
+			row.FITID = syntheticCSVFitID(&row, occurrences)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func resolveCSVColumns(header []string, profile *CSVImportProfileModel) (csvColumns, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, exists := positions[key]; !exists {
+			positions[key] = i
+		}
+	}
+
+	lookup := func(name string) (int, error) {
+		position, ok := positions[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("column %q not found in csv header", name)
+		}
+		return position, nil
+	}
+	optional := func(name *string) (int, error) {
+		if name == nil || *name == "" {
+			return -1, nil
+		}
+		return lookup(*name)
+	}
+
+	columns := csvColumns{}
+	var err error
+	if columns.date, err = lookup(profile.DateColumn); err != nil {
+		return columns, err
+	}
+	for _, name := range profile.DescriptionColumns {
+		position, err := lookup(name)
+		if err != nil {
+			return columns, err
+		}
+		columns.description = append(columns.description, position)
+	}
+	if columns.id, err = optional(profile.IDColumn); err != nil {
+		return columns, err
+	}
+	if columns.amount, err = optional(profile.AmountColumn); err != nil {
+		return columns, err
+	}
+	if columns.debit, err = optional(profile.DebitColumn); err != nil {
+		return columns, err
+	}
+	if columns.credit, err = optional(profile.CreditColumn); err != nil {
+		return columns, err
+	}
+
+	return columns, nil
+}
+
+func mapCSVRecord(row *CSVRow, record []string, columns csvColumns, layout string, profile *CSVImportProfileModel) error {
+	dateValue := csvCell(record, columns.date)
+	if fields := strings.Fields(dateValue); len(fields) > 1 {
+		dateValue = fields[0] // Drop a trailing time of day
+	}
+	date, err := time.Parse(layout, dateValue)
+	if err != nil {
+		return fmt.Errorf("invalid date %q", csvCell(record, columns.date))
+	}
+	row.Date = date
+
+	parts := make([]string, 0, len(columns.description))
+	for _, position := range columns.description {
+		if value := csvCell(record, position); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	row.Description = cleanDescription(strings.Join(parts, " "))
+	if row.Description == "" {
+		return fmt.Errorf("missing description")
+	}
+
+	if columns.amount >= 0 {
+		amount, err := parseCSVAmount(csvCell(record, columns.amount), profile.DecimalSeparator)
+		if err != nil {
+			return err
+		}
+		if amount.IsZero() {
+			return fmt.Errorf("zero amount")
+		}
+		isDebit := amount.IsNegative()
+		if profile.SignConvention == CSVSignPositiveDebit {
+			isDebit = amount.IsPositive()
+		}
+		row.TransactionType = TransactionTypeCredit
+		if isDebit {
+			row.TransactionType = TransactionTypeDebit
+		}
+		row.Amount = amount.Abs()
+		return nil
+	}
+
+	for _, side := range []struct {
+		position        int
+		transactionType string
+	}{
+		{columns.debit, TransactionTypeDebit},
+		{columns.credit, TransactionTypeCredit},
+	} {
+		value := csvCell(record, side.position)
+		if value == "" {
+			continue
+		}
+		amount, err := parseCSVAmount(value, profile.DecimalSeparator)
+		if err != nil {
+			return err
+		}
+		if amount.IsZero() {
+			continue
+		}
+		row.TransactionType = side.transactionType
+		row.Amount = amount.Abs()
+		return nil
+	}
+
+	return fmt.Errorf("missing amount")
+}
+
+// syntheticCSVFitID derives a FITID from the row content so re-importing the
+// same file (or an overlapping export) hits the ofx_fitid dedupe. Identical
+// rows within a file, like two equal purchases on the same day, are told
+// apart by their occurrence number.
+func syntheticCSVFitID(row *CSVRow, occurrences map[string]int) string {
+	key := strings.Join([]string{
+		row.Date.Format("2006-01-02"),
+		row.TransactionType,
+		row.Amount.StringFixed(2),
+		strings.ToUpper(row.Description),
+	}, "|")
+	occurrences[key]++
+
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%s%s-%d", csvFitIDPrefix, hex.EncodeToString(sum[:12]), occurrences[key])
+}
+
+// parseCSVAmount parses amounts such as "-1.234,56", "R$ 12,50", "(12.50)"
+// or "12,50-". The separator that is not the decimal one is treated as a
+// thousands separator.
+func parseCSVAmount(value, decimalSeparator string) (decimal.Decimal, error) {
+	original := value
+	value = strings.NewReplacer("R$", "", " ", "", "\u00a0", "").Replace(value)
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+	if strings.HasSuffix(value, "-") {
+		negative = !negative
+		value = strings.TrimSuffix(value, "-")
+	}
+
+	thousandsSeparator := "."
+	if decimalSeparator == "." {
+		thousandsSeparator = ","
+	}
+	value = strings.ReplaceAll(value, thousandsSeparator, "")
+	value = strings.Replace(value, decimalSeparator, ".", 1)
+
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount %q", original)
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+// csvDateLayout converts a profile date format (DD, MM, YY/YYYY and the
+// separators / - . or space) into a Go time layout.
+func csvDateLayout(format string) (string, error) {
+	layout := strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02").
+		Replace(strings.ToUpper(strings.TrimSpace(format)))
+
+	for _, r := range layout {
+		if !strings.ContainsRune("0123456789/-. ", r) {
+			return "", fmt.Errorf("invalid date format %q", format)
+		}
+	}
+	if !strings.Contains(layout, "01") || !strings.Contains(layout, "02") || !strings.Contains(layout, "06") {
+		return "", fmt.Errorf("invalid date format %q", format)
+	}
+	return layout, nil
+}
+
+func csvCell(record []string, position int) string {
+	if position < 0 || position >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[position])
+}
+
+func isBlankCSVRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// csvRowToInsertParams maps a parsed CSV row onto the same insert params an
+// OFX import produces.
+func csvRowToInsertParams(accountID int, row CSVRow) insertTransactionParams {
+	fitID := row.FITID
+	return insertTransactionParams{
+		AccountID:           accountID,
+		Description:         row.Description,
+		OriginalDescription: row.Description,
+		Amount:              row.Amount,
+		TransactionDate:     row.Date.Format(time.RFC3339),
+		TransactionType:     row.TransactionType,
+		OFXFitID:            &fitID,
+	}
+}
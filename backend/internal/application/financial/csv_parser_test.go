@@ -0,0 +1,180 @@
+package financial
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func csvTestString(s string) *string { return &s }
+
+func TestParseCSVStatement_BankExportWithCommaDecimals(t *testing.T) {
+	profile := &CSVImportProfileModel{
+		Delimiter:          ";",
+		SkipRows:           2,
+		DateColumn:         "Data",
+		DateFormat:         "DD/MM/YYYY",
+		DescriptionColumns: []string{"Lançamento", "Detalhe"},
+		AmountColumn:       csvTestString("Valor (R$)"),
+		DecimalSeparator:   ",",
+		SignConvention:     CSVSignNegativeDebit,
+	}
+	data := "Extrato conta corrente\r\nAgência 1234 Conta 56789-0\r\n" +
+		"Data;Lançamento;Detalhe;Valor (R$)\r\n" +
+		"05/10/2025;PIX ENVIADO;Maria;-1.234,56\r\n" +
+		"06/10/2025;SALARIO;;5.000,00\r\n" +
+		"\r\n" +
+		"07/10/2025;TARIFA;;abc\r\n" +
+		"31/02/2025;ESTORNO;;10,00\r\n"
+
+	rows, err := parseCSVStatement([]byte(data), profile)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, 4, rows[0].Line)
+	assert.Equal(t, "2025-10-05", rows[0].Date.Format("2006-01-02"))
+	assert.Equal(t, "PIX ENVIADO Maria", rows[0].Description)
+	assert.True(t, rows[0].Amount.Equal(decimal.RequireFromString("1234.56")))
+	assert.Equal(t, TransactionTypeDebit, rows[0].TransactionType)
+	assert.Empty(t, rows[0].Error)
+
+	assert.Equal(t, TransactionTypeCredit, rows[1].TransactionType)
+	assert.True(t, rows[1].Amount.Equal(decimal.NewFromInt(5000)))
+
+	assert.Equal(t, 7, rows[2].Line)
+	assert.Contains(t, rows[2].Error, "invalid amount")
+	assert.Contains(t, rows[3].Error, "invalid date")
+}
+
+func TestParseCSVStatement_CreditCardPositiveDebits(t *testing.T) {
+	profile := &CSVImportProfileModel{
+		Delimiter:          ",",
+		DateColumn:         "date",
+		DateFormat:         "YYYY-MM-DD",
+		DescriptionColumns: []string{"title"},
+		AmountColumn:       csvTestString("amount"),
+		DecimalSeparator:   ".",
+		SignConvention:     CSVSignPositiveDebit,
+	}
+	data := "\ufeffdate,title,amount\n" +
+		"2025-10-05,Uber *Trip,23.90\n" +
+		"2025-10-10,Pagamento recebido,-500.00\n"
+
+	rows, err := parseCSVStatement([]byte(data), profile)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, TransactionTypeDebit, rows[0].TransactionType)
+	assert.Equal(t, TransactionTypeCredit, rows[1].TransactionType)
+	assert.True(t, rows[1].Amount.Equal(decimal.NewFromInt(500)))
+}
+
+func TestParseCSVStatement_DebitAndCreditColumnsWithIDColumn(t *testing.T) {
+	profile := &CSVImportProfileModel{
+		Delimiter:          ",",
+		DateColumn:         "Date",
+		DateFormat:         "MM/DD/YY",
+		DescriptionColumns: []string{"Memo"},
+		IDColumn:           csvTestString("Ref"),
+		DebitColumn:        csvTestString("Debit"),
+		CreditColumn:       csvTestString("Credit"),
+		DecimalSeparator:   ".",
+		SignConvention:     CSVSignNegativeDebit,
+	}
+	data := "Ref,Date,Memo,Debit,Credit\n" +
+		"A1,10/05/25,Coffee,4.50,\n" +
+		",10/06/25,Refund,,\"1,200.00\"\n" +
+		"A3,10/07/25,Nothing,,\n"
+
+	rows, err := parseCSVStatement([]byte(data), profile)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, "CSV-A1", rows[0].FITID)
+	assert.Equal(t, TransactionTypeDebit, rows[0].TransactionType)
+
+	assert.Equal(t, TransactionTypeCredit, rows[1].TransactionType)
+	assert.True(t, rows[1].Amount.Equal(decimal.NewFromInt(1200)))
+	assert.Regexp(t, `^CSV-[0-9a-f]{24}-1$`, rows[1].FITID)
+
+	assert.Equal(t, "missing amount", rows[2].Error)
+}
+
+func TestParseCSVStatement_SyntheticFITIDsAreDeterministic(t *testing.T) {
+	profile := &CSVImportProfileModel{
+		Delimiter:          ",",
+		DateColumn:         "date",
+		DateFormat:         "DD/MM/YYYY",
+		DescriptionColumns: []string{"description"},
+		AmountColumn:       csvTestString("amount"),
+		DecimalSeparator:   ",",
+		SignConvention:     CSVSignNegativeDebit,
+	}
+	data := []byte("date,description,amount\n" +
+		"05/10/2025,Padaria,\"-8,00\"\n" +
+		"05/10/2025,Padaria,\"-8,00\"\n" +
+		"06/10/2025,Padaria,\"-8,00\"\n")
+
+	first, err := parseCSVStatement(data, profile)
+	require.NoError(t, err)
+	second, err := parseCSVStatement(data, profile)
+	require.NoError(t, err)
+
+	require.Len(t, first, 3)
+	for i := range first {
+		assert.Equal(t, first[i].FITID, second[i].FITID)
+	}
+	// Identical rows on the same day get distinct FITIDs
+	assert.NotEqual(t, first[0].FITID, first[1].FITID)
+	assert.NotEqual(t, first[0].FITID, first[2].FITID)
+}
+
+func TestParseCSVStatement_MissingHeaderColumn(t *testing.T) {
+	profile := &CSVImportProfileModel{
+		Delimiter:          ",",
+		DateColumn:         "date",
+		DateFormat:         "DD/MM/YYYY",
+		DescriptionColumns: []string{"description"},
+		AmountColumn:       csvTestString("valor"),
+		DecimalSeparator:   ",",
+		SignConvention:     CSVSignNegativeDebit,
+	}
+
+	_, err := parseCSVStatement([]byte("date,description,amount\n05/10/2025,x,1\n"), profile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `column "valor" not found`)
+}
+
+func TestParseCSVAmount(t *testing.T) {
+	tests := []struct {
+		value            string
+		decimalSeparator string
+		want             string
+	}{
+		{"-1.234,56", ",", "-1234.56"},
+		{"R$ 12,50", ",", "12.5"},
+		{"12,50-", ",", "-12.5"},
+		{"(12.50)", ".", "-12.5"},
+		{"1,234.56", ".", "1234.56"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseCSVAmount(tt.value, tt.decimalSeparator)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestCSVDateLayout(t *testing.T) {
+	layout, err := csvDateLayout("dd/mm/yyyy")
+	require.NoError(t, err)
+	assert.Equal(t, "02/01/2006", layout)
+
+	for _, format := range []string{"", "DD/MM", "YYYY-MM-DD HH:mm", "%d/%m/%Y"} {
+		_, err := csvDateLayout(format)
+		assert.Error(t, err, format)
+	}
+}
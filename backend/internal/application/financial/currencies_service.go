@@ -0,0 +1,254 @@
+package financial
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetBaseCurrencyInput struct {
+	OrganizationID int
+}
+
+// SetBaseCurrencyInput changes the currency totals are reported in. Rates
+// imported for the previous base currency stay stored but no longer apply.
+type SetBaseCurrencyInput struct {
+	OrganizationID int
+	BaseCurrency   string
+}
+
+type GetExchangeRatesInput struct {
+	OrganizationID int
+	Currency       string // Empty lists every currency
+	From           string // YYYY-MM-DD
+	To             string // YYYY-MM-DD
+}
+
+// ImportExchangeRatesInput carries a CSV of daily rates, one per line as
+// "date,currency,rate" (YYYY-MM-DD, ISO 4217 code, value of one unit in the
+// base currency). A header line and ";" as the delimiter are accepted.
+type ImportExchangeRatesInput struct {
+	OrganizationID int
+	CSVData        []byte
+	Source         string // Defaults to upload
+}
+
+type ImportExchangeRatesOutput struct {
+	Imported   int      `json:"imported"`
+	Currencies []string `json:"currencies"`
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+func (s *service) GetBaseCurrency(ctx context.Context, input GetBaseCurrencyInput) (string, error) {
+	currency, err := s.Repository.FetchBaseCurrency(ctx, fetchBaseCurrencyParams{
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch base currency")
+	}
+	return currency, nil
+}
+
+func (s *service) SetBaseCurrency(ctx context.Context, input SetBaseCurrencyInput) (string, error) {
+	code, err := normalizeCurrency(input.BaseCurrency)
+	if err != nil {
+		return "", err
+	}
+
+	currency, err := s.Repository.ModifyBaseCurrency(ctx, modifyBaseCurrencyParams{
+		OrganizationID: input.OrganizationID,
+		BaseCurrency:   code,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to update base currency")
+	}
+	return currency, nil
+}
+
+func (s *service) GetExchangeRates(ctx context.Context, input GetExchangeRatesInput) ([]ExchangeRate, error) {
+	params := fetchExchangeRatesParams{OrganizationID: input.OrganizationID}
+	if input.Currency != "" {
+		code, err := normalizeCurrency(input.Currency)
+		if err != nil {
+			return nil, err
+		}
+		params.Currency = &code
+	}
+	if input.From != "" {
+		from, err := parseTransactionDate(input.From)
+		if err != nil {
+			return nil, internalerrors.NewInvalidTimeFormatError("from")
+		}
+		params.From = &from
+	}
+	if input.To != "" {
+		to, err := parseTransactionDate(input.To)
+		if err != nil {
+			return nil, internalerrors.NewInvalidTimeFormatError("to")
+		}
+		params.To = &to
+	}
+
+	models, err := s.Repository.FetchExchangeRates(ctx, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch exchange rates")
+	}
+
+	return ExchangeRates{}.FromModel(models), nil
+}
+
+// ImportExchangeRates saves every rate in the file, replacing rates already
+// kept for the same currency and day, or none if any line is invalid.
+func (s *service) ImportExchangeRates(ctx context.Context, input ImportExchangeRatesInput) (ImportExchangeRatesOutput, error) {
+	baseCurrency, err := s.GetBaseCurrency(ctx, GetBaseCurrencyInput{OrganizationID: input.OrganizationID})
+	if err != nil {
+		return ImportExchangeRatesOutput{}, err
+	}
+
+	rates, err := parseExchangeRatesCSV(input.CSVData, baseCurrency)
+	if err != nil {
+		return ImportExchangeRatesOutput{}, err
+	}
+
+	source := input.Source
+	if source == "" {
+		source = ExchangeRateSourceUpload
+	}
+	imported, err := s.Repository.UpsertExchangeRates(ctx, upsertExchangeRatesParams{
+		OrganizationID: input.OrganizationID,
+		Rates:          rates,
+		Source:         source,
+	})
+	if err != nil {
+		return ImportExchangeRatesOutput{}, errors.Wrap(err, "failed to save exchange rates")
+	}
+
+	seen := make(map[string]bool)
+	output := ImportExchangeRatesOutput{Imported: imported, Currencies: []string{}}
+	for _, rate := range rates {
+		if !seen[rate.Currency] {
+			seen[rate.Currency] = true
+			output.Currencies = append(output.Currencies, rate.Currency)
+		}
+	}
+	sort.Strings(output.Currencies)
+
+	s.logger.Info(ctx, "Exchange rates imported",
+		"organization_id", input.OrganizationID,
+		"imported", imported,
+		"source", source,
+	)
+
+	return output, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// convertedAmount returns amount, part or all of tx's amount, in the
+// organization's base currency at tx's rate. It is false for a foreign amount
+// without a known rate, which callers leave out of their totals and report
+// as missing rather than mix currencies.
+func convertedAmount(tx *TransactionModel, amount decimal.Decimal) (decimal.Decimal, bool) {
+	if tx.ConvertedAmount == nil {
+		// Queries select the currency along with the converted amount; rows
+		// without either were not converted at all
+		if tx.Currency != "" {
+			return decimal.Zero, false
+		}
+		return amount, true
+	}
+	if amount.Equal(tx.Amount) {
+		return *tx.ConvertedAmount, true
+	}
+	if tx.Amount.IsZero() {
+		return amount, true
+	}
+	return amount.Mul(*tx.ConvertedAmount).Div(tx.Amount).Round(2), true
+}
+
+// normalizeCurrency upper-cases an ISO 4217 code and rejects anything else.
+func normalizeCurrency(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if !currencyCodePattern.MatchString(normalized) {
+		return "", errors.Wrap(internalerrors.ErrInvalidCurrency, "invalid currency code %q", code)
+	}
+	return normalized, nil
+}
+
+// parseExchangeRatesCSV reads the rates of an import, skipping a header line
+// and blank lines. Quotes of the base currency itself are rejected.
+func parseExchangeRatesCSV(data []byte, baseCurrency string) ([]exchangeRateRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	if firstLine, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rates []exchangeRateRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(internalerrors.ErrInvalidExchangeRates, "%s", err.Error())
+		}
+		if isBlankCSVRecord(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != 3 {
+			return nil, errors.Wrap(internalerrors.ErrInvalidExchangeRates, "line %d: expected date, currency and rate", line)
+		}
+
+		date, err := parseTransactionDate(strings.TrimSpace(record[0]))
+		if err != nil {
+			if line == 1 {
+				continue // Header
+			}
+			return nil, errors.Wrap(internalerrors.ErrInvalidExchangeRates, "line %d: invalid date %q", line, record[0])
+		}
+		currency, err := normalizeCurrency(record[1])
+		if err != nil {
+			return nil, errors.Wrap(internalerrors.ErrInvalidExchangeRates, "line %d: invalid currency %q", line, record[1])
+		}
+		if currency == baseCurrency {
+			return nil, errors.Wrap(internalerrors.ErrInvalidExchangeRates, "line %d: %s is the base currency", line, currency)
+		}
+		value := strings.TrimSpace(record[2])
+		if !strings.Contains(value, ".") {
+			value = strings.Replace(value, ",", ".", 1)
+		}
+		rate, err := decimal.NewFromString(value)
+		if err != nil || !rate.IsPositive() {
+			return nil, errors.Wrap(internalerrors.ErrInvalidExchangeRates, "line %d: invalid rate %q", line, record[2])
+		}
+
+		rates = append(rates, exchangeRateRow{Currency: currency, RateDate: date, Rate: rate})
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.Wrap(internalerrors.ErrInvalidExchangeRates, "no rates found")
+	}
+	return rates, nil
+}
@@ -0,0 +1,122 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExchangeRatesCSV(t *testing.T) {
+	march := func(day int) time.Time { return time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC) }
+
+	rates, err := parseExchangeRatesCSV([]byte("date;currency;rate\n2026-03-02;usd;5,1234\n\n2026-03-03;EUR;6.01\n"), "BRL")
+
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, exchangeRateRow{Currency: "USD", RateDate: march(2), Rate: decimal.RequireFromString("5.1234")}, rates[0])
+	assert.Equal(t, "EUR", rates[1].Currency)
+	assert.Equal(t, march(3), rates[1].RateDate)
+
+	invalid := map[string]string{
+		"base currency":  "2026-03-02,BRL,1\n",
+		"zero rate":      "2026-03-02,USD,0\n",
+		"bad currency":   "2026-03-02,US DOLLAR,5\n",
+		"bad date":       "2026-03-02,USD,5\n02/03/2026,USD,5\n",
+		"missing column": "2026-03-02,USD\n",
+		"only header":    "date,currency,rate\n",
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseExchangeRatesCSV([]byte(data), "BRL")
+			assert.ErrorIs(t, err, internalerrors.ErrInvalidExchangeRates)
+		})
+	}
+}
+
+func TestConvertedAmount(t *testing.T) {
+	converted := decimal.RequireFromString("510.00")
+	foreign := TransactionModel{Amount: decimal.NewFromInt(100), Currency: "USD", ConvertedAmount: &converted}
+	noRate := TransactionModel{Amount: decimal.NewFromInt(100), Currency: "USD"}
+
+	amount, ok := convertedAmount(&foreign, foreign.Amount)
+	assert.True(t, ok)
+	assert.Equal(t, "510", amount.String())
+	amount, _ = convertedAmount(&foreign, decimal.NewFromInt(30)) // A split of the transaction
+	assert.Equal(t, "153", amount.String())
+
+	// Without a rate the foreign amount is not passed off as the base currency
+	amount, ok = convertedAmount(&noRate, noRate.Amount)
+	assert.False(t, ok)
+	assert.True(t, amount.IsZero())
+}
+
+func TestSumControlledSpendingByCategory_ConvertsForeignAmounts(t *testing.T) {
+	groceries, travel := 1, 2
+	local, converted := decimal.NewFromInt(80), decimal.NewFromInt(500)
+
+	spending, missingRates := sumControlledSpendingByCategory([]TransactionModel{
+		{TransactionID: 1, CategoryID: &groceries, TransactionType: TransactionTypeDebit, Amount: local, Currency: "BRL", ConvertedAmount: &local},
+		{TransactionID: 2, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(100), Currency: "USD", ConvertedAmount: &converted},
+		{TransactionID: 3, CategoryID: &travel, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(70), Currency: "EUR"},
+		{TransactionID: 4, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(50), Currency: "EUR"},
+	}, map[int][]TransactionSplitModel{
+		2: {
+			{TransactionID: 2, CategoryID: &travel, Amount: decimal.NewFromInt(60)},
+			{TransactionID: 2, CategoryID: &groceries, Amount: decimal.NewFromInt(40)},
+		},
+		4: {
+			{TransactionID: 4, CategoryID: &travel, Amount: decimal.NewFromInt(25)},
+			{TransactionID: 4, CategoryID: &groceries, Amount: decimal.NewFromInt(25)},
+		},
+	}, map[int]struct{}{})
+
+	assert.Equal(t, "280", spending[groceries].String())
+	assert.Equal(t, "300", spending[travel].String())
+	assert.Equal(t, 2, missingRates) // Transactions 3 and 4, counted once each
+}
+
+func TestImportExchangeRates(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, logger: &logging.TestLogger{}}
+	ctx := context.Background()
+
+	mockRepo.On("FetchBaseCurrency", ctx, fetchBaseCurrencyParams{OrganizationID: 1}).Return("BRL", nil)
+	mockRepo.On("UpsertExchangeRates", ctx, mock.MatchedBy(func(params upsertExchangeRatesParams) bool {
+		return params.OrganizationID == 1 && params.Source == ExchangeRateSourceUpload && len(params.Rates) == 3
+	})).Return(3, nil)
+
+	result, err := svc.ImportExchangeRates(ctx, ImportExchangeRatesInput{
+		OrganizationID: 1,
+		CSVData:        []byte("2026-03-02,USD,5.10\n2026-03-03,USD,5.12\n2026-03-02,EUR,6.01\n"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, []string{"EUR", "USD"}, result.Currencies)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateAccount_DefaultsToBaseCurrency(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	mockRepo.On("FetchBaseCurrency", ctx, fetchBaseCurrencyParams{OrganizationID: 1}).Return("EUR", nil)
+	mockRepo.On("InsertAccount", ctx, mock.MatchedBy(func(params insertAccountParams) bool {
+		return params.Currency == "EUR"
+	})).Return(AccountModel{AccountID: 1, Currency: "EUR"}, nil)
+
+	_, err := svc.CreateAccount(ctx, CreateAccountInput{OrganizationID: 1, Name: "Conta", AccountType: AccountTypeChecking})
+	require.NoError(t, err)
+
+	_, err = svc.CreateAccount(ctx, CreateAccountInput{OrganizationID: 1, Name: "Conta", AccountType: AccountTypeChecking, Currency: "dollars"})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidCurrency)
+	mockRepo.AssertNumberOfCalls(t, "InsertAccount", 1)
+}
@@ -58,6 +58,14 @@ type Account struct {
 	IsActive       bool
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+
+	OpeningBalance     decimal.Decimal
+	OpeningBalanceDate *time.Time
+	ReconciledThrough  *time.Time
+
+	CardClosingDay  *int
+	CardDueDay      *int
+	BudgetByInvoice bool
 }
 
 func (a Account) FromModel(model *AccountModel) Account {
@@ -73,6 +81,14 @@ func (a Account) FromModel(model *AccountModel) Account {
 		IsActive:       model.IsActive,
 		CreatedAt:      model.CreatedAt,
 		UpdatedAt:      model.UpdatedAt,
+
+		OpeningBalance:     model.OpeningBalance,
+		OpeningBalanceDate: model.OpeningBalanceDate,
+		ReconciledThrough:  model.ReconciledThrough,
+
+		CardClosingDay:  model.CardClosingDay,
+		CardDueDay:      model.CardDueDay,
+		BudgetByInvoice: model.BudgetByInvoice,
 	}
 }
 
@@ -88,27 +104,33 @@ func (a Accounts) FromModel(models []AccountModel) Accounts {
 
 // Transaction DTO
 type Transaction struct {
-	TransactionID        int             `json:"transaction_id"`
-	AccountID            int             `json:"account_id"`
-	CategoryID           *int            `json:"category_id,omitempty"`
-	SavingsGoalID        *int            `json:"savings_goal_id,omitempty"` // Linked savings goal (orthogonal to category)
-	Description          string          `json:"description"`
-	OriginalDescription  *string         `json:"original_description,omitempty"` // Immutable OFX description
-	Amount               decimal.Decimal `json:"amount"`
-	TransactionDate      time.Time       `json:"transaction_date"`
-	TransactionType      string          `json:"transaction_type"`
-	OFXFitID             *string         `json:"ofx_fitid,omitempty"`
-	OFXCheckNum          *string         `json:"ofx_check_number,omitempty"`
-	OFXMemo              *string         `json:"ofx_memo,omitempty"`
-	RawOFXData           *string         `json:"raw_ofx_data,omitempty"`
-	IsClassified         bool            `json:"is_classified"`
-	ClassificationRuleID *int            `json:"classification_rule_id,omitempty"`
-	IsIgnored            bool            `json:"is_ignored"`
-	NeedsReview          bool            `json:"needs_review"`
-	Notes                *string         `json:"notes,omitempty"`
-	Tags                 []string        `json:"tags"`
-	CreatedAt            time.Time       `json:"created_at"`
-	UpdatedAt            time.Time       `json:"updated_at"`
+	TransactionID        int              `json:"transaction_id"`
+	AccountID            int              `json:"account_id"`
+	CategoryID           *int             `json:"category_id,omitempty"`
+	SavingsGoalID        *int             `json:"savings_goal_id,omitempty"` // Linked savings goal (orthogonal to category)
+	Description          string           `json:"description"`
+	OriginalDescription  *string          `json:"original_description,omitempty"` // Immutable OFX description
+	Amount               decimal.Decimal  `json:"amount"`                         // In the account's currency
+	Currency             string           `json:"currency,omitempty"`
+	ConvertedAmount      *decimal.Decimal `json:"converted_amount,omitempty"` // In the organization's base currency; omitted without a rate
+	TransactionDate      time.Time        `json:"transaction_date"`
+	TransactionType      string           `json:"transaction_type"`
+	OFXFitID             *string          `json:"ofx_fitid,omitempty"`
+	OFXCheckNum          *string          `json:"ofx_check_number,omitempty"`
+	OFXMemo              *string          `json:"ofx_memo,omitempty"`
+	RawOFXData           *string          `json:"raw_ofx_data,omitempty"`
+	IsClassified         bool             `json:"is_classified"`
+	ClassificationRuleID *int             `json:"classification_rule_id,omitempty"`
+	PatternID            *int             `json:"pattern_id,omitempty"`
+	IsIgnored            bool             `json:"is_ignored"`
+	NeedsReview          bool             `json:"needs_review"`
+	Notes                *string          `json:"notes,omitempty"`
+	Tags                 []string         `json:"tags"`
+	CreatedAt            time.Time        `json:"created_at"`
+	UpdatedAt            time.Time        `json:"updated_at"`
+
+	// Ranked category predictions; only listed with uncategorized transactions
+	CategorySuggestions []CategorySuggestion `json:"category_suggestions,omitempty"`
 }
 
 func (t Transaction) FromModel(model *TransactionModel) Transaction {
@@ -120,6 +142,8 @@ func (t Transaction) FromModel(model *TransactionModel) Transaction {
 		Description:          model.Description,
 		OriginalDescription:  model.OriginalDescription,
 		Amount:               model.Amount,
+		Currency:             model.Currency,
+		ConvertedAmount:      model.ConvertedAmount,
 		TransactionDate:      model.TransactionDate,
 		TransactionType:      model.TransactionType,
 		OFXFitID:             model.OFXFitID,
@@ -128,6 +152,7 @@ func (t Transaction) FromModel(model *TransactionModel) Transaction {
 		RawOFXData:           model.RawOFXData,
 		IsClassified:         model.IsClassified,
 		ClassificationRuleID: model.ClassificationRuleID,
+		PatternID:            model.PatternID,
 		IsIgnored:            model.IsIgnored,
 		NeedsReview:          model.NeedsReview,
 		Notes:                model.Notes,
@@ -147,26 +172,202 @@ func (t Transactions) FromModel(models []TransactionModel) Transactions {
 	return transactions
 }
 
+// TransactionSplit DTO
+type TransactionSplit struct {
+	SplitID       int             `json:"split_id"`
+	TransactionID int             `json:"transaction_id"`
+	CategoryID    *int            `json:"category_id,omitempty"`
+	SavingsGoalID *int            `json:"savings_goal_id,omitempty"`
+	Amount        decimal.Decimal `json:"amount"`
+	Notes         *string         `json:"notes,omitempty"`
+	TagIDs        []int           `json:"tag_ids"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+func (t TransactionSplit) FromModel(model *TransactionSplitModel) TransactionSplit {
+	tagIDs := make([]int, len(model.TagIDs))
+	for i, id := range model.TagIDs {
+		tagIDs[i] = int(id)
+	}
+	return TransactionSplit{
+		SplitID:       model.SplitID,
+		TransactionID: model.TransactionID,
+		CategoryID:    model.CategoryID,
+		SavingsGoalID: model.SavingsGoalID,
+		Amount:        model.Amount,
+		Notes:         model.Notes,
+		TagIDs:        tagIDs,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+	}
+}
+
+type TransactionSplits []TransactionSplit
+
+func (t TransactionSplits) FromModel(models []TransactionSplitModel) TransactionSplits {
+	splits := make(TransactionSplits, len(models))
+	for i, model := range models {
+		splits[i] = TransactionSplit{}.FromModel(&model)
+	}
+	return splits
+}
+
+// TransferLeg is one side of a transfer
+type TransferLeg struct {
+	TransactionID int             `json:"transaction_id"`
+	AccountID     int             `json:"account_id"`
+	Description   string          `json:"description"`
+	Date          time.Time       `json:"date"`
+	Amount        decimal.Decimal `json:"amount"`
+}
+
+// Transfer DTO
+type Transfer struct {
+	TransferID int         `json:"transfer_id"`
+	Detection  string      `json:"detection"`
+	Debit      TransferLeg `json:"debit"`
+	Credit     TransferLeg `json:"credit"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+func (t Transfer) FromModel(model *TransferModel) Transfer {
+	return Transfer{
+		TransferID: model.TransferID,
+		Detection:  model.Detection,
+		Debit: TransferLeg{
+			TransactionID: model.DebitTransactionID,
+			AccountID:     model.DebitAccountID,
+			Description:   model.DebitDescription,
+			Date:          model.DebitDate,
+			Amount:        model.DebitAmount,
+		},
+		Credit: TransferLeg{
+			TransactionID: model.CreditTransactionID,
+			AccountID:     model.CreditAccountID,
+			Description:   model.CreditDescription,
+			Date:          model.CreditDate,
+			Amount:        model.CreditAmount,
+		},
+		CreatedAt: model.CreatedAt,
+	}
+}
+
+type Transfers []Transfer
+
+func (t Transfers) FromModel(models []TransferModel) Transfers {
+	transfers := make(Transfers, len(models))
+	for i, model := range models {
+		transfers[i] = Transfer{}.FromModel(&model)
+	}
+	return transfers
+}
+
+// InstallmentPlan DTO. Remaining parcels are those after the last imported
+// one; LastMonth/LastYear is when the final parcel falls.
+type InstallmentPlan struct {
+	InstallmentPlanID  int             `json:"installment_plan_id"`
+	AccountID          int             `json:"account_id"`
+	CategoryID         *int            `json:"category_id,omitempty"`
+	Description        string          `json:"description"`
+	InstallmentAmount  decimal.Decimal `json:"installment_amount"`
+	InstallmentCount   int             `json:"installment_count"`
+	FirstMonth         int             `json:"first_month"`
+	FirstYear          int             `json:"first_year"`
+	LastMonth          int             `json:"last_month"`
+	LastYear           int             `json:"last_year"`
+	LastImportedNumber int             `json:"last_imported_number"`
+	RemainingCount     int             `json:"remaining_count"`
+	RemainingAmount    decimal.Decimal `json:"remaining_amount"`
+	IsActive           bool            `json:"is_active"`
+	CreatedAt          time.Time       `json:"created_at"`
+}
+
+func (p InstallmentPlan) FromModel(model *InstallmentPlanModel) InstallmentPlan {
+	last := installmentParcelMonth(model, model.InstallmentCount)
+	remaining := model.InstallmentCount - model.LastImportedNumber
+	return InstallmentPlan{
+		InstallmentPlanID:  model.InstallmentPlanID,
+		AccountID:          model.AccountID,
+		CategoryID:         model.CategoryID,
+		Description:        model.Description,
+		InstallmentAmount:  model.InstallmentAmount,
+		InstallmentCount:   model.InstallmentCount,
+		FirstMonth:         model.FirstMonth,
+		FirstYear:          model.FirstYear,
+		LastMonth:          int(last.Month()),
+		LastYear:           last.Year(),
+		LastImportedNumber: model.LastImportedNumber,
+		RemainingCount:     remaining,
+		RemainingAmount:    model.InstallmentAmount.Mul(decimal.NewFromInt(int64(remaining))),
+		IsActive:           model.IsActive,
+		CreatedAt:          model.CreatedAt,
+	}
+}
+
+type InstallmentPlans []InstallmentPlan
+
+func (p InstallmentPlans) FromModel(models []InstallmentPlanModel) InstallmentPlans {
+	plans := make(InstallmentPlans, len(models))
+	for i, model := range models {
+		plans[i] = InstallmentPlan{}.FromModel(&model)
+	}
+	return plans
+}
+
+// ExchangeRate DTO
+type ExchangeRate struct {
+	Currency     string          `json:"currency"`
+	BaseCurrency string          `json:"base_currency"`
+	RateDate     time.Time       `json:"rate_date"`
+	Rate         decimal.Decimal `json:"rate"`
+	Source       string          `json:"source"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+func (e ExchangeRate) FromModel(model *ExchangeRateModel) ExchangeRate {
+	return ExchangeRate{
+		Currency:     model.Currency,
+		BaseCurrency: model.BaseCurrency,
+		RateDate:     model.RateDate,
+		Rate:         model.Rate,
+		Source:       model.Source,
+		UpdatedAt:    model.UpdatedAt,
+	}
+}
+
+type ExchangeRates []ExchangeRate
+
+func (e ExchangeRates) FromModel(models []ExchangeRateModel) ExchangeRates {
+	rates := make(ExchangeRates, len(models))
+	for i, model := range models {
+		rates[i] = ExchangeRate{}.FromModel(&model)
+	}
+	return rates
+}
+
 // ClassificationRule DTO
 type ClassificationRule struct {
-	RuleID               int
-	UserID               int
-	CategoryID           int
-	Name                 string
-	Priority             int
-	MatchDescription     *string
-	MatchAmountMin       *decimal.Decimal
-	MatchAmountMax       *decimal.Decimal
-	MatchTransactionType *string
-	IsActive             bool
-	CreatedAt            time.Time
-	UpdatedAt            time.Time
+	RuleID               int              `json:"rule_id"`
+	UserID               int              `json:"user_id"`
+	OrganizationID       int              `json:"organization_id"`
+	CategoryID           int              `json:"category_id"`
+	Name                 string           `json:"name"`
+	Priority             int              `json:"priority"`
+	MatchDescription     *string          `json:"match_description,omitempty"`
+	MatchAmountMin       *decimal.Decimal `json:"match_amount_min,omitempty"`
+	MatchAmountMax       *decimal.Decimal `json:"match_amount_max,omitempty"`
+	MatchTransactionType *string          `json:"match_transaction_type,omitempty"`
+	IsActive             bool             `json:"is_active"`
+	CreatedAt            time.Time        `json:"created_at"`
+	UpdatedAt            time.Time        `json:"updated_at"`
 }
 
 func (c ClassificationRule) FromModel(model *ClassificationRuleModel) ClassificationRule {
 	return ClassificationRule{
 		RuleID:               model.RuleID,
 		UserID:               model.UserID,
+		OrganizationID:       model.OrganizationID,
 		CategoryID:           model.CategoryID,
 		Name:                 model.Name,
 		Priority:             model.Priority,
@@ -260,6 +461,14 @@ type PlannedEntry struct {
 	ParentEntryID *int `json:",omitempty"`
 	IsActive      bool
 
+	// Recurrence rule of recurrent entries
+	RecurrenceFrequency string
+	RecurrenceInterval  int
+	RecurrenceMonths    []int      `json:",omitempty"`
+	StartsOn            *time.Time `json:",omitempty"`
+	EndsOn              *time.Time `json:",omitempty"`
+	OccurrenceCount     *int       `json:",omitempty"`
+
 	// Tags assigned to the entry; transferred to a transaction when matched.
 	TagIDs []int
 
@@ -268,6 +477,11 @@ type PlannedEntry struct {
 }
 
 func (p PlannedEntry) FromModel(model *PlannedEntryModel) PlannedEntry {
+	var recurrenceMonths []int
+	for _, month := range model.RecurrenceMonths {
+		recurrenceMonths = append(recurrenceMonths, int(month))
+	}
+
 	return PlannedEntry{
 		PlannedEntryID:   model.PlannedEntryID,
 		UserID:           model.UserID,
@@ -288,6 +502,13 @@ func (p PlannedEntry) FromModel(model *PlannedEntryModel) PlannedEntry {
 		IsActive:         model.IsActive,
 		CreatedAt:        model.CreatedAt,
 		UpdatedAt:        model.UpdatedAt,
+
+		RecurrenceFrequency: model.RecurrenceFrequency,
+		RecurrenceInterval:  model.RecurrenceInterval,
+		RecurrenceMonths:    recurrenceMonths,
+		StartsOn:            model.StartsOn,
+		EndsOn:              model.EndsOn,
+		OccurrenceCount:     model.OccurrenceCount,
 	}
 }
 
@@ -355,6 +576,7 @@ type PlannedEntryWithStatus struct {
 	Status        string           // Current status for the month
 	StatusColor   string           // green, yellow, red, gray
 	MatchedAmount *decimal.Decimal `json:",omitempty"`
+	Occurrences   int              // Times the entry falls in the month (weekly entries: once per week)
 
 	// Matched transaction info (when status = matched)
 	MatchedTransactionID *int    `json:",omitempty"`
@@ -421,6 +643,45 @@ func (m MonthlySnapshots) FromModel(models []MonthlySnapshotModel) MonthlySnapsh
 	return snapshots
 }
 
+// MonthReopening is an audit record of a closed month being reopened
+type MonthReopening struct {
+	MonthReopeningID int              `json:"month_reopening_id"`
+	OrganizationID   int              `json:"organization_id"`
+	UserID           int              `json:"user_id"`
+	Month            int              `json:"month"`
+	Year             int              `json:"year"`
+	Reason           string           `json:"reason"`
+	ClosedAt         time.Time        `json:"closed_at"`
+	CarryoverAmount  *decimal.Decimal `json:"carryover_amount,omitempty"`
+	SnapshotCount    int              `json:"snapshot_count"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+func (m MonthReopening) FromModel(model *MonthReopeningModel) MonthReopening {
+	return MonthReopening{
+		MonthReopeningID: model.MonthReopeningID,
+		OrganizationID:   model.OrganizationID,
+		UserID:           model.UserID,
+		Month:            model.Month,
+		Year:             model.Year,
+		Reason:           model.Reason,
+		ClosedAt:         model.ClosedAt,
+		CarryoverAmount:  model.CarryoverAmount,
+		SnapshotCount:    model.SnapshotCount,
+		CreatedAt:        model.CreatedAt,
+	}
+}
+
+type MonthReopenings []MonthReopening
+
+func (m MonthReopenings) FromModel(models []MonthReopeningModel) MonthReopenings {
+	reopenings := make(MonthReopenings, len(models))
+	for i, model := range models {
+		reopenings[i] = MonthReopening{}.FromModel(&model)
+	}
+	return reopenings
+}
+
 // LinkedPlannedEntrySummary contains minimal info about a planned entry linked to a pattern
 type LinkedPlannedEntrySummary struct {
 	PlannedEntryID int    `json:"planned_entry_id"`
@@ -441,6 +702,7 @@ type Pattern struct {
 	TargetCategoryID   *int             `json:"target_category_id,omitempty"`
 	Action             PatternAction    `json:"action"`
 	ApplyRetroactively bool             `json:"apply_retroactively"`
+	Priority           int              `json:"priority"`
 	IsActive           bool             `json:"is_active"`
 	CreatedAt          time.Time        `json:"created_at"`
 	UpdatedAt          time.Time        `json:"updated_at"`
@@ -463,6 +725,7 @@ func (p Pattern) FromModel(model *AdvancedPatternModel) Pattern {
 		TargetCategoryID:   model.TargetCategoryID,
 		Action:             normalizePatternAction(model.Action),
 		ApplyRetroactively: model.ApplyRetroactively,
+		Priority:           model.Priority,
 		IsActive:           model.IsActive,
 		CreatedAt:          model.CreatedAt,
 		UpdatedAt:          model.UpdatedAt,
@@ -568,6 +831,9 @@ type SavingsGoalProgress struct {
 
 	// Monthly breakdown of contributions
 	MonthlyContributions []MonthlyContribution `json:"monthly_contributions,omitempty"`
+
+	// Foreign-currency contributions left out for lack of an exchange rate
+	MissingRates int `json:"missing_rates"`
 }
 
 // MonthlyContribution represents contributions to a goal in a specific month
@@ -646,5 +912,158 @@ type TagSpending struct {
 	Total            string            `json:"total"`   // spent: sum of expense transactions carrying the tag
 	Planned          string            `json:"planned"` // planned budget: sum of planned expense entries for the month
 	TransactionCount int               `json:"transaction_count"`
+	MissingRates     int               `json:"missing_rates"` // Transactions left out of total for lack of an exchange rate
 	PlannedEntries   []TagPlannedEntry `json:"planned_entries"`
 }
+
+// PluggyAccountLink DTO - a Pluggy account linked to a Celeiro account, with its sync status
+type PluggyAccountLink struct {
+	PluggyAccountLinkID int        `json:"pluggy_account_link_id"`
+	AccountID           int        `json:"account_id"`
+	PluggyItemID        string     `json:"pluggy_item_id"`
+	PluggyAccountID     string     `json:"pluggy_account_id"`
+	SyncCursor          *time.Time `json:"sync_cursor,omitempty"`
+	LastSyncedAt        *time.Time `json:"last_synced_at,omitempty"`
+	LastSyncStatus      string     `json:"last_sync_status"`
+	LastSyncError       *string    `json:"last_sync_error,omitempty"`
+	LastImportedCount   int        `json:"last_imported_count"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+func (p PluggyAccountLink) FromModel(model *PluggyAccountLinkModel) PluggyAccountLink {
+	return PluggyAccountLink{
+		PluggyAccountLinkID: model.PluggyAccountLinkID,
+		AccountID:           model.AccountID,
+		PluggyItemID:        model.PluggyItemID,
+		PluggyAccountID:     model.PluggyAccountID,
+		SyncCursor:          model.SyncCursor,
+		LastSyncedAt:        model.LastSyncedAt,
+		LastSyncStatus:      model.LastSyncStatus,
+		LastSyncError:       model.LastSyncError,
+		LastImportedCount:   model.LastImportedCount,
+		CreatedAt:           model.CreatedAt,
+		UpdatedAt:           model.UpdatedAt,
+	}
+}
+
+type PluggyAccountLinks []PluggyAccountLink
+
+func (p PluggyAccountLinks) FromModel(models []PluggyAccountLinkModel) PluggyAccountLinks {
+	links := make(PluggyAccountLinks, len(models))
+	for i, model := range models {
+		links[i] = PluggyAccountLink{}.FromModel(&model)
+	}
+	return links
+}
+
+// CSVImportProfile DTO - how to read a bank's CSV statement for an account
+type CSVImportProfile struct {
+	CSVImportProfileID int       `json:"csv_import_profile_id"`
+	AccountID          int       `json:"account_id"`
+	Name               string    `json:"name"`
+	Delimiter          string    `json:"delimiter"`
+	SkipRows           int       `json:"skip_rows"`
+	DateColumn         string    `json:"date_column"`
+	DateFormat         string    `json:"date_format"`
+	DescriptionColumns []string  `json:"description_columns"`
+	IDColumn           *string   `json:"id_column,omitempty"`
+	AmountColumn       *string   `json:"amount_column,omitempty"`
+	DebitColumn        *string   `json:"debit_column,omitempty"`
+	CreditColumn       *string   `json:"credit_column,omitempty"`
+	DecimalSeparator   string    `json:"decimal_separator"`
+	SignConvention     string    `json:"sign_convention"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+func (p CSVImportProfile) FromModel(model *CSVImportProfileModel) CSVImportProfile {
+	return CSVImportProfile{
+		CSVImportProfileID: model.CSVImportProfileID,
+		AccountID:          model.AccountID,
+		Name:               model.Name,
+		Delimiter:          model.Delimiter,
+		SkipRows:           model.SkipRows,
+		DateColumn:         model.DateColumn,
+		DateFormat:         model.DateFormat,
+		DescriptionColumns: model.DescriptionColumns,
+		IDColumn:           model.IDColumn,
+		AmountColumn:       model.AmountColumn,
+		DebitColumn:        model.DebitColumn,
+		CreditColumn:       model.CreditColumn,
+		DecimalSeparator:   model.DecimalSeparator,
+		SignConvention:     model.SignConvention,
+		CreatedAt:          model.CreatedAt,
+		UpdatedAt:          model.UpdatedAt,
+	}
+}
+
+type CSVImportProfiles []CSVImportProfile
+
+func (p CSVImportProfiles) FromModel(models []CSVImportProfileModel) CSVImportProfiles {
+	profiles := make(CSVImportProfiles, len(models))
+	for i, model := range models {
+		profiles[i] = CSVImportProfile{}.FromModel(&model)
+	}
+	return profiles
+}
+
+// ReconciliationCheckpoint compares a statement balance with the balance
+// computed from transactions at the end of the same day.
+type ReconciliationCheckpoint struct {
+	BalanceDate      time.Time       `json:"balance_date"`
+	StatementBalance decimal.Decimal `json:"statement_balance"`
+	ComputedBalance  decimal.Decimal `json:"computed_balance"`
+	Difference       decimal.Decimal `json:"difference"` // computed - statement
+	Source           string          `json:"source"`
+	Matches          bool            `json:"matches"`
+}
+
+func (c ReconciliationCheckpoint) FromModel(model *AccountStatementBalanceModel) ReconciliationCheckpoint {
+	difference := model.ComputedBalance.Sub(model.Balance)
+	return ReconciliationCheckpoint{
+		BalanceDate:      model.BalanceDate,
+		StatementBalance: model.Balance,
+		ComputedBalance:  model.ComputedBalance,
+		Difference:       difference,
+		Source:           model.Source,
+		Matches:          difference.IsZero(),
+	}
+}
+
+type ReconciliationCheckpoints []ReconciliationCheckpoint
+
+func (c ReconciliationCheckpoints) FromModel(models []AccountStatementBalanceModel) ReconciliationCheckpoints {
+	checkpoints := make(ReconciliationCheckpoints, len(models))
+	for i, model := range models {
+		checkpoints[i] = ReconciliationCheckpoint{}.FromModel(&model)
+	}
+	return checkpoints
+}
+
+// LedgerEntry is a transaction with the account balance right after it.
+type LedgerEntry struct {
+	Transaction    Transaction     `json:"transaction"`
+	RunningBalance decimal.Decimal `json:"running_balance"`
+}
+
+// ReconciliationDivergence is the window where the computed balance first
+// stops matching the bank: from the last matching checkpoint (or the opening
+// balance) up to the first checkpoint that does not match.
+type ReconciliationDivergence struct {
+	After           *time.Time      `json:"after,omitempty"` // Last matching checkpoint; nil when none matched
+	Through         time.Time       `json:"through"`
+	StartingBalance decimal.Decimal `json:"starting_balance"`
+	Difference      decimal.Decimal `json:"difference"`
+	Entries         []LedgerEntry   `json:"entries"`
+}
+
+type AccountReconciliation struct {
+	AccountID          int                        `json:"account_id"`
+	Balance            decimal.Decimal            `json:"balance"`
+	OpeningBalance     decimal.Decimal            `json:"opening_balance"`
+	OpeningBalanceDate *time.Time                 `json:"opening_balance_date,omitempty"`
+	ReconciledThrough  *time.Time                 `json:"reconciled_through,omitempty"`
+	Checkpoints        []ReconciliationCheckpoint `json:"checkpoints"`
+	Divergence         *ReconciliationDivergence  `json:"divergence,omitempty"`
+}
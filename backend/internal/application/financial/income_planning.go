@@ -20,6 +20,7 @@ type IncomePlanningReport struct {
 	Threshold          float64         `json:"threshold"`          // 0.25
 	Status             string          `json:"status"`             // "OK" or "WARNING"
 	Message            string          `json:"message"`
+	MissingRates       int             `json:"missingRates"` // Foreign-currency income left out for lack of an exchange rate
 }
 
 type GetIncomePlanningInput struct {
@@ -32,15 +33,17 @@ type GetIncomePlanningInput struct {
 // GetIncomePlanning calculates the income planning report for a given month
 func (s *service) GetIncomePlanning(ctx context.Context, input GetIncomePlanningInput) (*IncomePlanningReport, error) {
 	// 1. Calculate total income for the month (sum of all credit transactions)
-	totalIncome, err := s.calculateMonthlyIncome(ctx, input.UserID, input.OrganizationID, input.Month, input.Year)
+	totalIncome, missingRates, err := s.calculateMonthlyIncome(ctx, input.UserID, input.OrganizationID, input.Month, input.Year)
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. Get total planned amounts: sum of planned entries + controlled amounts
-	// Planned entries (recurrent expense entries)
+	// Planned entries (recurrent expense entries falling in the month)
 	plannedEntrySums, err := s.Repository.FetchPlannedEntrySumsByCategory(ctx, fetchPlannedEntrySumsByCategoryParams{
 		OrganizationID: input.OrganizationID,
+		Month:          input.Month,
+		Year:           input.Year,
 	})
 	if err != nil {
 		return nil, err
@@ -103,30 +106,47 @@ func (s *service) GetIncomePlanning(ctx context.Context, input GetIncomePlanning
 		Threshold:          UnplannedIncomeThreshold * 100, // Return as percentage (0.25)
 		Status:             status,
 		Message:            message,
+		MissingRates:       missingRates,
 	}, nil
 }
 
-// calculateMonthlyIncome sums all credit transactions for a given month
-func (s *service) calculateMonthlyIncome(ctx context.Context, userID, organizationID, month, year int) (decimal.Decimal, error) {
+// calculateMonthlyIncome sums all credit transactions for a given month, and
+// counts the ones left out for lack of an exchange rate
+func (s *service) calculateMonthlyIncome(ctx context.Context, userID, organizationID, month, year int) (decimal.Decimal, int, error) {
 	// Use the repository to fetch transactions by month for the organization
 	transactions, err := s.Repository.FetchTransactionsByMonth(ctx, fetchTransactionsByMonthParams{
 		OrganizationID: organizationID,
 		Month:          month,
 		Year:           year,
+		BudgetMonth:    true,
 	})
 	if err != nil {
-		return decimal.Zero, err
+		return decimal.Zero, 0, err
+	}
+
+	transfers, err := s.transferTransactionSet(ctx, organizationID)
+	if err != nil {
+		return decimal.Zero, 0, err
 	}
 
 	// Filter by credit type and sum amounts (month/year already filtered by query)
 	total := decimal.Zero
+	missingRates := 0
 	for _, tx := range transactions {
 		// Only include credit transactions
 		if tx.TransactionType != "credit" {
 			continue
 		}
-		total = total.Add(tx.Amount)
+		// Money arriving from another of our accounts is not income
+		if _, ok := transfers[tx.TransactionID]; ok {
+			continue
+		}
+		amount, ok := convertedAmount(&tx, tx.Amount)
+		if !ok {
+			missingRates++
+		}
+		total = total.Add(amount)
 	}
 
-	return total, nil
+	return total, missingRates, nil
 }
@@ -0,0 +1,442 @@
+package financial
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// installmentSuffixPattern matches the parcel suffix card statements put on
+// installment purchases: "LOJA X 03/10", "LOJA X PARC 3/10", "LOJA X - 03 / 10".
+// The candidates query runs the same pattern in Postgres, so it sticks to
+// syntax both engines read alike.
+var installmentSuffixPattern = regexp.MustCompile(`(?i)^(.*?)\s*(?:PARC(?:ELA)?\.?\s*)?([0-9]{1,2})\s*/\s*([0-9]{1,2})\s*$`)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetInstallmentPlansInput struct {
+	OrganizationID int
+}
+
+type DetectInstallmentsInput struct {
+	OrganizationID int
+}
+
+// DetectInstallmentsOutput lists the plans that got new parcels, and how many
+// future parcels were projected as planned entries.
+type DetectInstallmentsOutput struct {
+	Plans     []InstallmentPlan `json:"plans"`
+	Projected int               `json:"projected"`
+}
+
+// UpdateInstallmentPlanInput changes the category future parcels are planned
+// under, or stops projecting them. Either change replaces the projection.
+type UpdateInstallmentPlanInput struct {
+	InstallmentPlanID int
+	OrganizationID    int
+	CategoryID        *int
+	IsActive          *bool
+}
+
+type GetOutstandingInstallmentsInput struct {
+	OrganizationID int
+}
+
+type OutstandingInstallmentMonth struct {
+	Month  int             `json:"month"`
+	Year   int             `json:"year"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+// OutstandingInstallments is the installment debt still to be billed: every
+// parcel after the last imported one, of active plans.
+type OutstandingInstallments struct {
+	Total   decimal.Decimal               `json:"total"`
+	Plans   []InstallmentPlan             `json:"plans"`
+	ByMonth []OutstandingInstallmentMonth `json:"by_month"`
+}
+
+// installmentSuffix is a description split by parseInstallmentSuffix
+type installmentSuffix struct {
+	description string
+	number      int
+	count       int
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+func (s *service) GetInstallmentPlans(ctx context.Context, input GetInstallmentPlansInput) ([]InstallmentPlan, error) {
+	models, err := s.Repository.FetchInstallmentPlans(ctx, fetchInstallmentPlansParams{
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch installment plans")
+	}
+
+	return InstallmentPlans{}.FromModel(models), nil
+}
+
+// DetectInstallments groups card purchases with a parcel suffix into plans, by
+// merchant, number of parcels and the budget month of the first parcel, then
+// projects the parcels still to come into the months they will be billed in.
+// A projected parcel is marked matched once its transaction is imported.
+func (s *service) DetectInstallments(ctx context.Context, input DetectInstallmentsInput) (DetectInstallmentsOutput, error) {
+	candidates, err := s.Repository.FetchInstallmentCandidates(ctx, fetchInstallmentCandidatesParams{
+		OrganizationID: input.OrganizationID,
+		SuffixPattern:  installmentSuffixPattern.String(),
+	})
+	if err != nil {
+		return DetectInstallmentsOutput{}, errors.Wrap(err, "failed to fetch installment candidates")
+	}
+
+	output := DetectInstallmentsOutput{Plans: []InstallmentPlan{}}
+	if len(candidates) == 0 {
+		return output, nil
+	}
+
+	accounts, err := s.fetchAccountsByID(ctx, input.OrganizationID)
+	if err != nil {
+		return DetectInstallmentsOutput{}, err
+	}
+
+	isClosed := s.monthClosedChecker(ctx, input.OrganizationID)
+	matchedAt := s.system.Time.Now().Format(time.RFC3339)
+
+	var planIDs []int
+	for _, tx := range candidates {
+		description := tx.Description
+		if tx.OriginalDescription != nil && *tx.OriginalDescription != "" {
+			description = *tx.OriginalDescription
+		}
+		suffix, ok := parseInstallmentSuffix(description)
+		if !ok {
+			continue
+		}
+		account, ok := accounts[tx.AccountID]
+		if !ok {
+			continue
+		}
+
+		month := transactionBudgetMonth(&account, tx.TransactionDate)
+		first := month.AddDate(0, -(suffix.number - 1), 0)
+		plan, err := s.Repository.UpsertInstallmentPlan(ctx, upsertInstallmentPlanParams{
+			OrganizationID:    input.OrganizationID,
+			AccountID:         tx.AccountID,
+			CategoryID:        tx.CategoryID,
+			Description:       suffix.description,
+			InstallmentAmount: tx.Amount,
+			InstallmentCount:  suffix.count,
+			FirstMonth:        int(first.Month()),
+			FirstYear:         first.Year(),
+		})
+		if err != nil {
+			return DetectInstallmentsOutput{}, errors.Wrap(err, "failed to save installment plan")
+		}
+
+		parcel, err := s.Repository.UpsertInstallmentParcel(ctx, upsertInstallmentParcelParams{
+			InstallmentPlanID: plan.InstallmentPlanID,
+			InstallmentNumber: suffix.number,
+			Month:             int(month.Month()),
+			Year:              month.Year(),
+			TransactionID:     &tx.TransactionID,
+		})
+		if err != nil {
+			return DetectInstallmentsOutput{}, errors.Wrap(err, "failed to save installment parcel")
+		}
+		if parcel.TransactionID == nil || *parcel.TransactionID != tx.TransactionID {
+			continue // The parcel was already imported from another transaction
+		}
+		planIDs = append(planIDs, plan.InstallmentPlanID)
+
+		if parcel.PlannedEntryID == nil {
+			continue
+		}
+		closed, err := isClosed(parcel.Month, parcel.Year)
+		if err != nil {
+			return DetectInstallmentsOutput{}, err
+		}
+		if closed {
+			continue
+		}
+		status, err := s.Repository.UpsertPlannedEntryStatus(ctx, upsertPlannedEntryStatusParams{
+			PlannedEntryID: *parcel.PlannedEntryID,
+			Month:          parcel.Month,
+			Year:           parcel.Year,
+			Status:         PlannedEntryStatusMatched,
+		})
+		if err != nil {
+			return DetectInstallmentsOutput{}, errors.Wrap(err, "failed to match projected parcel")
+		}
+		if _, err := s.Repository.ModifyPlannedEntryStatus(ctx, modifyPlannedEntryStatusParams{
+			StatusID:             status.StatusID,
+			MatchedTransactionID: &tx.TransactionID,
+			MatchedAmount:        &tx.Amount,
+			MatchedAt:            &matchedAt,
+		}); err != nil {
+			return DetectInstallmentsOutput{}, errors.Wrap(err, "failed to match projected parcel")
+		}
+	}
+
+	planIDs = uniqueIDs(planIDs)
+	if len(planIDs) == 0 {
+		return output, nil
+	}
+
+	plans, err := s.Repository.FetchInstallmentPlans(ctx, fetchInstallmentPlansParams{
+		OrganizationID:     input.OrganizationID,
+		InstallmentPlanIDs: planIDs,
+	})
+	if err != nil {
+		return DetectInstallmentsOutput{}, errors.Wrap(err, "failed to fetch installment plans")
+	}
+	parcels, err := s.Repository.FetchInstallmentParcels(ctx, fetchInstallmentParcelsParams{
+		OrganizationID:     input.OrganizationID,
+		InstallmentPlanIDs: planIDs,
+	})
+	if err != nil {
+		return DetectInstallmentsOutput{}, errors.Wrap(err, "failed to fetch installment parcels")
+	}
+
+	for _, plan := range plans {
+		projected, err := s.projectInstallmentPlan(ctx, plan, accounts[plan.AccountID], parcels, isClosed)
+		if err != nil {
+			return DetectInstallmentsOutput{}, err
+		}
+		output.Projected += projected
+	}
+	output.Plans = InstallmentPlans{}.FromModel(plans)
+
+	s.logger.Info(ctx, "Installments detected",
+		"organization_id", input.OrganizationID,
+		"plans", len(output.Plans),
+		"projected", output.Projected,
+	)
+
+	return output, nil
+}
+
+func (s *service) UpdateInstallmentPlan(ctx context.Context, input UpdateInstallmentPlanInput) (InstallmentPlan, error) {
+	plan, err := s.Repository.ModifyInstallmentPlan(ctx, modifyInstallmentPlanParams{
+		InstallmentPlanID: input.InstallmentPlanID,
+		OrganizationID:    input.OrganizationID,
+		CategoryID:        input.CategoryID,
+		IsActive:          input.IsActive,
+	})
+	if err != nil {
+		return InstallmentPlan{}, errors.Wrap(err, "failed to update installment plan")
+	}
+
+	if err := s.Repository.RemoveInstallmentProjections(ctx, removeInstallmentProjectionsParams{
+		InstallmentPlanID: plan.InstallmentPlanID,
+		OrganizationID:    input.OrganizationID,
+	}); err != nil {
+		return InstallmentPlan{}, errors.Wrap(err, "failed to remove installment projections")
+	}
+
+	accounts, err := s.fetchAccountsByID(ctx, input.OrganizationID)
+	if err != nil {
+		return InstallmentPlan{}, err
+	}
+	parcels, err := s.Repository.FetchInstallmentParcels(ctx, fetchInstallmentParcelsParams{
+		OrganizationID:     input.OrganizationID,
+		InstallmentPlanIDs: []int{plan.InstallmentPlanID},
+	})
+	if err != nil {
+		return InstallmentPlan{}, errors.Wrap(err, "failed to fetch installment parcels")
+	}
+
+	if _, err := s.projectInstallmentPlan(ctx, plan, accounts[plan.AccountID], parcels,
+		s.monthClosedChecker(ctx, input.OrganizationID)); err != nil {
+		return InstallmentPlan{}, err
+	}
+
+	return InstallmentPlan{}.FromModel(&plan), nil
+}
+
+func (s *service) GetOutstandingInstallments(ctx context.Context, input GetOutstandingInstallmentsInput) (OutstandingInstallments, error) {
+	models, err := s.Repository.FetchInstallmentPlans(ctx, fetchInstallmentPlansParams{
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return OutstandingInstallments{}, errors.Wrap(err, "failed to fetch installment plans")
+	}
+
+	output := OutstandingInstallments{
+		Total:   decimal.Zero,
+		Plans:   []InstallmentPlan{},
+		ByMonth: []OutstandingInstallmentMonth{},
+	}
+	byMonth := make(map[time.Time]decimal.Decimal)
+	for _, model := range models {
+		if !model.IsActive || model.LastImportedNumber >= model.InstallmentCount {
+			continue
+		}
+
+		plan := InstallmentPlan{}.FromModel(&model)
+		output.Plans = append(output.Plans, plan)
+		output.Total = output.Total.Add(plan.RemainingAmount)
+		for number := model.LastImportedNumber + 1; number <= model.InstallmentCount; number++ {
+			month := installmentParcelMonth(&model, number)
+			byMonth[month] = byMonth[month].Add(model.InstallmentAmount)
+		}
+	}
+
+	for month, amount := range byMonth {
+		output.ByMonth = append(output.ByMonth, OutstandingInstallmentMonth{
+			Month:  int(month.Month()),
+			Year:   month.Year(),
+			Amount: amount,
+		})
+	}
+	sort.Slice(output.ByMonth, func(i, j int) bool {
+		if output.ByMonth[i].Year != output.ByMonth[j].Year {
+			return output.ByMonth[i].Year < output.ByMonth[j].Year
+		}
+		return output.ByMonth[i].Month < output.ByMonth[j].Month
+	})
+
+	return output, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// projectInstallmentPlan adds a planned expense, in the plan's category, for
+// each parcel after the last imported one that is not projected yet. Plans
+// that are inactive or have no category project nothing, and closed months
+// are left alone. Returns how many parcels were projected.
+func (s *service) projectInstallmentPlan(ctx context.Context, plan InstallmentPlanModel, account AccountModel, parcels []InstallmentParcelModel, isClosed func(month, year int) (bool, error)) (int, error) {
+	if !plan.IsActive || plan.CategoryID == nil {
+		return 0, nil
+	}
+
+	known := make(map[int]bool)
+	for _, parcel := range parcels {
+		if parcel.InstallmentPlanID == plan.InstallmentPlanID &&
+			(parcel.TransactionID != nil || parcel.PlannedEntryID != nil) {
+			known[parcel.InstallmentNumber] = true
+		}
+	}
+
+	projected := 0
+	for number := plan.LastImportedNumber + 1; number <= plan.InstallmentCount; number++ {
+		if known[number] {
+			continue
+		}
+		date := installmentParcelMonth(&plan, number)
+		month, year := int(date.Month()), date.Year()
+		closed, err := isClosed(month, year)
+		if err != nil {
+			return projected, err
+		}
+		if closed {
+			continue
+		}
+
+		entry, err := s.Repository.InsertPlannedEntry(ctx, insertPlannedEntryParams{
+			UserID:         account.UserID,
+			OrganizationID: plan.OrganizationID,
+			CategoryID:     *plan.CategoryID,
+			Description:    fmt.Sprintf("%s (%02d/%02d)", plan.Description, number, plan.InstallmentCount),
+			Amount:         plan.InstallmentAmount,
+			EntryType:      PlannedEntryTypeExpense,
+			IsRecurrent:    false,
+			TargetMonth:    &month,
+			TargetYear:     &year,
+		})
+		if err != nil {
+			return projected, errors.Wrap(err, "failed to project installment parcel")
+		}
+		if _, err := s.Repository.UpsertInstallmentParcel(ctx, upsertInstallmentParcelParams{
+			InstallmentPlanID: plan.InstallmentPlanID,
+			InstallmentNumber: number,
+			Month:             month,
+			Year:              year,
+			PlannedEntryID:    &entry.PlannedEntryID,
+		}); err != nil {
+			return projected, errors.Wrap(err, "failed to save installment parcel")
+		}
+		projected++
+	}
+
+	return projected, nil
+}
+
+func (s *service) fetchAccountsByID(ctx context.Context, organizationID int) (map[int]AccountModel, error) {
+	models, err := s.Repository.FetchAccounts(ctx, fetchAccountsParams{
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch accounts")
+	}
+
+	accounts := make(map[int]AccountModel, len(models))
+	for _, account := range models {
+		accounts[account.AccountID] = account
+	}
+	return accounts, nil
+}
+
+// monthClosedChecker returns a lookup of closed months that only asks the
+// repository once per month.
+func (s *service) monthClosedChecker(ctx context.Context, organizationID int) func(month, year int) (bool, error) {
+	closedMonths := make(map[[2]int]bool)
+	return func(month, year int) (bool, error) {
+		key := [2]int{year, month}
+		if closed, ok := closedMonths[key]; ok {
+			return closed, nil
+		}
+		closed, err := s.Repository.IsMonthClosed(ctx, monthClosureParams{
+			OrganizationID: organizationID,
+			Month:          month,
+			Year:           year,
+		})
+		if err != nil {
+			return false, errors.Wrap(err, "failed to check month closure")
+		}
+		closedMonths[key] = closed
+		return closed, nil
+	}
+}
+
+// parseInstallmentSuffix splits "LOJA X 03/10" into the merchant and the
+// parcel number and count. Descriptions whose numbers cannot be a parcel, such
+// as dates ("PADARIA 15/03"), are rejected.
+func parseInstallmentSuffix(description string) (installmentSuffix, bool) {
+	match := installmentSuffixPattern.FindStringSubmatch(strings.TrimSpace(description))
+	if match == nil {
+		return installmentSuffix{}, false
+	}
+
+	number, _ := strconv.Atoi(match[2])
+	count, _ := strconv.Atoi(match[3])
+	if count < 2 || number < 1 || number > count {
+		return installmentSuffix{}, false
+	}
+
+	merchant := strings.Trim(strings.Join(strings.Fields(match[1]), " "), " -*")
+	if merchant == "" {
+		return installmentSuffix{}, false
+	}
+
+	return installmentSuffix{description: merchant, number: number, count: count}, true
+}
+
+// installmentParcelMonth returns the first day of the budget month a parcel
+// falls in.
+func installmentParcelMonth(plan *InstallmentPlanModel, number int) time.Time {
+	return time.Date(plan.FirstYear, time.Month(plan.FirstMonth), 1, 0, 0, 0, 0, time.UTC).
+		AddDate(0, number-1, 0)
+}
@@ -0,0 +1,165 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInstallmentSuffix(t *testing.T) {
+	tests := []struct {
+		description string
+		want        installmentSuffix
+		wantOK      bool
+	}{
+		{description: "LOJA X 03/10", want: installmentSuffix{description: "LOJA X", number: 3, count: 10}, wantOK: true},
+		{description: "MAGAZINE  LUIZA - PARC 1 / 12 ", want: installmentSuffix{description: "MAGAZINE LUIZA", number: 1, count: 12}, wantOK: true},
+		{description: "Netshoes*Parcela 02/02", want: installmentSuffix{description: "Netshoes", number: 2, count: 2}, wantOK: true},
+		{description: "PADARIA 15/03"},
+		{description: "UBER 01/01"},
+		{description: "03/10"},
+		{description: "SUPERMERCADO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			got, ok := parseInstallmentSuffix(tt.description)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDetectInstallments_ProjectsRemainingParcels(t *testing.T) {
+	mockRepo := new(MockRepository)
+	stub := system.NewStubSystem()
+	stub.Time.SetTimes(time.Date(2026, time.March, 20, 9, 0, 0, 0, time.UTC))
+	svc := &service{Repository: mockRepo, system: stub.ToSystem(), logger: &logging.TestLogger{}}
+	ctx := context.Background()
+	categoryID := 8
+	original := "LOJA X 02/04"
+	closingDay, dueDay := 25, 5
+
+	mockRepo.On("FetchInstallmentCandidates", ctx, fetchInstallmentCandidatesParams{OrganizationID: 1, SuffixPattern: installmentSuffixPattern.String()}).Return([]TransactionModel{
+		{TransactionID: 50, AccountID: 3, CategoryID: &categoryID, Description: "Loja X", OriginalDescription: &original,
+			TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(150),
+			TransactionDate: time.Date(2026, time.February, 27, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+	mockRepo.On("FetchAccounts", ctx, fetchAccountsParams{OrganizationID: 1}).Return([]AccountModel{
+		{AccountID: 3, UserID: 4, AccountType: AccountTypeCreditCard,
+			CardClosingDay: &closingDay, CardDueDay: &dueDay, BudgetByInvoice: true},
+	}, nil)
+
+	// Bought after the February closing: billed on the invoice due April 5,
+	// so parcel 2 is April and parcel 1 was March
+	mockRepo.On("UpsertInstallmentPlan", ctx, upsertInstallmentPlanParams{
+		OrganizationID:    1,
+		AccountID:         3,
+		CategoryID:        &categoryID,
+		Description:       "LOJA X",
+		InstallmentAmount: decimal.NewFromInt(150),
+		InstallmentCount:  4,
+		FirstMonth:        3,
+		FirstYear:         2026,
+	}).Return(InstallmentPlanModel{InstallmentPlanID: 7}, nil)
+	transactionID := 50
+	plannedEntryID := 90
+	mockRepo.On("UpsertInstallmentParcel", ctx, upsertInstallmentParcelParams{
+		InstallmentPlanID: 7, InstallmentNumber: 2, Month: 4, Year: 2026, TransactionID: &transactionID,
+	}).Return(InstallmentParcelModel{InstallmentPlanID: 7, InstallmentNumber: 2, Month: 4, Year: 2026,
+		TransactionID: &transactionID, PlannedEntryID: &plannedEntryID}, nil)
+	mockRepo.On("IsMonthClosed", ctx, mock.Anything).Return(false, nil)
+	mockRepo.On("UpsertPlannedEntryStatus", ctx, upsertPlannedEntryStatusParams{
+		PlannedEntryID: 90, Month: 4, Year: 2026, Status: PlannedEntryStatusMatched,
+	}).Return(PlannedEntryStatusModel{StatusID: 33}, nil)
+	mockRepo.On("ModifyPlannedEntryStatus", ctx, mock.MatchedBy(func(params modifyPlannedEntryStatusParams) bool {
+		return params.StatusID == 33 && *params.MatchedTransactionID == 50
+	})).Return(PlannedEntryStatusModel{}, nil)
+
+	plan := InstallmentPlanModel{
+		InstallmentPlanID:  7,
+		OrganizationID:     1,
+		AccountID:          3,
+		CategoryID:         &categoryID,
+		Description:        "LOJA X",
+		InstallmentAmount:  decimal.NewFromInt(150),
+		InstallmentCount:   4,
+		FirstMonth:         3,
+		FirstYear:          2026,
+		IsActive:           true,
+		LastImportedNumber: 2,
+	}
+	mockRepo.On("FetchInstallmentPlans", ctx, fetchInstallmentPlansParams{OrganizationID: 1, InstallmentPlanIDs: []int{7}}).
+		Return([]InstallmentPlanModel{plan}, nil)
+	otherEntryID := 91
+	mockRepo.On("FetchInstallmentParcels", ctx, fetchInstallmentParcelsParams{OrganizationID: 1, InstallmentPlanIDs: []int{7}}).
+		Return([]InstallmentParcelModel{
+			{InstallmentPlanID: 7, InstallmentNumber: 2, Month: 4, Year: 2026, TransactionID: &transactionID, PlannedEntryID: &plannedEntryID},
+			{InstallmentPlanID: 7, InstallmentNumber: 3, Month: 5, Year: 2026, PlannedEntryID: &otherEntryID},
+		}, nil)
+
+	// Only parcel 4 is left to project
+	june, year := 6, 2026
+	mockRepo.On("InsertPlannedEntry", ctx, insertPlannedEntryParams{
+		UserID:         4,
+		OrganizationID: 1,
+		CategoryID:     8,
+		Description:    "LOJA X (04/04)",
+		Amount:         decimal.NewFromInt(150),
+		EntryType:      PlannedEntryTypeExpense,
+		TargetMonth:    &june,
+		TargetYear:     &year,
+	}).Return(PlannedEntryModel{PlannedEntryID: 92}, nil).Once()
+	projectedID := 92
+	mockRepo.On("UpsertInstallmentParcel", ctx, upsertInstallmentParcelParams{
+		InstallmentPlanID: 7, InstallmentNumber: 4, Month: 6, Year: 2026, PlannedEntryID: &projectedID,
+	}).Return(InstallmentParcelModel{}, nil).Once()
+
+	result, err := svc.DetectInstallments(ctx, DetectInstallmentsInput{OrganizationID: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Projected)
+	require.Len(t, result.Plans, 1)
+	assert.Equal(t, 2, result.Plans[0].RemainingCount)
+	assert.Equal(t, "300", result.Plans[0].RemainingAmount.String())
+	assert.Equal(t, 6, result.Plans[0].LastMonth)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetOutstandingInstallments(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	mockRepo.On("FetchInstallmentPlans", ctx, fetchInstallmentPlansParams{OrganizationID: 1}).Return([]InstallmentPlanModel{
+		{InstallmentPlanID: 1, InstallmentAmount: decimal.NewFromInt(100), InstallmentCount: 3,
+			FirstMonth: 11, FirstYear: 2025, IsActive: true, LastImportedNumber: 1},
+		{InstallmentPlanID: 2, InstallmentAmount: decimal.NewFromInt(40), InstallmentCount: 2,
+			FirstMonth: 12, FirstYear: 2025, IsActive: true, LastImportedNumber: 0},
+		// Fully imported
+		{InstallmentPlanID: 3, InstallmentAmount: decimal.NewFromInt(70), InstallmentCount: 2,
+			FirstMonth: 1, FirstYear: 2025, IsActive: true, LastImportedNumber: 2},
+		// Stopped by the user
+		{InstallmentPlanID: 4, InstallmentAmount: decimal.NewFromInt(500), InstallmentCount: 10,
+			FirstMonth: 1, FirstYear: 2026, IsActive: false},
+	}, nil)
+
+	result, err := svc.GetOutstandingInstallments(ctx, GetOutstandingInstallmentsInput{OrganizationID: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, "280", result.Total.String())
+	require.Len(t, result.Plans, 2)
+	require.Len(t, result.ByMonth, 2)
+	assert.Equal(t, OutstandingInstallmentMonth{Month: 12, Year: 2025, Amount: decimal.NewFromInt(140)}, result.ByMonth[0])
+	assert.Equal(t, 1, result.ByMonth[1].Month)
+	assert.Equal(t, 2026, result.ByMonth[1].Year)
+	assert.Equal(t, "140", result.ByMonth[1].Amount.String())
+}
@@ -0,0 +1,249 @@
+package financial
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/catrutech/celeiro/internal/application/jobs"
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+)
+
+// Job types handled by the financial domain
+const (
+	JobTypeCloseMonth                  = "financial.close_month"
+	JobTypeGenerateSavingsGoalEntries  = "financial.generate_savings_goal_entries"
+	JobTypeRefreshPlannedEntryStatuses = "financial.refresh_planned_entry_statuses"
+	JobTypeApplyPatternRetroactively   = "financial.apply_pattern_retroactively"
+	JobTypeSyncPluggyAccounts          = "financial.sync_pluggy_accounts"
+	JobTypeDetectTransfers             = "financial.detect_transfers"
+	JobTypeDetectInstallments          = "financial.detect_installments"
+)
+
+// closeMonthAfterDay is the day of the month from which the previous month is
+// closed automatically, for organizations that opted in. The grace period
+// lets late bank postings and OFX imports land in the month before it is
+// locked.
+const closeMonthAfterDay = 5
+
+var errJobQueueUnavailable = stderrors.New("job queue is not available")
+
+// organizationMonthJobPayload targets one organization and month. UserID is
+// the member the work is performed as (an admin when there is one).
+type organizationMonthJobPayload struct {
+	OrganizationID int `json:"organization_id"`
+	UserID         int `json:"user_id"`
+	Month          int `json:"month"`
+	Year           int `json:"year"`
+}
+
+type applyPatternJobPayload struct {
+	PatternID      int `json:"pattern_id"`
+	OrganizationID int `json:"organization_id"`
+	UserID         int `json:"user_id"`
+}
+
+type organizationJobPayload struct {
+	OrganizationID int `json:"organization_id"`
+}
+
+// RegisterJobHandlers registers the financial job handlers and the scheduler
+// that enqueues recurring maintenance for every organization.
+func RegisterJobHandlers(runner *jobs.Runner, jobsService jobs.Service, svc Service, repo Repository) {
+	runner.Register(JobTypeCloseMonth, func(ctx context.Context, job jobs.Job) error {
+		var payload organizationMonthJobPayload
+		if err := job.DecodePayload(&payload); err != nil {
+			return err
+		}
+		_, err := svc.CloseMonth(ctx, CloseMonthInput{
+			UserID:         payload.UserID,
+			OrganizationID: payload.OrganizationID,
+			Month:          payload.Month,
+			Year:           payload.Year,
+		})
+		// Someone already closed the month by hand
+		if errors.Is(err, internalerrors.ErrMonthClosed) {
+			return nil
+		}
+		return err
+	})
+
+	runner.Register(JobTypeGenerateSavingsGoalEntries, func(ctx context.Context, job jobs.Job) error {
+		var payload organizationMonthJobPayload
+		if err := job.DecodePayload(&payload); err != nil {
+			return err
+		}
+		return svc.GenerateSavingsGoalEntries(ctx, GenerateSavingsGoalEntriesInput{
+			UserID:         payload.UserID,
+			OrganizationID: payload.OrganizationID,
+			Month:          payload.Month,
+			Year:           payload.Year,
+		})
+	})
+
+	runner.Register(JobTypeRefreshPlannedEntryStatuses, func(ctx context.Context, job jobs.Job) error {
+		var payload organizationMonthJobPayload
+		if err := job.DecodePayload(&payload); err != nil {
+			return err
+		}
+		_, err := svc.RefreshPlannedEntryStatuses(ctx, RefreshPlannedEntryStatusesInput{
+			UserID:         payload.UserID,
+			OrganizationID: payload.OrganizationID,
+			Month:          payload.Month,
+			Year:           payload.Year,
+		})
+		return err
+	})
+
+	runner.Register(JobTypeApplyPatternRetroactively, func(ctx context.Context, job jobs.Job) error {
+		var payload applyPatternJobPayload
+		if err := job.DecodePayload(&payload); err != nil {
+			return err
+		}
+		_, err := svc.ApplyPatternRetroactivelySync(ctx, ApplyPatternRetroactivelyInput{
+			PatternID:      payload.PatternID,
+			UserID:         payload.UserID,
+			OrganizationID: payload.OrganizationID,
+		})
+		return err
+	})
+
+	runner.Register(JobTypeSyncPluggyAccounts, func(ctx context.Context, job jobs.Job) error {
+		var payload organizationJobPayload
+		if err := job.DecodePayload(&payload); err != nil {
+			return err
+		}
+		links, err := svc.GetPluggyAccountLinks(ctx, GetPluggyAccountLinksInput{OrganizationID: payload.OrganizationID})
+		if err != nil {
+			return err
+		}
+		// Sync every link even if one fails; each failure is recorded on its link
+		var failed []int
+		for _, link := range links {
+			if _, err := svc.SyncPluggyAccount(ctx, SyncPluggyAccountInput{
+				PluggyAccountLinkID: link.PluggyAccountLinkID,
+				OrganizationID:      payload.OrganizationID,
+			}); err != nil {
+				failed = append(failed, link.PluggyAccountLinkID)
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("pluggy sync failed for links %v", failed)
+		}
+		return nil
+	})
+
+	runner.Register(JobTypeDetectTransfers, func(ctx context.Context, job jobs.Job) error {
+		var payload organizationJobPayload
+		if err := job.DecodePayload(&payload); err != nil {
+			return err
+		}
+		_, err := svc.DetectTransfers(ctx, DetectTransfersInput{OrganizationID: payload.OrganizationID})
+		return err
+	})
+
+	runner.Register(JobTypeDetectInstallments, func(ctx context.Context, job jobs.Job) error {
+		var payload organizationJobPayload
+		if err := job.DecodePayload(&payload); err != nil {
+			return err
+		}
+		_, err := svc.DetectInstallments(ctx, DetectInstallmentsInput{OrganizationID: payload.OrganizationID})
+		return err
+	})
+
+	runner.RegisterScheduler(func(ctx context.Context, now time.Time) error {
+		return scheduleMaintenanceJobs(ctx, jobsService, svc, repo, now)
+	})
+}
+
+// scheduleMaintenanceJobs enqueues the recurring jobs due at now. Unique keys
+// make repeated calls within the same period no-ops.
+func scheduleMaintenanceJobs(ctx context.Context, jobsService jobs.Service, svc Service, repo Repository, now time.Time) error {
+	targets, err := repo.FetchMaintenanceTargets(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch maintenance targets")
+	}
+
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	previousMonth := currentMonth.AddDate(0, -1, 0)
+	today := now.Format("2006-01-02")
+
+	for _, target := range targets {
+		organizationID := target.OrganizationID
+		enqueue := func(jobType, period string, payload any) error {
+			uniqueKey := fmt.Sprintf("%s:%d:%s", jobType, organizationID, period)
+			_, err := jobsService.EnqueueJob(ctx, jobs.EnqueueJobInput{
+				JobType:        jobType,
+				OrganizationID: &organizationID,
+				Payload:        payload,
+				UniqueKey:      &uniqueKey,
+			})
+			return err
+		}
+		monthPayload := func(month time.Time) organizationMonthJobPayload {
+			return organizationMonthJobPayload{
+				OrganizationID: organizationID,
+				UserID:         target.UserID,
+				Month:          int(month.Month()),
+				Year:           month.Year(),
+			}
+		}
+
+		// Daily: goals created or edited during the month get their entry
+		if err := enqueue(JobTypeGenerateSavingsGoalEntries, today, monthPayload(currentMonth)); err != nil {
+			return err
+		}
+
+		// Once per month, as soon as the previous month is over
+		if err := enqueue(JobTypeRefreshPlannedEntryStatuses, previousMonth.Format("2006-01"), monthPayload(previousMonth)); err != nil {
+			return err
+		}
+
+		// Daily, ahead of the month closing so its totals leave transfers out
+		if err := enqueue(JobTypeDetectTransfers, today, organizationJobPayload{OrganizationID: organizationID}); err != nil {
+			return err
+		}
+
+		// Daily: new card parcels extend the projection of their plan
+		if err := enqueue(JobTypeDetectInstallments, today, organizationJobPayload{OrganizationID: organizationID}); err != nil {
+			return err
+		}
+
+		if target.AutoCloseMonths && now.Day() >= closeMonthAfterDay {
+			if err := enqueue(JobTypeCloseMonth, previousMonth.Format("2006-01"), monthPayload(previousMonth)); err != nil {
+				return err
+			}
+		}
+
+		links, err := svc.GetPluggyAccountLinks(ctx, GetPluggyAccountLinksInput{OrganizationID: organizationID})
+		if err != nil {
+			return err
+		}
+		if len(links) > 0 {
+			if err := enqueue(JobTypeSyncPluggyAccounts, today, organizationJobPayload{OrganizationID: organizationID}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *service) enqueueApplyPatternRetroactively(ctx context.Context, patternID, userID, organizationID int) error {
+	if s.jobs == nil {
+		return errJobQueueUnavailable
+	}
+
+	_, err := s.jobs.EnqueueJob(ctx, jobs.EnqueueJobInput{
+		JobType:        JobTypeApplyPatternRetroactively,
+		OrganizationID: &organizationID,
+		Payload: applyPatternJobPayload{
+			PatternID:      patternID,
+			OrganizationID: organizationID,
+			UserID:         userID,
+		},
+	})
+	return err
+}
@@ -0,0 +1,106 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/catrutech/celeiro/internal/application/jobs"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingJobsService captures enqueued jobs; other methods are not used by
+// the scheduler.
+type recordingJobsService struct {
+	jobs.Service
+	enqueued []jobs.EnqueueJobInput
+}
+
+func (r *recordingJobsService) EnqueueJob(ctx context.Context, input jobs.EnqueueJobInput) (*jobs.Job, error) {
+	r.enqueued = append(r.enqueued, input)
+	return &jobs.Job{JobType: input.JobType}, nil
+}
+
+func TestScheduleMaintenanceJobs(t *testing.T) {
+	tests := []struct {
+		name      string
+		now       time.Time
+		autoClose bool
+		links     []PluggyAccountLinkModel
+		wantKeys  []string
+	}{
+		{
+			name: "before close day without pluggy links",
+			now:  time.Date(2026, time.March, 3, 8, 0, 0, 0, time.UTC),
+			wantKeys: []string{
+				"financial.generate_savings_goal_entries:1:2026-03-03",
+				"financial.refresh_planned_entry_statuses:1:2026-02",
+				"financial.detect_transfers:1:2026-03-03",
+				"financial.detect_installments:1:2026-03-03",
+			},
+		},
+		{
+			name: "after close day without opting in",
+			now:  time.Date(2026, time.March, 10, 8, 0, 0, 0, time.UTC),
+			wantKeys: []string{
+				"financial.generate_savings_goal_entries:1:2026-03-10",
+				"financial.refresh_planned_entry_statuses:1:2026-02",
+				"financial.detect_transfers:1:2026-03-10",
+				"financial.detect_installments:1:2026-03-10",
+			},
+		},
+		{
+			name:      "after close day with pluggy links",
+			now:       time.Date(2026, time.January, 5, 8, 0, 0, 0, time.UTC),
+			autoClose: true,
+			links:     []PluggyAccountLinkModel{{PluggyAccountLinkID: 9, OrganizationID: 1}},
+			wantKeys: []string{
+				"financial.generate_savings_goal_entries:1:2026-01-05",
+				"financial.refresh_planned_entry_statuses:1:2025-12",
+				"financial.detect_transfers:1:2026-01-05",
+				"financial.detect_installments:1:2026-01-05",
+				"financial.close_month:1:2025-12",
+				"financial.sync_pluggy_accounts:1:2026-01-05",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(MockRepository)
+			svc := &service{
+				Repository: mockRepo,
+				system:     system.NewStubSystem().ToSystem(),
+				logger:     &logging.TestLogger{},
+			}
+			queue := &recordingJobsService{}
+
+			mockRepo.On("FetchMaintenanceTargets", ctx).
+				Return([]MaintenanceTargetModel{{OrganizationID: 1, UserID: 4, AutoCloseMonths: tt.autoClose}}, nil)
+			mockRepo.On("FetchPluggyAccountLinks", ctx, mock.Anything).
+				Return(tt.links, nil)
+
+			err := scheduleMaintenanceJobs(ctx, queue, svc, mockRepo, tt.now)
+			require.NoError(t, err)
+
+			var keys []string
+			for _, input := range queue.enqueued {
+				require.NotNil(t, input.UniqueKey)
+				require.NotNil(t, input.OrganizationID)
+				assert.Equal(t, 1, *input.OrganizationID)
+				keys = append(keys, *input.UniqueKey)
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+
+			monthPayload, ok := queue.enqueued[1].Payload.(organizationMonthJobPayload)
+			require.True(t, ok)
+			assert.Equal(t, 4, monthPayload.UserID)
+			assert.Equal(t, int(tt.now.AddDate(0, -1, 0).Month()), monthPayload.Month)
+		})
+	}
+}
@@ -38,14 +38,49 @@ type AccountModel struct {
 	AccountType string `db:"account_type"` // checking, savings, credit_card, investment
 	BankName    string `db:"bank_name"`
 
-	Balance  decimal.Decimal `db:"balance"`
+	Balance  decimal.Decimal `db:"balance"` // opening_balance plus transactions, maintained by a trigger
 	Currency string          `db:"currency"`
 
+	OpeningBalance     decimal.Decimal `db:"opening_balance"`
+	OpeningBalanceDate *time.Time      `db:"opening_balance_date"` // Transactions before this date are not counted
+	ReconciledThrough  *time.Time      `db:"reconciled_through"`   // Latest statement date the computed balance matched
+
+	// Credit card billing cycle; both set or both nil, credit cards only
+	CardClosingDay  *int `db:"card_closing_day"`
+	CardDueDay      *int `db:"card_due_day"`
+	BudgetByInvoice bool `db:"budget_by_invoice"` // Purchases count in the invoice's due month
+
 	IsActive bool `db:"is_active"`
 }
 
 type AccountsModel []AccountModel
 
+// AccountStatementBalanceModel is a balance reported by the bank (or typed in by
+// the user) for the end of a day, alongside the balance computed from our
+// transactions for the same day.
+type AccountStatementBalanceModel struct {
+	AccountStatementBalanceID int       `db:"account_statement_balance_id"`
+	CreatedAt                 time.Time `db:"created_at"`
+	UpdatedAt                 time.Time `db:"updated_at"`
+
+	OrganizationID int `db:"organization_id"`
+	AccountID      int `db:"account_id"`
+
+	BalanceDate time.Time       `db:"balance_date"`
+	Balance     decimal.Decimal `db:"balance"`
+	Source      string          `db:"source"` // ofx, manual
+
+	ComputedBalance decimal.Decimal `db:"computed_balance"`
+}
+
+type AccountStatementBalancesModel []AccountStatementBalanceModel
+
+// Statement balance sources
+const (
+	StatementBalanceSourceOFX    = "ofx"
+	StatementBalanceSourceManual = "manual"
+)
+
 // Transaction represents a financial transaction
 type TransactionModel struct {
 	TransactionID int       `db:"transaction_id"`
@@ -70,6 +105,7 @@ type TransactionModel struct {
 	// Classification
 	IsClassified         bool `db:"is_classified"`
 	ClassificationRuleID *int `db:"classification_rule_id"`
+	PatternID            *int `db:"pattern_id"` // Pattern that last categorized or ignored it
 
 	// Status
 	IsIgnored   bool `db:"is_ignored"`
@@ -81,18 +117,199 @@ type TransactionModel struct {
 	// Metadata
 	Notes *string        `db:"notes"`
 	Tags  pq.StringArray `db:"tags"`
+
+	// Joined from the account: its currency, and the amount in the
+	// organization's base currency at the transaction date's rate (nil when
+	// no rate is known). Not returned by inserts.
+	Currency        string           `db:"currency"`
+	ConvertedAmount *decimal.Decimal `db:"converted_amount"`
 }
 
 type TransactionsModel []TransactionModel
 
+// TransactionSplitModel is one allocation of a split transaction. The splits of
+// a transaction replace it in spending aggregates.
+type TransactionSplitModel struct {
+	SplitID   int       `db:"split_id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+
+	TransactionID int  `db:"transaction_id"`
+	CategoryID    *int `db:"category_id"`
+	SavingsGoalID *int `db:"savings_goal_id"`
+
+	Amount decimal.Decimal `db:"amount"` // Absolute, like the parent's
+	Notes  *string         `db:"notes"`
+	TagIDs pq.Int64Array   `db:"tag_ids"`
+}
+
+// Transfer detection sources
+const (
+	TransferDetectionAuto   = "auto"
+	TransferDetectionManual = "manual"
+)
+
+// TransferModel links the debit and credit legs of money moved between two of
+// the organization's accounts. The leg columns are joined from transactions.
+type TransferModel struct {
+	TransferID     int       `db:"transfer_id"`
+	CreatedAt      time.Time `db:"created_at"`
+	OrganizationID int       `db:"organization_id"`
+	Detection      string    `db:"detection"`
+
+	DebitTransactionID int             `db:"debit_transaction_id"`
+	DebitAccountID     int             `db:"debit_account_id"`
+	DebitDescription   string          `db:"debit_description"`
+	DebitDate          time.Time       `db:"debit_date"`
+	DebitAmount        decimal.Decimal `db:"debit_amount"`
+
+	CreditTransactionID int             `db:"credit_transaction_id"`
+	CreditAccountID     int             `db:"credit_account_id"`
+	CreditDescription   string          `db:"credit_description"`
+	CreditDate          time.Time       `db:"credit_date"`
+	CreditAmount        decimal.Decimal `db:"credit_amount"`
+}
+
+// CreditCardInvoiceModel records the payment of one credit card invoice,
+// identified by the month it is due. The billing cycle itself is derived from
+// the account's closing and due days.
+type CreditCardInvoiceModel struct {
+	CreditCardInvoiceID int       `db:"credit_card_invoice_id"`
+	CreatedAt           time.Time `db:"created_at"`
+	UpdatedAt           time.Time `db:"updated_at"`
+
+	OrganizationID int `db:"organization_id"`
+	AccountID      int `db:"account_id"`
+	Month          int `db:"month"`
+	Year           int `db:"year"`
+
+	PaymentTransactionID *int             `db:"payment_transaction_id"`
+	PaymentAmount        *decimal.Decimal `db:"payment_amount"` // Joined from the payment transaction
+	PaymentDate          *time.Time       `db:"payment_date"`
+}
+
+// InstallmentPlanModel is an installment purchase on a card. LastImportedNumber
+// is the highest parcel number with an imported transaction (0 for none).
+type InstallmentPlanModel struct {
+	InstallmentPlanID int       `db:"installment_plan_id"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
+
+	OrganizationID int  `db:"organization_id"`
+	AccountID      int  `db:"account_id"`
+	CategoryID     *int `db:"category_id"`
+
+	Description       string          `db:"description"`
+	InstallmentAmount decimal.Decimal `db:"installment_amount"`
+	InstallmentCount  int             `db:"installment_count"`
+	FirstMonth        int             `db:"first_month"`
+	FirstYear         int             `db:"first_year"`
+
+	IsActive bool `db:"is_active"`
+
+	LastImportedNumber int `db:"last_imported_number"`
+}
+
+// InstallmentParcelModel is one parcel of a plan, either imported
+// (TransactionID) or projected as a planned entry (PlannedEntryID).
+type InstallmentParcelModel struct {
+	InstallmentPlanID int  `db:"installment_plan_id"`
+	InstallmentNumber int  `db:"installment_number"`
+	Month             int  `db:"month"`
+	Year              int  `db:"year"`
+	TransactionID     *int `db:"transaction_id"`
+	PlannedEntryID    *int `db:"planned_entry_id"`
+}
+
+// ExchangeRateModel is what one unit of Currency was worth in BaseCurrency on
+// RateDate, as kept by the organization.
+type ExchangeRateModel struct {
+	OrganizationID int       `db:"organization_id"`
+	Currency       string    `db:"currency"`
+	BaseCurrency   string    `db:"base_currency"`
+	RateDate       time.Time `db:"rate_date"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+
+	Rate   decimal.Decimal `db:"rate"`
+	Source string          `db:"source"` // upload, cli
+}
+
+// Exchange rate sources
+const (
+	ExchangeRateSourceUpload = "upload"
+	ExchangeRateSourceCLI    = "cli"
+)
+
+// ReportCategoryMonthModel is what was spent in a category (nil when
+// uncategorized) in a budget month, in the base currency.
+type ReportCategoryMonthModel struct {
+	CategoryID *int            `db:"category_id"`
+	Month      int             `db:"month"`
+	Year       int             `db:"year"`
+	Amount     decimal.Decimal `db:"amount"`
+
+	MissingRates int `db:"missing_rates"` // Transactions left out of Amount for lack of an exchange rate
+}
+
+// ReportIncomeExpenseModel is what came in and went out in a budget month
+type ReportIncomeExpenseModel struct {
+	Month   int             `db:"month"`
+	Year    int             `db:"year"`
+	Income  decimal.Decimal `db:"income"`
+	Expense decimal.Decimal `db:"expense"`
+
+	MissingRates int `db:"missing_rates"` // Transactions left out for lack of an exchange rate
+}
+
+// ReportMerchantModel is the spending under one transaction description
+type ReportMerchantModel struct {
+	Merchant            string          `db:"merchant"`
+	Total               decimal.Decimal `db:"total"`
+	TransactionCount    int             `db:"transaction_count"`
+	LastTransactionDate time.Time       `db:"last_transaction_date"`
+	MissingRates        int             `db:"missing_rates"` // Transactions left out of Total for lack of an exchange rate
+}
+
+// ReportTransactionModel is a transaction, or one split of it, behind a
+// report figure
+type ReportTransactionModel struct {
+	TransactionID   int              `db:"transaction_id"`
+	SplitID         *int             `db:"split_id"`
+	AccountID       int              `db:"account_id"`
+	TransactionDate time.Time        `db:"transaction_date"`
+	Month           int              `db:"month"` // Budget month
+	Year            int              `db:"year"`
+	Description     string           `db:"description"`
+	CategoryID      *int             `db:"category_id"`
+	TransactionType string           `db:"transaction_type"`
+	Amount          *decimal.Decimal `db:"amount"` // Base currency; nil without an exchange rate
+}
+
+// AuditLogModel is one write to the organization's financial data
+type AuditLogModel struct {
+	AuditLogID int       `db:"audit_log_id"`
+	CreatedAt  time.Time `db:"created_at"`
+
+	OrganizationID int     `db:"organization_id"`
+	UserID         *int    `db:"user_id"`   // NULL for scheduled jobs or deleted users
+	UserName       *string `db:"user_name"` // Joined from users
+
+	EntityType string `db:"entity_type"`
+	EntityID   int    `db:"entity_id"`
+	Action     string `db:"action"`
+	Changes    []byte `db:"changes"` // JSON object of {"before", "after"} per changed field
+}
+
 // ClassificationRule represents an automatic transaction classification rule
 type ClassificationRuleModel struct {
 	RuleID    int       `db:"rule_id"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 
-	UserID     int `db:"user_id"`
-	CategoryID int `db:"category_id"`
+	UserID         int `db:"user_id"` // Creator; rules apply to the whole organization
+	OrganizationID int `db:"organization_id"`
+	CategoryID     int `db:"category_id"`
 
 	Name     string `db:"name"`
 	Priority int    `db:"priority"`
@@ -175,6 +392,14 @@ type PlannedEntryModel struct {
 	IsRecurrent   bool `db:"is_recurrent"`
 	ParentEntryID *int `db:"parent_entry_id"`
 
+	// Recurrence rule of recurrent entries (see plannedEntryOccurrences)
+	RecurrenceFrequency string        `db:"recurrence_frequency"`
+	RecurrenceInterval  int           `db:"recurrence_interval"` // Every N weeks/months/years
+	RecurrenceMonths    pq.Int64Array `db:"recurrence_months"`   // Only these months; empty means any
+	StartsOn            *time.Time    `db:"starts_on"`           // Defaults to CreatedAt
+	EndsOn              *time.Time    `db:"ends_on"`
+	OccurrenceCount     *int          `db:"occurrence_count"` // Stops after this many occurrences
+
 	// Target budget month/year (used by savings goal auto-generated entries)
 	TargetMonth *int `db:"target_month"`
 	TargetYear  *int `db:"target_year"`
@@ -199,6 +424,13 @@ const (
 	PlannedEntryTypeIncome  = "income"
 )
 
+// Recurrence frequencies of recurrent planned entries
+const (
+	RecurrenceFrequencyWeekly  = "weekly"
+	RecurrenceFrequencyMonthly = "monthly"
+	RecurrenceFrequencyYearly  = "yearly"
+)
+
 // PlannedEntryStatusModel tracks the monthly status of a planned entry
 type PlannedEntryStatusModel struct {
 	StatusID  int       `db:"status_id"`
@@ -244,6 +476,23 @@ type MonthlySnapshotModel struct {
 
 type MonthlySnapshotsModel []MonthlySnapshotModel
 
+// MonthReopeningModel records a closed month being reopened
+type MonthReopeningModel struct {
+	MonthReopeningID int       `db:"month_reopening_id"`
+	CreatedAt        time.Time `db:"created_at"`
+
+	OrganizationID int `db:"organization_id"`
+	UserID         int `db:"user_id"` // Who reopened
+
+	Month  int    `db:"month"`
+	Year   int    `db:"year"`
+	Reason string `db:"reason"`
+
+	ClosedAt        time.Time        `db:"closed_at"`
+	CarryoverAmount *decimal.Decimal `db:"carryover_amount"` // Signed; NULL when the month had no carry-over
+	SnapshotCount   int              `db:"snapshot_count"`
+}
+
 type PatternAction string
 
 const (
@@ -275,6 +524,7 @@ type PatternModel struct {
 	// Behavior
 	Action             PatternAction `db:"action"`
 	ApplyRetroactively bool          `db:"apply_retroactively"`
+	Priority           int           `db:"priority"` // Lower = evaluated first; first match wins
 	IsActive           bool          `db:"is_active"`
 }
 
@@ -353,6 +603,7 @@ type TagSpendingModel struct {
 	Color            string          `db:"color"`
 	Total            decimal.Decimal `db:"total"`
 	TransactionCount int             `db:"transaction_count"`
+	MissingRates     int             `db:"missing_rates"`
 }
 
 // TagPlannedModel is the planned expense budget for a tag in a given month
@@ -402,6 +653,67 @@ type GoalMonthlyContributionModel struct {
 	Amount decimal.Decimal `db:"amount"`
 }
 
+// PluggyAccountLinkModel links a Pluggy account to a Celeiro account and tracks
+// its incremental sync state
+type PluggyAccountLinkModel struct {
+	PluggyAccountLinkID int       `db:"pluggy_account_link_id"`
+	CreatedAt           time.Time `db:"created_at"`
+	UpdatedAt           time.Time `db:"updated_at"`
+
+	UserID         int `db:"user_id"`
+	OrganizationID int `db:"organization_id"`
+	AccountID      int `db:"account_id"`
+
+	PluggyItemID    string `db:"pluggy_item_id"`
+	PluggyAccountID string `db:"pluggy_account_id"`
+
+	SyncCursor        *time.Time `db:"sync_cursor"` // Last transaction date fully synced
+	LastSyncedAt      *time.Time `db:"last_synced_at"`
+	LastSyncStatus    string     `db:"last_sync_status"` // never, success, error
+	LastSyncError     *string    `db:"last_sync_error"`
+	LastImportedCount int        `db:"last_imported_count"`
+}
+
+type PluggyAccountLinksModel []PluggyAccountLinkModel
+
+// CSVImportProfileModel maps the columns of a bank's CSV statement onto
+// transactions for one account
+type CSVImportProfileModel struct {
+	CSVImportProfileID int       `db:"csv_import_profile_id"`
+	CreatedAt          time.Time `db:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+
+	UserID         int `db:"user_id"`
+	OrganizationID int `db:"organization_id"`
+	AccountID      int `db:"account_id"`
+
+	Name string `db:"name"`
+
+	Delimiter string `db:"delimiter"`
+	SkipRows  int    `db:"skip_rows"` // Lines before the header row
+
+	DateColumn         string         `db:"date_column"`
+	DateFormat         string         `db:"date_format"` // e.g. DD/MM/YYYY, YYYY-MM-DD
+	DescriptionColumns pq.StringArray `db:"description_columns"`
+	IDColumn           *string        `db:"id_column"`
+
+	AmountColumn     *string `db:"amount_column"`
+	DebitColumn      *string `db:"debit_column"`
+	CreditColumn     *string `db:"credit_column"`
+	DecimalSeparator string  `db:"decimal_separator"`
+	SignConvention   string  `db:"sign_convention"` // negative_debit, positive_debit
+}
+
+type CSVImportProfilesModel []CSVImportProfileModel
+
+// MaintenanceTargetModel is an organization that scheduled jobs run for, and
+// the member they run as.
+type MaintenanceTargetModel struct {
+	OrganizationID  int  `db:"organization_id"`
+	UserID          int  `db:"user_id"`
+	AutoCloseMonths bool `db:"auto_close_months"`
+}
+
 // =============================================================================
 // Amazon Sync Types
 // =============================================================================
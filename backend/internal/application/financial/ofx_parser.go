@@ -1,24 +1,94 @@
 package financial
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
+	"html"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/shopspring/decimal"
+	"golang.org/x/text/encoding/charmap"
 )
 
 type OFXTransaction struct {
-	Type        string          // DEBIT or CREDIT
-	DatePosted  time.Time       // Transaction date
-	Amount      decimal.Decimal // Transaction amount
-	FITID       string          // Financial Institution Transaction ID
-	Name        string          // Payee/Description
-	Memo        string          // Additional memo
+	Type       string          // DEBIT or CREDIT
+	DatePosted time.Time       // Transaction date, in the offset declared by the file (UTC if none)
+	Amount     decimal.Decimal // Transaction amount
+	FITID      string          // Financial Institution Transaction ID
+	Name       string          // Payee/Description
+	Memo       string          // Additional memo
+	CheckNum   string          // Check number, when present
+	Currency   string          // ISO 4217 code; CURSYM of <CURRENCY> or the statement's CURDEF
+}
+
+// OFXAccount identifies the account a statement belongs to. Credit card
+// statements (<CCACCTFROM>) only carry AccountID.
+type OFXAccount struct {
+	BankID       string
+	BranchID     string
+	AccountID    string
+	AccountType  string // CHECKING, SAVINGS, MONEYMRKT, CREDITLINE, CD or CREDITCARD
+	IsCreditCard bool
+}
+
+type OFXBalance struct {
+	Amount decimal.Decimal
+	AsOf   time.Time
+}
+
+// OFXStatement is one <STMTRS> (bank) or <CCSTMTRS> (credit card) response.
+// Transactions that appear outside any statement aggregate, as in some
+// hand-trimmed exports, are collected into a statement with an empty Account.
+type OFXStatement struct {
+	Account          OFXAccount
+	Currency         string
+	StartDate        time.Time
+	EndDate          time.Time
+	LedgerBalance    *OFXBalance
+	AvailableBalance *OFXBalance
+	Transactions     []OFXTransaction `json:"-"`
+}
+
+// OFXTransactionError describes a <STMTTRN> block that could not be parsed.
+// Index is the position of the block within its statement.
+type OFXTransactionError struct {
+	Index int
+	FITID string
+	Err   error
+}
+
+func (e OFXTransactionError) Error() string {
+	if e.FITID != "" {
+		return fmt.Sprintf("transaction %d (FITID %s): %v", e.Index, e.FITID, e.Err)
+	}
+	return fmt.Sprintf("transaction %d: %v", e.Index, e.Err)
+}
+
+// OFXDocument is the result of parsing an OFX 1.x (SGML) or 2.x (XML) file.
+type OFXDocument struct {
+	Version    string
+	Charset    string
+	Statements []OFXStatement
+	Errors     []OFXTransactionError
+	// Investment statements (<INVSTMTRS>) are recognised but not imported
+	SkippedInvestmentStatements int
+}
+
+// Transactions returns the valid transactions of every statement, in file order.
+func (d *OFXDocument) Transactions() []OFXTransaction {
+	count := 0
+	for _, st := range d.Statements {
+		count += len(st.Transactions)
+	}
+	transactions := make([]OFXTransaction, 0, count)
+	for _, st := range d.Statements {
+		transactions = append(transactions, st.Transactions...)
+	}
+	return transactions
 }
 
 type OFXParser struct{}
@@ -27,79 +97,176 @@ func NewOFXParser() *OFXParser {
 	return &OFXParser{}
 }
 
-// ParseOFX parses an OFX file and extracts transactions
+// ParseOFX parses an OFX file and returns the valid transactions of all its
+// statements. Use Parse to also get balances, account identity and the
+// per-transaction errors.
 func (p *OFXParser) ParseOFX(data []byte) ([]OFXTransaction, error) {
-	// Convert to string for easier parsing
-	content := string(data)
+	doc, err := p.Parse(data)
+	if err != nil {
+		return nil, err
+	}
 
-	// Find the <STMTTRN> blocks (statement transactions)
-	transactions := []OFXTransaction{}
+	transactions := doc.Transactions()
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("no transactions found in OFX file")
+	}
 
-	// Use regex to find all transaction blocks ((?s) enables multiline mode)
-	re := regexp.MustCompile(`(?s)<STMTTRN>(.*?)</STMTTRN>`)
-	matches := re.FindAllStringSubmatch(content, -1)
+	return transactions, nil
+}
 
-	for _, match := range matches {
-		if len(match) < 2 {
-			continue
-		}
+// Parse tokenizes an OFX file and extracts its statements. Malformed
+// <STMTTRN> blocks do not fail the parse; they are reported in
+// OFXDocument.Errors. An error is returned only when the file holds no bank
+// or credit card statement at all.
+func (p *OFXParser) Parse(data []byte) (*OFXDocument, error) {
+	header, body := splitOFXHeader(data)
+	doc := &OFXDocument{
+		Version: header.version,
+		Charset: header.charset,
+	}
 
-		txBlock := match[1]
-		tx, err := p.parseTransaction(txBlock)
-		if err != nil {
-			// Skip malformed transactions
-			continue
+	root := parseOFXElements(decodeStatementText(body, header.charset), header.xml)
+
+	var orphans *OFXStatement
+	var walk func(node *ofxNode)
+	walk = func(node *ofxNode) {
+		for _, child := range node.children {
+			switch child.name {
+			case "STMTRS", "CCSTMTRS":
+				doc.Statements = append(doc.Statements, p.parseStatement(child, doc))
+			case "INVSTMTRS":
+				doc.SkippedInvestmentStatements++
+			case "STMTTRN":
+				if orphans == nil {
+					orphans = &OFXStatement{}
+				}
+				p.appendTransaction(orphans, child, len(orphans.Transactions), doc)
+			default:
+				walk(child)
+			}
 		}
+	}
+	walk(root)
 
-		transactions = append(transactions, tx)
+	if orphans != nil {
+		doc.Statements = append(doc.Statements, *orphans)
 	}
 
-	if len(transactions) == 0 {
+	if len(doc.Statements) == 0 {
+		if doc.SkippedInvestmentStatements > 0 {
+			return nil, fmt.Errorf("investment statements are not supported")
+		}
 		return nil, fmt.Errorf("no transactions found in OFX file")
 	}
 
-	return transactions, nil
+	return doc, nil
 }
 
-func (p *OFXParser) parseTransaction(block string) (OFXTransaction, error) {
-	tx := OFXTransaction{}
-
-	scanner := bufio.NewScanner(bytes.NewReader([]byte(block)))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Parse different OFX tags
-		if strings.HasPrefix(line, "<TRNTYPE>") {
-			tx.Type = p.extractValue(line, "TRNTYPE")
-		} else if strings.HasPrefix(line, "<DTPOSTED>") {
-			dateStr := p.extractValue(line, "DTPOSTED")
-			if date, err := p.parseOFXDate(dateStr); err == nil {
-				tx.DatePosted = date
-			}
-		} else if strings.HasPrefix(line, "<TRNAMT>") {
-			amountStr := p.extractValue(line, "TRNAMT")
-			if amount, err := decimal.NewFromString(amountStr); err == nil {
-				tx.Amount = amount
-			}
-		} else if strings.HasPrefix(line, "<FITID>") {
-			tx.FITID = p.extractValue(line, "FITID")
-		} else if strings.HasPrefix(line, "<NAME>") {
-			tx.Name = p.extractValue(line, "NAME")
-		} else if strings.HasPrefix(line, "<MEMO>") {
-			tx.Memo = p.extractValue(line, "MEMO")
+func (p *OFXParser) parseStatement(node *ofxNode, doc *OFXDocument) OFXStatement {
+	st := OFXStatement{
+		Currency: strings.ToUpper(node.childValue("CURDEF")),
+	}
+
+	if acct := node.child("BANKACCTFROM"); acct != nil {
+		st.Account = OFXAccount{
+			BankID:      acct.childValue("BANKID"),
+			BranchID:    acct.childValue("BRANCHID"),
+			AccountID:   acct.childValue("ACCTID"),
+			AccountType: strings.ToUpper(acct.childValue("ACCTTYPE")),
+		}
+	} else if acct := node.child("CCACCTFROM"); acct != nil {
+		st.Account = OFXAccount{
+			AccountID:    acct.childValue("ACCTID"),
+			AccountType:  "CREDITCARD",
+			IsCreditCard: true,
 		}
 	}
 
+	st.LedgerBalance = p.parseBalance(node.child("LEDGERBAL"))
+	st.AvailableBalance = p.parseBalance(node.child("AVAILBAL"))
+
+	if list := node.child("BANKTRANLIST"); list != nil {
+		st.StartDate, _ = p.parseOFXDate(list.childValue("DTSTART"))
+		st.EndDate, _ = p.parseOFXDate(list.childValue("DTEND"))
+	}
+
+	index := 0
+	node.walk("STMTTRN", func(trn *ofxNode) {
+		p.appendTransaction(&st, trn, index, doc)
+		index++
+	})
+
+	return st
+}
+
+func (p *OFXParser) appendTransaction(st *OFXStatement, node *ofxNode, index int, doc *OFXDocument) {
+	tx, err := p.transactionFromNode(node, st.Currency)
+	if err != nil {
+		doc.Errors = append(doc.Errors, OFXTransactionError{Index: index, FITID: tx.FITID, Err: err})
+		return
+	}
+	st.Transactions = append(st.Transactions, tx)
+}
+
+func (p *OFXParser) parseBalance(node *ofxNode) *OFXBalance {
+	if node == nil {
+		return nil
+	}
+	amount, err := parseOFXAmount(node.childValue("BALAMT"))
+	if err != nil {
+		return nil
+	}
+	asOf, _ := p.parseOFXDate(node.childValue("DTASOF"))
+	return &OFXBalance{Amount: amount, AsOf: asOf}
+}
+
+// parseTransaction parses the contents of a single <STMTTRN> block.
+func (p *OFXParser) parseTransaction(block string) (OFXTransaction, error) {
+	node := parseOFXElements(block, false)
+	node.name = "STMTTRN"
+	return p.transactionFromNode(node, "")
+}
+
+func (p *OFXParser) transactionFromNode(node *ofxNode, currency string) (OFXTransaction, error) {
+	tx := OFXTransaction{
+		Type:     node.childValue("TRNTYPE"),
+		FITID:    node.childValue("FITID"),
+		Name:     node.childValue("NAME"),
+		Memo:     node.childValue("MEMO"),
+		CheckNum: node.childValue("CHECKNUM"),
+		Currency: currency,
+	}
+	if cur := node.child("CURRENCY"); cur != nil && cur.childValue("CURSYM") != "" {
+		tx.Currency = strings.ToUpper(cur.childValue("CURSYM"))
+	}
+
 	// Validate required fields
 	if tx.FITID == "" {
 		return tx, fmt.Errorf("missing FITID")
 	}
-	if tx.DatePosted.IsZero() {
+
+	dateStr := node.childValue("DTPOSTED")
+	if dateStr == "" {
 		return tx, fmt.Errorf("missing DTPOSTED")
 	}
-	if tx.Amount.IsZero() {
+	date, err := p.parseOFXDate(dateStr)
+	if err != nil {
+		return tx, fmt.Errorf("invalid DTPOSTED: %w", err)
+	}
+	tx.DatePosted = date
+
+	amountStr := node.childValue("TRNAMT")
+	if amountStr == "" {
 		return tx, fmt.Errorf("missing TRNAMT")
 	}
+	amount, err := parseOFXAmount(amountStr)
+	if err != nil {
+		return tx, fmt.Errorf("invalid TRNAMT %q", amountStr)
+	}
+	if amount.IsZero() {
+		return tx, fmt.Errorf("zero TRNAMT")
+	}
+	tx.Amount = amount
 
 	// Normalize transaction type
 	tx.Type = p.normalizeType(tx.Type, tx.Amount)
@@ -107,44 +274,55 @@ func (p *OFXParser) parseTransaction(block string) (OFXTransaction, error) {
 	return tx, nil
 }
 
-func (p *OFXParser) extractValue(line, tag string) string {
-	// Handle format: <TAG>value or <TAG>value</TAG>
-	line = strings.TrimSpace(line)
-
-	// Remove opening tag
-	line = strings.TrimPrefix(line, "<"+tag+">")
-
-	// Remove closing tag if present
-	line = strings.TrimSuffix(line, "</"+tag+">")
-
-	return strings.TrimSpace(line)
+// parseOFXAmount accepts both "1234.56" and the "1234,56" some Brazilian banks
+// emit despite the spec.
+func parseOFXAmount(s string) (decimal.Decimal, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, ",") && !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	return decimal.NewFromString(s)
 }
 
-func (p *OFXParser) parseOFXDate(dateStr string) (time.Time, error) {
-	// OFX date format: YYYYMMDD[HHMMSS][.XXX][TZ]
-	// Examples: 20231015, 20231015120000, 20231015120000.000[-3:EST]
+var ofxDateRe = regexp.MustCompile(`^(\d{4})(\d{2})(\d{2})(?:(\d{2})(\d{2})(?:(\d{2})(?:\.\d+)?)?)?\s*(?:\[\s*([+-]?\d+(?:\.\d+)?)?\s*(?::\s*([A-Za-z]*))?\s*\])?$`)
 
-	// Extract just the date part (first 8 characters)
+func (p *OFXParser) parseOFXDate(dateStr string) (time.Time, error) {
+	// OFX date format: YYYYMMDD[HHMM[SS[.XXX]]][[offset[:TZ]]]
+	// Examples: 20231015, 20231015120000, 20231015120000.000[-3:BRT]
+	dateStr = strings.TrimSpace(dateStr)
 	if len(dateStr) < 8 {
 		return time.Time{}, fmt.Errorf("invalid date length: %s", dateStr)
 	}
 
-	datePart := dateStr[:8]
+	m := ofxDateRe.FindStringSubmatch(dateStr)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("invalid date: %s", dateStr)
+	}
 
-	// Parse as YYYYMMDD
-	year, _ := strconv.Atoi(datePart[0:4])
-	month, _ := strconv.Atoi(datePart[4:6])
-	day, _ := strconv.Atoi(datePart[6:8])
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	year, month, day := atoi(m[1]), atoi(m[2]), atoi(m[3])
+	hour, minute, second := atoi(m[4]), atoi(m[5]), atoi(m[6])
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 || second > 60 {
+		return time.Time{}, fmt.Errorf("invalid date: %s", dateStr)
+	}
 
-	// If time component exists, parse it
-	hour, minute, second := 0, 0, 0
-	if len(dateStr) >= 14 {
-		hour, _ = strconv.Atoi(dateStr[8:10])
-		minute, _ = strconv.Atoi(dateStr[10:12])
-		second, _ = strconv.Atoi(dateStr[12:14])
+	loc := time.UTC
+	if m[7] != "" {
+		offsetHours, err := strconv.ParseFloat(m[7], 64)
+		if err != nil || offsetHours < -14 || offsetHours > 14 {
+			return time.Time{}, fmt.Errorf("invalid timezone offset: %s", dateStr)
+		}
+		loc = time.FixedZone(m[8], int(offsetHours*3600))
 	}
 
-	return time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC), nil
+	date := time.Date(year, time.Month(month), day, hour, minute, second, 0, loc)
+	if date.Day() != day {
+		return time.Time{}, fmt.Errorf("invalid date: %s", dateStr)
+	}
+	return date, nil
 }
 
 func (p *OFXParser) normalizeType(trnType string, amount decimal.Decimal) string {
@@ -165,6 +343,227 @@ func (p *OFXParser) normalizeType(trnType string, amount decimal.Decimal) string
 	}
 }
 
+// ============================================================================
+// Tokenizer
+// ============================================================================
+
+type ofxHeader struct {
+	version string
+	charset string
+	xml     bool
+}
+
+// splitOFXHeader separates the header from the <OFX> body. OFX 1.x uses
+// "KEY:VALUE" lines (OFXHEADER, VERSION, ENCODING, CHARSET); OFX 2.x uses an
+// XML declaration plus an <?OFX ...?> processing instruction.
+func splitOFXHeader(data []byte) (ofxHeader, []byte) {
+	var header ofxHeader
+
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return header, data
+	}
+
+	raw := string(data[:start])
+	if pi := strings.Index(strings.ToUpper(raw), "<?OFX"); pi >= 0 {
+		header.xml = true
+		header.version = xmlAttr(raw[pi:], "VERSION")
+		header.charset = xmlAttr(raw[:pi], "encoding")
+	} else if strings.HasPrefix(strings.TrimSpace(raw), "<?xml") {
+		header.xml = true
+		header.charset = xmlAttr(raw, "encoding")
+	} else {
+		var encoding string
+		for _, line := range strings.Split(raw, "\n") {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+			if !ok {
+				continue
+			}
+			switch strings.ToUpper(strings.TrimSpace(key)) {
+			case "VERSION":
+				header.version = strings.TrimSpace(value)
+			case "ENCODING":
+				encoding = strings.TrimSpace(value)
+			case "CHARSET":
+				header.charset = strings.TrimSpace(value)
+			}
+		}
+		if strings.EqualFold(encoding, "UTF-8") {
+			header.charset = "UTF-8"
+		}
+	}
+
+	return header, data[start:]
+}
+
+var xmlAttrRe = regexp.MustCompile(`\b([A-Za-z][\w.:-]*)\s*=\s*["']([^"']*)["']`)
+
+// xmlAttr returns the value of the first attribute called name, ignoring case
+func xmlAttr(s, name string) string {
+	for _, m := range xmlAttrRe.FindAllStringSubmatch(s, -1) {
+		if strings.EqualFold(m[1], name) {
+			return m[2]
+		}
+	}
+	return ""
+}
+
+// decodeStatementText converts OFX and CSV statements to UTF-8. Brazilian
+// banks commonly send latin1/Windows-1252 content, sometimes while declaring
+// USASCII or UTF-8, so the declared charset is only trusted when the bytes are
+// not valid UTF-8.
+func decodeStatementText(data []byte, charset string) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	decoder := charmap.Windows1252.NewDecoder()
+	switch strings.ToUpper(strings.ReplaceAll(charset, "-", "")) {
+	case "ISO88591", "88591", "LATIN1":
+		decoder = charmap.ISO8859_1.NewDecoder()
+	}
+
+	decoded, err := decoder.Bytes(data)
+	if err != nil {
+		return strings.ToValidUTF8(string(data), string(utf8.RuneError))
+	}
+	return string(decoded)
+}
+
+type ofxNode struct {
+	name     string
+	value    string
+	children []*ofxNode
+}
+
+func (n *ofxNode) child(name string) *ofxNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *ofxNode) childValue(name string) string {
+	if c := n.child(name); c != nil {
+		return c.value
+	}
+	return ""
+}
+
+// walk calls fn for every descendant named name, without descending into it.
+func (n *ofxNode) walk(name string, fn func(*ofxNode)) {
+	for _, c := range n.children {
+		if c.name == name {
+			fn(c)
+			continue
+		}
+		c.walk(name, fn)
+	}
+}
+
+// ofxAggregates lists the OFX aggregates the parser cares about. In SGML a
+// leaf sent without a value (e.g. an empty <MEMO>) looks like an aggregate
+// start tag; elements not listed here are closed as empty leaves instead of
+// swallowing the siblings that follow them.
+var ofxAggregates = map[string]bool{
+	"OFX": true, "SIGNONMSGSRSV1": true, "SONRS": true, "STATUS": true, "FI": true,
+	"BANKMSGSRSV1": true, "STMTTRNRS": true, "STMTRS": true, "BANKACCTFROM": true,
+	"CREDITCARDMSGSRSV1": true, "CCSTMTTRNRS": true, "CCSTMTRS": true, "CCACCTFROM": true,
+	"INVSTMTMSGSRSV1": true, "INVSTMTTRNRS": true, "INVSTMTRS": true,
+	"BANKTRANLIST": true, "STMTTRN": true, "PAYEE": true, "BANKACCTTO": true, "CCACCTTO": true,
+	"CURRENCY": true, "ORIGCURRENCY": true, "LEDGERBAL": true, "AVAILBAL": true,
+	"BALLIST": true, "BAL": true,
+}
+
+// parseOFXElements builds an element tree from OFX markup. It accepts both
+// SGML, where leaf elements have no closing tag, and XML. A start tag followed
+// by text is a leaf; an end tag closes the nearest open element with that name
+// and everything opened after it, and end tags with no open match (closing
+// tags of leaves, stray tags) are ignored. Truncated input yields the
+// elements read so far.
+func parseOFXElements(s string, xml bool) *ofxNode {
+	root := &ofxNode{}
+	stack := []*ofxNode{root}
+
+	i := 0
+	for i < len(s) {
+		lt := strings.IndexByte(s[i:], '<')
+		if lt < 0 {
+			lt = len(s) - i
+		}
+		if text := strings.TrimSpace(s[i : i+lt]); text != "" {
+			top := stack[len(stack)-1]
+			if top != root && len(top.children) == 0 {
+				top.value = html.UnescapeString(text)
+				stack = stack[:len(stack)-1]
+			}
+		}
+		i += lt
+		if i >= len(s) {
+			break
+		}
+
+		rest := s[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				return root
+			}
+			i += end + len("-->")
+			continue
+		case strings.HasPrefix(rest, "<?"), strings.HasPrefix(rest, "<!"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return root
+			}
+			i += end + 1
+			continue
+		}
+
+		gt := strings.IndexByte(rest, '>')
+		if gt < 0 {
+			return root
+		}
+		tag := strings.TrimSpace(rest[1:gt])
+		i += gt + 1
+
+		switch {
+		case strings.HasPrefix(tag, "/"):
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			for j := len(stack) - 1; j > 0; j-- {
+				if stack[j].name == name {
+					stack = stack[:j]
+					break
+				}
+			}
+		case strings.HasSuffix(tag, "/"):
+			top := stack[len(stack)-1]
+			top.children = append(top.children, &ofxNode{name: ofxTagName(tag[:len(tag)-1])})
+		case tag != "":
+			node := &ofxNode{name: ofxTagName(tag)}
+			top := stack[len(stack)-1]
+			if !xml && top != root && len(top.children) == 0 && !ofxAggregates[top.name] {
+				stack = stack[:len(stack)-1]
+				top = stack[len(stack)-1]
+			}
+			top.children = append(top.children, node)
+			stack = append(stack, node)
+		}
+	}
+
+	return root
+}
+
+func ofxTagName(tag string) string {
+	if sp := strings.IndexAny(tag, " \t\r\n"); sp >= 0 {
+		tag = tag[:sp]
+	}
+	return strings.ToUpper(tag)
+}
+
 // cleanDescription removes special characters and normalizes whitespace
 // Strips: ' " - . and extra spaces
 func cleanDescription(s string) string {
@@ -204,9 +603,14 @@ func (tx *OFXTransaction) ToInsertParams(accountID int) insertTransactionParams
 	// Ensure amount is absolute value
 	amount := tx.Amount.Abs()
 
-	// Format date as RFC3339 for parsing
+	// Format date as RFC3339 for parsing; the DATE column keeps the local day
 	dateStr := tx.DatePosted.Format(time.RFC3339)
 
+	var checkNum *string
+	if tx.CheckNum != "" {
+		checkNum = &tx.CheckNum
+	}
+
 	return insertTransactionParams{
 		AccountID:           accountID,
 		Description:         description,
@@ -215,6 +619,7 @@ func (tx *OFXTransaction) ToInsertParams(accountID int) insertTransactionParams
 		TransactionType:     tx.Type,
 		TransactionDate:     dateStr,
 		OFXFitID:            &tx.FITID,
+		OFXCheckNum:         checkNum,
 		OFXMemo:             &tx.Memo,
 	}
 }
@@ -186,29 +186,35 @@ func (s *OFXParserTestSuite) TestParseTransaction_MissingAmount() {
 	s.Contains(err.Error(), "missing TRNAMT")
 }
 
-// Test extractValue with both tag formats
-func (s *OFXParserTestSuite) TestExtractValue_WithClosingTag() {
-	line := "<NAME>Test Name</NAME>"
-	value := s.parser.extractValue(line, "NAME")
-	s.Equal("Test Name", value)
+// Test leaf values with both tag formats
+func (s *OFXParserTestSuite) TestParseOFXElements_WithClosingTag() {
+	root := parseOFXElements("<NAME>Test Name</NAME>", false)
+	s.Equal("Test Name", root.childValue("NAME"))
 }
 
-func (s *OFXParserTestSuite) TestExtractValue_WithoutClosingTag() {
-	line := "<NAME>Test Name"
-	value := s.parser.extractValue(line, "NAME")
-	s.Equal("Test Name", value)
+func (s *OFXParserTestSuite) TestParseOFXElements_WithoutClosingTag() {
+	root := parseOFXElements("<NAME>Test Name", false)
+	s.Equal("Test Name", root.childValue("NAME"))
 }
 
-func (s *OFXParserTestSuite) TestExtractValue_WithWhitespace() {
-	line := "  <NAME>  Test Name  </NAME>  "
-	value := s.parser.extractValue(line, "NAME")
-	s.Equal("Test Name", value)
+func (s *OFXParserTestSuite) TestParseOFXElements_WithWhitespace() {
+	root := parseOFXElements("  <NAME>  Test Name  </NAME>  ", false)
+	s.Equal("Test Name", root.childValue("NAME"))
 }
 
-func (s *OFXParserTestSuite) TestExtractValue_EmptyValue() {
-	line := "<NAME></NAME>"
-	value := s.parser.extractValue(line, "NAME")
-	s.Equal("", value)
+func (s *OFXParserTestSuite) TestParseOFXElements_EmptyValue() {
+	root := parseOFXElements("<NAME></NAME>", false)
+	s.NotNil(root.child("NAME"))
+	s.Equal("", root.childValue("NAME"))
+}
+
+func (s *OFXParserTestSuite) TestParseOFXElements_EmptySGMLLeafDoesNotSwallowSiblings() {
+	root := parseOFXElements("<STMTTRN>\n<MEMO>\n<FITID>abc\n<NAME>R&amp;D Ltda\n</STMTTRN>", false)
+	trn := root.child("STMTTRN")
+	s.Require().NotNil(trn)
+	s.Equal("", trn.childValue("MEMO"))
+	s.Equal("abc", trn.childValue("FITID"))
+	s.Equal("R&D Ltda", trn.childValue("NAME"))
 }
 
 // Test parseOFXDate with various formats
@@ -508,3 +514,178 @@ func (s *OFXParserTestSuite) TestToInsertParams_WithCleanedDescription() {
 	// Description should be cleaned: stripped of quotes, dashes, dots and normalized spaces
 	s.Equal("Joes Pizza NYC Lunch special", params.Description)
 }
+
+func (s *OFXParserTestSuite) TestParseOFXDate_OffsetIsApplied() {
+	date, err := s.parser.parseOFXDate("20251015143025.000[-3:BRT]")
+	s.Require().NoError(err)
+	s.True(date.Equal(time.Date(2025, 10, 15, 17, 30, 25, 0, time.UTC)))
+
+	date, err = s.parser.parseOFXDate("20251015[+5.5:IST]")
+	s.Require().NoError(err)
+	s.True(date.Equal(time.Date(2025, 10, 14, 18, 30, 0, 0, time.UTC)))
+}
+
+func (s *OFXParserTestSuite) TestParseOFXDate_Invalid() {
+	for _, value := range []string{"2025AB15", "20251315", "20250231", "20251015[-99:XXX]"} {
+		_, err := s.parser.parseOFXDate(value)
+		s.Error(err, value)
+	}
+}
+
+// Test a full OFX 1.x SGML bank statement with a latin1 body
+func (s *OFXParserTestSuite) TestParse_SGMLBankStatement() {
+	ofxData := "OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\nSECURITY:NONE\r\nENCODING:USASCII\r\nCHARSET:1252\r\n\r\n" + `
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20251031120000[-3:BRT]<LANGUAGE>POR</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<TRNUID>1
+<STATUS><CODE>0<SEVERITY>INFO</STATUS>
+<STMTRS>
+<CURDEF>BRL
+<BANKACCTFROM>
+<BANKID>0341
+<BRANCHID>1234
+<ACCTID>56789-0
+<ACCTTYPE>CHECKING
+</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20251001000000[-3:BRT]
+<DTEND>20251031000000[-3:BRT]
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20251005000000[-3:BRT]
+<TRNAMT>-12,50
+<FITID>20251005001
+<CHECKNUM>000123
+<MEMO>Caf` + "\xe9" + ` da Esquina
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20251006000000[-3:BRT]
+<TRNAMT>100.00
+<MEMO>Sem FITID
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL>
+<BALAMT>1500.25
+<DTASOF>20251031000000[-3:BRT]
+</LEDGERBAL>
+<AVAILBAL>
+<BALAMT>1400.25
+<DTASOF>20251031000000[-3:BRT]
+</AVAILBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+	doc, err := s.parser.Parse([]byte(ofxData))
+
+	s.Require().NoError(err)
+	s.Equal("102", doc.Version)
+	s.Equal("1252", doc.Charset)
+	s.Require().Len(doc.Statements, 1)
+
+	st := doc.Statements[0]
+	s.Equal(OFXAccount{BankID: "0341", BranchID: "1234", AccountID: "56789-0", AccountType: "CHECKING"}, st.Account)
+	s.Equal("BRL", st.Currency)
+	s.Equal(1, st.StartDate.Day())
+	s.Equal(31, st.EndDate.Day())
+	s.Require().NotNil(st.LedgerBalance)
+	s.True(st.LedgerBalance.Amount.Equal(decimal.RequireFromString("1500.25")))
+	s.Require().NotNil(st.AvailableBalance)
+	s.True(st.AvailableBalance.Amount.Equal(decimal.RequireFromString("1400.25")))
+
+	s.Require().Len(st.Transactions, 1)
+	tx := st.Transactions[0]
+	s.Equal("Café da Esquina", tx.Memo)
+	s.Equal("000123", tx.CheckNum)
+	s.Equal("BRL", tx.Currency)
+	s.True(tx.Amount.Equal(decimal.RequireFromString("-12.50")))
+	s.Equal("2025-10-05T00:00:00-03:00", tx.DatePosted.Format(time.RFC3339))
+
+	s.Require().Len(doc.Errors, 1)
+	s.Equal(1, doc.Errors[0].Index)
+	s.Contains(doc.Errors[0].Error(), "missing FITID")
+}
+
+// Test an OFX 2.x XML credit card statement
+func (s *OFXParserTestSuite) TestParse_XMLCreditCardStatement() {
+	ofxData := `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<CREDITCARDMSGSRSV1>
+<CCSTMTTRNRS>
+<TRNUID>1</TRNUID>
+<CCSTMTRS>
+<CURDEF>BRL</CURDEF>
+<CCACCTFROM><ACCTID>5555********1234</ACCTID></CCACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20251001</DTSTART>
+<DTEND>20251031</DTEND>
+<STMTTRN>
+<TRNTYPE>DEBIT</TRNTYPE>
+<DTPOSTED>20251010</DTPOSTED>
+<TRNAMT>-99.90</TRNAMT>
+<FITID>cc-1</FITID>
+<NAME>Spotify</NAME>
+<MEMO></MEMO>
+<CURRENCY><CURRATE>5.40</CURRATE><CURSYM>USD</CURSYM></CURRENCY>
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>-2345.67</BALAMT><DTASOF>20251031</DTASOF></LEDGERBAL>
+</CCSTMTRS>
+</CCSTMTTRNRS>
+</CREDITCARDMSGSRSV1>
+</OFX>`
+	doc, err := s.parser.Parse([]byte(ofxData))
+
+	s.Require().NoError(err)
+	s.Equal("220", doc.Version)
+	s.Require().Len(doc.Statements, 1)
+
+	st := doc.Statements[0]
+	s.True(st.Account.IsCreditCard)
+	s.Equal("5555********1234", st.Account.AccountID)
+	s.Nil(st.AvailableBalance)
+	s.Require().NotNil(st.LedgerBalance)
+	s.True(st.LedgerBalance.Amount.Equal(decimal.RequireFromString("-2345.67")))
+
+	s.Require().Len(st.Transactions, 1)
+	s.Equal("Spotify", st.Transactions[0].Name)
+	s.Equal("", st.Transactions[0].Memo)
+	s.Equal("USD", st.Transactions[0].Currency)
+	s.Equal(TransactionTypeDebit, st.Transactions[0].Type)
+	s.Empty(doc.Errors)
+}
+
+// Test that investment statements are skipped rather than misread
+func (s *OFXParserTestSuite) TestParse_InvestmentStatements() {
+	investment := `<INVSTMTMSGSRSV1><INVSTMTTRNRS><INVSTMTRS>
+<CURDEF>BRL
+<INVTRANLIST><INVBANKTRAN><STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20251001<TRNAMT>10<FITID>inv-1</STMTTRN></INVBANKTRAN></INVTRANLIST>
+</INVSTMTRS></INVSTMTTRNRS></INVSTMTMSGSRSV1>`
+
+	_, err := s.parser.Parse([]byte("<OFX>" + investment + "</OFX>"))
+	s.Require().Error(err)
+	s.Contains(err.Error(), "investment statements are not supported")
+
+	bank := `<BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>BRL
+<BANKTRANLIST><STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20251002<TRNAMT>-5<FITID>bank-1</STMTTRN></BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>`
+
+	doc, err := s.parser.Parse([]byte("<OFX>" + bank + investment + "</OFX>"))
+	s.Require().NoError(err)
+	s.Equal(1, doc.SkippedInvestmentStatements)
+	transactions := doc.Transactions()
+	s.Require().Len(transactions, 1)
+	s.Equal("bank-1", transactions[0].FITID)
+}
+
+func (s *OFXParserTestSuite) TestXMLAttr() {
+	s.Equal("ISO-8859-1", xmlAttr(`<?xml version='1.0' Encoding="ISO-8859-1"?>`, "encoding"))
+	s.Equal("1.0", xmlAttr(`<?xml version='1.0' Encoding="ISO-8859-1"?>`, "version"))
+	s.Equal("220", xmlAttr(`<?OFX OFXHEADER="200" VERSION="220"?>`, "VERSION"))
+	s.Equal("", xmlAttr(`<?OFX OFXHEADER="200" VERSION="220"?>`, "SECURITY"))
+}
@@ -0,0 +1,153 @@
+package financial
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ofxNameMaxLength is the NAME limit of OFX 1.x; longer descriptions are
+// carried whole in MEMO.
+const ofxNameMaxLength = 32
+
+// ofxStatementWriter writes one account's statement as OFX 1.02, the version
+// Brazilian banks and accounting software still expect. Closing tags are
+// written for every element, which both SGML and XML readers accept.
+type ofxStatementWriter struct {
+	w       io.Writer
+	account OFXAccount
+}
+
+func newOFXStatementWriter(w io.Writer, account OFXAccount) *ofxStatementWriter {
+	return &ofxStatementWriter{w: w, account: account}
+}
+
+// WriteHeader opens the statement. It must be called once, before any
+// transaction.
+func (o *ofxStatementWriter) WriteHeader(currency string, start, end, generatedAt time.Time) error {
+	var b strings.Builder
+	b.WriteString("OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\nSECURITY:NONE\r\nENCODING:UTF-8\r\nCHARSET:NONE\r\nCOMPRESSION:NONE\r\nOLDFILEUID:NONE\r\nNEWFILEUID:NONE\r\n\r\n")
+	b.WriteString("<OFX>\n")
+	b.WriteString("<SIGNONMSGSRSV1><SONRS>\n")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	ofxElement(&b, "DTSERVER", formatOFXDateTime(generatedAt))
+	ofxElement(&b, "LANGUAGE", "POR")
+	b.WriteString("</SONRS></SIGNONMSGSRSV1>\n")
+
+	if o.account.IsCreditCard {
+		b.WriteString("<CREDITCARDMSGSRSV1><CCSTMTTRNRS>\n")
+	} else {
+		b.WriteString("<BANKMSGSRSV1><STMTTRNRS>\n")
+	}
+	ofxElement(&b, "TRNUID", "1")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+
+	if o.account.IsCreditCard {
+		b.WriteString("<CCSTMTRS>\n")
+		ofxElement(&b, "CURDEF", currency)
+		b.WriteString("<CCACCTFROM>\n")
+		ofxElement(&b, "ACCTID", o.account.AccountID)
+		b.WriteString("</CCACCTFROM>\n")
+	} else {
+		b.WriteString("<STMTRS>\n")
+		ofxElement(&b, "CURDEF", currency)
+		b.WriteString("<BANKACCTFROM>\n")
+		if o.account.BankID != "" {
+			ofxElement(&b, "BANKID", o.account.BankID)
+		}
+		ofxElement(&b, "ACCTID", o.account.AccountID)
+		ofxElement(&b, "ACCTTYPE", o.account.AccountType)
+		b.WriteString("</BANKACCTFROM>\n")
+	}
+
+	b.WriteString("<BANKTRANLIST>\n")
+	ofxElement(&b, "DTSTART", formatOFXDate(start))
+	ofxElement(&b, "DTEND", formatOFXDate(end))
+
+	_, err := io.WriteString(o.w, b.String())
+	return err
+}
+
+func (o *ofxStatementWriter) WriteTransaction(tx OFXTransaction) error {
+	var b strings.Builder
+	b.WriteString("<STMTTRN>\n")
+	ofxElement(&b, "TRNTYPE", tx.Type)
+	ofxElement(&b, "DTPOSTED", formatOFXDate(tx.DatePosted))
+	ofxElement(&b, "TRNAMT", tx.Amount.StringFixed(2))
+	ofxElement(&b, "FITID", tx.FITID)
+	if tx.CheckNum != "" {
+		ofxElement(&b, "CHECKNUM", tx.CheckNum)
+	}
+	ofxElement(&b, "NAME", tx.Name)
+	if tx.Memo != "" {
+		ofxElement(&b, "MEMO", tx.Memo)
+	}
+	b.WriteString("</STMTTRN>\n")
+
+	_, err := io.WriteString(o.w, b.String())
+	return err
+}
+
+// WriteFooter closes the statement with the account's ledger balance.
+func (o *ofxStatementWriter) WriteFooter(balance OFXBalance) error {
+	var b strings.Builder
+	b.WriteString("</BANKTRANLIST>\n")
+	b.WriteString("<LEDGERBAL>\n")
+	ofxElement(&b, "BALAMT", balance.Amount.StringFixed(2))
+	ofxElement(&b, "DTASOF", formatOFXDateTime(balance.AsOf))
+	b.WriteString("</LEDGERBAL>\n")
+	if o.account.IsCreditCard {
+		b.WriteString("</CCSTMTRS>\n</CCSTMTTRNRS></CREDITCARDMSGSRSV1>\n")
+	} else {
+		b.WriteString("</STMTRS>\n</STMTTRNRS></BANKMSGSRSV1>\n")
+	}
+	b.WriteString("</OFX>\n")
+
+	_, err := io.WriteString(o.w, b.String())
+	return err
+}
+
+// ofxTransactionFromModel converts a stored transaction back to its statement
+// line. Transactions that were not imported get a FITID derived from their ID
+// so the file can be imported elsewhere without duplicates.
+func ofxTransactionFromModel(model *TransactionModel) OFXTransaction {
+	tx := OFXTransaction{
+		Type:       "CREDIT",
+		DatePosted: model.TransactionDate,
+		Amount:     model.Amount.Abs(),
+		FITID:      fmt.Sprintf("CELEIRO%d", model.TransactionID),
+		Name:       model.Description,
+	}
+	if model.TransactionType == TransactionTypeDebit {
+		tx.Type = "DEBIT"
+		tx.Amount = tx.Amount.Neg()
+	}
+	if model.OFXFitID != nil && *model.OFXFitID != "" {
+		tx.FITID = *model.OFXFitID
+	}
+	if model.OFXCheckNum != nil {
+		tx.CheckNum = *model.OFXCheckNum
+	}
+	if name := []rune(model.Description); len(name) > ofxNameMaxLength {
+		tx.Name = string(name[:ofxNameMaxLength])
+		tx.Memo = model.Description
+	}
+	return tx
+}
+
+func ofxElement(b *strings.Builder, name, value string) {
+	b.WriteString("<" + name + ">" + escapeOFXText(value) + "</" + name + ">\n")
+}
+
+func escapeOFXText(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(value)
+}
+
+func formatOFXDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+func formatOFXDateTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
@@ -0,0 +1,231 @@
+package financial
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultPatternPreviewLimit = 100
+	maxPatternPreviewLimit     = 500
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+// PreviewPatternInput is a draft pattern to evaluate without saving it. With a
+// PatternID the draft stands in for that pattern, as an update would.
+type PreviewPatternInput struct {
+	CreatePatternInput
+	PatternID int
+	Limit     int // Matches listed; the counts always cover every match
+}
+
+type PatternPreview struct {
+	TotalChecked  int                   `json:"total_checked"`
+	MatchedCount  int                   `json:"matched_count"`
+	ChangedCount  int                   `json:"changed_count"`  // Matches whose category, description or ignored flag would change
+	ShadowedCount int                   `json:"shadowed_count"` // Matches a pattern evaluated earlier claims on import
+	Matches       []PatternPreviewMatch `json:"matches"`
+	Truncated     bool                  `json:"truncated"`
+}
+
+type PatternPreviewMatch struct {
+	TransactionID       int             `json:"transaction_id"`
+	TransactionDate     time.Time       `json:"transaction_date"`
+	OriginalDescription string          `json:"original_description"`
+	Amount              decimal.Decimal `json:"amount"`
+
+	CurrentDescription string  `json:"current_description"`
+	CurrentCategoryID  *int    `json:"current_category_id"`
+	NewDescription     *string `json:"new_description,omitempty"` // Set when it would change
+	NewCategoryID      *int    `json:"new_category_id,omitempty"` // Set when it would change
+	WouldBeIgnored     bool    `json:"would_be_ignored"`
+
+	// The first other active pattern that matches the transaction. Shadowed
+	// means it is evaluated before the draft, so imports keep applying it;
+	// retroactive application still overwrites it.
+	MatchedByPatternID *int `json:"matched_by_pattern_id,omitempty"`
+	Shadowed           bool `json:"shadowed"`
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// PreviewPattern evaluates a draft pattern against the organization's
+// transactions with the same matching as imports and retroactive runs, and
+// reports what saving and applying it would do. Nothing is written.
+func (s *service) PreviewPattern(ctx context.Context, input PreviewPatternInput) (PatternPreview, error) {
+	draft, err := newPatternDraft(input.CreatePatternInput)
+	if err != nil {
+		return PatternPreview{}, err
+	}
+	draft.PatternID = input.PatternID
+
+	// A linked planned entry's description replaces the pattern's, as in
+	// applyPatternToTransaction
+	targetDescription := draft.TargetDescription
+	if input.PatternID != 0 {
+		existing, err := s.Repository.FetchAdvancedPatternByID(ctx, fetchAdvancedPatternByIDParams{
+			PatternID:      input.PatternID,
+			UserID:         input.UserID,
+			OrganizationID: input.OrganizationID,
+		})
+		if err != nil {
+			return PatternPreview{}, fmt.Errorf("failed to fetch pattern: %w", err)
+		}
+		draft.Priority = existing.Priority
+
+		linkedEntries, err := s.Repository.FetchPlannedEntriesByPatternIDs(ctx, fetchPlannedEntriesByPatternIDsParams{
+			PatternIDs:     []int{input.PatternID},
+			UserID:         input.UserID,
+			OrganizationID: input.OrganizationID,
+		})
+		if err != nil {
+			return PatternPreview{}, fmt.Errorf("failed to fetch linked planned entries: %w", err)
+		}
+		if len(linkedEntries) > 0 && draft.TargetDescription != nil {
+			targetDescription = &linkedEntries[0].Description
+		}
+	}
+
+	isActive := true
+	patterns, err := s.Repository.FetchAdvancedPatterns(ctx, fetchAdvancedPatternsParams{
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+		IsActive:       &isActive,
+	})
+	if err != nil {
+		return PatternPreview{}, fmt.Errorf("failed to fetch patterns: %w", err)
+	}
+	switch {
+	case input.Priority != nil:
+		draft.Priority = *input.Priority
+	case input.PatternID == 0:
+		draft.Priority = defaultNewPatternPriority(patterns)
+	}
+	matchers := s.compilePatternMatchers(ctx, patternsAroundDraft(patterns, &draft, input.PatternID == 0))
+	draftMatcher, err := newPatternMatcher(&draft)
+	if err != nil {
+		return PatternPreview{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	transactions, err := s.Repository.FetchTransactionsForPatternMatching(ctx, fetchTransactionsForPatternMatchingParams{
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return PatternPreview{}, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultPatternPreviewLimit
+	}
+	if limit > maxPatternPreviewLimit {
+		limit = maxPatternPreviewLimit
+	}
+
+	preview := PatternPreview{
+		TotalChecked: len(transactions),
+		Matches:      []PatternPreviewMatch{},
+	}
+	for i := range transactions {
+		tx := &transactions[i]
+		if !draftMatcher.matches(tx) {
+			continue
+		}
+
+		match := previewPatternMatch(tx, &draft, targetDescription)
+		for _, matcher := range matchers {
+			if matcher.matches(tx) {
+				match.MatchedByPatternID = &matcher.pattern.PatternID
+				match.Shadowed = patternEvaluatedBefore(matcher.pattern, &draft, input.PatternID == 0)
+				break
+			}
+		}
+
+		preview.MatchedCount++
+		if match.WouldBeIgnored || match.NewDescription != nil || match.NewCategoryID != nil {
+			preview.ChangedCount++
+		}
+		if match.Shadowed {
+			preview.ShadowedCount++
+		}
+		if len(preview.Matches) < limit {
+			preview.Matches = append(preview.Matches, match)
+		} else {
+			preview.Truncated = true
+		}
+	}
+
+	return preview, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// patternsAroundDraft returns the active patterns other than the one being
+// edited, in evaluation order.
+func patternsAroundDraft(patterns []AdvancedPatternModel, draft *PatternModel, isNew bool) []AdvancedPatternModel {
+	others := make([]AdvancedPatternModel, 0, len(patterns))
+	for i := range patterns {
+		if isNew || patterns[i].PatternID != draft.PatternID {
+			others = append(others, patterns[i])
+		}
+	}
+	return others
+}
+
+// patternEvaluatedBefore tells whether a saved pattern is evaluated before a
+// draft: lower priority first, then the newer pattern, as
+// fetchAdvancedPatternsQuery orders them. A new draft would be the newest.
+func patternEvaluatedBefore(pattern, draft *PatternModel, draftIsNew bool) bool {
+	if pattern.Priority != draft.Priority {
+		return pattern.Priority < draft.Priority
+	}
+	return !draftIsNew && pattern.PatternID > draft.PatternID
+}
+
+// defaultNewPatternPriority places a draft created without a priority ahead
+// of every active pattern, as insertAdvancedPatternQuery does on save.
+func defaultNewPatternPriority(patterns []AdvancedPatternModel) int {
+	if len(patterns) == 0 {
+		return 100
+	}
+	lowest := patterns[0].Priority
+	for _, pattern := range patterns[1:] {
+		lowest = min(lowest, pattern.Priority)
+	}
+	return lowest - 10
+}
+
+// previewPatternMatch describes what applying the draft would change on a
+// matched transaction.
+func previewPatternMatch(tx *TransactionModel, draft *PatternModel, targetDescription *string) PatternPreviewMatch {
+	match := PatternPreviewMatch{
+		TransactionID:       tx.TransactionID,
+		TransactionDate:     tx.TransactionDate,
+		OriginalDescription: patternMatchDescription(tx),
+		Amount:              tx.Amount,
+		CurrentDescription:  tx.Description,
+		CurrentCategoryID:   tx.CategoryID,
+	}
+
+	if normalizePatternAction(draft.Action) == PatternActionIgnore {
+		match.WouldBeIgnored = true
+		return match
+	}
+	if targetDescription != nil && *targetDescription != tx.Description {
+		match.NewDescription = targetDescription
+	}
+	if draft.TargetCategoryID != nil && (tx.CategoryID == nil || *tx.CategoryID != *draft.TargetCategoryID) {
+		match.NewCategoryID = draft.TargetCategoryID
+	}
+	return match
+}
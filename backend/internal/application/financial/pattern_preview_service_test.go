@@ -0,0 +1,86 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewPattern_ReportsChangesAndShadowedMatchesWithoutWriting(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockRepository{}
+	groceries, bakery := 4, 7
+	original := "PADARIA PAO QUENTE"
+	newer := "PADARIA.*"
+	older := "PAO QUENTE"
+	date := time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC)
+
+	repository.On("FetchAdvancedPatternByID", ctx, mock.Anything).Return(AdvancedPatternModel{PatternID: 5}, nil).Once()
+	repository.On("FetchPlannedEntriesByPatternIDs", ctx, mock.Anything).Return([]PlannedEntryByPatternModel{}, nil).Once()
+	repository.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{
+		{PatternID: 9, DescriptionPattern: &newer, Action: PatternActionCategorize},
+		{PatternID: 5, DescriptionPattern: &older, Action: PatternActionCategorize},
+		{PatternID: 2, DescriptionPattern: &older, Action: PatternActionCategorize},
+	}, nil).Once()
+	repository.On("FetchTransactionsForPatternMatching", ctx, mock.Anything).Return([]TransactionModel{
+		{TransactionID: 1, Description: "Padaria", OriginalDescription: &original, CategoryID: &bakery, TransactionDate: date, Amount: decimal.NewFromInt(12)},
+		{TransactionID: 2, Description: "PAO QUENTE", CategoryID: &groceries, TransactionDate: date, Amount: decimal.NewFromInt(8)},
+		{TransactionID: 3, Description: "MERCADO", TransactionDate: date, Amount: decimal.NewFromInt(90)},
+	}, nil).Once()
+
+	description := "Padaria"
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+	preview, err := svc.PreviewPattern(ctx, PreviewPatternInput{
+		CreatePatternInput: CreatePatternInput{
+			UserID:             3,
+			OrganizationID:     7,
+			DescriptionPattern: "PAO QUENTE",
+			TargetDescription:  &description,
+			TargetCategoryID:   &bakery,
+		},
+		PatternID: 5,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, preview.TotalChecked)
+	assert.Equal(t, 2, preview.MatchedCount)
+	assert.Equal(t, 1, preview.ChangedCount)
+	assert.Equal(t, 1, preview.ShadowedCount)
+	require.Len(t, preview.Matches, 2)
+
+	assert.Equal(t, original, preview.Matches[0].OriginalDescription)
+	assert.Nil(t, preview.Matches[0].NewCategoryID)
+	assert.Nil(t, preview.Matches[0].NewDescription)
+	assert.Equal(t, 9, *preview.Matches[0].MatchedByPatternID)
+	assert.True(t, preview.Matches[0].Shadowed)
+
+	assert.Equal(t, bakery, *preview.Matches[1].NewCategoryID)
+	assert.Equal(t, "Padaria", *preview.Matches[1].NewDescription)
+	assert.Equal(t, 2, *preview.Matches[1].MatchedByPatternID)
+	assert.False(t, preview.Matches[1].Shadowed)
+
+	repository.AssertExpectations(t)
+	repository.AssertNotCalled(t, "ModifyTransaction", mock.Anything, mock.Anything)
+}
+
+func TestPreviewPattern_RejectsInvalidRegex(t *testing.T) {
+	repository := &MockRepository{}
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+
+	_, err := svc.PreviewPattern(context.Background(), PreviewPatternInput{
+		CreatePatternInput: CreatePatternInput{
+			OrganizationID:     7,
+			Action:             PatternActionIgnore,
+			DescriptionPattern: "(",
+		},
+	})
+
+	assert.ErrorContains(t, err, "invalid description_pattern regex")
+	repository.AssertNotCalled(t, "FetchTransactionsForPatternMatching", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,237 @@
+package financial
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultPatternConflictLimit = 100
+	maxPatternConflictLimit     = 500
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type ReorderPatternsInput struct {
+	UserID         int
+	OrganizationID int
+	PatternIDs     []int // Every pattern of the organization, in evaluation order
+}
+
+type GetPatternConflictsInput struct {
+	UserID         int
+	OrganizationID int
+	Limit          int // Conflicts listed; ConflictCount always covers all
+}
+
+type PatternConflictReport struct {
+	TotalChecked  int               `json:"total_checked"`
+	ConflictCount int               `json:"conflict_count"`
+	Conflicts     []PatternConflict `json:"conflicts"`
+	Truncated     bool              `json:"truncated"`
+}
+
+// PatternConflict is a transaction matched by active patterns that would
+// categorize it differently. Imports apply the first of them.
+type PatternConflict struct {
+	TransactionID       int                      `json:"transaction_id"`
+	TransactionDate     time.Time                `json:"transaction_date"`
+	OriginalDescription string                   `json:"original_description"`
+	Amount              decimal.Decimal          `json:"amount"`
+	AppliedPatternID    *int                     `json:"applied_pattern_id"` // The pattern that last categorized it
+	Patterns            []PatternConflictPattern `json:"patterns"`           // Every matching pattern, in evaluation order
+}
+
+type PatternConflictPattern struct {
+	PatternID         int           `json:"pattern_id"`
+	Priority          int           `json:"priority"`
+	Action            PatternAction `json:"action"`
+	TargetDescription *string       `json:"target_description,omitempty"`
+	TargetCategoryID  *int          `json:"target_category_id,omitempty"`
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// ReorderPatterns rewrites pattern priorities to follow the given order.
+func (s *service) ReorderPatterns(ctx context.Context, input ReorderPatternsInput) ([]Pattern, error) {
+	existing, err := s.Repository.FetchAdvancedPatterns(ctx, fetchAdvancedPatternsParams{
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch patterns: %w", err)
+	}
+
+	// Require the full set so a stale client cannot leave two patterns
+	// sharing a priority
+	known := make(map[int]bool, len(existing))
+	for _, pattern := range existing {
+		known[pattern.PatternID] = false
+	}
+	for _, patternID := range input.PatternIDs {
+		seen, ok := known[patternID]
+		if !ok || seen {
+			return nil, errors.Wrap(internalerrors.ErrInvalidPatternOrder, "unknown or duplicated pattern %d", patternID)
+		}
+		known[patternID] = true
+	}
+	if len(input.PatternIDs) != len(existing) {
+		return nil, errors.Wrap(internalerrors.ErrInvalidPatternOrder, "pattern_ids must list every pattern of the organization")
+	}
+
+	updated, err := s.Repository.ModifyAdvancedPatternPriorities(ctx, modifyAdvancedPatternPrioritiesParams{
+		OrganizationID: input.OrganizationID,
+		PatternIDs:     input.PatternIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reorder patterns: %w", err)
+	}
+
+	before := make(map[int]Pattern, len(existing))
+	for i := range existing {
+		before[existing[i].PatternID] = Pattern{}.FromModel(&existing[i])
+	}
+	for i := range updated {
+		s.recordAudit(ctx, auditRecord{
+			OrganizationID: input.OrganizationID,
+			UserID:         input.UserID,
+			EntityType:     AuditEntityPattern,
+			EntityID:       updated[i].PatternID,
+			Action:         AuditActionUpdate,
+			Before:         before[updated[i].PatternID],
+			After:          Pattern{}.FromModel(&updated[i]),
+		})
+	}
+
+	return s.GetPatterns(ctx, GetPatternsInput{UserID: input.UserID, OrganizationID: input.OrganizationID})
+}
+
+// GetPatternConflicts lists the transactions that more than one active
+// pattern matches with different actions or targets, so the order decides
+// how they end up categorized.
+func (s *service) GetPatternConflicts(ctx context.Context, input GetPatternConflictsInput) (PatternConflictReport, error) {
+	isActive := true
+	patterns, err := s.Repository.FetchAdvancedPatterns(ctx, fetchAdvancedPatternsParams{
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+		IsActive:       &isActive,
+	})
+	if err != nil {
+		return PatternConflictReport{}, fmt.Errorf("failed to fetch patterns: %w", err)
+	}
+	matchers := s.compilePatternMatchers(ctx, patterns)
+
+	transactions, err := s.Repository.FetchTransactionsForPatternMatching(ctx, fetchTransactionsForPatternMatchingParams{
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return PatternConflictReport{}, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultPatternConflictLimit
+	}
+	if limit > maxPatternConflictLimit {
+		limit = maxPatternConflictLimit
+	}
+
+	report := PatternConflictReport{
+		TotalChecked: len(transactions),
+		Conflicts:    []PatternConflict{},
+	}
+	if len(matchers) < 2 {
+		return report, nil
+	}
+
+	for i := range transactions {
+		tx := &transactions[i]
+
+		var matched []PatternConflictPattern
+		targets := make(map[string]bool)
+		for _, matcher := range matchers {
+			if !matcher.matches(tx) {
+				continue
+			}
+			pattern := matcher.pattern
+			matched = append(matched, PatternConflictPattern{
+				PatternID:         pattern.PatternID,
+				Priority:          pattern.Priority,
+				Action:            normalizePatternAction(pattern.Action),
+				TargetDescription: pattern.TargetDescription,
+				TargetCategoryID:  pattern.TargetCategoryID,
+			})
+			targets[patternTargetKey(pattern)] = true
+		}
+		if len(targets) < 2 {
+			continue
+		}
+
+		report.ConflictCount++
+		if len(report.Conflicts) >= limit {
+			report.Truncated = true
+			continue
+		}
+
+		conflict := PatternConflict{
+			TransactionID:       tx.TransactionID,
+			TransactionDate:     tx.TransactionDate,
+			OriginalDescription: patternMatchDescription(tx),
+			Amount:              tx.Amount,
+			AppliedPatternID:    tx.PatternID,
+			Patterns:            matched,
+		}
+		report.Conflicts = append(report.Conflicts, conflict)
+	}
+
+	return report, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// compilePatternMatchers compiles patterns in the order given. A stored
+// pattern whose regex no longer compiles is logged and left out, as
+// matchesPattern would never match it.
+func (s *service) compilePatternMatchers(ctx context.Context, patterns []AdvancedPatternModel) []patternMatcher {
+	matchers := make([]patternMatcher, 0, len(patterns))
+	for i := range patterns {
+		matcher, err := newPatternMatcher(&patterns[i])
+		if err != nil {
+			s.logger.Warn(ctx, "Skipping pattern with an invalid regex",
+				"pattern_id", patterns[i].PatternID,
+				"error", err.Error(),
+			)
+			continue
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers
+}
+
+// patternTargetKey identifies what a pattern does to a transaction it matches.
+func patternTargetKey(pattern *PatternModel) string {
+	action := normalizePatternAction(pattern.Action)
+	if action == PatternActionIgnore {
+		return string(action)
+	}
+	key := string(action) + "|"
+	if pattern.TargetCategoryID != nil {
+		key += fmt.Sprint(*pattern.TargetCategoryID)
+	}
+	key += "|"
+	if pattern.TargetDescription != nil {
+		key += *pattern.TargetDescription
+	}
+	return key
+}
@@ -0,0 +1,104 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderPatterns_RejectsIncompleteOrDuplicatedOrder(t *testing.T) {
+	ctx := context.Background()
+	svc := &service{logger: &logging.TestLogger{}}
+
+	for name, patternIDs := range map[string][]int{
+		"missing":    {3},
+		"duplicated": {3, 3},
+		"unknown":    {3, 8},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repository := &MockRepository{}
+			repository.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{
+				{PatternID: 3}, {PatternID: 5},
+			}, nil).Once()
+			svc.Repository = repository
+
+			_, err := svc.ReorderPatterns(ctx, ReorderPatternsInput{OrganizationID: 7, PatternIDs: patternIDs})
+
+			assert.ErrorIs(t, err, internalerrors.ErrInvalidPatternOrder)
+			repository.AssertNotCalled(t, "ModifyAdvancedPatternPriorities", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReorderPatterns_RewritesPrioritiesInGivenOrder(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockRepository{}
+	repository.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{
+		{PatternID: 3, Priority: 10}, {PatternID: 5, Priority: 20},
+	}, nil).Once()
+	repository.On("ModifyAdvancedPatternPriorities", ctx, modifyAdvancedPatternPrioritiesParams{
+		OrganizationID: 7,
+		PatternIDs:     []int{5, 3},
+	}).Return([]AdvancedPatternModel{
+		{PatternID: 5, Priority: 10}, {PatternID: 3, Priority: 20},
+	}, nil).Once()
+	repository.On("InsertAuditLogEntry", ctx, mock.MatchedBy(func(params insertAuditLogEntryParams) bool {
+		return params.EntityType == AuditEntityPattern && params.Action == AuditActionUpdate
+	})).Return(nil).Twice()
+	repository.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{
+		{PatternID: 5, Priority: 10}, {PatternID: 3, Priority: 20},
+	}, nil).Once()
+	repository.On("FetchPlannedEntriesByPatternIDs", ctx, mock.Anything).Return([]PlannedEntryByPatternModel{}, nil).Once()
+
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+	patterns, err := svc.ReorderPatterns(ctx, ReorderPatternsInput{UserID: 3, OrganizationID: 7, PatternIDs: []int{5, 3}})
+
+	require.NoError(t, err)
+	require.Len(t, patterns, 2)
+	assert.Equal(t, 5, patterns[0].PatternID)
+	assert.Equal(t, 10, patterns[0].Priority)
+	repository.AssertExpectations(t)
+}
+
+func TestGetPatternConflicts_ListsMatchesWithDifferentTargets(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockRepository{}
+	bakery, groceries := 4, 7
+	date := time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC)
+
+	repository.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{
+		{PatternID: 9, Priority: 10, Action: PatternActionCategorize, DescriptionPattern: strPtr("PADARIA"), TargetDescription: strPtr("Padaria"), TargetCategoryID: &bakery},
+		{PatternID: 5, Priority: 20, Action: PatternActionCategorize, DescriptionPattern: strPtr("PAO"), TargetDescription: strPtr("Padaria"), TargetCategoryID: &bakery},
+		{PatternID: 2, Priority: 30, Action: PatternActionCategorize, DescriptionPattern: strPtr("MERCADO|CENTRAL"), TargetDescription: strPtr("Mercado"), TargetCategoryID: &groceries},
+	}, nil).Once()
+	repository.On("FetchTransactionsForPatternMatching", ctx, mock.Anything).Return([]TransactionModel{
+		// Patterns 9 and 2 disagree
+		{TransactionID: 1, Description: "PADARIA CENTRAL", PatternID: &[]int{9}[0], TransactionDate: date, Amount: decimal.NewFromInt(12)},
+		// Patterns 9 and 5 agree
+		{TransactionID: 2, Description: "PADARIA PAO", TransactionDate: date, Amount: decimal.NewFromInt(8)},
+		{TransactionID: 3, Description: "MERCADO", TransactionDate: date, Amount: decimal.NewFromInt(90)},
+	}, nil).Once()
+
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+	report, err := svc.GetPatternConflicts(ctx, GetPatternConflictsInput{OrganizationID: 7})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.TotalChecked)
+	assert.Equal(t, 1, report.ConflictCount)
+	require.Len(t, report.Conflicts, 1)
+
+	first := report.Conflicts[0]
+	assert.Equal(t, 1, first.TransactionID)
+	assert.Equal(t, 9, *first.AppliedPatternID)
+	require.Len(t, first.Patterns, 2)
+	assert.Equal(t, 9, first.Patterns[0].PatternID)
+	assert.Equal(t, 2, first.Patterns[1].PatternID)
+	repository.AssertExpectations(t)
+}
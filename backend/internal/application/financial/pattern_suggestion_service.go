@@ -0,0 +1,347 @@
+package financial
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+)
+
+const (
+	defaultPatternSuggestionMinOccurrences = 3
+	defaultPatternSuggestionLimit          = 20
+	maxPatternSuggestionLimit              = 100
+
+	// Leading words of a description kept in the cluster key; what follows is
+	// usually a branch, city or reference that varies between purchases
+	patternSuggestionKeyWords  = 4
+	patternSuggestionMaxSample = 3
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetPatternSuggestionsInput struct {
+	UserID         int
+	OrganizationID int
+	MinOccurrences int // Smallest cluster suggested; defaults to 3
+	Limit          int
+}
+
+// PatternSuggestion is a pattern proposed from a cluster of transactions that
+// share a normalized original description and that no active pattern matches.
+type PatternSuggestion struct {
+	Key                 string   `json:"key"` // Normalized description shared by the cluster
+	DescriptionPattern  string   `json:"description_pattern"`
+	TargetDescription   string   `json:"target_description"`
+	TargetCategoryID    *int     `json:"target_category_id"` // Nil when nothing in the cluster is categorized
+	CategoryAgreement   float64  `json:"category_agreement"` // Share of categorized transactions already in the target category
+	SampleTransactionID int      `json:"sample_transaction_id"`
+	SampleDescriptions  []string `json:"sample_descriptions"`
+
+	TransactionCount   int `json:"transaction_count"`   // Transactions in the cluster
+	ManualCount        int `json:"manual_count"`        // Of those, categorized by hand
+	UncategorizedCount int `json:"uncategorized_count"` // Of those, without a category
+	CoverageCount      int `json:"coverage_count"`      // Transactions the pattern would match, including already categorized ones
+}
+
+// AcceptPatternSuggestionInput creates the pattern suggested for the cluster
+// of TransactionID. Targets left nil come from the suggestion.
+type AcceptPatternSuggestionInput struct {
+	UserID             int
+	OrganizationID     int
+	TransactionID      int
+	TargetDescription  *string
+	TargetCategoryID   *int
+	ApplyRetroactively bool
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// GetPatternSuggestions clusters the transactions categorized by hand or not
+// categorized at all by normalized original description and proposes a
+// pattern for each cluster large enough, biggest first.
+func (s *service) GetPatternSuggestions(ctx context.Context, input GetPatternSuggestionsInput) ([]PatternSuggestion, error) {
+	minOccurrences := input.MinOccurrences
+	if minOccurrences < 2 {
+		minOccurrences = defaultPatternSuggestionMinOccurrences
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultPatternSuggestionLimit
+	}
+	if limit > maxPatternSuggestionLimit {
+		limit = maxPatternSuggestionLimit
+	}
+
+	transactions, clusters, err := s.fetchPatternSuggestionClusters(ctx, input.UserID, input.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []PatternSuggestion{}
+	for key, members := range clusters {
+		if len(members) < minOccurrences {
+			continue
+		}
+		suggestion, err := newPatternSuggestion(key, members, transactions)
+		if err != nil {
+			s.logger.Warn(ctx, "Skipping pattern suggestion", "key", key, "error", err.Error())
+			continue
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].TransactionCount != suggestions[j].TransactionCount {
+			return suggestions[i].TransactionCount > suggestions[j].TransactionCount
+		}
+		return suggestions[i].Key < suggestions[j].Key
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
+
+// AcceptPatternSuggestion turns the suggestion for a transaction's cluster
+// into a categorization pattern.
+func (s *service) AcceptPatternSuggestion(ctx context.Context, input AcceptPatternSuggestionInput) (Pattern, error) {
+	tx, err := s.Repository.FetchTransactionByID(ctx, fetchTransactionByIDParams{
+		TransactionID:  input.TransactionID,
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return Pattern{}, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+
+	key := patternSuggestionKey(patternMatchDescription(&tx))
+	if key == "" {
+		return Pattern{}, errors.Wrap(internalerrors.ErrTransactionDescriptionRequired, "transaction %d", tx.TransactionID)
+	}
+
+	transactions, clusters, err := s.fetchPatternSuggestionClusters(ctx, input.UserID, input.OrganizationID)
+	if err != nil {
+		return Pattern{}, err
+	}
+	// A transaction an active pattern already matches is in no cluster; the
+	// suggestion then rests on it alone
+	members, ok := clusters[key]
+	if !ok {
+		members = []*TransactionModel{&tx}
+	}
+
+	suggestion, err := newPatternSuggestion(key, members, transactions)
+	if err != nil {
+		return Pattern{}, err
+	}
+
+	targetCategoryID := suggestion.TargetCategoryID
+	if input.TargetCategoryID != nil {
+		targetCategoryID = input.TargetCategoryID
+	}
+	if targetCategoryID == nil {
+		return Pattern{}, errors.Wrap(internalerrors.ErrTransactionCategoryRequired, "pick a target category for %q", key)
+	}
+	targetDescription := suggestion.TargetDescription
+	if input.TargetDescription != nil {
+		targetDescription = *input.TargetDescription
+	}
+
+	return s.CreatePattern(ctx, CreatePatternInput{
+		UserID:             input.UserID,
+		OrganizationID:     input.OrganizationID,
+		Action:             PatternActionCategorize,
+		DescriptionPattern: suggestion.DescriptionPattern,
+		TargetDescription:  &targetDescription,
+		TargetCategoryID:   targetCategoryID,
+		ApplyRetroactively: input.ApplyRetroactively,
+	})
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// fetchPatternSuggestionClusters returns the organization's transactions and
+// the suggestion candidates among them grouped by key. Candidates are the
+// transactions without a category or categorized by hand, which no active
+// pattern matches.
+func (s *service) fetchPatternSuggestionClusters(ctx context.Context, userID, organizationID int) ([]TransactionModel, map[string][]*TransactionModel, error) {
+	isActive := true
+	patterns, err := s.Repository.FetchAdvancedPatterns(ctx, fetchAdvancedPatternsParams{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		IsActive:       &isActive,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch patterns: %w", err)
+	}
+	matchers := s.compilePatternMatchers(ctx, patterns)
+
+	transactions, err := s.Repository.FetchTransactionsForPatternMatching(ctx, fetchTransactionsForPatternMatchingParams{
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	clusters := make(map[string][]*TransactionModel)
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.CategoryID != nil && !isManuallyCategorized(tx) {
+			continue
+		}
+		if matchesAnyPattern(matchers, tx) {
+			continue
+		}
+		key := patternSuggestionKey(patternMatchDescription(tx))
+		if key == "" {
+			continue
+		}
+		clusters[key] = append(clusters[key], tx)
+	}
+
+	return transactions, clusters, nil
+}
+
+// newPatternSuggestion proposes a pattern for a cluster. The target category
+// and description are the ones most used in the cluster.
+func newPatternSuggestion(key string, members []*TransactionModel, transactions []TransactionModel) (PatternSuggestion, error) {
+	descriptionPattern := patternSuggestionRegex(key)
+	matcher, err := newPatternMatcher(&PatternModel{DescriptionPattern: &descriptionPattern})
+	if err != nil {
+		return PatternSuggestion{}, fmt.Errorf("invalid suggested pattern: %w", err)
+	}
+
+	suggestion := PatternSuggestion{
+		Key:                 key,
+		DescriptionPattern:  descriptionPattern,
+		SampleTransactionID: members[0].TransactionID,
+		SampleDescriptions:  []string{},
+		TransactionCount:    len(members),
+	}
+
+	categoryCounts := make(map[int]int)
+	descriptionCounts := make(map[string]int)
+	categorizedDescriptionCounts := make(map[string]int)
+	sampled := make(map[string]bool)
+	for _, tx := range members {
+		descriptionCounts[tx.Description]++
+		if tx.CategoryID == nil {
+			suggestion.UncategorizedCount++
+		} else {
+			suggestion.ManualCount++
+			categoryCounts[*tx.CategoryID]++
+			categorizedDescriptionCounts[tx.Description]++
+		}
+
+		original := patternMatchDescription(tx)
+		if !sampled[original] && len(suggestion.SampleDescriptions) < patternSuggestionMaxSample {
+			sampled[original] = true
+			suggestion.SampleDescriptions = append(suggestion.SampleDescriptions, original)
+		}
+	}
+
+	if categoryID, count, ok := mostUsed(categoryCounts); ok {
+		suggestion.TargetCategoryID = &categoryID
+		suggestion.CategoryAgreement = float64(count) / float64(suggestion.ManualCount)
+	}
+	// Descriptions someone settled on while categorizing beat raw bank text
+	if description, _, ok := mostUsed(categorizedDescriptionCounts); ok {
+		suggestion.TargetDescription = description
+	} else {
+		suggestion.TargetDescription, _, _ = mostUsed(descriptionCounts)
+	}
+
+	for i := range transactions {
+		if matcher.matches(&transactions[i]) {
+			suggestion.CoverageCount++
+		}
+	}
+
+	return suggestion, nil
+}
+
+// isManuallyCategorized tells whether a categorized transaction got its
+// category from someone rather than from a pattern or classification rule.
+func isManuallyCategorized(tx *TransactionModel) bool {
+	return tx.CategoryID != nil && tx.PatternID == nil && tx.ClassificationRuleID == nil
+}
+
+func matchesAnyPattern(matchers []patternMatcher, tx *TransactionModel) bool {
+	for _, matcher := range matchers {
+		if matcher.matches(tx) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternMatchDescription is the description patterns match against.
+func patternMatchDescription(tx *TransactionModel) string {
+	if tx.OriginalDescription != nil && *tx.OriginalDescription != "" {
+		return *tx.OriginalDescription
+	}
+	return tx.Description
+}
+
+// patternSuggestionKey normalizes a description into its leading words.
+func patternSuggestionKey(description string) string {
+	words := descriptionWords(description)
+	if len(words) > patternSuggestionKeyWords {
+		words = words[:patternSuggestionKeyWords]
+	}
+	return strings.Join(words, " ")
+}
+
+// descriptionWords splits a description into uppercased words. Punctuation,
+// single letters and words holding digits are dropped, as they tend to be
+// dates, installments and references that differ every time.
+func descriptionWords(description string) []string {
+	fields := strings.FieldsFunc(strings.ToUpper(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if utf8.RuneCountInString(field) < 2 || strings.IndexFunc(field, unicode.IsDigit) >= 0 {
+			continue
+		}
+		words = append(words, field)
+	}
+	return words
+}
+
+// patternSuggestionRegex matches descriptions holding the key's words in
+// order, whatever sits between them.
+func patternSuggestionRegex(key string) string {
+	words := strings.Fields(key)
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	return "(?i)" + strings.Join(words, ".*")
+}
+
+// mostUsed returns the key with the highest count, breaking ties towards the
+// smallest key so suggestions are stable.
+func mostUsed[K int | string](counts map[K]int) (K, int, bool) {
+	var best K
+	bestCount := 0
+	for key, count := range counts {
+		if count > bestCount || (count == bestCount && key < best) {
+			best, bestCount = key, count
+		}
+	}
+	return best, bestCount, bestCount > 0
+}
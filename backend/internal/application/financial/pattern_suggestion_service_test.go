@@ -0,0 +1,119 @@
+package financial
+
+import (
+	"context"
+	"testing"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternSuggestionKey_KeepsLeadingWords(t *testing.T) {
+	assert.Equal(t, "PADARIA PAO QUENTE", patternSuggestionKey("Padaria Pao-Quente 03/12"))
+	assert.Equal(t, "UBER TRIP SAO PAULO", patternSuggestionKey("UBER *TRIP 12AB SAO PAULO BR"))
+	assert.Equal(t, "", patternSuggestionKey("1234 / 5"))
+}
+
+func TestGetPatternSuggestions_ClustersManualAndUncategorizedTransactions(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockRepository{}
+	bakery, groceries := 4, 7
+	patternID := 9
+
+	repository.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{
+		{PatternID: patternID, Action: PatternActionCategorize, DescriptionPattern: strPtr("MERCADO")},
+	}, nil).Once()
+	repository.On("FetchTransactionsForPatternMatching", ctx, mock.Anything).Return([]TransactionModel{
+		{TransactionID: 1, Description: "Padaria", OriginalDescription: strPtr("PADARIA PAO QUENTE 0312"), CategoryID: &bakery},
+		{TransactionID: 2, Description: "Padaria", OriginalDescription: strPtr("PADARIA PAO QUENTE 1503"), CategoryID: &bakery},
+		{TransactionID: 3, Description: "PADARIA PAO QUENTE *22", OriginalDescription: strPtr("PADARIA PAO QUENTE *22")},
+		// Categorized by a pattern since deleted: covered, but not a candidate
+		{TransactionID: 4, Description: "Pão", OriginalDescription: strPtr("PADARIA PAO QUENTE 9999"), CategoryID: &bakery, PatternID: &[]int{3}[0]},
+		// Too few to suggest
+		{TransactionID: 5, Description: "UBER TRIP 1"},
+		{TransactionID: 6, Description: "UBER TRIP 2"},
+		// An active pattern already handles these
+		{TransactionID: 7, Description: "MERCADO X", CategoryID: &groceries},
+		{TransactionID: 8, Description: "MERCADO X"},
+		{TransactionID: 9, Description: "MERCADO X"},
+	}, nil).Once()
+
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+	suggestions, err := svc.GetPatternSuggestions(ctx, GetPatternSuggestionsInput{UserID: 3, OrganizationID: 7})
+
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	suggestion := suggestions[0]
+	assert.Equal(t, "PADARIA PAO QUENTE", suggestion.Key)
+	assert.Equal(t, "(?i)PADARIA.*PAO.*QUENTE", suggestion.DescriptionPattern)
+	assert.Equal(t, "Padaria", suggestion.TargetDescription)
+	assert.Equal(t, bakery, *suggestion.TargetCategoryID)
+	assert.Equal(t, 1.0, suggestion.CategoryAgreement)
+	assert.Equal(t, 3, suggestion.TransactionCount)
+	assert.Equal(t, 2, suggestion.ManualCount)
+	assert.Equal(t, 1, suggestion.UncategorizedCount)
+	assert.Equal(t, 4, suggestion.CoverageCount)
+	assert.Len(t, suggestion.SampleDescriptions, 3)
+	repository.AssertExpectations(t)
+}
+
+func TestAcceptPatternSuggestion_CreatesPatternFromCluster(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockRepository{}
+	bakery := 4
+	tx := TransactionModel{TransactionID: 3, Description: "PADARIA PAO QUENTE *22", OriginalDescription: strPtr("PADARIA PAO QUENTE *22")}
+
+	repository.On("FetchTransactionByID", ctx, mock.Anything).Return(tx, nil).Once()
+	repository.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{}, nil).Once()
+	repository.On("FetchTransactionsForPatternMatching", ctx, mock.Anything).Return([]TransactionModel{
+		{TransactionID: 1, Description: "Padaria", OriginalDescription: strPtr("PADARIA PAO QUENTE 0312"), CategoryID: &bakery},
+		tx,
+	}, nil).Once()
+	repository.On("InsertAdvancedPattern", ctx, mock.MatchedBy(func(params insertAdvancedPatternParams) bool {
+		return params.OrganizationID == 7 &&
+			params.Action == PatternActionCategorize &&
+			*params.DescriptionPattern == "(?i)PADARIA.*PAO.*QUENTE" &&
+			*params.TargetDescription == "Padaria" &&
+			*params.TargetCategoryID == bakery
+	})).Return(AdvancedPatternModel{PatternID: 11}, nil).Once()
+	repository.On("InsertAuditLogEntry", ctx, mock.Anything).Return(nil).Once()
+
+	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+	pattern, err := svc.AcceptPatternSuggestion(ctx, AcceptPatternSuggestionInput{UserID: 3, OrganizationID: 7, TransactionID: 3})
+
+	require.NoError(t, err)
+	assert.Equal(t, 11, pattern.PatternID)
+	repository.AssertExpectations(t)
+}
+
+func TestAcceptPatternSuggestion_RequiresCategoryAndDescription(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("uncategorized cluster", func(t *testing.T) {
+		repository := &MockRepository{}
+		tx := TransactionModel{TransactionID: 5, Description: "UBER TRIP 1"}
+		repository.On("FetchTransactionByID", ctx, mock.Anything).Return(tx, nil).Once()
+		repository.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{}, nil).Once()
+		repository.On("FetchTransactionsForPatternMatching", ctx, mock.Anything).Return([]TransactionModel{tx}, nil).Once()
+
+		svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+		_, err := svc.AcceptPatternSuggestion(ctx, AcceptPatternSuggestionInput{OrganizationID: 7, TransactionID: 5})
+
+		assert.ErrorIs(t, err, internalerrors.ErrTransactionCategoryRequired)
+		repository.AssertNotCalled(t, "InsertAdvancedPattern", mock.Anything, mock.Anything)
+	})
+
+	t.Run("description without words", func(t *testing.T) {
+		repository := &MockRepository{}
+		repository.On("FetchTransactionByID", ctx, mock.Anything).Return(TransactionModel{TransactionID: 6, Description: "0001/12"}, nil).Once()
+
+		svc := &service{Repository: repository, logger: &logging.TestLogger{}}
+		_, err := svc.AcceptPatternSuggestion(ctx, AcceptPatternSuggestionInput{OrganizationID: 7, TransactionID: 6})
+
+		assert.ErrorIs(t, err, internalerrors.ErrTransactionDescriptionRequired)
+		repository.AssertNotCalled(t, "FetchTransactionsForPatternMatching", mock.Anything, mock.Anything)
+	})
+}
@@ -36,6 +36,7 @@ type CreatePatternInput struct {
 	TargetDescription  *string
 	TargetCategoryID   *int
 	ApplyRetroactively bool
+	Priority           *int // Lower = evaluated first; nil goes ahead of every other pattern
 }
 
 type GetPatternsInput struct {
@@ -64,6 +65,7 @@ type UpdatePatternInput struct {
 	AmountMax          *string
 	TargetDescription  *string
 	TargetCategoryID   *int
+	Priority           *int
 }
 
 type DeletePatternInput struct {
@@ -83,80 +85,25 @@ type ApplyPatternOutput struct {
 // ============================================================================
 
 func (s *service) CreatePattern(ctx context.Context, input CreatePatternInput) (Pattern, error) {
-	action := normalizePatternAction(input.Action)
-	if action != PatternActionCategorize && action != PatternActionIgnore {
-		return Pattern{}, fmt.Errorf("invalid pattern action: %s", action)
-	}
-	if action == PatternActionCategorize {
-		if input.TargetDescription == nil || *input.TargetDescription == "" {
-			return Pattern{}, fmt.Errorf("target_description is required for categorize patterns")
-		}
-		if input.TargetCategoryID == nil || *input.TargetCategoryID == 0 {
-			return Pattern{}, fmt.Errorf("target_category_id is required for categorize patterns")
-		}
-	} else {
-		input.TargetDescription = nil
-		input.TargetCategoryID = nil
-		input.ApplyRetroactively = false
-	}
-
-	// Validate regex patterns
-	if input.DescriptionPattern == "" {
-		return Pattern{}, fmt.Errorf("description_pattern is required")
-	}
-
-	// Test description pattern is valid regex
-	if _, err := regexp.Compile(input.DescriptionPattern); err != nil {
-		return Pattern{}, fmt.Errorf("invalid description_pattern regex: %w", err)
-	}
-
-	// Test date pattern if provided
-	if input.DatePattern != nil && *input.DatePattern != "" {
-		if _, err := regexp.Compile(*input.DatePattern); err != nil {
-			return Pattern{}, fmt.Errorf("invalid date_pattern regex: %w", err)
-		}
-	}
-
-	// Test weekday pattern if provided
-	if input.WeekdayPattern != nil && *input.WeekdayPattern != "" {
-		if _, err := regexp.Compile(*input.WeekdayPattern); err != nil {
-			return Pattern{}, fmt.Errorf("invalid weekday_pattern regex: %w", err)
-		}
-	}
-
-	// Validate amount range
-	if (input.AmountMin != nil && input.AmountMax == nil) || (input.AmountMin == nil && input.AmountMax != nil) {
-		return Pattern{}, fmt.Errorf("amount_min and amount_max must both be provided or both be null")
-	}
-
-	if input.AmountMin != nil && input.AmountMax != nil && *input.AmountMin > *input.AmountMax {
-		return Pattern{}, fmt.Errorf("amount_min must be less than or equal to amount_max")
-	}
-
-	// Convert amount range to decimal
-	var amountMin, amountMax *decimal.Decimal
-	if input.AmountMin != nil {
-		min := decimal.NewFromFloat(*input.AmountMin)
-		amountMin = &min
-	}
-	if input.AmountMax != nil {
-		max := decimal.NewFromFloat(*input.AmountMax)
-		amountMax = &max
+	draft, err := newPatternDraft(input)
+	if err != nil {
+		return Pattern{}, err
 	}
 
 	// Create the pattern
 	patternModel, err := s.Repository.InsertAdvancedPattern(ctx, insertAdvancedPatternParams{
 		UserID:             input.UserID,
 		OrganizationID:     input.OrganizationID,
-		Action:             action,
-		DescriptionPattern: &input.DescriptionPattern,
-		DatePattern:        input.DatePattern,
-		WeekdayPattern:     input.WeekdayPattern,
-		AmountMin:          amountMin,
-		AmountMax:          amountMax,
-		TargetDescription:  input.TargetDescription,
-		TargetCategoryID:   input.TargetCategoryID,
-		ApplyRetroactively: input.ApplyRetroactively,
+		Action:             draft.Action,
+		DescriptionPattern: draft.DescriptionPattern,
+		DatePattern:        draft.DatePattern,
+		WeekdayPattern:     draft.WeekdayPattern,
+		AmountMin:          draft.AmountMin,
+		AmountMax:          draft.AmountMax,
+		TargetDescription:  draft.TargetDescription,
+		TargetCategoryID:   draft.TargetCategoryID,
+		ApplyRetroactively: draft.ApplyRetroactively,
+		Priority:           input.Priority,
 	})
 	if err != nil {
 		return Pattern{}, fmt.Errorf("failed to create pattern: %w", err)
@@ -164,11 +111,27 @@ func (s *service) CreatePattern(ctx context.Context, input CreatePatternInput) (
 
 	pattern := Pattern{}.FromModel(&patternModel)
 
-	// If apply_retroactively is true, apply the pattern to existing transactions
-	if input.ApplyRetroactively {
-		go s.applyPatternRetroactively(context.Background(), pattern, input.UserID, input.OrganizationID)
+	// If apply_retroactively is true, apply the pattern to existing transactions.
+	// The run goes through the job queue so it is retried if it fails.
+	if draft.ApplyRetroactively {
+		if err := s.enqueueApplyPatternRetroactively(ctx, pattern.PatternID, input.UserID, input.OrganizationID); err != nil {
+			s.logger.Warn(ctx, "Failed to enqueue retroactive pattern run, running in background",
+				"pattern_id", pattern.PatternID,
+				"error", err.Error(),
+			)
+			go s.applyPatternRetroactively(context.Background(), pattern, input.UserID, input.OrganizationID)
+		}
 	}
 
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntityPattern,
+		EntityID:       pattern.PatternID,
+		Action:         AuditActionCreate,
+		After:          pattern,
+	})
+
 	return pattern, nil
 }
 
@@ -292,16 +255,35 @@ func (s *service) UpdatePattern(ctx context.Context, input UpdatePatternInput) (
 		TargetCategoryID:     targetCategoryID,
 		TargetCategoryIDSet:  targetsChanged,
 		ApplyRetroactively:   applyRetroactively,
+		Priority:             input.Priority,
 	})
 	if err != nil {
 		return Pattern{}, fmt.Errorf("failed to update pattern: %w", err)
 	}
 
-	return Pattern{}.FromModel(&pattern), nil
+	updated := Pattern{}.FromModel(&pattern)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntityPattern,
+		EntityID:       updated.PatternID,
+		Action:         AuditActionUpdate,
+		Before:         Pattern{}.FromModel(&existing),
+		After:          updated,
+	})
+
+	return updated, nil
 }
 
 func (s *service) DeletePattern(ctx context.Context, input DeletePatternInput) error {
-	err := s.Repository.RemoveAdvancedPattern(ctx, removeAdvancedPatternParams{
+	existing, err := s.Repository.FetchAdvancedPatternByID(ctx, fetchAdvancedPatternByIDParams{
+		PatternID: input.PatternID, UserID: input.UserID, OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch pattern: %w", err)
+	}
+
+	err = s.Repository.RemoveAdvancedPattern(ctx, removeAdvancedPatternParams{
 		PatternID:      input.PatternID,
 		UserID:         input.UserID,
 		OrganizationID: input.OrganizationID,
@@ -310,6 +292,15 @@ func (s *service) DeletePattern(ctx context.Context, input DeletePatternInput) e
 		return fmt.Errorf("failed to delete pattern: %w", err)
 	}
 
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntityPattern,
+		EntityID:       input.PatternID,
+		Action:         AuditActionDelete,
+		Before:         Pattern{}.FromModel(&existing),
+	})
+
 	return nil
 }
 
@@ -390,6 +381,87 @@ func (s *service) ApplyPatternRetroactivelySync(ctx context.Context, input Apply
 // Helper Functions
 // ============================================================================
 
+// newPatternDraft validates the fields of a new pattern and returns it as it
+// would be saved. Ignore patterns drop their targets and never run
+// retroactively.
+func newPatternDraft(input CreatePatternInput) (PatternModel, error) {
+	action := normalizePatternAction(input.Action)
+	if action != PatternActionCategorize && action != PatternActionIgnore {
+		return PatternModel{}, fmt.Errorf("invalid pattern action: %s", action)
+	}
+	if action == PatternActionCategorize {
+		if input.TargetDescription == nil || *input.TargetDescription == "" {
+			return PatternModel{}, fmt.Errorf("target_description is required for categorize patterns")
+		}
+		if input.TargetCategoryID == nil || *input.TargetCategoryID == 0 {
+			return PatternModel{}, fmt.Errorf("target_category_id is required for categorize patterns")
+		}
+	} else {
+		input.TargetDescription = nil
+		input.TargetCategoryID = nil
+		input.ApplyRetroactively = false
+	}
+
+	// Validate regex patterns
+	if input.DescriptionPattern == "" {
+		return PatternModel{}, fmt.Errorf("description_pattern is required")
+	}
+
+	// Test description pattern is valid regex
+	if _, err := regexp.Compile(input.DescriptionPattern); err != nil {
+		return PatternModel{}, fmt.Errorf("invalid description_pattern regex: %w", err)
+	}
+
+	// Test date pattern if provided
+	if input.DatePattern != nil && *input.DatePattern != "" {
+		if _, err := regexp.Compile(*input.DatePattern); err != nil {
+			return PatternModel{}, fmt.Errorf("invalid date_pattern regex: %w", err)
+		}
+	}
+
+	// Test weekday pattern if provided
+	if input.WeekdayPattern != nil && *input.WeekdayPattern != "" {
+		if _, err := regexp.Compile(*input.WeekdayPattern); err != nil {
+			return PatternModel{}, fmt.Errorf("invalid weekday_pattern regex: %w", err)
+		}
+	}
+
+	// Validate amount range
+	if (input.AmountMin != nil && input.AmountMax == nil) || (input.AmountMin == nil && input.AmountMax != nil) {
+		return PatternModel{}, fmt.Errorf("amount_min and amount_max must both be provided or both be null")
+	}
+
+	if input.AmountMin != nil && input.AmountMax != nil && *input.AmountMin > *input.AmountMax {
+		return PatternModel{}, fmt.Errorf("amount_min must be less than or equal to amount_max")
+	}
+
+	// Convert amount range to decimal
+	var amountMin, amountMax *decimal.Decimal
+	if input.AmountMin != nil {
+		min := decimal.NewFromFloat(*input.AmountMin)
+		amountMin = &min
+	}
+	if input.AmountMax != nil {
+		max := decimal.NewFromFloat(*input.AmountMax)
+		amountMax = &max
+	}
+
+	return PatternModel{
+		UserID:             input.UserID,
+		OrganizationID:     input.OrganizationID,
+		Action:             action,
+		DescriptionPattern: &input.DescriptionPattern,
+		DatePattern:        input.DatePattern,
+		WeekdayPattern:     input.WeekdayPattern,
+		AmountMin:          amountMin,
+		AmountMax:          amountMax,
+		TargetDescription:  input.TargetDescription,
+		TargetCategoryID:   input.TargetCategoryID,
+		ApplyRetroactively: input.ApplyRetroactively,
+		IsActive:           true,
+	}, nil
+}
+
 // applyPatternRetroactively applies a pattern to all existing transactions that match (including already categorized ones)
 // This runs in a goroutine to avoid blocking the API response
 func (s *service) applyPatternRetroactively(ctx context.Context, pattern Pattern, userID, organizationID int) {
@@ -438,60 +510,77 @@ func (s *service) applyPatternRetroactively(ctx context.Context, pattern Pattern
 // matchesPattern checks if a transaction matches an advanced pattern
 // Note: Uses original_description for regex matching (not user-edited description)
 func (s *service) matchesPattern(ctx context.Context, tx *TransactionModel, pattern *PatternModel) bool {
-	// 1. Check description pattern (required) - uses original_description for consistent matching
+	matcher, err := newPatternMatcher(pattern)
+	if err != nil {
+		s.logger.Error(ctx, fmt.Sprintf("Invalid pattern regex: %v", err))
+		return false
+	}
+	return matcher.matches(tx)
+}
+
+// patternMatcher holds a pattern with its regexes compiled, so one pattern
+// can be checked against many transactions.
+type patternMatcher struct {
+	pattern     *PatternModel
+	description *regexp.Regexp
+	date        *regexp.Regexp
+	weekday     *regexp.Regexp
+}
+
+func newPatternMatcher(pattern *PatternModel) (patternMatcher, error) {
+	matcher := patternMatcher{pattern: pattern}
+	var err error
 	if pattern.DescriptionPattern != nil {
-		descRegex, err := regexp.Compile(*pattern.DescriptionPattern)
-		if err != nil {
-			s.logger.Error(ctx, fmt.Sprintf("Invalid description pattern regex: %v", err))
-			return false
+		if matcher.description, err = regexp.Compile(*pattern.DescriptionPattern); err != nil {
+			return patternMatcher{}, fmt.Errorf("description pattern: %w", err)
+		}
+	}
+	if pattern.DatePattern != nil && *pattern.DatePattern != "" {
+		if matcher.date, err = regexp.Compile(*pattern.DatePattern); err != nil {
+			return patternMatcher{}, fmt.Errorf("date pattern: %w", err)
+		}
+	}
+	if pattern.WeekdayPattern != nil && *pattern.WeekdayPattern != "" {
+		if matcher.weekday, err = regexp.Compile(*pattern.WeekdayPattern); err != nil {
+			return patternMatcher{}, fmt.Errorf("weekday pattern: %w", err)
 		}
+	}
+	return matcher, nil
+}
+
+func (m patternMatcher) matches(tx *TransactionModel) bool {
+	// 1. Check description pattern (required) - uses original_description for consistent matching
+	if m.description != nil {
 		// Use original_description if available, fallback to description for older transactions
 		descToMatch := tx.Description
 		if tx.OriginalDescription != nil && *tx.OriginalDescription != "" {
 			descToMatch = *tx.OriginalDescription
 		}
-		if !descRegex.MatchString(descToMatch) {
+		if !m.description.MatchString(descToMatch) {
 			return false
 		}
 	}
 
 	// 2. Check date pattern (optional)
-	if pattern.DatePattern != nil && *pattern.DatePattern != "" {
-		dateStr := tx.TransactionDate.Format("2006-01-02")
-		dateRegex, err := regexp.Compile(*pattern.DatePattern)
-		if err != nil {
-			s.logger.Error(ctx, fmt.Sprintf("Invalid date pattern regex: %v", err))
-			return false
-		}
-		if !dateRegex.MatchString(dateStr) {
-			return false
-		}
+	if m.date != nil && !m.date.MatchString(tx.TransactionDate.Format("2006-01-02")) {
+		return false
 	}
 
 	// 3. Check weekday pattern (optional)
 	// Weekday: 0=Sunday, 1=Monday, ..., 6=Saturday
-	if pattern.WeekdayPattern != nil && *pattern.WeekdayPattern != "" {
-		weekday := int(tx.TransactionDate.Weekday())
-		weekdayStr := strconv.Itoa(weekday)
-		weekdayRegex, err := regexp.Compile(*pattern.WeekdayPattern)
-		if err != nil {
-			s.logger.Error(ctx, fmt.Sprintf("Invalid weekday pattern regex: %v", err))
-			return false
-		}
-		if !weekdayRegex.MatchString(weekdayStr) {
-			return false
-		}
+	if m.weekday != nil && !m.weekday.MatchString(strconv.Itoa(int(tx.TransactionDate.Weekday()))) {
+		return false
 	}
 
 	// 4. Check amount range (optional)
-	if pattern.AmountMin != nil && pattern.AmountMax != nil {
+	if m.pattern.AmountMin != nil && m.pattern.AmountMax != nil {
 		// Get absolute value for comparison (we compare amounts regardless of debit/credit)
 		absAmount := tx.Amount
 		if absAmount.LessThan(decimal.Zero) {
 			absAmount = absAmount.Neg()
 		}
 
-		if absAmount.LessThan(*pattern.AmountMin) || absAmount.GreaterThan(*pattern.AmountMax) {
+		if absAmount.LessThan(*m.pattern.AmountMin) || absAmount.GreaterThan(*m.pattern.AmountMax) {
 			return false
 		}
 	}
@@ -504,12 +593,17 @@ func (s *service) matchesPattern(ctx context.Context, tx *TransactionModel, patt
 func (s *service) applyPatternToTransaction(ctx context.Context, tx *TransactionModel, pattern *PatternModel, userID, organizationID int) error {
 	if normalizePatternAction(pattern.Action) == PatternActionIgnore {
 		isIgnored := true
-		_, err := s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
+		updated, err := s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
 			TransactionID:  tx.TransactionID,
 			OrganizationID: organizationID,
 			IsIgnored:      &isIgnored,
+			PatternID:      &pattern.PatternID,
 		})
-		return err
+		if err != nil {
+			return err
+		}
+		s.recordPatternApplication(ctx, tx, &updated, userID, organizationID)
+		return nil
 	}
 	if pattern.TargetDescription == nil || pattern.TargetCategoryID == nil {
 		return fmt.Errorf("categorize pattern %d has no target mapping", pattern.PatternID)
@@ -546,11 +640,12 @@ func (s *service) applyPatternToTransaction(ctx context.Context, tx *Transaction
 	}
 
 	// Update transaction with the determined values
-	_, err = s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
+	updated, err := s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
 		TransactionID:  tx.TransactionID,
 		OrganizationID: organizationID,
 		Description:    &description,
 		CategoryID:     &categoryID,
+		PatternID:      &pattern.PatternID,
 	})
 	if err != nil {
 		return err
@@ -567,11 +662,14 @@ func (s *service) applyPatternToTransaction(ctx context.Context, tx *Transaction
 		if tx.SavingsGoalID == nil {
 			for _, entry := range linkedEntries {
 				if entry.SavingsGoalID != nil {
-					_, err := s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
+					withGoal, err := s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
 						TransactionID:  tx.TransactionID,
 						OrganizationID: organizationID,
 						SavingsGoalID:  entry.SavingsGoalID,
 					})
+					if err == nil {
+						updated = withGoal
+					}
 					if err != nil {
 						s.logger.Warn(ctx, "Failed to inherit savings goal from planned entry",
 							"transaction_id", tx.TransactionID,
@@ -644,9 +742,25 @@ func (s *service) applyPatternToTransaction(ctx context.Context, tx *Transaction
 		}
 	}
 
+	s.recordPatternApplication(ctx, tx, &updated, userID, organizationID)
 	return nil
 }
 
+// recordPatternApplication audits a transaction a pattern changed, as an
+// update by the user the pattern ran for; the change to pattern_id names the
+// pattern.
+func (s *service) recordPatternApplication(ctx context.Context, before, after *TransactionModel, userID, organizationID int) {
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		EntityType:     AuditEntityTransaction,
+		EntityID:       before.TransactionID,
+		Action:         AuditActionUpdate,
+		Before:         Transaction{}.FromModel(before),
+		After:          Transaction{}.FromModel(after),
+	})
+}
+
 // ApplyPatternsToTransactionInput contains parameters for applying patterns to a transaction.
 type ApplyPatternsToTransactionInput struct {
 	TransactionID  int
@@ -654,7 +768,8 @@ type ApplyPatternsToTransactionInput struct {
 	OrganizationID int
 }
 
-// AutoApplyPatterns evaluates all active patterns against a transaction and applies the first match.
+// AutoApplyPatterns evaluates all active patterns against a transaction, in
+// priority order, and applies the first match.
 //
 // It exists so OFX import and any other ingestion path can share the exact same behavior.
 func (s *service) AutoApplyPatterns(ctx context.Context, input ApplyPatternsToTransactionInput) (bool, error) {
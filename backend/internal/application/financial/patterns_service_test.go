@@ -110,8 +110,13 @@ func TestPatternsService_ApplyPatternToTransaction_IgnoreOnlyMarksTransactionIgn
 		return params.TransactionID == 42 &&
 			params.OrganizationID == 7 &&
 			params.IsIgnored != nil && *params.IsIgnored &&
-			params.Description == nil && params.CategoryID == nil
-	})).Return(TransactionModel{}, nil).Once()
+			params.Description == nil && params.CategoryID == nil &&
+			params.PatternID != nil && *params.PatternID == 9
+	})).Return(TransactionModel{TransactionID: 42, Description: "PIX PADARIA", IsIgnored: true, PatternID: &[]int{9}[0]}, nil).Once()
+	repository.On("InsertAuditLogEntry", ctx, mock.MatchedBy(func(params insertAuditLogEntryParams) bool {
+		return params.EntityType == AuditEntityTransaction && params.EntityID == 42 &&
+			params.Action == AuditActionUpdate && params.UserID != nil && *params.UserID == 3
+	})).Return(nil).Once()
 
 	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
 	tx := &TransactionModel{TransactionID: 42, Description: "PIX PADARIA"}
@@ -151,6 +156,9 @@ func TestPatternsService_CreateIgnorePattern_DropsTargetsAndDisablesRetroactiveA
 			params.TargetCategoryID == nil &&
 			!params.ApplyRetroactively
 	})).Return(AdvancedPatternModel{PatternID: 9, Action: PatternActionIgnore}, nil).Once()
+	repository.On("InsertAuditLogEntry", ctx, mock.MatchedBy(func(params insertAuditLogEntryParams) bool {
+		return params.EntityType == AuditEntityPattern && params.EntityID == 9 && params.Action == AuditActionCreate
+	})).Return(nil).Once()
 
 	description := "não deve persistir"
 	categoryID := 12
@@ -197,6 +205,9 @@ func TestPatternsService_UpdatePattern_ToIgnoreClearsTargets(t *testing.T) {
 			params.TargetCategoryIDSet && params.TargetCategoryID == nil &&
 			params.ApplyRetroactively != nil && !*params.ApplyRetroactively
 	})).Return(AdvancedPatternModel{PatternID: 9, Action: PatternActionIgnore}, nil).Once()
+	repository.On("InsertAuditLogEntry", ctx, mock.MatchedBy(func(params insertAuditLogEntryParams) bool {
+		return params.EntityType == AuditEntityPattern && params.EntityID == 9 && params.Action == AuditActionUpdate
+	})).Return(nil).Once()
 
 	svc := &service{Repository: repository, logger: &logging.TestLogger{}}
 	pattern, err := svc.UpdatePattern(ctx, UpdatePatternInput{
@@ -211,6 +222,7 @@ func TestPatternsService_UpdatePattern_ToIgnoreClearsTargets(t *testing.T) {
 func TestPatternsService_AutoApplyPatterns_AppliesNewestIgnoreMatchFirst(t *testing.T) {
 	ctx := context.Background()
 	repository := &MockRepository{}
+	repository.On("InsertAuditLogEntry", ctx, mock.Anything).Return(nil)
 	repository.On("FetchTransactionByID", ctx, mock.Anything).Return(TransactionModel{
 		TransactionID: 42, Description: "PIX PADARIA",
 	}, nil).Once()
@@ -0,0 +1,156 @@
+package financial
+
+import (
+	"slices"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+)
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// plannedEntryOccurrences returns how many times entry falls in a budget month:
+// 0 or 1, or for weekly entries the number of weeks it falls on. One-off
+// entries fall in their target month. The SQL function
+// planned_entry_occurrences evaluates the same rule in aggregate queries.
+func plannedEntryOccurrences(entry PlannedEntryModel, month, year int) int {
+	if !entry.IsRecurrent {
+		if entry.TargetMonth != nil && entry.TargetYear != nil &&
+			*entry.TargetMonth == month && *entry.TargetYear == year {
+			return 1
+		}
+		return 0
+	}
+	return recurrenceOccurrences(entry, month, year)
+}
+
+// recurrenceOccurrences evaluates the recurrence rule of entry for a month. The
+// rule first occurs on StartsOn (CreatedAt when unset); monthly and yearly
+// rules are evaluated by month, so the day only matters for weekly entries.
+func recurrenceOccurrences(entry PlannedEntryModel, month, year int) int {
+	if entry.RecurrenceFrequency == RecurrenceFrequencyWeekly {
+		return len(weeklyOccurrenceDates(entry, month, year))
+	}
+
+	anchor, monthStart, lastDay := recurrenceBounds(entry, month, year)
+	anchorMonth := time.Date(anchor.Year(), anchor.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if lastDay.Before(monthStart) || monthStart.Before(anchorMonth) {
+		return 0
+	}
+	interval := max(entry.RecurrenceInterval, 1)
+
+	// Without a number of occurrences only the month itself needs checking
+	candidate := monthStart
+	if entry.OccurrenceCount != nil {
+		candidate = anchorMonth
+	}
+	seen := 0
+	for ; !candidate.After(monthStart); candidate = candidate.AddDate(0, 1, 0) {
+		if !recurrenceMonthMatches(entry, anchor, candidate, interval) {
+			continue
+		}
+		seen++
+		if candidate.Equal(monthStart) && (entry.OccurrenceCount == nil || seen <= *entry.OccurrenceCount) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// weeklyOccurrenceDates returns the days a weekly entry falls on in a month.
+func weeklyOccurrenceDates(entry PlannedEntryModel, month, year int) []time.Time {
+	anchor, monthStart, lastDay := recurrenceBounds(entry, month, year)
+	if lastDay.Before(anchor) {
+		return nil
+	}
+
+	step := 7 * max(entry.RecurrenceInterval, 1)
+	first := 0
+	if monthStart.After(anchor) {
+		first = (daysBetween(anchor, monthStart) + step - 1) / step
+	}
+	last := daysBetween(anchor, lastDay) / step
+	if entry.OccurrenceCount != nil {
+		last = min(last, *entry.OccurrenceCount-1)
+	}
+
+	var dates []time.Time
+	for k := first; k <= last; k++ {
+		dates = append(dates, anchor.AddDate(0, 0, k*step))
+	}
+	return dates
+}
+
+// recurrenceBounds returns the first occurrence of entry's rule, and the first
+// and last day of the month the rule can fall on, up to its end date.
+func recurrenceBounds(entry PlannedEntryModel, month, year int) (anchor, monthStart, lastDay time.Time) {
+	monthStart = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	lastDay = monthStart.AddDate(0, 1, -1)
+	anchor = monthStart
+	if entry.StartsOn != nil {
+		anchor = truncateToDay(*entry.StartsOn)
+	} else if !entry.CreatedAt.IsZero() {
+		anchor = truncateToDay(entry.CreatedAt)
+	}
+	if entry.EndsOn != nil && truncateToDay(*entry.EndsOn).Before(lastDay) {
+		lastDay = truncateToDay(*entry.EndsOn)
+	}
+	return anchor, monthStart, lastDay
+}
+
+// recurrenceMonthMatches reports whether the month starting on monthStart fits
+// the rule of a monthly or yearly entry first occurring on anchor. Yearly
+// entries fall in the anchor's month unless months are given.
+func recurrenceMonthMatches(entry PlannedEntryModel, anchor, monthStart time.Time, interval int) bool {
+	if entry.RecurrenceFrequency == RecurrenceFrequencyYearly {
+		if (monthStart.Year()-anchor.Year())%interval != 0 {
+			return false
+		}
+		if len(entry.RecurrenceMonths) == 0 {
+			return monthStart.Month() == anchor.Month()
+		}
+	} else {
+		elapsed := (monthStart.Year()-anchor.Year())*12 + int(monthStart.Month()) - int(anchor.Month())
+		if elapsed%interval != 0 {
+			return false
+		}
+	}
+	return len(entry.RecurrenceMonths) == 0 || slices.Contains(entry.RecurrenceMonths, int64(monthStart.Month()))
+}
+
+// validateRecurrence checks the parts of a recurrence rule that are given.
+func validateRecurrence(frequency *string, interval *int, months []int, startsOn, endsOn *time.Time, count *int) error {
+	if frequency != nil && !slices.Contains([]string{RecurrenceFrequencyWeekly, RecurrenceFrequencyMonthly, RecurrenceFrequencyYearly}, *frequency) {
+		return errors.Wrap(internalerrors.ErrInvalidRecurrence, "unknown frequency %q", *frequency)
+	}
+	if interval != nil && (*interval < 1 || *interval > 120) {
+		return errors.Wrap(internalerrors.ErrInvalidRecurrence, "interval must be between 1 and 120")
+	}
+	for _, month := range months {
+		if month < 1 || month > 12 {
+			return errors.Wrap(internalerrors.ErrInvalidRecurrence, "invalid month %d", month)
+		}
+	}
+	if startsOn != nil && endsOn != nil && endsOn.Before(*startsOn) {
+		return errors.Wrap(internalerrors.ErrInvalidRecurrence, "end date is before the start date")
+	}
+	if count != nil && *count < 1 {
+		return errors.Wrap(internalerrors.ErrInvalidRecurrence, "number of occurrences must be positive")
+	}
+	return nil
+}
+
+// parseRecurrenceDate parses an optional YYYY-MM-DD date of a recurrence rule.
+func parseRecurrenceDate(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := parseTransactionDate(value)
+	if err != nil {
+		return nil, internalerrors.NewInvalidTimeFormatError(field)
+	}
+	return &date, nil
+}
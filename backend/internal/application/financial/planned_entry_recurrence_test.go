@@ -0,0 +1,175 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlannedEntryOccurrences(t *testing.T) {
+	date := func(year int, month time.Month, day int) *time.Time {
+		d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	count := func(n int) *int { return &n }
+	april, year := 4, 2026
+
+	tests := []struct {
+		name  string
+		entry PlannedEntryModel
+		month int
+		year  int
+		want  int
+	}{
+		{
+			name:  "one-off in its target month",
+			entry: PlannedEntryModel{TargetMonth: &april, TargetYear: &year},
+			month: 4, year: 2026, want: 1,
+		},
+		{
+			name:  "one-off in another month",
+			entry: PlannedEntryModel{TargetMonth: &april, TargetYear: &year},
+			month: 5, year: 2026, want: 0,
+		},
+		{
+			name:  "legacy monthly entry",
+			entry: PlannedEntryModel{IsRecurrent: true, CreatedAt: time.Date(2026, time.March, 18, 10, 0, 0, 0, time.UTC)},
+			month: 8, year: 2026, want: 1,
+		},
+		{
+			name:  "every two months, on month",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 2, StartsOn: date(2026, time.March, 10)},
+			month: 5, year: 2026, want: 1,
+		},
+		{
+			name:  "every two months, off month",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 2, StartsOn: date(2026, time.March, 10)},
+			month: 4, year: 2026, want: 0,
+		},
+		{
+			name:  "before the start date",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 1, StartsOn: date(2026, time.March, 10)},
+			month: 2, year: 2026, want: 0,
+		},
+		{
+			name:  "yearly IPVA in the following year",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyYearly, RecurrenceInterval: 1, StartsOn: date(2026, time.January, 15)},
+			month: 1, year: 2027, want: 1,
+		},
+		{
+			name:  "yearly IPVA in another month",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyYearly, RecurrenceInterval: 1, StartsOn: date(2026, time.January, 15)},
+			month: 2, year: 2027, want: 0,
+		},
+		{
+			name:  "yearly on given months",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyYearly, RecurrenceInterval: 1, RecurrenceMonths: pq.Int64Array{3, 9}, StartsOn: date(2026, time.January, 1)},
+			month: 9, year: 2026, want: 1,
+		},
+		{
+			name:  "monthly restricted to specific months",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 1, RecurrenceMonths: pq.Int64Array{6, 12}, StartsOn: date(2026, time.January, 1)},
+			month: 7, year: 2026, want: 0,
+		},
+		{
+			name:  "weekly falls on every Monday of the month",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyWeekly, RecurrenceInterval: 1, StartsOn: date(2026, time.March, 2)},
+			month: 3, year: 2026, want: 5,
+		},
+		{
+			name:  "weekly in a later month",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyWeekly, RecurrenceInterval: 1, StartsOn: date(2026, time.March, 2)},
+			month: 4, year: 2026, want: 4,
+		},
+		{
+			name:  "every two weeks",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyWeekly, RecurrenceInterval: 2, StartsOn: date(2026, time.March, 2)},
+			month: 4, year: 2026, want: 2,
+		},
+		{
+			name:  "weekly until an end date",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyWeekly, RecurrenceInterval: 1, StartsOn: date(2026, time.March, 2), EndsOn: date(2026, time.March, 20)},
+			month: 3, year: 2026, want: 3,
+		},
+		{
+			name:  "weekly with a number of occurrences",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyWeekly, RecurrenceInterval: 1, StartsOn: date(2026, time.March, 23), OccurrenceCount: count(3)},
+			month: 4, year: 2026, want: 1,
+		},
+		{
+			name:  "monthly within its occurrences",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 1, RecurrenceMonths: pq.Int64Array{1, 3, 5}, StartsOn: date(2026, time.January, 5), OccurrenceCount: count(3)},
+			month: 5, year: 2026, want: 1,
+		},
+		{
+			name:  "monthly after its occurrences",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 1, RecurrenceMonths: pq.Int64Array{1, 3, 5}, StartsOn: date(2026, time.January, 5), OccurrenceCount: count(3)},
+			month: 1, year: 2027, want: 0,
+		},
+		{
+			name:  "monthly after its end date",
+			entry: PlannedEntryModel{IsRecurrent: true, RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 1, StartsOn: date(2026, time.January, 5), EndsOn: date(2026, time.June, 30)},
+			month: 7, year: 2026, want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, plannedEntryOccurrences(tt.entry, tt.month, tt.year))
+		})
+	}
+}
+
+func TestCreatePlannedEntry_RejectsInvalidRecurrence(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	_, err := svc.CreatePlannedEntry(ctx, CreatePlannedEntryInput{OrganizationID: 1, IsRecurrent: true, RecurrenceFrequency: "daily"})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidRecurrence)
+
+	_, err = svc.CreatePlannedEntry(ctx, CreatePlannedEntryInput{OrganizationID: 1, IsRecurrent: true, RecurrenceMonths: []int{13}})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidRecurrence)
+
+	_, err = svc.CreatePlannedEntry(ctx, CreatePlannedEntryInput{OrganizationID: 1, IsRecurrent: true, StartsOn: "2026-05-01", EndsOn: "2026-04-01"})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidRecurrence)
+
+	mockRepo.AssertNotCalled(t, "InsertPlannedEntry", mock.Anything, mock.Anything)
+}
+
+func TestGenerateMonthlyInstances_DedupesByTargetMonth(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+	startsOn := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
+	parentID, april, year := 5, 4, 2026
+
+	mockRepo.On("FetchPlannedEntryByID", ctx, fetchPlannedEntryByIDParams{PlannedEntryID: 5, UserID: 1, OrganizationID: 1}).
+		Return(PlannedEntryModel{PlannedEntryID: 5, CategoryID: 3, Description: "Seguro", IsRecurrent: true,
+			RecurrenceFrequency: RecurrenceFrequencyMonthly, RecurrenceInterval: 1, StartsOn: &startsOn,
+			EntryType: PlannedEntryTypeExpense}, nil)
+	// Generated in March for April
+	mockRepo.On("FetchPlannedEntriesByParent", ctx, fetchPlannedEntriesByParentParams{ParentEntryID: 5, UserID: 1, OrganizationID: 1}).
+		Return([]PlannedEntryModel{{PlannedEntryID: 6, ParentEntryID: &parentID, IsActive: true, TargetMonth: &april, TargetYear: &year,
+			CreatedAt: time.Date(2026, time.March, 28, 0, 0, 0, 0, time.UTC)}}, nil)
+	mockRepo.On("InsertPlannedEntry", ctx, mock.MatchedBy(func(params insertPlannedEntryParams) bool {
+		return *params.ParentEntryID == 5 && *params.TargetMonth == 3 && *params.TargetYear == 2026
+	})).Return(PlannedEntryModel{PlannedEntryID: 7}, nil).Once()
+
+	instances, err := svc.GenerateMonthlyInstances(ctx, GenerateMonthlyInstancesInput{ParentEntryID: 5, UserID: 1, OrganizationID: 1, Month: 3, Year: 2026})
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+
+	_, err = svc.GenerateMonthlyInstances(ctx, GenerateMonthlyInstancesInput{ParentEntryID: 5, UserID: 1, OrganizationID: 1, Month: 4, Year: 2026})
+	assert.EqualError(t, err, "instance already exists for this month")
+
+	_, err = svc.GenerateMonthlyInstances(ctx, GenerateMonthlyInstancesInput{ParentEntryID: 5, UserID: 1, OrganizationID: 1, Month: 12, Year: 2025})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidRecurrence)
+	mockRepo.AssertExpectations(t)
+}
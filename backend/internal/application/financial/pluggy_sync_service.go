@@ -0,0 +1,322 @@
+package financial
+
+import (
+	"context"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/internal/integrations/pluggy"
+	"github.com/catrutech/celeiro/pkg/errors"
+)
+
+const (
+	// pluggyInitialSyncDays is how far back the first sync of a link reaches.
+	pluggyInitialSyncDays = 90
+
+	// pluggySyncOverlapDays re-reads the days before the cursor on every sync so
+	// transactions that were still pending at the previous sync are imported
+	// once they post. Re-imports are deduplicated by FITID.
+	pluggySyncOverlapDays = 7
+
+	pluggyFitIDPrefix = "PLUGGY-"
+
+	PluggySyncStatusNever   = "never"
+	PluggySyncStatusSuccess = "success"
+	PluggySyncStatusError   = "error"
+)
+
+// pluggySource is the subset of the Pluggy client used by the sync.
+type pluggySource interface {
+	IsConfigured() bool
+	FetchAccounts(ctx context.Context, itemID string) ([]pluggy.Account, error)
+	FetchTransactions(ctx context.Context, accountID string, from, to time.Time, page int) (pluggy.TransactionsPage, error)
+}
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetPluggyAccountLinksInput struct {
+	OrganizationID int
+	AccountID      *int
+}
+
+type LinkPluggyAccountInput struct {
+	UserID          int
+	OrganizationID  int
+	AccountID       int
+	PluggyItemID    string
+	PluggyAccountID string
+}
+
+type UnlinkPluggyAccountInput struct {
+	PluggyAccountLinkID int
+	OrganizationID      int
+}
+
+type SyncPluggyAccountInput struct {
+	PluggyAccountLinkID int
+	OrganizationID      int
+}
+
+type SyncPluggyAccountOutput struct {
+	Link              PluggyAccountLink `json:"link"`
+	FetchedCount      int               `json:"fetched_count"`
+	ImportedCount     int               `json:"imported_count"`
+	SkippedCount      int               `json:"skipped_count"` // pending, or dated in a closed month
+	PatternMatchCount int               `json:"pattern_match_count"`
+	RuleMatchCount    int               `json:"rule_match_count"`
+}
+
+// ============================================================================
+// Pluggy Account Links
+// ============================================================================
+
+func (s *service) GetPluggyAccountLinks(ctx context.Context, input GetPluggyAccountLinksInput) ([]PluggyAccountLink, error) {
+	links, err := s.Repository.FetchPluggyAccountLinks(ctx, fetchPluggyAccountLinksParams{
+		OrganizationID: input.OrganizationID,
+		AccountID:      input.AccountID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch pluggy account links")
+	}
+
+	return PluggyAccountLinks{}.FromModel(links), nil
+}
+
+// LinkPluggyAccount links a Pluggy account to a Celeiro account. Linking an
+// already-linked Pluggy account moves it to the new Celeiro account.
+func (s *service) LinkPluggyAccount(ctx context.Context, input LinkPluggyAccountInput) (PluggyAccountLink, error) {
+	if input.PluggyItemID == "" || input.PluggyAccountID == "" {
+		return PluggyAccountLink{}, internalerrors.ErrMissingRequiredFields
+	}
+
+	if _, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+		AccountID:      input.AccountID,
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+	}); err != nil {
+		return PluggyAccountLink{}, errors.Wrap(err, "account not found or access denied")
+	}
+
+	if err := s.ensurePluggyConfigured(); err != nil {
+		return PluggyAccountLink{}, err
+	}
+
+	pluggyAccounts, err := s.pluggy.FetchAccounts(ctx, input.PluggyItemID)
+	if err != nil {
+		return PluggyAccountLink{}, errors.Wrap(err, "failed to fetch pluggy accounts")
+	}
+	found := false
+	for _, account := range pluggyAccounts {
+		if account.ID == input.PluggyAccountID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return PluggyAccountLink{}, internalerrors.ErrPluggyAccountNotFound
+	}
+
+	link, err := s.Repository.InsertPluggyAccountLink(ctx, insertPluggyAccountLinkParams{
+		UserID:          input.UserID,
+		OrganizationID:  input.OrganizationID,
+		AccountID:       input.AccountID,
+		PluggyItemID:    input.PluggyItemID,
+		PluggyAccountID: input.PluggyAccountID,
+	})
+	if err != nil {
+		return PluggyAccountLink{}, errors.Wrap(err, "failed to link pluggy account")
+	}
+
+	return PluggyAccountLink{}.FromModel(&link), nil
+}
+
+func (s *service) UnlinkPluggyAccount(ctx context.Context, input UnlinkPluggyAccountInput) error {
+	err := s.Repository.RemovePluggyAccountLink(ctx, removePluggyAccountLinkParams{
+		PluggyAccountLinkID: input.PluggyAccountLinkID,
+		OrganizationID:      input.OrganizationID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to unlink pluggy account")
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Pluggy Sync
+// ============================================================================
+
+// SyncPluggyAccount pulls the transactions of a linked Pluggy account since its
+// sync cursor into the linked Celeiro account. Transactions go through the same
+// FITID dedupe path and pattern auto-apply as OFX imports. The outcome, failed
+// or not, is recorded on the link so it shows up in the sync status.
+func (s *service) SyncPluggyAccount(ctx context.Context, input SyncPluggyAccountInput) (SyncPluggyAccountOutput, error) {
+	link, err := s.Repository.FetchPluggyAccountLinkByID(ctx, fetchPluggyAccountLinkByIDParams{
+		PluggyAccountLinkID: input.PluggyAccountLinkID,
+		OrganizationID:      input.OrganizationID,
+	})
+	if err != nil {
+		return SyncPluggyAccountOutput{}, errors.Wrap(err, "pluggy account link not found")
+	}
+
+	if err := s.ensurePluggyConfigured(); err != nil {
+		return SyncPluggyAccountOutput{}, err
+	}
+
+	output, syncedUntil, syncErr := s.syncPluggyAccountLink(ctx, &link)
+
+	status := PluggySyncStatusSuccess
+	var syncCursor *time.Time
+	var lastError *string
+	if syncErr != nil {
+		status = PluggySyncStatusError
+		message := syncErr.Error()
+		lastError = &message
+	} else {
+		syncCursor = &syncedUntil
+	}
+
+	updated, err := s.Repository.ModifyPluggyAccountLinkSync(ctx, modifyPluggyAccountLinkSyncParams{
+		PluggyAccountLinkID: link.PluggyAccountLinkID,
+		OrganizationID:      link.OrganizationID,
+		SyncCursor:          syncCursor,
+		LastSyncStatus:      status,
+		LastSyncError:       lastError,
+		LastImportedCount:   output.ImportedCount,
+	})
+	if err != nil {
+		return SyncPluggyAccountOutput{}, errors.Wrap(err, "failed to record pluggy sync status")
+	}
+
+	if syncErr != nil {
+		return SyncPluggyAccountOutput{}, syncErr
+	}
+
+	output.Link = PluggyAccountLink{}.FromModel(&updated)
+	return output, nil
+}
+
+func (s *service) syncPluggyAccountLink(ctx context.Context, link *PluggyAccountLinkModel) (SyncPluggyAccountOutput, time.Time, error) {
+	var output SyncPluggyAccountOutput
+
+	now := s.system.Time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -pluggyInitialSyncDays)
+	if link.SyncCursor != nil {
+		from = link.SyncCursor.AddDate(0, 0, -pluggySyncOverlapDays)
+	}
+
+	var fetched []pluggy.Transaction
+	for page := 1; ; page++ {
+		resp, err := s.pluggy.FetchTransactions(ctx, link.PluggyAccountID, from, to, page)
+		if err != nil {
+			return output, time.Time{}, errors.Wrap(err, "failed to fetch pluggy transactions")
+		}
+		fetched = append(fetched, resp.Results...)
+		if page >= resp.TotalPages {
+			break
+		}
+	}
+	output.FetchedCount = len(fetched)
+
+	insertParams := make([]insertTransactionParams, 0, len(fetched))
+	closedDays := make(map[time.Time]bool)
+	for _, tx := range fetched {
+		if tx.Status == "PENDING" {
+			output.SkippedCount++
+			continue
+		}
+
+		day := truncateToDay(tx.Date)
+		closed, checked := closedDays[day]
+		if !checked {
+			var err error
+			closed, err = s.Repository.IsTransactionMonthClosed(ctx, transactionMonthClosureParams{
+				OrganizationID:  link.OrganizationID,
+				AccountID:       link.AccountID,
+				TransactionDate: day,
+			})
+			if err != nil {
+				return output, time.Time{}, errors.Wrap(err, "failed to check month closure")
+			}
+			closedDays[day] = closed
+		}
+		if closed {
+			output.SkippedCount++
+			continue
+		}
+
+		insertParams = append(insertParams, pluggyTransactionToInsertParams(link.AccountID, tx))
+	}
+
+	inserted, err := s.Repository.BulkInsertTransactions(ctx, bulkInsertTransactionsParams{
+		Transactions: insertParams,
+	})
+	if err != nil {
+		return output, time.Time{}, errors.Wrap(err, "failed to insert transactions")
+	}
+	output.ImportedCount = len(inserted)
+
+	classified := s.classifyImportedTransactions(ctx, inserted, link.UserID, link.OrganizationID)
+	output.PatternMatchCount = classified.PatternMatchCount
+	output.RuleMatchCount = classified.RuleMatchCount
+
+	s.logger.Info(ctx, "Pluggy sync completed",
+		"pluggy_account_link_id", link.PluggyAccountLinkID,
+		"account_id", link.AccountID,
+		"from", from.Format("2006-01-02"),
+		"to", to.Format("2006-01-02"),
+		"fetched", output.FetchedCount,
+		"imported", output.ImportedCount,
+		"skipped", output.SkippedCount,
+		"pattern_matched", output.PatternMatchCount,
+		"rule_matched", output.RuleMatchCount,
+	)
+
+	return output, to, nil
+}
+
+func (s *service) ensurePluggyConfigured() error {
+	if s.pluggy == nil || !s.pluggy.IsConfigured() {
+		return internalerrors.ErrPluggyNotConfigured
+	}
+	return nil
+}
+
+// pluggyTransactionToInsertParams maps a Pluggy transaction onto the same
+// insert params an OFX import produces. The Pluggy transaction ID becomes the
+// FITID so repeated syncs are deduplicated by the transactions upsert.
+func pluggyTransactionToInsertParams(accountID int, tx pluggy.Transaction) insertTransactionParams {
+	description := tx.Description
+	if description == "" && tx.DescriptionRaw != nil {
+		description = *tx.DescriptionRaw
+	}
+	description = cleanDescription(description)
+
+	transactionType := TransactionTypeDebit
+	switch tx.Type {
+	case "CREDIT":
+		transactionType = TransactionTypeCredit
+	case "DEBIT":
+		transactionType = TransactionTypeDebit
+	default:
+		if tx.Amount.IsPositive() {
+			transactionType = TransactionTypeCredit
+		}
+	}
+
+	fitID := pluggyFitIDPrefix + tx.ID
+
+	return insertTransactionParams{
+		AccountID:           accountID,
+		Description:         description,
+		OriginalDescription: description,
+		Amount:              tx.Amount.Abs(),
+		TransactionDate:     tx.Date.Format(time.RFC3339),
+		TransactionType:     transactionType,
+		OFXFitID:            &fitID,
+		OFXMemo:             tx.DescriptionRaw,
+	}
+}
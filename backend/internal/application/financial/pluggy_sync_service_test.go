@@ -0,0 +1,92 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/catrutech/celeiro/internal/integrations/pluggy"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePluggySource struct {
+	accounts []pluggy.Account
+	pages    []pluggy.TransactionsPage
+	from, to time.Time
+}
+
+func (f *fakePluggySource) IsConfigured() bool { return true }
+
+func (f *fakePluggySource) FetchAccounts(ctx context.Context, itemID string) ([]pluggy.Account, error) {
+	return f.accounts, nil
+}
+
+func (f *fakePluggySource) FetchTransactions(ctx context.Context, accountID string, from, to time.Time, page int) (pluggy.TransactionsPage, error) {
+	f.from, f.to = from, to
+	return f.pages[page-1], nil
+}
+
+func TestSyncPluggyAccount_ImportsPostedTransactionsAndAdvancesCursor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	stub := system.NewStubSystem()
+	stub.Time.SetTimes(time.Date(2026, time.March, 20, 15, 0, 0, 0, time.UTC))
+	source := &fakePluggySource{pages: []pluggy.TransactionsPage{
+		{TotalPages: 2, Results: []pluggy.Transaction{
+			{ID: "tx-1", Description: "PIX MERCADO", Amount: decimal.NewFromFloat(-42.5), Type: "DEBIT", Status: "POSTED",
+				Date: time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)},
+			{ID: "tx-2", Description: "PENDENTE", Amount: decimal.NewFromInt(-10), Type: "DEBIT", Status: "PENDING",
+				Date: time.Date(2026, time.March, 19, 0, 0, 0, 0, time.UTC)},
+		}},
+		{TotalPages: 2, Results: []pluggy.Transaction{
+			{ID: "tx-3", Description: "SALARIO", Amount: decimal.NewFromInt(5000), Type: "CREDIT", Status: "POSTED",
+				Date: time.Date(2026, time.February, 5, 0, 0, 0, 0, time.UTC)},
+		}},
+	}}
+	svc := &service{Repository: mockRepo, system: stub.ToSystem(), logger: &logging.TestLogger{}, pluggy: source}
+	ctx := context.Background()
+
+	cursor := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	link := PluggyAccountLinkModel{PluggyAccountLinkID: 3, UserID: 10, OrganizationID: 9, AccountID: 7,
+		PluggyAccountID: "acc-1", SyncCursor: &cursor}
+
+	mockRepo.On("FetchPluggyAccountLinkByID", ctx, fetchPluggyAccountLinkByIDParams{PluggyAccountLinkID: 3, OrganizationID: 9}).Return(link, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, transactionMonthClosureParams{
+		OrganizationID: 9, AccountID: 7, TransactionDate: time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC),
+	}).Return(false, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, transactionMonthClosureParams{
+		OrganizationID: 9, AccountID: 7, TransactionDate: time.Date(2026, time.February, 5, 0, 0, 0, 0, time.UTC),
+	}).Return(true, nil)
+	mockRepo.On("BulkInsertTransactions", ctx, mock.MatchedBy(func(params bulkInsertTransactionsParams) bool {
+		return len(params.Transactions) == 1 &&
+			*params.Transactions[0].OFXFitID == "PLUGGY-tx-1" &&
+			params.Transactions[0].AccountID == 7 &&
+			params.Transactions[0].TransactionType == TransactionTypeDebit &&
+			params.Transactions[0].Amount.Equal(decimal.NewFromFloat(42.5))
+	})).Return([]TransactionModel{{TransactionID: 55}}, nil)
+	mockRepo.On("FetchTransactionByID", ctx, fetchTransactionByIDParams{TransactionID: 55, OrganizationID: 9}).Return(TransactionModel{TransactionID: 55}, nil)
+	mockRepo.On("FetchAdvancedPatterns", ctx, mock.Anything).Return([]AdvancedPatternModel{}, nil)
+	mockRepo.On("FetchClassificationRules", ctx, mock.Anything).Return([]ClassificationRuleModel{}, nil)
+	syncedUntil := time.Date(2026, time.March, 20, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("ModifyPluggyAccountLinkSync", ctx, modifyPluggyAccountLinkSyncParams{
+		PluggyAccountLinkID: 3,
+		OrganizationID:      9,
+		SyncCursor:          &syncedUntil,
+		LastSyncStatus:      PluggySyncStatusSuccess,
+		LastImportedCount:   1,
+	}).Return(PluggyAccountLinkModel{PluggyAccountLinkID: 3, LastSyncStatus: PluggySyncStatusSuccess}, nil)
+
+	result, err := svc.SyncPluggyAccount(ctx, SyncPluggyAccountInput{PluggyAccountLinkID: 3, OrganizationID: 9})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.FetchedCount)
+	assert.Equal(t, 1, result.ImportedCount)
+	assert.Equal(t, 2, result.SkippedCount)
+	assert.Equal(t, PluggySyncStatusSuccess, result.Link.LastSyncStatus)
+	assert.Equal(t, "2026-02-22", source.from.Format("2006-01-02"))
+	mockRepo.AssertExpectations(t)
+}
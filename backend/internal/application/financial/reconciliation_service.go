@@ -0,0 +1,193 @@
+package financial
+
+import (
+	"context"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetAccountReconciliationInput struct {
+	AccountID      int
+	UserID         int
+	OrganizationID int
+}
+
+type RecordStatementBalanceInput struct {
+	AccountID      int
+	UserID         int
+	OrganizationID int
+	BalanceDate    string // YYYY-MM-DD, end of day
+	Balance        decimal.Decimal
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// GetAccountReconciliation compares every statement balance recorded for the
+// account with the computed balance on the same day, and lists the
+// transactions between the last matching statement and the first one after it
+// that does not match.
+func (s *service) GetAccountReconciliation(ctx context.Context, params GetAccountReconciliationInput) (AccountReconciliation, error) {
+	account, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+		AccountID:      params.AccountID,
+		UserID:         params.UserID,
+		OrganizationID: params.OrganizationID,
+	})
+	if err != nil {
+		return AccountReconciliation{}, errors.Wrap(err, "failed to fetch account")
+	}
+
+	balances, err := s.Repository.FetchAccountStatementBalances(ctx, fetchAccountStatementBalancesParams{
+		AccountID:      params.AccountID,
+		OrganizationID: params.OrganizationID,
+	})
+	if err != nil {
+		return AccountReconciliation{}, errors.Wrap(err, "failed to fetch statement balances")
+	}
+
+	checkpoints := ReconciliationCheckpoints{}.FromModel(balances)
+	divergence, err := s.findReconciliationDivergence(ctx, &account, checkpoints)
+	if err != nil {
+		return AccountReconciliation{}, err
+	}
+
+	return AccountReconciliation{
+		AccountID:          account.AccountID,
+		Balance:            account.Balance,
+		OpeningBalance:     account.OpeningBalance,
+		OpeningBalanceDate: account.OpeningBalanceDate,
+		ReconciledThrough:  account.ReconciledThrough,
+		Checkpoints:        checkpoints,
+		Divergence:         divergence,
+	}, nil
+}
+
+// RecordStatementBalance stores a balance read off a bank statement and
+// reconciles the account against it.
+func (s *service) RecordStatementBalance(ctx context.Context, params RecordStatementBalanceInput) (ReconciliationCheckpoint, error) {
+	balanceDate, err := time.Parse("2006-01-02", params.BalanceDate)
+	if err != nil {
+		return ReconciliationCheckpoint{}, internalerrors.NewInvalidTimeFormatError("balance_date")
+	}
+
+	if _, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+		AccountID:      params.AccountID,
+		UserID:         params.UserID,
+		OrganizationID: params.OrganizationID,
+	}); err != nil {
+		return ReconciliationCheckpoint{}, errors.Wrap(err, "failed to fetch account")
+	}
+
+	return s.reconcileStatementBalance(ctx, params.AccountID, params.OrganizationID,
+		balanceDate, params.Balance, StatementBalanceSourceManual)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// reconcileStatementBalance records the statement balance and, when the
+// computed balance for that day matches, moves the account's reconciled
+// marker up to it.
+func (s *service) reconcileStatementBalance(ctx context.Context, accountID, organizationID int, balanceDate time.Time, balance decimal.Decimal, source string) (ReconciliationCheckpoint, error) {
+	model, err := s.Repository.UpsertAccountStatementBalance(ctx, upsertAccountStatementBalanceParams{
+		AccountID:      accountID,
+		OrganizationID: organizationID,
+		BalanceDate:    balanceDate,
+		Balance:        balance,
+		Source:         source,
+	})
+	if err != nil {
+		return ReconciliationCheckpoint{}, errors.Wrap(err, "failed to record statement balance")
+	}
+
+	checkpoint := ReconciliationCheckpoint{}.FromModel(&model)
+	if checkpoint.Matches {
+		if err := s.Repository.ModifyAccountReconciledThrough(ctx, modifyAccountReconciledThroughParams{
+			AccountID:         accountID,
+			OrganizationID:    organizationID,
+			ReconciledThrough: balanceDate,
+		}); err != nil {
+			return ReconciliationCheckpoint{}, errors.Wrap(err, "failed to mark account reconciled")
+		}
+	}
+
+	return checkpoint, nil
+}
+
+// findReconciliationDivergence returns nil when the latest checkpoint matches
+// (or there are none). Earlier mismatches that a later statement agrees with
+// again are usually pending transactions and are not reported.
+func (s *service) findReconciliationDivergence(ctx context.Context, account *AccountModel, checkpoints []ReconciliationCheckpoint) (*ReconciliationDivergence, error) {
+	lastMatch := -1
+	for i, checkpoint := range checkpoints {
+		if checkpoint.Matches {
+			lastMatch = i
+		}
+	}
+	if lastMatch == len(checkpoints)-1 {
+		return nil, nil
+	}
+
+	mismatch := checkpoints[lastMatch+1]
+	divergence := ReconciliationDivergence{
+		Through:         mismatch.BalanceDate,
+		StartingBalance: account.OpeningBalance,
+		Difference:      mismatch.Difference,
+	}
+	if lastMatch >= 0 {
+		after := checkpoints[lastMatch].BalanceDate
+		divergence.After = &after
+		divergence.StartingBalance = checkpoints[lastMatch].ComputedBalance
+	}
+
+	transactions, err := s.Repository.FetchAccountTransactionsBetween(ctx, fetchAccountTransactionsBetweenParams{
+		AccountID:      account.AccountID,
+		OrganizationID: account.OrganizationID,
+		After:          divergence.After,
+		Through:        divergence.Through,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transactions for reconciliation")
+	}
+
+	running := divergence.StartingBalance
+	divergence.Entries = make([]LedgerEntry, len(transactions))
+	for i, model := range transactions {
+		if model.TransactionType == TransactionTypeCredit {
+			running = running.Add(model.Amount.Abs())
+		} else {
+			running = running.Sub(model.Amount.Abs())
+		}
+		divergence.Entries[i] = LedgerEntry{
+			Transaction:    Transaction{}.FromModel(&model),
+			RunningBalance: running,
+		}
+	}
+
+	return &divergence, nil
+}
+
+// statementBalanceDate is the day an OFX ledger balance refers to: its DTASOF,
+// falling back to the statement end date.
+func statementBalanceDate(statement *OFXStatement) (time.Time, bool) {
+	if statement.LedgerBalance == nil {
+		return time.Time{}, false
+	}
+	asOf := statement.LedgerBalance.AsOf
+	if asOf.IsZero() {
+		asOf = statement.EndDate
+	}
+	if asOf.IsZero() {
+		return time.Time{}, false
+	}
+	return time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC), true
+}
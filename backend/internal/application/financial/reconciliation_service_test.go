@@ -0,0 +1,108 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountReconciliation_ReportsWindowAfterLastMatch(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	september := time.Date(2025, time.September, 30, 0, 0, 0, 0, time.UTC)
+	october := time.Date(2025, time.October, 31, 0, 0, 0, 0, time.UTC)
+	reconciledThrough := september
+
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{
+		AccountID:         4,
+		OrganizationID:    1,
+		Balance:           decimal.NewFromInt(900),
+		OpeningBalance:    decimal.NewFromInt(100),
+		ReconciledThrough: &reconciledThrough,
+	}, nil)
+	mockRepo.On("FetchAccountStatementBalances", ctx, fetchAccountStatementBalancesParams{AccountID: 4, OrganizationID: 1}).
+		Return([]AccountStatementBalanceModel{
+			{BalanceDate: september, Balance: decimal.NewFromInt(1000), ComputedBalance: decimal.NewFromInt(1000), Source: StatementBalanceSourceOFX},
+			{BalanceDate: october, Balance: decimal.NewFromInt(850), ComputedBalance: decimal.NewFromInt(900), Source: StatementBalanceSourceOFX},
+		}, nil)
+	mockRepo.On("FetchAccountTransactionsBetween", ctx, fetchAccountTransactionsBetweenParams{
+		AccountID:      4,
+		OrganizationID: 1,
+		After:          &september,
+		Through:        october,
+	}).Return([]TransactionModel{
+		{TransactionID: 1, Amount: decimal.NewFromInt(150), TransactionType: TransactionTypeDebit},
+		{TransactionID: 2, Amount: decimal.NewFromInt(50), TransactionType: TransactionTypeCredit},
+		{TransactionID: 3, Amount: decimal.NewFromInt(-50), TransactionType: TransactionTypeCredit},
+	}, nil)
+
+	report, err := svc.GetAccountReconciliation(ctx, GetAccountReconciliationInput{AccountID: 4, UserID: 2, OrganizationID: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, &reconciledThrough, report.ReconciledThrough)
+	require.Len(t, report.Checkpoints, 2)
+	assert.True(t, report.Checkpoints[0].Matches)
+	assert.False(t, report.Checkpoints[1].Matches)
+	assert.Equal(t, "50", report.Checkpoints[1].Difference.String())
+
+	require.NotNil(t, report.Divergence)
+	assert.Equal(t, september, *report.Divergence.After)
+	assert.Equal(t, october, report.Divergence.Through)
+	assert.Equal(t, "1000", report.Divergence.StartingBalance.String())
+	require.Len(t, report.Divergence.Entries, 3)
+	assert.Equal(t, "850", report.Divergence.Entries[0].RunningBalance.String())
+	assert.Equal(t, "900", report.Divergence.Entries[1].RunningBalance.String())
+	assert.Equal(t, "950", report.Divergence.Entries[2].RunningBalance.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetAccountReconciliation_NoDivergenceWhenLatestMatches(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{AccountID: 4, OrganizationID: 1}, nil)
+	mockRepo.On("FetchAccountStatementBalances", ctx, mock.Anything).Return([]AccountStatementBalanceModel{
+		{BalanceDate: time.Date(2025, time.August, 31, 0, 0, 0, 0, time.UTC), Balance: decimal.NewFromInt(10), ComputedBalance: decimal.NewFromInt(0)},
+		{BalanceDate: time.Date(2025, time.September, 30, 0, 0, 0, 0, time.UTC), Balance: decimal.NewFromInt(20), ComputedBalance: decimal.NewFromInt(20)},
+	}, nil)
+
+	report, err := svc.GetAccountReconciliation(ctx, GetAccountReconciliationInput{AccountID: 4, OrganizationID: 1})
+
+	require.NoError(t, err)
+	assert.Nil(t, report.Divergence)
+	mockRepo.AssertNotCalled(t, "FetchAccountTransactionsBetween", mock.Anything, mock.Anything)
+}
+
+func TestRecordStatementBalance_OnlyMarksReconciledWhenBalanceMatches(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{AccountID: 4}, nil)
+	mockRepo.On("UpsertAccountStatementBalance", ctx, mock.MatchedBy(func(params upsertAccountStatementBalanceParams) bool {
+		return params.Source == StatementBalanceSourceManual
+	})).Return(AccountStatementBalanceModel{
+		Balance:         decimal.RequireFromString("120.50"),
+		ComputedBalance: decimal.RequireFromString("120.00"),
+	}, nil)
+
+	checkpoint, err := svc.RecordStatementBalance(ctx, RecordStatementBalanceInput{
+		AccountID:      4,
+		OrganizationID: 1,
+		BalanceDate:    "2025-10-31",
+		Balance:        decimal.RequireFromString("120.50"),
+	})
+
+	require.NoError(t, err)
+	assert.False(t, checkpoint.Matches)
+	assert.Equal(t, "-0.5", checkpoint.Difference.String())
+	mockRepo.AssertNotCalled(t, "ModifyAccountReconciledThrough", mock.Anything, mock.Anything)
+}
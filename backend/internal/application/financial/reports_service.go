@@ -0,0 +1,655 @@
+package financial
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultReportMonths       = 12
+	maxReportMonths           = 120
+	defaultReportMerchants    = 10
+	maxReportMerchants        = 100
+	defaultReportTransactions = 100
+	maxReportTransactions     = 1000
+	reportMonthLayout         = "2006-01"
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetCategoryTrendsInput struct {
+	UserID         int
+	OrganizationID int
+	From           string
+	To             string
+	CategoryID     *int
+}
+
+type GetYearOverYearInput struct {
+	OrganizationID int
+	Year           int // Defaults to the current year
+	CompareYear    int // Defaults to the year before
+}
+
+type GetTopMerchantsInput struct {
+	OrganizationID int
+	From           string
+	To             string
+	Limit          int // Defaults to 10
+}
+
+type GetIncomeExpenseInput struct {
+	OrganizationID int
+	From           string
+	To             string
+}
+
+// GetReportTransactionsInput drills down into a report figure. CategoryID,
+// Uncategorized, Merchant and TransactionType narrow the transactions of the
+// range the way the report grouped them.
+type GetReportTransactionsInput struct {
+	OrganizationID  int
+	From            string
+	To              string
+	CategoryID      *int
+	Uncategorized   bool
+	Merchant        string
+	TransactionType string
+	Limit           int // Defaults to 100
+	Offset          int
+}
+
+type ReportMonthAmount struct {
+	Month         int              `json:"month"`
+	Year          int              `json:"year"`
+	Amount        decimal.Decimal  `json:"amount"`
+	PlannedAmount *decimal.Decimal `json:"planned_amount,omitempty"` // From the month's snapshot, once consolidated
+}
+
+// CategoryTrend is a category's spending in each month of the range; a nil
+// CategoryID stands for uncategorized transactions.
+type CategoryTrend struct {
+	CategoryID   *int                `json:"category_id"`
+	CategoryName string              `json:"category_name"`
+	Months       []ReportMonthAmount `json:"months"`
+	Total        decimal.Decimal     `json:"total"`
+	Average      decimal.Decimal     `json:"average"`
+	Median       decimal.Decimal     `json:"median"`
+}
+
+// CategoryTrendsReport leaves out of its amounts the transactions lacking an
+// exchange rate into the base currency, and counts them in MissingRates.
+type CategoryTrendsReport struct {
+	From         time.Time       `json:"from"`
+	To           time.Time       `json:"to"`
+	Currency     string          `json:"currency"`
+	Categories   []CategoryTrend `json:"categories"`
+	MissingRates int             `json:"missing_rates"`
+}
+
+type ReportComparison struct {
+	Amount        decimal.Decimal  `json:"amount"`
+	CompareAmount decimal.Decimal  `json:"compare_amount"`
+	Change        decimal.Decimal  `json:"change"`
+	ChangePercent *decimal.Decimal `json:"change_percent,omitempty"` // Unset when nothing was spent in the compared period
+}
+
+type MonthComparison struct {
+	Month int `json:"month"`
+	ReportComparison
+}
+
+type CategoryComparison struct {
+	CategoryID   *int   `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	ReportComparison
+}
+
+// YearOverYearReport compares spending month by month and category by
+// category. When Year is the current year both years count only through the
+// current month.
+type YearOverYearReport struct {
+	Year         int                  `json:"year"`
+	CompareYear  int                  `json:"compare_year"`
+	ThroughMonth int                  `json:"through_month"`
+	Currency     string               `json:"currency"`
+	Total        ReportComparison     `json:"total"`
+	Months       []MonthComparison    `json:"months"`
+	Categories   []CategoryComparison `json:"categories"`
+	MissingRates int                  `json:"missing_rates"` // Transactions of both years left out for lack of an exchange rate
+}
+
+type MerchantSpending struct {
+	Merchant            string          `json:"merchant"`
+	Total               decimal.Decimal `json:"total"`
+	TransactionCount    int             `json:"transaction_count"`
+	LastTransactionDate time.Time       `json:"last_transaction_date"`
+	MissingRates        int             `json:"missing_rates"` // Transactions left out of Total for lack of an exchange rate
+}
+
+type TopMerchantsReport struct {
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	Currency     string             `json:"currency"`
+	Merchants    []MerchantSpending `json:"merchants"`
+	MissingRates int                `json:"missing_rates"`
+}
+
+type IncomeExpenseMonth struct {
+	Month        int              `json:"month"`
+	Year         int              `json:"year"`
+	Income       decimal.Decimal  `json:"income"`
+	Expense      decimal.Decimal  `json:"expense"`
+	Net          decimal.Decimal  `json:"net"`
+	SavingsRate  *decimal.Decimal `json:"savings_rate,omitempty"` // Percent of income left; unset without income
+	MissingRates int              `json:"missing_rates"`          // Transactions left out for lack of an exchange rate
+}
+
+type IncomeExpenseReport struct {
+	From         time.Time            `json:"from"`
+	To           time.Time            `json:"to"`
+	Currency     string               `json:"currency"`
+	TotalIncome  decimal.Decimal      `json:"total_income"`
+	TotalExpense decimal.Decimal      `json:"total_expense"`
+	Net          decimal.Decimal      `json:"net"`
+	Months       []IncomeExpenseMonth `json:"months"`
+	MissingRates int                  `json:"missing_rates"`
+}
+
+type ReportTransaction struct {
+	TransactionID   int              `json:"transaction_id"`
+	SplitID         *int             `json:"split_id,omitempty"`
+	AccountID       int              `json:"account_id"`
+	TransactionDate time.Time        `json:"transaction_date"`
+	Month           int              `json:"month"`
+	Year            int              `json:"year"`
+	Description     string           `json:"description"`
+	CategoryID      *int             `json:"category_id"`
+	TransactionType string           `json:"transaction_type"`
+	Amount          *decimal.Decimal `json:"amount"` // Nil without an exchange rate into the base currency
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// GetCategoryTrends returns each category's monthly spending over the range,
+// with its total, average and median month.
+func (s *service) GetCategoryTrends(ctx context.Context, input GetCategoryTrendsInput) (CategoryTrendsReport, error) {
+	from, to, err := s.reportRange(input.From, input.To)
+	if err != nil {
+		return CategoryTrendsReport{}, err
+	}
+	baseCurrency, err := s.GetBaseCurrency(ctx, GetBaseCurrencyInput{OrganizationID: input.OrganizationID})
+	if err != nil {
+		return CategoryTrendsReport{}, err
+	}
+
+	spending, err := s.Repository.FetchReportCategorySpending(ctx, fetchReportCategorySpendingParams{
+		OrganizationID: input.OrganizationID,
+		From:           from,
+		To:             to,
+		CategoryID:     input.CategoryID,
+	})
+	if err != nil {
+		return CategoryTrendsReport{}, errors.Wrap(err, "failed to fetch category spending")
+	}
+	snapshots, err := s.Repository.FetchMonthlySnapshots(ctx, fetchMonthlySnapshotsParams{
+		UserID:         input.UserID,
+		OrganizationID: input.OrganizationID,
+		CategoryID:     input.CategoryID,
+	})
+	if err != nil {
+		return CategoryTrendsReport{}, errors.Wrap(err, "failed to fetch monthly snapshots")
+	}
+	names, err := s.reportCategoryNames(ctx, input.OrganizationID)
+	if err != nil {
+		return CategoryTrendsReport{}, err
+	}
+
+	planned := make(map[reportCategoryKey]map[time.Time]decimal.Decimal)
+	for _, snapshot := range snapshots {
+		month := reportMonth(snapshot.Month, snapshot.Year)
+		if month.Before(from) || month.After(to) {
+			continue
+		}
+		key := reportCategoryKeyOf(&snapshot.CategoryID)
+		if planned[key] == nil {
+			planned[key] = make(map[time.Time]decimal.Decimal)
+		}
+		planned[key][month] = snapshot.PlannedAmount
+	}
+
+	spent := make(map[reportCategoryKey]map[time.Time]decimal.Decimal)
+	missingRates := 0
+	for _, row := range spending {
+		missingRates += row.MissingRates
+		key := reportCategoryKeyOf(row.CategoryID)
+		if spent[key] == nil {
+			spent[key] = make(map[time.Time]decimal.Decimal)
+		}
+		spent[key][reportMonth(row.Month, row.Year)] = row.Amount
+	}
+
+	keys := make([]reportCategoryKey, 0, len(spent))
+	for key := range spent {
+		keys = append(keys, key)
+	}
+	for key := range planned {
+		if _, ok := spent[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	report := CategoryTrendsReport{
+		From:         from,
+		To:           to,
+		Currency:     baseCurrency,
+		Categories:   make([]CategoryTrend, 0, len(keys)),
+		MissingRates: missingRates,
+	}
+	months := reportMonths(from, to)
+	for _, key := range keys {
+		trend := CategoryTrend{
+			CategoryID:   key.categoryID(),
+			CategoryName: names[key],
+			Months:       make([]ReportMonthAmount, 0, len(months)),
+		}
+		amounts := make([]decimal.Decimal, 0, len(months))
+		for _, month := range months {
+			amount := spent[key][month]
+			item := ReportMonthAmount{Month: int(month.Month()), Year: month.Year(), Amount: amount}
+			if plannedAmount, ok := planned[key][month]; ok {
+				item.PlannedAmount = &plannedAmount
+			}
+			trend.Months = append(trend.Months, item)
+			trend.Total = trend.Total.Add(amount)
+			amounts = append(amounts, amount)
+		}
+		trend.Average = trend.Total.Div(decimal.NewFromInt(int64(len(months)))).Round(2)
+		trend.Median = medianAmount(amounts)
+		report.Categories = append(report.Categories, trend)
+	}
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		if !report.Categories[i].Total.Equal(report.Categories[j].Total) {
+			return report.Categories[i].Total.GreaterThan(report.Categories[j].Total)
+		}
+		return report.Categories[i].CategoryName < report.Categories[j].CategoryName
+	})
+
+	return report, nil
+}
+
+// GetYearOverYear compares a year's spending with another's.
+func (s *service) GetYearOverYear(ctx context.Context, input GetYearOverYearInput) (YearOverYearReport, error) {
+	now := s.system.Time.Now()
+	year := input.Year
+	if year == 0 {
+		year = now.Year()
+	}
+	compareYear := input.CompareYear
+	if compareYear == 0 {
+		compareYear = year - 1
+	}
+	if year > now.Year() || compareYear > now.Year() {
+		return YearOverYearReport{}, errors.Wrap(internalerrors.ErrInvalidReportRange, "cannot compare years still to come")
+	}
+	if year == compareYear {
+		return YearOverYearReport{}, errors.Wrap(internalerrors.ErrInvalidReportRange, "cannot compare a year with itself")
+	}
+
+	throughMonth := 12
+	if year == now.Year() || compareYear == now.Year() {
+		throughMonth = int(now.Month())
+	}
+
+	baseCurrency, err := s.GetBaseCurrency(ctx, GetBaseCurrencyInput{OrganizationID: input.OrganizationID})
+	if err != nil {
+		return YearOverYearReport{}, err
+	}
+	current, err := s.Repository.FetchReportCategorySpending(ctx, fetchReportCategorySpendingParams{
+		OrganizationID: input.OrganizationID,
+		From:           reportMonth(1, year),
+		To:             reportMonth(throughMonth, year),
+	})
+	if err != nil {
+		return YearOverYearReport{}, errors.Wrap(err, "failed to fetch category spending")
+	}
+	compared, err := s.Repository.FetchReportCategorySpending(ctx, fetchReportCategorySpendingParams{
+		OrganizationID: input.OrganizationID,
+		From:           reportMonth(1, compareYear),
+		To:             reportMonth(throughMonth, compareYear),
+	})
+	if err != nil {
+		return YearOverYearReport{}, errors.Wrap(err, "failed to fetch category spending")
+	}
+	names, err := s.reportCategoryNames(ctx, input.OrganizationID)
+	if err != nil {
+		return YearOverYearReport{}, err
+	}
+
+	byMonth := make([]ReportComparison, throughMonth)
+	byCategory := make(map[reportCategoryKey]*ReportComparison)
+	category := func(categoryID *int) *ReportComparison {
+		key := reportCategoryKeyOf(categoryID)
+		if byCategory[key] == nil {
+			byCategory[key] = &ReportComparison{}
+		}
+		return byCategory[key]
+	}
+	var total ReportComparison
+	missingRates := 0
+	for _, row := range current {
+		missingRates += row.MissingRates
+		byMonth[row.Month-1].Amount = byMonth[row.Month-1].Amount.Add(row.Amount)
+		category(row.CategoryID).Amount = category(row.CategoryID).Amount.Add(row.Amount)
+		total.Amount = total.Amount.Add(row.Amount)
+	}
+	for _, row := range compared {
+		missingRates += row.MissingRates
+		byMonth[row.Month-1].CompareAmount = byMonth[row.Month-1].CompareAmount.Add(row.Amount)
+		category(row.CategoryID).CompareAmount = category(row.CategoryID).CompareAmount.Add(row.Amount)
+		total.CompareAmount = total.CompareAmount.Add(row.Amount)
+	}
+
+	report := YearOverYearReport{
+		Year:         year,
+		CompareYear:  compareYear,
+		ThroughMonth: throughMonth,
+		Currency:     baseCurrency,
+		Total:        withChange(total),
+		Months:       make([]MonthComparison, 0, throughMonth),
+		Categories:   make([]CategoryComparison, 0, len(byCategory)),
+		MissingRates: missingRates,
+	}
+	for i, comparison := range byMonth {
+		report.Months = append(report.Months, MonthComparison{Month: i + 1, ReportComparison: withChange(comparison)})
+	}
+	for key, comparison := range byCategory {
+		report.Categories = append(report.Categories, CategoryComparison{
+			CategoryID:       key.categoryID(),
+			CategoryName:     names[key],
+			ReportComparison: withChange(*comparison),
+		})
+	}
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		if !report.Categories[i].Amount.Equal(report.Categories[j].Amount) {
+			return report.Categories[i].Amount.GreaterThan(report.Categories[j].Amount)
+		}
+		return report.Categories[i].CategoryName < report.Categories[j].CategoryName
+	})
+
+	return report, nil
+}
+
+// GetTopMerchants returns where the most was spent over the range, telling
+// merchants apart by the transaction description.
+func (s *service) GetTopMerchants(ctx context.Context, input GetTopMerchantsInput) (TopMerchantsReport, error) {
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultReportMerchants
+	}
+	if limit < 1 || limit > maxReportMerchants {
+		return TopMerchantsReport{}, errors.Wrap(internalerrors.ErrInvalidReportRange, "limit must be between 1 and %d", maxReportMerchants)
+	}
+	from, to, err := s.reportRange(input.From, input.To)
+	if err != nil {
+		return TopMerchantsReport{}, err
+	}
+	baseCurrency, err := s.GetBaseCurrency(ctx, GetBaseCurrencyInput{OrganizationID: input.OrganizationID})
+	if err != nil {
+		return TopMerchantsReport{}, err
+	}
+
+	models, err := s.Repository.FetchReportMerchants(ctx, fetchReportMerchantsParams{
+		OrganizationID: input.OrganizationID,
+		From:           from,
+		To:             to,
+		Limit:          limit,
+	})
+	if err != nil {
+		return TopMerchantsReport{}, errors.Wrap(err, "failed to fetch merchants")
+	}
+
+	report := TopMerchantsReport{
+		From:      from,
+		To:        to,
+		Currency:  baseCurrency,
+		Merchants: make([]MerchantSpending, 0, len(models)),
+	}
+	for _, model := range models {
+		report.Merchants = append(report.Merchants, MerchantSpending{
+			Merchant:            model.Merchant,
+			Total:               model.Total,
+			TransactionCount:    model.TransactionCount,
+			LastTransactionDate: model.LastTransactionDate,
+			MissingRates:        model.MissingRates,
+		})
+		report.MissingRates += model.MissingRates
+	}
+	return report, nil
+}
+
+// GetIncomeExpense returns what came in and went out in each month of the
+// range.
+func (s *service) GetIncomeExpense(ctx context.Context, input GetIncomeExpenseInput) (IncomeExpenseReport, error) {
+	from, to, err := s.reportRange(input.From, input.To)
+	if err != nil {
+		return IncomeExpenseReport{}, err
+	}
+	baseCurrency, err := s.GetBaseCurrency(ctx, GetBaseCurrencyInput{OrganizationID: input.OrganizationID})
+	if err != nil {
+		return IncomeExpenseReport{}, err
+	}
+
+	models, err := s.Repository.FetchReportIncomeExpense(ctx, fetchReportIncomeExpenseParams{
+		OrganizationID: input.OrganizationID,
+		From:           from,
+		To:             to,
+	})
+	if err != nil {
+		return IncomeExpenseReport{}, errors.Wrap(err, "failed to fetch income and expenses")
+	}
+	byMonth := make(map[time.Time]ReportIncomeExpenseModel, len(models))
+	for _, model := range models {
+		byMonth[reportMonth(model.Month, model.Year)] = model
+	}
+
+	months := reportMonths(from, to)
+	report := IncomeExpenseReport{
+		From:     from,
+		To:       to,
+		Currency: baseCurrency,
+		Months:   make([]IncomeExpenseMonth, 0, len(months)),
+	}
+	for _, month := range months {
+		model := byMonth[month]
+		item := IncomeExpenseMonth{
+			Month:        int(month.Month()),
+			Year:         month.Year(),
+			Income:       model.Income,
+			Expense:      model.Expense,
+			Net:          model.Income.Sub(model.Expense),
+			MissingRates: model.MissingRates,
+		}
+		if model.Income.IsPositive() {
+			rate := item.Net.Div(model.Income).Mul(decimal.NewFromInt(100)).Round(2)
+			item.SavingsRate = &rate
+		}
+		report.Months = append(report.Months, item)
+		report.TotalIncome = report.TotalIncome.Add(model.Income)
+		report.TotalExpense = report.TotalExpense.Add(model.Expense)
+		report.MissingRates += model.MissingRates
+	}
+	report.Net = report.TotalIncome.Sub(report.TotalExpense)
+
+	return report, nil
+}
+
+// GetReportTransactions lists the transactions behind a report figure, most
+// recent first. Split transactions show up once per matching split.
+func (s *service) GetReportTransactions(ctx context.Context, input GetReportTransactionsInput) ([]ReportTransaction, error) {
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultReportTransactions
+	}
+	if limit < 1 || limit > maxReportTransactions || input.Offset < 0 {
+		return nil, errors.Wrap(internalerrors.ErrInvalidReportRange, "limit must be between 1 and %d", maxReportTransactions)
+	}
+	if input.CategoryID != nil && input.Uncategorized {
+		return nil, errors.Wrap(internalerrors.ErrInvalidReportRange, "a category and uncategorized cannot be combined")
+	}
+	if input.TransactionType != "" && input.TransactionType != TransactionTypeDebit && input.TransactionType != TransactionTypeCredit {
+		return nil, errors.Wrap(internalerrors.ErrInvalidReportRange, "unknown transaction type %q", input.TransactionType)
+	}
+	from, to, err := s.reportRange(input.From, input.To)
+	if err != nil {
+		return nil, err
+	}
+
+	params := fetchReportTransactionsParams{
+		OrganizationID: input.OrganizationID,
+		From:           from,
+		To:             to,
+		CategoryID:     input.CategoryID,
+		Uncategorized:  input.Uncategorized,
+		Limit:          limit,
+		Offset:         input.Offset,
+	}
+	if input.Merchant != "" {
+		params.Merchant = &input.Merchant
+	}
+	if input.TransactionType != "" {
+		params.TransactionType = &input.TransactionType
+	}
+	models, err := s.Repository.FetchReportTransactions(ctx, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch report transactions")
+	}
+
+	transactions := make([]ReportTransaction, 0, len(models))
+	for _, model := range models {
+		transactions = append(transactions, ReportTransaction{
+			TransactionID:   model.TransactionID,
+			SplitID:         model.SplitID,
+			AccountID:       model.AccountID,
+			TransactionDate: model.TransactionDate,
+			Month:           model.Month,
+			Year:            model.Year,
+			Description:     model.Description,
+			CategoryID:      model.CategoryID,
+			TransactionType: model.TransactionType,
+			Amount:          model.Amount,
+		})
+	}
+	return transactions, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// reportCategoryKey groups by category, with 0 for uncategorized transactions
+type reportCategoryKey int
+
+func reportCategoryKeyOf(categoryID *int) reportCategoryKey {
+	if categoryID == nil {
+		return 0
+	}
+	return reportCategoryKey(*categoryID)
+}
+
+func (k reportCategoryKey) categoryID() *int {
+	if k == 0 {
+		return nil
+	}
+	id := int(k)
+	return &id
+}
+
+func (s *service) reportCategoryNames(ctx context.Context, organizationID int) (map[reportCategoryKey]string, error) {
+	categories, err := s.Repository.FetchCategories(ctx, fetchCategoriesParams{
+		OrganizationID: &organizationID,
+		IncludeSystem:  true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch categories")
+	}
+	names := make(map[reportCategoryKey]string, len(categories))
+	for _, category := range categories {
+		names[reportCategoryKey(category.CategoryID)] = category.Name
+	}
+	return names, nil
+}
+
+// reportRange parses a YYYY-MM range into the first days of its months.
+// Without bounds it covers the last 12 months, counting the current one.
+func (s *service) reportRange(from, to string) (time.Time, time.Time, error) {
+	now := s.system.Time.Now()
+	end := reportMonth(int(now.Month()), now.Year())
+	if to != "" {
+		parsed, err := time.Parse(reportMonthLayout, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(internalerrors.ErrInvalidReportRange, "invalid month %q", to)
+		}
+		end = parsed
+	}
+	start := end.AddDate(0, 1-defaultReportMonths, 0)
+	if from != "" {
+		parsed, err := time.Parse(reportMonthLayout, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(internalerrors.ErrInvalidReportRange, "invalid month %q", from)
+		}
+		start = parsed
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, errors.Wrap(internalerrors.ErrInvalidReportRange, "from is after to")
+	}
+	if len(reportMonths(start, end)) > maxReportMonths {
+		return time.Time{}, time.Time{}, errors.Wrap(internalerrors.ErrInvalidReportRange, "a report spans at most %d months", maxReportMonths)
+	}
+	return start, end, nil
+}
+
+func reportMonth(month, year int) time.Time {
+	return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// reportMonths lists the first days of the months from start through end
+func reportMonths(start, end time.Time) []time.Time {
+	var months []time.Time
+	for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+		months = append(months, month)
+	}
+	return months
+}
+
+func medianAmount(amounts []decimal.Decimal) decimal.Decimal {
+	if len(amounts) == 0 {
+		return decimal.Zero
+	}
+	sorted := append([]decimal.Decimal(nil), amounts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return sorted[middle-1].Add(sorted[middle]).Div(decimal.NewFromInt(2)).Round(2)
+}
+
+func withChange(comparison ReportComparison) ReportComparison {
+	comparison.Change = comparison.Amount.Sub(comparison.CompareAmount)
+	if comparison.CompareAmount.IsPositive() {
+		percent := comparison.Change.Div(comparison.CompareAmount).Mul(decimal.NewFromInt(100)).Round(2)
+		comparison.ChangePercent = &percent
+	}
+	return comparison
+}
@@ -0,0 +1,161 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newReportsTestService(mockRepo *MockRepository) *service {
+	stub := system.NewStubSystem()
+	stub.Time.SetTimes(time.Date(2026, time.April, 15, 9, 0, 0, 0, time.UTC))
+	return &service{Repository: mockRepo, system: stub.ToSystem(), logger: &logging.TestLogger{}}
+}
+
+func TestGetCategoryTrends(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+	groceries := 3
+
+	mockRepo.On("FetchBaseCurrency", ctx, fetchBaseCurrencyParams{OrganizationID: 1}).Return("BRL", nil)
+	mockRepo.On("FetchReportCategorySpending", ctx, fetchReportCategorySpendingParams{
+		OrganizationID: 1,
+		From:           reportMonth(1, 2026),
+		To:             reportMonth(4, 2026),
+	}).Return([]ReportCategoryMonthModel{
+		{CategoryID: &groceries, Month: 1, Year: 2026, Amount: decimal.NewFromInt(300)},
+		{CategoryID: &groceries, Month: 2, Year: 2026, Amount: decimal.NewFromInt(500)},
+		{CategoryID: &groceries, Month: 4, Year: 2026, Amount: decimal.NewFromInt(400)},
+		{Month: 3, Year: 2026, Amount: decimal.NewFromInt(50)},
+	}, nil)
+	mockRepo.On("FetchMonthlySnapshots", ctx, mock.Anything).Return([]MonthlySnapshotModel{
+		{CategoryID: 3, Month: 2, Year: 2026, PlannedAmount: decimal.NewFromInt(450)},
+		{CategoryID: 3, Month: 2, Year: 2025, PlannedAmount: decimal.NewFromInt(400)},
+	}, nil)
+	mockRepo.On("FetchCategories", ctx, mock.Anything).Return([]CategoryModel{{CategoryID: 3, Name: "Mercado"}}, nil)
+
+	report, err := svc.GetCategoryTrends(ctx, GetCategoryTrendsInput{OrganizationID: 1, From: "2026-01", To: "2026-04"})
+
+	require.NoError(t, err)
+	require.Len(t, report.Categories, 2)
+	trend := report.Categories[0]
+	assert.Equal(t, "Mercado", trend.CategoryName)
+	require.Len(t, trend.Months, 4)
+	assert.True(t, trend.Months[2].Amount.IsZero())
+	assert.Nil(t, trend.Months[0].PlannedAmount)
+	require.NotNil(t, trend.Months[1].PlannedAmount)
+	assert.Equal(t, "450", trend.Months[1].PlannedAmount.String())
+	assert.Equal(t, "1200", trend.Total.String())
+	assert.Equal(t, "300", trend.Average.String())
+	assert.Equal(t, "350", trend.Median.String())
+
+	assert.Nil(t, report.Categories[1].CategoryID)
+	assert.Equal(t, "50", report.Categories[1].Total.String())
+}
+
+func TestGetYearOverYear(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+	groceries, travel := 3, 4
+
+	mockRepo.On("FetchBaseCurrency", ctx, fetchBaseCurrencyParams{OrganizationID: 1}).Return("BRL", nil)
+	// Both years through April, the current month
+	mockRepo.On("FetchReportCategorySpending", ctx, fetchReportCategorySpendingParams{
+		OrganizationID: 1, From: reportMonth(1, 2026), To: reportMonth(4, 2026),
+	}).Return([]ReportCategoryMonthModel{
+		{CategoryID: &groceries, Month: 1, Year: 2026, Amount: decimal.NewFromInt(600)},
+		{CategoryID: &travel, Month: 2, Year: 2026, Amount: decimal.NewFromInt(100)},
+	}, nil)
+	mockRepo.On("FetchReportCategorySpending", ctx, fetchReportCategorySpendingParams{
+		OrganizationID: 1, From: reportMonth(1, 2025), To: reportMonth(4, 2025),
+	}).Return([]ReportCategoryMonthModel{
+		{CategoryID: &groceries, Month: 1, Year: 2025, Amount: decimal.NewFromInt(400)},
+	}, nil)
+	mockRepo.On("FetchCategories", ctx, mock.Anything).Return([]CategoryModel{{CategoryID: 3, Name: "Mercado"}, {CategoryID: 4, Name: "Viagem"}}, nil)
+
+	report, err := svc.GetYearOverYear(ctx, GetYearOverYearInput{OrganizationID: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2026, report.Year)
+	assert.Equal(t, 2025, report.CompareYear)
+	assert.Equal(t, 4, report.ThroughMonth)
+	require.Len(t, report.Months, 4)
+	assert.Equal(t, "200", report.Months[0].Change.String())
+	require.NotNil(t, report.Months[0].ChangePercent)
+	assert.Equal(t, "50", report.Months[0].ChangePercent.String())
+	assert.Nil(t, report.Months[1].ChangePercent)
+	assert.Equal(t, "300", report.Total.Change.String())
+
+	require.Len(t, report.Categories, 2)
+	assert.Equal(t, "Mercado", report.Categories[0].CategoryName)
+	assert.Equal(t, "Viagem", report.Categories[1].CategoryName)
+
+	_, err = svc.GetYearOverYear(ctx, GetYearOverYearInput{OrganizationID: 1, Year: 2027})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidReportRange)
+}
+
+func TestGetIncomeExpense(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("FetchBaseCurrency", ctx, fetchBaseCurrencyParams{OrganizationID: 1}).Return("BRL", nil)
+	mockRepo.On("FetchReportIncomeExpense", ctx, fetchReportIncomeExpenseParams{
+		OrganizationID: 1, From: reportMonth(2, 2026), To: reportMonth(3, 2026),
+	}).Return([]ReportIncomeExpenseModel{
+		{Month: 2, Year: 2026, Income: decimal.NewFromInt(5000), Expense: decimal.NewFromInt(4000)},
+	}, nil)
+
+	report, err := svc.GetIncomeExpense(ctx, GetIncomeExpenseInput{OrganizationID: 1, From: "2026-02", To: "2026-03"})
+
+	require.NoError(t, err)
+	require.Len(t, report.Months, 2)
+	require.NotNil(t, report.Months[0].SavingsRate)
+	assert.Equal(t, "20", report.Months[0].SavingsRate.String())
+	assert.True(t, report.Months[1].Income.IsZero())
+	assert.Nil(t, report.Months[1].SavingsRate)
+	assert.Equal(t, "1000", report.Net.String())
+}
+
+func TestReportRange(t *testing.T) {
+	svc := newReportsTestService(new(MockRepository))
+
+	from, to, err := svc.reportRange("", "")
+	require.NoError(t, err)
+	assert.Equal(t, reportMonth(5, 2025), from)
+	assert.Equal(t, reportMonth(4, 2026), to)
+
+	_, _, err = svc.reportRange("2026-05", "2026-01")
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidReportRange)
+
+	_, _, err = svc.reportRange("2010-01", "2026-01")
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidReportRange)
+
+	_, _, err = svc.reportRange("2026-13", "")
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidReportRange)
+}
+
+func TestGetReportTransactions_RejectsInvalidFilters(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+	categoryID := 3
+
+	_, err := svc.GetReportTransactions(ctx, GetReportTransactionsInput{OrganizationID: 1, CategoryID: &categoryID, Uncategorized: true})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidReportRange)
+
+	_, err = svc.GetReportTransactions(ctx, GetReportTransactionsInput{OrganizationID: 1, TransactionType: "transfer"})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidReportRange)
+
+	mockRepo.AssertNotCalled(t, "FetchReportTransactions", mock.Anything, mock.Anything)
+}
@@ -2,6 +2,8 @@ package financial
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	database "github.com/catrutech/celeiro/pkg/database/persistent"
 	"github.com/catrutech/celeiro/pkg/system"
@@ -22,9 +24,14 @@ type Repository interface {
 	InsertAccount(ctx context.Context, params insertAccountParams) (AccountModel, error)
 	ModifyAccount(ctx context.Context, params modifyAccountParams) (AccountModel, error)
 	RemoveAccount(ctx context.Context, params removeAccountParams) error
+	CountAccountTransactionsInClosedMonths(ctx context.Context, params countAccountTransactionsInClosedMonthsParams) (int, error)
+	CountBillingCycleChangesInClosedMonths(ctx context.Context, params countBillingCycleChangesInClosedMonthsParams) (int, error)
+	FetchAccountTransactionConflicts(ctx context.Context, params moveAccountTransactionsParams) ([]string, error)
+	MoveAccountTransactions(ctx context.Context, params moveAccountTransactionsParams) (int, error)
 
 	// Transactions
 	FetchTransactions(ctx context.Context, params fetchTransactionsParams) ([]TransactionModel, error)
+	SearchTransactions(ctx context.Context, params searchTransactionsParams) ([]TransactionModel, error)
 	FetchTransactionByID(ctx context.Context, params fetchTransactionByIDParams) (TransactionModel, error)
 	FetchTransactionsByMonth(ctx context.Context, params fetchTransactionsByMonthParams) ([]TransactionModel, error)
 	FetchUncategorizedTransactions(ctx context.Context, params fetchUncategorizedTransactionsParams) ([]TransactionModel, error)
@@ -35,13 +42,14 @@ type Repository interface {
 	RemoveTransaction(ctx context.Context, params removeTransactionParams) error
 
 	// Spending Aggregation
-	FetchSpendingByCategory(ctx context.Context, params fetchSpendingByCategoryParams) (map[int]decimal.Decimal, error)
+	FetchSpendingByCategory(ctx context.Context, params fetchSpendingByCategoryParams) (map[int]CategorySpendingResult, error)
 
 	// Classification Rules
 	FetchClassificationRules(ctx context.Context, params fetchClassificationRulesParams) ([]ClassificationRuleModel, error)
 	FetchClassificationRuleByID(ctx context.Context, params fetchClassificationRuleByIDParams) (ClassificationRuleModel, error)
 	InsertClassificationRule(ctx context.Context, params insertClassificationRuleParams) (ClassificationRuleModel, error)
 	ModifyClassificationRule(ctx context.Context, params modifyClassificationRuleParams) (ClassificationRuleModel, error)
+	ModifyClassificationRulePriorities(ctx context.Context, params modifyClassificationRulePrioritiesParams) ([]ClassificationRuleModel, error)
 	RemoveClassificationRule(ctx context.Context, params removeClassificationRuleParams) error
 
 	// Category Budgets
@@ -51,11 +59,17 @@ type Repository interface {
 	ModifyCategoryBudget(ctx context.Context, params modifyCategoryBudgetParams) (CategoryBudgetModel, error)
 	RemoveCategoryBudget(ctx context.Context, params removeCategoryBudgetParams) error
 	IsMonthClosed(ctx context.Context, params monthClosureParams) (bool, error)
+	IsTransactionMonthClosed(ctx context.Context, params transactionMonthClosureParams) (bool, error)
 	MarkMonthClosed(ctx context.Context, params monthClosureParams) error
+	ReopenMonth(ctx context.Context, params reopenMonthParams) (MonthReopeningModel, error)
+	FetchMonthReopenings(ctx context.Context, params fetchMonthReopeningsParams) ([]MonthReopeningModel, error)
+	FetchAutoCloseMonths(ctx context.Context, params fetchAutoCloseMonthsParams) (bool, error)
+	ModifyAutoCloseMonths(ctx context.Context, params modifyAutoCloseMonthsParams) (bool, error)
 
 	// Planned Entries
 	FetchPlannedEntries(ctx context.Context, params fetchPlannedEntriesParams) ([]PlannedEntryModel, error)
 	FetchPlannedEntrySumsByCategory(ctx context.Context, params fetchPlannedEntrySumsByCategoryParams) (map[int]decimal.Decimal, error)
+	FetchPlannedEntrySettlementAccounts(ctx context.Context, params fetchPlannedEntrySettlementAccountsParams) (map[int]int, error)
 	FetchPlannedEntryByID(ctx context.Context, params fetchPlannedEntryByIDParams) (PlannedEntryModel, error)
 	FetchPlannedEntriesByParent(ctx context.Context, params fetchPlannedEntriesByParentParams) ([]PlannedEntryModel, error)
 	InsertPlannedEntry(ctx context.Context, params insertPlannedEntryParams) (PlannedEntryModel, error)
@@ -83,6 +97,7 @@ type Repository interface {
 	FetchAdvancedPatternByID(ctx context.Context, params fetchAdvancedPatternByIDParams) (AdvancedPatternModel, error)
 	InsertAdvancedPattern(ctx context.Context, params insertAdvancedPatternParams) (AdvancedPatternModel, error)
 	ModifyAdvancedPattern(ctx context.Context, params modifyAdvancedPatternParams) (AdvancedPatternModel, error)
+	ModifyAdvancedPatternPriorities(ctx context.Context, params modifyAdvancedPatternPrioritiesParams) ([]AdvancedPatternModel, error)
 	RemoveAdvancedPattern(ctx context.Context, params removeAdvancedPatternParams) error
 	FetchPlannedEntriesByPatternIDs(ctx context.Context, params fetchPlannedEntriesByPatternIDsParams) ([]PlannedEntryByPatternModel, error)
 
@@ -111,11 +126,76 @@ type Repository interface {
 
 	// Transaction Tags (junction table)
 	FetchTagsByTransactionID(ctx context.Context, params fetchTagsByTransactionIDParams) ([]TagModel, error)
+	FetchTransactionTagNames(ctx context.Context, params fetchTransactionTagNamesParams) (map[int][]string, error)
 	SetTransactionTags(ctx context.Context, params setTransactionTagsParams) error
 
+	// Transaction Splits
+	FetchTransactionSplits(ctx context.Context, params fetchTransactionSplitsParams) ([]TransactionSplitModel, error)
+	FetchTransactionSplitsByMonth(ctx context.Context, params fetchTransactionSplitsByMonthParams) ([]TransactionSplitModel, error)
+	ReplaceTransactionSplits(ctx context.Context, params replaceTransactionSplitsParams) ([]TransactionSplitModel, error)
+
+	// Transfers
+	FetchTransfers(ctx context.Context, params fetchTransfersParams) ([]TransferModel, error)
+	FetchTransferTransactionIDs(ctx context.Context, params fetchTransferTransactionIDsParams) ([]int, error)
+	FetchTransferCandidates(ctx context.Context, params fetchTransferCandidatesParams) ([]TransactionModel, error)
+	InsertTransfer(ctx context.Context, params insertTransferParams) (TransferModel, error)
+	RemoveTransfer(ctx context.Context, params removeTransferParams) error
+
+	// Credit Card Invoices
+	FetchCreditCardInvoices(ctx context.Context, params fetchCreditCardInvoicesParams) ([]CreditCardInvoiceModel, error)
+	UpsertCreditCardInvoicePayment(ctx context.Context, params upsertCreditCardInvoicePaymentParams) (CreditCardInvoiceModel, error)
+
+	// Installment Plans
+	FetchInstallmentCandidates(ctx context.Context, params fetchInstallmentCandidatesParams) ([]TransactionModel, error)
+	FetchInstallmentPlans(ctx context.Context, params fetchInstallmentPlansParams) ([]InstallmentPlanModel, error)
+	FetchInstallmentParcels(ctx context.Context, params fetchInstallmentParcelsParams) ([]InstallmentParcelModel, error)
+	UpsertInstallmentPlan(ctx context.Context, params upsertInstallmentPlanParams) (InstallmentPlanModel, error)
+	ModifyInstallmentPlan(ctx context.Context, params modifyInstallmentPlanParams) (InstallmentPlanModel, error)
+	UpsertInstallmentParcel(ctx context.Context, params upsertInstallmentParcelParams) (InstallmentParcelModel, error)
+	RemoveInstallmentProjections(ctx context.Context, params removeInstallmentProjectionsParams) error
+
+	// Currencies
+	FetchBaseCurrency(ctx context.Context, params fetchBaseCurrencyParams) (string, error)
+	ModifyBaseCurrency(ctx context.Context, params modifyBaseCurrencyParams) (string, error)
+	FetchExchangeRates(ctx context.Context, params fetchExchangeRatesParams) ([]ExchangeRateModel, error)
+	UpsertExchangeRates(ctx context.Context, params upsertExchangeRatesParams) (int, error)
+
+	// Reports
+	FetchReportCategorySpending(ctx context.Context, params fetchReportCategorySpendingParams) ([]ReportCategoryMonthModel, error)
+	FetchReportIncomeExpense(ctx context.Context, params fetchReportIncomeExpenseParams) ([]ReportIncomeExpenseModel, error)
+	FetchReportMerchants(ctx context.Context, params fetchReportMerchantsParams) ([]ReportMerchantModel, error)
+	FetchReportTransactions(ctx context.Context, params fetchReportTransactionsParams) ([]ReportTransactionModel, error)
+
+	// Audit Log
+	InsertAuditLogEntry(ctx context.Context, params insertAuditLogEntryParams) error
+	FetchAuditLog(ctx context.Context, params fetchAuditLogParams) ([]AuditLogModel, error)
+
 	// Planned Entry Tags (junction table)
 	FetchTagsByPlannedEntryID(ctx context.Context, params fetchTagsByPlannedEntryIDParams) ([]TagModel, error)
 	SetPlannedEntryTags(ctx context.Context, params setPlannedEntryTagsParams) error
+
+	// Pluggy Account Links
+	FetchPluggyAccountLinks(ctx context.Context, params fetchPluggyAccountLinksParams) ([]PluggyAccountLinkModel, error)
+	FetchPluggyAccountLinkByID(ctx context.Context, params fetchPluggyAccountLinkByIDParams) (PluggyAccountLinkModel, error)
+	InsertPluggyAccountLink(ctx context.Context, params insertPluggyAccountLinkParams) (PluggyAccountLinkModel, error)
+	ModifyPluggyAccountLinkSync(ctx context.Context, params modifyPluggyAccountLinkSyncParams) (PluggyAccountLinkModel, error)
+	RemovePluggyAccountLink(ctx context.Context, params removePluggyAccountLinkParams) error
+
+	// CSV Import Profiles
+	FetchCSVImportProfiles(ctx context.Context, params fetchCSVImportProfilesParams) ([]CSVImportProfileModel, error)
+	FetchCSVImportProfileByID(ctx context.Context, params fetchCSVImportProfileByIDParams) (CSVImportProfileModel, error)
+	InsertCSVImportProfile(ctx context.Context, params insertCSVImportProfileParams) (CSVImportProfileModel, error)
+	ModifyCSVImportProfile(ctx context.Context, params modifyCSVImportProfileParams) (CSVImportProfileModel, error)
+	RemoveCSVImportProfile(ctx context.Context, params removeCSVImportProfileParams) error
+
+	// Account Reconciliation
+	FetchAccountStatementBalances(ctx context.Context, params fetchAccountStatementBalancesParams) ([]AccountStatementBalanceModel, error)
+	UpsertAccountStatementBalance(ctx context.Context, params upsertAccountStatementBalanceParams) (AccountStatementBalanceModel, error)
+	FetchAccountTransactionsBetween(ctx context.Context, params fetchAccountTransactionsBetweenParams) ([]TransactionModel, error)
+	ModifyAccountReconciledThrough(ctx context.Context, params modifyAccountReconciledThroughParams) error
+
+	// Background Jobs
+	FetchMaintenanceTargets(ctx context.Context) ([]MaintenanceTargetModel, error)
 }
 
 type repository struct {
@@ -318,6 +398,12 @@ const fetchAccountsQuery = `
 		bank_name,
 		balance,
 		currency,
+		opening_balance,
+		opening_balance_date,
+		reconciled_through,
+		card_closing_day,
+		card_due_day,
+		budget_by_invoice,
 		is_active
 	FROM accounts
 	WHERE organization_id = $1
@@ -353,6 +439,12 @@ const fetchAccountByIDQuery = `
 		bank_name,
 		balance,
 		currency,
+		opening_balance,
+		opening_balance_date,
+		reconciled_through,
+		card_closing_day,
+		card_due_day,
+		budget_by_invoice,
 		is_active
 	FROM accounts
 	WHERE account_id = $1
@@ -369,28 +461,35 @@ func (r *repository) FetchAccountByID(ctx context.Context, params fetchAccountBy
 }
 
 type insertAccountParams struct {
-	UserID         int
-	OrganizationID int
-	Name           string
-	AccountType    string
-	BankName       string
-	Balance        decimal.Decimal
-	Currency       string
+	UserID             int
+	OrganizationID     int
+	Name               string
+	AccountType        string
+	BankName           string
+	Balance            decimal.Decimal // Opening balance; the account has no transactions yet
+	OpeningBalanceDate *string
+	Currency           string
+	CardClosingDay     *int
+	CardDueDay         *int
+	BudgetByInvoice    bool
 }
 
 const insertAccountQuery = `
 	-- financial.insertAccountQuery
-	INSERT INTO accounts (user_id, organization_id, name, account_type, bank_name, balance, currency)
-	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	INSERT INTO accounts (user_id, organization_id, name, account_type, bank_name, balance, opening_balance, opening_balance_date, currency,
+		card_closing_day, card_due_day, budget_by_invoice)
+	VALUES ($1, $2, $3, $4, $5, $6, $6, $7, $8, $9, $10, $11)
 	RETURNING account_id, created_at, updated_at, user_id, organization_id, name, account_type,
-			  bank_name, balance, currency, is_active;
+			  bank_name, balance, currency, opening_balance, opening_balance_date, reconciled_through,
+			  card_closing_day, card_due_day, budget_by_invoice, is_active;
 `
 
 func (r *repository) InsertAccount(ctx context.Context, params insertAccountParams) (AccountModel, error) {
 	var result AccountModel
 	err := r.db.Query(ctx, &result, insertAccountQuery,
 		params.UserID, params.OrganizationID, params.Name, params.AccountType,
-		params.BankName, params.Balance, params.Currency)
+		params.BankName, params.Balance, params.OpeningBalanceDate, params.Currency,
+		params.CardClosingDay, params.CardDueDay, params.BudgetByInvoice)
 	if err != nil {
 		return AccountModel{}, err
 	}
@@ -403,28 +502,54 @@ type modifyAccountParams struct {
 	OrganizationID int
 	Name           *string
 	BankName       *string
-	Balance        *decimal.Decimal
+	OpeningBalance *decimal.Decimal
 	IsActive       *bool
+
+	// OpeningBalanceDate is only applied when SetOpeningBalanceDate is true,
+	// so it can be cleared with nil.
+	SetOpeningBalanceDate bool
+	OpeningBalanceDate    *string
+
+	CardClosingDay  *int
+	CardDueDay      *int
+	BudgetByInvoice *bool
 }
 
+// modifyAccountQuery recomputes balance from the (possibly new) opening balance
+// and date; transaction changes keep it current through a trigger. Month-close
+// carryovers move no money and are left out, as in the trigger.
 const modifyAccountQuery = `
 	-- financial.modifyAccountQuery
-	UPDATE accounts
-	SET name = COALESCE($3, name),
-		bank_name = COALESCE($4, bank_name),
-		balance = COALESCE($5, balance),
-		is_active = COALESCE($6, is_active),
+	UPDATE accounts a
+	SET name = COALESCE($3, a.name),
+		bank_name = COALESCE($4, a.bank_name),
+		opening_balance = COALESCE($5, a.opening_balance),
+		opening_balance_date = CASE WHEN $7 THEN $8::DATE ELSE a.opening_balance_date END,
+		balance = COALESCE($5, a.opening_balance) + COALESCE((
+			SELECT SUM(CASE WHEN t.transaction_type = 'credit' THEN ABS(t.amount) ELSE -ABS(t.amount) END)
+			FROM transactions t
+			WHERE t.account_id = a.account_id
+				AND t.transaction_date >= COALESCE(CASE WHEN $7 THEN $8::DATE ELSE a.opening_balance_date END, '-infinity'::DATE)
+				AND (t.ofx_fitid IS NULL OR t.ofx_fitid NOT LIKE 'CARRYOVER-%')
+		), 0),
+		is_active = COALESCE($6, a.is_active),
+		card_closing_day = COALESCE($9, a.card_closing_day),
+		card_due_day = COALESCE($10, a.card_due_day),
+		budget_by_invoice = COALESCE($11, a.budget_by_invoice),
 		updated_at = NOW()
-	WHERE account_id = $1 AND organization_id = $2
+	WHERE a.account_id = $1 AND a.organization_id = $2
 	RETURNING account_id, created_at, updated_at, user_id, organization_id, name, account_type,
-			  bank_name, balance, currency, is_active;
+			  bank_name, balance, currency, opening_balance, opening_balance_date, reconciled_through,
+			  card_closing_day, card_due_day, budget_by_invoice, is_active;
 `
 
 func (r *repository) ModifyAccount(ctx context.Context, params modifyAccountParams) (AccountModel, error) {
 	var result AccountModel
 	err := r.db.Query(ctx, &result, modifyAccountQuery,
 		params.AccountID, params.OrganizationID,
-		params.Name, params.BankName, params.Balance, params.IsActive)
+		params.Name, params.BankName, params.OpeningBalance, params.IsActive,
+		params.SetOpeningBalanceDate, params.OpeningBalanceDate,
+		params.CardClosingDay, params.CardDueDay, params.BudgetByInvoice)
 	if err != nil {
 		return AccountModel{}, err
 	}
@@ -448,6 +573,118 @@ func (r *repository) RemoveAccount(ctx context.Context, params removeAccountPara
 	return err
 }
 
+type countAccountTransactionsInClosedMonthsParams struct {
+	AccountID      int
+	OrganizationID int
+}
+
+const countAccountTransactionsInClosedMonthsQuery = `
+	-- financial.countAccountTransactionsInClosedMonthsQuery
+	SELECT COUNT(*)
+	FROM transactions t
+	INNER JOIN accounts a ON a.account_id = t.account_id
+	INNER JOIN closed_months cm ON cm.organization_id = a.organization_id
+		AND make_date(cm.year, cm.month, 1) = date_trunc('month', transaction_budget_date(t.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day))::date
+	WHERE t.account_id = $1
+		AND a.organization_id = $2;
+`
+
+func (r *repository) CountAccountTransactionsInClosedMonths(ctx context.Context, params countAccountTransactionsInClosedMonthsParams) (int, error) {
+	var count int
+	err := r.db.Query(ctx, &count, countAccountTransactionsInClosedMonthsQuery, params.AccountID, params.OrganizationID)
+	return count, err
+}
+
+type countBillingCycleChangesInClosedMonthsParams struct {
+	AccountID       int
+	OrganizationID  int
+	BudgetByInvoice bool // The billing cycle the account would change to
+	CardClosingDay  *int
+	CardDueDay      *int
+}
+
+// countBillingCycleChangesInClosedMonthsQuery counts the account's
+// transactions a new billing cycle would move into or out of a closed budget
+// month.
+const countBillingCycleChangesInClosedMonthsQuery = `
+	-- financial.countBillingCycleChangesInClosedMonthsQuery
+	SELECT COUNT(*)
+	FROM transactions t
+	INNER JOIN accounts a ON a.account_id = t.account_id
+	CROSS JOIN LATERAL (
+		SELECT
+			date_trunc('month', transaction_budget_date(t.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day))::date AS old_month,
+			date_trunc('month', transaction_budget_date(t.transaction_date, $3, $4, $5))::date AS new_month
+	) m
+	WHERE t.account_id = $1
+		AND a.organization_id = $2
+		AND m.old_month <> m.new_month
+		AND EXISTS (
+			SELECT 1 FROM closed_months cm
+			WHERE cm.organization_id = a.organization_id
+				AND make_date(cm.year, cm.month, 1) IN (m.old_month, m.new_month)
+		);
+`
+
+func (r *repository) CountBillingCycleChangesInClosedMonths(ctx context.Context, params countBillingCycleChangesInClosedMonthsParams) (int, error) {
+	var count int
+	err := r.db.Query(ctx, &count, countBillingCycleChangesInClosedMonthsQuery,
+		params.AccountID, params.OrganizationID, params.BudgetByInvoice, params.CardClosingDay, params.CardDueDay)
+	return count, err
+}
+
+type moveAccountTransactionsParams struct {
+	FromAccountID  int
+	ToAccountID    int
+	OrganizationID int
+}
+
+// fetchAccountTransactionConflictsQuery lists the FITIDs of the source account
+// that the target account already has: the same bank entry imported twice,
+// which the unique FITID index keeps from being moved.
+const fetchAccountTransactionConflictsQuery = `
+	-- financial.fetchAccountTransactionConflictsQuery
+	SELECT t.ofx_fitid
+	FROM transactions t
+	INNER JOIN accounts source ON source.account_id = t.account_id
+	INNER JOIN transactions existing ON existing.account_id = $2 AND existing.ofx_fitid = t.ofx_fitid
+	WHERE t.account_id = $1
+		AND source.organization_id = $3
+	ORDER BY t.ofx_fitid;
+`
+
+func (r *repository) FetchAccountTransactionConflicts(ctx context.Context, params moveAccountTransactionsParams) ([]string, error) {
+	var fitIDs []string
+	err := r.db.Query(ctx, &fitIDs, fetchAccountTransactionConflictsQuery,
+		params.FromAccountID, params.ToAccountID, params.OrganizationID)
+	return fitIDs, err
+}
+
+// moveAccountTransactionsQuery moves every transaction to another account of
+// the same organization. Check for conflicts first: a FITID the target
+// account already has fails the whole move.
+const moveAccountTransactionsQuery = `
+	-- financial.moveAccountTransactionsQuery
+	WITH moved AS (
+		UPDATE transactions t
+		SET account_id = $2,
+			updated_at = NOW()
+		FROM accounts source, accounts target
+		WHERE t.account_id = $1
+			AND source.account_id = $1 AND source.organization_id = $3
+			AND target.account_id = $2 AND target.organization_id = $3
+		RETURNING t.transaction_id
+	)
+	SELECT COUNT(*) FROM moved;
+`
+
+func (r *repository) MoveAccountTransactions(ctx context.Context, params moveAccountTransactionsParams) (int, error) {
+	var moved int
+	err := r.db.Query(ctx, &moved, moveAccountTransactionsQuery,
+		params.FromAccountID, params.ToAccountID, params.OrganizationID)
+	return moved, err
+}
+
 // ============================================================================
 // Transactions
 // ============================================================================
@@ -478,11 +715,14 @@ const fetchTransactionsQuery = `
 		t.raw_ofx_data,
 		t.is_classified,
 		t.classification_rule_id,
+		t.pattern_id,
 		t.is_ignored,
 		t.needs_review,
 		t.savings_goal_id,
 		t.notes,
-		t.tags
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
 	FROM transactions t
 	INNER JOIN accounts a ON a.account_id = t.account_id
 	WHERE t.account_id = $1
@@ -501,6 +741,119 @@ func (r *repository) FetchTransactions(ctx context.Context, params fetchTransact
 	return result, nil
 }
 
+type searchTransactionsParams struct {
+	OrganizationID  int
+	From            *time.Time
+	To              *time.Time
+	AccountIDs      []int // nil matches every account
+	CategoryIDs     []int
+	TagIDs          []int // Matches transactions with any of the tags
+	MinAmount       *decimal.Decimal
+	MaxAmount       *decimal.Decimal
+	TransactionType *string
+	IsIgnored       *bool
+	NeedsReview     *bool
+	SavingsGoalID   *int
+	Pattern         *string // ILIKE pattern matched against descriptions and notes
+	SortBy          string
+	Descending      bool
+	// Keyset position: the sort value and ID of the last row already returned
+	AfterValue *string
+	AfterID    *int
+	Limit      int
+}
+
+// transactionSearchSorts maps each sort key to its column expression and the
+// type its cursor value is cast to. Only these are ever formatted into SQL.
+var transactionSearchSorts = map[string]struct{ expr, cast string }{
+	TransactionSortDate:   {expr: "t.transaction_date", cast: "DATE"},
+	TransactionSortAmount: {expr: "ABS(t.amount)", cast: "DECIMAL"},
+}
+
+// searchTransactionsQuery is formatted with the sort expression, direction,
+// keyset comparison and cursor cast. Each filter is skipped when NULL.
+const searchTransactionsQuery = `
+	-- financial.searchTransactionsQuery
+	SELECT
+		t.transaction_id,
+		t.created_at,
+		t.updated_at,
+		t.account_id,
+		t.category_id,
+		t.description,
+		t.original_description,
+		t.amount,
+		t.transaction_date,
+		t.transaction_type,
+		t.ofx_fitid,
+		t.ofx_check_number,
+		t.ofx_memo,
+		t.raw_ofx_data,
+		t.is_classified,
+		t.classification_rule_id,
+		t.pattern_id,
+		t.is_ignored,
+		t.needs_review,
+		t.savings_goal_id,
+		t.notes,
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
+	FROM transactions t
+	INNER JOIN accounts a ON a.account_id = t.account_id
+	WHERE a.organization_id = $1
+		AND ($2::DATE IS NULL OR t.transaction_date >= $2::DATE)
+		AND ($3::DATE IS NULL OR t.transaction_date <= $3::DATE)
+		AND ($4::INT[] IS NULL OR t.account_id = ANY($4::INT[]))
+		AND ($5::INT[] IS NULL OR t.category_id = ANY($5::INT[]) OR EXISTS (
+			SELECT 1 FROM transaction_splits ts
+			WHERE ts.transaction_id = t.transaction_id
+				AND ts.category_id = ANY($5::INT[])
+		))
+		AND ($6::INT[] IS NULL OR EXISTS (
+			SELECT 1 FROM transaction_tags tt
+			WHERE tt.transaction_id = t.transaction_id
+				AND tt.tag_id = ANY($6::INT[])
+		))
+		AND ($7::DECIMAL IS NULL OR ABS(t.amount) >= $7::DECIMAL)
+		AND ($8::DECIMAL IS NULL OR ABS(t.amount) <= $8::DECIMAL)
+		AND ($9::TEXT IS NULL OR t.transaction_type = $9::TEXT)
+		AND ($10::BOOLEAN IS NULL OR t.is_ignored = $10::BOOLEAN)
+		AND ($11::BOOLEAN IS NULL OR t.needs_review = $11::BOOLEAN)
+		AND ($12::INT IS NULL OR t.savings_goal_id = $12::INT)
+		AND ($13::TEXT IS NULL
+			OR t.description ILIKE $13::TEXT
+			OR t.original_description ILIKE $13::TEXT
+			OR t.notes ILIKE $13::TEXT)
+		AND ($14::TEXT IS NULL OR (%[1]s, t.transaction_id) %[3]s ($14::TEXT::%[4]s, $15::INT))
+	ORDER BY %[1]s %[2]s, t.transaction_id %[2]s
+	LIMIT $16;
+`
+
+func (r *repository) SearchTransactions(ctx context.Context, params searchTransactionsParams) ([]TransactionModel, error) {
+	sort, ok := transactionSearchSorts[params.SortBy]
+	if !ok {
+		sort = transactionSearchSorts[TransactionSortDate]
+	}
+	direction, comparison := "ASC", ">"
+	if params.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	query := fmt.Sprintf(searchTransactionsQuery, sort.expr, direction, comparison, sort.cast)
+
+	var result []TransactionModel
+	err := r.db.Query(ctx, &result, query,
+		params.OrganizationID, params.From, params.To,
+		params.AccountIDs, params.CategoryIDs, params.TagIDs,
+		params.MinAmount, params.MaxAmount, params.TransactionType,
+		params.IsIgnored, params.NeedsReview, params.SavingsGoalID,
+		params.Pattern, params.AfterValue, params.AfterID, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 type fetchTransactionByIDParams struct {
 	TransactionID  int
 	OrganizationID int
@@ -525,11 +878,14 @@ const fetchTransactionByIDQuery = `
 		t.raw_ofx_data,
 		t.is_classified,
 		t.classification_rule_id,
+		t.pattern_id,
 		t.is_ignored,
 		t.needs_review,
 		t.savings_goal_id,
 		t.notes,
-		t.tags
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
 	FROM transactions t
 	INNER JOIN accounts a ON a.account_id = t.account_id
 	WHERE t.transaction_id = $1
@@ -570,11 +926,14 @@ const fetchUncategorizedTransactionsQuery = `
 		t.raw_ofx_data,
 		t.is_classified,
 		t.classification_rule_id,
+		t.pattern_id,
 		t.is_ignored,
 		t.needs_review,
 		t.savings_goal_id,
 		t.notes,
-		t.tags
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
 	FROM transactions t
 	INNER JOIN accounts a ON a.account_id = t.account_id
 	WHERE a.organization_id = $1
@@ -617,11 +976,14 @@ const fetchTransactionsForPatternMatchingQuery = `
 		t.raw_ofx_data,
 		t.is_classified,
 		t.classification_rule_id,
+		t.pattern_id,
 		t.is_ignored,
 		t.needs_review,
 		t.savings_goal_id,
 		t.notes,
-		t.tags
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
 	FROM transactions t
 	INNER JOIN accounts a ON a.account_id = t.account_id
 	WHERE a.organization_id = $1
@@ -642,6 +1004,9 @@ type fetchTransactionsByMonthParams struct {
 	OrganizationID int
 	Month          int
 	Year           int
+	// BudgetMonth matches on the date transactions count on for budgets,
+	// which is the invoice due date on cards budgeted by invoice.
+	BudgetMonth bool
 }
 
 const fetchTransactionsByMonthQuery = `
@@ -663,23 +1028,32 @@ const fetchTransactionsByMonthQuery = `
 		t.raw_ofx_data,
 		t.is_classified,
 		t.classification_rule_id,
+		t.pattern_id,
 		t.is_ignored,
 		t.needs_review,
 		t.savings_goal_id,
 		t.notes,
-		t.tags
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
 	FROM transactions t
 	INNER JOIN accounts a ON a.account_id = t.account_id
+	CROSS JOIN LATERAL (
+		SELECT CASE WHEN $4
+			THEN transaction_budget_date(t.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day)
+			ELSE t.transaction_date
+		END AS month_date
+	) d
 	WHERE a.organization_id = $1
-		AND EXTRACT(MONTH FROM t.transaction_date) = $2
-		AND EXTRACT(YEAR FROM t.transaction_date) = $3
+		AND EXTRACT(MONTH FROM d.month_date) = $2
+		AND EXTRACT(YEAR FROM d.month_date) = $3
 	ORDER BY t.transaction_date ASC;
 `
 
 func (r *repository) FetchTransactionsByMonth(ctx context.Context, params fetchTransactionsByMonthParams) ([]TransactionModel, error) {
 	var result []TransactionModel
 	err := r.db.Query(ctx, &result, fetchTransactionsByMonthQuery,
-		params.OrganizationID, params.Month, params.Year)
+		params.OrganizationID, params.Month, params.Year, params.BudgetMonth)
 	if err != nil {
 		return nil, err
 	}
@@ -723,7 +1097,7 @@ const insertTransactionQuery = `
 		updated_at = NOW()
 	RETURNING transaction_id, created_at, updated_at, account_id, category_id, description, original_description, amount,
 			  transaction_date, transaction_type, ofx_fitid, ofx_check_number, ofx_memo, raw_ofx_data,
-			  is_classified, classification_rule_id, is_ignored, needs_review, notes, tags;
+			  is_classified, classification_rule_id, pattern_id, is_ignored, needs_review, notes, tags;
 `
 
 func (r *repository) InsertTransaction(ctx context.Context, params insertTransactionParams) (TransactionModel, error) {
@@ -766,6 +1140,9 @@ type modifyTransactionParams struct {
 	Notes          *string
 	IsIgnored      *bool
 	NeedsReview    *bool
+	// Set by automatic classification; also marks the transaction classified
+	ClassificationRuleID *int
+	PatternID            *int // Set by pattern application; use -1 to clear
 }
 
 // Writes are scoped to the organization, not the individual user. Celeiro is a
@@ -786,6 +1163,9 @@ const modifyTransactionQuery = `
 		notes = COALESCE($7, t.notes),
 		is_ignored = COALESCE($8, t.is_ignored),
 		needs_review = COALESCE($9, t.needs_review),
+		classification_rule_id = COALESCE($10, t.classification_rule_id),
+		pattern_id = CASE WHEN $11 = -1 THEN NULL ELSE COALESCE($11, t.pattern_id) END,
+		is_classified = CASE WHEN $10::int IS NOT NULL THEN true ELSE t.is_classified END,
 		updated_at = NOW()
 	FROM accounts a
 	WHERE t.transaction_id = $1
@@ -793,15 +1173,17 @@ const modifyTransactionQuery = `
 		AND a.organization_id = $2
 	RETURNING t.transaction_id, t.created_at, t.updated_at, t.account_id, t.category_id, t.description,
 			  t.original_description, t.amount, t.transaction_date, t.transaction_type, t.ofx_fitid,
-			  t.ofx_check_number, t.ofx_memo, t.raw_ofx_data, t.is_classified, t.classification_rule_id,
-			  t.is_ignored, t.needs_review, t.savings_goal_id, t.notes, t.tags;
+			  t.ofx_check_number, t.ofx_memo, t.raw_ofx_data, t.is_classified, t.classification_rule_id, t.pattern_id,
+			  t.is_ignored, t.needs_review, t.savings_goal_id, t.notes, t.tags, a.currency,
+			  to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount;
 `
 
 func (r *repository) ModifyTransaction(ctx context.Context, params modifyTransactionParams) (TransactionModel, error) {
 	var result TransactionModel
 	err := r.db.Query(ctx, &result, modifyTransactionQuery,
 		params.TransactionID, params.OrganizationID,
-		params.CategoryID, params.SavingsGoalID, params.Description, params.Amount, params.Notes, params.IsIgnored, params.NeedsReview)
+		params.CategoryID, params.SavingsGoalID, params.Description, params.Amount, params.Notes, params.IsIgnored, params.NeedsReview,
+		params.ClassificationRuleID, params.PatternID)
 	if err != nil {
 		return TransactionModel{}, err
 	}
@@ -834,8 +1216,8 @@ func (r *repository) RemoveTransaction(ctx context.Context, params removeTransac
 // ============================================================================
 
 type fetchClassificationRulesParams struct {
-	UserID   int
-	IsActive *bool
+	OrganizationID int
+	IsActive       *bool
 }
 
 const fetchClassificationRulesQuery = `
@@ -845,6 +1227,7 @@ const fetchClassificationRulesQuery = `
 		created_at,
 		updated_at,
 		user_id,
+		organization_id,
 		category_id,
 		name,
 		priority,
@@ -854,14 +1237,14 @@ const fetchClassificationRulesQuery = `
 		match_transaction_type,
 		is_active
 	FROM classification_rules
-	WHERE user_id = $1
+	WHERE organization_id = $1
 		AND (is_active = $2 OR $2 IS NULL)
-	ORDER BY priority ASC, created_at ASC;
+	ORDER BY priority ASC, created_at ASC, rule_id ASC;
 `
 
 func (r *repository) FetchClassificationRules(ctx context.Context, params fetchClassificationRulesParams) ([]ClassificationRuleModel, error) {
 	var result []ClassificationRuleModel
-	err := r.db.Query(ctx, &result, fetchClassificationRulesQuery, params.UserID, params.IsActive)
+	err := r.db.Query(ctx, &result, fetchClassificationRulesQuery, params.OrganizationID, params.IsActive)
 	if err != nil {
 		return nil, err
 	}
@@ -869,8 +1252,8 @@ func (r *repository) FetchClassificationRules(ctx context.Context, params fetchC
 }
 
 type fetchClassificationRuleByIDParams struct {
-	RuleID int
-	UserID int
+	RuleID         int
+	OrganizationID int
 }
 
 const fetchClassificationRuleByIDQuery = `
@@ -880,6 +1263,7 @@ const fetchClassificationRuleByIDQuery = `
 		created_at,
 		updated_at,
 		user_id,
+		organization_id,
 		category_id,
 		name,
 		priority,
@@ -889,12 +1273,12 @@ const fetchClassificationRuleByIDQuery = `
 		match_transaction_type,
 		is_active
 	FROM classification_rules
-	WHERE rule_id = $1 AND user_id = $2;
+	WHERE rule_id = $1 AND organization_id = $2;
 `
 
 func (r *repository) FetchClassificationRuleByID(ctx context.Context, params fetchClassificationRuleByIDParams) (ClassificationRuleModel, error) {
 	var result ClassificationRuleModel
-	err := r.db.Query(ctx, &result, fetchClassificationRuleByIDQuery, params.RuleID, params.UserID)
+	err := r.db.Query(ctx, &result, fetchClassificationRuleByIDQuery, params.RuleID, params.OrganizationID)
 	if err != nil {
 		return ClassificationRuleModel{}, err
 	}
@@ -903,6 +1287,7 @@ func (r *repository) FetchClassificationRuleByID(ctx context.Context, params fet
 
 type insertClassificationRuleParams struct {
 	UserID               int
+	OrganizationID       int
 	CategoryID           int
 	Name                 string
 	Priority             int
@@ -915,18 +1300,18 @@ type insertClassificationRuleParams struct {
 const insertClassificationRuleQuery = `
 	-- financial.insertClassificationRuleQuery
 	INSERT INTO classification_rules (
-		user_id, category_id, name, priority, match_description,
+		user_id, organization_id, category_id, name, priority, match_description,
 		match_amount_min, match_amount_max, match_transaction_type
 	)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	RETURNING rule_id, created_at, updated_at, user_id, category_id, name, priority,
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING rule_id, created_at, updated_at, user_id, organization_id, category_id, name, priority,
 			  match_description, match_amount_min, match_amount_max, match_transaction_type, is_active;
 `
 
 func (r *repository) InsertClassificationRule(ctx context.Context, params insertClassificationRuleParams) (ClassificationRuleModel, error) {
 	var result ClassificationRuleModel
 	err := r.db.Query(ctx, &result, insertClassificationRuleQuery,
-		params.UserID, params.CategoryID, params.Name, params.Priority,
+		params.UserID, params.OrganizationID, params.CategoryID, params.Name, params.Priority,
 		params.MatchDescription, params.MatchAmountMin, params.MatchAmountMax, params.MatchTransactionType)
 	if err != nil {
 		return ClassificationRuleModel{}, err
@@ -936,7 +1321,7 @@ func (r *repository) InsertClassificationRule(ctx context.Context, params insert
 
 type modifyClassificationRuleParams struct {
 	RuleID               int
-	UserID               int
+	OrganizationID       int
 	CategoryID           *int
 	Name                 *string
 	Priority             *int
@@ -959,15 +1344,15 @@ const modifyClassificationRuleQuery = `
 		match_transaction_type = COALESCE($9, match_transaction_type),
 		is_active = COALESCE($10, is_active),
 		updated_at = NOW()
-	WHERE rule_id = $1 AND user_id = $2
-	RETURNING rule_id, created_at, updated_at, user_id, category_id, name, priority,
+	WHERE rule_id = $1 AND organization_id = $2
+	RETURNING rule_id, created_at, updated_at, user_id, organization_id, category_id, name, priority,
 			  match_description, match_amount_min, match_amount_max, match_transaction_type, is_active;
 `
 
 func (r *repository) ModifyClassificationRule(ctx context.Context, params modifyClassificationRuleParams) (ClassificationRuleModel, error) {
 	var result ClassificationRuleModel
 	err := r.db.Query(ctx, &result, modifyClassificationRuleQuery,
-		params.RuleID, params.UserID, params.CategoryID, params.Name, params.Priority,
+		params.RuleID, params.OrganizationID, params.CategoryID, params.Name, params.Priority,
 		params.MatchDescription, params.MatchAmountMin, params.MatchAmountMax,
 		params.MatchTransactionType, params.IsActive)
 	if err != nil {
@@ -976,20 +1361,51 @@ func (r *repository) ModifyClassificationRule(ctx context.Context, params modify
 	return result, nil
 }
 
+type modifyClassificationRulePrioritiesParams struct {
+	OrganizationID int
+	RuleIDs        []int // Highest priority first
+}
+
+// Rules are renumbered 10, 20, 30... in the given order so a single rule can
+// later be slotted between two others with a plain update. Rules of the
+// organization missing from the list keep their priority.
+const modifyClassificationRulePrioritiesQuery = `
+	-- financial.modifyClassificationRulePrioritiesQuery
+	UPDATE classification_rules cr
+	SET priority = ordered.position * 10,
+		updated_at = NOW()
+	FROM UNNEST($2::int[]) WITH ORDINALITY AS ordered(rule_id, position)
+	WHERE cr.rule_id = ordered.rule_id
+		AND cr.organization_id = $1
+	RETURNING cr.rule_id, cr.created_at, cr.updated_at, cr.user_id, cr.organization_id, cr.category_id,
+			  cr.name, cr.priority, cr.match_description, cr.match_amount_min, cr.match_amount_max,
+			  cr.match_transaction_type, cr.is_active;
+`
+
+func (r *repository) ModifyClassificationRulePriorities(ctx context.Context, params modifyClassificationRulePrioritiesParams) ([]ClassificationRuleModel, error) {
+	var result []ClassificationRuleModel
+	err := r.db.Query(ctx, &result, modifyClassificationRulePrioritiesQuery, params.OrganizationID, params.RuleIDs)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 type removeClassificationRuleParams struct {
-	RuleID int
-	UserID int
+	RuleID         int
+	OrganizationID int
 }
 
 const removeClassificationRuleQuery = `
 	-- financial.removeClassificationRuleQuery
 	DELETE FROM classification_rules
-	WHERE rule_id = $1 AND user_id = $2;
+	WHERE rule_id = $1 AND organization_id = $2
+	RETURNING rule_id;
 `
 
 func (r *repository) RemoveClassificationRule(ctx context.Context, params removeClassificationRuleParams) error {
-	err := r.db.Run(ctx, removeClassificationRuleQuery, params.RuleID, params.UserID)
-	return err
+	var deletedID int
+	return r.db.Query(ctx, &deletedID, removeClassificationRuleQuery, params.RuleID, params.OrganizationID)
 }
 
 // ============================================================================
@@ -1003,27 +1419,39 @@ type fetchSpendingByCategoryParams struct {
 }
 
 type CategorySpendingResult struct {
-	CategoryID int             `db:"category_id"`
-	TotalSpent decimal.Decimal `db:"total_spent"`
+	CategoryID   int             `db:"category_id"`
+	TotalSpent   decimal.Decimal `db:"total_spent"`
+	MissingRates int             `db:"missing_rates"` // Allocations left out of TotalSpent
 }
 
+// Split transactions count through their splits (see transaction_allocations);
+// transfers between the organization's own accounts are not spending. Card
+// purchases budgeted by invoice count in the invoice's due month. Amounts are
+// converted to the base currency; ones without a known rate are counted
+// instead of summed.
 const fetchSpendingByCategoryQuery = `
 	-- financial.fetchSpendingByCategoryQuery
 	SELECT
-		t.category_id,
-		COALESCE(SUM(ABS(t.amount)), 0) as total_spent
-	FROM transactions t
-	INNER JOIN accounts a ON a.account_id = t.account_id
+		ta.category_id,
+		COALESCE(SUM(to_base_amount(ta.amount, a.organization_id, a.currency, ta.transaction_date)), 0) as total_spent,
+		COUNT(*) FILTER (WHERE to_base_amount(ta.amount, a.organization_id, a.currency, ta.transaction_date) IS NULL) as missing_rates
+	FROM transaction_allocations ta
+	INNER JOIN accounts a ON a.account_id = ta.account_id
 	WHERE a.organization_id = $1
-		AND t.category_id IS NOT NULL
-		AND EXTRACT(MONTH FROM t.transaction_date) = $2
-		AND EXTRACT(YEAR FROM t.transaction_date) = $3
-		AND t.transaction_type = 'debit'
-		AND t.is_ignored = false
-	GROUP BY t.category_id;
+		AND ta.category_id IS NOT NULL
+		AND EXTRACT(MONTH FROM transaction_budget_date(ta.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day)) = $2
+		AND EXTRACT(YEAR FROM transaction_budget_date(ta.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day)) = $3
+		AND ta.transaction_type = 'debit'
+		AND ta.is_ignored = false
+		AND NOT EXISTS (
+			SELECT 1 FROM transfers tr
+			WHERE tr.debit_transaction_id = ta.transaction_id
+				OR tr.credit_transaction_id = ta.transaction_id
+		)
+	GROUP BY ta.category_id;
 `
 
-func (r *repository) FetchSpendingByCategory(ctx context.Context, params fetchSpendingByCategoryParams) (map[int]decimal.Decimal, error) {
+func (r *repository) FetchSpendingByCategory(ctx context.Context, params fetchSpendingByCategoryParams) (map[int]CategorySpendingResult, error) {
 	var results []CategorySpendingResult
 	err := r.db.Query(ctx, &results, fetchSpendingByCategoryQuery,
 		params.OrganizationID, params.Month, params.Year)
@@ -1032,9 +1460,9 @@ func (r *repository) FetchSpendingByCategory(ctx context.Context, params fetchSp
 	}
 
 	// Convert to map
-	spendingMap := make(map[int]decimal.Decimal)
+	spendingMap := make(map[int]CategorySpendingResult)
 	for _, result := range results {
-		spendingMap[result.CategoryID] = result.TotalSpent
+		spendingMap[result.CategoryID] = result
 	}
 
 	return spendingMap, nil
@@ -1247,6 +1675,32 @@ func (r *repository) IsMonthClosed(ctx context.Context, params monthClosureParam
 	return closed, err
 }
 
+type transactionMonthClosureParams struct {
+	OrganizationID  int
+	AccountID       int
+	TransactionDate time.Time
+}
+
+// isTransactionMonthClosedQuery checks the budget month a transaction on the
+// account counts in, which for cards budgeted by invoice is the due month.
+const isTransactionMonthClosedQuery = `
+	-- financial.isTransactionMonthClosedQuery
+	SELECT EXISTS (
+		SELECT 1
+		FROM accounts a
+		INNER JOIN closed_months cm ON cm.organization_id = a.organization_id
+			AND make_date(cm.year, cm.month, 1) = date_trunc('month', transaction_budget_date($3::date, a.budget_by_invoice, a.card_closing_day, a.card_due_day))::date
+		WHERE a.account_id = $2
+			AND a.organization_id = $1
+	);
+`
+
+func (r *repository) IsTransactionMonthClosed(ctx context.Context, params transactionMonthClosureParams) (bool, error) {
+	var closed bool
+	err := r.db.Query(ctx, &closed, isTransactionMonthClosedQuery, params.OrganizationID, params.AccountID, params.TransactionDate)
+	return closed, err
+}
+
 const markMonthClosedQuery = `
 	-- financial.markMonthClosedQuery
 	INSERT INTO closed_months (organization_id, month, year)
@@ -1258,6 +1712,162 @@ func (r *repository) MarkMonthClosed(ctx context.Context, params monthClosurePar
 	return r.db.Run(ctx, markMonthClosedQuery, params.OrganizationID, params.Month, params.Year)
 }
 
+type reopenMonthParams struct {
+	OrganizationID int
+	UserID         int
+	Month          int
+	Year           int
+	Reason         string
+	CarryoverFitID string
+}
+
+const removeClosedMonthQuery = `
+	-- financial.removeClosedMonthQuery
+	DELETE FROM closed_months
+	WHERE organization_id = $1
+	  AND month = $2
+	  AND year = $3
+	RETURNING closed_at;
+`
+
+// Signed so the audit record tells a surplus from a deficit
+const removeCarryoverTransactionQuery = `
+	-- financial.removeCarryoverTransactionQuery
+	DELETE FROM transactions t
+	USING accounts a
+	WHERE a.account_id = t.account_id
+	  AND a.organization_id = $1
+	  AND t.ofx_fitid = $2
+	RETURNING CASE WHEN t.transaction_type = 'credit' THEN t.amount ELSE -t.amount END;
+`
+
+const removeMonthlySnapshotsQuery = `
+	-- financial.removeMonthlySnapshotsQuery
+	WITH removed AS (
+		DELETE FROM monthly_snapshots
+		WHERE organization_id = $1
+		  AND month = $2
+		  AND year = $3
+		RETURNING snapshot_id
+	)
+	SELECT COUNT(*) FROM removed;
+`
+
+const resetCategoryBudgetConsolidationQuery = `
+	-- financial.resetCategoryBudgetConsolidationQuery
+	UPDATE category_budgets
+	SET is_consolidated = FALSE,
+		consolidated_at = NULL,
+		updated_at = NOW()
+	WHERE organization_id = $1
+	  AND month = $2
+	  AND year = $3
+	  AND is_consolidated;
+`
+
+const insertMonthReopeningQuery = `
+	-- financial.insertMonthReopeningQuery
+	INSERT INTO month_reopenings (organization_id, user_id, month, year, reason, closed_at, carryover_amount, snapshot_count)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING month_reopening_id, created_at, organization_id, user_id, month, year, reason,
+			  closed_at, carryover_amount, snapshot_count;
+`
+
+// ReopenMonth undoes a month closing in one transaction: the closed_months row
+// goes first so the closed-month triggers allow the rest. The carry-over lives
+// in the following month, which must be open.
+func (r *repository) ReopenMonth(ctx context.Context, params reopenMonthParams) (MonthReopeningModel, error) {
+	var reopening MonthReopeningModel
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		var closedAt time.Time
+		if err := r.db.Query(ctx, &closedAt, removeClosedMonthQuery,
+			params.OrganizationID, params.Month, params.Year); err != nil {
+			return err
+		}
+
+		var carryovers []decimal.Decimal
+		if err := r.db.Query(ctx, &carryovers, removeCarryoverTransactionQuery,
+			params.OrganizationID, params.CarryoverFitID); err != nil {
+			return err
+		}
+		var carryoverAmount *decimal.Decimal
+		if len(carryovers) > 0 {
+			total := decimal.Sum(carryovers[0], carryovers[1:]...)
+			carryoverAmount = &total
+		}
+
+		var snapshotCount int
+		if err := r.db.Query(ctx, &snapshotCount, removeMonthlySnapshotsQuery,
+			params.OrganizationID, params.Month, params.Year); err != nil {
+			return err
+		}
+
+		if err := r.db.Run(ctx, resetCategoryBudgetConsolidationQuery,
+			params.OrganizationID, params.Month, params.Year); err != nil {
+			return err
+		}
+
+		return r.db.Query(ctx, &reopening, insertMonthReopeningQuery,
+			params.OrganizationID, params.UserID, params.Month, params.Year, params.Reason,
+			closedAt, carryoverAmount, snapshotCount)
+	})
+	return reopening, err
+}
+
+type fetchMonthReopeningsParams struct {
+	OrganizationID int
+}
+
+const fetchMonthReopeningsQuery = `
+	-- financial.fetchMonthReopeningsQuery
+	SELECT
+		month_reopening_id, created_at, organization_id, user_id, month, year, reason,
+		closed_at, carryover_amount, snapshot_count
+	FROM month_reopenings
+	WHERE organization_id = $1
+	ORDER BY created_at DESC;
+`
+
+func (r *repository) FetchMonthReopenings(ctx context.Context, params fetchMonthReopeningsParams) ([]MonthReopeningModel, error) {
+	var reopenings []MonthReopeningModel
+	err := r.db.Query(ctx, &reopenings, fetchMonthReopeningsQuery, params.OrganizationID)
+	return reopenings, err
+}
+
+type fetchAutoCloseMonthsParams struct {
+	OrganizationID int
+}
+
+const fetchAutoCloseMonthsQuery = `
+	-- financial.fetchAutoCloseMonthsQuery
+	SELECT auto_close_months FROM organizations WHERE organization_id = $1;
+`
+
+func (r *repository) FetchAutoCloseMonths(ctx context.Context, params fetchAutoCloseMonthsParams) (bool, error) {
+	var enabled bool
+	err := r.db.Query(ctx, &enabled, fetchAutoCloseMonthsQuery, params.OrganizationID)
+	return enabled, err
+}
+
+type modifyAutoCloseMonthsParams struct {
+	OrganizationID int
+	Enabled        bool
+}
+
+const modifyAutoCloseMonthsQuery = `
+	-- financial.modifyAutoCloseMonthsQuery
+	UPDATE organizations
+	SET auto_close_months = $2
+	WHERE organization_id = $1
+	RETURNING auto_close_months;
+`
+
+func (r *repository) ModifyAutoCloseMonths(ctx context.Context, params modifyAutoCloseMonthsParams) (bool, error) {
+	var enabled bool
+	err := r.db.Query(ctx, &enabled, modifyAutoCloseMonthsQuery, params.OrganizationID, params.Enabled)
+	return enabled, err
+}
+
 // ============================================================================
 // Planned Entries
 // ============================================================================
@@ -1291,6 +1901,12 @@ const fetchPlannedEntriesQuery = `
 		entry_type,
 		is_recurrent,
 		parent_entry_id,
+		recurrence_frequency,
+		recurrence_interval,
+		recurrence_months,
+		starts_on,
+		ends_on,
+		occurrence_count,
 		target_month,
 		target_year,
 		is_active
@@ -1311,10 +1927,14 @@ func (r *repository) FetchPlannedEntries(ctx context.Context, params fetchPlanne
 }
 
 // FetchPlannedEntrySumsByCategory returns the sum of planned entry amounts grouped by category.
-// Only includes active, recurrent, expense-type entries.
+// Only includes active expense-type entries that are recurrent, or instances of
+// one, falling in the month. Entries count once per occurrence (a weekly entry
+// falls in a month four or five times).
 // Used to calculate the "planned" portion of category budgets.
 type fetchPlannedEntrySumsByCategoryParams struct {
 	OrganizationID int
+	Month          int
+	Year           int
 }
 
 type categoryPlannedSum struct {
@@ -1325,19 +1945,20 @@ type categoryPlannedSum struct {
 const fetchPlannedEntrySumsByCategoryQuery = `
 	-- financial.fetchPlannedEntrySumsByCategoryQuery
 	SELECT
-		category_id,
-		COALESCE(SUM(amount), 0) AS planned_amount
-	FROM planned_entries
-	WHERE organization_id = $1
-		AND is_active = true
-		AND is_recurrent = true
-		AND entry_type = 'expense'
-	GROUP BY category_id;
+		pe.category_id,
+		COALESCE(SUM(pe.amount * planned_entry_occurrences(pe, $2, $3)), 0) AS planned_amount
+	FROM planned_entries pe
+	WHERE pe.organization_id = $1
+		AND pe.is_active = true
+		AND (pe.is_recurrent = true OR pe.parent_entry_id IS NOT NULL)
+		AND pe.entry_type = 'expense'
+	GROUP BY pe.category_id;
 `
 
 func (r *repository) FetchPlannedEntrySumsByCategory(ctx context.Context, params fetchPlannedEntrySumsByCategoryParams) (map[int]decimal.Decimal, error) {
 	var sums []categoryPlannedSum
-	err := r.db.Query(ctx, &sums, fetchPlannedEntrySumsByCategoryQuery, params.OrganizationID)
+	err := r.db.Query(ctx, &sums, fetchPlannedEntrySumsByCategoryQuery,
+		params.OrganizationID, params.Month, params.Year)
 	if err != nil {
 		return nil, err
 	}
@@ -1349,6 +1970,44 @@ func (r *repository) FetchPlannedEntrySumsByCategory(ctx context.Context, params
 	return result, nil
 }
 
+type fetchPlannedEntrySettlementAccountsParams struct {
+	OrganizationID int
+}
+
+type plannedEntrySettlementAccount struct {
+	PlannedEntryID int `db:"planned_entry_id"`
+	AccountID      int `db:"account_id"`
+}
+
+// Returns, per planned entry, the account of the transaction it was last
+// matched to: where the entry is expected to be paid or received again.
+const fetchPlannedEntrySettlementAccountsQuery = `
+	-- financial.fetchPlannedEntrySettlementAccountsQuery
+	SELECT DISTINCT ON (pes.planned_entry_id)
+		pes.planned_entry_id,
+		t.account_id
+	FROM planned_entry_statuses pes
+	INNER JOIN planned_entries pe ON pe.planned_entry_id = pes.planned_entry_id
+	INNER JOIN transactions t ON t.transaction_id = pes.matched_transaction_id
+	WHERE pe.organization_id = $1
+		AND pes.status = 'matched'
+	ORDER BY pes.planned_entry_id, pes.year DESC, pes.month DESC;
+`
+
+func (r *repository) FetchPlannedEntrySettlementAccounts(ctx context.Context, params fetchPlannedEntrySettlementAccountsParams) (map[int]int, error) {
+	var rows []plannedEntrySettlementAccount
+	err := r.db.Query(ctx, &rows, fetchPlannedEntrySettlementAccountsQuery, params.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]int, len(rows))
+	for _, row := range rows {
+		result[row.PlannedEntryID] = row.AccountID
+	}
+	return result, nil
+}
+
 type fetchPlannedEntryByIDParams struct {
 	PlannedEntryID int
 	UserID         int
@@ -1376,6 +2035,12 @@ const fetchPlannedEntryByIDQuery = `
 		entry_type,
 		is_recurrent,
 		parent_entry_id,
+		recurrence_frequency,
+		recurrence_interval,
+		recurrence_months,
+		starts_on,
+		ends_on,
+		occurrence_count,
 		target_month,
 		target_year,
 		is_active
@@ -1418,6 +2083,12 @@ const fetchPlannedEntriesByParentQuery = `
 		entry_type,
 		is_recurrent,
 		parent_entry_id,
+		recurrence_frequency,
+		recurrence_interval,
+		recurrence_months,
+		starts_on,
+		ends_on,
+		occurrence_count,
 		target_month,
 		target_year,
 		is_active
@@ -1453,6 +2124,14 @@ type insertPlannedEntryParams struct {
 	ParentEntryID    *int
 	TargetMonth      *int
 	TargetYear       *int
+
+	// Recurrence rule; nil frequency and interval mean every month
+	RecurrenceFrequency *string
+	RecurrenceInterval  *int
+	RecurrenceMonths    []int
+	StartsOn            *time.Time
+	EndsOn              *time.Time
+	OccurrenceCount     *int
 }
 
 const insertPlannedEntryQuery = `
@@ -1474,8 +2153,17 @@ const insertPlannedEntryQuery = `
 		is_recurrent,
 		parent_entry_id,
 		target_month,
-		target_year
-	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		target_year,
+		recurrence_frequency,
+		recurrence_interval,
+		recurrence_months,
+		starts_on,
+		ends_on,
+		occurrence_count
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+		COALESCE($18, 'monthly'), COALESCE($19, 1), $20, $21, $22, $23
+	)
 	RETURNING
 		planned_entry_id,
 		created_at,
@@ -1495,6 +2183,12 @@ const insertPlannedEntryQuery = `
 		entry_type,
 		is_recurrent,
 		parent_entry_id,
+		recurrence_frequency,
+		recurrence_interval,
+		recurrence_months,
+		starts_on,
+		ends_on,
+		occurrence_count,
 		target_month,
 		target_year,
 		is_active;
@@ -1507,7 +2201,9 @@ func (r *repository) InsertPlannedEntry(ctx context.Context, params insertPlanne
 		params.SavingsGoalID, params.Description, params.Amount, params.AmountMin, params.AmountMax,
 		params.ExpectedDayStart, params.ExpectedDayEnd, params.ExpectedDay,
 		params.EntryType, params.IsRecurrent, params.ParentEntryID,
-		params.TargetMonth, params.TargetYear)
+		params.TargetMonth, params.TargetYear,
+		params.RecurrenceFrequency, params.RecurrenceInterval, params.RecurrenceMonths,
+		params.StartsOn, params.EndsOn, params.OccurrenceCount)
 	return entry, err
 }
 
@@ -1527,6 +2223,17 @@ type modifyPlannedEntryParams struct {
 	EntryType        *string
 	IsActive         *bool
 	CategoryID       *int
+
+	// Recurrence rule. A non-nil RecurrenceMonths replaces the months (empty
+	// clears them); ClearEndsOn and ClearOccurrenceCount remove those limits.
+	RecurrenceFrequency  *string
+	RecurrenceInterval   *int
+	RecurrenceMonths     *[]int
+	StartsOn             *time.Time
+	EndsOn               *time.Time
+	ClearEndsOn          bool
+	OccurrenceCount      *int
+	ClearOccurrenceCount bool
 }
 
 const modifyPlannedEntryQuery = `
@@ -1553,6 +2260,15 @@ const modifyPlannedEntryQuery = `
 		entry_type = COALESCE($13, entry_type),
 		is_active = COALESCE($14, is_active),
 		category_id = COALESCE($15, category_id),
+		recurrence_frequency = COALESCE($16, recurrence_frequency),
+		recurrence_interval = COALESCE($17, recurrence_interval),
+		recurrence_months = CASE
+			WHEN $18::bool THEN NULLIF($19::int[], '{}')
+			ELSE recurrence_months
+		END,
+		starts_on = COALESCE($20, starts_on),
+		ends_on = CASE WHEN $21::bool THEN NULL ELSE COALESCE($22, ends_on) END,
+		occurrence_count = CASE WHEN $23::bool THEN NULL ELSE COALESCE($24, occurrence_count) END,
 		updated_at = CURRENT_TIMESTAMP
 	WHERE planned_entry_id = $1
 		AND user_id = $2
@@ -1576,18 +2292,33 @@ const modifyPlannedEntryQuery = `
 		entry_type,
 		is_recurrent,
 		parent_entry_id,
+		recurrence_frequency,
+		recurrence_interval,
+		recurrence_months,
+		starts_on,
+		ends_on,
+		occurrence_count,
 		target_month,
 		target_year,
 		is_active;
 `
 
 func (r *repository) ModifyPlannedEntry(ctx context.Context, params modifyPlannedEntryParams) (PlannedEntryModel, error) {
+	var recurrenceMonths []int
+	if params.RecurrenceMonths != nil {
+		recurrenceMonths = *params.RecurrenceMonths
+	}
+
 	var entry PlannedEntryModel
 	err := r.db.Query(ctx, &entry, modifyPlannedEntryQuery,
 		params.PlannedEntryID, params.UserID, params.OrganizationID,
 		params.PatternID, params.SavingsGoalID, params.Description, params.Amount, params.AmountMin,
 		params.AmountMax, params.ExpectedDayStart, params.ExpectedDayEnd,
-		params.ExpectedDay, params.EntryType, params.IsActive, params.CategoryID)
+		params.ExpectedDay, params.EntryType, params.IsActive, params.CategoryID,
+		params.RecurrenceFrequency, params.RecurrenceInterval,
+		params.RecurrenceMonths != nil, recurrenceMonths,
+		params.StartsOn, params.ClearEndsOn, params.EndsOn,
+		params.ClearOccurrenceCount, params.OccurrenceCount)
 	return entry, err
 }
 
@@ -1761,12 +2492,13 @@ const fetchAdvancedPatternsQuery = `
 		target_description,
 		target_category_id,
 		apply_retroactively,
+		priority,
 		is_active
 	FROM patterns
 	WHERE organization_id = $1
 		AND ($2::boolean IS NULL OR is_active = $2)
 		AND ($3::integer IS NULL OR target_category_id = $3)
-	ORDER BY created_at DESC;
+	ORDER BY priority ASC, pattern_id DESC;
 `
 
 func (r *repository) FetchAdvancedPatterns(ctx context.Context, params fetchAdvancedPatternsParams) ([]AdvancedPatternModel, error) {
@@ -1802,6 +2534,7 @@ const fetchAdvancedPatternByIDQuery = `
 		target_description,
 		target_category_id,
 		apply_retroactively,
+		priority,
 		is_active
 	FROM patterns
 	WHERE pattern_id = $1
@@ -1827,6 +2560,7 @@ type insertAdvancedPatternParams struct {
 	TargetDescription  *string
 	TargetCategoryID   *int
 	ApplyRetroactively bool
+	Priority           *int // nil puts the pattern ahead of the organization's others
 }
 
 const insertAdvancedPatternQuery = `
@@ -1842,8 +2576,10 @@ const insertAdvancedPatternQuery = `
 		action,
 		target_description,
 		target_category_id,
-		apply_retroactively
-	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		apply_retroactively,
+		priority
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+		COALESCE($12, (SELECT MIN(priority) - 10 FROM patterns WHERE organization_id = $2), 100))
 	RETURNING
 		pattern_id,
 		created_at,
@@ -1859,6 +2595,7 @@ const insertAdvancedPatternQuery = `
 		target_description,
 		target_category_id,
 		apply_retroactively,
+		priority,
 		is_active;
 `
 
@@ -1867,7 +2604,7 @@ func (r *repository) InsertAdvancedPattern(ctx context.Context, params insertAdv
 	err := r.db.Query(ctx, &pattern, insertAdvancedPatternQuery,
 		params.UserID, params.OrganizationID, params.DescriptionPattern,
 		params.DatePattern, params.WeekdayPattern, params.AmountMin, params.AmountMax,
-		params.Action, params.TargetDescription, params.TargetCategoryID, params.ApplyRetroactively)
+		params.Action, params.TargetDescription, params.TargetCategoryID, params.ApplyRetroactively, params.Priority)
 	return pattern, err
 }
 
@@ -1887,6 +2624,7 @@ type modifyAdvancedPatternParams struct {
 	TargetCategoryID     *int
 	TargetCategoryIDSet  bool
 	ApplyRetroactively   *bool
+	Priority             *int
 }
 
 const modifyAdvancedPatternQuery = `
@@ -1903,6 +2641,7 @@ const modifyAdvancedPatternQuery = `
 		target_description = CASE WHEN $10 THEN $11 ELSE target_description END,
 		target_category_id = CASE WHEN $12 THEN $13 ELSE target_category_id END,
 		apply_retroactively = COALESCE($14, apply_retroactively),
+		priority = COALESCE($15, priority),
 		updated_at = CURRENT_TIMESTAMP
 	WHERE pattern_id = $1
 		AND organization_id = $2
@@ -1921,6 +2660,7 @@ const modifyAdvancedPatternQuery = `
 		target_description,
 		target_category_id,
 		apply_retroactively,
+		priority,
 		is_active;
 `
 
@@ -1941,10 +2681,53 @@ func (r *repository) ModifyAdvancedPattern(ctx context.Context, params modifyAdv
 		params.TargetCategoryIDSet,
 		params.TargetCategoryID,
 		params.ApplyRetroactively,
+		params.Priority,
 	)
 	return pattern, err
 }
 
+type modifyAdvancedPatternPrioritiesParams struct {
+	OrganizationID int
+	PatternIDs     []int // In evaluation order
+}
+
+// Patterns are renumbered 10, 20, 30... as classification rules are.
+const modifyAdvancedPatternPrioritiesQuery = `
+	-- financial.modifyAdvancedPatternPrioritiesQuery
+	UPDATE patterns p
+	SET priority = ordered.position * 10,
+		updated_at = CURRENT_TIMESTAMP
+	FROM UNNEST($2::int[]) WITH ORDINALITY AS ordered(pattern_id, position)
+	WHERE p.pattern_id = ordered.pattern_id
+		AND p.organization_id = $1
+	RETURNING
+		p.pattern_id,
+		p.created_at,
+		p.updated_at,
+		p.user_id,
+		p.organization_id,
+		p.description_pattern,
+		p.date_pattern,
+		p.weekday_pattern,
+		p.amount_min,
+		p.amount_max,
+		p.action,
+		p.target_description,
+		p.target_category_id,
+		p.apply_retroactively,
+		p.priority,
+		p.is_active;
+`
+
+func (r *repository) ModifyAdvancedPatternPriorities(ctx context.Context, params modifyAdvancedPatternPrioritiesParams) ([]AdvancedPatternModel, error) {
+	var result []AdvancedPatternModel
+	err := r.db.Query(ctx, &result, modifyAdvancedPatternPrioritiesQuery, params.OrganizationID, params.PatternIDs)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 type removeAdvancedPatternParams struct {
 	PatternID      int
 	UserID         int
@@ -2675,11 +3458,14 @@ const fetchGoalContributionsQuery = `
 		t.raw_ofx_data,
 		t.is_classified,
 		t.classification_rule_id,
+		t.pattern_id,
 		t.is_ignored,
 		t.needs_review,
 		t.savings_goal_id,
 		t.notes,
-		t.tags
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
 	FROM transactions t
 	INNER JOIN accounts a ON a.account_id = t.account_id
 	WHERE t.savings_goal_id = $1
@@ -2700,12 +3486,14 @@ type fetchGoalMonthlyContributionsParams struct {
 	OrganizationID int
 }
 
+// Contributions without an exchange rate are left out; GetSavingsGoalProgress
+// counts them.
 const fetchGoalMonthlyContributionsQuery = `
 	-- financial.fetchGoalMonthlyContributionsQuery
 	SELECT
 		EXTRACT(MONTH FROM t.transaction_date)::int as month,
 		EXTRACT(YEAR FROM t.transaction_date)::int as year,
-		SUM(ABS(t.amount)) as amount
+		COALESCE(SUM(ABS(to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date))), 0) as amount
 	FROM transactions t
 	INNER JOIN accounts a ON a.account_id = t.account_id
 	WHERE t.savings_goal_id = $1
@@ -2760,25 +3548,38 @@ type fetchTagSpendingByMonthParams struct {
 
 // Aggregates expense (debit) spending per tag for a single month, scoped to the
 // organization. Only tags with at least one matching transaction are returned.
+// A split transaction is tagged through its splits, each counting its own amount.
+// Months are budget months (see transaction_budget_date); amounts are in the
+// base currency, and ones without a known rate are counted instead of summed.
 const fetchTagSpendingByMonthQuery = `
 	-- financial.fetchTagSpendingByMonthQuery
+	WITH tagged_allocations AS (
+		SELECT tt.tag_id, ta.*
+		FROM transaction_allocations ta
+		INNER JOIN transaction_tags tt ON tt.transaction_id = ta.transaction_id
+		WHERE ta.split_id IS NULL
+		UNION ALL
+		SELECT sts.tag_id, ta.*
+		FROM transaction_allocations ta
+		INNER JOIN transaction_split_tags sts ON sts.split_id = ta.split_id
+	)
 	SELECT
 		t.tag_id,
 		t.name,
 		t.icon,
 		t.color,
-		COALESCE(SUM(tx.amount), 0) AS total,
-		COUNT(tx.transaction_id) AS transaction_count
+		COALESCE(SUM(to_base_amount(tx.amount, a.organization_id, a.currency, tx.transaction_date)), 0) AS total,
+		COUNT(DISTINCT tx.transaction_id) AS transaction_count,
+		COUNT(DISTINCT tx.transaction_id) FILTER (WHERE to_base_amount(tx.amount, a.organization_id, a.currency, tx.transaction_date) IS NULL) AS missing_rates
 	FROM tags t
-	INNER JOIN transaction_tags tt ON tt.tag_id = t.tag_id
-	INNER JOIN transactions tx ON tx.transaction_id = tt.transaction_id
+	INNER JOIN tagged_allocations tx ON tx.tag_id = t.tag_id
 	INNER JOIN accounts a ON a.account_id = tx.account_id
 	WHERE t.organization_id = $1
 		AND a.organization_id = $1
 		AND tx.transaction_type = 'debit'
 		AND tx.is_ignored = false
-		AND EXTRACT(MONTH FROM tx.transaction_date) = $2
-		AND EXTRACT(YEAR FROM tx.transaction_date) = $3
+		AND EXTRACT(MONTH FROM transaction_budget_date(tx.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day)) = $2
+		AND EXTRACT(YEAR FROM transaction_budget_date(tx.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day)) = $3
 	GROUP BY t.tag_id, t.name, t.icon, t.color
 	ORDER BY total DESC;
 `
@@ -2797,8 +3598,9 @@ type fetchTagPlannedByMonthParams struct {
 }
 
 // Aggregates the planned expense budget per tag for a single month, scoped to
-// the organization. Includes recurrent active entries (apply every month) plus
-// one-time active entries targeting this month/year. Entries dismissed for the
+// the organization. Includes recurrent active entries whose rule falls in the
+// month, once per occurrence, plus one-time active entries targeting this
+// month/year. Entries dismissed for the
 // month are excluded because the user explicitly removed them from this month's
 // plan; entries already matched are kept, since the budget still planned for
 // them (they show as spent on the actual side). Only tags with a positive
@@ -2810,7 +3612,7 @@ const fetchTagPlannedByMonthQuery = `
 		t.name,
 		t.icon,
 		t.color,
-		COALESCE(SUM(pe.amount), 0) AS total
+		COALESCE(SUM(pe.amount * planned_entry_occurrences(pe, $2, $3)), 0) AS total
 	FROM tags t
 	INNER JOIN planned_entry_tags pet ON pet.tag_id = t.tag_id
 	INNER JOIN planned_entries pe ON pe.planned_entry_id = pet.planned_entry_id
@@ -2822,13 +3624,10 @@ const fetchTagPlannedByMonthQuery = `
 		AND pe.organization_id = $1
 		AND pe.is_active = true
 		AND pe.entry_type = 'expense'
-		AND (
-			pe.is_recurrent = true
-			OR (pe.target_month = $2 AND pe.target_year = $3)
-		)
+		AND planned_entry_occurrences(pe, $2, $3) > 0
 		AND (pes.status IS NULL OR pes.status <> 'dismissed')
 	GROUP BY t.tag_id, t.name, t.icon, t.color
-	HAVING COALESCE(SUM(pe.amount), 0) > 0
+	HAVING COALESCE(SUM(pe.amount * planned_entry_occurrences(pe, $2, $3)), 0) > 0
 	ORDER BY total DESC;
 `
 
@@ -2846,14 +3645,15 @@ type fetchTagPlannedEntriesByMonthParams struct {
 }
 
 // Lists planned entries under each tag for a month, excluding dismissed entries.
-// A matched status means the planned entry was paid.
+// Amounts cover every occurrence in the month. A matched status means the
+// planned entry was paid.
 const fetchTagPlannedEntriesByMonthQuery = `
 	-- financial.fetchTagPlannedEntriesByMonthQuery
 	SELECT
 		t.tag_id,
 		pe.planned_entry_id,
 		pe.description,
-		pe.amount,
+		pe.amount * planned_entry_occurrences(pe, $2, $3) AS amount,
 		COALESCE(pes.status, 'scheduled') AS status,
 		COALESCE(pes.status = 'matched', false) AS paid
 	FROM tags t
@@ -2867,10 +3667,7 @@ const fetchTagPlannedEntriesByMonthQuery = `
 		AND pe.organization_id = $1
 		AND pe.is_active = true
 		AND pe.entry_type = 'expense'
-		AND (
-			pe.is_recurrent = true
-			OR (pe.target_month = $2 AND pe.target_year = $3)
-		)
+		AND planned_entry_occurrences(pe, $2, $3) > 0
 		AND (pes.status IS NULL OR pes.status <> 'dismissed')
 	ORDER BY
 		t.tag_id,
@@ -3053,6 +3850,40 @@ func (r *repository) FetchTagsByTransactionID(ctx context.Context, params fetchT
 	return tags, err
 }
 
+type fetchTransactionTagNamesParams struct {
+	TransactionIDs []int
+}
+
+type transactionTagName struct {
+	TransactionID int    `db:"transaction_id"`
+	Name          string `db:"name"`
+}
+
+const fetchTransactionTagNamesQuery = `
+	-- financial.fetchTransactionTagNamesQuery
+	SELECT tt.transaction_id, t.name
+	FROM transaction_tags tt
+	INNER JOIN tags t ON t.tag_id = tt.tag_id
+	WHERE tt.transaction_id = ANY($1::INT[])
+	ORDER BY tt.transaction_id, t.name ASC;
+`
+
+// FetchTransactionTagNames returns the tag names of each of the transactions,
+// keyed by transaction ID. Transactions without tags are left out.
+func (r *repository) FetchTransactionTagNames(ctx context.Context, params fetchTransactionTagNamesParams) (map[int][]string, error) {
+	var rows []transactionTagName
+	err := r.db.Query(ctx, &rows, fetchTransactionTagNamesQuery, params.TransactionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int][]string)
+	for _, row := range rows {
+		result[row.TransactionID] = append(result[row.TransactionID], row.Name)
+	}
+	return result, nil
+}
+
 type setTransactionTagsParams struct {
 	TransactionID int
 	TagIDs        []int
@@ -3088,6 +3919,980 @@ func (r *repository) SetTransactionTags(ctx context.Context, params setTransacti
 	return nil
 }
 
+// ============================================================================
+// Transaction Splits
+// ============================================================================
+
+type fetchTransactionSplitsParams struct {
+	TransactionID  int
+	OrganizationID int
+}
+
+const fetchTransactionSplitsQuery = `
+	-- financial.fetchTransactionSplitsQuery
+	SELECT
+		s.split_id,
+		s.created_at,
+		s.updated_at,
+		s.transaction_id,
+		s.category_id,
+		s.savings_goal_id,
+		s.amount,
+		s.notes,
+		COALESCE(ARRAY_AGG(sts.tag_id ORDER BY sts.tag_id) FILTER (WHERE sts.tag_id IS NOT NULL), '{}') AS tag_ids
+	FROM transaction_splits s
+	INNER JOIN transactions t ON t.transaction_id = s.transaction_id
+	INNER JOIN accounts a ON a.account_id = t.account_id
+	LEFT JOIN transaction_split_tags sts ON sts.split_id = s.split_id
+	WHERE s.transaction_id = $1
+		AND a.organization_id = $2
+	GROUP BY s.split_id
+	ORDER BY s.split_id;
+`
+
+func (r *repository) FetchTransactionSplits(ctx context.Context, params fetchTransactionSplitsParams) ([]TransactionSplitModel, error) {
+	var splits []TransactionSplitModel
+	err := r.db.Query(ctx, &splits, fetchTransactionSplitsQuery,
+		params.TransactionID, params.OrganizationID)
+	return splits, err
+}
+
+type fetchTransactionSplitsByMonthParams struct {
+	OrganizationID int
+	Month          int
+	Year           int
+}
+
+// Months are budget months, matching fetchTransactionsByMonthQuery with
+// BudgetMonth set.
+const fetchTransactionSplitsByMonthQuery = `
+	-- financial.fetchTransactionSplitsByMonthQuery
+	SELECT
+		s.split_id,
+		s.created_at,
+		s.updated_at,
+		s.transaction_id,
+		s.category_id,
+		s.savings_goal_id,
+		s.amount,
+		s.notes,
+		COALESCE(ARRAY_AGG(sts.tag_id ORDER BY sts.tag_id) FILTER (WHERE sts.tag_id IS NOT NULL), '{}') AS tag_ids
+	FROM transaction_splits s
+	INNER JOIN transactions t ON t.transaction_id = s.transaction_id
+	INNER JOIN accounts a ON a.account_id = t.account_id
+	LEFT JOIN transaction_split_tags sts ON sts.split_id = s.split_id
+	WHERE a.organization_id = $1
+		AND EXTRACT(MONTH FROM transaction_budget_date(t.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day)) = $2
+		AND EXTRACT(YEAR FROM transaction_budget_date(t.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day)) = $3
+	GROUP BY s.split_id
+	ORDER BY s.transaction_id, s.split_id;
+`
+
+func (r *repository) FetchTransactionSplitsByMonth(ctx context.Context, params fetchTransactionSplitsByMonthParams) ([]TransactionSplitModel, error) {
+	var splits []TransactionSplitModel
+	err := r.db.Query(ctx, &splits, fetchTransactionSplitsByMonthQuery,
+		params.OrganizationID, params.Month, params.Year)
+	return splits, err
+}
+
+type transactionSplitParams struct {
+	CategoryID    *int
+	SavingsGoalID *int
+	Amount        decimal.Decimal
+	Notes         *string
+	TagIDs        []int
+}
+
+type replaceTransactionSplitsParams struct {
+	TransactionID int
+	Splits        []transactionSplitParams // Empty removes every split
+}
+
+const removeTransactionSplitsQuery = `
+	-- financial.removeTransactionSplitsQuery
+	DELETE FROM transaction_splits WHERE transaction_id = $1;
+`
+
+const insertTransactionSplitQuery = `
+	-- financial.insertTransactionSplitQuery
+	INSERT INTO transaction_splits (transaction_id, category_id, savings_goal_id, amount, notes)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING
+		split_id,
+		created_at,
+		updated_at,
+		transaction_id,
+		category_id,
+		savings_goal_id,
+		amount,
+		notes;
+`
+
+const insertTransactionSplitTagQuery = `
+	-- financial.insertTransactionSplitTagQuery
+	INSERT INTO transaction_split_tags (split_id, tag_id)
+	VALUES ($1, $2)
+	ON CONFLICT (split_id, tag_id) DO NOTHING;
+`
+
+// ReplaceTransactionSplits swaps the transaction's splits for the given ones
+// in a single transaction, so aggregates never see a partial set.
+func (r *repository) ReplaceTransactionSplits(ctx context.Context, params replaceTransactionSplitsParams) ([]TransactionSplitModel, error) {
+	splits := make([]TransactionSplitModel, 0, len(params.Splits))
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		if err := r.db.Run(ctx, removeTransactionSplitsQuery, params.TransactionID); err != nil {
+			return err
+		}
+
+		for _, split := range params.Splits {
+			var model TransactionSplitModel
+			if err := r.db.Query(ctx, &model, insertTransactionSplitQuery,
+				params.TransactionID, split.CategoryID, split.SavingsGoalID,
+				split.Amount, split.Notes); err != nil {
+				return err
+			}
+
+			for _, tagID := range split.TagIDs {
+				if err := r.db.Run(ctx, insertTransactionSplitTagQuery, model.SplitID, tagID); err != nil {
+					return err
+				}
+				model.TagIDs = append(model.TagIDs, int64(tagID))
+			}
+			splits = append(splits, model)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return splits, nil
+}
+
+// ============================================================================
+// Transfers
+// ============================================================================
+
+// transferColumnsSQL selects a transfer with both legs; it expects the transfer
+// as tr and joins the legs as d and c.
+const transferColumnsSQL = `
+		tr.transfer_id,
+		tr.created_at,
+		tr.organization_id,
+		tr.detection,
+		d.transaction_id AS debit_transaction_id,
+		d.account_id AS debit_account_id,
+		d.description AS debit_description,
+		d.transaction_date AS debit_date,
+		d.amount AS debit_amount,
+		c.transaction_id AS credit_transaction_id,
+		c.account_id AS credit_account_id,
+		c.description AS credit_description,
+		c.transaction_date AS credit_date,
+		c.amount AS credit_amount`
+
+type fetchTransfersParams struct {
+	OrganizationID int
+}
+
+const fetchTransfersQuery = `
+	-- financial.fetchTransfersQuery
+	SELECT` + transferColumnsSQL + `
+	FROM transfers tr
+	INNER JOIN transactions d ON d.transaction_id = tr.debit_transaction_id
+	INNER JOIN transactions c ON c.transaction_id = tr.credit_transaction_id
+	WHERE tr.organization_id = $1
+	ORDER BY d.transaction_date DESC, tr.transfer_id DESC;
+`
+
+func (r *repository) FetchTransfers(ctx context.Context, params fetchTransfersParams) ([]TransferModel, error) {
+	var transfers []TransferModel
+	err := r.db.Query(ctx, &transfers, fetchTransfersQuery, params.OrganizationID)
+	return transfers, err
+}
+
+type fetchTransferTransactionIDsParams struct {
+	OrganizationID int
+}
+
+// Both legs of every transfer in the organization.
+const fetchTransferTransactionIDsQuery = `
+	-- financial.fetchTransferTransactionIDsQuery
+	SELECT debit_transaction_id FROM transfers WHERE organization_id = $1
+	UNION ALL
+	SELECT credit_transaction_id FROM transfers WHERE organization_id = $1;
+`
+
+func (r *repository) FetchTransferTransactionIDs(ctx context.Context, params fetchTransferTransactionIDsParams) ([]int, error) {
+	var ids []int
+	err := r.db.Query(ctx, &ids, fetchTransferTransactionIDsQuery, params.OrganizationID)
+	return ids, err
+}
+
+type fetchTransferCandidatesParams struct {
+	OrganizationID int
+	From           time.Time
+	To             time.Time
+}
+
+// Transactions that could be a transfer leg: not ignored, not already linked
+// and not a month-closing carry-over.
+const fetchTransferCandidatesQuery = `
+	-- financial.fetchTransferCandidatesQuery
+	SELECT
+		t.transaction_id,
+		t.created_at,
+		t.updated_at,
+		t.account_id,
+		t.category_id,
+		t.description,
+		t.original_description,
+		t.amount,
+		t.transaction_date,
+		t.transaction_type,
+		t.ofx_fitid,
+		t.ofx_check_number,
+		t.ofx_memo,
+		t.raw_ofx_data,
+		t.is_classified,
+		t.classification_rule_id,
+		t.pattern_id,
+		t.is_ignored,
+		t.needs_review,
+		t.savings_goal_id,
+		t.notes,
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
+	FROM transactions t
+	INNER JOIN accounts a ON a.account_id = t.account_id
+	WHERE a.organization_id = $1
+		AND t.transaction_date BETWEEN $2::DATE AND $3::DATE
+		AND t.is_ignored = false
+		AND (t.ofx_fitid IS NULL OR t.ofx_fitid NOT LIKE 'CARRYOVER-%')
+		AND NOT EXISTS (
+			SELECT 1 FROM transfers tr
+			WHERE tr.debit_transaction_id = t.transaction_id
+				OR tr.credit_transaction_id = t.transaction_id
+		)
+	ORDER BY t.transaction_date, t.transaction_id;
+`
+
+func (r *repository) FetchTransferCandidates(ctx context.Context, params fetchTransferCandidatesParams) ([]TransactionModel, error) {
+	var transactions []TransactionModel
+	err := r.db.Query(ctx, &transactions, fetchTransferCandidatesQuery,
+		params.OrganizationID, params.From, params.To)
+	return transactions, err
+}
+
+type insertTransferParams struct {
+	OrganizationID      int
+	DebitTransactionID  int
+	CreditTransactionID int
+	Detection           string
+}
+
+const insertTransferQuery = `
+	-- financial.insertTransferQuery
+	WITH tr AS (
+		INSERT INTO transfers (organization_id, debit_transaction_id, credit_transaction_id, detection)
+		VALUES ($1, $2, $3, $4)
+		RETURNING transfer_id, created_at, organization_id, detection, debit_transaction_id, credit_transaction_id
+	)
+	SELECT` + transferColumnsSQL + `
+	FROM tr
+	INNER JOIN transactions d ON d.transaction_id = tr.debit_transaction_id
+	INNER JOIN transactions c ON c.transaction_id = tr.credit_transaction_id;
+`
+
+func (r *repository) InsertTransfer(ctx context.Context, params insertTransferParams) (TransferModel, error) {
+	var transfer TransferModel
+	err := r.db.Query(ctx, &transfer, insertTransferQuery,
+		params.OrganizationID, params.DebitTransactionID, params.CreditTransactionID, params.Detection)
+	return transfer, err
+}
+
+type removeTransferParams struct {
+	TransferID     int
+	OrganizationID int
+}
+
+const removeTransferQuery = `
+	-- financial.removeTransferQuery
+	DELETE FROM transfers
+	WHERE transfer_id = $1
+		AND organization_id = $2
+	RETURNING transfer_id;
+`
+
+func (r *repository) RemoveTransfer(ctx context.Context, params removeTransferParams) error {
+	var deletedID int
+	return r.db.Query(ctx, &deletedID, removeTransferQuery,
+		params.TransferID, params.OrganizationID)
+}
+
+// ============================================================================
+// Credit Card Invoices
+// ============================================================================
+
+type fetchCreditCardInvoicesParams struct {
+	AccountID      int
+	OrganizationID int
+}
+
+const fetchCreditCardInvoicesQuery = `
+	-- financial.fetchCreditCardInvoicesQuery
+	SELECT
+		i.credit_card_invoice_id,
+		i.created_at,
+		i.updated_at,
+		i.organization_id,
+		i.account_id,
+		i.month,
+		i.year,
+		i.payment_transaction_id,
+		ABS(t.amount) AS payment_amount,
+		t.transaction_date AS payment_date
+	FROM credit_card_invoices i
+	LEFT JOIN transactions t ON t.transaction_id = i.payment_transaction_id
+	WHERE i.account_id = $1
+		AND i.organization_id = $2
+	ORDER BY i.year, i.month;
+`
+
+func (r *repository) FetchCreditCardInvoices(ctx context.Context, params fetchCreditCardInvoicesParams) ([]CreditCardInvoiceModel, error) {
+	var invoices []CreditCardInvoiceModel
+	err := r.db.Query(ctx, &invoices, fetchCreditCardInvoicesQuery,
+		params.AccountID, params.OrganizationID)
+	return invoices, err
+}
+
+type upsertCreditCardInvoicePaymentParams struct {
+	OrganizationID       int
+	AccountID            int
+	Month                int
+	Year                 int
+	PaymentTransactionID *int // nil clears the payment
+}
+
+const upsertCreditCardInvoicePaymentQuery = `
+	-- financial.upsertCreditCardInvoicePaymentQuery
+	WITH upserted AS (
+		INSERT INTO credit_card_invoices (organization_id, account_id, month, year, payment_transaction_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (account_id, month, year) DO UPDATE
+		SET payment_transaction_id = EXCLUDED.payment_transaction_id,
+			updated_at = NOW()
+		RETURNING *
+	)
+	SELECT
+		u.credit_card_invoice_id,
+		u.created_at,
+		u.updated_at,
+		u.organization_id,
+		u.account_id,
+		u.month,
+		u.year,
+		u.payment_transaction_id,
+		ABS(t.amount) AS payment_amount,
+		t.transaction_date AS payment_date
+	FROM upserted u
+	LEFT JOIN transactions t ON t.transaction_id = u.payment_transaction_id;
+`
+
+func (r *repository) UpsertCreditCardInvoicePayment(ctx context.Context, params upsertCreditCardInvoicePaymentParams) (CreditCardInvoiceModel, error) {
+	var invoice CreditCardInvoiceModel
+	err := r.db.Query(ctx, &invoice, upsertCreditCardInvoicePaymentQuery,
+		params.OrganizationID, params.AccountID, params.Month, params.Year, params.PaymentTransactionID)
+	return invoice, err
+}
+
+// ============================================================================
+// Installment Plans
+// ============================================================================
+
+type fetchInstallmentCandidatesParams struct {
+	OrganizationID int
+	SuffixPattern  string // installmentSuffixPattern: merchant, parcel number and count
+}
+
+// Card debits ending in a parcel suffix ("03/10", "3 / 10") that are not yet a
+// parcel of any plan. The checks mirror parseInstallmentSuffix, so debits it
+// would reject, such as dates, are not fetched again on every run.
+const fetchInstallmentCandidatesQuery = `
+	-- financial.fetchInstallmentCandidatesQuery
+	SELECT
+		t.transaction_id,
+		t.created_at,
+		t.updated_at,
+		t.account_id,
+		t.category_id,
+		t.description,
+		t.original_description,
+		t.amount,
+		t.transaction_date,
+		t.transaction_type,
+		t.ofx_fitid,
+		t.ofx_check_number,
+		t.ofx_memo,
+		t.raw_ofx_data,
+		t.is_classified,
+		t.classification_rule_id,
+		t.pattern_id,
+		t.is_ignored,
+		t.needs_review,
+		t.savings_goal_id,
+		t.notes,
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
+	FROM transactions t
+	INNER JOIN accounts a ON a.account_id = t.account_id
+	CROSS JOIN LATERAL regexp_match(COALESCE(NULLIF(t.original_description, ''), t.description), $2) AS suffix(parts)
+	WHERE a.organization_id = $1
+		AND a.account_type = 'credit_card'
+		AND t.transaction_type = 'debit'
+		AND suffix.parts[3]::int >= 2
+		AND suffix.parts[2]::int BETWEEN 1 AND suffix.parts[3]::int
+		AND suffix.parts[1] ~ '[^\s*-]'
+		AND NOT EXISTS (
+			SELECT 1 FROM installment_parcels p WHERE p.transaction_id = t.transaction_id
+		)
+	ORDER BY t.transaction_date, t.transaction_id;
+`
+
+func (r *repository) FetchInstallmentCandidates(ctx context.Context, params fetchInstallmentCandidatesParams) ([]TransactionModel, error) {
+	var transactions []TransactionModel
+	err := r.db.Query(ctx, &transactions, fetchInstallmentCandidatesQuery, params.OrganizationID, params.SuffixPattern)
+	return transactions, err
+}
+
+type fetchInstallmentPlansParams struct {
+	OrganizationID     int
+	InstallmentPlanIDs []int // nil fetches all
+}
+
+// installmentPlanColumnsSQL selects a plan as ip with its parcels joined as p
+// and grouped by plan.
+const installmentPlanColumnsSQL = `
+		ip.installment_plan_id,
+		ip.created_at,
+		ip.updated_at,
+		ip.organization_id,
+		ip.account_id,
+		ip.category_id,
+		ip.description,
+		ip.installment_amount,
+		ip.installment_count,
+		ip.first_month,
+		ip.first_year,
+		ip.is_active,
+		COALESCE(MAX(p.installment_number) FILTER (WHERE p.transaction_id IS NOT NULL), 0) AS last_imported_number`
+
+const fetchInstallmentPlansQuery = `
+	-- financial.fetchInstallmentPlansQuery
+	SELECT` + installmentPlanColumnsSQL + `
+	FROM installment_plans ip
+	LEFT JOIN installment_parcels p ON p.installment_plan_id = ip.installment_plan_id
+	WHERE ip.organization_id = $1
+		AND ($2::INT[] IS NULL OR ip.installment_plan_id = ANY($2::INT[]))
+	GROUP BY ip.installment_plan_id
+	ORDER BY ip.first_year DESC, ip.first_month DESC, ip.installment_plan_id DESC;
+`
+
+func (r *repository) FetchInstallmentPlans(ctx context.Context, params fetchInstallmentPlansParams) ([]InstallmentPlanModel, error) {
+	var plans []InstallmentPlanModel
+	err := r.db.Query(ctx, &plans, fetchInstallmentPlansQuery,
+		params.OrganizationID, params.InstallmentPlanIDs)
+	return plans, err
+}
+
+type fetchInstallmentParcelsParams struct {
+	OrganizationID     int
+	InstallmentPlanIDs []int // nil fetches all
+}
+
+const fetchInstallmentParcelsQuery = `
+	-- financial.fetchInstallmentParcelsQuery
+	SELECT
+		p.installment_plan_id,
+		p.installment_number,
+		p.month,
+		p.year,
+		p.transaction_id,
+		p.planned_entry_id
+	FROM installment_parcels p
+	INNER JOIN installment_plans ip ON ip.installment_plan_id = p.installment_plan_id
+	WHERE ip.organization_id = $1
+		AND ($2::INT[] IS NULL OR ip.installment_plan_id = ANY($2::INT[]))
+	ORDER BY p.installment_plan_id, p.installment_number;
+`
+
+func (r *repository) FetchInstallmentParcels(ctx context.Context, params fetchInstallmentParcelsParams) ([]InstallmentParcelModel, error) {
+	var parcels []InstallmentParcelModel
+	err := r.db.Query(ctx, &parcels, fetchInstallmentParcelsQuery,
+		params.OrganizationID, params.InstallmentPlanIDs)
+	return parcels, err
+}
+
+type upsertInstallmentPlanParams struct {
+	OrganizationID    int
+	AccountID         int
+	CategoryID        *int // Only fills a plan without a category
+	Description       string
+	InstallmentAmount decimal.Decimal
+	InstallmentCount  int
+	FirstMonth        int
+	FirstYear         int
+}
+
+const upsertInstallmentPlanQuery = `
+	-- financial.upsertInstallmentPlanQuery
+	WITH ip AS (
+		INSERT INTO installment_plans (organization_id, account_id, category_id, description,
+			installment_amount, installment_count, first_month, first_year)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (account_id, description, installment_count, first_month, first_year) DO UPDATE
+		SET category_id = COALESCE(installment_plans.category_id, EXCLUDED.category_id),
+			updated_at = NOW()
+		RETURNING *
+	)
+	SELECT` + installmentPlanColumnsSQL + `
+	FROM ip
+	LEFT JOIN installment_parcels p ON p.installment_plan_id = ip.installment_plan_id
+	GROUP BY ip.installment_plan_id, ip.created_at, ip.updated_at, ip.organization_id, ip.account_id,
+		ip.category_id, ip.description, ip.installment_amount, ip.installment_count,
+		ip.first_month, ip.first_year, ip.is_active;
+`
+
+func (r *repository) UpsertInstallmentPlan(ctx context.Context, params upsertInstallmentPlanParams) (InstallmentPlanModel, error) {
+	var plan InstallmentPlanModel
+	err := r.db.Query(ctx, &plan, upsertInstallmentPlanQuery,
+		params.OrganizationID, params.AccountID, params.CategoryID, params.Description,
+		params.InstallmentAmount, params.InstallmentCount, params.FirstMonth, params.FirstYear)
+	return plan, err
+}
+
+type modifyInstallmentPlanParams struct {
+	InstallmentPlanID int
+	OrganizationID    int
+	CategoryID        *int
+	IsActive          *bool
+}
+
+const modifyInstallmentPlanQuery = `
+	-- financial.modifyInstallmentPlanQuery
+	WITH ip AS (
+		UPDATE installment_plans
+		SET category_id = COALESCE($3, category_id),
+			is_active = COALESCE($4, is_active),
+			updated_at = NOW()
+		WHERE installment_plan_id = $1 AND organization_id = $2
+		RETURNING *
+	)
+	SELECT` + installmentPlanColumnsSQL + `
+	FROM ip
+	LEFT JOIN installment_parcels p ON p.installment_plan_id = ip.installment_plan_id
+	GROUP BY ip.installment_plan_id, ip.created_at, ip.updated_at, ip.organization_id, ip.account_id,
+		ip.category_id, ip.description, ip.installment_amount, ip.installment_count,
+		ip.first_month, ip.first_year, ip.is_active;
+`
+
+func (r *repository) ModifyInstallmentPlan(ctx context.Context, params modifyInstallmentPlanParams) (InstallmentPlanModel, error) {
+	var plan InstallmentPlanModel
+	err := r.db.Query(ctx, &plan, modifyInstallmentPlanQuery,
+		params.InstallmentPlanID, params.OrganizationID, params.CategoryID, params.IsActive)
+	return plan, err
+}
+
+type upsertInstallmentParcelParams struct {
+	InstallmentPlanID int
+	InstallmentNumber int
+	Month             int
+	Year              int
+	TransactionID     *int // Only fills an empty parcel; a parcel already
+	PlannedEntryID    *int // linked keeps its transaction and entry
+}
+
+const upsertInstallmentParcelQuery = `
+	-- financial.upsertInstallmentParcelQuery
+	INSERT INTO installment_parcels (installment_plan_id, installment_number, month, year, transaction_id, planned_entry_id)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (installment_plan_id, installment_number) DO UPDATE
+	SET transaction_id = COALESCE(installment_parcels.transaction_id, EXCLUDED.transaction_id),
+		planned_entry_id = COALESCE(installment_parcels.planned_entry_id, EXCLUDED.planned_entry_id)
+	RETURNING installment_plan_id, installment_number, month, year, transaction_id, planned_entry_id;
+`
+
+func (r *repository) UpsertInstallmentParcel(ctx context.Context, params upsertInstallmentParcelParams) (InstallmentParcelModel, error) {
+	var parcel InstallmentParcelModel
+	err := r.db.Query(ctx, &parcel, upsertInstallmentParcelQuery,
+		params.InstallmentPlanID, params.InstallmentNumber, params.Month, params.Year,
+		params.TransactionID, params.PlannedEntryID)
+	return parcel, err
+}
+
+type removeInstallmentProjectionsParams struct {
+	InstallmentPlanID int
+	OrganizationID    int
+}
+
+// Drops the parcels that were only projected, with their planned entries.
+// Imported parcels, and projections into closed months, stay.
+const removeInstallmentProjectionsQuery = `
+	-- financial.removeInstallmentProjectionsQuery
+	WITH projected AS (
+		DELETE FROM installment_parcels p
+		USING installment_plans ip
+		WHERE ip.installment_plan_id = p.installment_plan_id
+			AND ip.installment_plan_id = $1
+			AND ip.organization_id = $2
+			AND p.transaction_id IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM closed_months cm
+				WHERE cm.organization_id = ip.organization_id
+					AND cm.month = p.month
+					AND cm.year = p.year
+			)
+		RETURNING p.planned_entry_id
+	)
+	DELETE FROM planned_entries
+	WHERE planned_entry_id IN (SELECT planned_entry_id FROM projected);
+`
+
+func (r *repository) RemoveInstallmentProjections(ctx context.Context, params removeInstallmentProjectionsParams) error {
+	return r.db.Run(ctx, removeInstallmentProjectionsQuery,
+		params.InstallmentPlanID, params.OrganizationID)
+}
+
+// ============================================================================
+// Currencies
+// ============================================================================
+
+type fetchBaseCurrencyParams struct {
+	OrganizationID int
+}
+
+const fetchBaseCurrencyQuery = `
+	-- financial.fetchBaseCurrencyQuery
+	SELECT base_currency FROM organizations WHERE organization_id = $1;
+`
+
+func (r *repository) FetchBaseCurrency(ctx context.Context, params fetchBaseCurrencyParams) (string, error) {
+	var currency string
+	err := r.db.Query(ctx, &currency, fetchBaseCurrencyQuery, params.OrganizationID)
+	return currency, err
+}
+
+type modifyBaseCurrencyParams struct {
+	OrganizationID int
+	BaseCurrency   string
+}
+
+const modifyBaseCurrencyQuery = `
+	-- financial.modifyBaseCurrencyQuery
+	UPDATE organizations
+	SET base_currency = $2
+	WHERE organization_id = $1
+	RETURNING base_currency;
+`
+
+func (r *repository) ModifyBaseCurrency(ctx context.Context, params modifyBaseCurrencyParams) (string, error) {
+	var currency string
+	err := r.db.Query(ctx, &currency, modifyBaseCurrencyQuery, params.OrganizationID, params.BaseCurrency)
+	return currency, err
+}
+
+type fetchExchangeRatesParams struct {
+	OrganizationID int
+	Currency       *string // nil fetches every currency
+	From           *time.Time
+	To             *time.Time
+}
+
+// Only rates quoted in the organization's current base currency.
+const fetchExchangeRatesQuery = `
+	-- financial.fetchExchangeRatesQuery
+	SELECT
+		er.organization_id,
+		er.currency,
+		er.base_currency,
+		er.rate_date,
+		er.created_at,
+		er.updated_at,
+		er.rate,
+		er.source
+	FROM exchange_rates er
+	INNER JOIN organizations o ON o.organization_id = er.organization_id
+		AND o.base_currency = er.base_currency
+	WHERE er.organization_id = $1
+		AND ($2::TEXT IS NULL OR er.currency = $2::TEXT)
+		AND ($3::DATE IS NULL OR er.rate_date >= $3::DATE)
+		AND ($4::DATE IS NULL OR er.rate_date <= $4::DATE)
+	ORDER BY er.currency, er.rate_date DESC;
+`
+
+func (r *repository) FetchExchangeRates(ctx context.Context, params fetchExchangeRatesParams) ([]ExchangeRateModel, error) {
+	var rates []ExchangeRateModel
+	err := r.db.Query(ctx, &rates, fetchExchangeRatesQuery,
+		params.OrganizationID, params.Currency, params.From, params.To)
+	return rates, err
+}
+
+type exchangeRateRow struct {
+	Currency string
+	RateDate time.Time
+	Rate     decimal.Decimal
+}
+
+type upsertExchangeRatesParams struct {
+	OrganizationID int
+	Rates          []exchangeRateRow
+	Source         string
+}
+
+// Rates are quoted in the organization's base currency at the time of import;
+// a new rate for the same day replaces the old one.
+const upsertExchangeRateQuery = `
+	-- financial.upsertExchangeRateQuery
+	INSERT INTO exchange_rates (organization_id, currency, base_currency, rate_date, rate, source)
+	SELECT o.organization_id, $2, o.base_currency, $3, $4, $5
+	FROM organizations o
+	WHERE o.organization_id = $1
+	ON CONFLICT (organization_id, currency, base_currency, rate_date) DO UPDATE
+	SET rate = EXCLUDED.rate,
+		source = EXCLUDED.source,
+		updated_at = NOW();
+`
+
+// UpsertExchangeRates saves all the rates or none, and returns how many were
+// saved.
+func (r *repository) UpsertExchangeRates(ctx context.Context, params upsertExchangeRatesParams) (int, error) {
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		for _, rate := range params.Rates {
+			if err := r.db.Run(ctx, upsertExchangeRateQuery,
+				params.OrganizationID, rate.Currency, rate.RateDate, rate.Rate, params.Source); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(params.Rates), nil
+}
+
+// ============================================================================
+// Reports
+// ============================================================================
+
+type fetchReportCategorySpendingParams struct {
+	OrganizationID int
+	From           time.Time // First day of the first month, as in every report query
+	To             time.Time // First day of the last month
+	CategoryID     *int
+}
+
+const fetchReportCategorySpendingQuery = `
+	-- financial.fetchReportCategorySpendingQuery
+	SELECT
+		ra.category_id,
+		EXTRACT(MONTH FROM ra.budget_month)::int AS month,
+		EXTRACT(YEAR FROM ra.budget_month)::int AS year,
+		COALESCE(SUM(ra.amount), 0) AS amount,
+		COUNT(DISTINCT ra.transaction_id) FILTER (WHERE ra.amount IS NULL) AS missing_rates
+	FROM report_allocations ra
+	WHERE ra.organization_id = $1
+		AND ra.transaction_type = 'debit'
+		AND ra.budget_month BETWEEN $2 AND $3
+		AND ($4::int IS NULL OR ra.category_id = $4)
+	GROUP BY ra.category_id, ra.budget_month
+	ORDER BY ra.budget_month, ra.category_id;
+`
+
+func (r *repository) FetchReportCategorySpending(ctx context.Context, params fetchReportCategorySpendingParams) ([]ReportCategoryMonthModel, error) {
+	var result []ReportCategoryMonthModel
+	err := r.db.Query(ctx, &result, fetchReportCategorySpendingQuery,
+		params.OrganizationID, params.From, params.To, params.CategoryID)
+	return result, err
+}
+
+type fetchReportIncomeExpenseParams struct {
+	OrganizationID int
+	From           time.Time
+	To             time.Time
+}
+
+const fetchReportIncomeExpenseQuery = `
+	-- financial.fetchReportIncomeExpenseQuery
+	SELECT
+		EXTRACT(MONTH FROM ra.budget_month)::int AS month,
+		EXTRACT(YEAR FROM ra.budget_month)::int AS year,
+		COALESCE(SUM(ra.amount) FILTER (WHERE ra.transaction_type = 'credit'), 0) AS income,
+		COALESCE(SUM(ra.amount) FILTER (WHERE ra.transaction_type = 'debit'), 0) AS expense,
+		COUNT(DISTINCT ra.transaction_id) FILTER (WHERE ra.amount IS NULL) AS missing_rates
+	FROM report_allocations ra
+	WHERE ra.organization_id = $1
+		AND ra.budget_month BETWEEN $2 AND $3
+	GROUP BY ra.budget_month
+	ORDER BY ra.budget_month;
+`
+
+func (r *repository) FetchReportIncomeExpense(ctx context.Context, params fetchReportIncomeExpenseParams) ([]ReportIncomeExpenseModel, error) {
+	var result []ReportIncomeExpenseModel
+	err := r.db.Query(ctx, &result, fetchReportIncomeExpenseQuery,
+		params.OrganizationID, params.From, params.To)
+	return result, err
+}
+
+type fetchReportMerchantsParams struct {
+	OrganizationID int
+	From           time.Time
+	To             time.Time
+	Limit          int
+}
+
+// Merchants are told apart by their transaction description, ignoring case
+// and surrounding spaces. Amounts without an exchange rate are NULL in
+// report_allocations; they are left out of the sums and counted instead.
+const fetchReportMerchantsQuery = `
+	-- financial.fetchReportMerchantsQuery
+	SELECT
+		UPPER(TRIM(ra.description)) AS merchant,
+		COALESCE(SUM(ra.amount), 0) AS total,
+		COUNT(DISTINCT ra.transaction_id) AS transaction_count,
+		COUNT(DISTINCT ra.transaction_id) FILTER (WHERE ra.amount IS NULL) AS missing_rates,
+		MAX(ra.transaction_date) AS last_transaction_date
+	FROM report_allocations ra
+	WHERE ra.organization_id = $1
+		AND ra.transaction_type = 'debit'
+		AND ra.budget_month BETWEEN $2 AND $3
+	GROUP BY UPPER(TRIM(ra.description))
+	ORDER BY total DESC, merchant
+	LIMIT $4;
+`
+
+func (r *repository) FetchReportMerchants(ctx context.Context, params fetchReportMerchantsParams) ([]ReportMerchantModel, error) {
+	var result []ReportMerchantModel
+	err := r.db.Query(ctx, &result, fetchReportMerchantsQuery,
+		params.OrganizationID, params.From, params.To, params.Limit)
+	return result, err
+}
+
+type fetchReportTransactionsParams struct {
+	OrganizationID  int
+	From            time.Time
+	To              time.Time
+	CategoryID      *int
+	Uncategorized   bool
+	Merchant        *string
+	TransactionType *string
+	Limit           int
+	Offset          int
+}
+
+const fetchReportTransactionsQuery = `
+	-- financial.fetchReportTransactionsQuery
+	SELECT
+		ra.transaction_id,
+		ra.split_id,
+		ra.account_id,
+		ra.transaction_date,
+		EXTRACT(MONTH FROM ra.budget_month)::int AS month,
+		EXTRACT(YEAR FROM ra.budget_month)::int AS year,
+		ra.description,
+		ra.category_id,
+		ra.transaction_type,
+		ra.amount
+	FROM report_allocations ra
+	WHERE ra.organization_id = $1
+		AND ra.budget_month BETWEEN $2 AND $3
+		AND ($4::int IS NULL OR ra.category_id = $4)
+		AND (NOT $5::bool OR ra.category_id IS NULL)
+		AND ($6::text IS NULL OR UPPER(TRIM(ra.description)) = UPPER(TRIM($6)))
+		AND ($7::text IS NULL OR ra.transaction_type = $7)
+	ORDER BY ra.transaction_date DESC, ra.transaction_id DESC, ra.split_id
+	LIMIT $8 OFFSET $9;
+`
+
+func (r *repository) FetchReportTransactions(ctx context.Context, params fetchReportTransactionsParams) ([]ReportTransactionModel, error) {
+	var result []ReportTransactionModel
+	err := r.db.Query(ctx, &result, fetchReportTransactionsQuery,
+		params.OrganizationID, params.From, params.To, params.CategoryID, params.Uncategorized,
+		params.Merchant, params.TransactionType, params.Limit, params.Offset)
+	return result, err
+}
+
+// ============================================================================
+// Audit Log
+// ============================================================================
+
+type insertAuditLogEntryParams struct {
+	OrganizationID int
+	UserID         *int
+	EntityType     string
+	EntityID       int
+	Action         string
+	Changes        []byte // JSON
+}
+
+const insertAuditLogEntryQuery = `
+	-- financial.insertAuditLogEntryQuery
+	INSERT INTO audit_log (organization_id, user_id, entity_type, entity_id, action, changes)
+	VALUES ($1, $2, $3, $4, $5, $6::JSONB);
+`
+
+func (r *repository) InsertAuditLogEntry(ctx context.Context, params insertAuditLogEntryParams) error {
+	return r.db.Run(ctx, insertAuditLogEntryQuery,
+		params.OrganizationID, params.UserID, params.EntityType, params.EntityID, params.Action, string(params.Changes))
+}
+
+type fetchAuditLogParams struct {
+	OrganizationID int
+	EntityType     *string
+	EntityID       *int
+	UserID         *int
+	Action         *string
+	From           *time.Time // Inclusive
+	To             *time.Time // Exclusive
+	BeforeID       *int       // Keyset position: entries older than this one
+	Limit          int
+}
+
+const fetchAuditLogQuery = `
+	-- financial.fetchAuditLogQuery
+	SELECT
+		al.audit_log_id,
+		al.created_at,
+		al.organization_id,
+		al.user_id,
+		u.name AS user_name,
+		al.entity_type,
+		al.entity_id,
+		al.action,
+		al.changes
+	FROM audit_log al
+	LEFT JOIN users u ON u.user_id = al.user_id
+	WHERE al.organization_id = $1
+		AND ($2::TEXT IS NULL OR al.entity_type = $2::TEXT)
+		AND ($3::INT IS NULL OR al.entity_id = $3::INT)
+		AND ($4::INT IS NULL OR al.user_id = $4::INT)
+		AND ($5::TEXT IS NULL OR al.action = $5::TEXT)
+		AND ($6::TIMESTAMP IS NULL OR al.created_at >= $6::TIMESTAMP)
+		AND ($7::TIMESTAMP IS NULL OR al.created_at < $7::TIMESTAMP)
+		AND ($8::INT IS NULL OR al.audit_log_id < $8::INT)
+	ORDER BY al.audit_log_id DESC
+	LIMIT $9;
+`
+
+func (r *repository) FetchAuditLog(ctx context.Context, params fetchAuditLogParams) ([]AuditLogModel, error) {
+	var result []AuditLogModel
+	err := r.db.Query(ctx, &result, fetchAuditLogQuery,
+		params.OrganizationID, params.EntityType, params.EntityID, params.UserID, params.Action,
+		params.From, params.To, params.BeforeID, params.Limit)
+	return result, err
+}
+
 // =============================================================================
 // Planned Entry Tags (junction table)
 // =============================================================================
@@ -3145,3 +4950,454 @@ func (r *repository) SetPlannedEntryTags(ctx context.Context, params setPlannedE
 
 	return nil
 }
+
+// =============================================================================
+// Pluggy Account Links
+// =============================================================================
+
+type fetchPluggyAccountLinksParams struct {
+	OrganizationID int
+	AccountID      *int
+}
+
+const fetchPluggyAccountLinksQuery = `
+	-- financial.fetchPluggyAccountLinksQuery
+	SELECT
+		pluggy_account_link_id, created_at, updated_at, user_id, organization_id, account_id,
+		pluggy_item_id, pluggy_account_id, sync_cursor, last_synced_at, last_sync_status,
+		last_sync_error, last_imported_count
+	FROM pluggy_account_links
+	WHERE organization_id = $1
+		AND ($2::int IS NULL OR account_id = $2)
+	ORDER BY pluggy_account_link_id ASC;
+`
+
+func (r *repository) FetchPluggyAccountLinks(ctx context.Context, params fetchPluggyAccountLinksParams) ([]PluggyAccountLinkModel, error) {
+	var links []PluggyAccountLinkModel
+	err := r.db.Query(ctx, &links, fetchPluggyAccountLinksQuery, params.OrganizationID, params.AccountID)
+	return links, err
+}
+
+type fetchPluggyAccountLinkByIDParams struct {
+	PluggyAccountLinkID int
+	OrganizationID      int
+}
+
+const fetchPluggyAccountLinkByIDQuery = `
+	-- financial.fetchPluggyAccountLinkByIDQuery
+	SELECT
+		pluggy_account_link_id, created_at, updated_at, user_id, organization_id, account_id,
+		pluggy_item_id, pluggy_account_id, sync_cursor, last_synced_at, last_sync_status,
+		last_sync_error, last_imported_count
+	FROM pluggy_account_links
+	WHERE pluggy_account_link_id = $1
+		AND organization_id = $2;
+`
+
+func (r *repository) FetchPluggyAccountLinkByID(ctx context.Context, params fetchPluggyAccountLinkByIDParams) (PluggyAccountLinkModel, error) {
+	var link PluggyAccountLinkModel
+	err := r.db.Query(ctx, &link, fetchPluggyAccountLinkByIDQuery, params.PluggyAccountLinkID, params.OrganizationID)
+	return link, err
+}
+
+type insertPluggyAccountLinkParams struct {
+	UserID          int
+	OrganizationID  int
+	AccountID       int
+	PluggyItemID    string
+	PluggyAccountID string
+}
+
+// Re-linking a Pluggy account to a different Celeiro account resets the cursor
+// so the new target account receives the full history.
+const insertPluggyAccountLinkQuery = `
+	-- financial.insertPluggyAccountLinkQuery
+	INSERT INTO pluggy_account_links (user_id, organization_id, account_id, pluggy_item_id, pluggy_account_id)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (organization_id, pluggy_account_id)
+	DO UPDATE SET
+		user_id = EXCLUDED.user_id,
+		pluggy_item_id = EXCLUDED.pluggy_item_id,
+		sync_cursor = CASE
+			WHEN pluggy_account_links.account_id = EXCLUDED.account_id THEN pluggy_account_links.sync_cursor
+			ELSE NULL
+		END,
+		account_id = EXCLUDED.account_id,
+		updated_at = NOW()
+	RETURNING pluggy_account_link_id, created_at, updated_at, user_id, organization_id, account_id,
+			  pluggy_item_id, pluggy_account_id, sync_cursor, last_synced_at, last_sync_status,
+			  last_sync_error, last_imported_count;
+`
+
+func (r *repository) InsertPluggyAccountLink(ctx context.Context, params insertPluggyAccountLinkParams) (PluggyAccountLinkModel, error) {
+	var link PluggyAccountLinkModel
+	err := r.db.Query(ctx, &link, insertPluggyAccountLinkQuery,
+		params.UserID, params.OrganizationID, params.AccountID, params.PluggyItemID, params.PluggyAccountID)
+	return link, err
+}
+
+type modifyPluggyAccountLinkSyncParams struct {
+	PluggyAccountLinkID int
+	OrganizationID      int
+	SyncCursor          *time.Time // nil keeps the current cursor
+	LastSyncStatus      string
+	LastSyncError       *string
+	LastImportedCount   int
+}
+
+const modifyPluggyAccountLinkSyncQuery = `
+	-- financial.modifyPluggyAccountLinkSyncQuery
+	UPDATE pluggy_account_links
+	SET sync_cursor = COALESCE($3, sync_cursor),
+		last_synced_at = NOW(),
+		last_sync_status = $4,
+		last_sync_error = $5,
+		last_imported_count = $6,
+		updated_at = NOW()
+	WHERE pluggy_account_link_id = $1 AND organization_id = $2
+	RETURNING pluggy_account_link_id, created_at, updated_at, user_id, organization_id, account_id,
+			  pluggy_item_id, pluggy_account_id, sync_cursor, last_synced_at, last_sync_status,
+			  last_sync_error, last_imported_count;
+`
+
+func (r *repository) ModifyPluggyAccountLinkSync(ctx context.Context, params modifyPluggyAccountLinkSyncParams) (PluggyAccountLinkModel, error) {
+	var link PluggyAccountLinkModel
+	err := r.db.Query(ctx, &link, modifyPluggyAccountLinkSyncQuery,
+		params.PluggyAccountLinkID, params.OrganizationID, params.SyncCursor,
+		params.LastSyncStatus, params.LastSyncError, params.LastImportedCount)
+	return link, err
+}
+
+type removePluggyAccountLinkParams struct {
+	PluggyAccountLinkID int
+	OrganizationID      int
+}
+
+const removePluggyAccountLinkQuery = `
+	-- financial.removePluggyAccountLinkQuery
+	DELETE FROM pluggy_account_links
+	WHERE pluggy_account_link_id = $1 AND organization_id = $2
+	RETURNING pluggy_account_link_id;
+`
+
+func (r *repository) RemovePluggyAccountLink(ctx context.Context, params removePluggyAccountLinkParams) error {
+	var deletedID int
+	return r.db.Query(ctx, &deletedID, removePluggyAccountLinkQuery,
+		params.PluggyAccountLinkID, params.OrganizationID)
+}
+
+// =============================================================================
+// CSV Import Profiles
+// =============================================================================
+
+type fetchCSVImportProfilesParams struct {
+	OrganizationID int
+	AccountID      int
+}
+
+const fetchCSVImportProfilesQuery = `
+	-- financial.fetchCSVImportProfilesQuery
+	SELECT
+		csv_import_profile_id, created_at, updated_at, user_id, organization_id, account_id, name,
+		delimiter, skip_rows, date_column, date_format, description_columns, id_column,
+		amount_column, debit_column, credit_column, decimal_separator, sign_convention
+	FROM csv_import_profiles
+	WHERE organization_id = $1
+		AND account_id = $2
+	ORDER BY name ASC;
+`
+
+func (r *repository) FetchCSVImportProfiles(ctx context.Context, params fetchCSVImportProfilesParams) ([]CSVImportProfileModel, error) {
+	var profiles []CSVImportProfileModel
+	err := r.db.Query(ctx, &profiles, fetchCSVImportProfilesQuery, params.OrganizationID, params.AccountID)
+	return profiles, err
+}
+
+type fetchCSVImportProfileByIDParams struct {
+	CSVImportProfileID int
+	OrganizationID     int
+	AccountID          int
+}
+
+const fetchCSVImportProfileByIDQuery = `
+	-- financial.fetchCSVImportProfileByIDQuery
+	SELECT
+		csv_import_profile_id, created_at, updated_at, user_id, organization_id, account_id, name,
+		delimiter, skip_rows, date_column, date_format, description_columns, id_column,
+		amount_column, debit_column, credit_column, decimal_separator, sign_convention
+	FROM csv_import_profiles
+	WHERE csv_import_profile_id = $1
+		AND organization_id = $2
+		AND account_id = $3;
+`
+
+func (r *repository) FetchCSVImportProfileByID(ctx context.Context, params fetchCSVImportProfileByIDParams) (CSVImportProfileModel, error) {
+	var profile CSVImportProfileModel
+	err := r.db.Query(ctx, &profile, fetchCSVImportProfileByIDQuery,
+		params.CSVImportProfileID, params.OrganizationID, params.AccountID)
+	return profile, err
+}
+
+type insertCSVImportProfileParams struct {
+	UserID         int
+	OrganizationID int
+	AccountID      int
+	Profile        CSVImportProfileModel
+}
+
+const insertCSVImportProfileQuery = `
+	-- financial.insertCSVImportProfileQuery
+	INSERT INTO csv_import_profiles (
+		user_id, organization_id, account_id, name, delimiter, skip_rows, date_column, date_format,
+		description_columns, id_column, amount_column, debit_column, credit_column, decimal_separator, sign_convention
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	RETURNING csv_import_profile_id, created_at, updated_at, user_id, organization_id, account_id, name,
+			  delimiter, skip_rows, date_column, date_format, description_columns, id_column,
+			  amount_column, debit_column, credit_column, decimal_separator, sign_convention;
+`
+
+func (r *repository) InsertCSVImportProfile(ctx context.Context, params insertCSVImportProfileParams) (CSVImportProfileModel, error) {
+	var profile CSVImportProfileModel
+	p := params.Profile
+	err := r.db.Query(ctx, &profile, insertCSVImportProfileQuery,
+		params.UserID, params.OrganizationID, params.AccountID, p.Name, p.Delimiter, p.SkipRows, p.DateColumn, p.DateFormat,
+		p.DescriptionColumns, p.IDColumn, p.AmountColumn, p.DebitColumn, p.CreditColumn, p.DecimalSeparator, p.SignConvention)
+	return profile, err
+}
+
+// modifyCSVImportProfileParams replaces every mapping field; the service merges
+// partial updates onto the stored profile first.
+type modifyCSVImportProfileParams struct {
+	CSVImportProfileID int
+	OrganizationID     int
+	AccountID          int
+	Profile            CSVImportProfileModel
+}
+
+const modifyCSVImportProfileQuery = `
+	-- financial.modifyCSVImportProfileQuery
+	UPDATE csv_import_profiles
+	SET name = $4,
+		delimiter = $5,
+		skip_rows = $6,
+		date_column = $7,
+		date_format = $8,
+		description_columns = $9,
+		id_column = $10,
+		amount_column = $11,
+		debit_column = $12,
+		credit_column = $13,
+		decimal_separator = $14,
+		sign_convention = $15,
+		updated_at = NOW()
+	WHERE csv_import_profile_id = $1 AND organization_id = $2 AND account_id = $3
+	RETURNING csv_import_profile_id, created_at, updated_at, user_id, organization_id, account_id, name,
+			  delimiter, skip_rows, date_column, date_format, description_columns, id_column,
+			  amount_column, debit_column, credit_column, decimal_separator, sign_convention;
+`
+
+func (r *repository) ModifyCSVImportProfile(ctx context.Context, params modifyCSVImportProfileParams) (CSVImportProfileModel, error) {
+	var profile CSVImportProfileModel
+	p := params.Profile
+	err := r.db.Query(ctx, &profile, modifyCSVImportProfileQuery,
+		params.CSVImportProfileID, params.OrganizationID, params.AccountID, p.Name, p.Delimiter, p.SkipRows, p.DateColumn, p.DateFormat,
+		p.DescriptionColumns, p.IDColumn, p.AmountColumn, p.DebitColumn, p.CreditColumn, p.DecimalSeparator, p.SignConvention)
+	return profile, err
+}
+
+type removeCSVImportProfileParams struct {
+	CSVImportProfileID int
+	OrganizationID     int
+	AccountID          int
+}
+
+const removeCSVImportProfileQuery = `
+	-- financial.removeCSVImportProfileQuery
+	DELETE FROM csv_import_profiles
+	WHERE csv_import_profile_id = $1 AND organization_id = $2 AND account_id = $3
+	RETURNING csv_import_profile_id;
+`
+
+func (r *repository) RemoveCSVImportProfile(ctx context.Context, params removeCSVImportProfileParams) error {
+	var deletedID int
+	return r.db.Query(ctx, &deletedID, removeCSVImportProfileQuery,
+		params.CSVImportProfileID, params.OrganizationID, params.AccountID)
+}
+
+// =============================================================================
+// Account Reconciliation
+// =============================================================================
+
+// accountComputedBalanceSQL is the account balance at the end of sb.balance_date:
+// the opening balance plus every counted transaction up to that day.
+const accountComputedBalanceSQL = `
+	a.opening_balance + COALESCE((
+		SELECT SUM(CASE WHEN t.transaction_type = 'credit' THEN ABS(t.amount) ELSE -ABS(t.amount) END)
+		FROM transactions t
+		WHERE t.account_id = a.account_id
+			AND t.transaction_date <= sb.balance_date
+			AND t.transaction_date >= COALESCE(a.opening_balance_date, '-infinity'::DATE)
+			AND (t.ofx_fitid IS NULL OR t.ofx_fitid NOT LIKE 'CARRYOVER-%')
+	), 0)`
+
+type fetchAccountStatementBalancesParams struct {
+	AccountID      int
+	OrganizationID int
+}
+
+const fetchAccountStatementBalancesQuery = `
+	-- financial.fetchAccountStatementBalancesQuery
+	SELECT
+		sb.account_statement_balance_id, sb.created_at, sb.updated_at, sb.organization_id, sb.account_id,
+		sb.balance_date, sb.balance, sb.source,
+		` + accountComputedBalanceSQL + ` AS computed_balance
+	FROM account_statement_balances sb
+	INNER JOIN accounts a ON a.account_id = sb.account_id
+	WHERE sb.account_id = $1
+		AND sb.organization_id = $2
+	ORDER BY sb.balance_date ASC;
+`
+
+func (r *repository) FetchAccountStatementBalances(ctx context.Context, params fetchAccountStatementBalancesParams) ([]AccountStatementBalanceModel, error) {
+	var balances []AccountStatementBalanceModel
+	err := r.db.Query(ctx, &balances, fetchAccountStatementBalancesQuery, params.AccountID, params.OrganizationID)
+	return balances, err
+}
+
+type upsertAccountStatementBalanceParams struct {
+	AccountID      int
+	OrganizationID int
+	BalanceDate    time.Time
+	Balance        decimal.Decimal
+	Source         string
+}
+
+// A newer statement for the same day replaces the earlier one.
+const upsertAccountStatementBalanceQuery = `
+	-- financial.upsertAccountStatementBalanceQuery
+	WITH sb AS (
+		INSERT INTO account_statement_balances (organization_id, account_id, balance_date, balance, source)
+		VALUES ($2, $1, $3, $4, $5)
+		ON CONFLICT (account_id, balance_date) DO UPDATE
+		SET balance = EXCLUDED.balance,
+			source = EXCLUDED.source,
+			updated_at = NOW()
+		RETURNING account_statement_balance_id, created_at, updated_at, organization_id, account_id,
+				  balance_date, balance, source
+	)
+	SELECT
+		sb.account_statement_balance_id, sb.created_at, sb.updated_at, sb.organization_id, sb.account_id,
+		sb.balance_date, sb.balance, sb.source,
+		` + accountComputedBalanceSQL + ` AS computed_balance
+	FROM sb
+	INNER JOIN accounts a ON a.account_id = sb.account_id;
+`
+
+func (r *repository) UpsertAccountStatementBalance(ctx context.Context, params upsertAccountStatementBalanceParams) (AccountStatementBalanceModel, error) {
+	var balance AccountStatementBalanceModel
+	err := r.db.Query(ctx, &balance, upsertAccountStatementBalanceQuery,
+		params.AccountID, params.OrganizationID, params.BalanceDate, params.Balance, params.Source)
+	return balance, err
+}
+
+type fetchAccountTransactionsBetweenParams struct {
+	AccountID      int
+	OrganizationID int
+	After          *time.Time // Exclusive; nil starts at the opening balance date
+	Through        time.Time  // Inclusive
+}
+
+// fetchAccountTransactionsBetweenQuery returns the transactions counted in the
+// account balance within the window, oldest first.
+const fetchAccountTransactionsBetweenQuery = `
+	-- financial.fetchAccountTransactionsBetweenQuery
+	SELECT
+		t.transaction_id,
+		t.created_at,
+		t.updated_at,
+		t.account_id,
+		t.category_id,
+		t.description,
+		t.original_description,
+		t.amount,
+		t.transaction_date,
+		t.transaction_type,
+		t.ofx_fitid,
+		t.ofx_check_number,
+		t.ofx_memo,
+		t.raw_ofx_data,
+		t.is_classified,
+		t.classification_rule_id,
+		t.pattern_id,
+		t.is_ignored,
+		t.needs_review,
+		t.savings_goal_id,
+		t.notes,
+		t.tags,
+		a.currency,
+		to_base_amount(t.amount, a.organization_id, a.currency, t.transaction_date) AS converted_amount
+	FROM transactions t
+	INNER JOIN accounts a ON a.account_id = t.account_id
+	WHERE t.account_id = $1
+		AND a.organization_id = $2
+		AND t.transaction_date >= COALESCE(a.opening_balance_date, '-infinity'::DATE)
+		AND ($3::DATE IS NULL OR t.transaction_date > $3::DATE)
+		AND t.transaction_date <= $4::DATE
+		AND (t.ofx_fitid IS NULL OR t.ofx_fitid NOT LIKE 'CARRYOVER-%')
+	ORDER BY t.transaction_date ASC, t.transaction_id ASC;
+`
+
+func (r *repository) FetchAccountTransactionsBetween(ctx context.Context, params fetchAccountTransactionsBetweenParams) ([]TransactionModel, error) {
+	var transactions []TransactionModel
+	err := r.db.Query(ctx, &transactions, fetchAccountTransactionsBetweenQuery,
+		params.AccountID, params.OrganizationID, params.After, params.Through)
+	return transactions, err
+}
+
+type modifyAccountReconciledThroughParams struct {
+	AccountID         int
+	OrganizationID    int
+	ReconciledThrough time.Time
+}
+
+// The marker only moves forward: matching an older statement again does not
+// un-reconcile later ones.
+const modifyAccountReconciledThroughQuery = `
+	-- financial.modifyAccountReconciledThroughQuery
+	UPDATE accounts
+	SET reconciled_through = GREATEST(COALESCE(reconciled_through, $3::DATE), $3::DATE),
+		updated_at = NOW()
+	WHERE account_id = $1 AND organization_id = $2
+	RETURNING account_id;
+`
+
+func (r *repository) ModifyAccountReconciledThrough(ctx context.Context, params modifyAccountReconciledThroughParams) error {
+	var accountID int
+	return r.db.Query(ctx, &accountID, modifyAccountReconciledThroughQuery,
+		params.AccountID, params.OrganizationID, params.ReconciledThrough)
+}
+
+// =============================================================================
+// Background Jobs
+// =============================================================================
+
+// fetchMaintenanceTargetsQuery returns one member per organization to run
+// scheduled jobs as, preferring admins and then the oldest membership.
+const fetchMaintenanceTargetsQuery = `
+	-- financial.fetchMaintenanceTargetsQuery
+	SELECT DISTINCT ON (uo.organization_id)
+		uo.organization_id, uo.user_id, o.auto_close_months
+	FROM user_organizations uo
+	INNER JOIN organizations o ON o.organization_id = uo.organization_id
+	ORDER BY uo.organization_id,
+		(uo.user_role IN ('admin', 'super_admin')) DESC,
+		uo.created_at ASC,
+		uo.user_id ASC;
+`
+
+func (r *repository) FetchMaintenanceTargets(ctx context.Context) ([]MaintenanceTargetModel, error) {
+	var targets []MaintenanceTargetModel
+	err := r.db.Query(ctx, &targets, fetchMaintenanceTargetsQuery)
+	return targets, err
+}
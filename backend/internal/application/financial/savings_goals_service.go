@@ -179,14 +179,20 @@ func (s *service) GetSavingsGoalProgress(ctx context.Context, input GetSavingsGo
 
 	// 3. Calculate current amount (initial amount + sum of transaction amounts)
 	currentAmount := goal.InitialAmount
+	missingRates := 0
 	for _, tx := range transactions {
+		amount, ok := convertedAmount(&tx, tx.Amount)
+		if !ok {
+			missingRates++
+			continue
+		}
 		// A linked debit is money set aside toward the goal (it leaves the
 		// checking account), so it adds to progress. A credit is money pulled
 		// back out of the goal (a withdrawal), so it subtracts.
 		if tx.TransactionType == TransactionTypeCredit {
-			currentAmount = currentAmount.Sub(tx.Amount)
+			currentAmount = currentAmount.Sub(amount)
 		} else {
-			currentAmount = currentAmount.Add(tx.Amount)
+			currentAmount = currentAmount.Add(amount)
 		}
 	}
 
@@ -220,6 +226,7 @@ func (s *service) GetSavingsGoalProgress(ctx context.Context, input GetSavingsGo
 		CurrentAmount:        currentAmount,
 		ProgressPercent:      progressPercent,
 		MonthlyContributions: contributions,
+		MissingRates:         missingRates,
 	}
 
 	// 6. Calculate additional metrics for "reserva" type goals
@@ -312,6 +319,14 @@ func (s *service) CreateSavingsGoal(ctx context.Context, input CreateSavingsGoal
 	}
 
 	goal.TagIDs = s.savingsGoalTagIDs(ctx, goal.SavingsGoalID)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntitySavingsGoal,
+		EntityID:       goal.SavingsGoalID,
+		Action:         AuditActionCreate,
+		After:          goal,
+	})
 	return goal, nil
 }
 
@@ -335,6 +350,11 @@ func (s *service) UpdateSavingsGoal(ctx context.Context, input UpdateSavingsGoal
 		return SavingsGoal{}, fmt.Errorf("target_amount must be greater than zero")
 	}
 
+	before, err := s.savingsGoalBeforeWrite(ctx, input.SavingsGoalID, input.UserID, input.OrganizationID)
+	if err != nil {
+		return SavingsGoal{}, err
+	}
+
 	model, err := s.Repository.ModifySavingsGoal(ctx, modifySavingsGoalParams{
 		SavingsGoalID:       input.SavingsGoalID,
 		UserID:              input.UserID,
@@ -373,11 +393,25 @@ func (s *service) UpdateSavingsGoal(ctx context.Context, input UpdateSavingsGoal
 	}
 
 	goal.TagIDs = s.savingsGoalTagIDs(ctx, goal.SavingsGoalID)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntitySavingsGoal,
+		EntityID:       goal.SavingsGoalID,
+		Action:         AuditActionUpdate,
+		Before:         before,
+		After:          goal,
+	})
 	return goal, nil
 }
 
 func (s *service) DeleteSavingsGoal(ctx context.Context, input DeleteSavingsGoalInput) error {
-	err := s.Repository.RemoveSavingsGoal(ctx, removeSavingsGoalParams{
+	before, err := s.savingsGoalBeforeWrite(ctx, input.SavingsGoalID, input.UserID, input.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	err = s.Repository.RemoveSavingsGoal(ctx, removeSavingsGoalParams{
 		SavingsGoalID:  input.SavingsGoalID,
 		UserID:         input.UserID,
 		OrganizationID: input.OrganizationID,
@@ -385,12 +419,26 @@ func (s *service) DeleteSavingsGoal(ctx context.Context, input DeleteSavingsGoal
 	if err != nil {
 		return fmt.Errorf("failed to delete savings goal: %w", err)
 	}
+
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntitySavingsGoal,
+		EntityID:       input.SavingsGoalID,
+		Action:         AuditActionDelete,
+		Before:         before,
+	})
 	return nil
 }
 
 func (s *service) CompleteSavingsGoal(ctx context.Context, input CompleteSavingsGoalInput) (SavingsGoal, error) {
 	isCompleted := true
 
+	before, err := s.savingsGoalBeforeWrite(ctx, input.SavingsGoalID, input.UserID, input.OrganizationID)
+	if err != nil {
+		return SavingsGoal{}, err
+	}
+
 	// The repository query automatically sets completed_at when is_completed changes to true
 	model, err := s.Repository.ModifySavingsGoal(ctx, modifySavingsGoalParams{
 		SavingsGoalID:  input.SavingsGoalID,
@@ -402,12 +450,28 @@ func (s *service) CompleteSavingsGoal(ctx context.Context, input CompleteSavings
 		return SavingsGoal{}, fmt.Errorf("failed to complete savings goal: %w", err)
 	}
 
-	return SavingsGoal{}.FromModel(&model), nil
+	goal := SavingsGoal{}.FromModel(&model)
+	goal.TagIDs = before.TagIDs
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntitySavingsGoal,
+		EntityID:       goal.SavingsGoalID,
+		Action:         AuditActionComplete,
+		Before:         before,
+		After:          goal,
+	})
+	return goal, nil
 }
 
 func (s *service) ReopenSavingsGoal(ctx context.Context, input ReopenSavingsGoalInput) (SavingsGoal, error) {
 	isCompleted := false
 
+	before, err := s.savingsGoalBeforeWrite(ctx, input.SavingsGoalID, input.UserID, input.OrganizationID)
+	if err != nil {
+		return SavingsGoal{}, err
+	}
+
 	// The repository query automatically clears completed_at when is_completed changes to false
 	model, err := s.Repository.ModifySavingsGoal(ctx, modifySavingsGoalParams{
 		SavingsGoalID:  input.SavingsGoalID,
@@ -422,7 +486,34 @@ func (s *service) ReopenSavingsGoal(ctx context.Context, input ReopenSavingsGoal
 		return SavingsGoal{}, fmt.Errorf("failed to reopen savings goal: %w", err)
 	}
 
-	return SavingsGoal{}.FromModel(&model), nil
+	goal := SavingsGoal{}.FromModel(&model)
+	goal.TagIDs = before.TagIDs
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntitySavingsGoal,
+		EntityID:       goal.SavingsGoalID,
+		Action:         AuditActionReopen,
+		Before:         before,
+		After:          goal,
+	})
+	return goal, nil
+}
+
+// savingsGoalBeforeWrite fetches a goal and its tags as they are before a
+// write, for the audit log.
+func (s *service) savingsGoalBeforeWrite(ctx context.Context, savingsGoalID, userID, organizationID int) (SavingsGoal, error) {
+	model, err := s.Repository.FetchSavingsGoalByID(ctx, fetchSavingsGoalByIDParams{
+		SavingsGoalID:  savingsGoalID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return SavingsGoal{}, fmt.Errorf("failed to fetch savings goal: %w", err)
+	}
+	goal := SavingsGoal{}.FromModel(&model)
+	goal.TagIDs = s.savingsGoalTagIDs(ctx, savingsGoalID)
+	return goal, nil
 }
 
 func isSavingsGoalNameConflict(err error) bool {
@@ -482,7 +573,7 @@ func (s *service) AddContribution(ctx context.Context, input AddContributionInpu
 	}
 
 	// 4. Update the goal's initial_amount
-	_, err = s.Repository.AddContribution(ctx, addContributionParams{
+	updated, err := s.Repository.AddContribution(ctx, addContributionParams{
 		SavingsGoalID:  input.SavingsGoalID,
 		UserID:         input.UserID,
 		OrganizationID: input.OrganizationID,
@@ -491,6 +582,15 @@ func (s *service) AddContribution(ctx context.Context, input AddContributionInpu
 	if err != nil {
 		return SavingsGoalProgress{}, fmt.Errorf("failed to add contribution: %w", err)
 	}
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntitySavingsGoal,
+		EntityID:       input.SavingsGoalID,
+		Action:         AuditActionUpdate,
+		Before:         SavingsGoal{}.FromModel(&goal),
+		After:          SavingsGoal{}.FromModel(&updated),
+	})
 
 	// 5. Return updated progress
 	return s.GetSavingsGoalProgress(ctx, GetSavingsGoalProgressInput{
@@ -537,6 +637,19 @@ func calculateOnTrackStatus(now, createdAt, dueDate time.Time, targetAmount, cur
 	return currentAmount.GreaterThanOrEqual(expectedAmount)
 }
 
+type GenerateSavingsGoalEntriesInput struct {
+	UserID         int
+	OrganizationID int
+	Month          int
+	Year           int
+}
+
+// GenerateSavingsGoalEntries creates the month's savings goal planned entries
+// ahead of time, so they exist even if nobody opens the budget page.
+func (s *service) GenerateSavingsGoalEntries(ctx context.Context, input GenerateSavingsGoalEntriesInput) error {
+	return s.generateSavingsGoalEntries(ctx, input.UserID, input.OrganizationID, input.Month, input.Year)
+}
+
 // generateSavingsGoalEntries creates planned entries for savings goals that don't
 // have one for the given month yet. Called lazily when budget page loads.
 func (s *service) generateSavingsGoalEntries(ctx context.Context, userID, orgID, month, year int) error {
@@ -670,10 +783,20 @@ func (s *service) calculateGoalMonthlyAmount(ctx context.Context, goal SavingsGo
 
 	currentAmount := goal.InitialAmount
 	for _, tx := range transactions {
+		amount, ok := convertedAmount(&tx, tx.Amount)
+		if !ok {
+			// The entry would be sized on a wrong balance; it is generated once
+			// the rate is imported
+			s.logger.Warn(ctx, "skipping savings goal entry without exchange rate",
+				"savings_goal_id", goal.SavingsGoalID,
+				"transaction_id", tx.TransactionID,
+			)
+			return decimal.Zero, false
+		}
 		if tx.TransactionType == TransactionTypeCredit {
-			currentAmount = currentAmount.Add(tx.Amount)
+			currentAmount = currentAmount.Add(amount)
 		} else {
-			currentAmount = currentAmount.Sub(tx.Amount)
+			currentAmount = currentAmount.Sub(amount)
 		}
 	}
 
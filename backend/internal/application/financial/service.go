@@ -9,7 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/catrutech/celeiro/internal/application/jobs"
 	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/internal/integrations/pluggy"
 	database "github.com/catrutech/celeiro/pkg/database/persistent"
 	"github.com/catrutech/celeiro/pkg/errors"
 	"github.com/catrutech/celeiro/pkg/logging"
@@ -44,9 +46,16 @@ type Service interface {
 	UpdateAccount(ctx context.Context, params UpdateAccountInput) (Account, error)
 	DeleteAccount(ctx context.Context, params DeleteAccountInput) error
 
+	// Account Reconciliation
+	GetAccountReconciliation(ctx context.Context, params GetAccountReconciliationInput) (AccountReconciliation, error)
+	RecordStatementBalance(ctx context.Context, params RecordStatementBalanceInput) (ReconciliationCheckpoint, error)
+
 	// Transactions
 	GetTransactions(ctx context.Context, params GetTransactionsInput) ([]Transaction, error)
+	SearchTransactions(ctx context.Context, params SearchTransactionsInput) (TransactionSearchResult, error)
+	ExportTransactions(ctx context.Context, params ExportTransactionsInput) (TransactionExport, error)
 	GetUncategorizedTransactions(ctx context.Context, params GetUncategorizedTransactionsInput) ([]Transaction, error)
+	AutoCategorizeTransactions(ctx context.Context, input AutoCategorizeTransactionsInput) (AutoCategorizeTransactionsOutput, error)
 	GetTransactionByID(ctx context.Context, params GetTransactionByIDInput) (Transaction, error)
 	CreateTransaction(ctx context.Context, params CreateTransactionInput) (Transaction, error)
 	ImportTransactionsFromOFX(ctx context.Context, params ImportOFXInput) (ImportOFXOutput, error)
@@ -61,6 +70,7 @@ type Service interface {
 	GetClassificationRuleByID(ctx context.Context, params GetClassificationRuleByIDInput) (ClassificationRule, error)
 	CreateClassificationRule(ctx context.Context, params CreateClassificationRuleInput) (ClassificationRule, error)
 	UpdateClassificationRule(ctx context.Context, params UpdateClassificationRuleInput) (ClassificationRule, error)
+	ReorderClassificationRules(ctx context.Context, params ReorderClassificationRulesInput) ([]ClassificationRule, error)
 	DeleteClassificationRule(ctx context.Context, params DeleteClassificationRuleInput) error
 
 	// Category Budgets
@@ -72,6 +82,10 @@ type Service interface {
 	ConsolidateCategoryBudget(ctx context.Context, params ConsolidateCategoryBudgetInput) (MonthlySnapshot, error)
 	CopyCategoryBudgetsFromMonth(ctx context.Context, params CopyCategoryBudgetsInput) ([]CategoryBudget, error)
 	CloseMonth(ctx context.Context, params CloseMonthInput) (CloseMonthResult, error)
+	ReopenMonth(ctx context.Context, params ReopenMonthInput) (MonthReopening, error)
+	GetMonthReopenings(ctx context.Context, params GetMonthReopeningsInput) ([]MonthReopening, error)
+	GetAutoCloseMonths(ctx context.Context, params GetAutoCloseMonthsInput) (bool, error)
+	SetAutoCloseMonths(ctx context.Context, params SetAutoCloseMonthsInput) (bool, error)
 
 	// Planned Entries
 	GetPlannedEntries(ctx context.Context, params GetPlannedEntriesInput) ([]PlannedEntry, error)
@@ -83,6 +97,7 @@ type Service interface {
 
 	// Planned Entry Statuses (Entrada Planejada)
 	GetPlannedEntriesForMonth(ctx context.Context, params GetPlannedEntriesForMonthInput) ([]PlannedEntryWithStatus, error)
+	RefreshPlannedEntryStatuses(ctx context.Context, params RefreshPlannedEntryStatusesInput) (int, error)
 	MatchPlannedEntryToTransaction(ctx context.Context, params MatchPlannedEntryInput) (PlannedEntryStatus, error)
 	UnmatchPlannedEntry(ctx context.Context, params UnmatchPlannedEntryInput) error
 	DismissPlannedEntry(ctx context.Context, params DismissPlannedEntryInput) (PlannedEntryStatus, error)
@@ -103,6 +118,11 @@ type Service interface {
 	UpdatePattern(ctx context.Context, input UpdatePatternInput) (Pattern, error)
 	DeletePattern(ctx context.Context, input DeletePatternInput) error
 	ApplyPatternRetroactivelySync(ctx context.Context, input ApplyPatternRetroactivelyInput) (ApplyPatternRetroactivelyOutput, error)
+	PreviewPattern(ctx context.Context, input PreviewPatternInput) (PatternPreview, error)
+	ReorderPatterns(ctx context.Context, input ReorderPatternsInput) ([]Pattern, error)
+	GetPatternConflicts(ctx context.Context, input GetPatternConflictsInput) (PatternConflictReport, error)
+	GetPatternSuggestions(ctx context.Context, input GetPatternSuggestionsInput) ([]PatternSuggestion, error)
+	AcceptPatternSuggestion(ctx context.Context, input AcceptPatternSuggestionInput) (Pattern, error)
 	ApplyPatternsToTransaction(ctx context.Context, input ApplyPatternsToTransactionInput) (bool, error)
 
 	// Savings Goals
@@ -116,6 +136,7 @@ type Service interface {
 	ReopenSavingsGoal(ctx context.Context, input ReopenSavingsGoalInput) (SavingsGoal, error)
 	GetGoalSummary(ctx context.Context, input GetGoalSummaryInput) (SavingsGoalDetail, error)
 	AddContribution(ctx context.Context, input AddContributionInput) (SavingsGoalProgress, error)
+	GenerateSavingsGoalEntries(ctx context.Context, input GenerateSavingsGoalEntriesInput) error
 
 	// Amazon Sync
 	SyncAmazonOrders(ctx context.Context, params SyncAmazonOrdersInput) (*SyncAmazonOrdersResult, error)
@@ -132,9 +153,63 @@ type Service interface {
 	GetTransactionTags(ctx context.Context, input GetTransactionTagsInput) ([]Tag, error)
 	SetTransactionTags(ctx context.Context, input SetTransactionTagsInput) error
 
+	// Transaction Splits
+	GetTransactionSplits(ctx context.Context, input GetTransactionSplitsInput) ([]TransactionSplit, error)
+	SetTransactionSplits(ctx context.Context, input SetTransactionSplitsInput) ([]TransactionSplit, error)
+
+	// Credit Card Invoices
+	GetCreditCardInvoices(ctx context.Context, input GetCreditCardInvoicesInput) ([]CreditCardInvoice, error)
+	GetCreditCardInvoice(ctx context.Context, input GetCreditCardInvoiceInput) (CreditCardInvoice, error)
+	SetCreditCardInvoicePayment(ctx context.Context, input SetCreditCardInvoicePaymentInput) (CreditCardInvoice, error)
+
+	// Installment Plans
+	GetInstallmentPlans(ctx context.Context, input GetInstallmentPlansInput) ([]InstallmentPlan, error)
+	DetectInstallments(ctx context.Context, input DetectInstallmentsInput) (DetectInstallmentsOutput, error)
+	UpdateInstallmentPlan(ctx context.Context, input UpdateInstallmentPlanInput) (InstallmentPlan, error)
+	GetOutstandingInstallments(ctx context.Context, input GetOutstandingInstallmentsInput) (OutstandingInstallments, error)
+
+	// Cash-Flow Forecast
+	GetCashFlowForecast(ctx context.Context, input GetCashFlowForecastInput) (CashFlowForecast, error)
+
+	// Reports
+	GetCategoryTrends(ctx context.Context, input GetCategoryTrendsInput) (CategoryTrendsReport, error)
+	GetYearOverYear(ctx context.Context, input GetYearOverYearInput) (YearOverYearReport, error)
+	GetTopMerchants(ctx context.Context, input GetTopMerchantsInput) (TopMerchantsReport, error)
+	GetIncomeExpense(ctx context.Context, input GetIncomeExpenseInput) (IncomeExpenseReport, error)
+	GetReportTransactions(ctx context.Context, input GetReportTransactionsInput) ([]ReportTransaction, error)
+
+	// Audit Log
+	GetAuditLog(ctx context.Context, input GetAuditLogInput) (AuditLogPage, error)
+
+	// Currencies
+	GetBaseCurrency(ctx context.Context, input GetBaseCurrencyInput) (string, error)
+	SetBaseCurrency(ctx context.Context, input SetBaseCurrencyInput) (string, error)
+	GetExchangeRates(ctx context.Context, input GetExchangeRatesInput) ([]ExchangeRate, error)
+	ImportExchangeRates(ctx context.Context, input ImportExchangeRatesInput) (ImportExchangeRatesOutput, error)
+
+	// Transfers
+	GetTransfers(ctx context.Context, input GetTransfersInput) ([]Transfer, error)
+	DetectTransfers(ctx context.Context, input DetectTransfersInput) (DetectTransfersOutput, error)
+	LinkTransfer(ctx context.Context, input LinkTransferInput) (Transfer, error)
+	UnlinkTransfer(ctx context.Context, input UnlinkTransferInput) error
+
 	// Planned Entry Tags
 	GetPlannedEntryTags(ctx context.Context, input GetPlannedEntryTagsInput) ([]Tag, error)
 	SetPlannedEntryTags(ctx context.Context, input SetPlannedEntryTagsInput) error
+
+	// Pluggy Sync
+	GetPluggyAccountLinks(ctx context.Context, input GetPluggyAccountLinksInput) ([]PluggyAccountLink, error)
+	LinkPluggyAccount(ctx context.Context, input LinkPluggyAccountInput) (PluggyAccountLink, error)
+	UnlinkPluggyAccount(ctx context.Context, input UnlinkPluggyAccountInput) error
+	SyncPluggyAccount(ctx context.Context, input SyncPluggyAccountInput) (SyncPluggyAccountOutput, error)
+
+	// CSV Import
+	GetCSVImportProfiles(ctx context.Context, input GetCSVImportProfilesInput) ([]CSVImportProfile, error)
+	CreateCSVImportProfile(ctx context.Context, input CreateCSVImportProfileInput) (CSVImportProfile, error)
+	UpdateCSVImportProfile(ctx context.Context, input UpdateCSVImportProfileInput) (CSVImportProfile, error)
+	DeleteCSVImportProfile(ctx context.Context, input DeleteCSVImportProfileInput) error
+	PreviewCSVImport(ctx context.Context, input ImportCSVInput) (PreviewCSVImportOutput, error)
+	ImportTransactionsFromCSV(ctx context.Context, input ImportCSVInput) (ImportCSVOutput, error)
 }
 
 type service struct {
@@ -143,6 +218,10 @@ type service struct {
 	logger     logging.Logger
 	db         database.Database
 	metrics    *metrics.Metrics
+	pluggy     pluggySource
+	jobs       jobs.Service
+
+	categoryPredictors *categoryPredictorCache // nil trains on every call
 }
 
 func New(
@@ -151,14 +230,23 @@ func New(
 	logger logging.Logger,
 	db database.Database,
 	metrics *metrics.Metrics,
+	pluggyClient *pluggy.Client,
+	jobsService jobs.Service,
 ) Service {
-	return &service{
+	s := &service{
 		Repository: repo,
 		system:     system,
 		logger:     logger,
 		db:         db,
 		metrics:    metrics,
+		jobs:       jobsService,
+
+		categoryPredictors: newCategoryPredictorCache(),
 	}
+	if pluggyClient != nil {
+		s.pluggy = pluggyClient
+	}
+	return s
 }
 
 // ============================================================================
@@ -311,24 +399,57 @@ func (s *service) GetAccountByID(ctx context.Context, params GetAccountByIDInput
 }
 
 type CreateAccountInput struct {
-	UserID         int
-	OrganizationID int
-	Name           string
-	AccountType    string
-	BankName       string
-	Balance        decimal.Decimal
-	Currency       string
+	UserID             int
+	OrganizationID     int
+	Name               string
+	AccountType        string
+	BankName           string
+	Balance            decimal.Decimal // Opening balance
+	OpeningBalanceDate *string         // YYYY-MM-DD; transactions before it are not counted
+	Currency           string
+
+	// Credit cards only
+	CardClosingDay  *int
+	CardDueDay      *int
+	BudgetByInvoice bool
 }
 
 func (s *service) CreateAccount(ctx context.Context, params CreateAccountInput) (Account, error) {
+	if params.OpeningBalanceDate != nil {
+		if _, err := parseTransactionDate(*params.OpeningBalanceDate); err != nil {
+			return Account{}, internalerrors.NewInvalidTimeFormatError("opening_balance_date")
+		}
+	}
+	if err := validateCardBillingCycle(params.AccountType, params.CardClosingDay, params.CardDueDay, params.BudgetByInvoice); err != nil {
+		return Account{}, err
+	}
+
+	// Accounts default to the organization's base currency
+	currency := params.Currency
+	if currency == "" {
+		baseCurrency, err := s.GetBaseCurrency(ctx, GetBaseCurrencyInput{OrganizationID: params.OrganizationID})
+		if err != nil {
+			return Account{}, err
+		}
+		currency = baseCurrency
+	}
+	currency, err := normalizeCurrency(currency)
+	if err != nil {
+		return Account{}, err
+	}
+
 	model, err := s.Repository.InsertAccount(ctx, insertAccountParams{
-		UserID:         params.UserID,
-		OrganizationID: params.OrganizationID,
-		Name:           params.Name,
-		AccountType:    params.AccountType,
-		BankName:       params.BankName,
-		Balance:        params.Balance,
-		Currency:       params.Currency,
+		UserID:             params.UserID,
+		OrganizationID:     params.OrganizationID,
+		Name:               params.Name,
+		AccountType:        params.AccountType,
+		BankName:           params.BankName,
+		Balance:            params.Balance,
+		OpeningBalanceDate: params.OpeningBalanceDate,
+		Currency:           currency,
+		CardClosingDay:     params.CardClosingDay,
+		CardDueDay:         params.CardDueDay,
+		BudgetByInvoice:    params.BudgetByInvoice,
 	})
 	if err != nil {
 		return Account{}, errors.Wrap(err, "failed to create account")
@@ -337,25 +458,86 @@ func (s *service) CreateAccount(ctx context.Context, params CreateAccountInput)
 	return Account{}.FromModel(&model), nil
 }
 
+// UpdateAccountInput has no Balance: it is derived from the opening balance
+// and the account's transactions. An empty OpeningBalanceDate clears it.
 type UpdateAccountInput struct {
-	AccountID      int
-	UserID         int
-	OrganizationID int
-	Name           *string
-	BankName       *string
-	Balance        *decimal.Decimal
-	IsActive       *bool
+	AccountID          int
+	UserID             int
+	OrganizationID     int
+	Name               *string
+	BankName           *string
+	OpeningBalance     *decimal.Decimal
+	OpeningBalanceDate *string
+	IsActive           *bool
+
+	// Credit card billing cycle; nil leaves the current setting
+	CardClosingDay  *int
+	CardDueDay      *int
+	BudgetByInvoice *bool
 }
 
 func (s *service) UpdateAccount(ctx context.Context, params UpdateAccountInput) (Account, error) {
+	var openingBalanceDate *string
+	if params.OpeningBalanceDate != nil && *params.OpeningBalanceDate != "" {
+		if _, err := parseTransactionDate(*params.OpeningBalanceDate); err != nil {
+			return Account{}, internalerrors.NewInvalidTimeFormatError("opening_balance_date")
+		}
+		openingBalanceDate = params.OpeningBalanceDate
+	}
+
+	if params.CardClosingDay != nil || params.CardDueDay != nil || params.BudgetByInvoice != nil {
+		current, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+			AccountID:      params.AccountID,
+			UserID:         params.UserID,
+			OrganizationID: params.OrganizationID,
+		})
+		if err != nil {
+			return Account{}, errors.Wrap(err, "failed to fetch account")
+		}
+		closingDay, dueDay, budgetByInvoice := current.CardClosingDay, current.CardDueDay, current.BudgetByInvoice
+		if params.CardClosingDay != nil {
+			closingDay = params.CardClosingDay
+		}
+		if params.CardDueDay != nil {
+			dueDay = params.CardDueDay
+		}
+		if params.BudgetByInvoice != nil {
+			budgetByInvoice = *params.BudgetByInvoice
+		}
+		if err := validateCardBillingCycle(current.AccountType, closingDay, dueDay, budgetByInvoice); err != nil {
+			return Account{}, err
+		}
+
+		// The billing cycle decides the budget month of card purchases, so a
+		// change must not move any into or out of a closed month
+		moving, err := s.Repository.CountBillingCycleChangesInClosedMonths(ctx, countBillingCycleChangesInClosedMonthsParams{
+			AccountID:       params.AccountID,
+			OrganizationID:  params.OrganizationID,
+			BudgetByInvoice: budgetByInvoice,
+			CardClosingDay:  closingDay,
+			CardDueDay:      dueDay,
+		})
+		if err != nil {
+			return Account{}, errors.Wrap(err, "failed to check closed months")
+		}
+		if moving > 0 {
+			return Account{}, errors.Wrap(internalerrors.ErrMonthClosed, "the new billing cycle moves %d transactions into or out of a closed month", moving)
+		}
+	}
+
 	model, err := s.Repository.ModifyAccount(ctx, modifyAccountParams{
-		AccountID:      params.AccountID,
-		UserID:         params.UserID,
-		OrganizationID: params.OrganizationID,
-		Name:           params.Name,
-		BankName:       params.BankName,
-		Balance:        params.Balance,
-		IsActive:       params.IsActive,
+		AccountID:             params.AccountID,
+		UserID:                params.UserID,
+		OrganizationID:        params.OrganizationID,
+		Name:                  params.Name,
+		BankName:              params.BankName,
+		OpeningBalance:        params.OpeningBalance,
+		IsActive:              params.IsActive,
+		SetOpeningBalanceDate: params.OpeningBalanceDate != nil,
+		OpeningBalanceDate:    openingBalanceDate,
+		CardClosingDay:        params.CardClosingDay,
+		CardDueDay:            params.CardDueDay,
+		BudgetByInvoice:       params.BudgetByInvoice,
 	})
 	if err != nil {
 		return Account{}, errors.Wrap(err, "failed to update account")
@@ -368,19 +550,93 @@ type DeleteAccountInput struct {
 	AccountID      int
 	UserID         int
 	OrganizationID int
+	// MoveTransactionsTo keeps the account's transactions by moving them to
+	// another active account; without it they are deleted with the account.
+	MoveTransactionsTo *int
 }
 
+// DeleteAccount refuses accounts with transactions in closed months: deleting
+// or moving them would change a month that was already consolidated. Archive
+// those accounts instead. Moving also refuses transactions the target account
+// already has, naming their FITIDs, rather than deleting them with the account.
 func (s *service) DeleteAccount(ctx context.Context, params DeleteAccountInput) error {
-	err := s.Repository.RemoveAccount(ctx, removeAccountParams{
+	if _, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
 		AccountID:      params.AccountID,
 		UserID:         params.UserID,
 		OrganizationID: params.OrganizationID,
+	}); err != nil {
+		return errors.Wrap(err, "failed to fetch account")
+	}
+
+	closedCount, err := s.Repository.CountAccountTransactionsInClosedMonths(ctx, countAccountTransactionsInClosedMonthsParams{
+		AccountID:      params.AccountID,
+		OrganizationID: params.OrganizationID,
 	})
 	if err != nil {
-		return errors.Wrap(err, "failed to delete account")
+		return errors.Wrap(err, "failed to check closed months")
+	}
+	if closedCount > 0 {
+		return errors.Wrap(internalerrors.ErrAccountHasClosedMonths, "%d transactions in closed months", closedCount)
 	}
 
-	return nil
+	if params.MoveTransactionsTo != nil {
+		if *params.MoveTransactionsTo == params.AccountID {
+			return errors.Wrap(internalerrors.ErrInvalidTargetAccount, "cannot move transactions to the account being deleted")
+		}
+		target, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+			AccountID:      *params.MoveTransactionsTo,
+			UserID:         params.UserID,
+			OrganizationID: params.OrganizationID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrap(internalerrors.ErrInvalidTargetAccount, "target account not found")
+			}
+			return errors.Wrap(err, "failed to fetch target account")
+		}
+		if !target.IsActive {
+			return errors.Wrap(internalerrors.ErrInvalidTargetAccount, "target account is archived")
+		}
+	}
+
+	// The move and the delete go together: a failure in either leaves the
+	// account and its transactions as they were
+	return s.db.Tx(ctx, func(ctx context.Context) error {
+		if params.MoveTransactionsTo != nil {
+			move := moveAccountTransactionsParams{
+				FromAccountID:  params.AccountID,
+				ToAccountID:    *params.MoveTransactionsTo,
+				OrganizationID: params.OrganizationID,
+			}
+			conflicts, err := s.Repository.FetchAccountTransactionConflicts(ctx, move)
+			if err != nil {
+				return errors.Wrap(err, "failed to check target account transactions")
+			}
+			if len(conflicts) > 0 {
+				return errors.Wrap(internalerrors.ErrTargetAccountHasTransactions, "FITIDs %s", strings.Join(conflicts, ", "))
+			}
+
+			moved, err := s.Repository.MoveAccountTransactions(ctx, move)
+			if err != nil {
+				return errors.Wrap(err, "failed to move transactions")
+			}
+			s.logger.Info(ctx, "Moved transactions before deleting account",
+				"account_id", params.AccountID,
+				"target_account_id", move.ToAccountID,
+				"moved", moved,
+			)
+		}
+
+		err := s.Repository.RemoveAccount(ctx, removeAccountParams{
+			AccountID:      params.AccountID,
+			UserID:         params.UserID,
+			OrganizationID: params.OrganizationID,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to delete account")
+		}
+		return nil
+	})
 }
 
 // ============================================================================
@@ -443,7 +699,10 @@ func (s *service) GetUncategorizedTransactions(ctx context.Context, params GetUn
 		models = models[start:end]
 	}
 
-	return Transactions{}.FromModel(models), nil
+	transactions := Transactions{}.FromModel(models)
+	s.attachCategorySuggestions(ctx, params.OrganizationID, models, transactions)
+
+	return transactions, nil
 }
 
 type GetTransactionByIDInput struct {
@@ -514,10 +773,10 @@ func (s *service) CreateTransaction(ctx context.Context, params CreateTransactio
 	if err != nil {
 		return Transaction{}, internalerrors.NewInvalidTimeFormatError("transaction_date")
 	}
-	if err := s.ensureMonthOpen(ctx, monthClosureParams{
-		OrganizationID: params.OrganizationID,
-		Month:          int(transactionDate.Month()),
-		Year:           transactionDate.Year(),
+	if err := s.ensureTransactionMonthOpen(ctx, transactionMonthClosureParams{
+		OrganizationID:  params.OrganizationID,
+		AccountID:       params.AccountID,
+		TransactionDate: transactionDate,
 	}); err != nil {
 		return Transaction{}, err
 	}
@@ -543,7 +802,17 @@ func (s *service) CreateTransaction(ctx context.Context, params CreateTransactio
 		return Transaction{}, errors.Wrap(err, "failed to create transaction")
 	}
 
-	return Transaction{}.FromModel(&model), nil
+	transaction := Transaction{}.FromModel(&model)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityTransaction,
+		EntityID:       transaction.TransactionID,
+		Action:         AuditActionCreate,
+		After:          transaction,
+	})
+
+	return transaction, nil
 }
 
 type ImportOFXInput struct {
@@ -556,8 +825,11 @@ type ImportOFXInput struct {
 type ImportOFXOutput struct {
 	ImportedCount  int
 	DuplicateCount int
-	ErrorCount     int
+	ErrorCount     int // <STMTTRN> blocks skipped because they could not be parsed
 	Transactions   []Transaction
+	Statements     []OFXStatement // Account identity, period, balances and currency per statement
+	// One per statement with a LEDGERBAL, compared against the computed balance
+	Reconciliations []ReconciliationCheckpoint
 }
 
 // ImportTransactionsFromOFX parses OFX data and imports transactions
@@ -569,7 +841,7 @@ func (s *service) ImportTransactionsFromOFX(ctx context.Context, params ImportOF
 	}
 
 	// Verify account ownership
-	_, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
+	account, err := s.Repository.FetchAccountByID(ctx, fetchAccountByIDParams{
 		AccountID:      params.AccountID,
 		UserID:         params.UserID,
 		OrganizationID: params.OrganizationID,
@@ -584,7 +856,7 @@ func (s *service) ImportTransactionsFromOFX(ctx context.Context, params ImportOF
 	// Parse OFX data
 	parser := NewOFXParser()
 	parseStart := s.system.Time.Now()
-	ofxTransactions, err := parser.ParseOFX(params.OFXData)
+	ofxDocument, err := parser.Parse(params.OFXData)
 	parseDuration := s.system.Time.Now().Sub(parseStart).Seconds()
 
 	// Record parse metrics
@@ -604,6 +876,23 @@ func (s *service) ImportTransactionsFromOFX(ctx context.Context, params ImportOF
 		return ImportOFXOutput{}, errors.Wrap(err, "failed to parse OFX file")
 	}
 
+	ofxTransactions := ofxDocument.Transactions()
+	for _, parseErr := range ofxDocument.Errors {
+		s.logger.Warn(ctx, "Skipping malformed OFX transaction",
+			"account_id", params.AccountID,
+			"error", parseErr.Error(),
+		)
+	}
+	for _, statement := range ofxDocument.Statements {
+		if statement.Currency != "" && account.Currency != "" && !strings.EqualFold(statement.Currency, account.Currency) {
+			s.logger.Warn(ctx, "OFX statement currency differs from account currency",
+				"account_id", params.AccountID,
+				"statement_currency", statement.Currency,
+				"account_currency", account.Currency,
+			)
+		}
+	}
+
 	// Record transaction count
 	if s.metrics != nil && s.metrics.OFXTransactionCount != nil {
 		s.metrics.OFXTransactionCount.Record(ctx, int64(len(ofxTransactions)))
@@ -611,18 +900,18 @@ func (s *service) ImportTransactionsFromOFX(ctx context.Context, params ImportOF
 
 	// Convert OFX transactions to repository insert params
 	insertParams := make([]insertTransactionParams, 0, len(ofxTransactions))
-	checkedMonths := make(map[[2]int]struct{})
+	checkedDays := make(map[time.Time]struct{})
 	for _, ofxTx := range ofxTransactions {
-		monthKey := [2]int{ofxTx.DatePosted.Year(), int(ofxTx.DatePosted.Month())}
-		if _, checked := checkedMonths[monthKey]; !checked {
-			if err := s.ensureMonthOpen(ctx, monthClosureParams{
-				OrganizationID: params.OrganizationID,
-				Month:          monthKey[1],
-				Year:           monthKey[0],
+		day := truncateToDay(ofxTx.DatePosted)
+		if _, checked := checkedDays[day]; !checked {
+			if err := s.ensureTransactionMonthOpen(ctx, transactionMonthClosureParams{
+				OrganizationID:  params.OrganizationID,
+				AccountID:       params.AccountID,
+				TransactionDate: day,
 			}); err != nil {
 				return ImportOFXOutput{}, err
 			}
-			checkedMonths[monthKey] = struct{}{}
+			checkedDays[day] = struct{}{}
 		}
 		insertParams = append(insertParams, ofxTx.ToInsertParams(params.AccountID))
 	}
@@ -639,24 +928,33 @@ func (s *service) ImportTransactionsFromOFX(ctx context.Context, params ImportOF
 	importedCount := len(inserted)
 	duplicateCount := len(ofxTransactions) - importedCount
 
-	// Auto-match imported transactions using advanced patterns (regex-based)
-	matchedCount := 0
-	for _, tx := range inserted {
-		matched, err := s.ApplyPatternsToTransaction(ctx, ApplyPatternsToTransactionInput{
-			TransactionID:  tx.TransactionID,
-			UserID:         params.UserID,
-			OrganizationID: params.OrganizationID,
-		})
+	// Classify imported transactions: regex patterns first, then classification rules
+	classified := s.classifyImportedTransactions(ctx, inserted, params.UserID, params.OrganizationID)
+
+	// Reconcile against the bank's ledger balance now that the transactions are in
+	var reconciliations []ReconciliationCheckpoint
+	for i := range ofxDocument.Statements {
+		balanceDate, ok := statementBalanceDate(&ofxDocument.Statements[i])
+		if !ok {
+			continue
+		}
+		checkpoint, err := s.reconcileStatementBalance(ctx, params.AccountID, params.OrganizationID,
+			balanceDate, ofxDocument.Statements[i].LedgerBalance.Amount, StatementBalanceSourceOFX)
 		if err != nil {
-			s.logger.Warn(ctx, "Failed to apply advanced patterns to transaction",
-				"transaction_id", tx.TransactionID,
+			s.logger.Warn(ctx, "Failed to reconcile OFX ledger balance",
+				"account_id", params.AccountID,
 				"error", err.Error(),
 			)
 			continue
 		}
-		if matched {
-			matchedCount++
+		if !checkpoint.Matches {
+			s.logger.Info(ctx, "OFX ledger balance differs from computed balance",
+				"account_id", params.AccountID,
+				"balance_date", balanceDate.Format("2006-01-02"),
+				"difference", checkpoint.Difference.String(),
+			)
 		}
+		reconciliations = append(reconciliations, checkpoint)
 	}
 
 	// Record successful import metrics
@@ -675,20 +973,25 @@ func (s *service) ImportTransactionsFromOFX(ctx context.Context, params ImportOF
 		"total_parsed", len(ofxTransactions),
 		"imported", importedCount,
 		"duplicates", duplicateCount,
-		"pattern_matched", matchedCount,
+		"errors", len(ofxDocument.Errors),
+		"pattern_matched", classified.PatternMatchCount,
+		"rule_matched", classified.RuleMatchCount,
 		"duration_seconds", importDuration,
 	)
 
 	return ImportOFXOutput{
-		ImportedCount:  importedCount,
-		DuplicateCount: duplicateCount,
-		ErrorCount:     0,
-		Transactions:   Transactions{}.FromModel(inserted),
+		ImportedCount:   importedCount,
+		DuplicateCount:  duplicateCount,
+		ErrorCount:      len(ofxDocument.Errors),
+		Transactions:    Transactions{}.FromModel(inserted),
+		Statements:      ofxDocument.Statements,
+		Reconciliations: reconciliations,
 	}, nil
 }
 
 type UpdateTransactionInput struct {
 	TransactionID  int
+	UserID         int // Who made the change; 0 for automated updates
 	OrganizationID int
 	CategoryID     *int
 	Description    *string
@@ -706,10 +1009,10 @@ func (s *service) UpdateTransaction(ctx context.Context, params UpdateTransactio
 	if err != nil {
 		return Transaction{}, errors.Wrap(err, "failed to fetch transaction for validation")
 	}
-	if err := s.ensureMonthOpen(ctx, monthClosureParams{
-		OrganizationID: params.OrganizationID,
-		Month:          int(existingTx.TransactionDate.Month()),
-		Year:           existingTx.TransactionDate.Year(),
+	if err := s.ensureTransactionMonthOpen(ctx, transactionMonthClosureParams{
+		OrganizationID:  params.OrganizationID,
+		AccountID:       existingTx.AccountID,
+		TransactionDate: existingTx.TransactionDate,
 	}); err != nil {
 		return Transaction{}, err
 	}
@@ -721,6 +1024,27 @@ func (s *service) UpdateTransaction(ctx context.Context, params UpdateTransactio
 		}
 	}
 
+	// Splits must keep adding up to the amount, so it cannot change under them
+	if params.Amount != nil && !params.Amount.Abs().Equal(existingTx.Amount.Abs()) {
+		splits, err := s.Repository.FetchTransactionSplits(ctx, fetchTransactionSplitsParams{
+			TransactionID:  params.TransactionID,
+			OrganizationID: params.OrganizationID,
+		})
+		if err != nil {
+			return Transaction{}, errors.Wrap(err, "failed to fetch transaction splits")
+		}
+		if len(splits) > 0 {
+			return Transaction{}, errors.Wrap(internalerrors.ErrInvalidTransactionSplits, "remove the splits before changing the amount")
+		}
+	}
+
+	// A category chosen by hand is no longer the pattern's doing
+	var patternID *int
+	if params.CategoryID != nil && (existingTx.CategoryID == nil || *existingTx.CategoryID != *params.CategoryID) {
+		clearPatternID := -1
+		patternID = &clearPatternID
+	}
+
 	model, err := s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
 		TransactionID:  params.TransactionID,
 		OrganizationID: params.OrganizationID,
@@ -730,21 +1054,42 @@ func (s *service) UpdateTransaction(ctx context.Context, params UpdateTransactio
 		Notes:          params.Notes,
 		IsIgnored:      params.IsIgnored,
 		NeedsReview:    params.NeedsReview,
+		PatternID:      patternID,
 	})
 	if err != nil {
 		return Transaction{}, errors.Wrap(err, "failed to update transaction")
 	}
 
-	return Transaction{}.FromModel(&model), nil
+	transaction := Transaction{}.FromModel(&model)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityTransaction,
+		EntityID:       transaction.TransactionID,
+		Action:         AuditActionUpdate,
+		Before:         Transaction{}.FromModel(&existingTx),
+		After:          transaction,
+	})
+
+	return transaction, nil
 }
 
 type DeleteTransactionInput struct {
 	TransactionID  int
+	UserID         int
 	OrganizationID int
 }
 
 func (s *service) DeleteTransaction(ctx context.Context, params DeleteTransactionInput) error {
-	err := s.Repository.RemoveTransaction(ctx, removeTransactionParams{
+	existingTx, err := s.Repository.FetchTransactionByID(ctx, fetchTransactionByIDParams{
+		TransactionID:  params.TransactionID,
+		OrganizationID: params.OrganizationID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch transaction")
+	}
+
+	err = s.Repository.RemoveTransaction(ctx, removeTransactionParams{
 		TransactionID:  params.TransactionID,
 		OrganizationID: params.OrganizationID,
 	})
@@ -752,6 +1097,15 @@ func (s *service) DeleteTransaction(ctx context.Context, params DeleteTransactio
 		return errors.Wrap(err, "failed to delete transaction")
 	}
 
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityTransaction,
+		EntityID:       params.TransactionID,
+		Action:         AuditActionDelete,
+		Before:         Transaction{}.FromModel(&existingTx),
+	})
+
 	return nil
 }
 
@@ -761,14 +1115,14 @@ func (s *service) DeleteTransaction(ctx context.Context, params DeleteTransactio
 // ============================================================================
 
 type GetClassificationRulesInput struct {
-	UserID   int
-	IsActive *bool
+	OrganizationID int
+	IsActive       *bool
 }
 
 func (s *service) GetClassificationRules(ctx context.Context, params GetClassificationRulesInput) ([]ClassificationRule, error) {
 	models, err := s.Repository.FetchClassificationRules(ctx, fetchClassificationRulesParams{
-		UserID:   params.UserID,
-		IsActive: params.IsActive,
+		OrganizationID: params.OrganizationID,
+		IsActive:       params.IsActive,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fetch classification rules")
@@ -778,14 +1132,14 @@ func (s *service) GetClassificationRules(ctx context.Context, params GetClassifi
 }
 
 type GetClassificationRuleByIDInput struct {
-	RuleID int
-	UserID int
+	RuleID         int
+	OrganizationID int
 }
 
 func (s *service) GetClassificationRuleByID(ctx context.Context, params GetClassificationRuleByIDInput) (ClassificationRule, error) {
 	model, err := s.Repository.FetchClassificationRuleByID(ctx, fetchClassificationRuleByIDParams{
-		RuleID: params.RuleID,
-		UserID: params.UserID,
+		RuleID:         params.RuleID,
+		OrganizationID: params.OrganizationID,
 	})
 	if err != nil {
 		return ClassificationRule{}, errors.Wrap(err, "failed to fetch classification rule")
@@ -796,6 +1150,7 @@ func (s *service) GetClassificationRuleByID(ctx context.Context, params GetClass
 
 type CreateClassificationRuleInput struct {
 	UserID               int
+	OrganizationID       int
 	CategoryID           int
 	Name                 string
 	Priority             int
@@ -806,11 +1161,35 @@ type CreateClassificationRuleInput struct {
 }
 
 func (s *service) CreateClassificationRule(ctx context.Context, params CreateClassificationRuleInput) (ClassificationRule, error) {
+	if strings.TrimSpace(params.Name) == "" {
+		return ClassificationRule{}, errors.Wrap(internalerrors.ErrInvalidClassificationRule, "name is required")
+	}
+	if err := validateClassificationRuleConditions(ClassificationRuleModel{
+		MatchDescription:     params.MatchDescription,
+		MatchAmountMin:       params.MatchAmountMin,
+		MatchAmountMax:       params.MatchAmountMax,
+		MatchTransactionType: params.MatchTransactionType,
+	}); err != nil {
+		return ClassificationRule{}, err
+	}
+	if _, err := s.Repository.FetchCategoryByID(ctx, fetchCategoryByIDParams{
+		CategoryID:     params.CategoryID,
+		OrganizationID: params.OrganizationID,
+	}); err != nil {
+		return ClassificationRule{}, errors.Wrap(err, "failed to fetch category")
+	}
+
+	priority := params.Priority
+	if priority == 0 {
+		priority = defaultClassificationRulePriority
+	}
+
 	model, err := s.Repository.InsertClassificationRule(ctx, insertClassificationRuleParams{
 		UserID:               params.UserID,
+		OrganizationID:       params.OrganizationID,
 		CategoryID:           params.CategoryID,
 		Name:                 params.Name,
-		Priority:             params.Priority,
+		Priority:             priority,
 		MatchDescription:     params.MatchDescription,
 		MatchAmountMin:       params.MatchAmountMin,
 		MatchAmountMax:       params.MatchAmountMax,
@@ -825,7 +1204,7 @@ func (s *service) CreateClassificationRule(ctx context.Context, params CreateCla
 
 type UpdateClassificationRuleInput struct {
 	RuleID               int
-	UserID               int
+	OrganizationID       int
 	CategoryID           *int
 	Name                 *string
 	Priority             *int
@@ -837,9 +1216,48 @@ type UpdateClassificationRuleInput struct {
 }
 
 func (s *service) UpdateClassificationRule(ctx context.Context, params UpdateClassificationRuleInput) (ClassificationRule, error) {
+	existing, err := s.Repository.FetchClassificationRuleByID(ctx, fetchClassificationRuleByIDParams{
+		RuleID:         params.RuleID,
+		OrganizationID: params.OrganizationID,
+	})
+	if err != nil {
+		return ClassificationRule{}, errors.Wrap(err, "failed to fetch classification rule")
+	}
+
+	if params.Name != nil && strings.TrimSpace(*params.Name) == "" {
+		return ClassificationRule{}, errors.Wrap(internalerrors.ErrInvalidClassificationRule, "name is required")
+	}
+
+	// Validate the rule as it will look after the update
+	merged := existing
+	if params.MatchDescription != nil {
+		merged.MatchDescription = params.MatchDescription
+	}
+	if params.MatchAmountMin != nil {
+		merged.MatchAmountMin = params.MatchAmountMin
+	}
+	if params.MatchAmountMax != nil {
+		merged.MatchAmountMax = params.MatchAmountMax
+	}
+	if params.MatchTransactionType != nil {
+		merged.MatchTransactionType = params.MatchTransactionType
+	}
+	if err := validateClassificationRuleConditions(merged); err != nil {
+		return ClassificationRule{}, err
+	}
+
+	if params.CategoryID != nil {
+		if _, err := s.Repository.FetchCategoryByID(ctx, fetchCategoryByIDParams{
+			CategoryID:     *params.CategoryID,
+			OrganizationID: params.OrganizationID,
+		}); err != nil {
+			return ClassificationRule{}, errors.Wrap(err, "failed to fetch category")
+		}
+	}
+
 	model, err := s.Repository.ModifyClassificationRule(ctx, modifyClassificationRuleParams{
 		RuleID:               params.RuleID,
-		UserID:               params.UserID,
+		OrganizationID:       params.OrganizationID,
 		CategoryID:           params.CategoryID,
 		Name:                 params.Name,
 		Priority:             params.Priority,
@@ -856,15 +1274,56 @@ func (s *service) UpdateClassificationRule(ctx context.Context, params UpdateCla
 	return ClassificationRule{}.FromModel(&model), nil
 }
 
+type ReorderClassificationRulesInput struct {
+	OrganizationID int
+	RuleIDs        []int // Every rule of the organization, highest priority first
+}
+
+// ReorderClassificationRules rewrites rule priorities to follow the given order.
+func (s *service) ReorderClassificationRules(ctx context.Context, params ReorderClassificationRulesInput) ([]ClassificationRule, error) {
+	existing, err := s.Repository.FetchClassificationRules(ctx, fetchClassificationRulesParams{
+		OrganizationID: params.OrganizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch classification rules")
+	}
+
+	// Require the full set so a stale client cannot leave two rules sharing a
+	// priority
+	known := make(map[int]bool, len(existing))
+	for _, rule := range existing {
+		known[rule.RuleID] = false
+	}
+	for _, ruleID := range params.RuleIDs {
+		seen, ok := known[ruleID]
+		if !ok || seen {
+			return nil, errors.Wrap(internalerrors.ErrInvalidClassificationRule, "unknown or duplicated rule %d", ruleID)
+		}
+		known[ruleID] = true
+	}
+	if len(params.RuleIDs) != len(existing) {
+		return nil, errors.Wrap(internalerrors.ErrInvalidClassificationRule, "rule_ids must list every rule of the organization")
+	}
+
+	if _, err := s.Repository.ModifyClassificationRulePriorities(ctx, modifyClassificationRulePrioritiesParams{
+		OrganizationID: params.OrganizationID,
+		RuleIDs:        params.RuleIDs,
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to reorder classification rules")
+	}
+
+	return s.GetClassificationRules(ctx, GetClassificationRulesInput{OrganizationID: params.OrganizationID})
+}
+
 type DeleteClassificationRuleInput struct {
-	RuleID int
-	UserID int
+	RuleID         int
+	OrganizationID int
 }
 
 func (s *service) DeleteClassificationRule(ctx context.Context, params DeleteClassificationRuleInput) error {
 	err := s.Repository.RemoveClassificationRule(ctx, removeClassificationRuleParams{
-		RuleID: params.RuleID,
-		UserID: params.UserID,
+		RuleID:         params.RuleID,
+		OrganizationID: params.OrganizationID,
 	})
 	if err != nil {
 		return errors.Wrap(err, "failed to delete classification rule")
@@ -951,7 +1410,17 @@ func (s *service) CreateCategoryBudget(ctx context.Context, params CreateCategor
 		return CategoryBudget{}, errors.Wrap(err, "failed to create category budget")
 	}
 
-	return CategoryBudget{}.FromModel(&model), nil
+	budget := CategoryBudget{}.FromModel(&model)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityCategoryBudget,
+		EntityID:       budget.CategoryBudgetID,
+		Action:         AuditActionCreate,
+		After:          budget,
+	})
+
+	return budget, nil
 }
 
 type UpdateCategoryBudgetInput struct {
@@ -992,7 +1461,18 @@ func (s *service) UpdateCategoryBudget(ctx context.Context, params UpdateCategor
 		return CategoryBudget{}, errors.Wrap(err, "failed to update category budget")
 	}
 
-	return CategoryBudget{}.FromModel(&model), nil
+	budget := CategoryBudget{}.FromModel(&model)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityCategoryBudget,
+		EntityID:       budget.CategoryBudgetID,
+		Action:         AuditActionUpdate,
+		Before:         CategoryBudget{}.FromModel(&existing),
+		After:          budget,
+	})
+
+	return budget, nil
 }
 
 type DeleteCategoryBudgetInput struct {
@@ -1027,6 +1507,15 @@ func (s *service) DeleteCategoryBudget(ctx context.Context, params DeleteCategor
 		return errors.Wrap(err, "failed to delete category budget")
 	}
 
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityCategoryBudget,
+		EntityID:       params.CategoryBudgetID,
+		Action:         AuditActionDelete,
+		Before:         CategoryBudget{}.FromModel(&existing),
+	})
+
 	return nil
 }
 
@@ -1041,6 +1530,20 @@ func (s *service) ensureMonthOpen(ctx context.Context, params monthClosureParams
 	return nil
 }
 
+// ensureTransactionMonthOpen checks the budget month a transaction on the
+// account counts in, which for cards budgeted by invoice is not the month of
+// the transaction date.
+func (s *service) ensureTransactionMonthOpen(ctx context.Context, params transactionMonthClosureParams) error {
+	closed, err := s.Repository.IsTransactionMonthClosed(ctx, params)
+	if err != nil {
+		return errors.Wrap(err, "failed to check month closure")
+	}
+	if closed {
+		return internalerrors.ErrMonthClosed
+	}
+	return nil
+}
+
 type ConsolidateCategoryBudgetInput struct {
 	CategoryBudgetID int
 	UserID           int
@@ -1074,7 +1577,11 @@ func (s *service) ConsolidateCategoryBudget(ctx context.Context, params Consolid
 		return MonthlySnapshot{}, errors.Wrap(err, "failed to fetch spending")
 	}
 
-	actualAmount := spending[budget.CategoryID]
+	// A snapshot is final, so it waits for the missing exchange rates
+	if missing := spending[budget.CategoryID].MissingRates; missing > 0 {
+		return MonthlySnapshot{}, errors.Wrap(internalerrors.ErrMissingExchangeRates, "%d transactions in category %d", missing, budget.CategoryID)
+	}
+	actualAmount := spending[budget.CategoryID].TotalSpent
 	if actualAmount.IsZero() {
 		actualAmount = decimal.Zero
 	}
@@ -1082,6 +1589,8 @@ func (s *service) ConsolidateCategoryBudget(ctx context.Context, params Consolid
 	// Calculate total planned: sum of planned entries + controlled amount
 	plannedEntrySums, err := s.Repository.FetchPlannedEntrySumsByCategory(ctx, fetchPlannedEntrySumsByCategoryParams{
 		OrganizationID: params.OrganizationID,
+		Month:          budget.Month,
+		Year:           budget.Year,
 	})
 	if err != nil {
 		return MonthlySnapshot{}, errors.Wrap(err, "failed to fetch planned entry sums")
@@ -1192,7 +1701,16 @@ func (s *service) CopyCategoryBudgetsFromMonth(ctx context.Context, params CopyC
 			return nil, fmt.Errorf("failed to create budget for category %d: %w", src.CategoryID, err)
 		}
 
-		createdBudgets = append(createdBudgets, CategoryBudget{}.FromModel(&model))
+		budget := CategoryBudget{}.FromModel(&model)
+		s.recordAudit(ctx, auditRecord{
+			OrganizationID: params.OrganizationID,
+			UserID:         params.UserID,
+			EntityType:     AuditEntityCategoryBudget,
+			EntityID:       budget.CategoryBudgetID,
+			Action:         AuditActionCreate,
+			After:          budget,
+		})
+		createdBudgets = append(createdBudgets, budget)
 	}
 
 	// Return empty array if nothing was copied (all categories already exist)
@@ -1237,6 +1755,17 @@ func (s *service) CloseMonth(ctx context.Context, params CloseMonthInput) (Close
 	if err := s.Repository.MarkMonthClosed(ctx, closure); err != nil {
 		return CloseMonthResult{}, errors.Wrap(err, "failed to mark month closed")
 	}
+
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityMonth,
+		EntityID:       auditMonthID(params.Month, params.Year),
+		Action:         AuditActionClose,
+		Before:         auditMonthState{Closed: false},
+		After:          auditMonthState{Closed: true, Surplus: &result.Surplus},
+	})
+
 	return result, nil
 }
 
@@ -1252,47 +1781,68 @@ func (s *service) closeMonth(ctx context.Context, params CloseMonthInput) (Close
 		return CloseMonthResult{}, errors.Wrap(err, "failed to fetch category budgets")
 	}
 
-	// 2. Consolidate all unconsolidated budgets (skip already consolidated ones)
-	snapshots := make([]MonthlySnapshot, 0)
-	for _, budget := range budgets {
-		if budget.IsConsolidated {
-			continue
-		}
-		snapshot, err := s.ConsolidateCategoryBudget(ctx, ConsolidateCategoryBudgetInput{
-			CategoryBudgetID: budget.CategoryBudgetID,
-			UserID:           params.UserID,
-			OrganizationID:   params.OrganizationID,
-		})
-		if err != nil {
-			return CloseMonthResult{}, errors.Wrap(err, "failed to consolidate budget")
-		}
-		snapshots = append(snapshots, snapshot)
-	}
-
-	// 3. Calculate real surplus/deficit: total_income - total_spending
+	// 2. Calculate real surplus/deficit: total_income - total_spending. A
+	// closed month cannot be corrected, so it is refused, before anything is
+	// consolidated, while a foreign amount has no exchange rate
 	transactions, err := s.Repository.FetchTransactionsByMonth(ctx, fetchTransactionsByMonthParams{
 		OrganizationID: params.OrganizationID,
 		Month:          params.Month,
 		Year:           params.Year,
+		BudgetMonth:    true,
 	})
 	if err != nil {
 		return CloseMonthResult{}, errors.Wrap(err, "failed to fetch transactions for month")
 	}
 
+	// Money moved between our own accounts is neither income nor spending
+	transfers, err := s.transferTransactionSet(ctx, params.OrganizationID)
+	if err != nil {
+		return CloseMonthResult{}, err
+	}
+
 	totalIncome := decimal.Zero
 	totalSpending := decimal.Zero
+	missingRates := 0
 	for _, tx := range transactions {
 		if tx.IsIgnored {
 			continue
 		}
+		if _, ok := transfers[tx.TransactionID]; ok {
+			continue
+		}
+		amount, ok := convertedAmount(&tx, tx.Amount)
+		if !ok {
+			missingRates++
+			continue
+		}
 		if tx.TransactionType == TransactionTypeCredit {
-			totalIncome = totalIncome.Add(tx.Amount)
+			totalIncome = totalIncome.Add(amount)
 		} else if tx.TransactionType == TransactionTypeDebit {
-			totalSpending = totalSpending.Add(tx.Amount)
+			totalSpending = totalSpending.Add(amount)
 		}
 	}
+	if missingRates > 0 {
+		return CloseMonthResult{}, errors.Wrap(internalerrors.ErrMissingExchangeRates, "%d transactions in %02d/%d", missingRates, params.Month, params.Year)
+	}
 	surplus := totalIncome.Sub(totalSpending)
 
+	// 3. Consolidate all unconsolidated budgets (skip already consolidated ones)
+	snapshots := make([]MonthlySnapshot, 0)
+	for _, budget := range budgets {
+		if budget.IsConsolidated {
+			continue
+		}
+		snapshot, err := s.ConsolidateCategoryBudget(ctx, ConsolidateCategoryBudgetInput{
+			CategoryBudgetID: budget.CategoryBudgetID,
+			UserID:           params.UserID,
+			OrganizationID:   params.OrganizationID,
+		})
+		if err != nil {
+			return CloseMonthResult{}, errors.Wrap(err, "failed to consolidate budget")
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
 	// No carry-over needed if balance is zero
 	if surplus.IsZero() {
 		return CloseMonthResult{Snapshots: snapshots, Surplus: surplus}, nil
@@ -1336,7 +1886,7 @@ func (s *service) closeMonth(ctx context.Context, params CloseMonthInput) (Close
 		txType = TransactionTypeDebit
 		txAmount = surplus.Neg()
 	}
-	carryoverFitID := fmt.Sprintf("CARRYOVER-%d-%02d", params.Year, params.Month)
+	carryoverFitID := monthCarryoverFitID(params.Month, params.Year)
 	description := fmt.Sprintf("Saldo anterior - %s/%d", ptMonthNames[params.Month], params.Year)
 
 	// 7. Insert carry-over transaction (ON CONFLICT on ofx_fitid ensures idempotency)
@@ -1361,6 +1911,135 @@ func (s *service) closeMonth(ctx context.Context, params CloseMonthInput) (Close
 	}, nil
 }
 
+// monthCarryoverFitID identifies the carry-over transaction a month closing
+// creates on the first day of the following month.
+func monthCarryoverFitID(month, year int) string {
+	return fmt.Sprintf("CARRYOVER-%d-%02d", year, month)
+}
+
+// ReopenMonthInput contains parameters for reopening a closed month
+type ReopenMonthInput struct {
+	UserID         int
+	OrganizationID int
+	Month          int
+	Year           int
+	Reason         string
+}
+
+// ReopenMonth reopens a closed month so late transactions can be added. The
+// month's carry-over transaction and snapshots are removed and its budgets are
+// unconsolidated, so closing it again produces fresh ones. Months must be
+// reopened latest first: the carry-over sits in the following month.
+func (s *service) ReopenMonth(ctx context.Context, params ReopenMonthInput) (MonthReopening, error) {
+	reason := strings.TrimSpace(params.Reason)
+	if reason == "" {
+		return MonthReopening{}, errors.Wrap(internalerrors.ErrMissingRequiredFields, "reason is required")
+	}
+
+	closed, err := s.Repository.IsMonthClosed(ctx, monthClosureParams{
+		OrganizationID: params.OrganizationID,
+		Month:          params.Month,
+		Year:           params.Year,
+	})
+	if err != nil {
+		return MonthReopening{}, errors.Wrap(err, "failed to check month closure")
+	}
+	if !closed {
+		return MonthReopening{}, internalerrors.ErrMonthNotClosed
+	}
+
+	nextMonth, nextYear := params.Month+1, params.Year
+	if nextMonth > 12 {
+		nextMonth, nextYear = 1, nextYear+1
+	}
+	if err := s.ensureMonthOpen(ctx, monthClosureParams{
+		OrganizationID: params.OrganizationID,
+		Month:          nextMonth,
+		Year:           nextYear,
+	}); err != nil {
+		return MonthReopening{}, errors.Wrap(err, "reopen %02d/%d first", nextMonth, nextYear)
+	}
+
+	model, err := s.Repository.ReopenMonth(ctx, reopenMonthParams{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		Month:          params.Month,
+		Year:           params.Year,
+		Reason:         reason,
+		CarryoverFitID: monthCarryoverFitID(params.Month, params.Year),
+	})
+	if err != nil {
+		return MonthReopening{}, errors.Wrap(err, "failed to reopen month")
+	}
+
+	s.logger.Info(ctx, "Month reopened",
+		"organization_id", params.OrganizationID,
+		"user_id", params.UserID,
+		"month", params.Month,
+		"year", params.Year,
+		"snapshots_removed", model.SnapshotCount,
+	)
+
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityMonth,
+		EntityID:       auditMonthID(params.Month, params.Year),
+		Action:         AuditActionReopen,
+		Before:         auditMonthState{Closed: true},
+		After:          auditMonthState{Closed: false, Reason: reason},
+	})
+
+	return MonthReopening{}.FromModel(&model), nil
+}
+
+type GetMonthReopeningsInput struct {
+	OrganizationID int
+}
+
+func (s *service) GetMonthReopenings(ctx context.Context, params GetMonthReopeningsInput) ([]MonthReopening, error) {
+	models, err := s.Repository.FetchMonthReopenings(ctx, fetchMonthReopeningsParams{
+		OrganizationID: params.OrganizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch month reopenings")
+	}
+
+	return MonthReopenings{}.FromModel(models), nil
+}
+
+type GetAutoCloseMonthsInput struct {
+	OrganizationID int
+}
+
+func (s *service) GetAutoCloseMonths(ctx context.Context, params GetAutoCloseMonthsInput) (bool, error) {
+	enabled, err := s.Repository.FetchAutoCloseMonths(ctx, fetchAutoCloseMonthsParams{
+		OrganizationID: params.OrganizationID,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to fetch auto close setting")
+	}
+	return enabled, nil
+}
+
+// SetAutoCloseMonthsInput turns on or off the closing of the previous month by
+// the maintenance job. It is off until an organization opts in.
+type SetAutoCloseMonthsInput struct {
+	OrganizationID int
+	Enabled        bool
+}
+
+func (s *service) SetAutoCloseMonths(ctx context.Context, params SetAutoCloseMonthsInput) (bool, error) {
+	enabled, err := s.Repository.ModifyAutoCloseMonths(ctx, modifyAutoCloseMonthsParams{
+		OrganizationID: params.OrganizationID,
+		Enabled:        params.Enabled,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to update auto close setting")
+	}
+	return enabled, nil
+}
+
 // ============================================================================
 // Planned Entries
 // ============================================================================
@@ -1452,6 +2131,15 @@ type CreatePlannedEntryInput struct {
 	TargetYear       *int
 	SavingsGoalID    *int
 	TagIDs           []int // Tags to assign - will transfer to matched transactions
+
+	// Recurrence rule of recurrent entries; left empty, the entry repeats
+	// every month from the month it is created
+	RecurrenceFrequency string // weekly, monthly or yearly
+	RecurrenceInterval  int    // Every N weeks/months/years
+	RecurrenceMonths    []int  // Only these months
+	StartsOn            string // YYYY-MM-DD
+	EndsOn              string // YYYY-MM-DD
+	OccurrenceCount     *int
 }
 
 func (s *service) CreatePlannedEntry(ctx context.Context, params CreatePlannedEntryInput) (PlannedEntry, error) {
@@ -1460,6 +2148,26 @@ func (s *service) CreatePlannedEntry(ctx context.Context, params CreatePlannedEn
 		params.EntryType = PlannedEntryTypeExpense
 	}
 
+	var frequency *string
+	if params.RecurrenceFrequency != "" {
+		frequency = &params.RecurrenceFrequency
+	}
+	var interval *int
+	if params.RecurrenceInterval != 0 {
+		interval = &params.RecurrenceInterval
+	}
+	startsOn, err := parseRecurrenceDate(params.StartsOn, "starts_on")
+	if err != nil {
+		return PlannedEntry{}, err
+	}
+	endsOn, err := parseRecurrenceDate(params.EndsOn, "ends_on")
+	if err != nil {
+		return PlannedEntry{}, err
+	}
+	if err := validateRecurrence(frequency, interval, params.RecurrenceMonths, startsOn, endsOn, params.OccurrenceCount); err != nil {
+		return PlannedEntry{}, err
+	}
+
 	model, err := s.Repository.InsertPlannedEntry(ctx, insertPlannedEntryParams{
 		UserID:           params.UserID,
 		OrganizationID:   params.OrganizationID,
@@ -1478,6 +2186,13 @@ func (s *service) CreatePlannedEntry(ctx context.Context, params CreatePlannedEn
 		TargetMonth:      params.TargetMonth,
 		TargetYear:       params.TargetYear,
 		SavingsGoalID:    params.SavingsGoalID,
+
+		RecurrenceFrequency: frequency,
+		RecurrenceInterval:  interval,
+		RecurrenceMonths:    params.RecurrenceMonths,
+		StartsOn:            startsOn,
+		EndsOn:              endsOn,
+		OccurrenceCount:     params.OccurrenceCount,
 	})
 	if err != nil {
 		return PlannedEntry{}, errors.Wrap(err, "failed to create planned entry")
@@ -1499,6 +2214,14 @@ func (s *service) CreatePlannedEntry(ctx context.Context, params CreatePlannedEn
 
 	entry := PlannedEntry{}.FromModel(&model)
 	entry.TagIDs = s.plannedEntryTagIDs(ctx, entry.PlannedEntryID)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityPlannedEntry,
+		EntityID:       entry.PlannedEntryID,
+		Action:         AuditActionCreate,
+		After:          entry,
+	})
 	return entry, nil
 }
 
@@ -1519,26 +2242,80 @@ type UpdatePlannedEntryInput struct {
 	SavingsGoalID    *int // Use -1 to clear
 	CategoryID       *int
 	TagIDs           *[]int // Tags to assign - nil means no change, empty array clears tags
+
+	// Recurrence rule - nil means no change
+	RecurrenceFrequency *string
+	RecurrenceInterval  *int
+	RecurrenceMonths    *[]int  // Empty array clears the months
+	StartsOn            *string // YYYY-MM-DD
+	EndsOn              *string // YYYY-MM-DD, empty string clears
+	OccurrenceCount     *int    // 0 clears
 }
 
 func (s *service) UpdatePlannedEntry(ctx context.Context, params UpdatePlannedEntryInput) (PlannedEntry, error) {
-	model, err := s.Repository.ModifyPlannedEntry(ctx, modifyPlannedEntryParams{
-		PlannedEntryID:   params.PlannedEntryID,
-		UserID:           params.UserID,
-		OrganizationID:   params.OrganizationID,
-		PatternID:        params.PatternID,
-		Description:      params.Description,
-		Amount:           params.Amount,
-		AmountMin:        params.AmountMin,
-		AmountMax:        params.AmountMax,
-		ExpectedDayStart: params.ExpectedDayStart,
-		ExpectedDayEnd:   params.ExpectedDayEnd,
-		ExpectedDay:      params.ExpectedDay,
-		EntryType:        params.EntryType,
-		IsActive:         params.IsActive,
-		SavingsGoalID:    params.SavingsGoalID,
-		CategoryID:       params.CategoryID,
+	modifyParams := modifyPlannedEntryParams{
+		PlannedEntryID:      params.PlannedEntryID,
+		UserID:              params.UserID,
+		OrganizationID:      params.OrganizationID,
+		PatternID:           params.PatternID,
+		Description:         params.Description,
+		Amount:              params.Amount,
+		AmountMin:           params.AmountMin,
+		AmountMax:           params.AmountMax,
+		ExpectedDayStart:    params.ExpectedDayStart,
+		ExpectedDayEnd:      params.ExpectedDayEnd,
+		ExpectedDay:         params.ExpectedDay,
+		EntryType:           params.EntryType,
+		IsActive:            params.IsActive,
+		SavingsGoalID:       params.SavingsGoalID,
+		CategoryID:          params.CategoryID,
+		RecurrenceFrequency: params.RecurrenceFrequency,
+		RecurrenceInterval:  params.RecurrenceInterval,
+		RecurrenceMonths:    params.RecurrenceMonths,
+	}
+	if params.StartsOn != nil {
+		startsOn, err := parseRecurrenceDate(*params.StartsOn, "starts_on")
+		if err != nil {
+			return PlannedEntry{}, err
+		}
+		modifyParams.StartsOn = startsOn
+	}
+	if params.EndsOn != nil {
+		endsOn, err := parseRecurrenceDate(*params.EndsOn, "ends_on")
+		if err != nil {
+			return PlannedEntry{}, err
+		}
+		modifyParams.EndsOn = endsOn
+		modifyParams.ClearEndsOn = endsOn == nil
+	}
+	if params.OccurrenceCount != nil {
+		if *params.OccurrenceCount == 0 {
+			modifyParams.ClearOccurrenceCount = true
+		} else {
+			modifyParams.OccurrenceCount = params.OccurrenceCount
+		}
+	}
+	var months []int
+	if params.RecurrenceMonths != nil {
+		months = *params.RecurrenceMonths
+	}
+	if err := validateRecurrence(params.RecurrenceFrequency, params.RecurrenceInterval, months,
+		modifyParams.StartsOn, modifyParams.EndsOn, modifyParams.OccurrenceCount); err != nil {
+		return PlannedEntry{}, err
+	}
+
+	existing, err := s.Repository.FetchPlannedEntryByID(ctx, fetchPlannedEntryByIDParams{
+		PlannedEntryID: params.PlannedEntryID,
+		UserID:         params.UserID,
+		OrganizationID: params.OrganizationID,
 	})
+	if err != nil {
+		return PlannedEntry{}, errors.Wrap(err, "failed to fetch planned entry")
+	}
+	before := PlannedEntry{}.FromModel(&existing)
+	before.TagIDs = s.plannedEntryTagIDs(ctx, before.PlannedEntryID)
+
+	model, err := s.Repository.ModifyPlannedEntry(ctx, modifyParams)
 	if err != nil {
 		return PlannedEntry{}, errors.Wrap(err, "failed to update planned entry")
 	}
@@ -1559,6 +2336,15 @@ func (s *service) UpdatePlannedEntry(ctx context.Context, params UpdatePlannedEn
 
 	entry := PlannedEntry{}.FromModel(&model)
 	entry.TagIDs = s.plannedEntryTagIDs(ctx, entry.PlannedEntryID)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityPlannedEntry,
+		EntityID:       entry.PlannedEntryID,
+		Action:         AuditActionUpdate,
+		Before:         before,
+		After:          entry,
+	})
 	return entry, nil
 }
 
@@ -1569,7 +2355,16 @@ type DeletePlannedEntryInput struct {
 }
 
 func (s *service) DeletePlannedEntry(ctx context.Context, params DeletePlannedEntryInput) error {
-	err := s.Repository.RemovePlannedEntry(ctx, removePlannedEntryParams{
+	existing, err := s.Repository.FetchPlannedEntryByID(ctx, fetchPlannedEntryByIDParams{
+		PlannedEntryID: params.PlannedEntryID,
+		UserID:         params.UserID,
+		OrganizationID: params.OrganizationID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch planned entry")
+	}
+
+	err = s.Repository.RemovePlannedEntry(ctx, removePlannedEntryParams{
 		PlannedEntryID: params.PlannedEntryID,
 		UserID:         params.UserID,
 		OrganizationID: params.OrganizationID,
@@ -1578,6 +2373,15 @@ func (s *service) DeletePlannedEntry(ctx context.Context, params DeletePlannedEn
 		return errors.Wrap(err, "failed to delete planned entry")
 	}
 
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: params.OrganizationID,
+		UserID:         params.UserID,
+		EntityType:     AuditEntityPlannedEntry,
+		EntityID:       params.PlannedEntryID,
+		Action:         AuditActionDelete,
+		Before:         PlannedEntry{}.FromModel(&existing),
+	})
+
 	return nil
 }
 
@@ -1604,6 +2408,9 @@ func (s *service) GenerateMonthlyInstances(ctx context.Context, params GenerateM
 	if !parent.IsRecurrent {
 		return nil, errors.New("entry is not recurrent")
 	}
+	if plannedEntryOccurrences(parent, params.Month, params.Year) == 0 {
+		return nil, errors.Wrap(internalerrors.ErrInvalidRecurrence, "entry does not occur in %02d/%d", params.Month, params.Year)
+	}
 
 	// Check if instance already exists for this month
 	existingInstances, err := s.Repository.FetchPlannedEntriesByParent(ctx, fetchPlannedEntriesByParentParams{
@@ -1615,15 +2422,20 @@ func (s *service) GenerateMonthlyInstances(ctx context.Context, params GenerateM
 		return nil, errors.Wrap(err, "failed to check existing instances")
 	}
 
-	// Check if an instance already exists for this specific month/year
+	// Check if an instance already exists for this specific month/year. Older
+	// instances carry no target month and are attributed to the month they
+	// were created in.
 	for _, instance := range existingInstances {
-		if instance.IsActive {
-			// Check if instance was created in the same month/year
-			instanceMonth := int(instance.CreatedAt.Month())
-			instanceYear := instance.CreatedAt.Year()
-			if instanceMonth == params.Month && instanceYear == params.Year {
-				return nil, errors.New("instance already exists for this month")
-			}
+		if !instance.IsActive {
+			continue
+		}
+		instanceMonth := int(instance.CreatedAt.Month())
+		instanceYear := instance.CreatedAt.Year()
+		if instance.TargetMonth != nil && instance.TargetYear != nil {
+			instanceMonth, instanceYear = *instance.TargetMonth, *instance.TargetYear
+		}
+		if instanceMonth == params.Month && instanceYear == params.Year {
+			return nil, errors.New("instance already exists for this month")
 		}
 	}
 
@@ -1638,6 +2450,8 @@ func (s *service) GenerateMonthlyInstances(ctx context.Context, params GenerateM
 		ParentEntryID:  &params.ParentEntryID,
 		ExpectedDay:    parent.ExpectedDay,
 		EntryType:      parent.EntryType,
+		TargetMonth:    &params.Month,
+		TargetYear:     &params.Year,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create monthly instance")
@@ -1739,9 +2553,18 @@ func (s *service) GetPlannedEntriesForMonth(ctx context.Context, params GetPlann
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fetch non-recurrent planned entries")
 	}
+	// An instance generated from a recurrent entry stands in for it this month
+	replaced := make(map[int]bool)
 	for _, entry := range allNonRecurrent {
-		if entry.TargetMonth != nil && entry.TargetYear != nil &&
-			*entry.TargetMonth == params.Month && *entry.TargetYear == params.Year {
+		if plannedEntryOccurrences(entry, params.Month, params.Year) > 0 && entry.ParentEntryID != nil {
+			replaced[*entry.ParentEntryID] = true
+		}
+	}
+	entries = slices.DeleteFunc(entries, func(entry PlannedEntryModel) bool {
+		return replaced[entry.PlannedEntryID]
+	})
+	for _, entry := range allNonRecurrent {
+		if plannedEntryOccurrences(entry, params.Month, params.Year) > 0 {
 			entries = append(entries, entry)
 		}
 	}
@@ -1789,6 +2612,7 @@ func (s *service) GetPlannedEntriesForMonth(ctx context.Context, params GetPlann
 		entryDTO.TagIDs = s.plannedEntryTagIDs(ctx, entry.PlannedEntryID)
 		statusDTO := PlannedEntryWithStatus{
 			PlannedEntry: entryDTO,
+			Occurrences:  plannedEntryOccurrences(entry, params.Month, params.Year),
 		}
 
 		// Check if we have a status for this month
@@ -1853,14 +2677,65 @@ func (s *service) computePlannedEntryStatus(entry PlannedEntryModel, currentDay,
 	return PlannedEntryStatusScheduled
 }
 
-func plannedEntryAppliesToMonth(entry PlannedEntryModel, month, year int) bool {
-	if entry.CreatedAt.IsZero() {
-		return true
+type RefreshPlannedEntryStatusesInput struct {
+	UserID         int
+	OrganizationID int
+	Month          int
+	Year           int
+}
+
+// RefreshPlannedEntryStatuses persists the pending status of planned entries
+// that went overdue without being matched or dismissed. Statuses of unresolved
+// entries are otherwise only computed on read; persisting them once the month
+// is over keeps a record of what was missed. Returns how many were persisted.
+func (s *service) RefreshPlannedEntryStatuses(ctx context.Context, params RefreshPlannedEntryStatusesInput) (int, error) {
+	entries, err := s.GetPlannedEntriesForMonth(ctx, GetPlannedEntriesForMonthInput{
+		UserID:         params.UserID,
+		OrganizationID: params.OrganizationID,
+		Month:          params.Month,
+		Year:           params.Year,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	statuses, err := s.Repository.FetchPlannedEntryStatusesByMonth(ctx, fetchPlannedEntryStatusesByMonthParams{
+		UserID:         params.UserID,
+		OrganizationID: params.OrganizationID,
+		Month:          params.Month,
+		Year:           params.Year,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to fetch planned entry statuses")
+	}
+	stored := make(map[int]bool, len(statuses))
+	for _, status := range statuses {
+		stored[status.PlannedEntryID] = true
 	}
 
-	createdYear := entry.CreatedAt.Year()
-	createdMonth := int(entry.CreatedAt.Month())
-	return year > createdYear || (year == createdYear && month >= createdMonth)
+	persisted := 0
+	for _, entry := range entries {
+		if stored[entry.PlannedEntryID] || entry.Status != PlannedEntryStatusPending {
+			continue
+		}
+		if _, err := s.Repository.UpsertPlannedEntryStatus(ctx, upsertPlannedEntryStatusParams{
+			PlannedEntryID: entry.PlannedEntryID,
+			Month:          params.Month,
+			Year:           params.Year,
+			Status:         PlannedEntryStatusPending,
+		}); err != nil {
+			return persisted, errors.Wrap(err, "failed to persist planned entry status")
+		}
+		persisted++
+	}
+
+	return persisted, nil
+}
+
+// plannedEntryAppliesToMonth reports whether the recurrence rule of entry falls
+// in the month.
+func plannedEntryAppliesToMonth(entry PlannedEntryModel, month, year int) bool {
+	return recurrenceOccurrences(entry, month, year) > 0
 }
 
 type MatchPlannedEntryInput struct {
@@ -1939,7 +2814,7 @@ func (s *service) MatchPlannedEntryToTransaction(ctx context.Context, params Mat
 	if entry.SavingsGoalID != nil && tx.SavingsGoalID == nil {
 		modifyParams.SavingsGoalID = entry.SavingsGoalID
 	}
-	_, err = s.Repository.ModifyTransaction(ctx, modifyParams)
+	updated, err := s.Repository.ModifyTransaction(ctx, modifyParams)
 	if err != nil {
 		s.logger.Warn(ctx, "failed to update transaction from planned entry",
 			"transaction_id", params.TransactionID,
@@ -1947,6 +2822,16 @@ func (s *service) MatchPlannedEntryToTransaction(ctx context.Context, params Mat
 			"description", entry.Description,
 			"error", err.Error(),
 		)
+	} else {
+		s.recordAudit(ctx, auditRecord{
+			OrganizationID: params.OrganizationID,
+			UserID:         params.UserID,
+			EntityType:     AuditEntityTransaction,
+			EntityID:       tx.TransactionID,
+			Action:         AuditActionUpdate,
+			Before:         Transaction{}.FromModel(&tx),
+			After:          Transaction{}.FromModel(&updated),
+		})
 	}
 
 	// 6. Transfer tags from planned entry to transaction (merge with existing)
@@ -2046,14 +2931,24 @@ func (s *service) updatePlannedEntryAmountFromTransaction(
 	}
 
 	if updateParams.Amount != nil || updateParams.AmountMin != nil || updateParams.AmountMax != nil {
-		_, err := s.Repository.ModifyPlannedEntry(ctx, updateParams)
+		updated, err := s.Repository.ModifyPlannedEntry(ctx, updateParams)
 		if err != nil {
 			s.logger.Warn(ctx, "failed to update planned entry amount from matched transaction",
 				"planned_entry_id", entry.PlannedEntryID,
 				"transaction_amount", tx.Amount,
 				"error", err.Error(),
 			)
+			return
 		}
+		s.recordAudit(ctx, auditRecord{
+			OrganizationID: organizationID,
+			UserID:         userID,
+			EntityType:     AuditEntityPlannedEntry,
+			EntityID:       entry.PlannedEntryID,
+			Action:         AuditActionUpdate,
+			Before:         PlannedEntry{}.FromModel(&entry),
+			After:          PlannedEntry{}.FromModel(&updated),
+		})
 	}
 }
 
@@ -2086,7 +2981,7 @@ func (s *service) UnmatchPlannedEntry(ctx context.Context, params UnmatchPlanned
 			OrganizationID: params.OrganizationID,
 		})
 		if err == nil && tx.OriginalDescription != nil {
-			_, err = s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
+			updated, err := s.Repository.ModifyTransaction(ctx, modifyTransactionParams{
 				TransactionID:  tx.TransactionID,
 				OrganizationID: params.OrganizationID,
 				Description:    tx.OriginalDescription,
@@ -2096,6 +2991,16 @@ func (s *service) UnmatchPlannedEntry(ctx context.Context, params UnmatchPlanned
 					"transaction_id", tx.TransactionID,
 					"error", err.Error(),
 				)
+			} else {
+				s.recordAudit(ctx, auditRecord{
+					OrganizationID: params.OrganizationID,
+					UserID:         params.UserID,
+					EntityType:     AuditEntityTransaction,
+					EntityID:       tx.TransactionID,
+					Action:         AuditActionUpdate,
+					Before:         Transaction{}.FromModel(&tx),
+					After:          Transaction{}.FromModel(&updated),
+				})
 			}
 		}
 	}
@@ -2387,6 +3292,7 @@ func (s *service) SyncAmazonOrders(ctx context.Context, params SyncAmazonOrdersI
 			// Update transaction description
 			_, err := s.UpdateTransaction(ctx, UpdateTransactionInput{
 				TransactionID:  bestMatch.TransactionID,
+				UserID:         params.UserID,
 				OrganizationID: params.OrganizationID,
 				Description:    &newDesc,
 			})
@@ -2568,6 +3474,7 @@ func (s *service) GetTagSpending(ctx context.Context, input GetTagSpendingInput)
 		spent          decimal.Decimal
 		planned        decimal.Decimal
 		txCount        int
+		missingRates   int
 		plannedEntries []TagPlannedEntry
 	}
 
@@ -2589,6 +3496,7 @@ func (s *service) GetTagSpending(ctx context.Context, input GetTagSpendingInput)
 		agg := get(m.TagID, m.Name, m.Icon, m.Color)
 		agg.spent = m.Total
 		agg.txCount = m.TransactionCount
+		agg.missingRates = m.MissingRates
 	}
 	for _, p := range plannedModels {
 		agg := get(p.TagID, p.Name, p.Icon, p.Color)
@@ -2632,6 +3540,7 @@ func (s *service) GetTagSpending(ctx context.Context, input GetTagSpendingInput)
 			Total:            agg.spent.StringFixed(2),
 			Planned:          agg.planned.StringFixed(2),
 			TransactionCount: agg.txCount,
+			MissingRates:     agg.missingRates,
 			PlannedEntries:   agg.plannedEntries,
 		}
 	}
@@ -2755,15 +3664,66 @@ func (s *service) GetTransactionTags(ctx context.Context, input GetTransactionTa
 }
 
 type SetTransactionTagsInput struct {
-	TransactionID int
-	TagIDs        []int
+	TransactionID  int
+	UserID         int
+	OrganizationID int
+	TagIDs         []int
+}
+
+// auditTransactionTags is how tag changes appear in a transaction's history
+type auditTransactionTags struct {
+	Tags []string `json:"tags"`
 }
 
 func (s *service) SetTransactionTags(ctx context.Context, input SetTransactionTagsInput) error {
-	return s.Repository.SetTransactionTags(ctx, setTransactionTagsParams{
+	if _, err := s.Repository.FetchTransactionByID(ctx, fetchTransactionByIDParams{
+		TransactionID:  input.TransactionID,
+		OrganizationID: input.OrganizationID,
+	}); err != nil {
+		return errors.Wrap(err, "failed to fetch transaction")
+	}
+
+	before, err := s.Repository.FetchTagsByTransactionID(ctx, fetchTagsByTransactionIDParams{
+		TransactionID: input.TransactionID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch transaction tags")
+	}
+
+	err = s.Repository.SetTransactionTags(ctx, setTransactionTagsParams{
 		TransactionID: input.TransactionID,
 		TagIDs:        input.TagIDs,
 	})
+	if err != nil {
+		return err
+	}
+
+	after, err := s.Repository.FetchTagsByTransactionID(ctx, fetchTagsByTransactionIDParams{
+		TransactionID: input.TransactionID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch transaction tags")
+	}
+
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntityTransaction,
+		EntityID:       input.TransactionID,
+		Action:         AuditActionUpdate,
+		Before:         auditTransactionTags{Tags: tagNames(before)},
+		After:          auditTransactionTags{Tags: tagNames(after)},
+	})
+
+	return nil
+}
+
+func tagNames(tags []TagModel) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
 }
 
 // ============================================================================
@@ -7,11 +7,14 @@ import (
 	"time"
 
 	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	database "github.com/catrutech/celeiro/pkg/database/persistent"
+	"github.com/catrutech/celeiro/pkg/logging"
 	"github.com/catrutech/celeiro/pkg/system"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockRepository is a mock implementation of Repository for testing
@@ -71,12 +74,32 @@ func (m *MockRepository) RemoveAccount(ctx context.Context, params removeAccount
 	return args.Error(0)
 }
 
+func (m *MockRepository) CountAccountTransactionsInClosedMonths(ctx context.Context, params countAccountTransactionsInClosedMonthsParams) (int, error) {
+	args := m.Called(ctx, params)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) FetchAccountTransactionConflicts(ctx context.Context, params moveAccountTransactionsParams) ([]string, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRepository) MoveAccountTransactions(ctx context.Context, params moveAccountTransactionsParams) (int, error) {
+	args := m.Called(ctx, params)
+	return args.Int(0), args.Error(1)
+}
+
 // Transactions
 func (m *MockRepository) FetchTransactions(ctx context.Context, params fetchTransactionsParams) ([]TransactionModel, error) {
 	args := m.Called(ctx, params)
 	return args.Get(0).([]TransactionModel), args.Error(1)
 }
 
+func (m *MockRepository) SearchTransactions(ctx context.Context, params searchTransactionsParams) ([]TransactionModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]TransactionModel), args.Error(1)
+}
+
 func (m *MockRepository) FetchTransactionByID(ctx context.Context, params fetchTransactionByIDParams) (TransactionModel, error) {
 	args := m.Called(ctx, params)
 	return args.Get(0).(TransactionModel), args.Error(1)
@@ -123,12 +146,12 @@ func (m *MockRepository) FetchUncategorizedTransactions(ctx context.Context, par
 }
 
 // Spending Aggregation
-func (m *MockRepository) FetchSpendingByCategory(ctx context.Context, params fetchSpendingByCategoryParams) (map[int]decimal.Decimal, error) {
+func (m *MockRepository) FetchSpendingByCategory(ctx context.Context, params fetchSpendingByCategoryParams) (map[int]CategorySpendingResult, error) {
 	args := m.Called(ctx, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(map[int]decimal.Decimal), args.Error(1)
+	return args.Get(0).(map[int]CategorySpendingResult), args.Error(1)
 }
 
 // Classification Rules
@@ -152,6 +175,11 @@ func (m *MockRepository) ModifyClassificationRule(ctx context.Context, params mo
 	return args.Get(0).(ClassificationRuleModel), args.Error(1)
 }
 
+func (m *MockRepository) ModifyClassificationRulePriorities(ctx context.Context, params modifyClassificationRulePrioritiesParams) ([]ClassificationRuleModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]ClassificationRuleModel), args.Error(1)
+}
+
 func (m *MockRepository) RemoveClassificationRule(ctx context.Context, params removeClassificationRuleParams) error {
 	args := m.Called(ctx, params)
 	return args.Error(0)
@@ -188,17 +216,42 @@ func (m *MockRepository) IsMonthClosed(ctx context.Context, params monthClosureP
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockRepository) IsTransactionMonthClosed(ctx context.Context, params transactionMonthClosureParams) (bool, error) {
+	args := m.Called(ctx, params)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) CountBillingCycleChangesInClosedMonths(ctx context.Context, params countBillingCycleChangesInClosedMonthsParams) (int, error) {
+	args := m.Called(ctx, params)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockRepository) MarkMonthClosed(ctx context.Context, params monthClosureParams) error {
 	args := m.Called(ctx, params)
 	return args.Error(0)
 }
 
+func (m *MockRepository) ReopenMonth(ctx context.Context, params reopenMonthParams) (MonthReopeningModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(MonthReopeningModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchMonthReopenings(ctx context.Context, params fetchMonthReopeningsParams) ([]MonthReopeningModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]MonthReopeningModel), args.Error(1)
+}
+
 // Planned Entries
 func (m *MockRepository) FetchPlannedEntries(ctx context.Context, params fetchPlannedEntriesParams) ([]PlannedEntryModel, error) {
 	args := m.Called(ctx, params)
 	return args.Get(0).([]PlannedEntryModel), args.Error(1)
 }
 
+func (m *MockRepository) FetchPlannedEntrySettlementAccounts(ctx context.Context, params fetchPlannedEntrySettlementAccountsParams) (map[int]int, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(map[int]int), args.Error(1)
+}
+
 func (m *MockRepository) FetchPlannedEntrySumsByCategory(ctx context.Context, params fetchPlannedEntrySumsByCategoryParams) (map[int]decimal.Decimal, error) {
 	args := m.Called(ctx, params)
 	return args.Get(0).(map[int]decimal.Decimal), args.Error(1)
@@ -307,6 +360,11 @@ func (m *MockRepository) ModifyAdvancedPattern(ctx context.Context, params modif
 	return args.Get(0).(AdvancedPatternModel), args.Error(1)
 }
 
+func (m *MockRepository) ModifyAdvancedPatternPriorities(ctx context.Context, params modifyAdvancedPatternPrioritiesParams) ([]AdvancedPatternModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]AdvancedPatternModel), args.Error(1)
+}
+
 func (m *MockRepository) RemoveAdvancedPattern(ctx context.Context, params removeAdvancedPatternParams) error {
 	args := m.Called(ctx, params)
 	return args.Error(0)
@@ -410,11 +468,157 @@ func (m *MockRepository) FetchTagsByTransactionID(ctx context.Context, params fe
 	return args.Get(0).([]TagModel), args.Error(1)
 }
 
+func (m *MockRepository) InsertAuditLogEntry(ctx context.Context, params insertAuditLogEntryParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FetchAuditLog(ctx context.Context, params fetchAuditLogParams) ([]AuditLogModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]AuditLogModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchTransactionTagNames(ctx context.Context, params fetchTransactionTagNamesParams) (map[int][]string, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(map[int][]string), args.Error(1)
+}
+
 func (m *MockRepository) SetTransactionTags(ctx context.Context, params setTransactionTagsParams) error {
 	args := m.Called(ctx, params)
 	return args.Error(0)
 }
 
+// Transaction Splits
+func (m *MockRepository) FetchTransactionSplits(ctx context.Context, params fetchTransactionSplitsParams) ([]TransactionSplitModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]TransactionSplitModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchTransactionSplitsByMonth(ctx context.Context, params fetchTransactionSplitsByMonthParams) ([]TransactionSplitModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]TransactionSplitModel), args.Error(1)
+}
+
+func (m *MockRepository) ReplaceTransactionSplits(ctx context.Context, params replaceTransactionSplitsParams) ([]TransactionSplitModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]TransactionSplitModel), args.Error(1)
+}
+
+// Transfers
+func (m *MockRepository) FetchTransfers(ctx context.Context, params fetchTransfersParams) ([]TransferModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]TransferModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchTransferTransactionIDs(ctx context.Context, params fetchTransferTransactionIDsParams) ([]int, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockRepository) FetchTransferCandidates(ctx context.Context, params fetchTransferCandidatesParams) ([]TransactionModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]TransactionModel), args.Error(1)
+}
+
+func (m *MockRepository) InsertTransfer(ctx context.Context, params insertTransferParams) (TransferModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(TransferModel), args.Error(1)
+}
+
+func (m *MockRepository) RemoveTransfer(ctx context.Context, params removeTransferParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+// Credit Card Invoices
+func (m *MockRepository) FetchCreditCardInvoices(ctx context.Context, params fetchCreditCardInvoicesParams) ([]CreditCardInvoiceModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]CreditCardInvoiceModel), args.Error(1)
+}
+
+func (m *MockRepository) UpsertCreditCardInvoicePayment(ctx context.Context, params upsertCreditCardInvoicePaymentParams) (CreditCardInvoiceModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(CreditCardInvoiceModel), args.Error(1)
+}
+
+// Installment Plans
+func (m *MockRepository) FetchInstallmentCandidates(ctx context.Context, params fetchInstallmentCandidatesParams) ([]TransactionModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]TransactionModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchInstallmentPlans(ctx context.Context, params fetchInstallmentPlansParams) ([]InstallmentPlanModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]InstallmentPlanModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchInstallmentParcels(ctx context.Context, params fetchInstallmentParcelsParams) ([]InstallmentParcelModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]InstallmentParcelModel), args.Error(1)
+}
+
+func (m *MockRepository) UpsertInstallmentPlan(ctx context.Context, params upsertInstallmentPlanParams) (InstallmentPlanModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(InstallmentPlanModel), args.Error(1)
+}
+
+func (m *MockRepository) ModifyInstallmentPlan(ctx context.Context, params modifyInstallmentPlanParams) (InstallmentPlanModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(InstallmentPlanModel), args.Error(1)
+}
+
+func (m *MockRepository) UpsertInstallmentParcel(ctx context.Context, params upsertInstallmentParcelParams) (InstallmentParcelModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(InstallmentParcelModel), args.Error(1)
+}
+
+func (m *MockRepository) RemoveInstallmentProjections(ctx context.Context, params removeInstallmentProjectionsParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+// Currencies
+func (m *MockRepository) FetchBaseCurrency(ctx context.Context, params fetchBaseCurrencyParams) (string, error) {
+	args := m.Called(ctx, params)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) ModifyBaseCurrency(ctx context.Context, params modifyBaseCurrencyParams) (string, error) {
+	args := m.Called(ctx, params)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) FetchExchangeRates(ctx context.Context, params fetchExchangeRatesParams) ([]ExchangeRateModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]ExchangeRateModel), args.Error(1)
+}
+
+func (m *MockRepository) UpsertExchangeRates(ctx context.Context, params upsertExchangeRatesParams) (int, error) {
+	args := m.Called(ctx, params)
+	return args.Int(0), args.Error(1)
+}
+
+// Reports
+func (m *MockRepository) FetchReportCategorySpending(ctx context.Context, params fetchReportCategorySpendingParams) ([]ReportCategoryMonthModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]ReportCategoryMonthModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchReportIncomeExpense(ctx context.Context, params fetchReportIncomeExpenseParams) ([]ReportIncomeExpenseModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]ReportIncomeExpenseModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchReportMerchants(ctx context.Context, params fetchReportMerchantsParams) ([]ReportMerchantModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]ReportMerchantModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchReportTransactions(ctx context.Context, params fetchReportTransactionsParams) ([]ReportTransactionModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]ReportTransactionModel), args.Error(1)
+}
+
 // Planned Entry Tags (junction table)
 func (m *MockRepository) FetchTagsByPlannedEntryID(ctx context.Context, params fetchTagsByPlannedEntryIDParams) ([]TagModel, error) {
 	args := m.Called(ctx, params)
@@ -436,6 +640,95 @@ func (m *MockRepository) SetSavingsGoalTags(ctx context.Context, params setSavin
 	return args.Error(0)
 }
 
+// Pluggy Account Links
+func (m *MockRepository) FetchPluggyAccountLinks(ctx context.Context, params fetchPluggyAccountLinksParams) ([]PluggyAccountLinkModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]PluggyAccountLinkModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchPluggyAccountLinkByID(ctx context.Context, params fetchPluggyAccountLinkByIDParams) (PluggyAccountLinkModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(PluggyAccountLinkModel), args.Error(1)
+}
+
+func (m *MockRepository) InsertPluggyAccountLink(ctx context.Context, params insertPluggyAccountLinkParams) (PluggyAccountLinkModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(PluggyAccountLinkModel), args.Error(1)
+}
+
+func (m *MockRepository) ModifyPluggyAccountLinkSync(ctx context.Context, params modifyPluggyAccountLinkSyncParams) (PluggyAccountLinkModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(PluggyAccountLinkModel), args.Error(1)
+}
+
+func (m *MockRepository) RemovePluggyAccountLink(ctx context.Context, params removePluggyAccountLinkParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FetchCSVImportProfiles(ctx context.Context, params fetchCSVImportProfilesParams) ([]CSVImportProfileModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]CSVImportProfileModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchCSVImportProfileByID(ctx context.Context, params fetchCSVImportProfileByIDParams) (CSVImportProfileModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(CSVImportProfileModel), args.Error(1)
+}
+
+func (m *MockRepository) InsertCSVImportProfile(ctx context.Context, params insertCSVImportProfileParams) (CSVImportProfileModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(CSVImportProfileModel), args.Error(1)
+}
+
+func (m *MockRepository) ModifyCSVImportProfile(ctx context.Context, params modifyCSVImportProfileParams) (CSVImportProfileModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(CSVImportProfileModel), args.Error(1)
+}
+
+func (m *MockRepository) RemoveCSVImportProfile(ctx context.Context, params removeCSVImportProfileParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FetchAccountStatementBalances(ctx context.Context, params fetchAccountStatementBalancesParams) ([]AccountStatementBalanceModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]AccountStatementBalanceModel), args.Error(1)
+}
+
+func (m *MockRepository) UpsertAccountStatementBalance(ctx context.Context, params upsertAccountStatementBalanceParams) (AccountStatementBalanceModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(AccountStatementBalanceModel), args.Error(1)
+}
+
+func (m *MockRepository) FetchAccountTransactionsBetween(ctx context.Context, params fetchAccountTransactionsBetweenParams) ([]TransactionModel, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]TransactionModel), args.Error(1)
+}
+
+func (m *MockRepository) ModifyAccountReconciledThrough(ctx context.Context, params modifyAccountReconciledThroughParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FetchAutoCloseMonths(ctx context.Context, params fetchAutoCloseMonthsParams) (bool, error) {
+	args := m.Called(ctx, params)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) ModifyAutoCloseMonths(ctx context.Context, params modifyAutoCloseMonthsParams) (bool, error) {
+	args := m.Called(ctx, params)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) FetchMaintenanceTargets(ctx context.Context) ([]MaintenanceTargetModel, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]MaintenanceTargetModel), args.Error(1)
+}
+
 // ============================================================================
 // Service Tests
 // ============================================================================
@@ -471,7 +764,9 @@ func TestFinancialService_CreateTransaction_RejectsClosedMonth(t *testing.T) {
 	svc := &service{Repository: mockRepo, system: system.NewSystem()}
 	ctx := context.Background()
 
-	mockRepo.On("IsMonthClosed", ctx, monthClosureParams{OrganizationID: 9, Month: 6, Year: 2026}).Return(true, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, transactionMonthClosureParams{
+		OrganizationID: 9, AccountID: 1, TransactionDate: time.Date(2026, time.June, 18, 0, 0, 0, 0, time.UTC),
+	}).Return(true, nil)
 
 	_, err := svc.CreateTransaction(ctx, CreateTransactionInput{
 		AccountID: 1, UserID: 10, OrganizationID: 9, Description: "Mercado",
@@ -486,10 +781,12 @@ func TestFinancialService_UpdateTransaction_RejectsClosedMonth(t *testing.T) {
 	mockRepo := new(MockRepository)
 	svc := &service{Repository: mockRepo, system: system.NewSystem()}
 	ctx := context.Background()
-	tx := TransactionModel{TransactionID: 4, TransactionDate: time.Date(2026, time.June, 18, 0, 0, 0, 0, time.UTC)}
+	tx := TransactionModel{TransactionID: 4, AccountID: 2, TransactionDate: time.Date(2026, time.June, 18, 0, 0, 0, 0, time.UTC)}
 
 	mockRepo.On("FetchTransactionByID", ctx, fetchTransactionByIDParams{TransactionID: 4, OrganizationID: 9}).Return(tx, nil)
-	mockRepo.On("IsMonthClosed", ctx, monthClosureParams{OrganizationID: 9, Month: 6, Year: 2026}).Return(true, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, transactionMonthClosureParams{
+		OrganizationID: 9, AccountID: 2, TransactionDate: tx.TransactionDate,
+	}).Return(true, nil)
 
 	description := "Novo nome"
 	_, err := svc.UpdateTransaction(ctx, UpdateTransactionInput{TransactionID: 4, OrganizationID: 9, Description: &description})
@@ -525,8 +822,13 @@ func TestFinancialService_CloseMonth_MarksMonthClosedAfterSuccess(t *testing.T)
 		OrganizationID: 9,
 		Month:          6,
 		Year:           2026,
+		BudgetMonth:    true,
 	}).Return([]TransactionModel{}, nil)
+	mockRepo.On("FetchTransferTransactionIDs", ctx, fetchTransferTransactionIDsParams{OrganizationID: 9}).Return([]int{}, nil)
 	mockRepo.On("MarkMonthClosed", ctx, closure).Return(nil)
+	mockRepo.On("InsertAuditLogEntry", ctx, mock.MatchedBy(func(params insertAuditLogEntryParams) bool {
+		return params.EntityType == AuditEntityMonth && params.EntityID == 202606 && params.Action == AuditActionClose
+	})).Return(nil)
 
 	result, err := svc.CloseMonth(ctx, CloseMonthInput{
 		UserID:         10,
@@ -540,6 +842,87 @@ func TestFinancialService_CloseMonth_MarksMonthClosedAfterSuccess(t *testing.T)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestFinancialService_CloseMonth_RefusesMissingExchangeRates(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, system: system.NewSystem()}
+	ctx := context.Background()
+	converted := decimal.NewFromInt(510)
+
+	mockRepo.On("IsMonthClosed", ctx, monthClosureParams{OrganizationID: 9, Month: 6, Year: 2026}).Return(false, nil)
+	mockRepo.On("FetchCategoryBudgets", ctx, mock.Anything).Return([]CategoryBudgetModel{{CategoryBudgetID: 1, CategoryID: 3}}, nil)
+	mockRepo.On("FetchTransactionsByMonth", ctx, mock.Anything).Return([]TransactionModel{
+		{TransactionID: 1, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(100), Currency: "USD", ConvertedAmount: &converted},
+		{TransactionID: 2, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(40), Currency: "EUR"},
+	}, nil)
+	mockRepo.On("FetchTransferTransactionIDs", ctx, fetchTransferTransactionIDsParams{OrganizationID: 9}).Return([]int{}, nil)
+
+	_, err := svc.CloseMonth(ctx, CloseMonthInput{UserID: 10, OrganizationID: 9, Month: 6, Year: 2026})
+
+	assert.ErrorIs(t, err, internalerrors.ErrMissingExchangeRates)
+	mockRepo.AssertNotCalled(t, "FetchCategoryBudgetByID", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "MarkMonthClosed", mock.Anything, mock.Anything)
+}
+
+func TestFinancialService_ReopenMonth_RefusesOpenMonth(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	mockRepo.On("IsMonthClosed", ctx, monthClosureParams{OrganizationID: 9, Month: 6, Year: 2026}).Return(false, nil)
+
+	_, err := svc.ReopenMonth(ctx, ReopenMonthInput{OrganizationID: 9, Month: 6, Year: 2026, Reason: "late invoice"})
+
+	assert.ErrorIs(t, err, internalerrors.ErrMonthNotClosed)
+	mockRepo.AssertNotCalled(t, "ReopenMonth", mock.Anything, mock.Anything)
+}
+
+func TestFinancialService_ReopenMonth_RequiresLaterMonthsOpen(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	mockRepo.On("IsMonthClosed", ctx, monthClosureParams{OrganizationID: 9, Month: 12, Year: 2025}).Return(true, nil)
+	mockRepo.On("IsMonthClosed", ctx, monthClosureParams{OrganizationID: 9, Month: 1, Year: 2026}).Return(true, nil)
+
+	_, err := svc.ReopenMonth(ctx, ReopenMonthInput{OrganizationID: 9, Month: 12, Year: 2025, Reason: "late invoice"})
+
+	assert.ErrorIs(t, err, internalerrors.ErrMonthClosed)
+	mockRepo.AssertNotCalled(t, "ReopenMonth", mock.Anything, mock.Anything)
+}
+
+func TestFinancialService_ReopenMonth_RemovesCarryoverAndRecordsReason(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, logger: &logging.TestLogger{}}
+	ctx := context.Background()
+
+	mockRepo.On("IsMonthClosed", ctx, monthClosureParams{OrganizationID: 9, Month: 6, Year: 2026}).Return(true, nil)
+	mockRepo.On("IsMonthClosed", ctx, monthClosureParams{OrganizationID: 9, Month: 7, Year: 2026}).Return(false, nil)
+	mockRepo.On("ReopenMonth", ctx, reopenMonthParams{
+		OrganizationID: 9,
+		UserID:         10,
+		Month:          6,
+		Year:           2026,
+		Reason:         "late invoice",
+		CarryoverFitID: "CARRYOVER-2026-06",
+	}).Return(MonthReopeningModel{MonthReopeningID: 1, UserID: 10, Month: 6, Year: 2026, Reason: "late invoice", SnapshotCount: 3}, nil)
+	mockRepo.On("InsertAuditLogEntry", ctx, mock.MatchedBy(func(params insertAuditLogEntryParams) bool {
+		return params.EntityType == AuditEntityMonth && params.EntityID == 202606 && params.Action == AuditActionReopen
+	})).Return(nil)
+
+	reopening, err := svc.ReopenMonth(ctx, ReopenMonthInput{
+		UserID:         10,
+		OrganizationID: 9,
+		Month:          6,
+		Year:           2026,
+		Reason:         "  late invoice ",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "late invoice", reopening.Reason)
+	assert.Equal(t, 3, reopening.SnapshotCount)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestGetCategories_Success(t *testing.T) {
 	mockRepo := new(MockRepository)
 	svc := &service{
@@ -618,6 +1001,85 @@ func TestCreateAccount_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestDeleteAccount_RefusesAccountsWithClosedMonths(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, logger: &logging.TestLogger{}}
+	ctx := context.Background()
+
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{AccountID: 5, IsActive: true}, nil)
+	mockRepo.On("CountAccountTransactionsInClosedMonths", ctx, countAccountTransactionsInClosedMonthsParams{
+		AccountID:      5,
+		OrganizationID: 1,
+	}).Return(3, nil)
+
+	err := svc.DeleteAccount(ctx, DeleteAccountInput{AccountID: 5, UserID: 1, OrganizationID: 1})
+
+	assert.ErrorIs(t, err, internalerrors.ErrAccountHasClosedMonths)
+	mockRepo.AssertNotCalled(t, "MoveAccountTransactions", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "RemoveAccount", mock.Anything, mock.Anything)
+}
+
+func TestDeleteAccount_MovesTransactionsBeforeRemoving(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, db: database.NewMemoryDatabase(), logger: &logging.TestLogger{}}
+	ctx := context.Background()
+	target := 6
+	move := moveAccountTransactionsParams{FromAccountID: 5, ToAccountID: 6, OrganizationID: 1}
+
+	mockRepo.On("FetchAccountByID", ctx, fetchAccountByIDParams{AccountID: 5, UserID: 1, OrganizationID: 1}).
+		Return(AccountModel{AccountID: 5, IsActive: true}, nil)
+	mockRepo.On("FetchAccountByID", ctx, fetchAccountByIDParams{AccountID: 6, UserID: 1, OrganizationID: 1}).
+		Return(AccountModel{AccountID: 6, IsActive: true}, nil)
+	mockRepo.On("CountAccountTransactionsInClosedMonths", ctx, mock.Anything).Return(0, nil)
+	mockRepo.On("FetchAccountTransactionConflicts", ctx, move).Return([]string{}, nil)
+	moveCall := mockRepo.On("MoveAccountTransactions", ctx, move).Return(12, nil)
+	mockRepo.On("RemoveAccount", ctx, removeAccountParams{AccountID: 5, UserID: 1, OrganizationID: 1}).
+		Return(nil).NotBefore(moveCall)
+
+	err := svc.DeleteAccount(ctx, DeleteAccountInput{AccountID: 5, UserID: 1, OrganizationID: 1, MoveTransactionsTo: &target})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteAccount_RefusesTransactionsTheTargetAccountHas(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, db: database.NewMemoryDatabase(), logger: &logging.TestLogger{}}
+	ctx := context.Background()
+	target := 6
+
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{AccountID: 6, IsActive: true}, nil)
+	mockRepo.On("CountAccountTransactionsInClosedMonths", ctx, mock.Anything).Return(0, nil)
+	mockRepo.On("FetchAccountTransactionConflicts", ctx, mock.Anything).Return([]string{"FIT-1", "FIT-2"}, nil)
+
+	err := svc.DeleteAccount(ctx, DeleteAccountInput{AccountID: 5, UserID: 1, OrganizationID: 1, MoveTransactionsTo: &target})
+
+	assert.ErrorIs(t, err, internalerrors.ErrTargetAccountHasTransactions)
+	assert.Contains(t, err.Error(), "FIT-1, FIT-2")
+	mockRepo.AssertNotCalled(t, "MoveAccountTransactions", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "RemoveAccount", mock.Anything, mock.Anything)
+}
+
+func TestDeleteAccount_RejectsArchivedOrSameTargetAccount(t *testing.T) {
+	ctx := context.Background()
+
+	for _, target := range []int{5, 6} {
+		mockRepo := new(MockRepository)
+		svc := &service{Repository: mockRepo, logger: &logging.TestLogger{}}
+
+		mockRepo.On("FetchAccountByID", ctx, fetchAccountByIDParams{AccountID: 5, OrganizationID: 1}).
+			Return(AccountModel{AccountID: 5, IsActive: true}, nil)
+		mockRepo.On("FetchAccountByID", ctx, fetchAccountByIDParams{AccountID: 6, OrganizationID: 1}).
+			Return(AccountModel{AccountID: 6, IsActive: false}, nil)
+		mockRepo.On("CountAccountTransactionsInClosedMonths", ctx, mock.Anything).Return(0, nil)
+
+		err := svc.DeleteAccount(ctx, DeleteAccountInput{AccountID: 5, OrganizationID: 1, MoveTransactionsTo: &target})
+
+		assert.ErrorIs(t, err, internalerrors.ErrInvalidTargetAccount, "target %d", target)
+		mockRepo.AssertNotCalled(t, "RemoveAccount", mock.Anything, mock.Anything)
+	}
+}
+
 func TestGetTransactions_DefaultLimit(t *testing.T) {
 	mockRepo := new(MockRepository)
 	svc := &service{
@@ -649,8 +1111,11 @@ func TestFinancialService_CreateTransaction_PreservesDescriptionAndNotes(t *test
 	mockRepo := new(MockRepository)
 	svc := &service{Repository: mockRepo, system: system.NewSystem()}
 	ctx := context.Background()
+	mockRepo.On("InsertAuditLogEntry", ctx, mock.Anything).Return(nil)
 
-	mockRepo.On("IsMonthClosed", ctx, monthClosureParams{OrganizationID: 9, Month: 7, Year: 2026}).Return(false, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, transactionMonthClosureParams{
+		OrganizationID: 9, AccountID: 9, TransactionDate: time.Date(2026, time.July, 19, 0, 0, 0, 0, time.UTC),
+	}).Return(false, nil)
 	mockRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(params insertTransactionParams) bool {
 		return params.Description == "Mercado semanal" &&
 			params.OriginalDescription == "Mercado semanal" &&
@@ -710,13 +1175,16 @@ func TestFinancialService_ComputePlannedEntryStatus_PastExpectedDayIsPending(t *
 	assert.Equal(t, PlannedEntryStatusPending, status)
 }
 
-// pacingTestSetup wires the four repository calls GetControllableCategoryPacing
-// makes: categories, budgets, transactions, and income budget (for the filter).
+// pacingTestSetup wires the repository calls GetControllableCategoryPacing
+// makes: categories, budgets, transactions, matched IDs, splits, and income
+// budget (for the filter).
 func pacingTestSetup(mockRepo *MockRepository, categories []CategoryModel, budgets []CategoryBudgetModel, plannedIncome decimal.Decimal) {
 	mockRepo.On("FetchCategories", mock.Anything, mock.Anything).Return(categories, nil)
 	mockRepo.On("FetchCategoryBudgets", mock.Anything, mock.Anything).Return(budgets, nil)
 	mockRepo.On("FetchTransactionsByMonth", mock.Anything, mock.Anything).Return([]TransactionModel{}, nil)
 	mockRepo.On("FetchMatchedTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransferTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransactionSplitsByMonth", mock.Anything, mock.Anything).Return([]TransactionSplitModel{}, nil)
 	mockRepo.On("FetchIncomeBudgetForMonth", mock.Anything, mock.Anything).Return(plannedIncome, nil)
 }
 
@@ -789,6 +1257,8 @@ func TestGetControllableCategoryPacing_IncludesSystemCategories(t *testing.T) {
 	mockRepo.On("FetchCategoryBudgets", mock.Anything, mock.Anything).Return(budgets, nil)
 	mockRepo.On("FetchTransactionsByMonth", mock.Anything, mock.Anything).Return([]TransactionModel{}, nil)
 	mockRepo.On("FetchMatchedTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransferTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransactionSplitsByMonth", mock.Anything, mock.Anything).Return([]TransactionSplitModel{}, nil)
 	mockRepo.On("FetchIncomeBudgetForMonth", mock.Anything, mock.Anything).Return(decimal.NewFromInt(45000), nil)
 
 	result, err := svc.GetControllableCategoryPacing(ctx, GetControllableCategoryPacingInput{
@@ -850,6 +1320,8 @@ func TestGetControllableCategoryPacing_ExcludesPlannedSpending(t *testing.T) {
 	mockRepo.On("FetchCategoryBudgets", mock.Anything, mock.Anything).Return(budgets, nil)
 	mockRepo.On("FetchTransactionsByMonth", mock.Anything, mock.Anything).Return(transactions, nil)
 	mockRepo.On("FetchMatchedTransactionIDs", mock.Anything, mock.Anything).Return([]int{7}, nil)
+	mockRepo.On("FetchTransferTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransactionSplitsByMonth", mock.Anything, mock.Anything).Return([]TransactionSplitModel{}, nil)
 	mockRepo.On("FetchIncomeBudgetForMonth", mock.Anything, mock.Anything).Return(decimal.NewFromInt(45000), nil)
 
 	result, err := svc.GetControllableCategoryPacing(ctx, GetControllableCategoryPacingInput{
@@ -884,6 +1356,8 @@ func TestGetControllableCategoryPacing_UsesBudgetGranularityForExpectedSpend(t *
 	mockRepo.On("FetchCategoryBudgets", mock.Anything, mock.Anything).Return(budgets, nil)
 	mockRepo.On("FetchTransactionsByMonth", mock.Anything, mock.Anything).Return(transactions, nil)
 	mockRepo.On("FetchMatchedTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransferTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransactionSplitsByMonth", mock.Anything, mock.Anything).Return([]TransactionSplitModel{}, nil)
 	mockRepo.On("FetchIncomeBudgetForMonth", mock.Anything, mock.Anything).Return(decimal.Zero, nil)
 
 	result, err := svc.GetControllableCategoryPacing(ctx, GetControllableCategoryPacingInput{
@@ -932,6 +1406,8 @@ func TestGetControllableCategoryPacing_DerivesGranularityFromPreviousMonth(t *te
 		return params.Month == 5 && params.Year == 2026
 	})).Return(previousTransactions, nil)
 	mockRepo.On("FetchMatchedTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransferTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransactionSplitsByMonth", mock.Anything, mock.Anything).Return([]TransactionSplitModel{}, nil)
 	mockRepo.On("FetchIncomeBudgetForMonth", mock.Anything, mock.Anything).Return(decimal.Zero, nil)
 
 	result, err := svc.GetControllableCategoryPacing(ctx, GetControllableCategoryPacingInput{
@@ -1036,3 +1512,63 @@ func TestCreateSavingsGoal_ReturnsNameExistsForOpenGoalNameConflict(t *testing.T
 	assert.ErrorIs(t, err, internalerrors.ErrSavingsGoalNameExists)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestImportTransactionsFromOFX_CountsMalformedTransactionsAndReturnsStatement(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, system: system.NewStubSystem().ToSystem(), logger: &logging.TestLogger{}}
+	ctx := context.Background()
+
+	ofxData := `<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>BRL
+<BANKACCTFROM><BANKID>260<ACCTID>1234-5<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20251002<TRNAMT>-30.00<FITID>ok-1<MEMO>Padaria</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>2025XX02<TRNAMT>-10.00<FITID>bad-date</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20251003<TRNAMT>abc<FITID>bad-amount</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>870.00<DTASOF>20251031</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+
+	mockRepo.On("FetchAccountByID", ctx, mock.Anything).Return(AccountModel{AccountID: 1, Currency: "BRL"}, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.Anything).Return(false, nil)
+	mockRepo.On("BulkInsertTransactions", ctx, mock.MatchedBy(func(params bulkInsertTransactionsParams) bool {
+		return len(params.Transactions) == 1 && *params.Transactions[0].OFXFitID == "ok-1"
+	})).Return([]TransactionModel{}, nil)
+
+	balanceDate := time.Date(2025, time.October, 31, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("UpsertAccountStatementBalance", ctx, mock.MatchedBy(func(params upsertAccountStatementBalanceParams) bool {
+		return params.BalanceDate.Equal(balanceDate) &&
+			params.Balance.Equal(decimal.NewFromInt(870)) &&
+			params.Source == StatementBalanceSourceOFX
+	})).Return(AccountStatementBalanceModel{
+		AccountID:       1,
+		BalanceDate:     balanceDate,
+		Balance:         decimal.NewFromInt(870),
+		Source:          StatementBalanceSourceOFX,
+		ComputedBalance: decimal.NewFromInt(870),
+	}, nil)
+	mockRepo.On("ModifyAccountReconciledThrough", ctx, modifyAccountReconciledThroughParams{
+		AccountID:         1,
+		OrganizationID:    1,
+		ReconciledThrough: balanceDate,
+	}).Return(nil)
+
+	output, err := svc.ImportTransactionsFromOFX(ctx, ImportOFXInput{
+		AccountID:      1,
+		UserID:         1,
+		OrganizationID: 1,
+		OFXData:        []byte(ofxData),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, output.Reconciliations, 1)
+	assert.True(t, output.Reconciliations[0].Matches)
+	assert.Equal(t, 2, output.ErrorCount)
+	assert.Equal(t, 1, output.DuplicateCount)
+	require.Len(t, output.Statements, 1)
+	assert.Equal(t, "1234-5", output.Statements[0].Account.AccountID)
+	assert.Equal(t, "BRL", output.Statements[0].Currency)
+	require.NotNil(t, output.Statements[0].LedgerBalance)
+	assert.Equal(t, "870", output.Statements[0].LedgerBalance.Amount.String())
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,441 @@
+package financial
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Transaction export formats
+const (
+	TransactionExportCSV  = "csv"
+	TransactionExportOFX  = "ofx"
+	TransactionExportXLSX = "xlsx"
+)
+
+// transactionExportBatchSize is how many transactions are read per query
+// while an export is streamed.
+const transactionExportBatchSize = 500
+
+// transactionExportColumns heads the CSV and XLSX exports. Amounts are signed:
+// debits are negative.
+var transactionExportColumns = []string{"Date", "Account", "Description", "Category", "Tags", "Type", "Amount", "Currency", "Notes"}
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type ExportTransactionsInput struct {
+	SearchTransactionsInput        // Filters; Cursor and Limit are ignored, and the order defaults to oldest first
+	Format                  string // csv, ofx or xlsx
+	DecimalSeparator        string // CSV only: "." (default) or ","; with "," fields are separated by ";"
+}
+
+// TransactionExport is a validated export. Nothing is read from the
+// transactions until Write streams them out.
+type TransactionExport struct {
+	ContentType string
+	FileName    string
+	write       func(w io.Writer) error
+}
+
+// Write streams the export to w, a batch of transactions at a time.
+func (e TransactionExport) Write(w io.Writer) error {
+	return e.write(w)
+}
+
+// transactionExportLookups resolves the IDs of exported transactions to the
+// names shown in the file.
+type transactionExportLookups struct {
+	accounts   map[int]AccountModel
+	categories map[int]string
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// ExportTransactions prepares an export of the transactions matching the same
+// filters as SearchTransactions. OFX produces one statement per account: a
+// single .ofx when only one account is exported, otherwise a ZIP holding a
+// file for each account with matching transactions.
+func (s *service) ExportTransactions(ctx context.Context, params ExportTransactionsInput) (TransactionExport, error) {
+	filters := params.SearchTransactionsInput
+	if filters.SortOrder == "" {
+		filters.SortOrder = "asc"
+	}
+	search, err := newTransactionSearch(filters)
+	if err != nil {
+		return TransactionExport{}, err
+	}
+	search.Limit = transactionExportBatchSize
+
+	lookups, err := s.transactionExportLookups(ctx, params.OrganizationID)
+	if err != nil {
+		return TransactionExport{}, err
+	}
+
+	now := s.system.Time.Now()
+	baseName := "transactions-" + now.Format("2006-01-02")
+
+	switch params.Format {
+	case TransactionExportCSV:
+		comma := ','
+		switch params.DecimalSeparator {
+		case "", ".":
+		case ",":
+			comma = ';'
+		default:
+			return TransactionExport{}, errors.Wrap(internalerrors.ErrInvalidTransactionExport, "invalid decimal separator %q", params.DecimalSeparator)
+		}
+		return TransactionExport{
+			ContentType: "text/csv; charset=utf-8",
+			FileName:    baseName + ".csv",
+			write: func(w io.Writer) error {
+				return s.writeTransactionsCSV(ctx, w, search, lookups, comma)
+			},
+		}, nil
+
+	case TransactionExportXLSX:
+		return TransactionExport{
+			ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			FileName:    baseName + ".xlsx",
+			write: func(w io.Writer) error {
+				return s.writeTransactionsXLSX(ctx, w, search, lookups)
+			},
+		}, nil
+
+	case TransactionExportOFX:
+		// Statements list their transactions in date order
+		search.SortBy = TransactionSortDate
+		search.Descending = false
+
+		var accounts []AccountModel
+		for _, account := range lookups.accounts {
+			if search.AccountIDs == nil || slices.Contains(search.AccountIDs, account.AccountID) {
+				accounts = append(accounts, account)
+			}
+		}
+		if len(accounts) == 0 {
+			return TransactionExport{}, errors.Wrap(internalerrors.ErrInvalidTransactionExport, "no accounts to export")
+		}
+		slices.SortFunc(accounts, func(a, b AccountModel) int { return a.AccountID - b.AccountID })
+
+		if len(accounts) == 1 {
+			return TransactionExport{
+				ContentType: "application/x-ofx",
+				FileName:    baseName + ".ofx",
+				write: func(w io.Writer) error {
+					return s.writeAccountOFX(ctx, w, nil, search, accounts[0], now)
+				},
+			}, nil
+		}
+		return TransactionExport{
+			ContentType: "application/zip",
+			FileName:    baseName + "-ofx.zip",
+			write: func(w io.Writer) error {
+				archive := zip.NewWriter(w)
+				for _, account := range accounts {
+					if err := s.writeAccountOFX(ctx, nil, archive, search, account, now); err != nil {
+						return err
+					}
+				}
+				return archive.Close()
+			},
+		}, nil
+
+	default:
+		return TransactionExport{}, errors.Wrap(internalerrors.ErrInvalidTransactionExport, "invalid format %q", params.Format)
+	}
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (s *service) transactionExportLookups(ctx context.Context, organizationID int) (transactionExportLookups, error) {
+	accounts, err := s.Repository.FetchAccounts(ctx, fetchAccountsParams{OrganizationID: organizationID})
+	if err != nil {
+		return transactionExportLookups{}, errors.Wrap(err, "failed to fetch accounts")
+	}
+	categories, err := s.Repository.FetchCategories(ctx, fetchCategoriesParams{OrganizationID: &organizationID, IncludeSystem: true})
+	if err != nil {
+		return transactionExportLookups{}, errors.Wrap(err, "failed to fetch categories")
+	}
+
+	lookups := transactionExportLookups{
+		accounts:   make(map[int]AccountModel, len(accounts)),
+		categories: make(map[int]string, len(categories)),
+	}
+	for _, account := range accounts {
+		lookups.accounts[account.AccountID] = account
+	}
+	for _, category := range categories {
+		lookups.categories[category.CategoryID] = category.Name
+	}
+	return lookups, nil
+}
+
+// eachTransactionBatch pages through every transaction matching search by
+// keyset, handing each batch to fn.
+func (s *service) eachTransactionBatch(ctx context.Context, search searchTransactionsParams, fn func(batch []TransactionModel) error) error {
+	for {
+		batch, err := s.Repository.SearchTransactions(ctx, search)
+		if err != nil {
+			return errors.Wrap(err, "failed to search transactions")
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < search.Limit {
+			return nil
+		}
+
+		last := batch[len(batch)-1]
+		value := transactionSortValue(&last, search.SortBy)
+		search.AfterValue = &value
+		search.AfterID = &last.TransactionID
+	}
+}
+
+func (s *service) writeTransactionsCSV(ctx context.Context, w io.Writer, search searchTransactionsParams, lookups transactionExportLookups, comma rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	if err := writer.Write(transactionExportColumns); err != nil {
+		return err
+	}
+
+	err := s.eachTransactionBatch(ctx, search, func(batch []TransactionModel) error {
+		tags, err := s.transactionExportTags(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for i := range batch {
+			tx := &batch[i]
+			notes := ""
+			if tx.Notes != nil {
+				notes = *tx.Notes
+			}
+			amount := signedTransactionAmount(tx).StringFixed(2)
+			if comma == ';' {
+				amount = strings.Replace(amount, ".", ",", 1)
+			}
+			record := []string{
+				tx.TransactionDate.Format("2006-01-02"),
+				csvText(lookups.accounts[tx.AccountID].Name),
+				csvText(tx.Description),
+				csvText(lookups.categoryName(tx.CategoryID)),
+				csvText(strings.Join(tags[tx.TransactionID], ", ")),
+				tx.TransactionType,
+				amount,
+				tx.Currency,
+				csvText(notes),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvText keeps spreadsheets from evaluating free text as a formula: cells
+// starting with =, +, - or @ (or a tab or carriage return) get a leading '.
+// Amounts are written as they are so they stay numbers.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (s *service) writeTransactionsXLSX(ctx context.Context, w io.Writer, search searchTransactionsParams, lookups transactionExportLookups) error {
+	workbook, err := newXLSXWriter(w, "Transactions")
+	if err != nil {
+		return err
+	}
+
+	header := make([]xlsxCell, len(transactionExportColumns))
+	for i, column := range transactionExportColumns {
+		header[i] = xlsxHeader(column)
+	}
+	if err := workbook.WriteRow(header...); err != nil {
+		return err
+	}
+
+	err = s.eachTransactionBatch(ctx, search, func(batch []TransactionModel) error {
+		tags, err := s.transactionExportTags(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for i := range batch {
+			tx := &batch[i]
+			notes := ""
+			if tx.Notes != nil {
+				notes = *tx.Notes
+			}
+			err := workbook.WriteRow(
+				xlsxDate(tx.TransactionDate),
+				xlsxString(lookups.accounts[tx.AccountID].Name),
+				xlsxString(tx.Description),
+				xlsxString(lookups.categoryName(tx.CategoryID)),
+				xlsxString(strings.Join(tags[tx.TransactionID], ", ")),
+				xlsxString(tx.TransactionType),
+				xlsxAmount(signedTransactionAmount(tx)),
+				xlsxString(tx.Currency),
+				xlsxString(notes),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return workbook.Close()
+}
+
+// writeAccountOFX writes one account's statement to w, or to a new file in
+// archive when w is nil. The archive file is only created once the account
+// has a matching transaction, so accounts without any are left out.
+func (s *service) writeAccountOFX(ctx context.Context, w io.Writer, archive *zip.Writer, search searchTransactionsParams, account AccountModel, now time.Time) error {
+	search.AccountIDs = []int{account.AccountID}
+
+	var statement *ofxStatementWriter
+	open := func(start time.Time) error {
+		if archive != nil {
+			file, err := archive.Create(ofxExportFileName(account))
+			if err != nil {
+				return err
+			}
+			w = file
+		}
+		statement = newOFXStatementWriter(w, ofxAccountFromModel(account))
+		if search.From != nil {
+			start = *search.From
+		}
+		end := now
+		if search.To != nil {
+			end = *search.To
+		}
+		return statement.WriteHeader(account.Currency, start, end, now)
+	}
+
+	err := s.eachTransactionBatch(ctx, search, func(batch []TransactionModel) error {
+		if statement == nil {
+			if err := open(batch[0].TransactionDate); err != nil {
+				return err
+			}
+		}
+		for i := range batch {
+			if err := statement.WriteTransaction(ofxTransactionFromModel(&batch[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if statement == nil {
+		if archive != nil {
+			return nil
+		}
+		// A single account is always exported, even without transactions
+		if err := open(now); err != nil {
+			return err
+		}
+	}
+
+	return statement.WriteFooter(OFXBalance{Amount: account.Balance, AsOf: now})
+}
+
+// transactionExportTags returns the tag names of each transaction in batch.
+func (s *service) transactionExportTags(ctx context.Context, batch []TransactionModel) (map[int][]string, error) {
+	ids := make([]int, len(batch))
+	for i := range batch {
+		ids[i] = batch[i].TransactionID
+	}
+	tags, err := s.Repository.FetchTransactionTagNames(ctx, fetchTransactionTagNamesParams{TransactionIDs: ids})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transaction tags")
+	}
+	return tags, nil
+}
+
+func (l transactionExportLookups) categoryName(categoryID *int) string {
+	if categoryID == nil {
+		return ""
+	}
+	return l.categories[*categoryID]
+}
+
+// signedTransactionAmount returns the amount negated for debits.
+func signedTransactionAmount(tx *TransactionModel) decimal.Decimal {
+	if tx.TransactionType == TransactionTypeDebit {
+		return tx.Amount.Abs().Neg()
+	}
+	return tx.Amount.Abs()
+}
+
+func ofxAccountFromModel(account AccountModel) OFXAccount {
+	ofxAccount := OFXAccount{AccountID: fmt.Sprint(account.AccountID)}
+	switch account.AccountType {
+	case AccountTypeCreditCard:
+		ofxAccount.AccountType = "CREDITCARD"
+		ofxAccount.IsCreditCard = true
+	case AccountTypeSavings:
+		ofxAccount.AccountType = "SAVINGS"
+	case AccountTypeInvestment:
+		ofxAccount.AccountType = "MONEYMRKT"
+	default:
+		ofxAccount.AccountType = "CHECKING"
+	}
+	return ofxAccount
+}
+
+// ofxExportFileName names an account's statement inside the OFX archive, e.g.
+// "12-nubank-conta.ofx".
+func ofxExportFileName(account AccountModel) string {
+	var name strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(account.Name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			name.WriteRune(r)
+			dash = false
+		} else if !dash && name.Len() > 0 {
+			name.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(name.String(), "-")
+	if slug == "" {
+		return fmt.Sprintf("%d.ofx", account.AccountID)
+	}
+	return fmt.Sprintf("%d-%s.ofx", account.AccountID, slug)
+}
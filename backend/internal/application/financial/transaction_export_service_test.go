@@ -0,0 +1,324 @@
+package financial
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func mockTransactionExportLookups(mockRepo *MockRepository, ctx context.Context) {
+	mockRepo.On("FetchAccounts", ctx, fetchAccountsParams{OrganizationID: 1}).Return([]AccountModel{
+		{AccountID: 10, Name: "Conta Família", AccountType: AccountTypeChecking, Currency: "BRL", Balance: decimal.NewFromInt(1500)},
+		{AccountID: 20, Name: "Cartão", AccountType: AccountTypeCreditCard, Currency: "BRL"},
+	}, nil)
+	mockRepo.On("FetchCategories", ctx, mock.Anything).Return([]CategoryModel{{CategoryID: 3, Name: "Mercado"}}, nil)
+}
+
+func TestExportTransactions_CSVPagesThroughAllTransactions(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+	mockTransactionExportLookups(mockRepo, ctx)
+	groceries := 3
+	notes := "semana"
+
+	firstBatch := make([]TransactionModel, transactionExportBatchSize)
+	for i := range firstBatch {
+		firstBatch[i] = TransactionModel{
+			TransactionID:   i + 1,
+			AccountID:       10,
+			Description:     "PADARIA",
+			Amount:          decimal.RequireFromString("12.5"),
+			TransactionDate: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC),
+			TransactionType: TransactionTypeDebit,
+			Currency:        "BRL",
+		}
+	}
+	firstBatch[0].CategoryID = &groceries
+	firstBatch[0].Notes = &notes
+
+	mockRepo.On("SearchTransactions", ctx, mock.MatchedBy(func(p searchTransactionsParams) bool {
+		return p.AfterID == nil
+	})).Return(firstBatch, nil).Once()
+	mockRepo.On("SearchTransactions", ctx, mock.MatchedBy(func(p searchTransactionsParams) bool {
+		return p.AfterID != nil && *p.AfterID == transactionExportBatchSize && *p.AfterValue == "2026-01-05" && !p.Descending
+	})).Return([]TransactionModel{{
+		TransactionID:   900,
+		AccountID:       10,
+		Description:     "SALARIO",
+		Amount:          decimal.NewFromInt(5000),
+		TransactionDate: time.Date(2026, time.January, 6, 0, 0, 0, 0, time.UTC),
+		TransactionType: TransactionTypeCredit,
+		Currency:        "BRL",
+	}}, nil).Once()
+	mockRepo.On("FetchTransactionTagNames", ctx, mock.Anything).Return(map[int][]string{1: {"Casa", "Férias"}}, nil)
+
+	export, err := svc.ExportTransactions(ctx, ExportTransactionsInput{
+		SearchTransactionsInput: SearchTransactionsInput{OrganizationID: 1},
+		Format:                  TransactionExportCSV,
+		DecimalSeparator:        ",",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "transactions-2026-04-15.csv", export.FileName)
+
+	var buffer bytes.Buffer
+	require.NoError(t, export.Write(&buffer))
+	mockRepo.AssertExpectations(t)
+
+	reader := csv.NewReader(&buffer)
+	reader.Comma = ';'
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, transactionExportBatchSize+2)
+	assert.Equal(t, transactionExportColumns, records[0])
+	assert.Equal(t, []string{"2026-01-05", "Conta Família", "PADARIA", "Mercado", "Casa, Férias", "debit", "-12,50", "BRL", "semana"}, records[1])
+	assert.Equal(t, "5000,00", records[len(records)-1][6])
+}
+
+func TestExportTransactions_CSVNeutralizesFormulas(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+	mockTransactionExportLookups(mockRepo, ctx)
+	notes := "@SUM(A1:A9)"
+
+	mockRepo.On("SearchTransactions", ctx, mock.Anything).Return([]TransactionModel{{
+		TransactionID:   1,
+		AccountID:       10,
+		Description:     `=HYPERLINK("http://example.com","PIX")`,
+		Notes:           &notes,
+		Amount:          decimal.RequireFromString("12.5"),
+		TransactionDate: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC),
+		TransactionType: TransactionTypeDebit,
+		Currency:        "BRL",
+	}}, nil).Once()
+	mockRepo.On("FetchTransactionTagNames", ctx, mock.Anything).Return(map[int][]string{1: {"+55", "-casa"}}, nil)
+
+	export, err := svc.ExportTransactions(ctx, ExportTransactionsInput{
+		SearchTransactionsInput: SearchTransactionsInput{OrganizationID: 1},
+		Format:                  TransactionExportCSV,
+	})
+	require.NoError(t, err)
+
+	var buffer bytes.Buffer
+	require.NoError(t, export.Write(&buffer))
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, `'=HYPERLINK("http://example.com","PIX")`, records[1][2])
+	assert.Equal(t, "'+55, -casa", records[1][4])
+	assert.Equal(t, "-12.50", records[1][6])
+	assert.Equal(t, "'@SUM(A1:A9)", records[1][8])
+}
+
+func TestExportTransactions_OFXRoundTripsThroughParser(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+	mockTransactionExportLookups(mockRepo, ctx)
+	fitID := "2026010501"
+
+	mockRepo.On("SearchTransactions", ctx, mock.MatchedBy(func(p searchTransactionsParams) bool {
+		return len(p.AccountIDs) == 1 && p.AccountIDs[0] == 10 && p.SortBy == TransactionSortDate && !p.Descending
+	})).Return([]TransactionModel{
+		{
+			TransactionID:   1,
+			AccountID:       10,
+			Description:     "COMPRA CARTAO - SUPERMERCADO BOM PRECO & CIA",
+			Amount:          decimal.RequireFromString("230.10"),
+			TransactionDate: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC),
+			TransactionType: TransactionTypeDebit,
+			OFXFitID:        &fitID,
+		},
+		{
+			TransactionID:   2,
+			AccountID:       10,
+			Description:     "PIX RECEBIDO",
+			Amount:          decimal.NewFromInt(100),
+			TransactionDate: time.Date(2026, time.January, 7, 0, 0, 0, 0, time.UTC),
+			TransactionType: TransactionTypeCredit,
+		},
+	}, nil)
+
+	export, err := svc.ExportTransactions(ctx, ExportTransactionsInput{
+		SearchTransactionsInput: SearchTransactionsInput{OrganizationID: 1, AccountIDs: []int{10}},
+		Format:                  TransactionExportOFX,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "application/x-ofx", export.ContentType)
+
+	var buffer bytes.Buffer
+	require.NoError(t, export.Write(&buffer))
+	mockRepo.AssertNotCalled(t, "FetchTransactionTagNames", mock.Anything, mock.Anything)
+
+	doc, err := NewOFXParser().Parse(buffer.Bytes())
+	require.NoError(t, err)
+	require.Empty(t, doc.Errors)
+	require.Len(t, doc.Statements, 1)
+	statement := doc.Statements[0]
+	assert.Equal(t, "10", statement.Account.AccountID)
+	assert.Equal(t, "CHECKING", statement.Account.AccountType)
+	assert.Equal(t, "BRL", statement.Currency)
+	require.NotNil(t, statement.LedgerBalance)
+	assert.Equal(t, "1500", statement.LedgerBalance.Amount.String())
+
+	require.Len(t, statement.Transactions, 2)
+	purchase := statement.Transactions[0]
+	assert.Equal(t, TransactionTypeDebit, purchase.Type)
+	assert.Equal(t, "-230.1", purchase.Amount.String())
+	assert.Equal(t, fitID, purchase.FITID)
+	assert.Equal(t, "COMPRA CARTAO - SUPERMERCADO BOM", purchase.Name)
+	assert.Equal(t, "COMPRA CARTAO - SUPERMERCADO BOM PRECO & CIA", purchase.Memo)
+	assert.Equal(t, "CELEIRO2", statement.Transactions[1].FITID)
+}
+
+func TestExportTransactions_OFXArchiveSkipsAccountsWithoutTransactions(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+	mockTransactionExportLookups(mockRepo, ctx)
+
+	mockRepo.On("SearchTransactions", ctx, mock.MatchedBy(func(p searchTransactionsParams) bool {
+		return p.AccountIDs[0] == 10
+	})).Return([]TransactionModel{}, nil)
+	mockRepo.On("SearchTransactions", ctx, mock.MatchedBy(func(p searchTransactionsParams) bool {
+		return p.AccountIDs[0] == 20
+	})).Return([]TransactionModel{{
+		TransactionID:   5,
+		AccountID:       20,
+		Description:     "STREAMING",
+		Amount:          decimal.NewFromInt(40),
+		TransactionDate: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		TransactionType: TransactionTypeDebit,
+	}}, nil)
+
+	export, err := svc.ExportTransactions(ctx, ExportTransactionsInput{
+		SearchTransactionsInput: SearchTransactionsInput{OrganizationID: 1},
+		Format:                  TransactionExportOFX,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "application/zip", export.ContentType)
+
+	var buffer bytes.Buffer
+	require.NoError(t, export.Write(&buffer))
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.NoError(t, err)
+	require.Len(t, archive.File, 1)
+	assert.Equal(t, "20-cartão.ofx", archive.File[0].Name)
+
+	doc, err := NewOFXParser().Parse(readZipFile(t, archive.File[0]))
+	require.NoError(t, err)
+	assert.True(t, doc.Statements[0].Account.IsCreditCard)
+}
+
+func TestExportTransactions_XLSXHasCategoryAndTagColumns(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+	mockTransactionExportLookups(mockRepo, ctx)
+	groceries := 3
+
+	mockRepo.On("SearchTransactions", ctx, mock.Anything).Return([]TransactionModel{{
+		TransactionID:   1,
+		AccountID:       10,
+		CategoryID:      &groceries,
+		Description:     "MERCADO <CENTRO>",
+		Amount:          decimal.RequireFromString("89.90"),
+		TransactionDate: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC),
+		TransactionType: TransactionTypeDebit,
+		Currency:        "BRL",
+	}}, nil)
+	mockRepo.On("FetchTransactionTagNames", ctx, fetchTransactionTagNamesParams{TransactionIDs: []int{1}}).
+		Return(map[int][]string{1: {"Casa"}}, nil)
+
+	export, err := svc.ExportTransactions(ctx, ExportTransactionsInput{
+		SearchTransactionsInput: SearchTransactionsInput{OrganizationID: 1},
+		Format:                  TransactionExportXLSX,
+	})
+	require.NoError(t, err)
+
+	var buffer bytes.Buffer
+	require.NoError(t, export.Write(&buffer))
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.NoError(t, err)
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		assert.Contains(t, files, name)
+	}
+
+	sheet := string(readZipFile(t, files["xl/worksheets/sheet1.xml"]))
+	assert.Contains(t, sheet, `<c r="D1" s="3" t="inlineStr"><is><t xml:space="preserve">Category</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2" s="1"><v>46027</v></c>`) // 2026-01-05
+	assert.Contains(t, sheet, `MERCADO &lt;CENTRO&gt;`)
+	assert.Contains(t, sheet, `<c r="D2" t="inlineStr"><is><t xml:space="preserve">Mercado</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="E2" t="inlineStr"><is><t xml:space="preserve">Casa</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="G2" s="2"><v>-89.9</v></c>`)
+	assert.NotContains(t, sheet, `r="I2"`) // No notes
+}
+
+func TestExportTransactions_RejectsInvalidOptions(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := newReportsTestService(mockRepo)
+	ctx := context.Background()
+	mockTransactionExportLookups(mockRepo, ctx)
+
+	_, err := svc.ExportTransactions(ctx, ExportTransactionsInput{
+		SearchTransactionsInput: SearchTransactionsInput{OrganizationID: 1},
+		Format:                  "pdf",
+	})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidTransactionExport)
+
+	_, err = svc.ExportTransactions(ctx, ExportTransactionsInput{
+		SearchTransactionsInput: SearchTransactionsInput{OrganizationID: 1},
+		Format:                  TransactionExportCSV,
+		DecimalSeparator:        "'",
+	})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidTransactionExport)
+
+	_, err = svc.ExportTransactions(ctx, ExportTransactionsInput{
+		SearchTransactionsInput: SearchTransactionsInput{OrganizationID: 1, AccountIDs: []int{99}},
+		Format:                  TransactionExportOFX,
+	})
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidTransactionExport)
+
+	_, err = svc.ExportTransactions(ctx, ExportTransactionsInput{
+		SearchTransactionsInput: SearchTransactionsInput{OrganizationID: 1, From: "05/01/2026"},
+		Format:                  TransactionExportCSV,
+	})
+	assert.Error(t, err)
+
+	mockRepo.AssertNotCalled(t, "SearchTransactions", mock.Anything, mock.Anything)
+}
+
+func TestXLSXColumn(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumn(0))
+	assert.Equal(t, "Z", xlsxColumn(25))
+	assert.Equal(t, "AA", xlsxColumn(26))
+	assert.Equal(t, "AZ", xlsxColumn(51))
+	assert.Equal(t, "BA", xlsxColumn(52))
+}
+
+func readZipFile(t *testing.T, file *zip.File) []byte {
+	reader, err := file.Open()
+	require.NoError(t, err)
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return data
+}
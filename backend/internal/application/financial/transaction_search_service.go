@@ -0,0 +1,235 @@
+package financial
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Transaction search sort keys
+const (
+	TransactionSortDate   = "date"
+	TransactionSortAmount = "amount" // Absolute amount
+)
+
+const (
+	defaultTransactionSearchLimit = 50
+	maxTransactionSearchLimit     = 500
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type SearchTransactionsInput struct {
+	OrganizationID  int
+	From            string // YYYY-MM-DD, inclusive
+	To              string // YYYY-MM-DD, inclusive
+	AccountIDs      []int
+	CategoryIDs     []int
+	TagIDs          []int
+	MinAmount       *decimal.Decimal // Compared against the absolute amount
+	MaxAmount       *decimal.Decimal
+	TransactionType string
+	IsIgnored       *bool
+	NeedsReview     *bool
+	SavingsGoalID   *int
+	Query           string // Matched against description, original description and notes
+	SortBy          string // date (default) or amount
+	SortOrder       string // desc (default) or asc
+	Cursor          string // NextCursor from the previous page
+	Limit           int
+}
+
+type TransactionSearchResult struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   *string       `json:"next_cursor"` // nil on the last page
+}
+
+// transactionSearchCursor is the keyset position encoded into the opaque
+// cursor. It carries the sort so a cursor cannot be replayed against a
+// different ordering.
+type transactionSearchCursor struct {
+	SortBy        string `json:"s"`
+	Descending    bool   `json:"d"`
+	Value         string `json:"v"`
+	TransactionID int    `json:"id"`
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+// SearchTransactions lists the organization's transactions across accounts,
+// one page at a time. Pages are cut by keyset rather than offset so results
+// stay stable while transactions are imported.
+func (s *service) SearchTransactions(ctx context.Context, params SearchTransactionsInput) (TransactionSearchResult, error) {
+	search, err := newTransactionSearch(params)
+	if err != nil {
+		return TransactionSearchResult{}, err
+	}
+
+	if search.Limit <= 0 {
+		search.Limit = defaultTransactionSearchLimit
+	}
+	if search.Limit > maxTransactionSearchLimit {
+		search.Limit = maxTransactionSearchLimit
+	}
+
+	if params.Cursor != "" {
+		cursor, err := decodeTransactionSearchCursor(params.Cursor)
+		if err != nil || cursor.SortBy != search.SortBy || cursor.Descending != search.Descending ||
+			!validTransactionSortValue(cursor.Value, cursor.SortBy) {
+			return TransactionSearchResult{}, internalerrors.ErrInvalidCursor
+		}
+		search.AfterValue = &cursor.Value
+		search.AfterID = &cursor.TransactionID
+	}
+
+	pageSize := search.Limit
+	search.Limit++ // One extra row tells whether another page exists
+
+	models, err := s.Repository.SearchTransactions(ctx, search)
+	if err != nil {
+		return TransactionSearchResult{}, errors.Wrap(err, "failed to search transactions")
+	}
+
+	result := TransactionSearchResult{}
+	if len(models) > pageSize {
+		models = models[:pageSize]
+		last := models[pageSize-1]
+		next := encodeTransactionSearchCursor(transactionSearchCursor{
+			SortBy:        search.SortBy,
+			Descending:    search.Descending,
+			Value:         transactionSortValue(&last, search.SortBy),
+			TransactionID: last.TransactionID,
+		})
+		result.NextCursor = &next
+	}
+	result.Transactions = Transactions{}.FromModel(models)
+
+	return result, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// newTransactionSearch validates the filters shared by the transaction list
+// and exports. Paging is left to the caller.
+func newTransactionSearch(params SearchTransactionsInput) (searchTransactionsParams, error) {
+	search := searchTransactionsParams{
+		OrganizationID: params.OrganizationID,
+		AccountIDs:     nilIfEmpty(params.AccountIDs),
+		CategoryIDs:    nilIfEmpty(params.CategoryIDs),
+		TagIDs:         nilIfEmpty(params.TagIDs),
+		MinAmount:      params.MinAmount,
+		MaxAmount:      params.MaxAmount,
+		IsIgnored:      params.IsIgnored,
+		NeedsReview:    params.NeedsReview,
+		SavingsGoalID:  params.SavingsGoalID,
+		SortBy:         params.SortBy,
+		Descending:     true,
+		Limit:          params.Limit,
+	}
+
+	if params.From != "" {
+		from, err := parseTransactionDate(params.From)
+		if err != nil {
+			return searchTransactionsParams{}, internalerrors.NewInvalidTimeFormatError("from")
+		}
+		search.From = &from
+	}
+	if params.To != "" {
+		to, err := parseTransactionDate(params.To)
+		if err != nil {
+			return searchTransactionsParams{}, internalerrors.NewInvalidTimeFormatError("to")
+		}
+		search.To = &to
+	}
+
+	switch params.TransactionType {
+	case "":
+	case TransactionTypeDebit, TransactionTypeCredit:
+		search.TransactionType = &params.TransactionType
+	default:
+		return searchTransactionsParams{}, errors.Wrap(internalerrors.ErrInvalidFormat, "invalid transaction type %q", params.TransactionType)
+	}
+
+	switch search.SortBy {
+	case "":
+		search.SortBy = TransactionSortDate
+	case TransactionSortDate, TransactionSortAmount:
+	default:
+		return searchTransactionsParams{}, errors.Wrap(internalerrors.ErrInvalidFormat, "invalid sort %q", params.SortBy)
+	}
+
+	switch strings.ToLower(params.SortOrder) {
+	case "", "desc":
+	case "asc":
+		search.Descending = false
+	default:
+		return searchTransactionsParams{}, errors.Wrap(internalerrors.ErrInvalidFormat, "invalid sort order %q", params.SortOrder)
+	}
+
+	if query := strings.TrimSpace(params.Query); query != "" {
+		pattern := "%" + escapeLikePattern(query) + "%"
+		search.Pattern = &pattern
+	}
+
+	return search, nil
+}
+
+func transactionSortValue(model *TransactionModel, sortBy string) string {
+	if sortBy == TransactionSortAmount {
+		return model.Amount.Abs().String()
+	}
+	return model.TransactionDate.Format("2006-01-02")
+}
+
+func validTransactionSortValue(value, sortBy string) bool {
+	if sortBy == TransactionSortAmount {
+		_, err := decimal.NewFromString(value)
+		return err == nil
+	}
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
+
+func encodeTransactionSearchCursor(cursor transactionSearchCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeTransactionSearchCursor(encoded string) (transactionSearchCursor, error) {
+	var cursor transactionSearchCursor
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return cursor, err
+	}
+	if cursor.Value == "" || cursor.TransactionID <= 0 {
+		return cursor, errors.New("incomplete cursor")
+	}
+	return cursor, nil
+}
+
+// escapeLikePattern makes user input match literally inside a LIKE pattern.
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func nilIfEmpty(ids []int) []int {
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
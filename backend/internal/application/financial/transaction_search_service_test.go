@@ -0,0 +1,105 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTransactions_PagesWithCursor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	october := time.Date(2025, time.October, 10, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("SearchTransactions", ctx, mock.MatchedBy(func(params searchTransactionsParams) bool {
+		return params.AfterValue == nil && params.Limit == 3 && params.Descending &&
+			params.SortBy == TransactionSortDate && *params.Pattern == `%50\% off%` &&
+			params.AccountIDs == nil
+	})).Return([]TransactionModel{
+		{TransactionID: 9, TransactionDate: october, Amount: decimal.NewFromInt(10)},
+		{TransactionID: 7, TransactionDate: october, Amount: decimal.NewFromInt(20)},
+		{TransactionID: 3, TransactionDate: october, Amount: decimal.NewFromInt(30)},
+	}, nil).Once()
+
+	first, err := svc.SearchTransactions(ctx, SearchTransactionsInput{
+		OrganizationID: 1,
+		AccountIDs:     []int{},
+		Query:          " 50% off ",
+		Limit:          2,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, first.Transactions, 2)
+	require.NotNil(t, first.NextCursor)
+
+	mockRepo.On("SearchTransactions", ctx, mock.MatchedBy(func(params searchTransactionsParams) bool {
+		return params.AfterValue != nil && *params.AfterValue == "2025-10-10" && *params.AfterID == 7
+	})).Return([]TransactionModel{
+		{TransactionID: 3, TransactionDate: october, Amount: decimal.NewFromInt(30)},
+	}, nil).Once()
+
+	second, err := svc.SearchTransactions(ctx, SearchTransactionsInput{
+		OrganizationID: 1,
+		Query:          " 50% off ",
+		Cursor:         *first.NextCursor,
+		Limit:          2,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, second.Transactions, 1)
+	assert.Nil(t, second.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchTransactions_RejectsCursorFromAnotherSort(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+
+	cursor := encodeTransactionSearchCursor(transactionSearchCursor{
+		SortBy:        TransactionSortDate,
+		Descending:    true,
+		Value:         "2025-10-10",
+		TransactionID: 7,
+	})
+
+	_, err := svc.SearchTransactions(ctx, SearchTransactionsInput{
+		OrganizationID: 1,
+		SortBy:         TransactionSortAmount,
+		Cursor:         cursor,
+	})
+
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidCursor)
+	mockRepo.AssertNotCalled(t, "SearchTransactions", mock.Anything, mock.Anything)
+}
+
+func TestSearchTransactions_ValidatesFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		input SearchTransactionsInput
+		want  error
+	}{
+		{name: "bad from date", input: SearchTransactionsInput{From: "10/10/2025"}, want: internalerrors.ErrInvalidFormat},
+		{name: "bad type", input: SearchTransactionsInput{TransactionType: "transfer"}, want: internalerrors.ErrInvalidFormat},
+		{name: "bad sort", input: SearchTransactionsInput{SortBy: "description"}, want: internalerrors.ErrInvalidFormat},
+		{name: "bad order", input: SearchTransactionsInput{SortOrder: "up"}, want: internalerrors.ErrInvalidFormat},
+		{name: "garbage cursor", input: SearchTransactionsInput{Cursor: "not-a-cursor"}, want: internalerrors.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &service{Repository: new(MockRepository)}
+
+			_, err := svc.SearchTransactions(context.Background(), tt.input)
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
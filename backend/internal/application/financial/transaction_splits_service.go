@@ -0,0 +1,193 @@
+package financial
+
+import (
+	"context"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetTransactionSplitsInput struct {
+	TransactionID  int
+	OrganizationID int
+}
+
+type TransactionSplitInput struct {
+	CategoryID    *int
+	SavingsGoalID *int
+	Amount        decimal.Decimal
+	Notes         *string
+	TagIDs        []int
+}
+
+// SetTransactionSplitsInput replaces every split of a transaction. An empty
+// Splits removes them, returning the transaction to its own category.
+type SetTransactionSplitsInput struct {
+	TransactionID  int
+	UserID         int
+	OrganizationID int
+	Splits         []TransactionSplitInput
+}
+
+// auditTransactionSplits is how split changes appear in a transaction's history
+type auditTransactionSplits struct {
+	Splits []TransactionSplit `json:"splits"`
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+func (s *service) GetTransactionSplits(ctx context.Context, input GetTransactionSplitsInput) ([]TransactionSplit, error) {
+	if _, err := s.Repository.FetchTransactionByID(ctx, fetchTransactionByIDParams{
+		TransactionID:  input.TransactionID,
+		OrganizationID: input.OrganizationID,
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transaction")
+	}
+
+	models, err := s.Repository.FetchTransactionSplits(ctx, fetchTransactionSplitsParams{
+		TransactionID:  input.TransactionID,
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transaction splits")
+	}
+
+	return TransactionSplits{}.FromModel(models), nil
+}
+
+// SetTransactionSplits divides a transaction across categories. There must be
+// at least two splits, each with a positive amount, adding up exactly to the
+// transaction amount.
+func (s *service) SetTransactionSplits(ctx context.Context, input SetTransactionSplitsInput) ([]TransactionSplit, error) {
+	tx, err := s.Repository.FetchTransactionByID(ctx, fetchTransactionByIDParams{
+		TransactionID:  input.TransactionID,
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transaction")
+	}
+	if err := s.ensureTransactionMonthOpen(ctx, transactionMonthClosureParams{
+		OrganizationID:  input.OrganizationID,
+		AccountID:       tx.AccountID,
+		TransactionDate: tx.TransactionDate,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := validateTransactionSplits(tx.Amount, input.Splits); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.Repository.FetchTransactionSplits(ctx, fetchTransactionSplitsParams{
+		TransactionID:  input.TransactionID,
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transaction splits")
+	}
+
+	checkedCategories := make(map[int]struct{})
+	checkedGoals := make(map[int]struct{})
+	splits := make([]transactionSplitParams, len(input.Splits))
+	for i, split := range input.Splits {
+		if split.CategoryID != nil {
+			if _, ok := checkedCategories[*split.CategoryID]; !ok {
+				if err := s.validateCategoryTransactionType(ctx, *split.CategoryID, tx.TransactionType, input.OrganizationID); err != nil {
+					return nil, err
+				}
+				checkedCategories[*split.CategoryID] = struct{}{}
+			}
+		}
+		if split.SavingsGoalID != nil {
+			if _, ok := checkedGoals[*split.SavingsGoalID]; !ok {
+				if _, err := s.Repository.FetchSavingsGoalByID(ctx, fetchSavingsGoalByIDParams{
+					SavingsGoalID:  *split.SavingsGoalID,
+					UserID:         input.UserID,
+					OrganizationID: input.OrganizationID,
+				}); err != nil {
+					return nil, errors.Wrap(err, "failed to fetch savings goal")
+				}
+				checkedGoals[*split.SavingsGoalID] = struct{}{}
+			}
+		}
+
+		splits[i] = transactionSplitParams{
+			CategoryID:    split.CategoryID,
+			SavingsGoalID: split.SavingsGoalID,
+			Amount:        split.Amount,
+			Notes:         split.Notes,
+			TagIDs:        uniqueIDs(split.TagIDs),
+		}
+	}
+
+	models, err := s.Repository.ReplaceTransactionSplits(ctx, replaceTransactionSplitsParams{
+		TransactionID: input.TransactionID,
+		Splits:        splits,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save transaction splits")
+	}
+
+	result := TransactionSplits{}.FromModel(models)
+	s.recordAudit(ctx, auditRecord{
+		OrganizationID: input.OrganizationID,
+		UserID:         input.UserID,
+		EntityType:     AuditEntityTransaction,
+		EntityID:       input.TransactionID,
+		Action:         AuditActionUpdate,
+		Before:         auditTransactionSplits{Splits: TransactionSplits{}.FromModel(previous)},
+		After:          auditTransactionSplits{Splits: result},
+	})
+
+	return result, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func validateTransactionSplits(amount decimal.Decimal, splits []TransactionSplitInput) error {
+	if len(splits) == 0 {
+		return nil
+	}
+	if len(splits) == 1 {
+		return errors.Wrap(internalerrors.ErrInvalidTransactionSplits, "a split transaction needs at least two splits")
+	}
+
+	total := decimal.Zero
+	for i, split := range splits {
+		if !split.Amount.IsPositive() {
+			return errors.Wrap(internalerrors.ErrInvalidTransactionSplits, "split %d: amount must be positive", i+1)
+		}
+		if !split.Amount.Equal(split.Amount.Round(2)) {
+			return errors.Wrap(internalerrors.ErrInvalidTransactionSplits, "split %d: amount has more than two decimal places", i+1)
+		}
+		total = total.Add(split.Amount)
+	}
+
+	if !total.Equal(amount.Abs()) {
+		return errors.Wrap(internalerrors.ErrInvalidTransactionSplits,
+			"splits add up to %s but the transaction amount is %s", total.StringFixed(2), amount.Abs().StringFixed(2))
+	}
+	return nil
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]struct{}, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique
+}
@@ -0,0 +1,148 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTransactionSplits_RequiresSplitsToAddUpToAmount(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+	groceries, household := 10, 11
+
+	mockRepo.On("FetchTransactionByID", ctx, mock.Anything).Return(TransactionModel{
+		TransactionID:   5,
+		Amount:          decimal.RequireFromString("150.00"),
+		TransactionDate: time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC),
+		TransactionType: TransactionTypeDebit,
+	}, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.Anything).Return(false, nil)
+
+	_, err := svc.SetTransactionSplits(ctx, SetTransactionSplitsInput{
+		TransactionID:  5,
+		OrganizationID: 1,
+		Splits: []TransactionSplitInput{
+			{CategoryID: &groceries, Amount: decimal.RequireFromString("100.00")},
+			{CategoryID: &household, Amount: decimal.RequireFromString("49.99")},
+		},
+	})
+
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidTransactionSplits)
+	mockRepo.AssertNotCalled(t, "ReplaceTransactionSplits", mock.Anything, mock.Anything)
+}
+
+func TestSetTransactionSplits_ReplacesSplits(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+	groceries, household := 10, 11
+
+	mockRepo.On("FetchTransactionByID", ctx, mock.Anything).Return(TransactionModel{
+		TransactionID:   5,
+		Amount:          decimal.RequireFromString("150.00"),
+		TransactionDate: time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC),
+		TransactionType: TransactionTypeDebit,
+	}, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.Anything).Return(false, nil)
+	mockRepo.On("FetchTransactionSplits", ctx, fetchTransactionSplitsParams{TransactionID: 5, OrganizationID: 1}).
+		Return([]TransactionSplitModel{}, nil)
+	mockRepo.On("FetchCategoryByID", ctx, mock.Anything).Return(CategoryModel{CategoryType: "expense"}, nil)
+	mockRepo.On("ReplaceTransactionSplits", ctx, replaceTransactionSplitsParams{
+		TransactionID: 5,
+		Splits: []transactionSplitParams{
+			{CategoryID: &groceries, Amount: decimal.RequireFromString("100.00"), TagIDs: []int{3}},
+			{CategoryID: &household, Amount: decimal.RequireFromString("50.00"), TagIDs: []int{}},
+		},
+	}).Return([]TransactionSplitModel{
+		{SplitID: 1, TransactionID: 5, CategoryID: &groceries, Amount: decimal.RequireFromString("100.00")},
+		{SplitID: 2, TransactionID: 5, CategoryID: &household, Amount: decimal.RequireFromString("50.00")},
+	}, nil)
+	mockRepo.On("InsertAuditLogEntry", ctx, mock.MatchedBy(func(params insertAuditLogEntryParams) bool {
+		return params.EntityType == AuditEntityTransaction && params.EntityID == 5 && params.Action == AuditActionUpdate
+	})).Return(nil).Once()
+
+	splits, err := svc.SetTransactionSplits(ctx, SetTransactionSplitsInput{
+		TransactionID:  5,
+		OrganizationID: 1,
+		Splits: []TransactionSplitInput{
+			{CategoryID: &groceries, Amount: decimal.RequireFromString("100.00"), TagIDs: []int{3, 3}},
+			{CategoryID: &household, Amount: decimal.RequireFromString("50.00")},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, splits, 2)
+	mockRepo.AssertNumberOfCalls(t, "FetchCategoryByID", 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTransaction_RefusesAmountChangeWhenSplit(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo}
+	ctx := context.Background()
+	newAmount := decimal.NewFromInt(200)
+
+	mockRepo.On("FetchTransactionByID", ctx, mock.Anything).Return(TransactionModel{
+		TransactionID:   5,
+		Amount:          decimal.NewFromInt(150),
+		TransactionDate: time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC),
+	}, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.Anything).Return(false, nil)
+	mockRepo.On("FetchTransactionSplits", ctx, fetchTransactionSplitsParams{TransactionID: 5, OrganizationID: 1}).
+		Return([]TransactionSplitModel{{SplitID: 1}, {SplitID: 2}}, nil)
+
+	_, err := svc.UpdateTransaction(ctx, UpdateTransactionInput{TransactionID: 5, OrganizationID: 1, Amount: &newAmount})
+
+	assert.ErrorIs(t, err, internalerrors.ErrInvalidTransactionSplits)
+	mockRepo.AssertNotCalled(t, "ModifyTransaction", mock.Anything, mock.Anything)
+}
+
+func TestGetControllableCategoryPacing_CountsSplitAmounts(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := &service{Repository: mockRepo, system: system.NewSystem()}
+	ctx := context.Background()
+
+	groceries, household := 10, 11
+	categories := []CategoryModel{
+		{CategoryID: groceries, Name: "Mercado", CategoryType: "expense"},
+		{CategoryID: household, Name: "Casa", CategoryType: "expense"},
+	}
+	budgets := []CategoryBudgetModel{
+		{CategoryID: groceries, ControlledAmount: decimal.NewFromInt(1000)},
+		{CategoryID: household, ControlledAmount: decimal.NewFromInt(1000)},
+	}
+	transactions := []TransactionModel{
+		{TransactionID: 5, CategoryID: &groceries, TransactionType: TransactionTypeDebit, Amount: decimal.NewFromInt(150)},
+	}
+	mockRepo.On("FetchCategories", mock.Anything, mock.Anything).Return(categories, nil)
+	mockRepo.On("FetchCategoryBudgets", mock.Anything, mock.Anything).Return(budgets, nil)
+	mockRepo.On("FetchTransactionsByMonth", mock.Anything, mock.Anything).Return(transactions, nil)
+	mockRepo.On("FetchMatchedTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransferTransactionIDs", mock.Anything, mock.Anything).Return([]int{}, nil)
+	mockRepo.On("FetchTransactionSplitsByMonth", mock.Anything, mock.Anything).Return([]TransactionSplitModel{
+		{SplitID: 1, TransactionID: 5, CategoryID: &groceries, Amount: decimal.NewFromInt(100)},
+		{SplitID: 2, TransactionID: 5, CategoryID: &household, Amount: decimal.NewFromInt(50)},
+	}, nil)
+	mockRepo.On("FetchIncomeBudgetForMonth", mock.Anything, mock.Anything).Return(decimal.Zero, nil)
+
+	result, err := svc.GetControllableCategoryPacing(ctx, GetControllableCategoryPacingInput{
+		UserID: 1, OrganizationID: 1, Month: 6, Year: 2026,
+	})
+
+	require.NoError(t, err)
+	spent := map[int]decimal.Decimal{}
+	for _, category := range result.Categories {
+		spent[category.CategoryID] = category.Spent
+	}
+	assert.Equal(t, "100", spent[groceries].String())
+	assert.Equal(t, "50", spent[household].String())
+}
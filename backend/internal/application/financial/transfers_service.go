@@ -0,0 +1,393 @@
+package financial
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/errors"
+)
+
+// transferDateWindowDays is how far apart the two legs of a transfer may be
+// posted. Card bill payments in particular tend to post a day or two apart.
+const transferDateWindowDays = 3
+
+// transferHints are description fragments (upper case, without accents) that
+// banks put on transfers between accounts.
+var transferHints = []string{
+	"PAGAMENTO FATURA",
+	"PAGTO FATURA",
+	"PAG FATURA",
+	"PAGAMENTO RECEBIDO",
+	"PAGAMENTO EFETUADO",
+	"PIX ENVIADO",
+	"PIX RECEBIDO",
+	"PIX TRANSF",
+	"TRANSFERENCIA",
+	"TRANSF ",
+	"TED ",
+	"DOC ",
+	"APLICACAO",
+	"RESGATE",
+}
+
+var transferHintReplacer = strings.NewReplacer(
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A",
+	"É", "E", "Ê", "E",
+	"Í", "I",
+	"Ó", "O", "Ô", "O", "Õ", "O",
+	"Ú", "U",
+	"Ç", "C",
+)
+
+// ============================================================================
+// Input/Output Structures
+// ============================================================================
+
+type GetTransfersInput struct {
+	OrganizationID int
+}
+
+// DetectTransfersInput bounds the dates searched for transfer legs. Both
+// default to the start of the previous month through today.
+type DetectTransfersInput struct {
+	OrganizationID int
+	From           string // YYYY-MM-DD
+	To             string // YYYY-MM-DD
+}
+
+// TransferSuggestion is a pair that looks like a transfer but was not linked
+// automatically, because neither leg says so or another pair fits as well.
+type TransferSuggestion struct {
+	Debit     Transaction `json:"debit"`
+	Credit    Transaction `json:"credit"`
+	DaysApart int         `json:"days_apart"`
+}
+
+type DetectTransfersOutput struct {
+	Linked      []Transfer           `json:"linked"`
+	Suggestions []TransferSuggestion `json:"suggestions"`
+}
+
+type LinkTransferInput struct {
+	OrganizationID      int
+	DebitTransactionID  int
+	CreditTransactionID int
+}
+
+type UnlinkTransferInput struct {
+	TransferID     int
+	OrganizationID int
+}
+
+// transferPair is a debit and a credit matched by detectTransferPairs
+type transferPair struct {
+	debit     TransactionModel
+	credit    TransactionModel
+	daysApart int
+	confident bool
+}
+
+// ============================================================================
+// Service Methods
+// ============================================================================
+
+func (s *service) GetTransfers(ctx context.Context, input GetTransfersInput) ([]Transfer, error) {
+	models, err := s.Repository.FetchTransfers(ctx, fetchTransfersParams{
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transfers")
+	}
+
+	return Transfers{}.FromModel(models), nil
+}
+
+// DetectTransfers pairs debits with credits of the same amount in another of
+// the organization's accounts within a few days. Pairs where either leg reads
+// like a transfer are linked; the rest are returned as suggestions. Legs in
+// closed months are left alone.
+func (s *service) DetectTransfers(ctx context.Context, input DetectTransfersInput) (DetectTransfersOutput, error) {
+	now := s.system.Time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if input.From != "" {
+		parsed, err := parseTransactionDate(input.From)
+		if err != nil {
+			return DetectTransfersOutput{}, internalerrors.NewInvalidTimeFormatError("from")
+		}
+		from = parsed
+	}
+	if input.To != "" {
+		parsed, err := parseTransactionDate(input.To)
+		if err != nil {
+			return DetectTransfersOutput{}, internalerrors.NewInvalidTimeFormatError("to")
+		}
+		to = parsed
+	}
+
+	candidates, err := s.Repository.FetchTransferCandidates(ctx, fetchTransferCandidatesParams{
+		OrganizationID: input.OrganizationID,
+		From:           from,
+		To:             to,
+	})
+	if err != nil {
+		return DetectTransfersOutput{}, errors.Wrap(err, "failed to fetch transfer candidates")
+	}
+
+	closedDays := make(map[transactionMonthClosureParams]bool)
+	isClosed := func(tx *TransactionModel) (bool, error) {
+		key := transactionMonthClosureParams{
+			OrganizationID:  input.OrganizationID,
+			AccountID:       tx.AccountID,
+			TransactionDate: truncateToDay(tx.TransactionDate),
+		}
+		if closed, ok := closedDays[key]; ok {
+			return closed, nil
+		}
+		closed, err := s.Repository.IsTransactionMonthClosed(ctx, key)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to check month closure")
+		}
+		closedDays[key] = closed
+		return closed, nil
+	}
+
+	output := DetectTransfersOutput{
+		Linked:      []Transfer{},
+		Suggestions: []TransferSuggestion{},
+	}
+	for _, pair := range detectTransferPairs(candidates) {
+		if !pair.confident {
+			output.Suggestions = append(output.Suggestions, TransferSuggestion{
+				Debit:     Transaction{}.FromModel(&pair.debit),
+				Credit:    Transaction{}.FromModel(&pair.credit),
+				DaysApart: pair.daysApart,
+			})
+			continue
+		}
+
+		debitClosed, err := isClosed(&pair.debit)
+		if err != nil {
+			return DetectTransfersOutput{}, err
+		}
+		creditClosed, err := isClosed(&pair.credit)
+		if err != nil {
+			return DetectTransfersOutput{}, err
+		}
+		if debitClosed || creditClosed {
+			continue
+		}
+
+		model, err := s.Repository.InsertTransfer(ctx, insertTransferParams{
+			OrganizationID:      input.OrganizationID,
+			DebitTransactionID:  pair.debit.TransactionID,
+			CreditTransactionID: pair.credit.TransactionID,
+			Detection:           TransferDetectionAuto,
+		})
+		if err != nil {
+			return DetectTransfersOutput{}, errors.Wrap(err, "failed to link transfer")
+		}
+		output.Linked = append(output.Linked, Transfer{}.FromModel(&model))
+	}
+
+	if len(output.Linked) > 0 {
+		s.logger.Info(ctx, "Transfers detected",
+			"organization_id", input.OrganizationID,
+			"linked", len(output.Linked),
+			"suggested", len(output.Suggestions),
+		)
+	}
+
+	return output, nil
+}
+
+// LinkTransfer links two transactions as a transfer by hand. The amounts may
+// differ (fees, exchange), but the legs must be a debit and a credit in
+// different accounts, neither already part of a transfer.
+func (s *service) LinkTransfer(ctx context.Context, input LinkTransferInput) (Transfer, error) {
+	debit, err := s.Repository.FetchTransactionByID(ctx, fetchTransactionByIDParams{
+		TransactionID:  input.DebitTransactionID,
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return Transfer{}, errors.Wrap(err, "failed to fetch debit transaction")
+	}
+	credit, err := s.Repository.FetchTransactionByID(ctx, fetchTransactionByIDParams{
+		TransactionID:  input.CreditTransactionID,
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return Transfer{}, errors.Wrap(err, "failed to fetch credit transaction")
+	}
+
+	if debit.TransactionType != TransactionTypeDebit || credit.TransactionType != TransactionTypeCredit {
+		return Transfer{}, errors.Wrap(internalerrors.ErrInvalidTransfer, "a transfer links a debit to a credit")
+	}
+	if debit.AccountID == credit.AccountID {
+		return Transfer{}, errors.Wrap(internalerrors.ErrInvalidTransfer, "both legs are in the same account")
+	}
+
+	linkedIDs, err := s.Repository.FetchTransferTransactionIDs(ctx, fetchTransferTransactionIDsParams{
+		OrganizationID: input.OrganizationID,
+	})
+	if err != nil {
+		return Transfer{}, errors.Wrap(err, "failed to fetch linked transactions")
+	}
+	for _, id := range linkedIDs {
+		if id == debit.TransactionID || id == credit.TransactionID {
+			return Transfer{}, errors.Wrap(internalerrors.ErrInvalidTransfer, "transaction %d is already part of a transfer", id)
+		}
+	}
+
+	for _, leg := range []TransactionModel{debit, credit} {
+		if err := s.ensureTransactionMonthOpen(ctx, transactionMonthClosureParams{
+			OrganizationID:  input.OrganizationID,
+			AccountID:       leg.AccountID,
+			TransactionDate: leg.TransactionDate,
+		}); err != nil {
+			return Transfer{}, err
+		}
+	}
+
+	model, err := s.Repository.InsertTransfer(ctx, insertTransferParams{
+		OrganizationID:      input.OrganizationID,
+		DebitTransactionID:  debit.TransactionID,
+		CreditTransactionID: credit.TransactionID,
+		Detection:           TransferDetectionManual,
+	})
+	if err != nil {
+		return Transfer{}, errors.Wrap(err, "failed to link transfer")
+	}
+
+	return Transfer{}.FromModel(&model), nil
+}
+
+// UnlinkTransfer turns both legs back into ordinary income and spending.
+func (s *service) UnlinkTransfer(ctx context.Context, input UnlinkTransferInput) error {
+	if err := s.Repository.RemoveTransfer(ctx, removeTransferParams{
+		TransferID:     input.TransferID,
+		OrganizationID: input.OrganizationID,
+	}); err != nil {
+		return errors.Wrap(err, "failed to unlink transfer")
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// detectTransferPairs matches each debit, oldest first, with the best unused
+// credit of the same amount in another account within the date window: one
+// with a transfer hint first, then the closest in date. A pair is confident
+// when a leg carries a hint and no other credit fits equally well.
+func detectTransferPairs(transactions []TransactionModel) []transferPair {
+	var debits, credits []TransactionModel
+	for _, tx := range transactions {
+		switch tx.TransactionType {
+		case TransactionTypeDebit:
+			debits = append(debits, tx)
+		case TransactionTypeCredit:
+			credits = append(credits, tx)
+		}
+	}
+	sort.SliceStable(debits, func(i, j int) bool {
+		return debits[i].TransactionDate.Before(debits[j].TransactionDate)
+	})
+
+	type candidate struct {
+		index     int
+		hint      bool
+		daysApart int
+	}
+
+	used := make(map[int]bool, len(credits))
+	var pairs []transferPair
+	for _, debit := range debits {
+		debitHint := hasTransferHint(&debit)
+
+		var matches []candidate
+		for i, credit := range credits {
+			if used[i] || credit.AccountID == debit.AccountID || !credit.Amount.Abs().Equal(debit.Amount.Abs()) {
+				continue
+			}
+			days := daysBetween(debit.TransactionDate, credit.TransactionDate)
+			if days > transferDateWindowDays {
+				continue
+			}
+			matches = append(matches, candidate{
+				index:     i,
+				hint:      debitHint || hasTransferHint(&credit),
+				daysApart: days,
+			})
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].hint != matches[j].hint {
+				return matches[i].hint
+			}
+			return matches[i].daysApart < matches[j].daysApart
+		})
+		best := matches[0]
+		ambiguous := len(matches) > 1 &&
+			matches[1].hint == best.hint && matches[1].daysApart == best.daysApart
+
+		used[best.index] = true
+		pairs = append(pairs, transferPair{
+			debit:     debit,
+			credit:    credits[best.index],
+			daysApart: best.daysApart,
+			confident: best.hint && !ambiguous,
+		})
+	}
+
+	return pairs
+}
+
+func hasTransferHint(tx *TransactionModel) bool {
+	descriptions := []string{tx.Description}
+	if tx.OriginalDescription != nil {
+		descriptions = append(descriptions, *tx.OriginalDescription)
+	}
+	for _, description := range descriptions {
+		normalized := transferHintReplacer.Replace(strings.ToUpper(description)) + " "
+		for _, hint := range transferHints {
+			if strings.Contains(normalized, hint) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func daysBetween(a, b time.Time) int {
+	days := int(a.Sub(b).Hours() / 24)
+	if days < 0 {
+		return -days
+	}
+	return days
+}
+
+// transferTransactionSet returns the IDs of every transfer leg in the
+// organization, which are neither income nor spending.
+func (s *service) transferTransactionSet(ctx context.Context, organizationID int) (map[int]struct{}, error) {
+	ids, err := s.Repository.FetchTransferTransactionIDs(ctx, fetchTransferTransactionIDsParams{
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch transfer transactions")
+	}
+
+	set := make(map[int]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set, nil
+}
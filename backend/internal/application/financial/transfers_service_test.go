@@ -0,0 +1,151 @@
+package financial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalerrors "github.com/catrutech/celeiro/internal/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTransferPairs(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC) }
+	tx := func(id, accountID int, txType, description string, amount int64, date time.Time) TransactionModel {
+		return TransactionModel{
+			TransactionID:   id,
+			AccountID:       accountID,
+			TransactionType: txType,
+			Description:     description,
+			Amount:          decimal.NewFromInt(amount),
+			TransactionDate: date,
+		}
+	}
+
+	pairs := detectTransferPairs([]TransactionModel{
+		// Card bill paid from checking, posted on the card two days later
+		tx(1, 10, TransactionTypeDebit, "PAGAMENTO FATURA NUBANK", 1200, day(5)),
+		tx(2, 20, TransactionTypeCredit, "Pagamento recebido", 1200, day(7)),
+		// Same amount but the same account: never a transfer
+		tx(3, 10, TransactionTypeCredit, "Estorno", 1200, day(5)),
+		// Matching amounts without any hint: only a suggestion
+		tx(4, 10, TransactionTypeDebit, "Mercado", 80, day(9)),
+		tx(5, 30, TransactionTypeCredit, "Reembolso", 80, day(10)),
+		// Too far apart
+		tx(6, 10, TransactionTypeDebit, "Pix enviado", 300, day(1)),
+		tx(7, 30, TransactionTypeCredit, "Pix recebido", 300, day(20)),
+	})
+
+	require.Len(t, pairs, 2)
+	assert.Equal(t, 1, pairs[0].debit.TransactionID)
+	assert.Equal(t, 2, pairs[0].credit.TransactionID)
+	assert.Equal(t, 2, pairs[0].daysApart)
+	assert.True(t, pairs[0].confident)
+	assert.Equal(t, 4, pairs[1].debit.TransactionID)
+	assert.Equal(t, 5, pairs[1].credit.TransactionID)
+	assert.False(t, pairs[1].confident)
+}
+
+func TestHasTransferHint_IgnoresCaseAndAccents(t *testing.T) {
+	original := "TRANSFERÊNCIA ENVIADA"
+
+	assert.True(t, hasTransferHint(&TransactionModel{Description: "Transferência para poupança"}))
+	assert.True(t, hasTransferHint(&TransactionModel{Description: "Conta", OriginalDescription: &original}))
+	assert.True(t, hasTransferHint(&TransactionModel{Description: "TED"}))
+	assert.False(t, hasTransferHint(&TransactionModel{Description: "Padaria Tedesco"}))
+}
+
+func TestDetectTransfers_SkipsClosedMonths(t *testing.T) {
+	mockRepo := new(MockRepository)
+	stub := system.NewStubSystem()
+	stub.Time.SetTimes(time.Date(2026, time.March, 15, 9, 0, 0, 0, time.UTC))
+	svc := &service{Repository: mockRepo, system: stub.ToSystem(), logger: &logging.TestLogger{}}
+	ctx := context.Background()
+
+	mockRepo.On("FetchTransferCandidates", ctx, fetchTransferCandidatesParams{
+		OrganizationID: 1,
+		From:           time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC),
+	}).Return([]TransactionModel{
+		{TransactionID: 1, AccountID: 10, TransactionType: TransactionTypeDebit, Description: "PIX ENVIADO",
+			Amount: decimal.NewFromInt(500), TransactionDate: time.Date(2026, time.February, 27, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: 2, AccountID: 20, TransactionType: TransactionTypeCredit, Description: "PIX RECEBIDO",
+			Amount: decimal.NewFromInt(500), TransactionDate: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: 3, AccountID: 10, TransactionType: TransactionTypeDebit, Description: "PIX ENVIADO",
+			Amount: decimal.NewFromInt(90), TransactionDate: time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: 4, AccountID: 20, TransactionType: TransactionTypeCredit, Description: "PIX RECEBIDO",
+			Amount: decimal.NewFromInt(90), TransactionDate: time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.MatchedBy(func(params transactionMonthClosureParams) bool {
+		return params.TransactionDate.Month() == time.February
+	})).Return(true, nil)
+	mockRepo.On("IsTransactionMonthClosed", ctx, mock.MatchedBy(func(params transactionMonthClosureParams) bool {
+		return params.TransactionDate.Month() == time.March
+	})).Return(false, nil)
+	mockRepo.On("InsertTransfer", ctx, insertTransferParams{
+		OrganizationID:      1,
+		DebitTransactionID:  3,
+		CreditTransactionID: 4,
+		Detection:           TransferDetectionAuto,
+	}).Return(TransferModel{TransferID: 7, DebitTransactionID: 3, CreditTransactionID: 4, Detection: TransferDetectionAuto}, nil)
+
+	result, err := svc.DetectTransfers(ctx, DetectTransfersInput{OrganizationID: 1})
+
+	require.NoError(t, err)
+	require.Len(t, result.Linked, 1)
+	assert.Equal(t, 7, result.Linked[0].TransferID)
+	assert.Empty(t, result.Suggestions)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLinkTransfer_RejectsInvalidLegs(t *testing.T) {
+	march := time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		debit     TransactionModel
+		credit    TransactionModel
+		linkedIDs []int
+	}{
+		{
+			name:   "two debits",
+			debit:  TransactionModel{TransactionID: 1, AccountID: 10, TransactionType: TransactionTypeDebit},
+			credit: TransactionModel{TransactionID: 2, AccountID: 20, TransactionType: TransactionTypeDebit},
+		},
+		{
+			name:   "same account",
+			debit:  TransactionModel{TransactionID: 1, AccountID: 10, TransactionType: TransactionTypeDebit},
+			credit: TransactionModel{TransactionID: 2, AccountID: 10, TransactionType: TransactionTypeCredit},
+		},
+		{
+			name:      "already linked",
+			debit:     TransactionModel{TransactionID: 1, AccountID: 10, TransactionType: TransactionTypeDebit},
+			credit:    TransactionModel{TransactionID: 2, AccountID: 20, TransactionType: TransactionTypeCredit},
+			linkedIDs: []int{2, 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			svc := &service{Repository: mockRepo}
+			ctx := context.Background()
+			tt.debit.TransactionDate = march
+			tt.credit.TransactionDate = march
+
+			mockRepo.On("FetchTransactionByID", ctx, fetchTransactionByIDParams{TransactionID: 1, OrganizationID: 1}).Return(tt.debit, nil)
+			mockRepo.On("FetchTransactionByID", ctx, fetchTransactionByIDParams{TransactionID: 2, OrganizationID: 1}).Return(tt.credit, nil)
+			mockRepo.On("FetchTransferTransactionIDs", ctx, mock.Anything).Return(tt.linkedIDs, nil)
+
+			_, err := svc.LinkTransfer(ctx, LinkTransferInput{OrganizationID: 1, DebitTransactionID: 1, CreditTransactionID: 2})
+
+			assert.ErrorIs(t, err, internalerrors.ErrInvalidTransfer)
+			mockRepo.AssertNotCalled(t, "InsertTransfer", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package financial
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// xlsxWriter streams a single-sheet workbook: rows are written straight into
+// the zipped sheet as they come, so the sheet is never held in memory. Text is
+// written as inline strings rather than through a shared strings table for the
+// same reason.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+}
+
+type xlsxCell struct {
+	kind  byte // s (inline string), n (number) or 0 (empty)
+	value string
+	style int // Index into cellXfs of xlsxStyles
+}
+
+// Cell styles declared in xlsxStyles
+const (
+	xlsxStyleDefault = iota
+	xlsxStyleDate
+	xlsxStyleAmount
+	xlsxStyleHeader
+)
+
+// Spreadsheet dates count days from 1899-12-30
+var xlsxEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+func newXLSXWriter(w io.Writer, sheetName string) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbookStart + xlsxEscape(sheetName) + xlsxWorkbookEnd},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(file)
+	if _, err := sheet.WriteString(xlsxSheetStart); err != nil {
+		return nil, err
+	}
+
+	return &xlsxWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (x *xlsxWriter) WriteRow(cells ...xlsxCell) error {
+	x.rows++
+	row := strconv.Itoa(x.rows)
+
+	x.sheet.WriteString(`<row r="` + row + `">`)
+	for i, cell := range cells {
+		if cell.kind == 0 {
+			continue
+		}
+		x.sheet.WriteString(`<c r="` + xlsxColumn(i) + row + `"`)
+		if cell.style != xlsxStyleDefault {
+			x.sheet.WriteString(` s="` + strconv.Itoa(cell.style) + `"`)
+		}
+		if cell.kind == 's' {
+			x.sheet.WriteString(` t="inlineStr"><is><t xml:space="preserve">` + xlsxEscape(cell.value) + `</t></is></c>`)
+		} else {
+			x.sheet.WriteString(`><v>` + cell.value + `</v></c>`)
+		}
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Close finishes the sheet and the archive. The underlying writer is left open.
+func (x *xlsxWriter) Close() error {
+	if _, err := x.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.archive.Close()
+}
+
+func xlsxString(value string) xlsxCell {
+	if value == "" {
+		return xlsxCell{}
+	}
+	return xlsxCell{kind: 's', value: value}
+}
+
+func xlsxHeader(value string) xlsxCell {
+	return xlsxCell{kind: 's', value: value, style: xlsxStyleHeader}
+}
+
+func xlsxAmount(value decimal.Decimal) xlsxCell {
+	return xlsxCell{kind: 'n', value: value.String(), style: xlsxStyleAmount}
+}
+
+// xlsxDate writes the calendar day of t as a date serial.
+func xlsxDate(t time.Time) xlsxCell {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	serial := int(day.Sub(xlsxEpoch).Hours() / 24)
+	return xlsxCell{kind: 'n', value: strconv.Itoa(serial), style: xlsxStyleDate}
+}
+
+// xlsxColumn converts a zero-based column index to its letters: 0 is A, 26 is AA.
+func xlsxColumn(index int) string {
+	var letters []byte
+	for index >= 0 {
+		letters = append([]byte{byte('A' + index%26)}, letters...)
+		index = index/26 - 1
+	}
+	return string(letters)
+}
+
+// xlsxEscape escapes text for XML. Characters XML cannot carry are replaced.
+func xlsxEscape(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="`
+
+const xlsxWorkbookEnd = `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// Built-in number formats: 14 is the locale's short date, 4 is #,##0.00
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs></styleSheet>`
+
+// The header row stays frozen while scrolling
+const xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+	`<sheetData>`
+
+const xlsxSheetEnd = `</sheetData></worksheet>`
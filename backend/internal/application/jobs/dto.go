@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job DTO
+type Job struct {
+	JobID          int        `json:"job_id"`
+	JobType        string     `json:"job_type"`
+	OrganizationID *int       `json:"organization_id,omitempty"`
+	Payload        string     `json:"payload"`
+	UniqueKey      *string    `json:"unique_key,omitempty"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	MaxAttempts    int        `json:"max_attempts"`
+	RunAt          time.Time  `json:"run_at"`
+	LockedBy       *string    `json:"locked_by,omitempty"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (j Job) FromModel(model *JobModel) Job {
+	return Job{
+		JobID:          model.JobID,
+		JobType:        model.JobType,
+		OrganizationID: model.OrganizationID,
+		Payload:        model.Payload,
+		UniqueKey:      model.UniqueKey,
+		Status:         model.Status,
+		Attempts:       model.Attempts,
+		MaxAttempts:    model.MaxAttempts,
+		RunAt:          model.RunAt,
+		LockedBy:       model.LockedBy,
+		LockedUntil:    model.LockedUntil,
+		LastError:      model.LastError,
+		FinishedAt:     model.FinishedAt,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
+
+// DecodePayload unmarshals the job payload into dest.
+func (j Job) DecodePayload(dest any) error {
+	return json.Unmarshal([]byte(j.Payload), dest)
+}
+
+type Jobs []Job
+
+func (j Jobs) FromModel(models []JobModel) Jobs {
+	jobs := make(Jobs, len(models))
+	for i, model := range models {
+		jobs[i] = Job{}.FromModel(&model)
+	}
+	return jobs
+}
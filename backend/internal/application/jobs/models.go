@@ -0,0 +1,35 @@
+package jobs
+
+import "time"
+
+// JobStatus constants
+const (
+	JobStatusPending   = "pending"   // Waiting for run_at
+	JobStatusRunning   = "running"   // Claimed by a worker, lease in locked_until
+	JobStatusSucceeded = "succeeded" // Finished successfully
+	JobStatusFailed    = "failed"    // Exhausted max_attempts; only retried manually
+)
+
+// JobModel represents a queued background job
+type JobModel struct {
+	JobID     int       `db:"job_id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+
+	JobType        string  `db:"job_type"`
+	OrganizationID *int    `db:"organization_id"`
+	Payload        string  `db:"payload"` // JSONB stored as string
+	UniqueKey      *string `db:"unique_key"`
+
+	Status      string    `db:"status"`
+	Attempts    int       `db:"attempts"`
+	MaxAttempts int       `db:"max_attempts"`
+	RunAt       time.Time `db:"run_at"`
+
+	LockedBy    *string    `db:"locked_by"`
+	LockedUntil *time.Time `db:"locked_until"`
+	LastError   *string    `db:"last_error"`
+	FinishedAt  *time.Time `db:"finished_at"`
+}
+
+type JobsModel []JobModel
@@ -0,0 +1,261 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	database "github.com/catrutech/celeiro/pkg/database/persistent"
+)
+
+type Repository interface {
+	FetchJobs(ctx context.Context, params fetchJobsParams) ([]JobModel, error)
+	FetchJobByID(ctx context.Context, params fetchJobByIDParams) (JobModel, error)
+	InsertJob(ctx context.Context, params insertJobParams) ([]JobModel, error)
+	ClaimNextJob(ctx context.Context, params claimNextJobParams) ([]JobModel, error)
+	ClaimJobByID(ctx context.Context, params claimJobByIDParams) ([]JobModel, error)
+	ModifyJobSucceeded(ctx context.Context, params modifyJobSucceededParams) error
+	ModifyJobFailed(ctx context.Context, params modifyJobFailedParams) (JobModel, error)
+	ModifyJobRetry(ctx context.Context, params modifyJobRetryParams) (JobModel, error)
+	DeleteFinishedJobs(ctx context.Context, params deleteFinishedJobsParams) (int, error)
+}
+
+type repository struct {
+	db database.Database
+}
+
+func NewRepository(db database.Database) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+type fetchJobsParams struct {
+	Status  *string
+	JobType *string
+	Limit   int
+}
+
+const fetchJobsQuery = `
+	-- jobs.fetchJobsQuery
+	SELECT
+		job_id, created_at, updated_at, job_type, organization_id, payload, unique_key,
+		status, attempts, max_attempts, run_at, locked_by, locked_until, last_error, finished_at
+	FROM jobs
+	WHERE ($1::text IS NULL OR status = $1)
+		AND ($2::text IS NULL OR job_type = $2)
+	ORDER BY job_id DESC
+	LIMIT $3;
+`
+
+func (r *repository) FetchJobs(ctx context.Context, params fetchJobsParams) ([]JobModel, error) {
+	var jobs []JobModel
+	err := r.db.Query(ctx, &jobs, fetchJobsQuery, params.Status, params.JobType, params.Limit)
+	return jobs, err
+}
+
+type fetchJobByIDParams struct {
+	JobID int
+}
+
+const fetchJobByIDQuery = `
+	-- jobs.fetchJobByIDQuery
+	SELECT
+		job_id, created_at, updated_at, job_type, organization_id, payload, unique_key,
+		status, attempts, max_attempts, run_at, locked_by, locked_until, last_error, finished_at
+	FROM jobs
+	WHERE job_id = $1;
+`
+
+func (r *repository) FetchJobByID(ctx context.Context, params fetchJobByIDParams) (JobModel, error) {
+	var job JobModel
+	err := r.db.Query(ctx, &job, fetchJobByIDQuery, params.JobID)
+	return job, err
+}
+
+type insertJobParams struct {
+	JobType        string
+	OrganizationID *int
+	Payload        string
+	UniqueKey      *string
+	MaxAttempts    int
+	RunAt          time.Time
+}
+
+// A job whose unique_key already exists is not inserted; the returned slice is
+// then empty.
+const insertJobQuery = `
+	-- jobs.insertJobQuery
+	INSERT INTO jobs (job_type, organization_id, payload, unique_key, max_attempts, run_at)
+	VALUES ($1, $2, $3::jsonb, $4, $5, $6)
+	ON CONFLICT (unique_key) WHERE unique_key IS NOT NULL DO NOTHING
+	RETURNING job_id, created_at, updated_at, job_type, organization_id, payload, unique_key,
+			  status, attempts, max_attempts, run_at, locked_by, locked_until, last_error, finished_at;
+`
+
+func (r *repository) InsertJob(ctx context.Context, params insertJobParams) ([]JobModel, error) {
+	var jobs []JobModel
+	err := r.db.Query(ctx, &jobs, insertJobQuery,
+		params.JobType, params.OrganizationID, params.Payload, params.UniqueKey, params.MaxAttempts, params.RunAt)
+	return jobs, err
+}
+
+type claimNextJobParams struct {
+	WorkerID string
+	Lease    time.Duration
+}
+
+// claimNextJobQuery takes the oldest due job, or a running job whose lease
+// expired, and leases it to the worker. SKIP LOCKED lets several workers poll
+// the same table without blocking on each other.
+const claimNextJobQuery = `
+	-- jobs.claimNextJobQuery
+	UPDATE jobs
+	SET status = 'running',
+		attempts = attempts + 1,
+		locked_by = $1,
+		locked_until = NOW() + ($2 * INTERVAL '1 second'),
+		updated_at = NOW()
+	WHERE job_id = (
+		SELECT job_id
+		FROM jobs
+		WHERE (status = 'pending' AND run_at <= NOW())
+			OR (status = 'running' AND locked_until < NOW())
+		ORDER BY run_at ASC, job_id ASC
+		FOR UPDATE SKIP LOCKED
+		LIMIT 1
+	)
+	RETURNING job_id, created_at, updated_at, job_type, organization_id, payload, unique_key,
+			  status, attempts, max_attempts, run_at, locked_by, locked_until, last_error, finished_at;
+`
+
+func (r *repository) ClaimNextJob(ctx context.Context, params claimNextJobParams) ([]JobModel, error) {
+	var jobs []JobModel
+	err := r.db.Query(ctx, &jobs, claimNextJobQuery, params.WorkerID, params.Lease.Seconds())
+	return jobs, err
+}
+
+type claimJobByIDParams struct {
+	JobID    int
+	WorkerID string
+	Lease    time.Duration
+}
+
+// claimJobByIDQuery leases a specific job regardless of run_at, as long as no
+// other worker holds a live lease on it. Used to run a job on demand.
+const claimJobByIDQuery = `
+	-- jobs.claimJobByIDQuery
+	UPDATE jobs
+	SET status = 'running',
+		attempts = attempts + 1,
+		locked_by = $2,
+		locked_until = NOW() + ($3 * INTERVAL '1 second'),
+		updated_at = NOW()
+	WHERE job_id = $1
+		AND (status IN ('pending', 'failed') OR (status = 'running' AND locked_until < NOW()))
+	RETURNING job_id, created_at, updated_at, job_type, organization_id, payload, unique_key,
+			  status, attempts, max_attempts, run_at, locked_by, locked_until, last_error, finished_at;
+`
+
+func (r *repository) ClaimJobByID(ctx context.Context, params claimJobByIDParams) ([]JobModel, error) {
+	var jobs []JobModel
+	err := r.db.Query(ctx, &jobs, claimJobByIDQuery, params.JobID, params.WorkerID, params.Lease.Seconds())
+	return jobs, err
+}
+
+type modifyJobSucceededParams struct {
+	JobID    int
+	WorkerID string
+}
+
+const modifyJobSucceededQuery = `
+	-- jobs.modifyJobSucceededQuery
+	UPDATE jobs
+	SET status = 'succeeded',
+		locked_by = NULL,
+		locked_until = NULL,
+		last_error = NULL,
+		finished_at = NOW(),
+		updated_at = NOW()
+	WHERE job_id = $1 AND locked_by = $2;
+`
+
+func (r *repository) ModifyJobSucceeded(ctx context.Context, params modifyJobSucceededParams) error {
+	return r.db.Run(ctx, modifyJobSucceededQuery, params.JobID, params.WorkerID)
+}
+
+type modifyJobFailedParams struct {
+	JobID      int
+	WorkerID   string
+	LastError  string
+	RetryDelay time.Duration
+}
+
+// A failed attempt goes back to pending with a delayed run_at until attempts
+// reaches max_attempts; then the job is parked as failed.
+const modifyJobFailedQuery = `
+	-- jobs.modifyJobFailedQuery
+	UPDATE jobs
+	SET status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'pending' END,
+		run_at = CASE WHEN attempts >= max_attempts THEN run_at ELSE NOW() + ($4 * INTERVAL '1 second') END,
+		finished_at = CASE WHEN attempts >= max_attempts THEN NOW() ELSE NULL END,
+		locked_by = NULL,
+		locked_until = NULL,
+		last_error = $3,
+		updated_at = NOW()
+	WHERE job_id = $1 AND locked_by = $2
+	RETURNING job_id, created_at, updated_at, job_type, organization_id, payload, unique_key,
+			  status, attempts, max_attempts, run_at, locked_by, locked_until, last_error, finished_at;
+`
+
+func (r *repository) ModifyJobFailed(ctx context.Context, params modifyJobFailedParams) (JobModel, error) {
+	var job JobModel
+	err := r.db.Query(ctx, &job, modifyJobFailedQuery,
+		params.JobID, params.WorkerID, params.LastError, params.RetryDelay.Seconds())
+	return job, err
+}
+
+type modifyJobRetryParams struct {
+	JobID int
+}
+
+const modifyJobRetryQuery = `
+	-- jobs.modifyJobRetryQuery
+	UPDATE jobs
+	SET status = 'pending',
+		attempts = 0,
+		run_at = NOW(),
+		finished_at = NULL,
+		updated_at = NOW()
+	WHERE job_id = $1 AND status = 'failed'
+	RETURNING job_id, created_at, updated_at, job_type, organization_id, payload, unique_key,
+			  status, attempts, max_attempts, run_at, locked_by, locked_until, last_error, finished_at;
+`
+
+func (r *repository) ModifyJobRetry(ctx context.Context, params modifyJobRetryParams) (JobModel, error) {
+	var job JobModel
+	err := r.db.Query(ctx, &job, modifyJobRetryQuery, params.JobID)
+	return job, err
+}
+
+type deleteFinishedJobsParams struct {
+	FinishedBefore time.Time
+}
+
+// deleteFinishedJobsQuery removes succeeded and failed jobs and returns how
+// many were removed. Pending and running jobs are never touched.
+const deleteFinishedJobsQuery = `
+	-- jobs.deleteFinishedJobsQuery
+	WITH deleted AS (
+		DELETE FROM jobs
+		WHERE status IN ('succeeded', 'failed')
+			AND finished_at < $1
+		RETURNING job_id
+	)
+	SELECT COUNT(*) FROM deleted;
+`
+
+func (r *repository) DeleteFinishedJobs(ctx context.Context, params deleteFinishedJobsParams) (int, error) {
+	var deleted int
+	err := r.db.Query(ctx, &deleted, deleteFinishedJobsQuery, params.FinishedBefore)
+	return deleted, err
+}
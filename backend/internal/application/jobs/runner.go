@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+)
+
+const defaultLease = 10 * time.Minute
+
+// HandlerFunc runs a claimed job. Returning an error schedules a retry.
+type HandlerFunc func(ctx context.Context, job Job) error
+
+// SchedulerFunc enqueues recurring jobs that are due at now. It runs on every
+// worker tick, so it must rely on unique keys to stay idempotent.
+type SchedulerFunc func(ctx context.Context, now time.Time) error
+
+// Runner dispatches claimed jobs to the handler registered for their type.
+// Domains register their handlers and schedulers at startup.
+type Runner struct {
+	service  Service
+	system   *system.System
+	logger   logging.Logger
+	workerID string
+
+	mu         sync.RWMutex
+	handlers   map[string]HandlerFunc
+	schedulers []SchedulerFunc
+}
+
+func NewRunner(service Service, system *system.System, logger logging.Logger) *Runner {
+	hostname, _ := os.Hostname()
+	return &Runner{
+		service:  service,
+		system:   system,
+		logger:   logger,
+		workerID: fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		handlers: make(map[string]HandlerFunc),
+	}
+}
+
+func (r *Runner) Register(jobType string, handler HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[jobType] = handler
+}
+
+func (r *Runner) RegisterScheduler(scheduler SchedulerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schedulers = append(r.schedulers, scheduler)
+}
+
+// JobTypes returns the registered job types.
+func (r *Runner) JobTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.handlers))
+	for jobType := range r.handlers {
+		types = append(types, jobType)
+	}
+	return types
+}
+
+// Schedule runs every registered scheduler, then prunes old finished jobs. A
+// failing scheduler is logged and does not stop the others.
+func (r *Runner) Schedule(ctx context.Context) {
+	r.mu.RLock()
+	schedulers := append([]SchedulerFunc(nil), r.schedulers...)
+	r.mu.RUnlock()
+
+	now := r.system.Time.Now()
+	for _, scheduler := range schedulers {
+		if err := scheduler(ctx, now); err != nil {
+			r.logger.Error(ctx, "Job scheduler failed", "error", err.Error())
+		}
+	}
+
+	pruned, err := r.service.PruneJobs(ctx, PruneJobsInput{})
+	if err != nil {
+		r.logger.Error(ctx, "Failed to prune finished jobs", "error", err.Error())
+		return
+	}
+	if pruned > 0 {
+		r.logger.Info(ctx, "Pruned finished jobs", "count", pruned)
+	}
+}
+
+// RunDue claims and runs due jobs until the queue is drained or max jobs ran.
+// It returns how many jobs were run.
+func (r *Runner) RunDue(ctx context.Context, max int) (int, error) {
+	ran := 0
+	for ran < max {
+		if ctx.Err() != nil {
+			return ran, nil
+		}
+
+		job, err := r.service.ClaimNextJob(ctx, ClaimNextJobInput{
+			WorkerID: r.workerID,
+			Lease:    defaultLease,
+		})
+		if err != nil {
+			return ran, err
+		}
+		if job == nil {
+			return ran, nil
+		}
+
+		r.execute(ctx, *job)
+		ran++
+	}
+	return ran, nil
+}
+
+// RunJob runs a specific job now, ignoring run_at. Failed jobs can be run
+// again this way without resetting their attempt count.
+func (r *Runner) RunJob(ctx context.Context, jobID int) (Job, error) {
+	job, err := r.service.ClaimJobByID(ctx, ClaimJobByIDInput{
+		JobID:    jobID,
+		WorkerID: r.workerID,
+		Lease:    defaultLease,
+	})
+	if err != nil {
+		return Job{}, err
+	}
+
+	r.execute(ctx, job)
+	return r.service.GetJobByID(ctx, GetJobByIDInput{JobID: jobID})
+}
+
+func (r *Runner) execute(ctx context.Context, job Job) {
+	r.mu.RLock()
+	handler, ok := r.handlers[job.JobType]
+	r.mu.RUnlock()
+
+	start := r.system.Time.Now()
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler registered for job type %q", job.JobType)
+	} else {
+		err = runHandler(ctx, handler, job)
+	}
+
+	if err == nil {
+		if completeErr := r.service.CompleteJob(ctx, CompleteJobInput{JobID: job.JobID, WorkerID: r.workerID}); completeErr != nil {
+			r.logger.Error(ctx, "Failed to mark job as succeeded", "job_id", job.JobID, "error", completeErr.Error())
+			return
+		}
+		r.logger.Info(ctx, "Job succeeded",
+			"job_id", job.JobID,
+			"job_type", job.JobType,
+			"attempt", job.Attempts,
+			"duration_seconds", r.system.Time.Now().Sub(start).Seconds(),
+		)
+		return
+	}
+
+	failed, failErr := r.service.FailJob(ctx, FailJobInput{
+		JobID:    job.JobID,
+		WorkerID: r.workerID,
+		Attempts: job.Attempts,
+		Err:      err,
+	})
+	if failErr != nil {
+		r.logger.Error(ctx, "Failed to record job failure", "job_id", job.JobID, "error", failErr.Error())
+		return
+	}
+	r.logger.Warn(ctx, "Job failed",
+		"job_id", job.JobID,
+		"job_type", job.JobType,
+		"attempt", job.Attempts,
+		"max_attempts", job.MaxAttempts,
+		"status", failed.Status,
+		"error", err.Error(),
+	)
+}
+
+// runHandler turns a handler panic into a job failure so one bad job cannot
+// take the worker down.
+func runHandler(ctx context.Context, handler HandlerFunc, job Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return handler(ctx, job)
+}
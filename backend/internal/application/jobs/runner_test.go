@@ -0,0 +1,216 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) EnqueueJob(ctx context.Context, input EnqueueJobInput) (*Job, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Job), args.Error(1)
+}
+
+func (m *MockService) GetJobs(ctx context.Context, input GetJobsInput) ([]Job, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).([]Job), args.Error(1)
+}
+
+func (m *MockService) GetJobByID(ctx context.Context, input GetJobByIDInput) (Job, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).(Job), args.Error(1)
+}
+
+func (m *MockService) RetryJob(ctx context.Context, input RetryJobInput) (Job, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).(Job), args.Error(1)
+}
+
+func (m *MockService) PruneJobs(ctx context.Context, input PruneJobsInput) (int, error) {
+	args := m.Called(ctx, input)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockService) ClaimNextJob(ctx context.Context, input ClaimNextJobInput) (*Job, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Job), args.Error(1)
+}
+
+func (m *MockService) ClaimJobByID(ctx context.Context, input ClaimJobByIDInput) (Job, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).(Job), args.Error(1)
+}
+
+func (m *MockService) CompleteJob(ctx context.Context, input CompleteJobInput) error {
+	args := m.Called(ctx, input)
+	return args.Error(0)
+}
+
+func (m *MockService) FailJob(ctx context.Context, input FailJobInput) (Job, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).(Job), args.Error(1)
+}
+
+func newTestRunner(svc Service) *Runner {
+	runner := NewRunner(svc, system.NewStubSystem().ToSystem(), &logging.TestLogger{})
+	runner.workerID = "test-worker"
+	return runner
+}
+
+func TestRunDue_CompletesSuccessfulJobsUntilQueueIsEmpty(t *testing.T) {
+	ctx := context.Background()
+	svc := new(MockService)
+	runner := newTestRunner(svc)
+
+	first := &Job{JobID: 1, JobType: "test.ok", Attempts: 1, MaxAttempts: 5}
+	second := &Job{JobID: 2, JobType: "test.ok", Attempts: 1, MaxAttempts: 5}
+	claim := ClaimNextJobInput{WorkerID: "test-worker", Lease: defaultLease}
+	svc.On("ClaimNextJob", ctx, claim).Return(first, nil).Once()
+	svc.On("ClaimNextJob", ctx, claim).Return(second, nil).Once()
+	svc.On("ClaimNextJob", ctx, claim).Return(nil, nil).Once()
+	svc.On("CompleteJob", ctx, CompleteJobInput{JobID: 1, WorkerID: "test-worker"}).Return(nil)
+	svc.On("CompleteJob", ctx, CompleteJobInput{JobID: 2, WorkerID: "test-worker"}).Return(nil)
+
+	var handled []int
+	runner.Register("test.ok", func(ctx context.Context, job Job) error {
+		handled = append(handled, job.JobID)
+		return nil
+	})
+
+	ran, err := runner.RunDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ran)
+	assert.Equal(t, []int{1, 2}, handled)
+	svc.AssertExpectations(t)
+}
+
+func TestRunDue_RecordsFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		jobType   string
+		handler   HandlerFunc
+		wantError string
+	}{
+		{
+			name:    "handler error",
+			jobType: "test.fail",
+			handler: func(ctx context.Context, job Job) error {
+				return errors.New("boom")
+			},
+			wantError: "boom",
+		},
+		{
+			name:    "handler panic",
+			jobType: "test.fail",
+			handler: func(ctx context.Context, job Job) error {
+				panic("kaboom")
+			},
+			wantError: "job panicked: kaboom",
+		},
+		{
+			name:      "unregistered type",
+			jobType:   "test.unknown",
+			wantError: `no handler registered for job type "test.unknown"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := new(MockService)
+			runner := newTestRunner(svc)
+			if tt.handler != nil {
+				runner.Register(tt.jobType, tt.handler)
+			}
+
+			job := &Job{JobID: 7, JobType: tt.jobType, Attempts: 3, MaxAttempts: 5}
+			svc.On("ClaimNextJob", ctx, mock.Anything).Return(job, nil).Once()
+			svc.On("FailJob", ctx, mock.MatchedBy(func(input FailJobInput) bool {
+				return input.JobID == 7 &&
+					input.WorkerID == "test-worker" &&
+					input.Attempts == 3 &&
+					input.Err.Error() == tt.wantError
+			})).Return(Job{JobID: 7, Status: JobStatusPending}, nil)
+
+			ran, err := runner.RunDue(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, 1, ran)
+			svc.AssertExpectations(t)
+			svc.AssertNotCalled(t, "CompleteJob", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSchedule_PrunesFinishedJobsAfterScheduling(t *testing.T) {
+	ctx := context.Background()
+	svc := new(MockService)
+	runner := newTestRunner(svc)
+
+	var calls []string
+	runner.RegisterScheduler(func(ctx context.Context, now time.Time) error {
+		calls = append(calls, "failing scheduler")
+		return errors.New("boom")
+	})
+	runner.RegisterScheduler(func(ctx context.Context, now time.Time) error {
+		calls = append(calls, "scheduler")
+		return nil
+	})
+	svc.On("PruneJobs", ctx, PruneJobsInput{}).Run(func(mock.Arguments) {
+		calls = append(calls, "prune")
+	}).Return(3, nil).Once()
+
+	runner.Schedule(ctx)
+
+	assert.Equal(t, []string{"failing scheduler", "scheduler", "prune"}, calls)
+	svc.AssertExpectations(t)
+}
+
+func TestPruneJobs_DeletesJobsFinishedBeforeTheRetention(t *testing.T) {
+	ctx := context.Background()
+	repo := &pruneRepository{deleted: 4}
+	stub := system.NewStubSystem()
+	stub.Time.SetTimes(time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC))
+	svc := New(repo, stub.ToSystem(), &logging.TestLogger{})
+
+	deleted, err := svc.PruneJobs(ctx, PruneJobsInput{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, deleted)
+	assert.Equal(t, time.Date(2026, time.July, 18, 12, 0, 0, 0, time.UTC), repo.finishedBefore)
+}
+
+// pruneRepository records the cutoff it is asked to prune at
+type pruneRepository struct {
+	Repository
+	finishedBefore time.Time
+	deleted        int
+}
+
+func (r *pruneRepository) DeleteFinishedJobs(ctx context.Context, params deleteFinishedJobsParams) (int, error) {
+	r.finishedBefore = params.FinishedBefore
+	return r.deleted, nil
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, retryDelay(0))
+	assert.Equal(t, time.Minute, retryDelay(1))
+	assert.Equal(t, 2*time.Minute, retryDelay(2))
+	assert.Equal(t, 16*time.Minute, retryDelay(5))
+	assert.Equal(t, 6*time.Hour, retryDelay(20))
+}
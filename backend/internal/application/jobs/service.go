@@ -0,0 +1,291 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/catrutech/celeiro/pkg/errors"
+	"github.com/catrutech/celeiro/pkg/logging"
+	"github.com/catrutech/celeiro/pkg/system"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultListLimit   = 50
+
+	// defaultRetention keeps finished jobs well past the longest period a
+	// unique key covers (a month), so pruning never lets a recurring job be
+	// enqueued twice for the same period.
+	defaultRetention = 90 * 24 * time.Hour
+)
+
+var (
+	ErrJobNotClaimable = errors.New("job is not pending or failed, or is leased by another worker")
+	ErrJobNotRetryable = errors.New("only failed jobs can be retried")
+)
+
+type Service interface {
+	// Queue
+	EnqueueJob(ctx context.Context, input EnqueueJobInput) (*Job, error)
+	GetJobs(ctx context.Context, input GetJobsInput) ([]Job, error)
+	GetJobByID(ctx context.Context, input GetJobByIDInput) (Job, error)
+	RetryJob(ctx context.Context, input RetryJobInput) (Job, error)
+	PruneJobs(ctx context.Context, input PruneJobsInput) (int, error)
+
+	// Leases
+	ClaimNextJob(ctx context.Context, input ClaimNextJobInput) (*Job, error)
+	ClaimJobByID(ctx context.Context, input ClaimJobByIDInput) (Job, error)
+	CompleteJob(ctx context.Context, input CompleteJobInput) error
+	FailJob(ctx context.Context, input FailJobInput) (Job, error)
+}
+
+type service struct {
+	Repository Repository
+	system     *system.System
+	logger     logging.Logger
+}
+
+func New(
+	repo Repository,
+	system *system.System,
+	logger logging.Logger,
+) Service {
+	return &service{
+		Repository: repo,
+		system:     system,
+		logger:     logger,
+	}
+}
+
+// ============================================================================
+// Queue
+// ============================================================================
+
+type EnqueueJobInput struct {
+	JobType        string
+	OrganizationID *int
+	Payload        any        // Marshaled to JSON; nil stores {}
+	UniqueKey      *string    // Jobs sharing a key are only enqueued once
+	RunAt          *time.Time // nil runs as soon as a worker is free
+	MaxAttempts    int        // 0 uses the default
+}
+
+// EnqueueJob adds a job to the queue. It returns nil without error when a job
+// with the same UniqueKey was already enqueued.
+func (s *service) EnqueueJob(ctx context.Context, input EnqueueJobInput) (*Job, error) {
+	payload := "{}"
+	if input.Payload != nil {
+		raw, err := json.Marshal(input.Payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal job payload")
+		}
+		payload = string(raw)
+	}
+
+	runAt := s.system.Time.Now()
+	if input.RunAt != nil {
+		runAt = *input.RunAt
+	}
+
+	maxAttempts := input.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	models, err := s.Repository.InsertJob(ctx, insertJobParams{
+		JobType:        input.JobType,
+		OrganizationID: input.OrganizationID,
+		Payload:        payload,
+		UniqueKey:      input.UniqueKey,
+		MaxAttempts:    maxAttempts,
+		RunAt:          runAt,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to enqueue job")
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	job := Job{}.FromModel(&models[0])
+	return &job, nil
+}
+
+type GetJobsInput struct {
+	Status  *string
+	JobType *string
+	Limit   int
+}
+
+func (s *service) GetJobs(ctx context.Context, input GetJobsInput) ([]Job, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	models, err := s.Repository.FetchJobs(ctx, fetchJobsParams{
+		Status:  input.Status,
+		JobType: input.JobType,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch jobs")
+	}
+
+	return Jobs{}.FromModel(models), nil
+}
+
+type GetJobByIDInput struct {
+	JobID int
+}
+
+func (s *service) GetJobByID(ctx context.Context, input GetJobByIDInput) (Job, error) {
+	model, err := s.Repository.FetchJobByID(ctx, fetchJobByIDParams{JobID: input.JobID})
+	if err != nil {
+		return Job{}, errors.Wrap(err, "failed to fetch job")
+	}
+
+	return Job{}.FromModel(&model), nil
+}
+
+type RetryJobInput struct {
+	JobID int
+}
+
+// RetryJob puts a failed job back in the queue with a fresh attempt budget.
+func (s *service) RetryJob(ctx context.Context, input RetryJobInput) (Job, error) {
+	model, err := s.Repository.ModifyJobRetry(ctx, modifyJobRetryParams{JobID: input.JobID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Job{}, ErrJobNotRetryable
+		}
+		return Job{}, errors.Wrap(err, "failed to retry job")
+	}
+
+	return Job{}.FromModel(&model), nil
+}
+
+type PruneJobsInput struct {
+	Retention time.Duration // 0 uses the default of 90 days
+}
+
+// PruneJobs deletes succeeded and failed jobs that finished longer than
+// Retention ago and returns how many were deleted.
+func (s *service) PruneJobs(ctx context.Context, input PruneJobsInput) (int, error) {
+	retention := input.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+
+	deleted, err := s.Repository.DeleteFinishedJobs(ctx, deleteFinishedJobsParams{
+		FinishedBefore: s.system.Time.Now().Add(-retention),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to prune jobs")
+	}
+
+	return deleted, nil
+}
+
+// ============================================================================
+// Leases
+// ============================================================================
+
+type ClaimNextJobInput struct {
+	WorkerID string
+	Lease    time.Duration
+}
+
+// ClaimNextJob leases the next due job to the worker, or returns nil when the
+// queue has nothing due.
+func (s *service) ClaimNextJob(ctx context.Context, input ClaimNextJobInput) (*Job, error) {
+	models, err := s.Repository.ClaimNextJob(ctx, claimNextJobParams{
+		WorkerID: input.WorkerID,
+		Lease:    input.Lease,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim job")
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	job := Job{}.FromModel(&models[0])
+	return &job, nil
+}
+
+type ClaimJobByIDInput struct {
+	JobID    int
+	WorkerID string
+	Lease    time.Duration
+}
+
+func (s *service) ClaimJobByID(ctx context.Context, input ClaimJobByIDInput) (Job, error) {
+	models, err := s.Repository.ClaimJobByID(ctx, claimJobByIDParams{
+		JobID:    input.JobID,
+		WorkerID: input.WorkerID,
+		Lease:    input.Lease,
+	})
+	if err != nil {
+		return Job{}, errors.Wrap(err, "failed to claim job")
+	}
+	if len(models) == 0 {
+		return Job{}, ErrJobNotClaimable
+	}
+
+	return Job{}.FromModel(&models[0]), nil
+}
+
+type CompleteJobInput struct {
+	JobID    int
+	WorkerID string
+}
+
+func (s *service) CompleteJob(ctx context.Context, input CompleteJobInput) error {
+	err := s.Repository.ModifyJobSucceeded(ctx, modifyJobSucceededParams{
+		JobID:    input.JobID,
+		WorkerID: input.WorkerID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to complete job")
+	}
+
+	return nil
+}
+
+type FailJobInput struct {
+	JobID    int
+	WorkerID string
+	Attempts int
+	Err      error
+}
+
+// FailJob records a failed attempt. The job is retried with exponential backoff
+// until it runs out of attempts.
+func (s *service) FailJob(ctx context.Context, input FailJobInput) (Job, error) {
+	model, err := s.Repository.ModifyJobFailed(ctx, modifyJobFailedParams{
+		JobID:      input.JobID,
+		WorkerID:   input.WorkerID,
+		LastError:  input.Err.Error(),
+		RetryDelay: retryDelay(input.Attempts),
+	})
+	if err != nil {
+		return Job{}, errors.Wrap(err, "failed to record job failure")
+	}
+
+	return Job{}.FromModel(&model), nil
+}
+
+// retryDelay doubles from one minute per attempt, capped at six hours.
+func retryDelay(attempts int) time.Duration {
+	delay := time.Minute
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= 6*time.Hour {
+			return 6 * time.Hour
+		}
+	}
+	return delay
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/catrutech/celeiro/pkg/logging"
+	"go.uber.org/fx"
+)
+
+const (
+	workerPollInterval     = 30 * time.Second
+	workerScheduleInterval = time.Hour
+	workerBatchSize        = 20
+)
+
+// Worker polls the queue in the background of the web process. Several
+// instances can run at once; leases keep them from running the same job.
+type Worker struct {
+	runner *Runner
+	logger logging.Logger
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewWorker(lc fx.Lifecycle, runner *Runner, logger logging.Logger) *Worker {
+	worker := &Worker{
+		runner: runner,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			runCtx, cancel := context.WithCancel(context.Background())
+			worker.cancel = cancel
+			logger.Info(ctx, "Starting job worker", "poll_interval", workerPollInterval.String())
+			go worker.loop(runCtx)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Info(ctx, "Stopping job worker")
+			worker.cancel()
+			select {
+			case <-worker.done:
+			case <-ctx.Done():
+			}
+			return nil
+		},
+	})
+
+	return worker
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	defer close(w.done)
+
+	poll := time.NewTicker(workerPollInterval)
+	defer poll.Stop()
+	schedule := time.NewTicker(workerScheduleInterval)
+	defer schedule.Stop()
+
+	w.runner.Schedule(ctx)
+	w.runDue(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-schedule.C:
+			w.runner.Schedule(ctx)
+		case <-poll.C:
+			w.runDue(ctx)
+		}
+	}
+}
+
+func (w *Worker) runDue(ctx context.Context) {
+	if _, err := w.runner.RunDue(ctx, workerBatchSize); err != nil {
+		w.logger.Error(ctx, "Job worker failed to run due jobs", "error", err.Error())
+	}
+}
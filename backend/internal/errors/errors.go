@@ -20,6 +20,7 @@ var (
 	ErrInvalidCredentials            = pkgerrors.New("invalid credentials")
 	ErrEmailNotVerified              = pkgerrors.New("email not verified")
 	ErrMonthClosed                   = pkgerrors.New("month is closed")
+	ErrMonthNotClosed                = pkgerrors.New("month is not closed")
 	ErrUserAlreadyExists             = pkgerrors.New("user already exists")
 	ErrInviteNotFound                = pkgerrors.New("invite not found")
 	ErrInviteExpired                 = pkgerrors.New("invite has expired")
@@ -28,6 +29,30 @@ var (
 	ErrRecaptchaFailed               = pkgerrors.New("recaptcha verification failed")
 	ErrSavingsGoalNameExists         = pkgerrors.New("savings goal name already exists")
 	ErrPatternRetroactiveUnsupported = pkgerrors.New("ignore patterns cannot be applied retroactively")
+	ErrPluggyNotConfigured           = pkgerrors.New("pluggy integration is not configured")
+	ErrPluggyAccountNotFound         = pkgerrors.New("pluggy account not found in item")
+	ErrInvalidClassificationRule     = pkgerrors.New("invalid classification rule")
+	ErrInvalidCSVImportProfile       = pkgerrors.New("invalid csv import profile")
+	ErrCSVImportProfileNameExists    = pkgerrors.New("csv import profile name already exists")
+	ErrInvalidCSVFile                = pkgerrors.New("invalid csv file")
+	ErrAccountHasClosedMonths        = pkgerrors.New("account has transactions in closed months")
+	ErrInvalidTargetAccount          = pkgerrors.New("invalid target account")
+	ErrTargetAccountHasTransactions  = pkgerrors.New("target account already has these transactions")
+	ErrInvalidCursor                 = pkgerrors.New("invalid pagination cursor")
+	ErrInvalidTransactionSplits      = pkgerrors.New("invalid transaction splits")
+	ErrInvalidTransfer               = pkgerrors.New("invalid transfer")
+	ErrInvalidBillingCycle           = pkgerrors.New("invalid credit card billing cycle")
+	ErrInvalidCurrency               = pkgerrors.New("invalid currency")
+	ErrInvalidExchangeRates          = pkgerrors.New("invalid exchange rates file")
+	ErrMissingExchangeRates          = pkgerrors.New("exchange rates missing for foreign-currency transactions")
+	ErrInvalidRecurrence             = pkgerrors.New("invalid recurrence rule")
+	ErrInvalidForecast               = pkgerrors.New("invalid cash-flow forecast")
+	ErrInvalidReportRange            = pkgerrors.New("invalid report range")
+	ErrInvalidOrganizationArchive    = pkgerrors.New("invalid organization archive")
+	ErrOrganizationNotEmpty          = pkgerrors.New("organization is not empty")
+	ErrInvalidTransactionExport      = pkgerrors.New("invalid transaction export")
+	ErrInvalidPatternOrder           = pkgerrors.New("invalid pattern order")
+	ErrInvalidPredictionThreshold    = pkgerrors.New("invalid prediction threshold")
 
 	// Transaction pattern draft errors
 	ErrTransactionCategoryRequired    = pkgerrors.New("transaction has no category")
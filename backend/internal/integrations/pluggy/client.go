@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/catrutech/celeiro/internal/config"
+	"github.com/shopspring/decimal"
 )
 
 const defaultHTTPTimeout = 15 * time.Second
@@ -48,6 +49,43 @@ type ConnectToken struct {
 	AccessToken string `json:"accessToken"`
 }
 
+// Account is the subset of a Pluggy account needed to link it to a Celeiro account.
+type Account struct {
+	ID           string          `json:"id"`
+	ItemID       string          `json:"itemId"`
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`    // BANK, CREDIT
+	Subtype      string          `json:"subtype"` // CHECKING_ACCOUNT, SAVINGS_ACCOUNT, CREDIT_CARD
+	Number       string          `json:"number"`
+	Balance      decimal.Decimal `json:"balance"`
+	CurrencyCode string          `json:"currencyCode"`
+}
+
+type accountsPage struct {
+	Results []Account `json:"results"`
+}
+
+// Transaction is a single Pluggy transaction. Amount is signed from the
+// account holder's perspective; Type carries the DEBIT/CREDIT direction.
+type Transaction struct {
+	ID             string          `json:"id"`
+	AccountID      string          `json:"accountId"`
+	Description    string          `json:"description"`
+	DescriptionRaw *string         `json:"descriptionRaw"`
+	CurrencyCode   string          `json:"currencyCode"`
+	Amount         decimal.Decimal `json:"amount"`
+	Date           time.Time       `json:"date"`
+	Type           string          `json:"type"`   // DEBIT, CREDIT
+	Status         string          `json:"status"` // PENDING, POSTED
+}
+
+type TransactionsPage struct {
+	Total      int           `json:"total"`
+	TotalPages int           `json:"totalPages"`
+	Page       int           `json:"page"`
+	Results    []Transaction `json:"results"`
+}
+
 func New(cfg *config.Config) *Client {
 	return &Client{
 		baseURL:      strings.TrimRight(cfg.Pluggy.BaseURL, "/"),
@@ -122,6 +160,41 @@ func (c *Client) ListTransactionsByAccount(ctx context.Context, accountID string
 	return resp, nil
 }
 
+// FetchAccounts returns the typed accounts of a Pluggy item.
+func (c *Client) FetchAccounts(ctx context.Context, itemID string) ([]Account, error) {
+	query := url.Values{}
+	query.Set("itemId", itemID)
+
+	var resp accountsPage
+	if err := c.doJSON(ctx, http.MethodGet, "/accounts", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Results, nil
+}
+
+// FetchTransactions returns one page (1-based) of typed transactions for a
+// Pluggy account between from and to, inclusive.
+func (c *Client) FetchTransactions(ctx context.Context, accountID string, from, to time.Time, page int) (TransactionsPage, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	query := url.Values{}
+	query.Set("accountId", accountID)
+	query.Set("from", from.Format("2006-01-02"))
+	query.Set("to", to.Format("2006-01-02"))
+	query.Set("pageSize", "500")
+	query.Set("page", strconv.Itoa(page))
+
+	var resp TransactionsPage
+	if err := c.doJSON(ctx, http.MethodGet, "/transactions", query, nil, &resp); err != nil {
+		return TransactionsPage{}, err
+	}
+
+	return resp, nil
+}
+
 func (c *Client) doJSON(ctx context.Context, method, path string, query url.Values, body any, out any) error {
 	if !c.IsConfigured() {
 		return fmt.Errorf("pluggy credentials are not configured")
@@ -76,6 +76,50 @@ func TestQueryDateRangeDefaultsToCurrentMonth(t *testing.T) {
 	assert.Equal(t, "2026-03-31", to.Format("2006-01-02"))
 }
 
+func TestFetchTransactionsDecodesTypedPage(t *testing.T) {
+	client := &Client{
+		baseURL:      "https://api.example.test",
+		clientID:     "client-id",
+		clientSecret: "client-secret",
+		httpClient: &http.Client{
+			Timeout: 2 * time.Second,
+			Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				switch r.URL.Path {
+				case "/auth":
+					return jsonResponse(t, http.StatusOK, authResponse{APIKey: "test-api-key", ExpiresIn: 3600}), nil
+				case "/transactions":
+					assert.Equal(t, "acc-1", r.URL.Query().Get("accountId"))
+					assert.Equal(t, "2026-03-01", r.URL.Query().Get("from"))
+					assert.Equal(t, "2026-03-31", r.URL.Query().Get("to"))
+					assert.Equal(t, "2", r.URL.Query().Get("page"))
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     make(http.Header),
+						Body: io.NopCloser(bytes.NewBufferString(`{"total":501,"totalPages":2,"page":2,"results":[
+							{"id":"tx-1","accountId":"acc-1","description":"PIX MERCADO","amount":-42.5,
+							 "date":"2026-03-10T00:00:00.000Z","type":"DEBIT","status":"POSTED","currencyCode":"BRL"}]}`)),
+					}, nil
+				default:
+					return jsonResponse(t, http.StatusNotFound, map[string]string{"error": "not found"}), nil
+				}
+			}),
+		},
+	}
+
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+	page, err := client.FetchTransactions(context.Background(), "acc-1", from, to, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, page.TotalPages)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, "tx-1", page.Results[0].ID)
+	assert.Equal(t, "-42.5", page.Results[0].Amount.String())
+	assert.Equal(t, "DEBIT", page.Results[0].Type)
+	assert.Equal(t, "2026-03-10", page.Results[0].Date.Format("2006-01-02"))
+}
+
 type roundTripFunc func(*http.Request) (*http.Response, error)
 
 func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
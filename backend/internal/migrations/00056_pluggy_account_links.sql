@@ -0,0 +1,32 @@
+-- +goose Up
+-- Links a Pluggy account (inside a Pluggy item/connection) to a Celeiro account.
+-- sync_cursor is the last transaction date fully synced; the next sync starts a
+-- few days before it so pending transactions that later post are picked up.
+
+CREATE TABLE pluggy_account_links (
+    pluggy_account_link_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    user_id INT NOT NULL REFERENCES users(user_id),
+    organization_id INT NOT NULL REFERENCES organizations(organization_id),
+    account_id INT NOT NULL REFERENCES accounts(account_id) ON DELETE CASCADE,
+
+    pluggy_item_id VARCHAR(64) NOT NULL,
+    pluggy_account_id VARCHAR(64) NOT NULL,
+
+    sync_cursor DATE,
+    last_synced_at TIMESTAMP,
+    last_sync_status VARCHAR(20) NOT NULL DEFAULT 'never'
+        CHECK (last_sync_status IN ('never', 'success', 'error')),
+    last_sync_error TEXT,
+    last_imported_count INT NOT NULL DEFAULT 0,
+
+    UNIQUE (organization_id, pluggy_account_id)
+);
+
+CREATE INDEX idx_pluggy_account_links_organization_id ON pluggy_account_links(organization_id);
+CREATE INDEX idx_pluggy_account_links_account_id ON pluggy_account_links(account_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS pluggy_account_links CASCADE;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Postgres-backed job queue. Workers claim due jobs with FOR UPDATE SKIP LOCKED
+-- and hold a lease (locked_until); a job whose lease expired is claimable again,
+-- so a crashed worker does not strand it. unique_key deduplicates recurring jobs
+-- (e.g. one close_month per organization and month).
+
+CREATE TABLE jobs (
+    job_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    job_type VARCHAR(64) NOT NULL,
+    organization_id INT REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    payload JSONB NOT NULL DEFAULT '{}',
+    unique_key VARCHAR(255),
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'succeeded', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    locked_by VARCHAR(255),
+    locked_until TIMESTAMP,
+    last_error TEXT,
+    finished_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_jobs_unique_key ON jobs(unique_key) WHERE unique_key IS NOT NULL;
+CREATE INDEX idx_jobs_claimable ON jobs(run_at) WHERE status IN ('pending', 'running');
+CREATE INDEX idx_jobs_status ON jobs(status);
+
+-- +goose Down
+DROP TABLE IF EXISTS jobs CASCADE;
@@ -0,0 +1,55 @@
+-- +goose Up
+
+-- View-only members for shared organizations
+INSERT INTO roles (role_name) VALUES ('viewer');
+
+INSERT INTO permissions (permission) VALUES
+('view_transactions'),
+('edit_transactions'),
+('manage_budgets'),
+('close_month'),
+('manage_patterns'),
+('manage_goals');
+
+-- Admins bypass permission checks, but get the full list so clients can rely
+-- on user_permissions alone. Sessions created before this migration keep their
+-- old permission list until the user logs in again.
+INSERT INTO role_permissions (role_name, permission) VALUES
+('super_admin', 'view_transactions'),
+('super_admin', 'edit_transactions'),
+('super_admin', 'manage_budgets'),
+('super_admin', 'close_month'),
+('super_admin', 'manage_patterns'),
+('super_admin', 'manage_goals'),
+
+('admin', 'view_transactions'),
+('admin', 'edit_transactions'),
+('admin', 'manage_budgets'),
+('admin', 'close_month'),
+('admin', 'manage_patterns'),
+('admin', 'manage_goals'),
+
+('regular_manager', 'view_transactions'),
+('regular_manager', 'edit_transactions'),
+('regular_manager', 'manage_budgets'),
+('regular_manager', 'close_month'),
+('regular_manager', 'manage_patterns'),
+('regular_manager', 'manage_goals'),
+
+('regular_user', 'view_transactions'),
+('regular_user', 'edit_transactions'),
+
+('viewer', 'view_transactions');
+
+-- +goose Down
+DELETE FROM role_permissions WHERE permission IN (
+    'view_transactions', 'edit_transactions', 'manage_budgets',
+    'close_month', 'manage_patterns', 'manage_goals'
+);
+DELETE FROM permissions WHERE permission IN (
+    'view_transactions', 'edit_transactions', 'manage_budgets',
+    'close_month', 'manage_patterns', 'manage_goals'
+);
+UPDATE user_organizations SET user_role = 'regular_user' WHERE user_role = 'viewer';
+UPDATE organization_invites SET role = 'regular_user' WHERE role = 'viewer';
+DELETE FROM roles WHERE role_name = 'viewer';
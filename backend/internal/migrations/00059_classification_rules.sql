@@ -0,0 +1,35 @@
+-- +goose Up
+-- Classification rules were dropped in 00015 in favor of regex patterns. They
+-- come back as simple, explicitly ordered categorization rules evaluated during
+-- import after patterns, and are now shared by the whole organization.
+CREATE TABLE classification_rules (
+    rule_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    organization_id INT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories(category_id) ON DELETE CASCADE,
+
+    name VARCHAR(255) NOT NULL,
+    priority INT NOT NULL DEFAULT 100,  -- Lower = higher priority (first match wins)
+
+    -- Match conditions (all are optional, AND logic if multiple present)
+    match_description VARCHAR(500),  -- Substring match (case-insensitive)
+    match_amount_min DECIMAL(15, 2),
+    match_amount_max DECIMAL(15, 2),
+    match_transaction_type VARCHAR(20),  -- 'debit', 'credit', or NULL (any)
+
+    is_active BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE INDEX idx_classification_rules_category_id ON classification_rules(category_id);
+CREATE INDEX idx_classification_rules_priority ON classification_rules(organization_id, priority ASC, created_at ASC)
+WHERE is_active = true;
+
+-- transactions.classification_rule_id stays a plain INT: an ON DELETE SET NULL
+-- foreign key would update transactions in closed months and make the
+-- closed-month trigger reject the rule deletion.
+
+-- +goose Down
+DROP TABLE IF EXISTS classification_rules;
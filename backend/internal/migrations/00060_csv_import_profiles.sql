@@ -0,0 +1,40 @@
+-- +goose Up
+-- Column mappings for CSV statements, saved per account since every bank (and
+-- Nubank's credit card export) lays its CSV out differently. Columns are
+-- referenced by their header name.
+
+CREATE TABLE csv_import_profiles (
+    csv_import_profile_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    user_id INT NOT NULL REFERENCES users(user_id),
+    organization_id INT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    account_id INT NOT NULL REFERENCES accounts(account_id) ON DELETE CASCADE,
+
+    name VARCHAR(255) NOT NULL,
+
+    delimiter VARCHAR(1) NOT NULL DEFAULT ',',
+    skip_rows INT NOT NULL DEFAULT 0 CHECK (skip_rows >= 0),  -- Lines before the header row
+
+    date_column VARCHAR(255) NOT NULL,
+    date_format VARCHAR(20) NOT NULL DEFAULT 'DD/MM/YYYY',
+    description_columns TEXT[] NOT NULL,  -- Joined in order
+    id_column VARCHAR(255),  -- Bank-provided transaction id, used as FITID when present
+
+    -- Either a single signed amount column, or separate debit and credit columns
+    amount_column VARCHAR(255),
+    debit_column VARCHAR(255),
+    credit_column VARCHAR(255),
+    decimal_separator VARCHAR(1) NOT NULL DEFAULT ',' CHECK (decimal_separator IN (',', '.')),
+    sign_convention VARCHAR(20) NOT NULL DEFAULT 'negative_debit'
+        CHECK (sign_convention IN ('negative_debit', 'positive_debit')),
+
+    CHECK (amount_column IS NOT NULL OR (debit_column IS NOT NULL AND credit_column IS NOT NULL)),
+    UNIQUE (account_id, name)
+);
+
+CREATE INDEX idx_csv_import_profiles_organization_id ON csv_import_profiles(organization_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS csv_import_profiles CASCADE;
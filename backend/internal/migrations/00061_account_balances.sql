@@ -0,0 +1,74 @@
+-- +goose Up
+-- accounts.balance becomes derived: opening_balance plus every transaction on or
+-- after opening_balance_date (credits add, debits subtract), kept current by a
+-- trigger so imports, manual edits and syncs all move it.
+ALTER TABLE accounts
+    ADD COLUMN opening_balance DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN opening_balance_date DATE,  -- NULL counts every transaction
+    ADD COLUMN reconciled_through DATE;    -- Latest statement date the computed balance matched
+
+-- The balance entered when the account was created was never touched by
+-- imports, so it is the best opening balance we have.
+UPDATE accounts SET opening_balance = COALESCE(balance, 0.00);
+
+UPDATE accounts a
+SET balance = a.opening_balance + COALESCE((
+    SELECT SUM(CASE WHEN t.transaction_type = 'credit' THEN ABS(t.amount) ELSE -ABS(t.amount) END)
+    FROM transactions t
+    WHERE t.account_id = a.account_id
+), 0.00);
+
+-- Balances reported by the bank (OFX LEDGERBAL) or typed in by the user,
+-- compared against the computed balance on the same date.
+CREATE TABLE account_statement_balances (
+    account_statement_balance_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    organization_id INT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    account_id INT NOT NULL REFERENCES accounts(account_id) ON DELETE CASCADE,
+
+    balance_date DATE NOT NULL,  -- End of day the balance refers to
+    balance DECIMAL(15, 2) NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('ofx', 'manual')),
+
+    UNIQUE (account_id, balance_date)
+);
+
+CREATE INDEX idx_account_statement_balances_organization_id ON account_statement_balances(organization_id);
+
+-- +goose StatementBegin
+CREATE FUNCTION apply_transaction_to_account_balance()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE accounts
+        SET balance = balance - CASE WHEN OLD.transaction_type = 'credit' THEN ABS(OLD.amount) ELSE -ABS(OLD.amount) END
+        WHERE account_id = OLD.account_id
+          AND (opening_balance_date IS NULL OR OLD.transaction_date >= opening_balance_date);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE accounts
+        SET balance = balance + CASE WHEN NEW.transaction_type = 'credit' THEN ABS(NEW.amount) ELSE -ABS(NEW.amount) END
+        WHERE account_id = NEW.account_id
+          AND (opening_balance_date IS NULL OR NEW.transaction_date >= opening_balance_date);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER transactions_update_account_balance
+AFTER INSERT OR DELETE OR UPDATE OF account_id, amount, transaction_date, transaction_type ON transactions
+FOR EACH ROW EXECUTE FUNCTION apply_transaction_to_account_balance();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS transactions_update_account_balance ON transactions;
+DROP FUNCTION IF EXISTS apply_transaction_to_account_balance();
+DROP TABLE IF EXISTS account_statement_balances CASCADE;
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS reconciled_through,
+    DROP COLUMN IF EXISTS opening_balance_date,
+    DROP COLUMN IF EXISTS opening_balance;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Audit trail for reopened months. Reopening removes the closed_months row,
+-- the CARRYOVER-YYYY-MM transaction and the month's snapshots; what was
+-- removed is kept here so the previous closing can still be explained.
+CREATE TABLE month_reopenings (
+    month_reopening_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    organization_id INT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(user_id),  -- Who reopened
+
+    month INT NOT NULL CHECK (month BETWEEN 1 AND 12),
+    year INT NOT NULL,
+    reason TEXT NOT NULL,
+
+    closed_at TIMESTAMP NOT NULL,  -- When the month had been closed
+    carryover_amount DECIMAL(15, 2),  -- Signed amount of the removed carry-over; NULL when there was none
+    snapshot_count INT NOT NULL DEFAULT 0  -- Monthly snapshots removed
+);
+
+CREATE INDEX idx_month_reopenings_organization_month ON month_reopenings(organization_id, year, month);
+
+-- +goose Down
+DROP TABLE IF EXISTS month_reopenings CASCADE;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Keyset pagination for the transaction search walks these in sort order;
+-- transaction_id breaks ties between rows on the same date or amount.
+CREATE INDEX idx_transactions_date_id ON transactions(transaction_date DESC, transaction_id DESC);
+CREATE INDEX idx_transactions_account_date_id ON transactions(account_id, transaction_date DESC, transaction_id DESC);
+CREATE INDEX idx_transactions_abs_amount_id ON transactions((ABS(amount)), transaction_id);
+CREATE INDEX idx_transactions_needs_review ON transactions(needs_review) WHERE needs_review = true;
+
+-- Free-text search is a substring match (ILIKE '%term%'), which only trigram
+-- indexes can serve.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_transactions_description_trgm ON transactions USING GIN (description gin_trgm_ops);
+CREATE INDEX idx_transactions_original_description_trgm ON transactions USING GIN (original_description gin_trgm_ops);
+CREATE INDEX idx_transactions_notes_trgm ON transactions USING GIN (notes gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_transactions_notes_trgm;
+DROP INDEX IF EXISTS idx_transactions_original_description_trgm;
+DROP INDEX IF EXISTS idx_transactions_description_trgm;
+DROP INDEX IF EXISTS idx_transactions_needs_review;
+DROP INDEX IF EXISTS idx_transactions_abs_amount_id;
+DROP INDEX IF EXISTS idx_transactions_account_date_id;
+DROP INDEX IF EXISTS idx_transactions_date_id;
@@ -0,0 +1,103 @@
+-- +goose Up
+-- A split divides one transaction into allocations, each with its own category,
+-- savings goal, tags and notes. Split amounts are absolute (like
+-- transactions.amount) and add up to the parent amount; the service enforces
+-- the sum because it can only hold once every split of a transaction is written.
+CREATE TABLE transaction_splits (
+    split_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    transaction_id INT NOT NULL REFERENCES transactions(transaction_id) ON DELETE CASCADE,
+    category_id INT REFERENCES categories(category_id),
+    savings_goal_id INT REFERENCES savings_goals(savings_goal_id) ON DELETE SET NULL,
+
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    notes TEXT
+);
+
+CREATE INDEX idx_transaction_splits_transaction_id ON transaction_splits(transaction_id);
+CREATE INDEX idx_transaction_splits_category_id ON transaction_splits(category_id);
+CREATE INDEX idx_transaction_splits_savings_goal_id ON transaction_splits(savings_goal_id);
+
+CREATE TABLE transaction_split_tags (
+    split_id INT NOT NULL REFERENCES transaction_splits(split_id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tags(tag_id) ON DELETE CASCADE,
+    PRIMARY KEY (split_id, tag_id)
+);
+
+CREATE INDEX idx_transaction_split_tags_tag_id ON transaction_split_tags(tag_id);
+
+-- One row per budgetable allocation: each split of a split transaction, or the
+-- transaction itself when it has none. Spending aggregates read this instead of
+-- transactions so split amounts replace the parent's.
+CREATE VIEW transaction_allocations AS
+SELECT
+    t.transaction_id,
+    s.split_id,
+    t.account_id,
+    t.transaction_date,
+    t.transaction_type,
+    t.is_ignored,
+    s.category_id,
+    s.savings_goal_id,
+    s.amount
+FROM transactions t
+INNER JOIN transaction_splits s ON s.transaction_id = t.transaction_id
+UNION ALL
+SELECT
+    t.transaction_id,
+    NULL::INT AS split_id,
+    t.account_id,
+    t.transaction_date,
+    t.transaction_type,
+    t.is_ignored,
+    t.category_id,
+    t.savings_goal_id,
+    ABS(t.amount) AS amount
+FROM transactions t
+WHERE NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.transaction_id);
+
+-- Splits belong to their parent's month, so they are frozen with it.
+-- +goose StatementBegin
+CREATE FUNCTION prevent_closed_month_split_mutation()
+RETURNS TRIGGER AS $$
+DECLARE
+    split_transaction_id INT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        split_transaction_id := OLD.transaction_id;
+    ELSE
+        split_transaction_id := NEW.transaction_id;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1
+        FROM transactions t
+        INNER JOIN accounts a ON a.account_id = t.account_id
+        INNER JOIN closed_months cm ON cm.organization_id = a.organization_id
+            AND cm.month = EXTRACT(MONTH FROM t.transaction_date)::INT
+            AND cm.year = EXTRACT(YEAR FROM t.transaction_date)::INT
+        WHERE t.transaction_id = split_transaction_id
+    ) THEN
+        RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER transaction_splits_reject_closed_month
+BEFORE INSERT OR UPDATE OR DELETE ON transaction_splits
+FOR EACH ROW EXECUTE FUNCTION prevent_closed_month_split_mutation();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS transaction_splits_reject_closed_month ON transaction_splits;
+DROP FUNCTION IF EXISTS prevent_closed_month_split_mutation();
+DROP VIEW IF EXISTS transaction_allocations;
+DROP TABLE IF EXISTS transaction_split_tags CASCADE;
+DROP TABLE IF EXISTS transaction_splits CASCADE;
@@ -0,0 +1,60 @@
+-- +goose Up
+-- A transfer links the two legs of money moving between the organization's own
+-- accounts (paying the card bill from checking, moving savings). Linked legs
+-- are neither income nor spending.
+CREATE TABLE transfers (
+    transfer_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    organization_id INT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    debit_transaction_id INT NOT NULL UNIQUE REFERENCES transactions(transaction_id) ON DELETE CASCADE,
+    credit_transaction_id INT NOT NULL UNIQUE REFERENCES transactions(transaction_id) ON DELETE CASCADE,
+
+    detection VARCHAR(10) NOT NULL CHECK (detection IN ('auto', 'manual')),
+
+    CHECK (debit_transaction_id <> credit_transaction_id)
+);
+
+CREATE INDEX idx_transfers_organization_id ON transfers(organization_id);
+
+-- Linking or unlinking changes income and spending of both legs' months.
+-- +goose StatementBegin
+CREATE FUNCTION prevent_closed_month_transfer_mutation()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_transfer transfers%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_transfer := OLD;
+    ELSE
+        row_transfer := NEW;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1
+        FROM transactions t
+        INNER JOIN accounts a ON a.account_id = t.account_id
+        INNER JOIN closed_months cm ON cm.organization_id = a.organization_id
+            AND cm.month = EXTRACT(MONTH FROM t.transaction_date)::INT
+            AND cm.year = EXTRACT(YEAR FROM t.transaction_date)::INT
+        WHERE t.transaction_id IN (row_transfer.debit_transaction_id, row_transfer.credit_transaction_id)
+    ) THEN
+        RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER transfers_reject_closed_month
+BEFORE INSERT OR DELETE ON transfers
+FOR EACH ROW EXECUTE FUNCTION prevent_closed_month_transfer_mutation();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS transfers_reject_closed_month ON transfers;
+DROP FUNCTION IF EXISTS prevent_closed_month_transfer_mutation();
+DROP TABLE IF EXISTS transfers CASCADE;
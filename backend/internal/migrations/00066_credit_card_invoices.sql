@@ -0,0 +1,74 @@
+-- +goose Up
+-- Credit card billing cycle. A purchase belongs to the invoice that closes on
+-- the first closing day on or after it; the invoice is due on due_day of the
+-- same month when due_day is after closing_day, otherwise of the next month.
+-- Days past the end of a short month fall on its last day.
+ALTER TABLE accounts
+    ADD COLUMN card_closing_day INT CHECK (card_closing_day BETWEEN 1 AND 31),
+    ADD COLUMN card_due_day INT CHECK (card_due_day BETWEEN 1 AND 31),
+    ADD COLUMN budget_by_invoice BOOLEAN NOT NULL DEFAULT FALSE, -- Budget card purchases in the invoice's due month
+    ADD CONSTRAINT accounts_card_billing_cycle_check CHECK (
+        (card_closing_day IS NULL) = (card_due_day IS NULL)
+        AND (card_closing_day IS NULL OR account_type = 'credit_card')
+        AND (NOT budget_by_invoice OR card_closing_day IS NOT NULL)
+    );
+
+-- +goose StatementBegin
+CREATE FUNCTION card_invoice_due_date(purchase_date DATE, closing_day INT, due_day INT)
+RETURNS DATE AS $$
+DECLARE
+    cycle_month DATE := DATE_TRUNC('month', purchase_date)::DATE;
+BEGIN
+    IF purchase_date > cycle_month + LEAST(closing_day, EXTRACT(DAY FROM cycle_month + INTERVAL '1 month - 1 day')::INT) - 1 THEN
+        cycle_month := (cycle_month + INTERVAL '1 month')::DATE;
+    END IF;
+    IF due_day <= closing_day THEN
+        cycle_month := (cycle_month + INTERVAL '1 month')::DATE;
+    END IF;
+    RETURN cycle_month + LEAST(due_day, EXTRACT(DAY FROM cycle_month + INTERVAL '1 month - 1 day')::INT) - 1;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+-- +goose StatementEnd
+
+-- The date a transaction counts on for budgets: the invoice due date on cards
+-- budgeted by invoice, the transaction date everywhere else.
+-- +goose StatementBegin
+CREATE FUNCTION transaction_budget_date(transaction_date DATE, budget_by_invoice BOOLEAN, closing_day INT, due_day INT)
+RETURNS DATE AS $$
+    SELECT CASE
+        WHEN budget_by_invoice AND closing_day IS NOT NULL THEN card_invoice_due_date(transaction_date, closing_day, due_day)
+        ELSE transaction_date
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+-- +goose StatementEnd
+
+-- Invoices themselves follow from the billing cycle; a row is only kept for
+-- what cannot be derived, the payment made from another account. Without one,
+-- transfers into the card after the closing date count as the payment.
+CREATE TABLE credit_card_invoices (
+    credit_card_invoice_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    organization_id INT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    account_id INT NOT NULL REFERENCES accounts(account_id) ON DELETE CASCADE,
+
+    month INT NOT NULL CHECK (month BETWEEN 1 AND 12), -- Month the invoice is due
+    year INT NOT NULL,
+
+    payment_transaction_id INT REFERENCES transactions(transaction_id) ON DELETE SET NULL,
+
+    UNIQUE (account_id, month, year)
+);
+
+CREATE INDEX idx_credit_card_invoices_organization_id ON credit_card_invoices(organization_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS credit_card_invoices CASCADE;
+DROP FUNCTION IF EXISTS transaction_budget_date(DATE, BOOLEAN, INT, INT);
+DROP FUNCTION IF EXISTS card_invoice_due_date(DATE, INT, INT);
+ALTER TABLE accounts
+    DROP CONSTRAINT IF EXISTS accounts_card_billing_cycle_check,
+    DROP COLUMN IF EXISTS budget_by_invoice,
+    DROP COLUMN IF EXISTS card_due_day,
+    DROP COLUMN IF EXISTS card_closing_day;
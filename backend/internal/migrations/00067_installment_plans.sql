@@ -0,0 +1,45 @@
+-- +goose Up
+-- An installment purchase ("LOJA X 03/10" on a card statement) is one plan
+-- with a parcel per month. A plan is identified by the card, the merchant,
+-- the number of parcels and the month of the first parcel.
+CREATE TABLE installment_plans (
+    installment_plan_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    organization_id INT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    account_id INT NOT NULL REFERENCES accounts(account_id) ON DELETE CASCADE,
+    category_id INT REFERENCES categories(category_id) ON DELETE SET NULL, -- Category future parcels are planned under
+
+    description VARCHAR(255) NOT NULL, -- Merchant, without the parcel suffix
+    installment_amount DECIMAL(15, 2) NOT NULL CHECK (installment_amount > 0),
+    installment_count INT NOT NULL CHECK (installment_count BETWEEN 2 AND 99),
+    first_month INT NOT NULL CHECK (first_month BETWEEN 1 AND 12), -- Budget month of parcel 1
+    first_year INT NOT NULL,
+
+    is_active BOOLEAN NOT NULL DEFAULT TRUE, -- Inactive plans keep their parcels but project nothing
+
+    UNIQUE (account_id, description, installment_count, first_month, first_year)
+);
+
+CREATE INDEX idx_installment_plans_organization_id ON installment_plans(organization_id);
+
+-- One row per known parcel: imported (transaction_id) or projected into a
+-- future month as a planned entry (planned_entry_id). A projected parcel is
+-- matched to its transaction when that is imported.
+CREATE TABLE installment_parcels (
+    installment_plan_id INT NOT NULL REFERENCES installment_plans(installment_plan_id) ON DELETE CASCADE,
+    installment_number INT NOT NULL CHECK (installment_number >= 1),
+
+    month INT NOT NULL CHECK (month BETWEEN 1 AND 12), -- Budget month the parcel falls in
+    year INT NOT NULL,
+
+    transaction_id INT UNIQUE REFERENCES transactions(transaction_id) ON DELETE SET NULL,
+    planned_entry_id INT UNIQUE REFERENCES planned_entries(planned_entry_id) ON DELETE SET NULL,
+
+    PRIMARY KEY (installment_plan_id, installment_number)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS installment_parcels CASCADE;
+DROP TABLE IF EXISTS installment_plans CASCADE;
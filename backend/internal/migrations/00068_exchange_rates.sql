@@ -0,0 +1,53 @@
+-- +goose Up
+-- Amounts are stored in the currency of their account; totals are reported in
+-- the organization's base currency.
+ALTER TABLE organizations
+    ADD COLUMN base_currency VARCHAR(3) NOT NULL DEFAULT 'BRL';
+
+-- Daily rates kept by each organization: one unit of currency is worth rate
+-- units of base_currency on rate_date. Rates are kept per base currency, so
+-- changing the base leaves the old quotes unused rather than wrong.
+CREATE TABLE exchange_rates (
+    organization_id INT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    rate_date DATE NOT NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    rate DECIMAL(20, 10) NOT NULL CHECK (rate > 0),
+    source VARCHAR(20) NOT NULL, -- upload, cli
+
+    PRIMARY KEY (organization_id, currency, base_currency, rate_date),
+    CHECK (currency <> base_currency)
+);
+
+-- An amount in from_currency converted to the organization's base currency at the
+-- latest rate on or before on_date. Amounts already in the base currency (or
+-- in accounts without one) come back as they are; NULL when no rate is known.
+-- +goose StatementBegin
+CREATE FUNCTION to_base_amount(amount DECIMAL, org_id INT, from_currency VARCHAR, on_date DATE)
+RETURNS DECIMAL AS $$
+    SELECT CASE
+        WHEN from_currency IS NULL OR UPPER(from_currency) = o.base_currency THEN amount
+        ELSE ROUND(amount * (
+            SELECT er.rate
+            FROM exchange_rates er
+            WHERE er.organization_id = o.organization_id
+                AND er.currency = UPPER(from_currency)
+                AND er.base_currency = o.base_currency
+                AND er.rate_date <= on_date
+            ORDER BY er.rate_date DESC
+            LIMIT 1
+        ), 2)
+    END
+    FROM organizations o
+    WHERE o.organization_id = org_id;
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION IF EXISTS to_base_amount(DECIMAL, INT, VARCHAR, DATE);
+DROP TABLE IF EXISTS exchange_rates CASCADE;
+ALTER TABLE organizations DROP COLUMN IF EXISTS base_currency;
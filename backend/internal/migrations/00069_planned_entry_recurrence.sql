@@ -0,0 +1,121 @@
+-- +goose Up
+-- Recurrence rules of recurrent planned entries. Existing entries keep their
+-- behaviour: monthly, every month, starting the month they were created.
+ALTER TABLE planned_entries
+    ADD COLUMN recurrence_frequency VARCHAR(10) NOT NULL DEFAULT 'monthly'
+        CHECK (recurrence_frequency IN ('weekly', 'monthly', 'yearly')),
+    ADD COLUMN recurrence_interval INT NOT NULL DEFAULT 1 CHECK (recurrence_interval > 0), -- Every N weeks/months/years
+    ADD COLUMN recurrence_months INT[], -- Only these months (1-12); for yearly entries, the months of the year it falls in
+    ADD COLUMN starts_on DATE, -- First occurrence; defaults to the creation date
+    ADD COLUMN ends_on DATE, -- No occurrences after this date
+    ADD COLUMN occurrence_count INT CHECK (occurrence_count > 0), -- Stops after this many occurrences
+    ADD CONSTRAINT planned_entries_recurrence_dates CHECK (ends_on IS NULL OR starts_on IS NULL OR ends_on >= starts_on);
+
+-- Whether a month (its first day) matches the rule of a monthly or yearly entry
+-- first occurring on anchor.
+-- +goose StatementBegin
+CREATE FUNCTION planned_entry_month_matches(pe planned_entries, anchor DATE, month_start DATE)
+RETURNS BOOLEAN AS $$
+    SELECT CASE pe.recurrence_frequency
+        WHEN 'yearly' THEN
+            (EXTRACT(YEAR FROM month_start) - EXTRACT(YEAR FROM anchor))::int % pe.recurrence_interval = 0
+            AND EXTRACT(MONTH FROM month_start)::int = ANY(
+                COALESCE(NULLIF(pe.recurrence_months, '{}'), ARRAY[EXTRACT(MONTH FROM anchor)::int])
+            )
+        ELSE
+            ((EXTRACT(YEAR FROM month_start) - EXTRACT(YEAR FROM anchor)) * 12
+                + EXTRACT(MONTH FROM month_start) - EXTRACT(MONTH FROM anchor))::int % pe.recurrence_interval = 0
+            AND (
+                pe.recurrence_months IS NULL OR cardinality(pe.recurrence_months) = 0
+                OR EXTRACT(MONTH FROM month_start)::int = ANY(pe.recurrence_months)
+            )
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+-- +goose StatementEnd
+
+-- How many times a planned entry falls in a budget month: 0 or 1, or the number
+-- of weeks a weekly entry falls on. One-off entries fall in their target month.
+-- Mirrors plannedEntryOccurrences in the financial service; in addition, a
+-- recurrent entry does not fall in a month it has an active instance for, as
+-- the instance stands in for it.
+-- +goose StatementBegin
+CREATE FUNCTION planned_entry_occurrences(pe planned_entries, for_month INT, for_year INT)
+RETURNS INT AS $$
+DECLARE
+    month_start DATE := make_date(for_year, for_month, 1);
+    last_day DATE := (make_date(for_year, for_month, 1) + INTERVAL '1 month')::date - 1;
+    anchor DATE := COALESCE(pe.starts_on, pe.created_at::date);
+    step INT;
+    first_k INT;
+    last_k INT;
+    candidate DATE;
+    seen INT := 0;
+BEGIN
+    IF NOT pe.is_recurrent THEN
+        RETURN CASE WHEN pe.target_month = for_month AND pe.target_year = for_year THEN 1 ELSE 0 END;
+    END IF;
+    IF EXISTS (
+        SELECT 1
+        FROM planned_entries instance
+        WHERE instance.parent_entry_id = pe.planned_entry_id
+            AND instance.is_active = true
+            AND instance.target_month = for_month
+            AND instance.target_year = for_year
+    ) THEN
+        RETURN 0;
+    END IF;
+    IF anchor IS NULL THEN
+        anchor := month_start;
+    END IF;
+    IF pe.ends_on IS NOT NULL AND pe.ends_on < last_day THEN
+        last_day := pe.ends_on;
+    END IF;
+
+    IF pe.recurrence_frequency = 'weekly' THEN
+        step := 7 * pe.recurrence_interval;
+        IF last_day < anchor THEN
+            RETURN 0;
+        END IF;
+        first_k := GREATEST(0, CEIL((month_start - anchor)::numeric / step)::int);
+        last_k := (last_day - anchor) / step;
+        IF pe.occurrence_count IS NOT NULL THEN
+            last_k := LEAST(last_k, pe.occurrence_count - 1);
+        END IF;
+        RETURN GREATEST(0, last_k - first_k + 1);
+    END IF;
+
+    IF last_day < month_start OR month_start < date_trunc('month', anchor)::date THEN
+        RETURN 0;
+    END IF;
+
+    -- Monthly and yearly entries: walk the months from the first one, counting
+    -- occurrences when the entry stops after a number of them
+    candidate := date_trunc('month', anchor)::date;
+    IF pe.occurrence_count IS NULL THEN
+        candidate := month_start;
+    END IF;
+    WHILE candidate <= month_start LOOP
+        IF planned_entry_month_matches(pe, anchor, candidate) THEN
+            seen := seen + 1;
+            IF candidate = month_start THEN
+                RETURN CASE WHEN pe.occurrence_count IS NULL OR seen <= pe.occurrence_count THEN 1 ELSE 0 END;
+            END IF;
+        END IF;
+        candidate := (candidate + INTERVAL '1 month')::date;
+    END LOOP;
+    RETURN 0;
+END;
+$$ LANGUAGE plpgsql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION IF EXISTS planned_entry_occurrences(planned_entries, INT, INT);
+DROP FUNCTION IF EXISTS planned_entry_month_matches(planned_entries, DATE, DATE);
+ALTER TABLE planned_entries
+    DROP CONSTRAINT IF EXISTS planned_entries_recurrence_dates,
+    DROP COLUMN IF EXISTS occurrence_count,
+    DROP COLUMN IF EXISTS ends_on,
+    DROP COLUMN IF EXISTS starts_on,
+    DROP COLUMN IF EXISTS recurrence_months,
+    DROP COLUMN IF EXISTS recurrence_interval,
+    DROP COLUMN IF EXISTS recurrence_frequency;
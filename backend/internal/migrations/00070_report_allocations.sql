@@ -0,0 +1,28 @@
+-- +goose Up
+-- What reports count: each transaction (or split) in the month it is budgeted
+-- in, in the organization's base currency. Ignored transactions and transfers
+-- between the organization's own accounts are left out.
+CREATE VIEW report_allocations AS
+SELECT
+    ta.transaction_id,
+    ta.split_id,
+    a.organization_id,
+    ta.account_id,
+    ta.transaction_date,
+    date_trunc('month', transaction_budget_date(ta.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day))::date AS budget_month,
+    ta.transaction_type,
+    ta.category_id,
+    t.description,
+    COALESCE(to_base_amount(ta.amount, a.organization_id, a.currency, ta.transaction_date), ta.amount) AS amount
+FROM transaction_allocations ta
+INNER JOIN accounts a ON a.account_id = ta.account_id
+INNER JOIN transactions t ON t.transaction_id = ta.transaction_id
+WHERE ta.is_ignored = false
+    AND NOT EXISTS (
+        SELECT 1 FROM transfers tr
+        WHERE tr.debit_transaction_id = ta.transaction_id
+            OR tr.credit_transaction_id = ta.transaction_id
+    );
+
+-- +goose Down
+DROP VIEW IF EXISTS report_allocations;
@@ -0,0 +1,47 @@
+-- +goose Up
+-- Who changed what in an organization's financial data. Each row records one
+-- write and, per changed field, its value before and after. Rows are never
+-- updated or deleted by the application; they only go away with their
+-- organization.
+CREATE TABLE audit_log (
+    audit_log_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    organization_id INT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(user_id) ON DELETE SET NULL,  -- NULL for scheduled jobs
+
+    entity_type VARCHAR(30) NOT NULL,  -- transaction, category_budget, pattern, planned_entry, savings_goal, month
+    entity_id INT NOT NULL,  -- YYYYMM for months
+    action VARCHAR(20) NOT NULL,  -- create, update, delete, close, reopen, ...
+    changes JSONB NOT NULL DEFAULT '{}'  -- {"field": {"before": ..., "after": ...}}
+);
+
+CREATE INDEX idx_audit_log_organization ON audit_log(organization_id, audit_log_id DESC);
+CREATE INDEX idx_audit_log_entity ON audit_log(organization_id, entity_type, entity_id, audit_log_id DESC);
+
+-- Direct updates and deletes are rejected; the cascades from deleting an
+-- organization or a user run nested in a foreign key trigger and pass.
+-- +goose StatementBegin
+CREATE FUNCTION prevent_audit_log_mutation()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF pg_trigger_depth() = 1 THEN
+        RAISE EXCEPTION 'audit log is append-only' USING ERRCODE = 'P0001';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION prevent_audit_log_mutation();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS prevent_audit_log_mutation();
+DROP TABLE IF EXISTS audit_log CASCADE;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Patterns were evaluated newest first. They get an explicit priority (lower
+-- = evaluated first, first match wins), numbered to keep the current order.
+ALTER TABLE patterns ADD COLUMN priority INT NOT NULL DEFAULT 100;
+
+UPDATE patterns p
+SET priority = ordered.position * 10
+FROM (
+    SELECT pattern_id,
+           ROW_NUMBER() OVER (PARTITION BY organization_id ORDER BY created_at DESC, pattern_id DESC) AS position
+    FROM patterns
+) ordered
+WHERE p.pattern_id = ordered.pattern_id;
+
+CREATE INDEX idx_patterns_priority ON patterns(organization_id, priority ASC, pattern_id DESC)
+WHERE is_active = true;
+
+-- The pattern that last categorized or ignored the transaction; cleared when
+-- someone recategorizes it by hand. A plain INT for the same reason as
+-- classification_rule_id (see 00059).
+ALTER TABLE transactions ADD COLUMN pattern_id INT;
+
+-- +goose Down
+ALTER TABLE transactions DROP COLUMN IF EXISTS pattern_id;
+DROP INDEX IF EXISTS idx_patterns_priority;
+ALTER TABLE patterns DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,69 @@
+-- +goose Up
+-- Closing a month inserts a CARRYOVER-YYYY-MM transaction into the checking
+-- account to carry the surplus into the next budget month. It moves no money,
+-- so it must not count towards the account balance; counting it added every
+-- closed month's surplus to the balance again.
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION apply_transaction_to_account_balance()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND (OLD.ofx_fitid IS NULL OR OLD.ofx_fitid NOT LIKE 'CARRYOVER-%') THEN
+        UPDATE accounts
+        SET balance = balance - CASE WHEN OLD.transaction_type = 'credit' THEN ABS(OLD.amount) ELSE -ABS(OLD.amount) END
+        WHERE account_id = OLD.account_id
+          AND (opening_balance_date IS NULL OR OLD.transaction_date >= opening_balance_date);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND (NEW.ofx_fitid IS NULL OR NEW.ofx_fitid NOT LIKE 'CARRYOVER-%') THEN
+        UPDATE accounts
+        SET balance = balance + CASE WHEN NEW.transaction_type = 'credit' THEN ABS(NEW.amount) ELSE -ABS(NEW.amount) END
+        WHERE account_id = NEW.account_id
+          AND (opening_balance_date IS NULL OR NEW.transaction_date >= opening_balance_date);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+UPDATE accounts a
+SET balance = a.opening_balance + COALESCE((
+    SELECT SUM(CASE WHEN t.transaction_type = 'credit' THEN ABS(t.amount) ELSE -ABS(t.amount) END)
+    FROM transactions t
+    WHERE t.account_id = a.account_id
+      AND t.transaction_date >= COALESCE(a.opening_balance_date, '-infinity'::DATE)
+      AND (t.ofx_fitid IS NULL OR t.ofx_fitid NOT LIKE 'CARRYOVER-%')
+), 0.00);
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION apply_transaction_to_account_balance()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE accounts
+        SET balance = balance - CASE WHEN OLD.transaction_type = 'credit' THEN ABS(OLD.amount) ELSE -ABS(OLD.amount) END
+        WHERE account_id = OLD.account_id
+          AND (opening_balance_date IS NULL OR OLD.transaction_date >= opening_balance_date);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE accounts
+        SET balance = balance + CASE WHEN NEW.transaction_type = 'credit' THEN ABS(NEW.amount) ELSE -ABS(NEW.amount) END
+        WHERE account_id = NEW.account_id
+          AND (opening_balance_date IS NULL OR NEW.transaction_date >= opening_balance_date);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+UPDATE accounts a
+SET balance = a.opening_balance + COALESCE((
+    SELECT SUM(CASE WHEN t.transaction_type = 'credit' THEN ABS(t.amount) ELSE -ABS(t.amount) END)
+    FROM transactions t
+    WHERE t.account_id = a.account_id
+      AND t.transaction_date >= COALESCE(a.opening_balance_date, '-infinity'::DATE)
+), 0.00);
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Closing a month locks its transactions, so the maintenance job only closes
+-- the previous month for organizations that ask for it.
+ALTER TABLE organizations
+    ADD COLUMN auto_close_months BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE organizations DROP COLUMN auto_close_months;
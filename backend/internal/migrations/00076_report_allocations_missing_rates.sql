@@ -0,0 +1,50 @@
+-- +goose Up
+-- Foreign-currency allocations without an exchange rate used to fall back to
+-- their unconverted amount; the amount is now NULL so reports can leave them
+-- out of totals and tell how many are missing a rate.
+CREATE OR REPLACE VIEW report_allocations AS
+SELECT
+    ta.transaction_id,
+    ta.split_id,
+    a.organization_id,
+    ta.account_id,
+    ta.transaction_date,
+    date_trunc('month', transaction_budget_date(ta.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day))::date AS budget_month,
+    ta.transaction_type,
+    ta.category_id,
+    t.description,
+    to_base_amount(ta.amount, a.organization_id, a.currency, ta.transaction_date) AS amount
+FROM transaction_allocations ta
+INNER JOIN accounts a ON a.account_id = ta.account_id
+INNER JOIN transactions t ON t.transaction_id = ta.transaction_id
+WHERE ta.is_ignored = false
+    AND (t.ofx_fitid IS NULL OR t.ofx_fitid NOT LIKE 'CARRYOVER-%')
+    AND NOT EXISTS (
+        SELECT 1 FROM transfers tr
+        WHERE tr.debit_transaction_id = ta.transaction_id
+            OR tr.credit_transaction_id = ta.transaction_id
+    );
+
+-- +goose Down
+CREATE OR REPLACE VIEW report_allocations AS
+SELECT
+    ta.transaction_id,
+    ta.split_id,
+    a.organization_id,
+    ta.account_id,
+    ta.transaction_date,
+    date_trunc('month', transaction_budget_date(ta.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day))::date AS budget_month,
+    ta.transaction_type,
+    ta.category_id,
+    t.description,
+    COALESCE(to_base_amount(ta.amount, a.organization_id, a.currency, ta.transaction_date), ta.amount) AS amount
+FROM transaction_allocations ta
+INNER JOIN accounts a ON a.account_id = ta.account_id
+INNER JOIN transactions t ON t.transaction_id = ta.transaction_id
+WHERE ta.is_ignored = false
+    AND (t.ofx_fitid IS NULL OR t.ofx_fitid NOT LIKE 'CARRYOVER-%')
+    AND NOT EXISTS (
+        SELECT 1 FROM transfers tr
+        WHERE tr.debit_transaction_id = ta.transaction_id
+            OR tr.credit_transaction_id = ta.transaction_id
+    );
@@ -0,0 +1,243 @@
+-- +goose Up
+-- Closed months are budget months: a card budgeted by invoice counts its
+-- purchases in the month the invoice is due, so that is the month checked.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION prevent_closed_month_transaction_mutation()
+RETURNS TRIGGER AS $$
+DECLARE
+    old_account accounts%ROWTYPE;
+    new_account accounts%ROWTYPE;
+    budget_date DATE;
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        SELECT * INTO old_account FROM accounts WHERE account_id = OLD.account_id;
+        budget_date := transaction_budget_date(OLD.transaction_date, old_account.budget_by_invoice, old_account.card_closing_day, old_account.card_due_day);
+        IF EXISTS (
+            SELECT 1 FROM closed_months
+            WHERE organization_id = old_account.organization_id
+              AND month = EXTRACT(MONTH FROM budget_date)::INT
+              AND year = EXTRACT(YEAR FROM budget_date)::INT
+        ) THEN
+            RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        SELECT * INTO new_account FROM accounts WHERE account_id = NEW.account_id;
+        budget_date := transaction_budget_date(NEW.transaction_date, new_account.budget_by_invoice, new_account.card_closing_day, new_account.card_due_day);
+        IF EXISTS (
+            SELECT 1 FROM closed_months
+            WHERE organization_id = new_account.organization_id
+              AND month = EXTRACT(MONTH FROM budget_date)::INT
+              AND year = EXTRACT(YEAR FROM budget_date)::INT
+        ) THEN
+            RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+        END IF;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION prevent_closed_month_split_mutation()
+RETURNS TRIGGER AS $$
+DECLARE
+    split_transaction_id INT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        split_transaction_id := OLD.transaction_id;
+    ELSE
+        split_transaction_id := NEW.transaction_id;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1
+        FROM transactions t
+        INNER JOIN accounts a ON a.account_id = t.account_id
+        INNER JOIN closed_months cm ON cm.organization_id = a.organization_id
+            AND make_date(cm.year, cm.month, 1) = date_trunc('month', transaction_budget_date(t.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day))::date
+        WHERE t.transaction_id = split_transaction_id
+    ) THEN
+        RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION prevent_closed_month_transfer_mutation()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_transfer transfers%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_transfer := OLD;
+    ELSE
+        row_transfer := NEW;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1
+        FROM transactions t
+        INNER JOIN accounts a ON a.account_id = t.account_id
+        INNER JOIN closed_months cm ON cm.organization_id = a.organization_id
+            AND make_date(cm.year, cm.month, 1) = date_trunc('month', transaction_budget_date(t.transaction_date, a.budget_by_invoice, a.card_closing_day, a.card_due_day))::date
+        WHERE t.transaction_id IN (row_transfer.debit_transaction_id, row_transfer.credit_transaction_id)
+    ) THEN
+        RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- Changing a card's billing cycle moves its purchases between budget months,
+-- which must not reach into a closed one.
+-- +goose StatementBegin
+CREATE FUNCTION prevent_closed_month_billing_cycle_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1
+        FROM transactions t
+        CROSS JOIN LATERAL (
+            SELECT
+                date_trunc('month', transaction_budget_date(t.transaction_date, OLD.budget_by_invoice, OLD.card_closing_day, OLD.card_due_day))::date AS old_month,
+                date_trunc('month', transaction_budget_date(t.transaction_date, NEW.budget_by_invoice, NEW.card_closing_day, NEW.card_due_day))::date AS new_month
+        ) m
+        INNER JOIN closed_months cm ON cm.organization_id = NEW.organization_id
+            AND make_date(cm.year, cm.month, 1) IN (m.old_month, m.new_month)
+        WHERE t.account_id = NEW.account_id
+            AND m.old_month <> m.new_month
+    ) THEN
+        RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER accounts_reject_closed_month_billing_cycle
+BEFORE UPDATE OF budget_by_invoice, card_closing_day, card_due_day ON accounts
+FOR EACH ROW EXECUTE FUNCTION prevent_closed_month_billing_cycle_change();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS accounts_reject_closed_month_billing_cycle ON accounts;
+DROP FUNCTION IF EXISTS prevent_closed_month_billing_cycle_change();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION prevent_closed_month_transaction_mutation()
+RETURNS TRIGGER AS $$
+DECLARE
+    old_organization_id INT;
+    new_organization_id INT;
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        SELECT organization_id INTO old_organization_id FROM accounts WHERE account_id = OLD.account_id;
+        IF EXISTS (
+            SELECT 1 FROM closed_months
+            WHERE organization_id = old_organization_id
+              AND month = EXTRACT(MONTH FROM OLD.transaction_date)::INT
+              AND year = EXTRACT(YEAR FROM OLD.transaction_date)::INT
+        ) THEN
+            RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        SELECT organization_id INTO new_organization_id FROM accounts WHERE account_id = NEW.account_id;
+        IF EXISTS (
+            SELECT 1 FROM closed_months
+            WHERE organization_id = new_organization_id
+              AND month = EXTRACT(MONTH FROM NEW.transaction_date)::INT
+              AND year = EXTRACT(YEAR FROM NEW.transaction_date)::INT
+        ) THEN
+            RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+        END IF;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION prevent_closed_month_split_mutation()
+RETURNS TRIGGER AS $$
+DECLARE
+    split_transaction_id INT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        split_transaction_id := OLD.transaction_id;
+    ELSE
+        split_transaction_id := NEW.transaction_id;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1
+        FROM transactions t
+        INNER JOIN accounts a ON a.account_id = t.account_id
+        INNER JOIN closed_months cm ON cm.organization_id = a.organization_id
+            AND cm.month = EXTRACT(MONTH FROM t.transaction_date)::INT
+            AND cm.year = EXTRACT(YEAR FROM t.transaction_date)::INT
+        WHERE t.transaction_id = split_transaction_id
+    ) THEN
+        RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION prevent_closed_month_transfer_mutation()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_transfer transfers%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_transfer := OLD;
+    ELSE
+        row_transfer := NEW;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1
+        FROM transactions t
+        INNER JOIN accounts a ON a.account_id = t.account_id
+        INNER JOIN closed_months cm ON cm.organization_id = a.organization_id
+            AND cm.month = EXTRACT(MONTH FROM t.transaction_date)::INT
+            AND cm.year = EXTRACT(YEAR FROM t.transaction_date)::INT
+        WHERE t.transaction_id IN (row_transfer.debit_transaction_id, row_transfer.credit_transaction_id)
+    ) THEN
+        RAISE EXCEPTION 'month is closed' USING ERRCODE = 'P0001';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Sessions read their roles and permissions again every 30 seconds, so grants
+-- such as the ones in 00058 reach sessions created before them without a new
+-- login.
+COMMENT ON TABLE role_permissions IS 'Open sessions read these again every 30 seconds; changes apply without a new login';
+
+-- +goose Down
+COMMENT ON TABLE role_permissions IS NULL;
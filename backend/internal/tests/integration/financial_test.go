@@ -77,7 +77,7 @@ func (test *FinancialTestSuite) TestPatternSchema_IgnoreActionHasNoTargetsAndCan
 		"Ignore Pattern Org",
 	)
 
-	patternService := financial.New(test.financialRepo, test.System, test.Logger, test.DB, nil)
+	patternService := financial.New(test.financialRepo, test.System, test.Logger, test.DB, nil, nil, nil)
 	pattern, err := patternService.CreatePattern(ctx, financial.CreatePatternInput{
 		UserID:             auth.GetUserID(),
 		OrganizationID:     auth.GetOrganizationID(),
@@ -326,3 +326,38 @@ func (test *FinancialTestSuite) TestPlannedEntry_SetPatternID_FromNull() {
 	test.Require().NotNil(updatedEntry.PatternID, "pattern_id should be set after update")
 	test.Equal(patternID, *updatedEntry.PatternID)
 }
+
+// Regression test: the pattern evaluation order (priority, then newest) was
+// also written into the account, planned entry and month reopening queries,
+// whose tables have no priority column, failing every list call at runtime.
+func (test *FinancialTestSuite) TestPatternPriorityOrder_RunsAgainstSchema() {
+	ctx := context.Background()
+	auth := test.CreateUserAndAuthenticate("pattern-order@example.com", "Pattern Order User", "Pattern Order Org")
+	userID := auth.GetUserID()
+	orgID := auth.GetOrganizationID()
+
+	var patternIDs []int
+	for _, priority := range []int{30, 10, 10} {
+		var patternID int
+		err := test.DB.Query(ctx, &patternID, `
+			INSERT INTO patterns (user_id, organization_id, action, description_pattern, priority)
+			VALUES ($1, $2, 'ignore', '.*TARIFA.*', $3)
+			RETURNING pattern_id
+		`, userID, orgID, priority)
+		test.Require().NoError(err)
+		patternIDs = append(patternIDs, patternID)
+	}
+
+	service := financial.New(test.financialRepo, test.System, test.Logger, test.DB, nil, nil, nil)
+	patterns, err := service.GetPatterns(ctx, financial.GetPatternsInput{UserID: userID, OrganizationID: orgID})
+	test.Require().NoError(err)
+	test.Require().Len(patterns, 3)
+	test.Equal([]int{patternIDs[2], patternIDs[1], patternIDs[0]}, []int{patterns[0].PatternID, patterns[1].PatternID, patterns[2].PatternID})
+
+	_, err = service.GetAccounts(ctx, financial.GetAccountsInput{UserID: userID, OrganizationID: orgID})
+	test.Require().NoError(err)
+	_, err = service.GetPlannedEntries(ctx, financial.GetPlannedEntriesInput{UserID: userID, OrganizationID: orgID})
+	test.Require().NoError(err)
+	_, err = service.GetMonthReopenings(ctx, financial.GetMonthReopeningsInput{OrganizationID: orgID})
+	test.Require().NoError(err)
+}
@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -21,6 +22,7 @@ type OrganizationHandler interface {
 	GetPendingInvites(w http.ResponseWriter, r *http.Request)
 	CancelOrganizationInvite(w http.ResponseWriter, r *http.Request)
 	AcceptOrganizationInvite(w http.ResponseWriter, r *http.Request)
+	ExportOrganization(w http.ResponseWriter, r *http.Request)
 }
 
 // GetOrganizationMembers
@@ -310,3 +312,30 @@ func (h *handler) AcceptOrganizationInvite(w http.ResponseWriter, r *http.Reques
 	response := AuthenticateResponse{}.FromDTO(authResult)
 	responses.NewSuccess(response, w)
 }
+
+// ExportOrganization
+
+// ExportOrganization downloads the organization's data as a ZIP archive that
+// the restoreOrganization command can load into another organization.
+func (h *handler) ExportOrganization(w http.ResponseWriter, r *http.Request) {
+	orgIDStr := chi.URLParam(r, "orgId")
+	orgID, err := strconv.Atoi(orgIDStr)
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidFormat)
+		return
+	}
+
+	output, err := h.accountsService.ExportOrganization(r.Context(), accounts.ExportOrganizationInput{
+		OrganizationID: orgID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("organization-%d-%s.zip", orgID, output.Manifest.ExportedAt.Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(output.Data)
+}
@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/catrutech/celeiro/internal/application"
@@ -213,9 +214,17 @@ func (h *Handler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Archived accounts have is_active=false; omit the filter to list both
+	var isActive *bool
+	if activeStr := r.URL.Query().Get("is_active"); activeStr != "" {
+		b := activeStr == "true"
+		isActive = &b
+	}
+
 	accounts, err := h.app.FinancialService.GetAccounts(r.Context(), financialApp.GetAccountsInput{
 		UserID:         userID,
 		OrganizationID: organizationID,
+		IsActive:       isActive,
 	})
 	if err != nil {
 		responses.NewError(w, err)
@@ -238,6 +247,12 @@ func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		BankName    string  `json:"bank_name"`
 		Balance     float64 `json:"balance"`
 		Currency    string  `json:"currency"`
+
+		OpeningBalanceDate *string `json:"opening_balance_date"`
+
+		CardClosingDay  *int `json:"card_closing_day"`
+		CardDueDay      *int `json:"card_due_day"`
+		BudgetByInvoice bool `json:"budget_by_invoice"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -253,6 +268,12 @@ func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		BankName:       req.BankName,
 		Balance:        decimal.NewFromFloat(req.Balance),
 		Currency:       req.Currency,
+
+		OpeningBalanceDate: req.OpeningBalanceDate,
+
+		CardClosingDay:  req.CardClosingDay,
+		CardDueDay:      req.CardDueDay,
+		BudgetByInvoice: req.BudgetByInvoice,
 	})
 	if err != nil {
 		responses.NewError(w, err)
@@ -288,103 +309,179 @@ func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	responses.NewSuccess(account, w)
 }
 
-// ============================================================================
-// Pluggy
-// ============================================================================
-
-func (h *Handler) CreatePluggyConnectToken(w http.ResponseWriter, r *http.Request) {
+// UpdateAccount edits an account. Setting is_active to false archives it:
+// archived accounts keep their history but are no longer offered as import
+// targets.
+func (h *Handler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	if h.app.PluggyClient == nil {
-		responses.NewError(w, fmt.Errorf("pluggy client is not available"))
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	token, err := h.app.PluggyClient.CreateConnectToken(r.Context(), pluggy.CreateConnectTokenInput{
-		ClientUserID: fmt.Sprintf("celeiro:%d:%d", organizationID, userID),
-		Language:     "pt",
+	var req struct {
+		Name               *string  `json:"name"`
+		BankName           *string  `json:"bank_name"`
+		OpeningBalance     *float64 `json:"opening_balance"`
+		OpeningBalanceDate *string  `json:"opening_balance_date"`
+		IsActive           *bool    `json:"is_active"`
+		CardClosingDay     *int     `json:"card_closing_day"`
+		CardDueDay         *int     `json:"card_due_day"`
+		BudgetByInvoice    *bool    `json:"budget_by_invoice"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var openingBalance *decimal.Decimal
+	if req.OpeningBalance != nil {
+		amount := decimal.NewFromFloat(*req.OpeningBalance)
+		openingBalance = &amount
+	}
+
+	account, err := h.app.FinancialService.UpdateAccount(r.Context(), financialApp.UpdateAccountInput{
+		AccountID:          accountID,
+		UserID:             userID,
+		OrganizationID:     organizationID,
+		Name:               req.Name,
+		BankName:           req.BankName,
+		OpeningBalance:     openingBalance,
+		OpeningBalanceDate: req.OpeningBalanceDate,
+		IsActive:           req.IsActive,
+		CardClosingDay:     req.CardClosingDay,
+		CardDueDay:         req.CardDueDay,
+		BudgetByInvoice:    req.BudgetByInvoice,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(token, w)
+	responses.NewSuccess(account, w)
 }
 
-func (h *Handler) ListPluggyConnectors(w http.ResponseWriter, r *http.Request) {
-	if _, _, err := h.getSessionInfo(r); err != nil {
+// DeleteAccount deletes an account and its transactions, or moves the
+// transactions first with ?move_transactions_to={accountId}.
+func (h *Handler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	connectors, err := h.app.PluggyClient.ListConnectors(r.Context())
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var moveTransactionsTo *int
+	if targetStr := r.URL.Query().Get("move_transactions_to"); targetStr != "" {
+		target, err := strconv.Atoi(targetStr)
+		if err != nil {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+		moveTransactionsTo = &target
+	}
+
+	err = h.app.FinancialService.DeleteAccount(r.Context(), financialApp.DeleteAccountInput{
+		AccountID:          accountID,
+		UserID:             userID,
+		OrganizationID:     organizationID,
+		MoveTransactionsTo: moveTransactionsTo,
+	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(connectors, w)
+	responses.NewSuccess(map[string]string{"message": "account deleted successfully"}, w)
 }
 
-func (h *Handler) ListPluggyAccountsByItem(w http.ResponseWriter, r *http.Request) {
-	if _, _, err := h.getSessionInfo(r); err != nil {
+// GetAccountReconciliation compares recorded statement balances with the
+// computed balance and shows where they first diverge.
+func (h *Handler) GetAccountReconciliation(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	itemID := chi.URLParam(r, "itemId")
-	if itemID == "" {
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	accounts, err := h.app.PluggyClient.ListAccountsByItem(r.Context(), itemID)
+	report, err := h.app.FinancialService.GetAccountReconciliation(r.Context(), financialApp.GetAccountReconciliationInput{
+		AccountID:      accountID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(accounts, w)
+	responses.NewSuccess(report, w)
 }
 
-func (h *Handler) ListPluggyTransactionsByAccount(w http.ResponseWriter, r *http.Request) {
-	if _, _, err := h.getSessionInfo(r); err != nil {
+// RecordStatementBalance reconciles the account against a balance typed in
+// from a bank statement.
+func (h *Handler) RecordStatementBalance(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	accountID := chi.URLParam(r, "accountId")
-	if accountID == "" {
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	from, to, err := pluggy.QueryDateRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"), time.Now())
-	if err != nil {
-		responses.NewError(w, err)
+	var req struct {
+		BalanceDate string  `json:"balance_date"`
+		Balance     float64 `json:"balance"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	transactions, err := h.app.PluggyClient.ListTransactionsByAccount(r.Context(), accountID, from, to)
+	checkpoint, err := h.app.FinancialService.RecordStatementBalance(r.Context(), financialApp.RecordStatementBalanceInput{
+		AccountID:      accountID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		BalanceDate:    req.BalanceDate,
+		Balance:        decimal.NewFromFloat(req.Balance),
+	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(transactions, w)
+	responses.NewSuccess(checkpoint, w)
 }
 
 // ============================================================================
-// Transactions
+// Credit Card Invoices
 // ============================================================================
 
-func (h *Handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
-	_, organizationID, err := h.getSessionInfo(r)
+// ListCreditCardInvoices lists a card's invoices due in ?year= (default: the
+// current year), without their transactions.
+func (h *Handler) ListCreditCardInvoices(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
@@ -396,683 +493,2655 @@ func (h *Handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse query parameters
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if limit == 0 {
-		limit = 1000 // Increased default limit to show more transactions
+	var year int
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		year, err = strconv.Atoi(yearStr)
+		if err != nil {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
 	}
 
-	transactions, err := h.app.FinancialService.GetTransactions(r.Context(), financialApp.GetTransactionsInput{
+	invoices, err := h.app.FinancialService.GetCreditCardInvoices(r.Context(), financialApp.GetCreditCardInvoicesInput{
 		AccountID:      accountID,
+		UserID:         userID,
 		OrganizationID: organizationID,
-		Limit:          limit,
-		Offset:         offset,
+		Year:           year,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(transactions, w)
+	responses.NewSuccess(invoices, w)
 }
 
-func (h *Handler) ListUncategorizedTransactions(w http.ResponseWriter, r *http.Request) {
-	_, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) GetCreditCardInvoice(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Parse query parameters
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if limit == 0 {
-		limit = 1000
+	accountID, month, year, err := parseInvoiceURLParams(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	transactions, err := h.app.FinancialService.GetUncategorizedTransactions(r.Context(), financialApp.GetUncategorizedTransactionsInput{
+	invoice, err := h.app.FinancialService.GetCreditCardInvoice(r.Context(), financialApp.GetCreditCardInvoiceInput{
+		AccountID:      accountID,
+		UserID:         userID,
 		OrganizationID: organizationID,
-		Limit:          limit,
-		Offset:         offset,
+		Month:          month,
+		Year:           year,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(transactions, w)
+	responses.NewSuccess(invoice, w)
 }
 
-func (h *Handler) ImportOFX(w http.ResponseWriter, r *http.Request) {
+// SetCreditCardInvoicePayment links the transaction that paid an invoice; a
+// null payment_transaction_id unlinks it.
+func (h *Handler) SetCreditCardInvoicePayment(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	accountID, month, year, err := parseInvoiceURLParams(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	// Parse multipart form
-	err = r.ParseMultipartForm(10 << 20) // 10 MB max
-	if err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
+	var req struct {
+		PaymentTransactionID *int `json:"payment_transaction_id"`
 	}
-
-	file, _, err := r.FormFile("ofx_file")
-	if err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
-	defer file.Close()
 
-	// Read file content (must read until EOF; a single Read() can truncate)
-	fileBytes, err := io.ReadAll(io.LimitReader(file, 10<<20))
+	invoice, err := h.app.FinancialService.SetCreditCardInvoicePayment(r.Context(), financialApp.SetCreditCardInvoicePaymentInput{
+		AccountID:            accountID,
+		UserID:               userID,
+		OrganizationID:       organizationID,
+		Month:                month,
+		Year:                 year,
+		PaymentTransactionID: req.PaymentTransactionID,
+	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	// Import transactions from OFX
-	result, err := h.app.FinancialService.ImportTransactionsFromOFX(r.Context(), financialApp.ImportOFXInput{
-		AccountID:      accountID,
-		UserID:         userID,
-		OrganizationID: organizationID,
-		OFXData:        fileBytes,
-	})
+	responses.NewSuccess(invoice, w)
+}
+
+func parseInvoiceURLParams(r *http.Request) (int, int, int, error) {
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
 	if err != nil {
-		responses.NewError(w, err)
-		return
+		return 0, 0, 0, err
 	}
-
-	responses.NewSuccess(result, w)
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	month, err := strconv.Atoi(chi.URLParam(r, "month"))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return accountID, month, year, nil
 }
 
-func (h *Handler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
+// ============================================================================
+// Pluggy
+// ============================================================================
+
+func (h *Handler) CreatePluggyConnectToken(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
-	if err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+	if h.app.PluggyClient == nil {
+		responses.NewError(w, fmt.Errorf("pluggy client is not available"))
 		return
 	}
 
-	// Verify account ownership
-	_, err = h.app.FinancialService.GetAccountByID(r.Context(), financialApp.GetAccountByIDInput{
-		AccountID:      accountID,
-		UserID:         userID,
-		OrganizationID: organizationID,
+	token, err := h.app.PluggyClient.CreateConnectToken(r.Context(), pluggy.CreateConnectTokenInput{
+		ClientUserID: fmt.Sprintf("celeiro:%d:%d", organizationID, userID),
+		Language:     "pt",
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	var req struct {
-		Description     string  `json:"description"`
-		Amount          float64 `json:"amount"`
-		TransactionDate string  `json:"transaction_date"`
-		TransactionType string  `json:"transaction_type"`
-		CategoryID      *int    `json:"category_id,omitempty"`
-		Notes           *string `json:"notes,omitempty"`
-	}
+	responses.NewSuccess(token, w)
+}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+func (h *Handler) ListPluggyConnectors(w http.ResponseWriter, r *http.Request) {
+	if _, _, err := h.getSessionInfo(r); err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Validation
-	if req.Description == "" {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-	if req.Amount <= 0 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+	connectors, err := h.app.PluggyClient.ListConnectors(r.Context())
+	if err != nil {
+		responses.NewError(w, err)
 		return
 	}
-	if req.TransactionDate == "" {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+
+	responses.NewSuccess(connectors, w)
+}
+
+func (h *Handler) ListPluggyAccountsByItem(w http.ResponseWriter, r *http.Request) {
+	if _, _, err := h.getSessionInfo(r); err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
-	if req.TransactionType != "debit" && req.TransactionType != "credit" {
+
+	itemID := chi.URLParam(r, "itemId")
+	if itemID == "" {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	// Convert notes to string
-	notes := ""
-	if req.Notes != nil {
-		notes = *req.Notes
-	}
-
-	transaction, err := h.app.FinancialService.CreateTransaction(r.Context(), financialApp.CreateTransactionInput{
-		AccountID:       accountID,
-		UserID:          userID,
-		OrganizationID:  organizationID,
-		CategoryID:      req.CategoryID,
-		Description:     req.Description,
-		Amount:          decimal.NewFromFloat(req.Amount),
-		TransactionDate: req.TransactionDate,
-		TransactionType: req.TransactionType,
-		Notes:           notes,
-	})
+	accounts, err := h.app.PluggyClient.ListAccountsByItem(r.Context(), itemID)
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(transaction, w)
+	responses.NewSuccess(accounts, w)
 }
 
-func (h *Handler) UpdateTransaction(w http.ResponseWriter, r *http.Request) {
-	_, organizationID, err := h.getSessionInfo(r)
-	if err != nil {
+func (h *Handler) ListPluggyTransactionsByAccount(w http.ResponseWriter, r *http.Request) {
+	if _, _, err := h.getSessionInfo(r); err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	transactionID, err := strconv.Atoi(chi.URLParam(r, "transactionId"))
-	if err != nil {
+	accountID := chi.URLParam(r, "accountId")
+	if accountID == "" {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	var req struct {
-		CategoryID  *int     `json:"category_id"`
-		Description *string  `json:"description"`
-		Amount      *float64 `json:"amount"`
-		Notes       *string  `json:"notes"`
-		IsIgnored   *bool    `json:"is_ignored"`
-		NeedsReview *bool    `json:"needs_review"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+	from, to, err := pluggy.QueryDateRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"), time.Now())
+	if err != nil {
+		responses.NewError(w, err)
 		return
 	}
 
-	// Convert amount to decimal if provided
-	var amountDecimal *decimal.Decimal
-	if req.Amount != nil {
-		amt := decimal.NewFromFloat(*req.Amount)
-		amountDecimal = &amt
-	}
-
-	transaction, err := h.app.FinancialService.UpdateTransaction(r.Context(), financialApp.UpdateTransactionInput{
-		TransactionID:  transactionID,
-		OrganizationID: organizationID,
-		CategoryID:     req.CategoryID,
-		Description:    req.Description,
-		Amount:         amountDecimal,
-		Notes:          req.Notes,
-		IsIgnored:      req.IsIgnored,
-		NeedsReview:    req.NeedsReview,
-	})
+	transactions, err := h.app.PluggyClient.ListTransactionsByAccount(r.Context(), accountID, from, to)
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(transaction, w)
+	responses.NewSuccess(transactions, w)
 }
 
-// ============================================================================
-// Budget Pacing
-// ============================================================================
-
-// GetControllableCategoryPacing returns budget pacing data for controllable categories.
-// Query params: month (1-12), year (YYYY). Defaults to current month if not provided.
-func (h *Handler) GetControllableCategoryPacing(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+// ListPluggyAccountLinks returns the linked Pluggy accounts with their sync status.
+func (h *Handler) ListPluggyAccountLinks(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Parse optional month/year query params, default to current month
-	now := time.Now()
-	month := now.Month()
-	year := now.Year()
-
-	if monthParam := r.URL.Query().Get("month"); monthParam != "" {
-		m, err := strconv.Atoi(monthParam)
-		if err != nil || m < 1 || m > 12 {
-			responses.NewError(w, errors.ErrInvalidRequestBody)
-			return
-		}
-		month = time.Month(m)
-	}
-
-	if yearParam := r.URL.Query().Get("year"); yearParam != "" {
-		y, err := strconv.Atoi(yearParam)
-		if err != nil || y < 2000 || y > 2100 {
+	var accountID *int
+	if raw := r.URL.Query().Get("account_id"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
 			responses.NewError(w, errors.ErrInvalidRequestBody)
 			return
 		}
-		year = y
+		accountID = &parsed
 	}
 
-	pacing, err := h.app.FinancialService.GetControllableCategoryPacing(r.Context(), financialApp.GetControllableCategoryPacingInput{
-		UserID:         userID,
+	links, err := h.app.FinancialService.GetPluggyAccountLinks(r.Context(), financialApp.GetPluggyAccountLinksInput{
 		OrganizationID: organizationID,
-		Month:          int(month),
-		Year:           year,
+		AccountID:      accountID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(pacing, w)
+	responses.NewSuccess(links, w)
 }
 
-// ============================================================================
-// Category Budgets
-// ============================================================================
-
-func (h *Handler) ListCategoryBudgets(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) CreatePluggyAccountLink(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Parse optional query parameters
-	var month, year, categoryID *int
-	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
-		m, err := strconv.Atoi(monthStr)
-		if err == nil {
-			month = &m
-		}
-	}
-	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
-		y, err := strconv.Atoi(yearStr)
-		if err == nil {
-			year = &y
-		}
+	var req struct {
+		AccountID       int    `json:"account_id"`
+		PluggyItemID    string `json:"pluggy_item_id"`
+		PluggyAccountID string `json:"pluggy_account_id"`
 	}
-	if catStr := r.URL.Query().Get("category_id"); catStr != "" {
-		c, err := strconv.Atoi(catStr)
-		if err == nil {
-			categoryID = &c
-		}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	budgets, err := h.app.FinancialService.GetCategoryBudgets(r.Context(), financialApp.GetCategoryBudgetsInput{
-		UserID:         userID,
-		OrganizationID: organizationID,
-		Month:          month,
-		Year:           year,
-		CategoryID:     categoryID,
+	link, err := h.app.FinancialService.LinkPluggyAccount(r.Context(), financialApp.LinkPluggyAccountInput{
+		UserID:          userID,
+		OrganizationID:  organizationID,
+		AccountID:       req.AccountID,
+		PluggyItemID:    req.PluggyItemID,
+		PluggyAccountID: req.PluggyAccountID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(budgets, w)
+	responses.NewSuccess(link, w)
 }
 
-func (h *Handler) GetCategoryBudget(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) DeletePluggyAccountLink(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	budgetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	linkID, err := strconv.Atoi(chi.URLParam(r, "linkId"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	budget, err := h.app.FinancialService.GetCategoryBudgetByID(r.Context(), financialApp.GetCategoryBudgetByIDInput{
-		CategoryBudgetID: budgetID,
-		UserID:           userID,
-		OrganizationID:   organizationID,
+	err = h.app.FinancialService.UnlinkPluggyAccount(r.Context(), financialApp.UnlinkPluggyAccountInput{
+		PluggyAccountLinkID: linkID,
+		OrganizationID:      organizationID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(budget, w)
+	responses.NewSuccess(map[string]string{"message": "pluggy account unlinked successfully"}, w)
 }
 
-func (h *Handler) CreateCategoryBudget(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) SyncPluggyAccountLink(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	var req struct {
-		CategoryID       int     `json:"category_id"`
-		Month            int     `json:"month"`
-		Year             int     `json:"year"`
-		ControlledAmount float64 `json:"controlled_amount"`
-		Granularity      *int    `json:"granularity,omitempty"`
+	linkID, err := strconv.Atoi(chi.URLParam(r, "linkId"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+	result, err := h.app.FinancialService.SyncPluggyAccount(r.Context(), financialApp.SyncPluggyAccountInput{
+		PluggyAccountLinkID: linkID,
+		OrganizationID:      organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
 		return
 	}
-	if req.Granularity != nil && *req.Granularity < 2 {
+
+	responses.NewSuccess(result, w)
+}
+
+// ============================================================================
+// CSV Import Profiles
+// ============================================================================
+
+type csvImportProfileRequest struct {
+	Name               *string  `json:"name"`
+	Delimiter          *string  `json:"delimiter"`
+	SkipRows           *int     `json:"skip_rows"`
+	DateColumn         *string  `json:"date_column"`
+	DateFormat         *string  `json:"date_format"`
+	DescriptionColumns []string `json:"description_columns"`
+	IDColumn           *string  `json:"id_column"`
+	AmountColumn       *string  `json:"amount_column"`
+	DebitColumn        *string  `json:"debit_column"`
+	CreditColumn       *string  `json:"credit_column"`
+	DecimalSeparator   *string  `json:"decimal_separator"`
+	SignConvention     *string  `json:"sign_convention"`
+}
+
+func (h *Handler) ListCSVImportProfiles(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	budget, err := h.app.FinancialService.CreateCategoryBudget(r.Context(), financialApp.CreateCategoryBudgetInput{
-		UserID:           userID,
-		OrganizationID:   organizationID,
-		CategoryID:       req.CategoryID,
-		Month:            req.Month,
-		Year:             req.Year,
-		ControlledAmount: decimal.NewFromFloat(req.ControlledAmount),
-		Granularity:      req.Granularity,
+	profiles, err := h.app.FinancialService.GetCSVImportProfiles(r.Context(), financialApp.GetCSVImportProfilesInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		AccountID:      accountID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(budget, w)
+	responses.NewSuccess(profiles, w)
 }
 
-func (h *Handler) UpdateCategoryBudget(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) CreateCSVImportProfile(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	budgetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	var req struct {
-		ControlledAmount *float64        `json:"controlled_amount,omitempty"`
-		Granularity      json.RawMessage `json:"granularity,omitempty"`
-	}
-
+	var req csvImportProfileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	var controlledAmount *decimal.Decimal
-	if req.ControlledAmount != nil {
-		amt := decimal.NewFromFloat(*req.ControlledAmount)
-		controlledAmount = &amt
-	}
-	var granularity *int
-	granularitySet := req.Granularity != nil
-	if granularitySet && string(req.Granularity) != "null" {
-		var value int
-		if err := json.Unmarshal(req.Granularity, &value); err != nil || value < 2 {
-			responses.NewError(w, errors.ErrInvalidRequestBody)
-			return
+	value := func(s *string) string {
+		if s == nil {
+			return ""
 		}
-		granularity = &value
+		return *s
+	}
+	skipRows := 0
+	if req.SkipRows != nil {
+		skipRows = *req.SkipRows
 	}
 
-	budget, err := h.app.FinancialService.UpdateCategoryBudget(r.Context(), financialApp.UpdateCategoryBudgetInput{
-		CategoryBudgetID: budgetID,
-		UserID:           userID,
-		OrganizationID:   organizationID,
-		ControlledAmount: controlledAmount,
-		Granularity:      granularity,
-		GranularitySet:   granularitySet,
+	profile, err := h.app.FinancialService.CreateCSVImportProfile(r.Context(), financialApp.CreateCSVImportProfileInput{
+		UserID:             userID,
+		OrganizationID:     organizationID,
+		AccountID:          accountID,
+		Name:               value(req.Name),
+		Delimiter:          value(req.Delimiter),
+		SkipRows:           skipRows,
+		DateColumn:         value(req.DateColumn),
+		DateFormat:         value(req.DateFormat),
+		DescriptionColumns: req.DescriptionColumns,
+		IDColumn:           req.IDColumn,
+		AmountColumn:       req.AmountColumn,
+		DebitColumn:        req.DebitColumn,
+		CreditColumn:       req.CreditColumn,
+		DecimalSeparator:   value(req.DecimalSeparator),
+		SignConvention:     value(req.SignConvention),
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(budget, w)
+	responses.NewSuccess(profile, w)
 }
 
-func (h *Handler) DeleteCategoryBudget(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) UpdateCSVImportProfile(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	budgetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	err = h.app.FinancialService.DeleteCategoryBudget(r.Context(), financialApp.DeleteCategoryBudgetInput{
-		CategoryBudgetID: budgetID,
-		UserID:           userID,
-		OrganizationID:   organizationID,
-	})
-	if err != nil {
-		responses.NewError(w, err)
-		return
-	}
-
-	responses.NewSuccess(map[string]string{"message": "category budget deleted successfully"}, w)
-}
-
-func (h *Handler) ConsolidateCategoryBudget(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+	profileID, err := strconv.Atoi(chi.URLParam(r, "profileId"))
 	if err != nil {
-		responses.NewError(w, errors.ErrUnauthorized)
+		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	budgetID, err := strconv.Atoi(chi.URLParam(r, "id"))
-	if err != nil {
+	var req csvImportProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	snapshot, err := h.app.FinancialService.ConsolidateCategoryBudget(r.Context(), financialApp.ConsolidateCategoryBudgetInput{
-		CategoryBudgetID: budgetID,
-		UserID:           userID,
-		OrganizationID:   organizationID,
+	profile, err := h.app.FinancialService.UpdateCSVImportProfile(r.Context(), financialApp.UpdateCSVImportProfileInput{
+		CSVImportProfileID: profileID,
+		OrganizationID:     organizationID,
+		AccountID:          accountID,
+		Name:               req.Name,
+		Delimiter:          req.Delimiter,
+		SkipRows:           req.SkipRows,
+		DateColumn:         req.DateColumn,
+		DateFormat:         req.DateFormat,
+		DescriptionColumns: req.DescriptionColumns,
+		IDColumn:           req.IDColumn,
+		AmountColumn:       req.AmountColumn,
+		DebitColumn:        req.DebitColumn,
+		CreditColumn:       req.CreditColumn,
+		DecimalSeparator:   req.DecimalSeparator,
+		SignConvention:     req.SignConvention,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(snapshot, w)
+	responses.NewSuccess(profile, w)
 }
 
-func (h *Handler) CopyCategoryBudgetsFromMonth(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) DeleteCSVImportProfile(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	var req struct {
-		SourceMonth int `json:"source_month"`
-		SourceYear  int `json:"source_year"`
-		TargetMonth int `json:"target_month"`
-		TargetYear  int `json:"target_year"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	// Validate required fields
-	if req.SourceMonth < 1 || req.SourceMonth > 12 ||
-		req.TargetMonth < 1 || req.TargetMonth > 12 ||
-		req.SourceYear < 2000 || req.TargetYear < 2000 {
+	profileID, err := strconv.Atoi(chi.URLParam(r, "profileId"))
+	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	budgets, err := h.app.FinancialService.CopyCategoryBudgetsFromMonth(r.Context(), financialApp.CopyCategoryBudgetsInput{
-		UserID:         userID,
-		OrganizationID: organizationID,
-		SourceMonth:    req.SourceMonth,
-		SourceYear:     req.SourceYear,
-		TargetMonth:    req.TargetMonth,
-		TargetYear:     req.TargetYear,
+	err = h.app.FinancialService.DeleteCSVImportProfile(r.Context(), financialApp.DeleteCSVImportProfileInput{
+		CSVImportProfileID: profileID,
+		OrganizationID:     organizationID,
+		AccountID:          accountID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(budgets, w)
+	responses.NewSuccess(map[string]string{"message": "csv import profile deleted successfully"}, w)
+}
+
+// ============================================================================
+// Transactions
+// ============================================================================
+
+func (h *Handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Parse query parameters
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	if limit == 0 {
+		limit = 1000 // Increased default limit to show more transactions
+	}
+
+	transactions, err := h.app.FinancialService.GetTransactions(r.Context(), financialApp.GetTransactionsInput{
+		AccountID:      accountID,
+		OrganizationID: organizationID,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(transactions, w)
+}
+
+// SearchTransactions lists transactions across all of the organization's
+// accounts. List filters take comma-separated IDs; pass next_cursor back as
+// cursor to fetch the following page.
+func (h *Handler) SearchTransactions(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	input, err := parseTransactionSearchQuery(r, organizationID)
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	input.Cursor = r.URL.Query().Get("cursor")
+	input.Limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+
+	result, err := h.app.FinancialService.SearchTransactions(r.Context(), input)
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(result, w)
+}
+
+// ExportTransactions downloads the transactions matching the same filters as
+// SearchTransactions as csv (with decimal_separator "." or ","), ofx or xlsx.
+// The file is streamed as it is read, so a failure midway can only abort the
+// connection.
+func (h *Handler) ExportTransactions(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	filters, err := parseTransactionSearchQuery(r, organizationID)
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	export, err := h.app.FinancialService.ExportTransactions(r.Context(), financialApp.ExportTransactionsInput{
+		SearchTransactionsInput: filters,
+		Format:                  r.URL.Query().Get("format"),
+		DecimalSeparator:        r.URL.Query().Get("decimal_separator"),
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName))
+	w.WriteHeader(http.StatusOK)
+	if err := export.Write(w); err != nil {
+		// Headers are already out; dropping the connection keeps the client
+		// from taking a truncated file for a complete one
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// parseTransactionSearchQuery reads the transaction filters shared by the
+// list and export endpoints. List filters take comma-separated IDs.
+func parseTransactionSearchQuery(r *http.Request, organizationID int) (financialApp.SearchTransactionsInput, error) {
+	query := r.URL.Query()
+	input := financialApp.SearchTransactionsInput{
+		OrganizationID:  organizationID,
+		From:            query.Get("from"),
+		To:              query.Get("to"),
+		TransactionType: query.Get("type"),
+		Query:           query.Get("q"),
+		SortBy:          query.Get("sort"),
+		SortOrder:       query.Get("order"),
+	}
+
+	var err error
+	if input.AccountIDs, err = parseIDList(query.Get("account_ids")); err != nil {
+		return input, err
+	}
+	if input.CategoryIDs, err = parseIDList(query.Get("category_ids")); err != nil {
+		return input, err
+	}
+	if input.TagIDs, err = parseIDList(query.Get("tag_ids")); err != nil {
+		return input, err
+	}
+
+	if minStr := query.Get("min_amount"); minStr != "" {
+		v, err := decimal.NewFromString(minStr)
+		if err != nil {
+			return input, err
+		}
+		input.MinAmount = &v
+	}
+	if maxStr := query.Get("max_amount"); maxStr != "" {
+		v, err := decimal.NewFromString(maxStr)
+		if err != nil {
+			return input, err
+		}
+		input.MaxAmount = &v
+	}
+
+	if ignoredStr := query.Get("is_ignored"); ignoredStr != "" {
+		b := ignoredStr == "true"
+		input.IsIgnored = &b
+	}
+	if reviewStr := query.Get("needs_review"); reviewStr != "" {
+		b := reviewStr == "true"
+		input.NeedsReview = &b
+	}
+
+	if goalStr := query.Get("savings_goal_id"); goalStr != "" {
+		g, err := strconv.Atoi(goalStr)
+		if err != nil {
+			return input, err
+		}
+		input.SavingsGoalID = &g
+	}
+
+	return input, nil
+}
+
+// parseIDList parses a comma-separated list of IDs; an empty string yields nil.
+func parseIDList(value string) ([]int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parts := strings.Split(value, ",")
+	ids := make([]int, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (h *Handler) ListUncategorizedTransactions(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	// Parse query parameters
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	if limit == 0 {
+		limit = 1000
+	}
+
+	transactions, err := h.app.FinancialService.GetUncategorizedTransactions(r.Context(), financialApp.GetUncategorizedTransactionsInput{
+		OrganizationID: organizationID,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(transactions, w)
+}
+
+// AutoCategorizeTransactions applies predicted categories to uncategorized
+// transactions at or above min_confidence (default 0.9) and flags them for
+// review
+func (h *Handler) AutoCategorizeTransactions(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		MinConfidence float64 `json:"min_confidence"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	output, err := h.app.FinancialService.AutoCategorizeTransactions(r.Context(), financialApp.AutoCategorizeTransactionsInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		MinConfidence:  req.MinConfidence,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(output, w)
+}
+
+func (h *Handler) ImportOFX(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Parse multipart form
+	err = r.ParseMultipartForm(10 << 20) // 10 MB max
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	file, _, err := r.FormFile("ofx_file")
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	defer file.Close()
+
+	// Read file content (must read until EOF; a single Read() can truncate)
+	fileBytes, err := io.ReadAll(io.LimitReader(file, 10<<20))
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	// Import transactions from OFX
+	result, err := h.app.FinancialService.ImportTransactionsFromOFX(r.Context(), financialApp.ImportOFXInput{
+		AccountID:      accountID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		OFXData:        fileBytes,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(result, w)
+}
+
+// ImportCSV imports a CSV statement using a saved CSV import profile.
+// Multipart form: csv_file, profile_id.
+func (h *Handler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	input, err := h.readCSVImportRequest(r)
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	result, err := h.app.FinancialService.ImportTransactionsFromCSV(r.Context(), input)
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(result, w)
+}
+
+// PreviewCSVImport returns the rows a CSV import would create, without importing.
+// Takes the same multipart form as ImportCSV.
+func (h *Handler) PreviewCSVImport(w http.ResponseWriter, r *http.Request) {
+	input, err := h.readCSVImportRequest(r)
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	result, err := h.app.FinancialService.PreviewCSVImport(r.Context(), input)
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(result, w)
+}
+
+func (h *Handler) readCSVImportRequest(r *http.Request) (financialApp.ImportCSVInput, error) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		return financialApp.ImportCSVInput{}, errors.ErrUnauthorized
+	}
+
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
+		return financialApp.ImportCSVInput{}, errors.ErrInvalidRequestBody
+	}
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10 MB max
+		return financialApp.ImportCSVInput{}, errors.ErrInvalidRequestBody
+	}
+
+	profileID, err := strconv.Atoi(r.FormValue("profile_id"))
+	if err != nil {
+		return financialApp.ImportCSVInput{}, errors.ErrMissingRequiredFields
+	}
+
+	file, _, err := r.FormFile("csv_file")
+	if err != nil {
+		return financialApp.ImportCSVInput{}, errors.ErrInvalidRequestBody
+	}
+	defer file.Close()
+
+	fileBytes, err := io.ReadAll(io.LimitReader(file, 10<<20))
+	if err != nil {
+		return financialApp.ImportCSVInput{}, err
+	}
+
+	return financialApp.ImportCSVInput{
+		AccountID:          accountID,
+		UserID:             userID,
+		OrganizationID:     organizationID,
+		CSVImportProfileID: profileID,
+		CSVData:            fileBytes,
+	}, nil
+}
+
+func (h *Handler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	accountID, err := strconv.Atoi(chi.URLParam(r, "accountId"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Verify account ownership
+	_, err = h.app.FinancialService.GetAccountByID(r.Context(), financialApp.GetAccountByIDInput{
+		AccountID:      accountID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	var req struct {
+		Description     string  `json:"description"`
+		Amount          float64 `json:"amount"`
+		TransactionDate string  `json:"transaction_date"`
+		TransactionType string  `json:"transaction_type"`
+		CategoryID      *int    `json:"category_id,omitempty"`
+		Notes           *string `json:"notes,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Validation
+	if req.Description == "" {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if req.Amount <= 0 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if req.TransactionDate == "" {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if req.TransactionType != "debit" && req.TransactionType != "credit" {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Convert notes to string
+	notes := ""
+	if req.Notes != nil {
+		notes = *req.Notes
+	}
+
+	transaction, err := h.app.FinancialService.CreateTransaction(r.Context(), financialApp.CreateTransactionInput{
+		AccountID:       accountID,
+		UserID:          userID,
+		OrganizationID:  organizationID,
+		CategoryID:      req.CategoryID,
+		Description:     req.Description,
+		Amount:          decimal.NewFromFloat(req.Amount),
+		TransactionDate: req.TransactionDate,
+		TransactionType: req.TransactionType,
+		Notes:           notes,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(transaction, w)
+}
+
+func (h *Handler) UpdateTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	transactionID, err := strconv.Atoi(chi.URLParam(r, "transactionId"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var req struct {
+		CategoryID  *int     `json:"category_id"`
+		Description *string  `json:"description"`
+		Amount      *float64 `json:"amount"`
+		Notes       *string  `json:"notes"`
+		IsIgnored   *bool    `json:"is_ignored"`
+		NeedsReview *bool    `json:"needs_review"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Convert amount to decimal if provided
+	var amountDecimal *decimal.Decimal
+	if req.Amount != nil {
+		amt := decimal.NewFromFloat(*req.Amount)
+		amountDecimal = &amt
+	}
+
+	transaction, err := h.app.FinancialService.UpdateTransaction(r.Context(), financialApp.UpdateTransactionInput{
+		TransactionID:  transactionID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		CategoryID:     req.CategoryID,
+		Description:    req.Description,
+		Amount:         amountDecimal,
+		Notes:          req.Notes,
+		IsIgnored:      req.IsIgnored,
+		NeedsReview:    req.NeedsReview,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(transaction, w)
+}
+
+// ============================================================================
+// Budget Pacing
+// ============================================================================
+
+// GetControllableCategoryPacing returns budget pacing data for controllable categories.
+// Query params: month (1-12), year (YYYY). Defaults to current month if not provided.
+func (h *Handler) GetControllableCategoryPacing(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	// Parse optional month/year query params, default to current month
+	now := time.Now()
+	month := now.Month()
+	year := now.Year()
+
+	if monthParam := r.URL.Query().Get("month"); monthParam != "" {
+		m, err := strconv.Atoi(monthParam)
+		if err != nil || m < 1 || m > 12 {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+		month = time.Month(m)
+	}
+
+	if yearParam := r.URL.Query().Get("year"); yearParam != "" {
+		y, err := strconv.Atoi(yearParam)
+		if err != nil || y < 2000 || y > 2100 {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+		year = y
+	}
+
+	pacing, err := h.app.FinancialService.GetControllableCategoryPacing(r.Context(), financialApp.GetControllableCategoryPacingInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Month:          int(month),
+		Year:           year,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(pacing, w)
+}
+
+// ============================================================================
+// Category Budgets
+// ============================================================================
+
+func (h *Handler) ListCategoryBudgets(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	// Parse optional query parameters
+	var month, year, categoryID *int
+	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
+		m, err := strconv.Atoi(monthStr)
+		if err == nil {
+			month = &m
+		}
+	}
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		y, err := strconv.Atoi(yearStr)
+		if err == nil {
+			year = &y
+		}
+	}
+	if catStr := r.URL.Query().Get("category_id"); catStr != "" {
+		c, err := strconv.Atoi(catStr)
+		if err == nil {
+			categoryID = &c
+		}
+	}
+
+	budgets, err := h.app.FinancialService.GetCategoryBudgets(r.Context(), financialApp.GetCategoryBudgetsInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Month:          month,
+		Year:           year,
+		CategoryID:     categoryID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(budgets, w)
+}
+
+func (h *Handler) GetCategoryBudget(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	budgetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	budget, err := h.app.FinancialService.GetCategoryBudgetByID(r.Context(), financialApp.GetCategoryBudgetByIDInput{
+		CategoryBudgetID: budgetID,
+		UserID:           userID,
+		OrganizationID:   organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(budget, w)
+}
+
+func (h *Handler) CreateCategoryBudget(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		CategoryID       int     `json:"category_id"`
+		Month            int     `json:"month"`
+		Year             int     `json:"year"`
+		ControlledAmount float64 `json:"controlled_amount"`
+		Granularity      *int    `json:"granularity,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if req.Granularity != nil && *req.Granularity < 2 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	budget, err := h.app.FinancialService.CreateCategoryBudget(r.Context(), financialApp.CreateCategoryBudgetInput{
+		UserID:           userID,
+		OrganizationID:   organizationID,
+		CategoryID:       req.CategoryID,
+		Month:            req.Month,
+		Year:             req.Year,
+		ControlledAmount: decimal.NewFromFloat(req.ControlledAmount),
+		Granularity:      req.Granularity,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(budget, w)
+}
+
+func (h *Handler) UpdateCategoryBudget(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	budgetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var req struct {
+		ControlledAmount *float64        `json:"controlled_amount,omitempty"`
+		Granularity      json.RawMessage `json:"granularity,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var controlledAmount *decimal.Decimal
+	if req.ControlledAmount != nil {
+		amt := decimal.NewFromFloat(*req.ControlledAmount)
+		controlledAmount = &amt
+	}
+	var granularity *int
+	granularitySet := req.Granularity != nil
+	if granularitySet && string(req.Granularity) != "null" {
+		var value int
+		if err := json.Unmarshal(req.Granularity, &value); err != nil || value < 2 {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+		granularity = &value
+	}
+
+	budget, err := h.app.FinancialService.UpdateCategoryBudget(r.Context(), financialApp.UpdateCategoryBudgetInput{
+		CategoryBudgetID: budgetID,
+		UserID:           userID,
+		OrganizationID:   organizationID,
+		ControlledAmount: controlledAmount,
+		Granularity:      granularity,
+		GranularitySet:   granularitySet,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(budget, w)
+}
+
+func (h *Handler) DeleteCategoryBudget(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	budgetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	err = h.app.FinancialService.DeleteCategoryBudget(r.Context(), financialApp.DeleteCategoryBudgetInput{
+		CategoryBudgetID: budgetID,
+		UserID:           userID,
+		OrganizationID:   organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(map[string]string{"message": "category budget deleted successfully"}, w)
+}
+
+func (h *Handler) ConsolidateCategoryBudget(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	budgetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	snapshot, err := h.app.FinancialService.ConsolidateCategoryBudget(r.Context(), financialApp.ConsolidateCategoryBudgetInput{
+		CategoryBudgetID: budgetID,
+		UserID:           userID,
+		OrganizationID:   organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(snapshot, w)
+}
+
+func (h *Handler) CopyCategoryBudgetsFromMonth(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		SourceMonth int `json:"source_month"`
+		SourceYear  int `json:"source_year"`
+		TargetMonth int `json:"target_month"`
+		TargetYear  int `json:"target_year"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Validate required fields
+	if req.SourceMonth < 1 || req.SourceMonth > 12 ||
+		req.TargetMonth < 1 || req.TargetMonth > 12 ||
+		req.SourceYear < 2000 || req.TargetYear < 2000 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	budgets, err := h.app.FinancialService.CopyCategoryBudgetsFromMonth(r.Context(), financialApp.CopyCategoryBudgetsInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		SourceMonth:    req.SourceMonth,
+		SourceYear:     req.SourceYear,
+		TargetMonth:    req.TargetMonth,
+		TargetYear:     req.TargetYear,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(budgets, w)
 }
 
 // CloseMonth consolidates all category budgets for a month and creates a carry-over transaction.
 func (h *Handler) CloseMonth(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
-		responses.NewError(w, errors.ErrUnauthorized)
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		Month int `json:"month"`
+		Year  int `json:"year"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if req.Month < 1 || req.Month > 12 || req.Year < 2000 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	result, err := h.app.FinancialService.CloseMonth(r.Context(), financialApp.CloseMonthInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Month:          req.Month,
+		Year:           req.Year,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(result, w)
+}
+
+// ReopenMonth reopens a closed month, discarding its carry-over and snapshots.
+func (h *Handler) ReopenMonth(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		Month  int    `json:"month"`
+		Year   int    `json:"year"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if req.Month < 1 || req.Month > 12 || req.Year < 2000 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	reopening, err := h.app.FinancialService.ReopenMonth(r.Context(), financialApp.ReopenMonthInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Month:          req.Month,
+		Year:           req.Year,
+		Reason:         req.Reason,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(reopening, w)
+}
+
+func (h *Handler) ListMonthReopenings(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	reopenings, err := h.app.FinancialService.GetMonthReopenings(r.Context(), financialApp.GetMonthReopeningsInput{
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(reopenings, w)
+}
+
+func (h *Handler) GetAutoCloseMonths(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	enabled, err := h.app.FinancialService.GetAutoCloseMonths(r.Context(), financialApp.GetAutoCloseMonthsInput{
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(map[string]bool{"enabled": enabled}, w)
+}
+
+// SetAutoCloseMonths opts the organization in or out of closing the previous
+// month automatically a few days into the next one.
+func (h *Handler) SetAutoCloseMonths(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	enabled, err := h.app.FinancialService.SetAutoCloseMonths(r.Context(), financialApp.SetAutoCloseMonthsInput{
+		OrganizationID: organizationID,
+		Enabled:        *req.Enabled,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(map[string]bool{"enabled": enabled}, w)
+}
+
+// ============================================================================
+// Planned Entries
+// ============================================================================
+
+func (h *Handler) ListPlannedEntries(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	// Parse optional query parameters
+	var categoryID *int
+	var isRecurrent, isActive *bool
+
+	if catStr := r.URL.Query().Get("category_id"); catStr != "" {
+		c, err := strconv.Atoi(catStr)
+		if err == nil {
+			categoryID = &c
+		}
+	}
+	if recStr := r.URL.Query().Get("is_recurrent"); recStr != "" {
+		b := recStr == "true"
+		isRecurrent = &b
+	}
+	if actStr := r.URL.Query().Get("is_active"); actStr != "" {
+		b := actStr == "true"
+		isActive = &b
+	}
+
+	entries, err := h.app.FinancialService.GetPlannedEntries(r.Context(), financialApp.GetPlannedEntriesInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		CategoryID:     categoryID,
+		IsRecurrent:    isRecurrent,
+		IsActive:       isActive,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(entries, w)
+}
+
+func (h *Handler) GetPlannedEntry(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	entry, err := h.app.FinancialService.GetPlannedEntryByID(r.Context(), financialApp.GetPlannedEntryByIDInput{
+		PlannedEntryID: entryID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(entry, w)
+}
+
+func (h *Handler) CreatePlannedEntry(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		CategoryID         int      `json:"category_id"`
+		PatternID          *int     `json:"pattern_id,omitempty"`
+		Description        string   `json:"description"`
+		DescriptionPattern *string  `json:"description_pattern,omitempty"`
+		Amount             float64  `json:"amount"`
+		AmountMin          *float64 `json:"amount_min,omitempty"`
+		AmountMax          *float64 `json:"amount_max,omitempty"`
+		ExpectedDayStart   *int     `json:"expected_day_start,omitempty"`
+		ExpectedDayEnd     *int     `json:"expected_day_end,omitempty"`
+		ExpectedDay        *int     `json:"expected_day,omitempty"`
+		EntryType          string   `json:"entry_type"`
+		IsRecurrent        bool     `json:"is_recurrent"`
+		ParentEntryID      *int     `json:"parent_entry_id,omitempty"`
+		TargetMonth        *int     `json:"target_month,omitempty"`
+		TargetYear         *int     `json:"target_year,omitempty"`
+		SavingsGoalID      *int     `json:"savings_goal_id,omitempty"`
+		TagIDs             []int    `json:"tag_ids,omitempty"`
+
+		RecurrenceFrequency string `json:"recurrence_frequency,omitempty"` // weekly, monthly or yearly
+		RecurrenceInterval  int    `json:"recurrence_interval,omitempty"`
+		RecurrenceMonths    []int  `json:"recurrence_months,omitempty"`
+		StartsOn            string `json:"starts_on,omitempty"` // YYYY-MM-DD
+		EndsOn              string `json:"ends_on,omitempty"`   // YYYY-MM-DD
+		OccurrenceCount     *int   `json:"occurrence_count,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Convert amount range to decimal pointers
+	var amountMin, amountMax *decimal.Decimal
+	if req.AmountMin != nil {
+		amt := decimal.NewFromFloat(*req.AmountMin)
+		amountMin = &amt
+	}
+	if req.AmountMax != nil {
+		amt := decimal.NewFromFloat(*req.AmountMax)
+		amountMax = &amt
+	}
+
+	// If a description pattern is provided, create an Advanced Pattern first
+	var patternID *int
+	if req.DescriptionPattern != nil && *req.DescriptionPattern != "" {
+		pattern, err := h.app.FinancialService.CreatePattern(r.Context(), financialApp.CreatePatternInput{
+			UserID:             userID,
+			OrganizationID:     organizationID,
+			DescriptionPattern: *req.DescriptionPattern,
+			AmountMin:          req.AmountMin,
+			AmountMax:          req.AmountMax,
+			TargetDescription:  &req.Description,
+			TargetCategoryID:   &req.CategoryID,
+			ApplyRetroactively: false,
+		})
+		if err != nil {
+			responses.NewError(w, err)
+			return
+		}
+		patternID = &pattern.PatternID
+	} else if req.PatternID != nil {
+		patternID = req.PatternID
+	}
+
+	entry, err := h.app.FinancialService.CreatePlannedEntry(r.Context(), financialApp.CreatePlannedEntryInput{
+		UserID:           userID,
+		OrganizationID:   organizationID,
+		CategoryID:       req.CategoryID,
+		PatternID:        patternID,
+		Description:      req.Description,
+		Amount:           decimal.NewFromFloat(req.Amount),
+		AmountMin:        amountMin,
+		AmountMax:        amountMax,
+		ExpectedDayStart: req.ExpectedDayStart,
+		ExpectedDayEnd:   req.ExpectedDayEnd,
+		ExpectedDay:      req.ExpectedDay,
+		EntryType:        req.EntryType,
+		IsRecurrent:      req.IsRecurrent,
+		ParentEntryID:    req.ParentEntryID,
+		TargetMonth:      req.TargetMonth,
+		TargetYear:       req.TargetYear,
+		SavingsGoalID:    req.SavingsGoalID,
+		TagIDs:           req.TagIDs,
+
+		RecurrenceFrequency: req.RecurrenceFrequency,
+		RecurrenceInterval:  req.RecurrenceInterval,
+		RecurrenceMonths:    req.RecurrenceMonths,
+		StartsOn:            req.StartsOn,
+		EndsOn:              req.EndsOn,
+		OccurrenceCount:     req.OccurrenceCount,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(entry, w)
+}
+
+func (h *Handler) UpdatePlannedEntry(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var req struct {
+		Description      *string  `json:"description,omitempty"`
+		Amount           *float64 `json:"amount,omitempty"`
+		AmountMin        *float64 `json:"amount_min,omitempty"`
+		AmountMax        *float64 `json:"amount_max,omitempty"`
+		ExpectedDayStart *int     `json:"expected_day_start,omitempty"`
+		ExpectedDayEnd   *int     `json:"expected_day_end,omitempty"`
+		ExpectedDay      *int     `json:"expected_day,omitempty"`
+		EntryType        *string  `json:"entry_type,omitempty"`
+		IsActive         *bool    `json:"is_active,omitempty"`
+		PatternID        *int     `json:"pattern_id,omitempty"`
+		SavingsGoalID    *int     `json:"savings_goal_id,omitempty"`
+		CategoryID       *int     `json:"category_id,omitempty"`
+		TagIDs           *[]int   `json:"tag_ids,omitempty"` // nil = no change, [] = clear tags
+
+		RecurrenceFrequency *string `json:"recurrence_frequency,omitempty"`
+		RecurrenceInterval  *int    `json:"recurrence_interval,omitempty"`
+		RecurrenceMonths    *[]int  `json:"recurrence_months,omitempty"` // [] = any month
+		StartsOn            *string `json:"starts_on,omitempty"`
+		EndsOn              *string `json:"ends_on,omitempty"`          // "" = no end date
+		OccurrenceCount     *int    `json:"occurrence_count,omitempty"` // 0 = no limit
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var amount *decimal.Decimal
+	if req.Amount != nil {
+		amt := decimal.NewFromFloat(*req.Amount)
+		amount = &amt
+	}
+
+	var amountMin *decimal.Decimal
+	if req.AmountMin != nil {
+		amt := decimal.NewFromFloat(*req.AmountMin)
+		amountMin = &amt
+	}
+
+	var amountMax *decimal.Decimal
+	if req.AmountMax != nil {
+		amt := decimal.NewFromFloat(*req.AmountMax)
+		amountMax = &amt
+	}
+
+	entry, err := h.app.FinancialService.UpdatePlannedEntry(r.Context(), financialApp.UpdatePlannedEntryInput{
+		PlannedEntryID:   entryID,
+		UserID:           userID,
+		OrganizationID:   organizationID,
+		Description:      req.Description,
+		Amount:           amount,
+		AmountMin:        amountMin,
+		AmountMax:        amountMax,
+		ExpectedDayStart: req.ExpectedDayStart,
+		ExpectedDayEnd:   req.ExpectedDayEnd,
+		ExpectedDay:      req.ExpectedDay,
+		EntryType:        req.EntryType,
+		IsActive:         req.IsActive,
+		PatternID:        req.PatternID,
+		SavingsGoalID:    req.SavingsGoalID,
+		CategoryID:       req.CategoryID,
+		TagIDs:           req.TagIDs,
+
+		RecurrenceFrequency: req.RecurrenceFrequency,
+		RecurrenceInterval:  req.RecurrenceInterval,
+		RecurrenceMonths:    req.RecurrenceMonths,
+		StartsOn:            req.StartsOn,
+		EndsOn:              req.EndsOn,
+		OccurrenceCount:     req.OccurrenceCount,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(entry, w)
+}
+
+func (h *Handler) DeletePlannedEntry(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	err = h.app.FinancialService.DeletePlannedEntry(r.Context(), financialApp.DeletePlannedEntryInput{
+		PlannedEntryID: entryID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(map[string]string{"message": "planned entry deleted successfully"}, w)
+}
+
+func (h *Handler) GenerateMonthlyInstances(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var req struct {
+		Month int `json:"month"`
+		Year  int `json:"year"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	instances, err := h.app.FinancialService.GenerateMonthlyInstances(r.Context(), financialApp.GenerateMonthlyInstancesInput{
+		ParentEntryID:  entryID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Month:          req.Month,
+		Year:           req.Year,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(instances, w)
+}
+
+// ============================================================================
+// Monthly Snapshots
+// ============================================================================
+
+func (h *Handler) ListMonthlySnapshots(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	// Parse optional query parameters
+	var categoryID, month, year *int
+	if catStr := r.URL.Query().Get("category_id"); catStr != "" {
+		c, err := strconv.Atoi(catStr)
+		if err == nil {
+			categoryID = &c
+		}
+	}
+	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
+		m, err := strconv.Atoi(monthStr)
+		if err == nil {
+			month = &m
+		}
+	}
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		y, err := strconv.Atoi(yearStr)
+		if err == nil {
+			year = &y
+		}
+	}
+
+	snapshots, err := h.app.FinancialService.GetMonthlySnapshots(r.Context(), financialApp.GetMonthlySnapshotsInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		CategoryID:     categoryID,
+		Month:          month,
+		Year:           year,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(snapshots, w)
+}
+
+func (h *Handler) GetMonthlySnapshot(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	snapshotID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	snapshot, err := h.app.FinancialService.GetMonthlySnapshotByID(r.Context(), financialApp.GetMonthlySnapshotByIDInput{
+		SnapshotID:     snapshotID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(snapshot, w)
+}
+
+// GetTransactionPlannedEntry retrieves the planned entry linked to a transaction
+// GET /financial/transactions/{id}/planned-entry
+func (h *Handler) GetTransactionPlannedEntry(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	transactionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	entry, err := h.app.FinancialService.GetPlannedEntryForTransaction(r.Context(), financialApp.GetPlannedEntryForTransactionInput{
+		TransactionID:  transactionID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	// Return empty object if no linked entry (instead of null for better frontend handling)
+	if entry == nil {
+		responses.NewSuccess(struct{}{}, w)
+		return
+	}
+
+	responses.NewSuccess(entry, w)
+}
+
+// ============================================================================
+// Patterns
+// ============================================================================
+
+func (h *Handler) CreatePattern(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		Action             financialApp.PatternAction `json:"action"`
+		DescriptionPattern string                     `json:"description_pattern"`
+		DatePattern        *string                    `json:"date_pattern,omitempty"`
+		WeekdayPattern     *string                    `json:"weekday_pattern,omitempty"`
+		AmountRange        *financialApp.AmountRange  `json:"amount_range,omitempty"`
+		TargetDescription  *string                    `json:"target_description,omitempty"`
+		TargetCategoryID   *int                       `json:"target_category_id,omitempty"`
+		Priority           *int                       `json:"priority,omitempty"`
+		ApplyRetroactively bool                       `json:"apply_retroactively"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Validation
+	if req.DescriptionPattern == "" {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	// Parse amount range
+	var amountMin, amountMax *float64
+	if req.AmountRange != nil {
+		amountMin = &req.AmountRange.Min
+		amountMax = &req.AmountRange.Max
+	}
+
+	pattern, err := h.app.FinancialService.CreatePattern(r.Context(), financialApp.CreatePatternInput{
+		UserID:             userID,
+		OrganizationID:     organizationID,
+		Action:             req.Action,
+		DescriptionPattern: req.DescriptionPattern,
+		DatePattern:        req.DatePattern,
+		WeekdayPattern:     req.WeekdayPattern,
+		AmountMin:          amountMin,
+		AmountMax:          amountMax,
+		TargetDescription:  req.TargetDescription,
+		TargetCategoryID:   req.TargetCategoryID,
+		Priority:           req.Priority,
+		ApplyRetroactively: req.ApplyRetroactively,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(pattern, w)
+}
+
+// PreviewPattern evaluates a draft pattern, with the same body as
+// CreatePattern plus an optional pattern_id to preview an edit, and lists the
+// transactions it would match (limit, default 100) without saving anything.
+func (h *Handler) PreviewPattern(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		PatternID          int                        `json:"pattern_id"`
+		Action             financialApp.PatternAction `json:"action"`
+		DescriptionPattern string                     `json:"description_pattern"`
+		DatePattern        *string                    `json:"date_pattern,omitempty"`
+		WeekdayPattern     *string                    `json:"weekday_pattern,omitempty"`
+		AmountRange        *financialApp.AmountRange  `json:"amount_range,omitempty"`
+		TargetDescription  *string                    `json:"target_description,omitempty"`
+		TargetCategoryID   *int                       `json:"target_category_id,omitempty"`
+		Priority           *int                       `json:"priority,omitempty"`
+		Limit              int                        `json:"limit"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if req.DescriptionPattern == "" {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var amountMin, amountMax *float64
+	if req.AmountRange != nil {
+		amountMin = &req.AmountRange.Min
+		amountMax = &req.AmountRange.Max
+	}
+
+	preview, err := h.app.FinancialService.PreviewPattern(r.Context(), financialApp.PreviewPatternInput{
+		CreatePatternInput: financialApp.CreatePatternInput{
+			UserID:             userID,
+			OrganizationID:     organizationID,
+			Action:             req.Action,
+			DescriptionPattern: req.DescriptionPattern,
+			DatePattern:        req.DatePattern,
+			WeekdayPattern:     req.WeekdayPattern,
+			AmountMin:          amountMin,
+			AmountMax:          amountMax,
+			TargetDescription:  req.TargetDescription,
+			TargetCategoryID:   req.TargetCategoryID,
+			Priority:           req.Priority,
+		},
+		PatternID: req.PatternID,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(preview, w)
+}
+
+func (h *Handler) GetPatterns(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	// Parse optional query parameters
+	var isActive *bool
+	var categoryID *int
+
+	if activeStr := r.URL.Query().Get("is_active"); activeStr != "" {
+		b := activeStr == "true"
+		isActive = &b
+	}
+
+	if catStr := r.URL.Query().Get("category_id"); catStr != "" {
+		c, err := strconv.Atoi(catStr)
+		if err == nil {
+			categoryID = &c
+		}
+	}
+
+	patterns, err := h.app.FinancialService.GetPatterns(r.Context(), financialApp.GetPatternsInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		IsActive:       isActive,
+		CategoryID:     categoryID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(patterns, w)
+}
+
+func (h *Handler) GetPattern(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	patternID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	pattern, err := h.app.FinancialService.GetPatternByID(r.Context(), financialApp.GetPatternByIDInput{
+		PatternID:      patternID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(pattern, w)
+}
+
+func (h *Handler) UpdatePattern(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	patternID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var req struct {
+		IsActive           *bool                       `json:"is_active,omitempty"`
+		Action             *financialApp.PatternAction `json:"action,omitempty"`
+		DescriptionPattern *string                     `json:"description_pattern,omitempty"`
+		DatePattern        *string                     `json:"date_pattern,omitempty"`
+		WeekdayPattern     *string                     `json:"weekday_pattern,omitempty"`
+		AmountRange        *struct {
+			Min float64 `json:"min"`
+			Max float64 `json:"max"`
+		} `json:"amount_range,omitempty"`
+		TargetDescription *string `json:"target_description,omitempty"`
+		TargetCategoryID  *int    `json:"target_category_id,omitempty"`
+		Priority          *int    `json:"priority,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Convert amount_range to strings if provided
+	var amountMin, amountMax *string
+	if req.AmountRange != nil {
+		minStr := fmt.Sprintf("%.2f", req.AmountRange.Min)
+		maxStr := fmt.Sprintf("%.2f", req.AmountRange.Max)
+		amountMin = &minStr
+		amountMax = &maxStr
+	}
+
+	pattern, err := h.app.FinancialService.UpdatePattern(r.Context(), financialApp.UpdatePatternInput{
+		PatternID:          patternID,
+		UserID:             userID,
+		OrganizationID:     organizationID,
+		Action:             req.Action,
+		IsActive:           req.IsActive,
+		DescriptionPattern: req.DescriptionPattern,
+		DatePattern:        req.DatePattern,
+		WeekdayPattern:     req.WeekdayPattern,
+		AmountMin:          amountMin,
+		AmountMax:          amountMax,
+		TargetDescription:  req.TargetDescription,
+		TargetCategoryID:   req.TargetCategoryID,
+		Priority:           req.Priority,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(pattern, w)
+}
+
+// ReorderPatterns sets pattern priorities from an ordered list of IDs
+func (h *Handler) ReorderPatterns(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		PatternIDs []int `json:"pattern_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	patterns, err := h.app.FinancialService.ReorderPatterns(r.Context(), financialApp.ReorderPatternsInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		PatternIDs:     req.PatternIDs,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(patterns, w)
+}
+
+// GetPatternConflicts lists transactions that active patterns would
+// categorize differently (limit, default 100)
+func (h *Handler) GetPatternConflicts(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var limit int
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+	}
+
+	report, err := h.app.FinancialService.GetPatternConflicts(r.Context(), financialApp.GetPatternConflictsInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Limit:          limit,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(report, w)
+}
+
+// GetPatternSuggestions proposes patterns for descriptions that keep being
+// categorized by hand (min_occurrences, default 3; limit, default 20)
+func (h *Handler) GetPatternSuggestions(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var minOccurrences, limit int
+	if minStr := r.URL.Query().Get("min_occurrences"); minStr != "" {
+		minOccurrences, err = strconv.Atoi(minStr)
+		if err != nil {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+	}
+
+	suggestions, err := h.app.FinancialService.GetPatternSuggestions(r.Context(), financialApp.GetPatternSuggestionsInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		MinOccurrences: minOccurrences,
+		Limit:          limit,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(suggestions, w)
+}
+
+// AcceptPatternSuggestion creates the pattern suggested for a transaction's
+// cluster, optionally overriding its targets
+func (h *Handler) AcceptPatternSuggestion(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		TransactionID      int     `json:"transaction_id"`
+		TargetDescription  *string `json:"target_description,omitempty"`
+		TargetCategoryID   *int    `json:"target_category_id,omitempty"`
+		ApplyRetroactively bool    `json:"apply_retroactively"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if req.TransactionID == 0 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	pattern, err := h.app.FinancialService.AcceptPatternSuggestion(r.Context(), financialApp.AcceptPatternSuggestionInput{
+		UserID:             userID,
+		OrganizationID:     organizationID,
+		TransactionID:      req.TransactionID,
+		TargetDescription:  req.TargetDescription,
+		TargetCategoryID:   req.TargetCategoryID,
+		ApplyRetroactively: req.ApplyRetroactively,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(pattern, w)
+}
+
+func (h *Handler) DeletePattern(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	patternID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	err = h.app.FinancialService.DeletePattern(r.Context(), financialApp.DeletePatternInput{
+		PatternID:      patternID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(map[string]string{"message": "pattern deleted successfully"}, w)
+}
+
+// ApplyPatternRetroactively applies a pattern to all existing uncategorized transactions
+func (h *Handler) ApplyPatternRetroactively(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	patternID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	result, err := h.app.FinancialService.ApplyPatternRetroactivelySync(r.Context(), financialApp.ApplyPatternRetroactivelyInput{
+		PatternID:      patternID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(map[string]int{
+		"updated_count": result.UpdatedCount,
+		"total_checked": result.TotalChecked,
+	}, w)
+}
+
+// ============================================================================
+// Classification Rules
+// ============================================================================
+
+func (h *Handler) ListClassificationRules(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var isActive *bool
+	if activeStr := r.URL.Query().Get("is_active"); activeStr != "" {
+		b := activeStr == "true"
+		isActive = &b
+	}
+
+	rules, err := h.app.FinancialService.GetClassificationRules(r.Context(), financialApp.GetClassificationRulesInput{
+		OrganizationID: organizationID,
+		IsActive:       isActive,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(rules, w)
+}
+
+func (h *Handler) GetClassificationRule(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	ruleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	rule, err := h.app.FinancialService.GetClassificationRuleByID(r.Context(), financialApp.GetClassificationRuleByIDInput{
+		RuleID:         ruleID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(rule, w)
+}
+
+func (h *Handler) CreateClassificationRule(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		CategoryID           int              `json:"category_id"`
+		Name                 string           `json:"name"`
+		Priority             int              `json:"priority"`
+		MatchDescription     *string          `json:"match_description,omitempty"`
+		MatchAmountMin       *decimal.Decimal `json:"match_amount_min,omitempty"`
+		MatchAmountMax       *decimal.Decimal `json:"match_amount_max,omitempty"`
+		MatchTransactionType *string          `json:"match_transaction_type,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	if req.CategoryID == 0 {
+		responses.NewError(w, errors.ErrMissingRequiredFields)
+		return
+	}
+
+	rule, err := h.app.FinancialService.CreateClassificationRule(r.Context(), financialApp.CreateClassificationRuleInput{
+		UserID:               userID,
+		OrganizationID:       organizationID,
+		CategoryID:           req.CategoryID,
+		Name:                 req.Name,
+		Priority:             req.Priority,
+		MatchDescription:     req.MatchDescription,
+		MatchAmountMin:       req.MatchAmountMin,
+		MatchAmountMax:       req.MatchAmountMax,
+		MatchTransactionType: req.MatchTransactionType,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(rule, w)
+}
+
+func (h *Handler) UpdateClassificationRule(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	ruleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var req struct {
+		CategoryID           *int             `json:"category_id,omitempty"`
+		Name                 *string          `json:"name,omitempty"`
+		Priority             *int             `json:"priority,omitempty"`
+		MatchDescription     *string          `json:"match_description,omitempty"`
+		MatchAmountMin       *decimal.Decimal `json:"match_amount_min,omitempty"`
+		MatchAmountMax       *decimal.Decimal `json:"match_amount_max,omitempty"`
+		MatchTransactionType *string          `json:"match_transaction_type,omitempty"`
+		IsActive             *bool            `json:"is_active,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	rule, err := h.app.FinancialService.UpdateClassificationRule(r.Context(), financialApp.UpdateClassificationRuleInput{
+		RuleID:               ruleID,
+		OrganizationID:       organizationID,
+		CategoryID:           req.CategoryID,
+		Name:                 req.Name,
+		Priority:             req.Priority,
+		MatchDescription:     req.MatchDescription,
+		MatchAmountMin:       req.MatchAmountMin,
+		MatchAmountMax:       req.MatchAmountMax,
+		MatchTransactionType: req.MatchTransactionType,
+		IsActive:             req.IsActive,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(rule, w)
+}
+
+// ReorderClassificationRules sets rule priorities from an ordered list of IDs
+func (h *Handler) ReorderClassificationRules(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req struct {
+		RuleIDs []int `json:"rule_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	rules, err := h.app.FinancialService.ReorderClassificationRules(r.Context(), financialApp.ReorderClassificationRulesInput{
+		OrganizationID: organizationID,
+		RuleIDs:        req.RuleIDs,
+	})
+	if err != nil {
+		responses.NewError(w, err)
 		return
 	}
 
-	var req struct {
-		Month int `json:"month"`
-		Year  int `json:"year"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+	responses.NewSuccess(rules, w)
+}
+
+func (h *Handler) DeleteClassificationRule(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
-	if req.Month < 1 || req.Month > 12 || req.Year < 2000 {
+
+	ruleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	result, err := h.app.FinancialService.CloseMonth(r.Context(), financialApp.CloseMonthInput{
-		UserID:         userID,
+	err = h.app.FinancialService.DeleteClassificationRule(r.Context(), financialApp.DeleteClassificationRuleInput{
+		RuleID:         ruleID,
 		OrganizationID: organizationID,
-		Month:          req.Month,
-		Year:           req.Year,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(result, w)
+	responses.NewSuccess(map[string]string{"message": "classification rule deleted successfully"}, w)
 }
 
 // ============================================================================
-// Planned Entries
+// Income Planning
 // ============================================================================
 
-func (h *Handler) ListPlannedEntries(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetIncomePlanning(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Parse optional query parameters
-	var categoryID *int
-	var isRecurrent, isActive *bool
+	// Parse query parameters
+	monthStr := r.URL.Query().Get("month")
+	yearStr := r.URL.Query().Get("year")
 
-	if catStr := r.URL.Query().Get("category_id"); catStr != "" {
-		c, err := strconv.Atoi(catStr)
-		if err == nil {
-			categoryID = &c
-		}
+	if monthStr == "" || yearStr == "" {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
-	if recStr := r.URL.Query().Get("is_recurrent"); recStr != "" {
-		b := recStr == "true"
-		isRecurrent = &b
+
+	month, err := strconv.Atoi(monthStr)
+	if err != nil || month < 1 || month > 12 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
-	if actStr := r.URL.Query().Get("is_active"); actStr != "" {
-		b := actStr == "true"
-		isActive = &b
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil || year < 2020 || year > 2100 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	entries, err := h.app.FinancialService.GetPlannedEntries(r.Context(), financialApp.GetPlannedEntriesInput{
+	report, err := h.app.FinancialService.GetIncomePlanning(r.Context(), financialApp.GetIncomePlanningInput{
 		UserID:         userID,
 		OrganizationID: organizationID,
-		CategoryID:     categoryID,
-		IsRecurrent:    isRecurrent,
-		IsActive:       isActive,
+		Month:          month,
+		Year:           year,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(entries, w)
+	responses.NewSuccess(report, w)
 }
 
-func (h *Handler) GetPlannedEntry(w http.ResponseWriter, r *http.Request) {
+// ============================================================================
+// Planned Entry Statuses (Entrada Planejada)
+// ============================================================================
+
+// GetPlannedEntriesForMonth returns planned entries with their status for a given month/year
+// GET /financial/planned-entries/month?month=X&year=Y
+func (h *Handler) GetPlannedEntriesForMonth(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
-	if err != nil {
+	// Parse required query parameters
+	monthStr := r.URL.Query().Get("month")
+	yearStr := r.URL.Query().Get("year")
+
+	if monthStr == "" || yearStr == "" {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	entry, err := h.app.FinancialService.GetPlannedEntryByID(r.Context(), financialApp.GetPlannedEntryByIDInput{
-		PlannedEntryID: entryID,
+	month, err := strconv.Atoi(monthStr)
+	if err != nil || month < 1 || month > 12 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil || year < 2000 || year > 2100 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	entries, err := h.app.FinancialService.GetPlannedEntriesForMonth(r.Context(), financialApp.GetPlannedEntriesForMonthInput{
 		UserID:         userID,
 		OrganizationID: organizationID,
+		Month:          month,
+		Year:           year,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(entry, w)
+	responses.NewSuccess(entries, w)
 }
 
-func (h *Handler) CreatePlannedEntry(w http.ResponseWriter, r *http.Request) {
+// MatchPlannedEntry matches a transaction to a planned entry for a specific month
+// POST /financial/planned-entries/{id}/match
+func (h *Handler) MatchPlannedEntry(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
+	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
 	var req struct {
-		CategoryID         int      `json:"category_id"`
-		PatternID          *int     `json:"pattern_id,omitempty"`
-		Description        string   `json:"description"`
-		DescriptionPattern *string  `json:"description_pattern,omitempty"`
-		Amount             float64  `json:"amount"`
-		AmountMin          *float64 `json:"amount_min,omitempty"`
-		AmountMax          *float64 `json:"amount_max,omitempty"`
-		ExpectedDayStart   *int     `json:"expected_day_start,omitempty"`
-		ExpectedDayEnd     *int     `json:"expected_day_end,omitempty"`
-		ExpectedDay        *int     `json:"expected_day,omitempty"`
-		EntryType          string   `json:"entry_type"`
-		IsRecurrent        bool     `json:"is_recurrent"`
-		ParentEntryID      *int     `json:"parent_entry_id,omitempty"`
-		TargetMonth        *int     `json:"target_month,omitempty"`
-		TargetYear         *int     `json:"target_year,omitempty"`
-		SavingsGoalID      *int     `json:"savings_goal_id,omitempty"`
-		TagIDs             []int    `json:"tag_ids,omitempty"`
+		TransactionID int `json:"transaction_id"`
+		Month         int `json:"month"`
+		Year          int `json:"year"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -1080,68 +3149,31 @@ func (h *Handler) CreatePlannedEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Convert amount range to decimal pointers
-	var amountMin, amountMax *decimal.Decimal
-	if req.AmountMin != nil {
-		amt := decimal.NewFromFloat(*req.AmountMin)
-		amountMin = &amt
-	}
-	if req.AmountMax != nil {
-		amt := decimal.NewFromFloat(*req.AmountMax)
-		amountMax = &amt
-	}
-
-	// If a description pattern is provided, create an Advanced Pattern first
-	var patternID *int
-	if req.DescriptionPattern != nil && *req.DescriptionPattern != "" {
-		pattern, err := h.app.FinancialService.CreatePattern(r.Context(), financialApp.CreatePatternInput{
-			UserID:             userID,
-			OrganizationID:     organizationID,
-			DescriptionPattern: *req.DescriptionPattern,
-			AmountMin:          req.AmountMin,
-			AmountMax:          req.AmountMax,
-			TargetDescription:  &req.Description,
-			TargetCategoryID:   &req.CategoryID,
-			ApplyRetroactively: false,
-		})
-		if err != nil {
-			responses.NewError(w, err)
-			return
-		}
-		patternID = &pattern.PatternID
-	} else if req.PatternID != nil {
-		patternID = req.PatternID
+	// Validation
+	if req.TransactionID == 0 || req.Month < 1 || req.Month > 12 || req.Year < 2000 || req.Year > 2100 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	entry, err := h.app.FinancialService.CreatePlannedEntry(r.Context(), financialApp.CreatePlannedEntryInput{
-		UserID:           userID,
-		OrganizationID:   organizationID,
-		CategoryID:       req.CategoryID,
-		PatternID:        patternID,
-		Description:      req.Description,
-		Amount:           decimal.NewFromFloat(req.Amount),
-		AmountMin:        amountMin,
-		AmountMax:        amountMax,
-		ExpectedDayStart: req.ExpectedDayStart,
-		ExpectedDayEnd:   req.ExpectedDayEnd,
-		ExpectedDay:      req.ExpectedDay,
-		EntryType:        req.EntryType,
-		IsRecurrent:      req.IsRecurrent,
-		ParentEntryID:    req.ParentEntryID,
-		TargetMonth:      req.TargetMonth,
-		TargetYear:       req.TargetYear,
-		SavingsGoalID:    req.SavingsGoalID,
-		TagIDs:           req.TagIDs,
+	status, err := h.app.FinancialService.MatchPlannedEntryToTransaction(r.Context(), financialApp.MatchPlannedEntryInput{
+		PlannedEntryID: entryID,
+		TransactionID:  req.TransactionID,
+		Month:          req.Month,
+		Year:           req.Year,
+		UserID:         userID,
+		OrganizationID: organizationID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(entry, w)
+	responses.NewSuccess(status, w)
 }
 
-func (h *Handler) UpdatePlannedEntry(w http.ResponseWriter, r *http.Request) {
+// UnmatchPlannedEntry removes the match between a planned entry and a transaction
+// DELETE /financial/planned-entries/{id}/match
+func (h *Handler) UnmatchPlannedEntry(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
@@ -1154,72 +3186,45 @@ func (h *Handler) UpdatePlannedEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		Description      *string  `json:"description,omitempty"`
-		Amount           *float64 `json:"amount,omitempty"`
-		AmountMin        *float64 `json:"amount_min,omitempty"`
-		AmountMax        *float64 `json:"amount_max,omitempty"`
-		ExpectedDayStart *int     `json:"expected_day_start,omitempty"`
-		ExpectedDayEnd   *int     `json:"expected_day_end,omitempty"`
-		ExpectedDay      *int     `json:"expected_day,omitempty"`
-		EntryType        *string  `json:"entry_type,omitempty"`
-		IsActive         *bool    `json:"is_active,omitempty"`
-		PatternID        *int     `json:"pattern_id,omitempty"`
-		SavingsGoalID    *int     `json:"savings_goal_id,omitempty"`
-		CategoryID       *int     `json:"category_id,omitempty"`
-		TagIDs           *[]int   `json:"tag_ids,omitempty"` // nil = no change, [] = clear tags
-	}
+	// Parse query parameters for month/year
+	monthStr := r.URL.Query().Get("month")
+	yearStr := r.URL.Query().Get("year")
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if monthStr == "" || yearStr == "" {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	var amount *decimal.Decimal
-	if req.Amount != nil {
-		amt := decimal.NewFromFloat(*req.Amount)
-		amount = &amt
-	}
-
-	var amountMin *decimal.Decimal
-	if req.AmountMin != nil {
-		amt := decimal.NewFromFloat(*req.AmountMin)
-		amountMin = &amt
+	month, err := strconv.Atoi(monthStr)
+	if err != nil || month < 1 || month > 12 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	var amountMax *decimal.Decimal
-	if req.AmountMax != nil {
-		amt := decimal.NewFromFloat(*req.AmountMax)
-		amountMax = &amt
+	year, err := strconv.Atoi(yearStr)
+	if err != nil || year < 2000 || year > 2100 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	entry, err := h.app.FinancialService.UpdatePlannedEntry(r.Context(), financialApp.UpdatePlannedEntryInput{
-		PlannedEntryID:   entryID,
-		UserID:           userID,
-		OrganizationID:   organizationID,
-		Description:      req.Description,
-		Amount:           amount,
-		AmountMin:        amountMin,
-		AmountMax:        amountMax,
-		ExpectedDayStart: req.ExpectedDayStart,
-		ExpectedDayEnd:   req.ExpectedDayEnd,
-		ExpectedDay:      req.ExpectedDay,
-		EntryType:        req.EntryType,
-		IsActive:         req.IsActive,
-		PatternID:        req.PatternID,
-		SavingsGoalID:    req.SavingsGoalID,
-		CategoryID:       req.CategoryID,
-		TagIDs:           req.TagIDs,
+	err = h.app.FinancialService.UnmatchPlannedEntry(r.Context(), financialApp.UnmatchPlannedEntryInput{
+		PlannedEntryID: entryID,
+		Month:          month,
+		Year:           year,
+		UserID:         userID,
+		OrganizationID: organizationID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(entry, w)
+	responses.NewSuccess(map[string]string{"message": "planned entry unmatched successfully"}, w)
 }
 
-func (h *Handler) DeletePlannedEntry(w http.ResponseWriter, r *http.Request) {
+// DismissPlannedEntry dismisses a planned entry for a specific month
+// POST /financial/planned-entries/{id}/dismiss
+func (h *Handler) DismissPlannedEntry(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
@@ -1232,8 +3237,34 @@ func (h *Handler) DeletePlannedEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.app.FinancialService.DeletePlannedEntry(r.Context(), financialApp.DeletePlannedEntryInput{
+	var req struct {
+		Month  int     `json:"month"`
+		Year   int     `json:"year"`
+		Reason *string `json:"reason,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Validation
+	if req.Month < 1 || req.Month > 12 || req.Year < 2000 || req.Year > 2100 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	// Convert optional reason to string
+	reason := ""
+	if req.Reason != nil {
+		reason = *req.Reason
+	}
+
+	status, err := h.app.FinancialService.DismissPlannedEntry(r.Context(), financialApp.DismissPlannedEntryInput{
 		PlannedEntryID: entryID,
+		Month:          req.Month,
+		Year:           req.Year,
+		Reason:         reason,
 		UserID:         userID,
 		OrganizationID: organizationID,
 	})
@@ -1242,10 +3273,12 @@ func (h *Handler) DeletePlannedEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	responses.NewSuccess(map[string]string{"message": "planned entry deleted successfully"}, w)
+	responses.NewSuccess(status, w)
 }
 
-func (h *Handler) GenerateMonthlyInstances(w http.ResponseWriter, r *http.Request) {
+// UndismissPlannedEntry undismisses a planned entry for a specific month
+// DELETE /financial/planned-entries/{id}/dismiss
+func (h *Handler) UndismissPlannedEntry(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
@@ -1258,36 +3291,47 @@ func (h *Handler) GenerateMonthlyInstances(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	var req struct {
-		Month int `json:"month"`
-		Year  int `json:"year"`
+	// Parse query parameters for month/year
+	monthStr := r.URL.Query().Get("month")
+	yearStr := r.URL.Query().Get("year")
+
+	if monthStr == "" || yearStr == "" {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	month, err := strconv.Atoi(monthStr)
+	if err != nil || month < 1 || month > 12 {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	instances, err := h.app.FinancialService.GenerateMonthlyInstances(r.Context(), financialApp.GenerateMonthlyInstancesInput{
-		ParentEntryID:  entryID,
+	year, err := strconv.Atoi(yearStr)
+	if err != nil || year < 2000 || year > 2100 {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	status, err := h.app.FinancialService.UndismissPlannedEntry(r.Context(), financialApp.UndismissPlannedEntryInput{
+		PlannedEntryID: entryID,
+		Month:          month,
+		Year:           year,
 		UserID:         userID,
 		OrganizationID: organizationID,
-		Month:          req.Month,
-		Year:           req.Year,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(instances, w)
+	responses.NewSuccess(status, w)
 }
 
 // ============================================================================
-// Monthly Snapshots
+// Savings Goals
 // ============================================================================
 
-func (h *Handler) ListMonthlySnapshots(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) ListSavingsGoals(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
@@ -1295,56 +3339,55 @@ func (h *Handler) ListMonthlySnapshots(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse optional query parameters
-	var categoryID, month, year *int
-	if catStr := r.URL.Query().Get("category_id"); catStr != "" {
-		c, err := strconv.Atoi(catStr)
-		if err == nil {
-			categoryID = &c
-		}
+	// Default to is_active=true to hide soft-deleted goals unless explicitly requested
+	defaultActive := true
+	isActive := &defaultActive
+	if activeStr := r.URL.Query().Get("is_active"); activeStr != "" {
+		active := activeStr == "true"
+		isActive = &active
 	}
-	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
-		m, err := strconv.Atoi(monthStr)
-		if err == nil {
-			month = &m
-		}
+
+	var isCompleted *bool
+	if completedStr := r.URL.Query().Get("is_completed"); completedStr != "" {
+		completed := completedStr == "true"
+		isCompleted = &completed
 	}
-	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
-		y, err := strconv.Atoi(yearStr)
-		if err == nil {
-			year = &y
-		}
+
+	var goalType *string
+	if typeStr := r.URL.Query().Get("goal_type"); typeStr != "" {
+		goalType = &typeStr
 	}
 
-	snapshots, err := h.app.FinancialService.GetMonthlySnapshots(r.Context(), financialApp.GetMonthlySnapshotsInput{
+	goals, err := h.app.FinancialService.GetSavingsGoals(r.Context(), financialApp.GetSavingsGoalsInput{
 		UserID:         userID,
 		OrganizationID: organizationID,
-		CategoryID:     categoryID,
-		Month:          month,
-		Year:           year,
+		IsActive:       isActive,
+		IsCompleted:    isCompleted,
+		GoalType:       goalType,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(snapshots, w)
+	responses.NewSuccess(goals, w)
 }
 
-func (h *Handler) GetMonthlySnapshot(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetSavingsGoal(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	snapshotID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	snapshot, err := h.app.FinancialService.GetMonthlySnapshotByID(r.Context(), financialApp.GetMonthlySnapshotByIDInput{
-		SnapshotID:     snapshotID,
+	goal, err := h.app.FinancialService.GetSavingsGoalByID(r.Context(), financialApp.GetSavingsGoalByIDInput{
+		SavingsGoalID:  goalID,
 		UserID:         userID,
 		OrganizationID: organizationID,
 	})
@@ -1353,26 +3396,24 @@ func (h *Handler) GetMonthlySnapshot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	responses.NewSuccess(snapshot, w)
+	responses.NewSuccess(goal, w)
 }
 
-// GetTransactionPlannedEntry retrieves the planned entry linked to a transaction
-// GET /financial/transactions/{id}/planned-entry
-func (h *Handler) GetTransactionPlannedEntry(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetSavingsGoalProgress(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	transactionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	entry, err := h.app.FinancialService.GetPlannedEntryForTransaction(r.Context(), financialApp.GetPlannedEntryForTransactionInput{
-		TransactionID:  transactionID,
+	progress, err := h.app.FinancialService.GetSavingsGoalProgress(r.Context(), financialApp.GetSavingsGoalProgressInput{
+		SavingsGoalID:  goalID,
 		UserID:         userID,
 		OrganizationID: organizationID,
 	})
@@ -1381,20 +3422,36 @@ func (h *Handler) GetTransactionPlannedEntry(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Return empty object if no linked entry (instead of null for better frontend handling)
-	if entry == nil {
-		responses.NewSuccess(struct{}{}, w)
+	responses.NewSuccess(progress, w)
+}
+
+func (h *Handler) GetSavingsGoalSummary(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	responses.NewSuccess(entry, w)
-}
+	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
 
-// ============================================================================
-// Patterns
-// ============================================================================
+	summary, err := h.app.FinancialService.GetGoalSummary(r.Context(), financialApp.GetGoalSummaryInput{
+		SavingsGoalID:  goalID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
 
-func (h *Handler) CreatePattern(w http.ResponseWriter, r *http.Request) {
+	responses.NewSuccess(summary, w)
+}
+
+func (h *Handler) CreateSavingsGoal(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
@@ -1402,14 +3459,18 @@ func (h *Handler) CreatePattern(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Action             financialApp.PatternAction `json:"action"`
-		DescriptionPattern string                     `json:"description_pattern"`
-		DatePattern        *string                    `json:"date_pattern,omitempty"`
-		WeekdayPattern     *string                    `json:"weekday_pattern,omitempty"`
-		AmountRange        *financialApp.AmountRange  `json:"amount_range,omitempty"`
-		TargetDescription  *string                    `json:"target_description,omitempty"`
-		TargetCategoryID   *int                       `json:"target_category_id,omitempty"`
-		ApplyRetroactively bool                       `json:"apply_retroactively"`
+		Name                string   `json:"name"`
+		GoalType            string   `json:"goal_type"`
+		TargetAmount        float64  `json:"target_amount"`
+		InitialAmount       float64  `json:"initial_amount"` // Pre-existing balance
+		DueDate             *string  `json:"due_date,omitempty"`
+		StartDate           *string  `json:"start_date,omitempty"`
+		Icon                *string  `json:"icon,omitempty"`
+		Color               *string  `json:"color,omitempty"`
+		Notes               *string  `json:"notes,omitempty"`
+		CategoryID          *int     `json:"category_id,omitempty"`
+		MonthlyContribution *float64 `json:"monthly_contribution,omitempty"`
+		TagIDs              []int    `json:"tag_ids,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -1417,91 +3478,119 @@ func (h *Handler) CreatePattern(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validation
-	if req.DescriptionPattern == "" {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-	// Parse amount range
-	var amountMin, amountMax *float64
-	if req.AmountRange != nil {
-		amountMin = &req.AmountRange.Min
-		amountMax = &req.AmountRange.Max
+	var monthlyContribution *decimal.Decimal
+	if req.MonthlyContribution != nil {
+		mc := decimal.NewFromFloat(*req.MonthlyContribution)
+		monthlyContribution = &mc
 	}
 
-	pattern, err := h.app.FinancialService.CreatePattern(r.Context(), financialApp.CreatePatternInput{
-		UserID:             userID,
-		OrganizationID:     organizationID,
-		Action:             req.Action,
-		DescriptionPattern: req.DescriptionPattern,
-		DatePattern:        req.DatePattern,
-		WeekdayPattern:     req.WeekdayPattern,
-		AmountMin:          amountMin,
-		AmountMax:          amountMax,
-		TargetDescription:  req.TargetDescription,
-		TargetCategoryID:   req.TargetCategoryID,
-		ApplyRetroactively: req.ApplyRetroactively,
+	goal, err := h.app.FinancialService.CreateSavingsGoal(r.Context(), financialApp.CreateSavingsGoalInput{
+		UserID:              userID,
+		OrganizationID:      organizationID,
+		Name:                req.Name,
+		GoalType:            req.GoalType,
+		TargetAmount:        decimal.NewFromFloat(req.TargetAmount),
+		InitialAmount:       decimal.NewFromFloat(req.InitialAmount),
+		DueDate:             req.DueDate,
+		StartDate:           req.StartDate,
+		Icon:                req.Icon,
+		Color:               req.Color,
+		Notes:               req.Notes,
+		CategoryID:          req.CategoryID,
+		MonthlyContribution: monthlyContribution,
+		TagIDs:              req.TagIDs,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(pattern, w)
+	responses.NewSuccess(goal, w)
 }
 
-func (h *Handler) GetPatterns(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateSavingsGoal(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Parse optional query parameters
-	var isActive *bool
-	var categoryID *int
+	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	var req struct {
+		Name                *string  `json:"name,omitempty"`
+		TargetAmount        *float64 `json:"target_amount,omitempty"`
+		DueDate             *string  `json:"due_date,omitempty"`
+		StartDate           *string  `json:"start_date,omitempty"`
+		Icon                *string  `json:"icon,omitempty"`
+		Color               *string  `json:"color,omitempty"`
+		Notes               *string  `json:"notes,omitempty"`
+		CategoryID          *int     `json:"category_id,omitempty"`
+		MonthlyContribution *float64 `json:"monthly_contribution,omitempty"`
+		TagIDs              *[]int   `json:"tag_ids,omitempty"` // nil = no change, [] = clear tags
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
 
-	if activeStr := r.URL.Query().Get("is_active"); activeStr != "" {
-		b := activeStr == "true"
-		isActive = &b
+	// Convert target amount to decimal if provided
+	var targetAmount *decimal.Decimal
+	if req.TargetAmount != nil {
+		amt := decimal.NewFromFloat(*req.TargetAmount)
+		targetAmount = &amt
 	}
 
-	if catStr := r.URL.Query().Get("category_id"); catStr != "" {
-		c, err := strconv.Atoi(catStr)
-		if err == nil {
-			categoryID = &c
-		}
+	// Convert monthly contribution to decimal if provided
+	var monthlyContribution *decimal.Decimal
+	if req.MonthlyContribution != nil {
+		mc := decimal.NewFromFloat(*req.MonthlyContribution)
+		monthlyContribution = &mc
 	}
 
-	patterns, err := h.app.FinancialService.GetPatterns(r.Context(), financialApp.GetPatternsInput{
-		UserID:         userID,
-		OrganizationID: organizationID,
-		IsActive:       isActive,
-		CategoryID:     categoryID,
+	goal, err := h.app.FinancialService.UpdateSavingsGoal(r.Context(), financialApp.UpdateSavingsGoalInput{
+		SavingsGoalID:       goalID,
+		UserID:              userID,
+		OrganizationID:      organizationID,
+		Name:                req.Name,
+		TargetAmount:        targetAmount,
+		DueDate:             req.DueDate,
+		StartDate:           req.StartDate,
+		Icon:                req.Icon,
+		Color:               req.Color,
+		Notes:               req.Notes,
+		CategoryID:          req.CategoryID,
+		MonthlyContribution: monthlyContribution,
+		TagIDs:              req.TagIDs,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(patterns, w)
+	responses.NewSuccess(goal, w)
 }
 
-func (h *Handler) GetPattern(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) DeleteSavingsGoal(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	patternID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	pattern, err := h.app.FinancialService.GetPatternByID(r.Context(), financialApp.GetPatternByIDInput{
-		PatternID:      patternID,
+	err = h.app.FinancialService.DeleteSavingsGoal(r.Context(), financialApp.DeleteSavingsGoalInput{
+		SavingsGoalID:  goalID,
 		UserID:         userID,
 		OrganizationID: organizationID,
 	})
@@ -1510,87 +3599,50 @@ func (h *Handler) GetPattern(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	responses.NewSuccess(pattern, w)
+	responses.NewSuccess(map[string]string{"message": "savings goal deleted successfully"}, w)
 }
 
-func (h *Handler) UpdatePattern(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) CompleteSavingsGoal(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	patternID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	var req struct {
-		IsActive           *bool                       `json:"is_active,omitempty"`
-		Action             *financialApp.PatternAction `json:"action,omitempty"`
-		DescriptionPattern *string                     `json:"description_pattern,omitempty"`
-		DatePattern        *string                     `json:"date_pattern,omitempty"`
-		WeekdayPattern     *string                     `json:"weekday_pattern,omitempty"`
-		AmountRange        *struct {
-			Min float64 `json:"min"`
-			Max float64 `json:"max"`
-		} `json:"amount_range,omitempty"`
-		TargetDescription *string `json:"target_description,omitempty"`
-		TargetCategoryID  *int    `json:"target_category_id,omitempty"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	// Convert amount_range to strings if provided
-	var amountMin, amountMax *string
-	if req.AmountRange != nil {
-		minStr := fmt.Sprintf("%.2f", req.AmountRange.Min)
-		maxStr := fmt.Sprintf("%.2f", req.AmountRange.Max)
-		amountMin = &minStr
-		amountMax = &maxStr
-	}
-
-	pattern, err := h.app.FinancialService.UpdatePattern(r.Context(), financialApp.UpdatePatternInput{
-		PatternID:          patternID,
-		UserID:             userID,
-		OrganizationID:     organizationID,
-		Action:             req.Action,
-		IsActive:           req.IsActive,
-		DescriptionPattern: req.DescriptionPattern,
-		DatePattern:        req.DatePattern,
-		WeekdayPattern:     req.WeekdayPattern,
-		AmountMin:          amountMin,
-		AmountMax:          amountMax,
-		TargetDescription:  req.TargetDescription,
-		TargetCategoryID:   req.TargetCategoryID,
+	goal, err := h.app.FinancialService.CompleteSavingsGoal(r.Context(), financialApp.CompleteSavingsGoalInput{
+		SavingsGoalID:  goalID,
+		UserID:         userID,
+		OrganizationID: organizationID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(pattern, w)
+	responses.NewSuccess(goal, w)
 }
 
-func (h *Handler) DeletePattern(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) ReopenSavingsGoal(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	patternID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	err = h.app.FinancialService.DeletePattern(r.Context(), financialApp.DeletePatternInput{
-		PatternID:      patternID,
+	goal, err := h.app.FinancialService.ReopenSavingsGoal(r.Context(), financialApp.ReopenSavingsGoalInput{
+		SavingsGoalID:  goalID,
 		UserID:         userID,
 		OrganizationID: organizationID,
 	})
@@ -1599,121 +3651,208 @@ func (h *Handler) DeletePattern(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	responses.NewSuccess(map[string]string{"message": "pattern deleted successfully"}, w)
+	responses.NewSuccess(goal, w)
 }
 
-// ApplyPatternRetroactively applies a pattern to all existing uncategorized transactions
-func (h *Handler) ApplyPatternRetroactively(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) AddContribution(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	patternID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	result, err := h.app.FinancialService.ApplyPatternRetroactivelySync(r.Context(), financialApp.ApplyPatternRetroactivelyInput{
-		PatternID:      patternID,
+	var req struct {
+		Amount float64 `json:"amount"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	progress, err := h.app.FinancialService.AddContribution(r.Context(), financialApp.AddContributionInput{
+		SavingsGoalID:  goalID,
 		UserID:         userID,
 		OrganizationID: organizationID,
+		Amount:         decimal.NewFromFloat(req.Amount),
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(map[string]int{
-		"updated_count": result.UpdatedCount,
-		"total_checked": result.TotalChecked,
-	}, w)
+	responses.NewSuccess(progress, w)
 }
 
 // ============================================================================
-// Income Planning
+// Amazon Sync
 // ============================================================================
 
-func (h *Handler) GetIncomePlanning(w http.ResponseWriter, r *http.Request) {
+// AmazonOrder represents an order extracted from Amazon by the Chrome extension
+type AmazonOrder struct {
+	OrderID    string `json:"order_id"`
+	Date       string `json:"date"`
+	ParsedDate *struct {
+		Day   int    `json:"day"`
+		Month int    `json:"month"`
+		Year  int    `json:"year"`
+		ISO   string `json:"iso"`
+	} `json:"parsed_date,omitempty"`
+	Total       string   `json:"total"`
+	ParsedTotal *float64 `json:"parsed_total,omitempty"`
+	Items       []struct {
+		Name string  `json:"name"`
+		URL  *string `json:"url,omitempty"`
+	} `json:"items"`
+	IsTransaction bool `json:"is_transaction,omitempty"`
+}
+
+// SyncAmazonOrders receives Amazon orders from the Chrome extension and matches them with transactions
+// POST /financial/amazon/sync
+func (h *Handler) SyncAmazonOrders(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Parse query parameters
-	monthStr := r.URL.Query().Get("month")
-	yearStr := r.URL.Query().Get("year")
+	var req struct {
+		Orders []AmazonOrder `json:"orders"`
+		Month  int           `json:"month"`
+		Year   int           `json:"year"`
+	}
 
-	if monthStr == "" || yearStr == "" {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	month, err := strconv.Atoi(monthStr)
-	if err != nil || month < 1 || month > 12 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+	// Validation
+	if len(req.Orders) == 0 {
+		responses.NewSuccess(map[string]interface{}{
+			"matched_count": 0,
+			"total_orders":  0,
+			"message":       "Nenhum pedido enviado",
+		}, w)
+		return
+	}
+
+	// Convert orders to service input
+	amazonOrders := make([]financialApp.AmazonOrderInput, 0, len(req.Orders))
+	for _, order := range req.Orders {
+		var parsedTotal decimal.Decimal
+		if order.ParsedTotal != nil {
+			parsedTotal = decimal.NewFromFloat(*order.ParsedTotal)
+		}
+
+		var parsedDate *string
+		if order.ParsedDate != nil && order.ParsedDate.ISO != "" {
+			parsedDate = &order.ParsedDate.ISO
+		}
+
+		// Build item description
+		var itemDescription string
+		if len(order.Items) > 0 {
+			itemDescription = order.Items[0].Name
+			if len(order.Items) > 1 {
+				itemDescription = fmt.Sprintf("%s (+%d itens)", order.Items[0].Name, len(order.Items)-1)
+			}
+		}
+
+		amazonOrders = append(amazonOrders, financialApp.AmazonOrderInput{
+			OrderID:         order.OrderID,
+			DateString:      order.Date,
+			ParsedDate:      parsedDate,
+			TotalString:     order.Total,
+			ParsedTotal:     parsedTotal,
+			ItemDescription: itemDescription,
+		})
+	}
+
+	result, err := h.app.FinancialService.SyncAmazonOrders(r.Context(), financialApp.SyncAmazonOrdersInput{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Orders:         amazonOrders,
+		Month:          req.Month,
+		Year:           req.Year,
+	})
+	if err != nil {
+		responses.NewError(w, err)
 		return
 	}
 
-	year, err := strconv.Atoi(yearStr)
-	if err != nil || year < 2020 || year > 2100 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+	responses.NewSuccess(result, w)
+}
+
+// ============================================================================
+// Tags
+// ============================================================================
+
+// tagColorPalette contains visually appealing colors for tags
+var tagColorPalette = []string{
+	"#6366F1", // Indigo
+	"#8B5CF6", // Violet
+	"#EC4899", // Pink
+	"#EF4444", // Red
+	"#F97316", // Orange
+	"#F59E0B", // Amber
+	"#84CC16", // Lime
+	"#10B981", // Emerald
+	"#14B8A6", // Teal
+	"#06B6D4", // Cyan
+	"#3B82F6", // Blue
+	"#A855F7", // Purple
+}
+
+func getRandomTagColor() string {
+	return tagColorPalette[rand.Intn(len(tagColorPalette))]
+}
+
+func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	report, err := h.app.FinancialService.GetIncomePlanning(r.Context(), financialApp.GetIncomePlanningInput{
+	tags, err := h.app.FinancialService.GetTags(r.Context(), financialApp.GetTagsInput{
 		UserID:         userID,
 		OrganizationID: organizationID,
-		Month:          month,
-		Year:           year,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(report, w)
+	responses.NewSuccess(tags, w)
 }
 
-// ============================================================================
-// Planned Entry Statuses (Entrada Planejada)
-// ============================================================================
-
-// GetPlannedEntriesForMonth returns planned entries with their status for a given month/year
-// GET /financial/planned-entries/month?month=X&year=Y
-func (h *Handler) GetPlannedEntriesForMonth(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) ListTagSpending(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Parse required query parameters
-	monthStr := r.URL.Query().Get("month")
-	yearStr := r.URL.Query().Get("year")
-
-	if monthStr == "" || yearStr == "" {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	month, err := strconv.Atoi(monthStr)
+	month, err := strconv.Atoi(r.URL.Query().Get("month"))
 	if err != nil || month < 1 || month > 12 {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	year, err := strconv.Atoi(yearStr)
-	if err != nil || year < 2000 || year > 2100 {
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil || year < 1900 {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	entries, err := h.app.FinancialService.GetPlannedEntriesForMonth(r.Context(), financialApp.GetPlannedEntriesForMonthInput{
-		UserID:         userID,
+	spending, err := h.app.FinancialService.GetTagSpending(r.Context(), financialApp.GetTagSpendingInput{
 		OrganizationID: organizationID,
 		Month:          month,
 		Year:           year,
@@ -1723,46 +3862,24 @@ func (h *Handler) GetPlannedEntriesForMonth(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	responses.NewSuccess(entries, w)
+	responses.NewSuccess(spending, w)
 }
 
-// MatchPlannedEntry matches a transaction to a planned entry for a specific month
-// POST /financial/planned-entries/{id}/match
-func (h *Handler) MatchPlannedEntry(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetTag(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	tagID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	var req struct {
-		TransactionID int `json:"transaction_id"`
-		Month         int `json:"month"`
-		Year          int `json:"year"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	// Validation
-	if req.TransactionID == 0 || req.Month < 1 || req.Month > 12 || req.Year < 2000 || req.Year > 2100 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	status, err := h.app.FinancialService.MatchPlannedEntryToTransaction(r.Context(), financialApp.MatchPlannedEntryInput{
-		PlannedEntryID: entryID,
-		TransactionID:  req.TransactionID,
-		Month:          req.Month,
-		Year:           req.Year,
+	tag, err := h.app.FinancialService.GetTagByID(r.Context(), financialApp.GetTagByIDInput{
+		TagID:          tagID,
 		UserID:         userID,
 		OrganizationID: organizationID,
 	})
@@ -1771,79 +3888,67 @@ func (h *Handler) MatchPlannedEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	responses.NewSuccess(status, w)
+	responses.NewSuccess(tag, w)
 }
 
-// UnmatchPlannedEntry removes the match between a planned entry and a transaction
-// DELETE /financial/planned-entries/{id}/match
-func (h *Handler) UnmatchPlannedEntry(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) CreateTag(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
-	if err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
+	var req struct {
+		Name  string `json:"name"`
+		Icon  string `json:"icon"`
+		Color string `json:"color"`
 	}
 
-	// Parse query parameters for month/year
-	monthStr := r.URL.Query().Get("month")
-	yearStr := r.URL.Query().Get("year")
-
-	if monthStr == "" || yearStr == "" {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	month, err := strconv.Atoi(monthStr)
-	if err != nil || month < 1 || month > 12 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
+	// Set defaults
+	if req.Icon == "" {
+		req.Icon = "🏷️"
 	}
-
-	year, err := strconv.Atoi(yearStr)
-	if err != nil || year < 2000 || year > 2100 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
+	if req.Color == "" {
+		req.Color = getRandomTagColor()
 	}
 
-	err = h.app.FinancialService.UnmatchPlannedEntry(r.Context(), financialApp.UnmatchPlannedEntryInput{
-		PlannedEntryID: entryID,
-		Month:          month,
-		Year:           year,
+	tag, err := h.app.FinancialService.CreateTag(r.Context(), financialApp.CreateTagInput{
 		UserID:         userID,
 		OrganizationID: organizationID,
+		Name:           req.Name,
+		Icon:           req.Icon,
+		Color:          req.Color,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(map[string]string{"message": "planned entry unmatched successfully"}, w)
+	responses.NewSuccess(tag, w)
 }
 
-// DismissPlannedEntry dismisses a planned entry for a specific month
-// POST /financial/planned-entries/{id}/dismiss
-func (h *Handler) DismissPlannedEntry(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateTag(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	tagID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
 	var req struct {
-		Month  int     `json:"month"`
-		Year   int     `json:"year"`
-		Reason *string `json:"reason,omitempty"`
+		Name  *string `json:"name"`
+		Icon  *string `json:"icon"`
+		Color *string `json:"color"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -1851,74 +3956,37 @@ func (h *Handler) DismissPlannedEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validation
-	if req.Month < 1 || req.Month > 12 || req.Year < 2000 || req.Year > 2100 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	// Convert optional reason to string
-	reason := ""
-	if req.Reason != nil {
-		reason = *req.Reason
-	}
-
-	status, err := h.app.FinancialService.DismissPlannedEntry(r.Context(), financialApp.DismissPlannedEntryInput{
-		PlannedEntryID: entryID,
-		Month:          req.Month,
-		Year:           req.Year,
-		Reason:         reason,
+	tag, err := h.app.FinancialService.UpdateTag(r.Context(), financialApp.UpdateTagInput{
+		TagID:          tagID,
 		UserID:         userID,
 		OrganizationID: organizationID,
+		Name:           req.Name,
+		Icon:           req.Icon,
+		Color:          req.Color,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(status, w)
+	responses.NewSuccess(tag, w)
 }
 
-// UndismissPlannedEntry undismisses a planned entry for a specific month
-// DELETE /financial/planned-entries/{id}/dismiss
-func (h *Handler) UndismissPlannedEntry(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	tagID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	// Parse query parameters for month/year
-	monthStr := r.URL.Query().Get("month")
-	yearStr := r.URL.Query().Get("year")
-
-	if monthStr == "" || yearStr == "" {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	month, err := strconv.Atoi(monthStr)
-	if err != nil || month < 1 || month > 12 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	year, err := strconv.Atoi(yearStr)
-	if err != nil || year < 2000 || year > 2100 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	status, err := h.app.FinancialService.UndismissPlannedEntry(r.Context(), financialApp.UndismissPlannedEntryInput{
-		PlannedEntryID: entryID,
-		Month:          month,
-		Year:           year,
+	err = h.app.FinancialService.DeleteTag(r.Context(), financialApp.DeleteTagInput{
+		TagID:          tagID,
 		UserID:         userID,
 		OrganizationID: organizationID,
 	})
@@ -1927,123 +3995,92 @@ func (h *Handler) UndismissPlannedEntry(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	responses.NewSuccess(status, w)
+	responses.NewSuccess(map[string]string{"message": "tag deleted successfully"}, w)
 }
 
 // ============================================================================
-// Savings Goals
+// Transaction Tags
 // ============================================================================
 
-func (h *Handler) ListSavingsGoals(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) GetTransactionTags(w http.ResponseWriter, r *http.Request) {
+	_, _, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Parse optional query parameters
-	// Default to is_active=true to hide soft-deleted goals unless explicitly requested
-	defaultActive := true
-	isActive := &defaultActive
-	if activeStr := r.URL.Query().Get("is_active"); activeStr != "" {
-		active := activeStr == "true"
-		isActive = &active
-	}
-
-	var isCompleted *bool
-	if completedStr := r.URL.Query().Get("is_completed"); completedStr != "" {
-		completed := completedStr == "true"
-		isCompleted = &completed
-	}
-
-	var goalType *string
-	if typeStr := r.URL.Query().Get("goal_type"); typeStr != "" {
-		goalType = &typeStr
+	transactionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	goals, err := h.app.FinancialService.GetSavingsGoals(r.Context(), financialApp.GetSavingsGoalsInput{
-		UserID:         userID,
-		OrganizationID: organizationID,
-		IsActive:       isActive,
-		IsCompleted:    isCompleted,
-		GoalType:       goalType,
+	tags, err := h.app.FinancialService.GetTransactionTags(r.Context(), financialApp.GetTransactionTagsInput{
+		TransactionID: transactionID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(goals, w)
+	responses.NewSuccess(tags, w)
 }
 
-func (h *Handler) GetSavingsGoal(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SetTransactionTags(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	transactionID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	goal, err := h.app.FinancialService.GetSavingsGoalByID(r.Context(), financialApp.GetSavingsGoalByIDInput{
-		SavingsGoalID:  goalID,
-		UserID:         userID,
-		OrganizationID: organizationID,
-	})
-	if err != nil {
-		responses.NewError(w, err)
-		return
-	}
-
-	responses.NewSuccess(goal, w)
-}
-
-func (h *Handler) GetSavingsGoalProgress(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
-	if err != nil {
-		responses.NewError(w, errors.ErrUnauthorized)
-		return
+	var req struct {
+		TagIDs []int `json:"tag_ids"`
 	}
 
-	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
-	if err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	progress, err := h.app.FinancialService.GetSavingsGoalProgress(r.Context(), financialApp.GetSavingsGoalProgressInput{
-		SavingsGoalID:  goalID,
+	err = h.app.FinancialService.SetTransactionTags(r.Context(), financialApp.SetTransactionTagsInput{
+		TransactionID:  transactionID,
 		UserID:         userID,
 		OrganizationID: organizationID,
+		TagIDs:         req.TagIDs,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(progress, w)
+	responses.NewSuccess(map[string]string{"message": "tags updated successfully"}, w)
 }
 
-func (h *Handler) GetSavingsGoalSummary(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+// ============================================================================
+// Transaction Splits
+// ============================================================================
+
+func (h *Handler) GetTransactionSplits(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	transactionID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	summary, err := h.app.FinancialService.GetGoalSummary(r.Context(), financialApp.GetGoalSummaryInput{
-		SavingsGoalID:  goalID,
-		UserID:         userID,
+	splits, err := h.app.FinancialService.GetTransactionSplits(r.Context(), financialApp.GetTransactionSplitsInput{
+		TransactionID:  transactionID,
 		OrganizationID: organizationID,
 	})
 	if err != nil {
@@ -2051,202 +4088,174 @@ func (h *Handler) GetSavingsGoalSummary(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	responses.NewSuccess(summary, w)
+	responses.NewSuccess(splits, w)
 }
 
-func (h *Handler) CreateSavingsGoal(w http.ResponseWriter, r *http.Request) {
+// SetTransactionSplits replaces the splits of a transaction; an empty list
+// removes them.
+func (h *Handler) SetTransactionSplits(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	var req struct {
-		Name                string   `json:"name"`
-		GoalType            string   `json:"goal_type"`
-		TargetAmount        float64  `json:"target_amount"`
-		InitialAmount       float64  `json:"initial_amount"` // Pre-existing balance
-		DueDate             *string  `json:"due_date,omitempty"`
-		StartDate           *string  `json:"start_date,omitempty"`
-		Icon                *string  `json:"icon,omitempty"`
-		Color               *string  `json:"color,omitempty"`
-		Notes               *string  `json:"notes,omitempty"`
-		CategoryID          *int     `json:"category_id,omitempty"`
-		MonthlyContribution *float64 `json:"monthly_contribution,omitempty"`
-		TagIDs              []int    `json:"tag_ids,omitempty"`
+	transactionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
+	var req struct {
+		Splits []struct {
+			CategoryID    *int    `json:"category_id"`
+			SavingsGoalID *int    `json:"savings_goal_id"`
+			Amount        float64 `json:"amount"`
+			Notes         *string `json:"notes"`
+			TagIDs        []int   `json:"tag_ids"`
+		} `json:"splits"`
+	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	var monthlyContribution *decimal.Decimal
-	if req.MonthlyContribution != nil {
-		mc := decimal.NewFromFloat(*req.MonthlyContribution)
-		monthlyContribution = &mc
+	input := financialApp.SetTransactionSplitsInput{
+		TransactionID:  transactionID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Splits:         make([]financialApp.TransactionSplitInput, len(req.Splits)),
+	}
+	for i, split := range req.Splits {
+		input.Splits[i] = financialApp.TransactionSplitInput{
+			CategoryID:    split.CategoryID,
+			SavingsGoalID: split.SavingsGoalID,
+			Amount:        decimal.NewFromFloat(split.Amount),
+			Notes:         split.Notes,
+			TagIDs:        split.TagIDs,
+		}
 	}
 
-	goal, err := h.app.FinancialService.CreateSavingsGoal(r.Context(), financialApp.CreateSavingsGoalInput{
-		UserID:              userID,
-		OrganizationID:      organizationID,
-		Name:                req.Name,
-		GoalType:            req.GoalType,
-		TargetAmount:        decimal.NewFromFloat(req.TargetAmount),
-		InitialAmount:       decimal.NewFromFloat(req.InitialAmount),
-		DueDate:             req.DueDate,
-		StartDate:           req.StartDate,
-		Icon:                req.Icon,
-		Color:               req.Color,
-		Notes:               req.Notes,
-		CategoryID:          req.CategoryID,
-		MonthlyContribution: monthlyContribution,
-		TagIDs:              req.TagIDs,
-	})
+	splits, err := h.app.FinancialService.SetTransactionSplits(r.Context(), input)
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(goal, w)
+	responses.NewSuccess(splits, w)
 }
 
-func (h *Handler) UpdateSavingsGoal(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+// ============================================================================
+// Transfers
+// ============================================================================
+
+func (h *Handler) ListTransfers(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	transfers, err := h.app.FinancialService.GetTransfers(r.Context(), financialApp.GetTransfersInput{
+		OrganizationID: organizationID,
+	})
 	if err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+		responses.NewError(w, err)
 		return
 	}
 
-	var req struct {
-		Name                *string  `json:"name,omitempty"`
-		TargetAmount        *float64 `json:"target_amount,omitempty"`
-		DueDate             *string  `json:"due_date,omitempty"`
-		StartDate           *string  `json:"start_date,omitempty"`
-		Icon                *string  `json:"icon,omitempty"`
-		Color               *string  `json:"color,omitempty"`
-		Notes               *string  `json:"notes,omitempty"`
-		CategoryID          *int     `json:"category_id,omitempty"`
-		MonthlyContribution *float64 `json:"monthly_contribution,omitempty"`
-		TagIDs              *[]int   `json:"tag_ids,omitempty"` // nil = no change, [] = clear tags
-	}
+	responses.NewSuccess(transfers, w)
+}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+// LinkTransfer marks a debit and a credit in two of the organization's
+// accounts as one transfer.
+func (h *Handler) LinkTransfer(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	// Convert target amount to decimal if provided
-	var targetAmount *decimal.Decimal
-	if req.TargetAmount != nil {
-		amt := decimal.NewFromFloat(*req.TargetAmount)
-		targetAmount = &amt
+	var req struct {
+		DebitTransactionID  int `json:"debit_transaction_id"`
+		CreditTransactionID int `json:"credit_transaction_id"`
 	}
-
-	// Convert monthly contribution to decimal if provided
-	var monthlyContribution *decimal.Decimal
-	if req.MonthlyContribution != nil {
-		mc := decimal.NewFromFloat(*req.MonthlyContribution)
-		monthlyContribution = &mc
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	goal, err := h.app.FinancialService.UpdateSavingsGoal(r.Context(), financialApp.UpdateSavingsGoalInput{
-		SavingsGoalID:       goalID,
-		UserID:              userID,
+	transfer, err := h.app.FinancialService.LinkTransfer(r.Context(), financialApp.LinkTransferInput{
 		OrganizationID:      organizationID,
-		Name:                req.Name,
-		TargetAmount:        targetAmount,
-		DueDate:             req.DueDate,
-		StartDate:           req.StartDate,
-		Icon:                req.Icon,
-		Color:               req.Color,
-		Notes:               req.Notes,
-		CategoryID:          req.CategoryID,
-		MonthlyContribution: monthlyContribution,
-		TagIDs:              req.TagIDs,
+		DebitTransactionID:  req.DebitTransactionID,
+		CreditTransactionID: req.CreditTransactionID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(goal, w)
+	responses.NewSuccess(transfer, w)
 }
 
-func (h *Handler) DeleteSavingsGoal(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+// DetectTransfers links the transfers found between from and to (YYYY-MM-DD,
+// both optional) and returns the uncertain pairs as suggestions.
+func (h *Handler) DetectTransfers(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
-	if err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	err = h.app.FinancialService.DeleteSavingsGoal(r.Context(), financialApp.DeleteSavingsGoalInput{
-		SavingsGoalID:  goalID,
-		UserID:         userID,
+	result, err := h.app.FinancialService.DetectTransfers(r.Context(), financialApp.DetectTransfersInput{
 		OrganizationID: organizationID,
+		From:           r.URL.Query().Get("from"),
+		To:             r.URL.Query().Get("to"),
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(map[string]string{"message": "savings goal deleted successfully"}, w)
+	responses.NewSuccess(result, w)
 }
 
-func (h *Handler) CompleteSavingsGoal(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) UnlinkTransfer(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	transferID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	goal, err := h.app.FinancialService.CompleteSavingsGoal(r.Context(), financialApp.CompleteSavingsGoalInput{
-		SavingsGoalID:  goalID,
-		UserID:         userID,
+	if err := h.app.FinancialService.UnlinkTransfer(r.Context(), financialApp.UnlinkTransferInput{
+		TransferID:     transferID,
 		OrganizationID: organizationID,
-	})
-	if err != nil {
+	}); err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(goal, w)
+	responses.NewSuccess(map[string]string{"message": "transfer unlinked successfully"}, w)
 }
 
-func (h *Handler) ReopenSavingsGoal(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
-	if err != nil {
-		responses.NewError(w, errors.ErrUnauthorized)
-		return
-	}
+// ============================================================================
+// Installment Plans
+// ============================================================================
 
-	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
+func (h *Handler) ListInstallmentPlans(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	goal, err := h.app.FinancialService.ReopenSavingsGoal(r.Context(), financialApp.ReopenSavingsGoalInput{
-		SavingsGoalID:  goalID,
-		UserID:         userID,
+	plans, err := h.app.FinancialService.GetInstallmentPlans(r.Context(), financialApp.GetInstallmentPlansInput{
 		OrganizationID: organizationID,
 	})
 	if err != nil {
@@ -2254,178 +4263,95 @@ func (h *Handler) ReopenSavingsGoal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	responses.NewSuccess(goal, w)
+	responses.NewSuccess(plans, w)
 }
 
-func (h *Handler) AddContribution(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+// DetectInstallments groups imported card parcels into plans and projects the
+// parcels still to come.
+func (h *Handler) DetectInstallments(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	goalID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	result, err := h.app.FinancialService.DetectInstallments(r.Context(), financialApp.DetectInstallmentsInput{
+		OrganizationID: organizationID,
+	})
 	if err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+		responses.NewError(w, err)
 		return
 	}
 
-	var req struct {
-		Amount float64 `json:"amount"`
-	}
+	responses.NewSuccess(result, w)
+}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+func (h *Handler) GetOutstandingInstallments(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	progress, err := h.app.FinancialService.AddContribution(r.Context(), financialApp.AddContributionInput{
-		SavingsGoalID:  goalID,
-		UserID:         userID,
+	result, err := h.app.FinancialService.GetOutstandingInstallments(r.Context(), financialApp.GetOutstandingInstallmentsInput{
 		OrganizationID: organizationID,
-		Amount:         decimal.NewFromFloat(req.Amount),
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(progress, w)
-}
-
-// ============================================================================
-// Amazon Sync
-// ============================================================================
-
-// AmazonOrder represents an order extracted from Amazon by the Chrome extension
-type AmazonOrder struct {
-	OrderID    string `json:"order_id"`
-	Date       string `json:"date"`
-	ParsedDate *struct {
-		Day   int    `json:"day"`
-		Month int    `json:"month"`
-		Year  int    `json:"year"`
-		ISO   string `json:"iso"`
-	} `json:"parsed_date,omitempty"`
-	Total       string   `json:"total"`
-	ParsedTotal *float64 `json:"parsed_total,omitempty"`
-	Items       []struct {
-		Name string  `json:"name"`
-		URL  *string `json:"url,omitempty"`
-	} `json:"items"`
-	IsTransaction bool `json:"is_transaction,omitempty"`
+	responses.NewSuccess(result, w)
 }
 
-// SyncAmazonOrders receives Amazon orders from the Chrome extension and matches them with transactions
-// POST /financial/amazon/sync
-func (h *Handler) SyncAmazonOrders(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) UpdateInstallmentPlan(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	var req struct {
-		Orders []AmazonOrder `json:"orders"`
-		Month  int           `json:"month"`
-		Year   int           `json:"year"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	planID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	// Validation
-	if len(req.Orders) == 0 {
-		responses.NewSuccess(map[string]interface{}{
-			"matched_count": 0,
-			"total_orders":  0,
-			"message":       "Nenhum pedido enviado",
-		}, w)
-		return
-	}
-
-	// Convert orders to service input
-	amazonOrders := make([]financialApp.AmazonOrderInput, 0, len(req.Orders))
-	for _, order := range req.Orders {
-		var parsedTotal decimal.Decimal
-		if order.ParsedTotal != nil {
-			parsedTotal = decimal.NewFromFloat(*order.ParsedTotal)
-		}
-
-		var parsedDate *string
-		if order.ParsedDate != nil && order.ParsedDate.ISO != "" {
-			parsedDate = &order.ParsedDate.ISO
-		}
-
-		// Build item description
-		var itemDescription string
-		if len(order.Items) > 0 {
-			itemDescription = order.Items[0].Name
-			if len(order.Items) > 1 {
-				itemDescription = fmt.Sprintf("%s (+%d itens)", order.Items[0].Name, len(order.Items)-1)
-			}
-		}
+		return
+	}
 
-		amazonOrders = append(amazonOrders, financialApp.AmazonOrderInput{
-			OrderID:         order.OrderID,
-			DateString:      order.Date,
-			ParsedDate:      parsedDate,
-			TotalString:     order.Total,
-			ParsedTotal:     parsedTotal,
-			ItemDescription: itemDescription,
-		})
+	var req struct {
+		CategoryID *int  `json:"category_id"`
+		IsActive   *bool `json:"is_active"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
 	}
 
-	result, err := h.app.FinancialService.SyncAmazonOrders(r.Context(), financialApp.SyncAmazonOrdersInput{
-		UserID:         userID,
-		OrganizationID: organizationID,
-		Orders:         amazonOrders,
-		Month:          req.Month,
-		Year:           req.Year,
+	plan, err := h.app.FinancialService.UpdateInstallmentPlan(r.Context(), financialApp.UpdateInstallmentPlanInput{
+		InstallmentPlanID: planID,
+		OrganizationID:    organizationID,
+		CategoryID:        req.CategoryID,
+		IsActive:          req.IsActive,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(result, w)
+	responses.NewSuccess(plan, w)
 }
 
 // ============================================================================
-// Tags
+// Currencies
 // ============================================================================
 
-// tagColorPalette contains visually appealing colors for tags
-var tagColorPalette = []string{
-	"#6366F1", // Indigo
-	"#8B5CF6", // Violet
-	"#EC4899", // Pink
-	"#EF4444", // Red
-	"#F97316", // Orange
-	"#F59E0B", // Amber
-	"#84CC16", // Lime
-	"#10B981", // Emerald
-	"#14B8A6", // Teal
-	"#06B6D4", // Cyan
-	"#3B82F6", // Blue
-	"#A855F7", // Purple
-}
-
-func getRandomTagColor() string {
-	return tagColorPalette[rand.Intn(len(tagColorPalette))]
-}
-
-func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+func (h *Handler) GetBaseCurrency(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	tags, err := h.app.FinancialService.GetTags(r.Context(), financialApp.GetTagsInput{
-		UserID:         userID,
+	currency, err := h.app.FinancialService.GetBaseCurrency(r.Context(), financialApp.GetBaseCurrencyInput{
 		OrganizationID: organizationID,
 	})
 	if err != nil {
@@ -2433,232 +4359,398 @@ func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	responses.NewSuccess(tags, w)
+	responses.NewSuccess(map[string]string{"base_currency": currency}, w)
 }
 
-func (h *Handler) ListTagSpending(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SetBaseCurrency(w http.ResponseWriter, r *http.Request) {
 	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	month, err := strconv.Atoi(r.URL.Query().Get("month"))
-	if err != nil || month < 1 || month > 12 {
+	var req struct {
+		BaseCurrency string `json:"base_currency"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	year, err := strconv.Atoi(r.URL.Query().Get("year"))
-	if err != nil || year < 1900 {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+	currency, err := h.app.FinancialService.SetBaseCurrency(r.Context(), financialApp.SetBaseCurrencyInput{
+		OrganizationID: organizationID,
+		BaseCurrency:   req.BaseCurrency,
+	})
+	if err != nil {
+		responses.NewError(w, err)
 		return
 	}
 
-	spending, err := h.app.FinancialService.GetTagSpending(r.Context(), financialApp.GetTagSpendingInput{
+	responses.NewSuccess(map[string]string{"base_currency": currency}, w)
+}
+
+// GetCashFlowForecast projects daily account balances for the next months
+// (months, default 3). account_id chooses where planned entries without a
+// matching history settle.
+func (h *Handler) GetCashFlowForecast(w http.ResponseWriter, r *http.Request) {
+	userID, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	input := financialApp.GetCashFlowForecastInput{
+		UserID:         userID,
 		OrganizationID: organizationID,
-		Month:          month,
-		Year:           year,
-	})
+	}
+	if raw := r.URL.Query().Get("months"); raw != "" {
+		months, err := strconv.Atoi(raw)
+		if err != nil {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+		input.Months = months
+	}
+	if raw := r.URL.Query().Get("account_id"); raw != "" {
+		accountID, err := strconv.Atoi(raw)
+		if err != nil {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+		input.AccountID = &accountID
+	}
+
+	forecast, err := h.app.FinancialService.GetCashFlowForecast(r.Context(), input)
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(spending, w)
+	responses.NewSuccess(forecast, w)
 }
 
-func (h *Handler) GetTag(w http.ResponseWriter, r *http.Request) {
+// Reports take an optional range of months, from and to (YYYY-MM), and cover
+// the last 12 months without one.
+
+// parseOptionalIntQuery reads an integer query parameter, nil when absent
+func parseOptionalIntQuery(r *http.Request, name string) (*int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// GetCategoryTrends reports monthly spending per category, optionally for one
+// category (category_id).
+func (h *Handler) GetCategoryTrends(w http.ResponseWriter, r *http.Request) {
 	userID, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	tagID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	categoryID, err := parseOptionalIntQuery(r, "category_id")
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	tag, err := h.app.FinancialService.GetTagByID(r.Context(), financialApp.GetTagByIDInput{
-		TagID:          tagID,
+	report, err := h.app.FinancialService.GetCategoryTrends(r.Context(), financialApp.GetCategoryTrendsInput{
 		UserID:         userID,
 		OrganizationID: organizationID,
+		From:           r.URL.Query().Get("from"),
+		To:             r.URL.Query().Get("to"),
+		CategoryID:     categoryID,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(tag, w)
+	responses.NewSuccess(report, w)
 }
 
-func (h *Handler) CreateTag(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+// GetYearOverYear compares a year's spending (year, default the current one)
+// with another's (compare_year, default the year before).
+func (h *Handler) GetYearOverYear(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	var req struct {
-		Name  string `json:"name"`
-		Icon  string `json:"icon"`
-		Color string `json:"color"`
+	input := financialApp.GetYearOverYearInput{OrganizationID: organizationID}
+	year, err := parseOptionalIntQuery(r, "year")
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if year != nil {
+		input.Year = *year
+	}
+	compareYear, err := parseOptionalIntQuery(r, "compare_year")
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if compareYear != nil {
+		input.CompareYear = *compareYear
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	report, err := h.app.FinancialService.GetYearOverYear(r.Context(), input)
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	responses.NewSuccess(report, w)
+}
+
+// GetTopMerchants lists the merchants most was spent at (limit, default 10).
+func (h *Handler) GetTopMerchants(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
+	}
+
+	input := financialApp.GetTopMerchantsInput{
+		OrganizationID: organizationID,
+		From:           r.URL.Query().Get("from"),
+		To:             r.URL.Query().Get("to"),
+	}
+	limit, err := parseOptionalIntQuery(r, "limit")
+	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
+	if limit != nil {
+		input.Limit = *limit
+	}
 
-	// Set defaults
-	if req.Icon == "" {
-		req.Icon = "🏷️"
+	report, err := h.app.FinancialService.GetTopMerchants(r.Context(), input)
+	if err != nil {
+		responses.NewError(w, err)
+		return
 	}
-	if req.Color == "" {
-		req.Color = getRandomTagColor()
+
+	responses.NewSuccess(report, w)
+}
+
+// GetIncomeExpense reports income against expenses month by month.
+func (h *Handler) GetIncomeExpense(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
+		return
 	}
 
-	tag, err := h.app.FinancialService.CreateTag(r.Context(), financialApp.CreateTagInput{
-		UserID:         userID,
+	report, err := h.app.FinancialService.GetIncomeExpense(r.Context(), financialApp.GetIncomeExpenseInput{
 		OrganizationID: organizationID,
-		Name:           req.Name,
-		Icon:           req.Icon,
-		Color:          req.Color,
+		From:           r.URL.Query().Get("from"),
+		To:             r.URL.Query().Get("to"),
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(tag, w)
+	responses.NewSuccess(report, w)
 }
 
-func (h *Handler) UpdateTag(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+// GetReportTransactions lists the transactions behind a report figure,
+// narrowed by category_id, uncategorized=true, merchant or transaction_type,
+// and paged with limit and offset.
+func (h *Handler) GetReportTransactions(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	tagID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	query := r.URL.Query()
+	input := financialApp.GetReportTransactionsInput{
+		OrganizationID:  organizationID,
+		From:            query.Get("from"),
+		To:              query.Get("to"),
+		Merchant:        query.Get("merchant"),
+		TransactionType: query.Get("transaction_type"),
+	}
+	if raw := query.Get("uncategorized"); raw != "" {
+		uncategorized, err := strconv.ParseBool(raw)
+		if err != nil {
+			responses.NewError(w, errors.ErrInvalidRequestBody)
+			return
+		}
+		input.Uncategorized = uncategorized
+	}
+	categoryID, err := parseOptionalIntQuery(r, "category_id")
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	input.CategoryID = categoryID
+	limit, err := parseOptionalIntQuery(r, "limit")
+	if err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if limit != nil {
+		input.Limit = *limit
+	}
+	offset, err := parseOptionalIntQuery(r, "offset")
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
+	if offset != nil {
+		input.Offset = *offset
+	}
 
-	var req struct {
-		Name  *string `json:"name"`
-		Icon  *string `json:"icon"`
-		Color *string `json:"color"`
+	transactions, err := h.app.FinancialService.GetReportTransactions(r.Context(), input)
+	if err != nil {
+		responses.NewError(w, err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
+	responses.NewSuccess(transactions, w)
+}
+
+// ListExchangeRates lists the rates kept against the base currency, optionally
+// for one currency and between from and to (YYYY-MM-DD).
+func (h *Handler) ListExchangeRates(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
+	if err != nil {
+		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	tag, err := h.app.FinancialService.UpdateTag(r.Context(), financialApp.UpdateTagInput{
-		TagID:          tagID,
-		UserID:         userID,
+	rates, err := h.app.FinancialService.GetExchangeRates(r.Context(), financialApp.GetExchangeRatesInput{
 		OrganizationID: organizationID,
-		Name:           req.Name,
-		Icon:           req.Icon,
-		Color:          req.Color,
+		Currency:       r.URL.Query().Get("currency"),
+		From:           r.URL.Query().Get("from"),
+		To:             r.URL.Query().Get("to"),
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(tag, w)
+	responses.NewSuccess(rates, w)
 }
 
-func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
-	userID, organizationID, err := h.getSessionInfo(r)
+// ImportExchangeRates reads a CSV of daily rates from the rates_file form field.
+func (h *Handler) ImportExchangeRates(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	tagID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10 MB max
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+
+	file, _, err := r.FormFile("rates_file")
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
+	defer file.Close()
 
-	err = h.app.FinancialService.DeleteTag(r.Context(), financialApp.DeleteTagInput{
-		TagID:          tagID,
-		UserID:         userID,
+	fileBytes, err := io.ReadAll(io.LimitReader(file, 10<<20))
+	if err != nil {
+		responses.NewError(w, err)
+		return
+	}
+
+	result, err := h.app.FinancialService.ImportExchangeRates(r.Context(), financialApp.ImportExchangeRatesInput{
 		OrganizationID: organizationID,
+		CSVData:        fileBytes,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(map[string]string{"message": "tag deleted successfully"}, w)
+	responses.NewSuccess(result, w)
 }
 
 // ============================================================================
-// Transaction Tags
+// Audit Log
 // ============================================================================
 
-func (h *Handler) GetTransactionTags(w http.ResponseWriter, r *http.Request) {
-	_, _, err := h.getSessionInfo(r)
+// GetAuditLog lists who changed what, newest first, filtered by entity_type,
+// entity_id, user_id, action and a from/to range (YYYY-MM-DD). Pages continue
+// from the previous page's next_cursor.
+func (h *Handler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	transactionID, err := strconv.Atoi(chi.URLParam(r, "id"))
-	if err != nil {
+	query := r.URL.Query()
+	input := financialApp.GetAuditLogInput{
+		OrganizationID: organizationID,
+		EntityType:     query.Get("entity_type"),
+		Action:         query.Get("action"),
+		From:           query.Get("from"),
+		To:             query.Get("to"),
+		Cursor:         query.Get("cursor"),
+	}
+	if input.EntityID, err = parseOptionalIntQuery(r, "entity_id"); err != nil {
+		responses.NewError(w, errors.ErrInvalidRequestBody)
+		return
+	}
+	if input.UserID, err = parseOptionalIntQuery(r, "user_id"); err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
+	input.Limit, _ = strconv.Atoi(query.Get("limit"))
 
-	tags, err := h.app.FinancialService.GetTransactionTags(r.Context(), financialApp.GetTransactionTagsInput{
-		TransactionID: transactionID,
-	})
+	page, err := h.app.FinancialService.GetAuditLog(r.Context(), input)
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(tags, w)
+	responses.NewSuccess(page, w)
 }
 
-func (h *Handler) SetTransactionTags(w http.ResponseWriter, r *http.Request) {
-	_, _, err := h.getSessionInfo(r)
+// GetEntityAuditLog lists the history of one transaction, budget, pattern,
+// planned entry, savings goal or month (YYYYMM).
+func (h *Handler) GetEntityAuditLog(w http.ResponseWriter, r *http.Request) {
+	_, organizationID, err := h.getSessionInfo(r)
 	if err != nil {
 		responses.NewError(w, errors.ErrUnauthorized)
 		return
 	}
 
-	transactionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	entityID, err := strconv.Atoi(chi.URLParam(r, "entityId"))
 	if err != nil {
 		responses.NewError(w, errors.ErrInvalidRequestBody)
 		return
 	}
 
-	var req struct {
-		TagIDs []int `json:"tag_ids"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		responses.NewError(w, errors.ErrInvalidRequestBody)
-		return
-	}
-
-	err = h.app.FinancialService.SetTransactionTags(r.Context(), financialApp.SetTransactionTagsInput{
-		TransactionID: transactionID,
-		TagIDs:        req.TagIDs,
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	page, err := h.app.FinancialService.GetAuditLog(r.Context(), financialApp.GetAuditLogInput{
+		OrganizationID: organizationID,
+		EntityType:     chi.URLParam(r, "entityType"),
+		EntityID:       &entityID,
+		Cursor:         r.URL.Query().Get("cursor"),
+		Limit:          limit,
 	})
 	if err != nil {
 		responses.NewError(w, err)
 		return
 	}
 
-	responses.NewSuccess(map[string]string{"message": "tags updated successfully"}, w)
+	responses.NewSuccess(page, w)
 }
@@ -6,6 +6,8 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/catrutech/celeiro/internal/application"
 	"github.com/catrutech/celeiro/internal/application/accounts"
@@ -22,16 +24,60 @@ type Middleware interface {
 	extractActiveOrganization(r *http.Request) int
 }
 
+// sessionOrganizationsTTL bounds how long a change to a user's roles or to
+// their permissions takes to reach sessions that are already open.
+const sessionOrganizationsTTL = 30 * time.Second
+
 type middleware struct {
-	app    *application.Application
-	logger logging.Logger
+	app           *application.Application
+	logger        logging.Logger
+	organizations *sessionOrganizationsCache
 }
 
 func NewMiddleware(app *application.Application, logger logging.Logger) Middleware {
 	return &middleware{
-		app:    app,
-		logger: logger,
+		app:           app,
+		logger:        logger,
+		organizations: newSessionOrganizationsCache(),
+	}
+}
+
+// sessionOrganizationsCache keeps each user's organizations, roles and
+// permissions for sessionOrganizationsTTL so they are not read on every
+// request.
+type sessionOrganizationsCache struct {
+	mu      sync.Mutex
+	entries map[int]sessionOrganizationsCacheEntry
+}
+
+type sessionOrganizationsCacheEntry struct {
+	organizations []accounts.OrganizationWithPermissions
+	loadedAt      time.Time
+}
+
+func newSessionOrganizationsCache() *sessionOrganizationsCache {
+	return &sessionOrganizationsCache{entries: make(map[int]sessionOrganizationsCacheEntry)}
+}
+
+func (c *sessionOrganizationsCache) get(userID int, now time.Time) ([]accounts.OrganizationWithPermissions, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[userID]
+	if !ok || now.Sub(entry.loadedAt) >= sessionOrganizationsTTL {
+		return nil, false
 	}
+	return entry.organizations, true
+}
+
+func (c *sessionOrganizationsCache) put(userID int, organizations []accounts.OrganizationWithPermissions, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, entry := range c.entries {
+		if now.Sub(entry.loadedAt) >= sessionOrganizationsTTL {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[userID] = sessionOrganizationsCacheEntry{organizations: organizations, loadedAt: now}
 }
 
 func (m *middleware) LogError(next http.Handler) http.Handler {
@@ -55,6 +101,17 @@ func (m *middleware) Session(next http.Handler) http.Handler {
 			})
 
 			if err == nil {
+				// Roles and their permissions may have changed since login,
+				// e.g. by a migration granting new permissions, so they are
+				// read again rather than trusted from the stored session
+				organizations, err := m.loadOrganizations(r, session.Info.User.ID)
+				if err != nil {
+					m.logger.Error(r.Context(), "Failed to refresh session organizations", "user_id", session.Info.User.ID, "error", err.Error())
+					http.Error(w, "Failed to load session permissions", http.StatusServiceUnavailable)
+					return
+				}
+				session.Info.Organizations = organizations
+
 				activeOrganizationID := m.extractActiveOrganization(r)
 				hasExplicitOrganization := r.Header.Get("X-Active-Organization") != ""
 
@@ -89,6 +146,24 @@ func (m *middleware) Session(next http.Handler) http.Handler {
 	})
 }
 
+// loadOrganizations returns the user's organizations with their current roles
+// and permissions, read at most sessionOrganizationsTTL ago.
+func (m *middleware) loadOrganizations(r *http.Request, userID int) ([]accounts.OrganizationWithPermissions, error) {
+	now := time.Now()
+	if organizations, ok := m.organizations.get(userID, now); ok {
+		return organizations, nil
+	}
+
+	organizations, err := m.app.AccountsService.GetOrganizationsByUser(r.Context(), accounts.GetOrganizationsByUserInput{
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.organizations.put(userID, organizations, now)
+	return organizations, nil
+}
+
 func (m *middleware) RequireSession(next http.HandlerFunc, requiredPermissions []accounts.Permission) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		session, err := m.app.AccountsService.GetSessionFromContext(r.Context())
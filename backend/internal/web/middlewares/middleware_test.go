@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -26,6 +27,7 @@ func TestMiddlewareTestSuite(t *testing.T) {
 type MiddlewareTestSuite struct {
 	suite.Suite
 	app        *application.Application
+	db         *database.MemoryDatabase
 	logger     logging.Logger
 	middleware Middleware
 }
@@ -36,6 +38,29 @@ func (test *MiddlewareTestSuite) SetupTest() {
 	test.middleware = NewMiddleware(test.app, test.logger)
 }
 
+func (test *MiddlewareTestSuite) SetupSubTest() {
+	test.db.Reset()
+	test.middleware = NewMiddleware(test.app, test.logger)
+}
+
+// expectOrganizations makes the session refresh return the organizations the
+// session was created with.
+func (test *MiddlewareTestSuite) expectOrganizations(info accounts.SessionInfo) {
+	organizations := make([]accounts.OrganizationWithPermissionsModel, 0, len(info.Organizations))
+	for _, o := range info.Organizations {
+		permissions := make([]string, len(o.UserPermissions))
+		for i, p := range o.UserPermissions {
+			permissions[i] = string(p)
+		}
+		organizations = append(organizations, accounts.OrganizationWithPermissionsModel{
+			OrganizationModel: accounts.OrganizationModel{OrganizationID: o.OrganizationID, Name: o.Name},
+			UserRole:          o.UserRole,
+			UserPermissions:   permissions,
+		})
+	}
+	test.db.ExpectQuery(accounts.FetchOrganizationsByUserQuery, info.User.ID).WillReturn(organizations)
+}
+
 func (test *MiddlewareTestSuite) createTestLogger() logging.Logger {
 	return &logging.TestLogger{}
 }
@@ -52,6 +77,7 @@ func (test *MiddlewareTestSuite) createTestConfig() *config.Config {
 
 func (test *MiddlewareTestSuite) createTestApplication() *application.Application {
 	persistentDB := database.NewMemoryDatabase()
+	test.db = persistentDB
 	transientDB := transientdb.NewMemoryTransientDB()
 	cfg := test.createTestConfig()
 
@@ -85,6 +111,7 @@ func (test *MiddlewareTestSuite) TestSessionMiddleware() {
 			Info: userSession,
 		})
 		test.Require().NoError(err)
+		test.expectOrganizations(userSession)
 
 		handlerCalled := false
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -155,6 +182,7 @@ func (test *MiddlewareTestSuite) TestSessionMiddleware() {
 		}
 		session, err := test.app.AccountsService.CreateSession(context.Background(), accounts.CreateSessionInput{Info: userSession})
 		test.Require().NoError(err)
+		test.expectOrganizations(userSession)
 
 		handlerCalled := false
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -183,6 +211,7 @@ func (test *MiddlewareTestSuite) TestSessionMiddleware() {
 		}
 		session, err := test.app.AccountsService.CreateSession(context.Background(), accounts.CreateSessionInput{Info: userSession})
 		test.Require().NoError(err)
+		test.expectOrganizations(userSession)
 
 		handlerCalled := false
 		router := chi.NewRouter()
@@ -242,6 +271,7 @@ func (test *MiddlewareTestSuite) TestSessionMiddleware() {
 			Info: userSession,
 		})
 		test.Require().NoError(err)
+		test.expectOrganizations(userSession)
 
 		handlerCalled := false
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -287,6 +317,7 @@ func (test *MiddlewareTestSuite) TestSessionMiddleware() {
 			Info: userSession,
 		})
 		test.Require().NoError(err)
+		test.expectOrganizations(userSession)
 
 		handlerCalled := false
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -304,6 +335,147 @@ func (test *MiddlewareTestSuite) TestSessionMiddleware() {
 		test.True(handlerCalled)
 		test.Equal(http.StatusOK, rr.Code)
 	})
+
+	test.Run("viewer can read but not edit financial data", func() {
+		userSession := accounts.SessionInfo{
+			User: accounts.UserForSessionInfo{
+				ID:    123,
+				Email: "test@example.com",
+			},
+			Organizations: []accounts.OrganizationWithPermissions{
+				{
+					Organization: accounts.Organization{
+						OrganizationID: 1,
+						Name:           "Test Organization",
+					},
+					UserRole: accounts.RoleViewer,
+					UserPermissions: []accounts.Permission{
+						accounts.PermissionViewTransactions,
+					},
+				},
+			},
+		}
+		session, err := test.app.AccountsService.CreateSession(context.Background(), accounts.CreateSessionInput{
+			Info: userSession,
+		})
+		test.Require().NoError(err)
+		test.expectOrganizations(userSession)
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		for permission, expectedStatus := range map[accounts.Permission]int{
+			accounts.PermissionViewTransactions: http.StatusOK,
+			accounts.PermissionEditTransactions: http.StatusForbidden,
+			accounts.PermissionManageBudgets:    http.StatusForbidden,
+			accounts.PermissionCloseMonth:       http.StatusForbidden,
+		} {
+			req := httptest.NewRequest("GET", "/financial/test", nil)
+			req.Header.Set("Authorization", "Bearer "+session.Token)
+
+			rr := httptest.NewRecorder()
+			test.middleware.Session(test.middleware.RequireSession(handler, []accounts.Permission{permission})).ServeHTTP(rr, req)
+
+			test.Equal(expectedStatus, rr.Code, string(permission))
+		}
+	})
+	test.Run("permissions granted to the role after login", func() {
+		userSession := accounts.SessionInfo{
+			User: accounts.UserForSessionInfo{ID: 123, Email: "test@example.com"},
+			Organizations: []accounts.OrganizationWithPermissions{{
+				Organization: accounts.Organization{OrganizationID: 1, Name: "Test Organization"},
+				UserRole:     accounts.RoleRegularUser,
+				UserPermissions: []accounts.Permission{
+					accounts.PermissionViewOrganizations,
+				},
+			}},
+		}
+		session, err := test.app.AccountsService.CreateSession(context.Background(), accounts.CreateSessionInput{Info: userSession})
+		test.Require().NoError(err)
+
+		test.db.ExpectQuery(accounts.FetchOrganizationsByUserQuery, 123).WillReturn([]accounts.OrganizationWithPermissionsModel{{
+			OrganizationModel: accounts.OrganizationModel{OrganizationID: 1, Name: "Test Organization"},
+			UserRole:          accounts.RoleRegularUser,
+			UserPermissions:   []string{string(accounts.PermissionViewOrganizations), string(accounts.PermissionEditTransactions)},
+		}})
+
+		handlerCalled := false
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerCalled = true
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/financial/test", nil)
+		req.Header.Set("Authorization", "Bearer "+session.Token)
+
+		rr := httptest.NewRecorder()
+		permissions := []accounts.Permission{accounts.PermissionEditTransactions}
+		test.middleware.Session(test.middleware.RequireSession(handler, permissions)).ServeHTTP(rr, req)
+
+		test.True(handlerCalled)
+		test.Equal(http.StatusOK, rr.Code)
+		test.NoError(test.db.ExpectationsWereMet())
+	})
+
+	test.Run("when the permissions cannot be refreshed", func() {
+		userSession := accounts.SessionInfo{
+			User: accounts.UserForSessionInfo{ID: 123, Email: "test@example.com"},
+			Organizations: []accounts.OrganizationWithPermissions{{
+				Organization: accounts.Organization{OrganizationID: 1, Name: "Test Organization"},
+				UserRole:     accounts.RoleAdmin,
+			}},
+		}
+		session, err := test.app.AccountsService.CreateSession(context.Background(), accounts.CreateSessionInput{Info: userSession})
+		test.Require().NoError(err)
+
+		test.db.ExpectQuery(accounts.FetchOrganizationsByUserQuery, 123).WillReturnError(errors.New("connection refused"))
+
+		handlerCalled := false
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerCalled = true
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+session.Token)
+
+		rr := httptest.NewRecorder()
+		test.middleware.Session(test.middleware.RequireSession(handler, nil)).ServeHTTP(rr, req)
+
+		test.False(handlerCalled)
+		test.Equal(http.StatusServiceUnavailable, rr.Code)
+	})
+
+	test.Run("reuses refreshed permissions for a while", func() {
+		userSession := accounts.SessionInfo{
+			User: accounts.UserForSessionInfo{ID: 123, Email: "test@example.com"},
+			Organizations: []accounts.OrganizationWithPermissions{{
+				Organization:    accounts.Organization{OrganizationID: 1, Name: "Test Organization"},
+				UserRole:        accounts.RoleRegularUser,
+				UserPermissions: []accounts.Permission{accounts.PermissionViewTransactions},
+			}},
+		}
+		session, err := test.app.AccountsService.CreateSession(context.Background(), accounts.CreateSessionInput{Info: userSession})
+		test.Require().NoError(err)
+		test.expectOrganizations(userSession)
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		for range 3 {
+			req := httptest.NewRequest("GET", "/financial/test", nil)
+			req.Header.Set("Authorization", "Bearer "+session.Token)
+
+			rr := httptest.NewRecorder()
+			permissions := []accounts.Permission{accounts.PermissionViewTransactions}
+			test.middleware.Session(test.middleware.RequireSession(handler, permissions)).ServeHTTP(rr, req)
+
+			test.Equal(http.StatusOK, rr.Code)
+		}
+		test.NoError(test.db.ExpectationsWereMet())
+	})
 }
 
 func (test *MiddlewareTestSuite) TestExtractSessionID() {
@@ -39,11 +39,36 @@ var errorMappings = map[error]ErrorMapping{
 	errors.ErrInvalidSession:                 {Status: http.StatusUnauthorized, Code: "INVALID_SESSION"},
 	errors.ErrEmailNotVerified:               {Status: http.StatusForbidden, Code: "EMAIL_NOT_VERIFIED"},
 	errors.ErrMonthClosed:                    {Status: http.StatusConflict, Code: "MONTH_CLOSED"},
+	errors.ErrMonthNotClosed:                 {Status: http.StatusConflict, Code: "MONTH_NOT_CLOSED"},
 	errors.ErrActivationFailed:               {Status: http.StatusUnauthorized, Code: "ACTIVATION_FAILED"},
 	errors.ErrInvalidCode:                    {Status: http.StatusUnauthorized, Code: "INVALID_CODE"},
 	errors.ErrRecaptchaFailed:                {Status: http.StatusBadRequest, Code: "RECAPTCHA_FAILED"},
 	errors.ErrSavingsGoalNameExists:          {Status: http.StatusConflict, Code: "SAVINGS_GOAL_NAME_EXISTS"},
 	errors.ErrPatternRetroactiveUnsupported:  {Status: http.StatusBadRequest, Code: "PATTERN_RETROACTIVE_UNSUPPORTED"},
+	errors.ErrPluggyNotConfigured:            {Status: http.StatusServiceUnavailable, Code: "PLUGGY_NOT_CONFIGURED"},
+	errors.ErrPluggyAccountNotFound:          {Status: http.StatusNotFound, Code: "PLUGGY_ACCOUNT_NOT_FOUND"},
+	errors.ErrInvalidClassificationRule:      {Status: http.StatusBadRequest, Code: "INVALID_CLASSIFICATION_RULE"},
+	errors.ErrInvalidCSVImportProfile:        {Status: http.StatusBadRequest, Code: "INVALID_CSV_IMPORT_PROFILE"},
+	errors.ErrCSVImportProfileNameExists:     {Status: http.StatusConflict, Code: "CSV_IMPORT_PROFILE_NAME_EXISTS"},
+	errors.ErrInvalidCSVFile:                 {Status: http.StatusBadRequest, Code: "INVALID_CSV_FILE"},
+	errors.ErrAccountHasClosedMonths:         {Status: http.StatusConflict, Code: "ACCOUNT_HAS_CLOSED_MONTHS"},
+	errors.ErrInvalidTargetAccount:           {Status: http.StatusBadRequest, Code: "INVALID_TARGET_ACCOUNT"},
+	errors.ErrTargetAccountHasTransactions:   {Status: http.StatusConflict, Code: "TARGET_ACCOUNT_HAS_TRANSACTIONS"},
+	errors.ErrInvalidCursor:                  {Status: http.StatusBadRequest, Code: "INVALID_CURSOR"},
+	errors.ErrInvalidTransactionSplits:       {Status: http.StatusBadRequest, Code: "INVALID_TRANSACTION_SPLITS"},
+	errors.ErrInvalidTransfer:                {Status: http.StatusBadRequest, Code: "INVALID_TRANSFER"},
+	errors.ErrInvalidBillingCycle:            {Status: http.StatusBadRequest, Code: "INVALID_BILLING_CYCLE"},
+	errors.ErrInvalidCurrency:                {Status: http.StatusBadRequest, Code: "INVALID_CURRENCY"},
+	errors.ErrInvalidExchangeRates:           {Status: http.StatusBadRequest, Code: "INVALID_EXCHANGE_RATES"},
+	errors.ErrMissingExchangeRates:           {Status: http.StatusConflict, Code: "MISSING_EXCHANGE_RATES"},
+	errors.ErrInvalidRecurrence:              {Status: http.StatusBadRequest, Code: "INVALID_RECURRENCE"},
+	errors.ErrInvalidForecast:                {Status: http.StatusBadRequest, Code: "INVALID_FORECAST"},
+	errors.ErrInvalidReportRange:             {Status: http.StatusBadRequest, Code: "INVALID_REPORT_RANGE"},
+	errors.ErrInvalidOrganizationArchive:     {Status: http.StatusBadRequest, Code: "INVALID_ORGANIZATION_ARCHIVE"},
+	errors.ErrOrganizationNotEmpty:           {Status: http.StatusConflict, Code: "ORGANIZATION_NOT_EMPTY"},
+	errors.ErrInvalidTransactionExport:       {Status: http.StatusBadRequest, Code: "INVALID_TRANSACTION_EXPORT"},
+	errors.ErrInvalidPatternOrder:            {Status: http.StatusBadRequest, Code: "INVALID_PATTERN_ORDER"},
+	errors.ErrInvalidPredictionThreshold:     {Status: http.StatusBadRequest, Code: "INVALID_PREDICTION_THRESHOLD"},
 	errors.ErrTransactionCategoryRequired:    {Status: http.StatusBadRequest, Code: "TRANSACTION_CATEGORY_REQUIRED"},
 	errors.ErrTransactionDescriptionRequired: {Status: http.StatusBadRequest, Code: "TRANSACTION_DESCRIPTION_REQUIRED"},
 }
@@ -55,6 +55,7 @@ func NewRouter(application *application.Application, logger logging.Logger, cfg
 	r.Get("/organizations/{orgId}/invites", mw.RequireSession(ah.GetPendingInvites, []accounts.Permission{}))
 	r.Post("/organizations/{orgId}/invites", mw.RequireSession(ah.CreateOrganizationInvite, []accounts.Permission{accounts.PermissionCreateRegularUsers}))
 	r.Delete("/organizations/{orgId}/invites/{inviteId}", mw.RequireSession(ah.CancelOrganizationInvite, []accounts.Permission{}))
+	r.Get("/organizations/{orgId}/export", mw.RequireSession(ah.ExportOrganization, []accounts.Permission{accounts.PermissionEditOrganizations}))
 
 	// Public invite acceptance (token-based auth)
 	r.Post("/invites/accept", ah.AcceptOrganizationInvite)
@@ -77,97 +78,172 @@ func NewRouter(application *application.Application, logger logging.Logger, cfg
 	// Financial endpoints (all require authentication)
 	r.Route("/financial", func(r chi.Router) {
 		// Categories
-		r.Get("/categories", mw.RequireSession(fh.ListCategories, []accounts.Permission{}))
-		r.Post("/categories", mw.RequireSession(fh.CreateCategory, []accounts.Permission{}))
-		r.Patch("/categories/{id}", mw.RequireSession(fh.UpdateCategory, []accounts.Permission{}))
-		r.Delete("/categories/{id}", mw.RequireSession(fh.DeleteCategory, []accounts.Permission{}))
+		r.Get("/categories", mw.RequireSession(fh.ListCategories, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/categories", mw.RequireSession(fh.CreateCategory, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Patch("/categories/{id}", mw.RequireSession(fh.UpdateCategory, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Delete("/categories/{id}", mw.RequireSession(fh.DeleteCategory, []accounts.Permission{accounts.PermissionManageBudgets}))
 
 		// Accounts
-		r.Get("/accounts", mw.RequireSession(fh.ListAccounts, []accounts.Permission{}))
-		r.Post("/accounts", mw.RequireSession(fh.CreateAccount, []accounts.Permission{}))
-		r.Get("/accounts/{accountId}", mw.RequireSession(fh.GetAccount, []accounts.Permission{}))
+		r.Get("/accounts", mw.RequireSession(fh.ListAccounts, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/accounts", mw.RequireSession(fh.CreateAccount, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Get("/accounts/{accountId}", mw.RequireSession(fh.GetAccount, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Patch("/accounts/{accountId}", mw.RequireSession(fh.UpdateAccount, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Delete("/accounts/{accountId}", mw.RequireSession(fh.DeleteAccount, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Get("/accounts/{accountId}/reconciliation", mw.RequireSession(fh.GetAccountReconciliation, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/accounts/{accountId}/reconciliation/balances", mw.RequireSession(fh.RecordStatementBalance, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Get("/accounts/{accountId}/invoices", mw.RequireSession(fh.ListCreditCardInvoices, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/accounts/{accountId}/invoices/{year}/{month}", mw.RequireSession(fh.GetCreditCardInvoice, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/accounts/{accountId}/invoices/{year}/{month}/payment", mw.RequireSession(fh.SetCreditCardInvoicePayment, []accounts.Permission{accounts.PermissionEditTransactions}))
 
 		// Pluggy
-		r.Post("/integrations/pluggy/connect-token", mw.RequireSession(fh.CreatePluggyConnectToken, []accounts.Permission{}))
-		r.Get("/integrations/pluggy/connectors", mw.RequireSession(fh.ListPluggyConnectors, []accounts.Permission{}))
-		r.Get("/integrations/pluggy/items/{itemId}/accounts", mw.RequireSession(fh.ListPluggyAccountsByItem, []accounts.Permission{}))
-		r.Get("/integrations/pluggy/accounts/{accountId}/transactions", mw.RequireSession(fh.ListPluggyTransactionsByAccount, []accounts.Permission{}))
+		r.Post("/integrations/pluggy/connect-token", mw.RequireSession(fh.CreatePluggyConnectToken, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Get("/integrations/pluggy/connectors", mw.RequireSession(fh.ListPluggyConnectors, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/integrations/pluggy/items/{itemId}/accounts", mw.RequireSession(fh.ListPluggyAccountsByItem, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/integrations/pluggy/accounts/{accountId}/transactions", mw.RequireSession(fh.ListPluggyTransactionsByAccount, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/integrations/pluggy/links", mw.RequireSession(fh.ListPluggyAccountLinks, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/integrations/pluggy/links", mw.RequireSession(fh.CreatePluggyAccountLink, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Delete("/integrations/pluggy/links/{linkId}", mw.RequireSession(fh.DeletePluggyAccountLink, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Post("/integrations/pluggy/links/{linkId}/sync", mw.RequireSession(fh.SyncPluggyAccountLink, []accounts.Permission{accounts.PermissionEditTransactions}))
+
+		// CSV Import Profiles
+		r.Get("/accounts/{accountId}/csv-profiles", mw.RequireSession(fh.ListCSVImportProfiles, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/accounts/{accountId}/csv-profiles", mw.RequireSession(fh.CreateCSVImportProfile, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Patch("/accounts/{accountId}/csv-profiles/{profileId}", mw.RequireSession(fh.UpdateCSVImportProfile, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Delete("/accounts/{accountId}/csv-profiles/{profileId}", mw.RequireSession(fh.DeleteCSVImportProfile, []accounts.Permission{accounts.PermissionEditTransactions}))
 
 		// Transactions
-		r.Get("/accounts/{accountId}/transactions", mw.RequireSession(fh.ListTransactions, []accounts.Permission{}))
-		r.Get("/transactions/uncategorized", mw.RequireSession(fh.ListUncategorizedTransactions, []accounts.Permission{}))
-		r.Post("/accounts/{accountId}/transactions", mw.RequireSession(fh.CreateTransaction, []accounts.Permission{}))
-		r.Post("/accounts/{accountId}/transactions/import", mw.RequireSession(fh.ImportOFX, []accounts.Permission{}))
-		r.Patch("/accounts/{accountId}/transactions/{transactionId}", mw.RequireSession(fh.UpdateTransaction, []accounts.Permission{}))
+		r.Get("/accounts/{accountId}/transactions", mw.RequireSession(fh.ListTransactions, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/transactions", mw.RequireSession(fh.SearchTransactions, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/transactions/export", mw.RequireSession(fh.ExportTransactions, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/transactions/uncategorized", mw.RequireSession(fh.ListUncategorizedTransactions, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/transactions/uncategorized/auto-categorize", mw.RequireSession(fh.AutoCategorizeTransactions, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Post("/accounts/{accountId}/transactions", mw.RequireSession(fh.CreateTransaction, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Post("/accounts/{accountId}/transactions/import", mw.RequireSession(fh.ImportOFX, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Post("/accounts/{accountId}/transactions/import/csv", mw.RequireSession(fh.ImportCSV, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Post("/accounts/{accountId}/transactions/import/csv/preview", mw.RequireSession(fh.PreviewCSVImport, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Patch("/accounts/{accountId}/transactions/{transactionId}", mw.RequireSession(fh.UpdateTransaction, []accounts.Permission{accounts.PermissionEditTransactions}))
 
 		// Category Budgets
-		r.Get("/budgets/categories", mw.RequireSession(fh.ListCategoryBudgets, []accounts.Permission{}))
-		r.Post("/budgets/categories", mw.RequireSession(fh.CreateCategoryBudget, []accounts.Permission{}))
-		r.Get("/budgets/categories/{id}", mw.RequireSession(fh.GetCategoryBudget, []accounts.Permission{}))
-		r.Put("/budgets/categories/{id}", mw.RequireSession(fh.UpdateCategoryBudget, []accounts.Permission{}))
-		r.Delete("/budgets/categories/{id}", mw.RequireSession(fh.DeleteCategoryBudget, []accounts.Permission{}))
-		r.Post("/budgets/categories/{id}/consolidate", mw.RequireSession(fh.ConsolidateCategoryBudget, []accounts.Permission{}))
-		r.Post("/budgets/categories/copy", mw.RequireSession(fh.CopyCategoryBudgetsFromMonth, []accounts.Permission{}))
-		r.Post("/month/close", mw.RequireSession(fh.CloseMonth, []accounts.Permission{}))
-		r.Get("/budgets/categories/pacing", mw.RequireSession(fh.GetControllableCategoryPacing, []accounts.Permission{}))
+		r.Get("/budgets/categories", mw.RequireSession(fh.ListCategoryBudgets, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/budgets/categories", mw.RequireSession(fh.CreateCategoryBudget, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Get("/budgets/categories/{id}", mw.RequireSession(fh.GetCategoryBudget, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/budgets/categories/{id}", mw.RequireSession(fh.UpdateCategoryBudget, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Delete("/budgets/categories/{id}", mw.RequireSession(fh.DeleteCategoryBudget, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Post("/budgets/categories/{id}/consolidate", mw.RequireSession(fh.ConsolidateCategoryBudget, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Post("/budgets/categories/copy", mw.RequireSession(fh.CopyCategoryBudgetsFromMonth, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Post("/month/close", mw.RequireSession(fh.CloseMonth, []accounts.Permission{accounts.PermissionCloseMonth}))
+		r.Post("/month/reopen", mw.RequireSession(fh.ReopenMonth, []accounts.Permission{accounts.PermissionCloseMonth}))
+		r.Get("/month/reopenings", mw.RequireSession(fh.ListMonthReopenings, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/month/auto-close", mw.RequireSession(fh.GetAutoCloseMonths, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/month/auto-close", mw.RequireSession(fh.SetAutoCloseMonths, []accounts.Permission{accounts.PermissionCloseMonth}))
+		r.Get("/budgets/categories/pacing", mw.RequireSession(fh.GetControllableCategoryPacing, []accounts.Permission{accounts.PermissionViewTransactions}))
 
 		// Planned Entries
-		r.Get("/planned-entries", mw.RequireSession(fh.ListPlannedEntries, []accounts.Permission{}))
-		r.Post("/planned-entries", mw.RequireSession(fh.CreatePlannedEntry, []accounts.Permission{}))
-		r.Get("/planned-entries/month", mw.RequireSession(fh.GetPlannedEntriesForMonth, []accounts.Permission{}))
-		r.Get("/planned-entries/{id}", mw.RequireSession(fh.GetPlannedEntry, []accounts.Permission{}))
-		r.Put("/planned-entries/{id}", mw.RequireSession(fh.UpdatePlannedEntry, []accounts.Permission{}))
-		r.Delete("/planned-entries/{id}", mw.RequireSession(fh.DeletePlannedEntry, []accounts.Permission{}))
-		r.Post("/planned-entries/{id}/generate", mw.RequireSession(fh.GenerateMonthlyInstances, []accounts.Permission{}))
-		r.Post("/planned-entries/{id}/match", mw.RequireSession(fh.MatchPlannedEntry, []accounts.Permission{}))
-		r.Delete("/planned-entries/{id}/match", mw.RequireSession(fh.UnmatchPlannedEntry, []accounts.Permission{}))
-		r.Post("/planned-entries/{id}/dismiss", mw.RequireSession(fh.DismissPlannedEntry, []accounts.Permission{}))
-		r.Delete("/planned-entries/{id}/dismiss", mw.RequireSession(fh.UndismissPlannedEntry, []accounts.Permission{}))
+		r.Get("/planned-entries", mw.RequireSession(fh.ListPlannedEntries, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/planned-entries", mw.RequireSession(fh.CreatePlannedEntry, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Get("/planned-entries/month", mw.RequireSession(fh.GetPlannedEntriesForMonth, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/planned-entries/{id}", mw.RequireSession(fh.GetPlannedEntry, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/planned-entries/{id}", mw.RequireSession(fh.UpdatePlannedEntry, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Delete("/planned-entries/{id}", mw.RequireSession(fh.DeletePlannedEntry, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Post("/planned-entries/{id}/generate", mw.RequireSession(fh.GenerateMonthlyInstances, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Post("/planned-entries/{id}/match", mw.RequireSession(fh.MatchPlannedEntry, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Delete("/planned-entries/{id}/match", mw.RequireSession(fh.UnmatchPlannedEntry, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Post("/planned-entries/{id}/dismiss", mw.RequireSession(fh.DismissPlannedEntry, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Delete("/planned-entries/{id}/dismiss", mw.RequireSession(fh.UndismissPlannedEntry, []accounts.Permission{accounts.PermissionManageBudgets}))
 
 		// Monthly Snapshots
-		r.Get("/snapshots", mw.RequireSession(fh.ListMonthlySnapshots, []accounts.Permission{}))
-		r.Get("/snapshots/{id}", mw.RequireSession(fh.GetMonthlySnapshot, []accounts.Permission{}))
+		r.Get("/snapshots", mw.RequireSession(fh.ListMonthlySnapshots, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/snapshots/{id}", mw.RequireSession(fh.GetMonthlySnapshot, []accounts.Permission{accounts.PermissionViewTransactions}))
 
 		// Transaction Planned Entry
-		r.Get("/transactions/{id}/planned-entry", mw.RequireSession(fh.GetTransactionPlannedEntry, []accounts.Permission{}))
+		r.Get("/transactions/{id}/planned-entry", mw.RequireSession(fh.GetTransactionPlannedEntry, []accounts.Permission{accounts.PermissionViewTransactions}))
 
 		// Income Planning
-		r.Get("/income-planning", mw.RequireSession(fh.GetIncomePlanning, []accounts.Permission{}))
+		r.Get("/income-planning", mw.RequireSession(fh.GetIncomePlanning, []accounts.Permission{accounts.PermissionViewTransactions}))
 
 		// Patterns
-		r.Post("/patterns", mw.RequireSession(fh.CreatePattern, []accounts.Permission{}))
-		r.Get("/patterns", mw.RequireSession(fh.GetPatterns, []accounts.Permission{}))
-		r.Get("/patterns/{id}", mw.RequireSession(fh.GetPattern, []accounts.Permission{}))
-		r.Put("/patterns/{id}", mw.RequireSession(fh.UpdatePattern, []accounts.Permission{}))
-		r.Delete("/patterns/{id}", mw.RequireSession(fh.DeletePattern, []accounts.Permission{}))
-		r.Post("/patterns/{id}/apply-retroactively", mw.RequireSession(fh.ApplyPatternRetroactively, []accounts.Permission{}))
+		r.Post("/patterns", mw.RequireSession(fh.CreatePattern, []accounts.Permission{accounts.PermissionManagePatterns}))
+		r.Post("/patterns/preview", mw.RequireSession(fh.PreviewPattern, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/patterns", mw.RequireSession(fh.GetPatterns, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/patterns/order", mw.RequireSession(fh.ReorderPatterns, []accounts.Permission{accounts.PermissionManagePatterns}))
+		r.Get("/patterns/conflicts", mw.RequireSession(fh.GetPatternConflicts, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/patterns/suggestions", mw.RequireSession(fh.GetPatternSuggestions, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/patterns/suggestions/accept", mw.RequireSession(fh.AcceptPatternSuggestion, []accounts.Permission{accounts.PermissionManagePatterns}))
+		r.Get("/patterns/{id}", mw.RequireSession(fh.GetPattern, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/patterns/{id}", mw.RequireSession(fh.UpdatePattern, []accounts.Permission{accounts.PermissionManagePatterns}))
+		r.Delete("/patterns/{id}", mw.RequireSession(fh.DeletePattern, []accounts.Permission{accounts.PermissionManagePatterns}))
+		r.Post("/patterns/{id}/apply-retroactively", mw.RequireSession(fh.ApplyPatternRetroactively, []accounts.Permission{accounts.PermissionManagePatterns}))
+
+		// Classification Rules
+		r.Get("/rules", mw.RequireSession(fh.ListClassificationRules, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/rules", mw.RequireSession(fh.CreateClassificationRule, []accounts.Permission{accounts.PermissionManagePatterns}))
+		r.Put("/rules/order", mw.RequireSession(fh.ReorderClassificationRules, []accounts.Permission{accounts.PermissionManagePatterns}))
+		r.Get("/rules/{id}", mw.RequireSession(fh.GetClassificationRule, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Patch("/rules/{id}", mw.RequireSession(fh.UpdateClassificationRule, []accounts.Permission{accounts.PermissionManagePatterns}))
+		r.Delete("/rules/{id}", mw.RequireSession(fh.DeleteClassificationRule, []accounts.Permission{accounts.PermissionManagePatterns}))
 
 		// Savings Goals
-		r.Get("/savings-goals", mw.RequireSession(fh.ListSavingsGoals, []accounts.Permission{}))
-		r.Post("/savings-goals", mw.RequireSession(fh.CreateSavingsGoal, []accounts.Permission{}))
-		r.Get("/savings-goals/{id}", mw.RequireSession(fh.GetSavingsGoal, []accounts.Permission{}))
-		r.Get("/savings-goals/{id}/progress", mw.RequireSession(fh.GetSavingsGoalProgress, []accounts.Permission{}))
-		r.Get("/savings-goals/{id}/summary", mw.RequireSession(fh.GetSavingsGoalSummary, []accounts.Permission{}))
-		r.Put("/savings-goals/{id}", mw.RequireSession(fh.UpdateSavingsGoal, []accounts.Permission{}))
-		r.Delete("/savings-goals/{id}", mw.RequireSession(fh.DeleteSavingsGoal, []accounts.Permission{}))
-		r.Post("/savings-goals/{id}/complete", mw.RequireSession(fh.CompleteSavingsGoal, []accounts.Permission{}))
-		r.Post("/savings-goals/{id}/reopen", mw.RequireSession(fh.ReopenSavingsGoal, []accounts.Permission{}))
-		r.Post("/savings-goals/{id}/contribute", mw.RequireSession(fh.AddContribution, []accounts.Permission{}))
+		r.Get("/savings-goals", mw.RequireSession(fh.ListSavingsGoals, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/savings-goals", mw.RequireSession(fh.CreateSavingsGoal, []accounts.Permission{accounts.PermissionManageGoals}))
+		r.Get("/savings-goals/{id}", mw.RequireSession(fh.GetSavingsGoal, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/savings-goals/{id}/progress", mw.RequireSession(fh.GetSavingsGoalProgress, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/savings-goals/{id}/summary", mw.RequireSession(fh.GetSavingsGoalSummary, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/savings-goals/{id}", mw.RequireSession(fh.UpdateSavingsGoal, []accounts.Permission{accounts.PermissionManageGoals}))
+		r.Delete("/savings-goals/{id}", mw.RequireSession(fh.DeleteSavingsGoal, []accounts.Permission{accounts.PermissionManageGoals}))
+		r.Post("/savings-goals/{id}/complete", mw.RequireSession(fh.CompleteSavingsGoal, []accounts.Permission{accounts.PermissionManageGoals}))
+		r.Post("/savings-goals/{id}/reopen", mw.RequireSession(fh.ReopenSavingsGoal, []accounts.Permission{accounts.PermissionManageGoals}))
+		r.Post("/savings-goals/{id}/contribute", mw.RequireSession(fh.AddContribution, []accounts.Permission{accounts.PermissionManageGoals}))
 
 		// Amazon Sync (Chrome Extension)
-		r.Post("/amazon/sync", mw.RequireSession(fh.SyncAmazonOrders, []accounts.Permission{}))
+		r.Post("/amazon/sync", mw.RequireSession(fh.SyncAmazonOrders, []accounts.Permission{accounts.PermissionEditTransactions}))
 
 		// Tags
-		r.Get("/tags", mw.RequireSession(fh.ListTags, []accounts.Permission{}))
-		r.Get("/tags/spending", mw.RequireSession(fh.ListTagSpending, []accounts.Permission{}))
-		r.Post("/tags", mw.RequireSession(fh.CreateTag, []accounts.Permission{}))
-		r.Get("/tags/{id}", mw.RequireSession(fh.GetTag, []accounts.Permission{}))
-		r.Patch("/tags/{id}", mw.RequireSession(fh.UpdateTag, []accounts.Permission{}))
-		r.Delete("/tags/{id}", mw.RequireSession(fh.DeleteTag, []accounts.Permission{}))
+		r.Get("/tags", mw.RequireSession(fh.ListTags, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/tags/spending", mw.RequireSession(fh.ListTagSpending, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/tags", mw.RequireSession(fh.CreateTag, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Get("/tags/{id}", mw.RequireSession(fh.GetTag, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Patch("/tags/{id}", mw.RequireSession(fh.UpdateTag, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Delete("/tags/{id}", mw.RequireSession(fh.DeleteTag, []accounts.Permission{accounts.PermissionEditTransactions}))
 
 		// Transaction Tags
-		r.Get("/transactions/{id}/tags", mw.RequireSession(fh.GetTransactionTags, []accounts.Permission{}))
-		r.Put("/transactions/{id}/tags", mw.RequireSession(fh.SetTransactionTags, []accounts.Permission{}))
+		r.Get("/transactions/{id}/tags", mw.RequireSession(fh.GetTransactionTags, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/transactions/{id}/tags", mw.RequireSession(fh.SetTransactionTags, []accounts.Permission{accounts.PermissionEditTransactions}))
+
+		// Transaction Splits
+		r.Get("/transactions/{id}/splits", mw.RequireSession(fh.GetTransactionSplits, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/transactions/{id}/splits", mw.RequireSession(fh.SetTransactionSplits, []accounts.Permission{accounts.PermissionEditTransactions}))
+
+		// Transfers
+		r.Get("/transfers", mw.RequireSession(fh.ListTransfers, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/transfers", mw.RequireSession(fh.LinkTransfer, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Post("/transfers/detect", mw.RequireSession(fh.DetectTransfers, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Delete("/transfers/{id}", mw.RequireSession(fh.UnlinkTransfer, []accounts.Permission{accounts.PermissionEditTransactions}))
+
+		// Installment Plans
+		r.Get("/installments", mw.RequireSession(fh.ListInstallmentPlans, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/installments/detect", mw.RequireSession(fh.DetectInstallments, []accounts.Permission{accounts.PermissionEditTransactions}))
+		r.Get("/installments/outstanding", mw.RequireSession(fh.GetOutstandingInstallments, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Patch("/installments/{id}", mw.RequireSession(fh.UpdateInstallmentPlan, []accounts.Permission{accounts.PermissionEditTransactions}))
+
+		// Cash-Flow Forecast
+		r.Get("/forecast", mw.RequireSession(fh.GetCashFlowForecast, []accounts.Permission{accounts.PermissionViewTransactions}))
+
+		// Reports
+		r.Get("/reports/category-trends", mw.RequireSession(fh.GetCategoryTrends, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/reports/year-over-year", mw.RequireSession(fh.GetYearOverYear, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/reports/top-merchants", mw.RequireSession(fh.GetTopMerchants, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/reports/income-expense", mw.RequireSession(fh.GetIncomeExpense, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/reports/transactions", mw.RequireSession(fh.GetReportTransactions, []accounts.Permission{accounts.PermissionViewTransactions}))
+
+		// Audit Log
+		r.Get("/audit", mw.RequireSession(fh.GetAuditLog, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Get("/audit/{entityType}/{entityId}", mw.RequireSession(fh.GetEntityAuditLog, []accounts.Permission{accounts.PermissionViewTransactions}))
+
+		// Currencies
+		r.Get("/currency", mw.RequireSession(fh.GetBaseCurrency, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Put("/currency", mw.RequireSession(fh.SetBaseCurrency, []accounts.Permission{accounts.PermissionManageBudgets}))
+		r.Get("/exchange-rates", mw.RequireSession(fh.ListExchangeRates, []accounts.Permission{accounts.PermissionViewTransactions}))
+		r.Post("/exchange-rates/import", mw.RequireSession(fh.ImportExchangeRates, []accounts.Permission{accounts.PermissionManageBudgets}))
 	})
 
 	return r
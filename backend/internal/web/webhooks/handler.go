@@ -247,16 +247,32 @@ func (h *Handler) HandleEmailInbound(w http.ResponseWriter, r *http.Request) {
 	// Parse subject line to find target account
 	targetAccountName := parseSubjectForAccountName(payload.Data.Subject)
 
-	// Get user's accounts
-	isActive := true
+	// Get user's accounts, archived ones included so a subject naming one is
+	// rejected instead of silently importing somewhere else
 	userAccounts, err := h.app.FinancialService.GetAccounts(ctx, financial.GetAccountsInput{
 		UserID:         user.UserID,
 		OrganizationID: organizationID,
-		IsActive:       &isActive,
 	})
-	if err != nil || len(userAccounts) == 0 {
+	if err != nil {
+		h.logger.Error(ctx, "Failed to fetch accounts", "user_id", user.UserID, "error", err)
+	}
+
+	targetAccount, err := selectInboundTargetAccount(userAccounts, targetAccountName)
+	if stderrors.Is(err, errArchivedTargetAccount) {
+		h.logger.Warn(ctx, "Inbound email targets an archived account",
+			"user_id", user.UserID,
+			"account_name", targetAccountName,
+		)
+		h.sendErrorEmail(ctx, userEmail, fmt.Sprintf("A conta \"%s\" está arquivada. Reative a conta no Celeiro ou envie para outra conta.", targetAccountName))
+		responses.NewSuccess(EmailInboundResponse{
+			Success: false,
+			Message: "Target account is archived",
+		}, w)
+		return
+	}
+	if err != nil {
 		h.logger.Error(ctx, "User has no accounts", "user_id", user.UserID)
-		h.sendErrorEmail(ctx, userEmail, "Nenhuma conta encontrada. Crie uma conta no Celeiro antes de importar transações.")
+		h.sendErrorEmail(ctx, userEmail, "Nenhuma conta ativa encontrada. Crie ou reative uma conta no Celeiro antes de importar transações.")
 		responses.NewSuccess(EmailInboundResponse{
 			Success: false,
 			Message: "No accounts found",
@@ -264,21 +280,6 @@ func (h *Handler) HandleEmailInbound(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Find target account (by name match or use first active account)
-	var targetAccount financial.Account
-	if targetAccountName != "" {
-		for _, acc := range userAccounts {
-			if strings.EqualFold(acc.Name, targetAccountName) {
-				targetAccount = acc
-				break
-			}
-		}
-	}
-	if targetAccount.AccountID == 0 {
-		// Use first active account
-		targetAccount = userAccounts[0]
-	}
-
 	// Process each OFX attachment
 	var totalImported, totalDuplicates int
 	var importErrors []string
@@ -334,6 +335,41 @@ func (h *Handler) HandleEmailInbound(w http.ResponseWriter, r *http.Request) {
 	}, w)
 }
 
+var (
+	errNoActiveAccounts      = stderrors.New("no active accounts")
+	errArchivedTargetAccount = stderrors.New("target account is archived")
+)
+
+// selectInboundTargetAccount picks the account named in the subject, or the
+// first active account when the subject names none. Archived accounts are
+// never picked; naming one is an error.
+func selectInboundTargetAccount(userAccounts []financial.Account, targetAccountName string) (financial.Account, error) {
+	var active []financial.Account
+	for _, acc := range userAccounts {
+		if acc.IsActive {
+			active = append(active, acc)
+		}
+	}
+
+	if targetAccountName != "" {
+		for _, acc := range active {
+			if strings.EqualFold(acc.Name, targetAccountName) {
+				return acc, nil
+			}
+		}
+		for _, acc := range userAccounts {
+			if !acc.IsActive && strings.EqualFold(acc.Name, targetAccountName) {
+				return financial.Account{}, errArchivedTargetAccount
+			}
+		}
+	}
+
+	if len(active) == 0 {
+		return financial.Account{}, errNoActiveAccounts
+	}
+	return active[0], nil
+}
+
 // extractEmail extracts the email address from a "Name <email@domain.com>" format
 func extractEmail(from string) string {
 	// Try to extract email from angle brackets
@@ -13,6 +13,7 @@ import (
 
 	"github.com/catrutech/celeiro/internal/application"
 	"github.com/catrutech/celeiro/internal/application/accounts"
+	"github.com/catrutech/celeiro/internal/application/financial"
 	"github.com/catrutech/celeiro/internal/web/responses"
 	"github.com/catrutech/celeiro/pkg/logging"
 	"github.com/stretchr/testify/assert"
@@ -264,3 +265,37 @@ func jsonHTTPResponse(status int, body string) *http.Response {
 		Body:       io.NopCloser(strings.NewReader(body)),
 	}
 }
+
+func TestSelectInboundTargetAccount(t *testing.T) {
+	userAccounts := []financial.Account{
+		{AccountID: 3, Name: "Inter", IsActive: false},
+		{AccountID: 2, Name: "Nubank", IsActive: true},
+		{AccountID: 1, Name: "Itaú", IsActive: true},
+	}
+
+	tests := []struct {
+		name     string
+		accounts []financial.Account
+		subject  string
+		wantID   int
+		wantErr  error
+	}{
+		{name: "named active account", accounts: userAccounts, subject: "itaú", wantID: 1},
+		{name: "no name picks first active", accounts: userAccounts, subject: "", wantID: 2},
+		{name: "unknown name picks first active", accounts: userAccounts, subject: "Bradesco", wantID: 2},
+		{name: "named archived account is rejected", accounts: userAccounts, subject: "Inter", wantErr: errArchivedTargetAccount},
+		{name: "only archived accounts", accounts: userAccounts[:1], subject: "", wantErr: errNoActiveAccounts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := selectInboundTargetAccount(tt.accounts, tt.subject)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, account.AccountID)
+		})
+	}
+}
@@ -12,8 +12,9 @@ interface InviteMemberModalProps {
 
 // Role options matching backend roles
 const ROLE_OPTIONS = [
+  { value: 'viewer', label: 'Visualizador', description: 'Pode apenas visualizar as finanças' },
   { value: 'regular_user', label: 'Usuário', description: 'Pode visualizar e criar transações' },
-  { value: 'regular_manager', label: 'Gerente', description: 'Pode gerenciar categorias e padrões' },
+  { value: 'regular_manager', label: 'Gerente', description: 'Pode gerenciar categorias, orçamentos, padrões e metas' },
   { value: 'admin', label: 'Administrador', description: 'Acesso completo, pode convidar membros' },
 ];
 
@@ -11,6 +11,7 @@ const ROLE_LABELS: Record<string, string> = {
   'admin': 'Administrador',
   'regular_manager': 'Gerente',
   'regular_user': 'Usuário',
+  'viewer': 'Visualizador',
 };
 
 function getRoleLabel(role: string): string {
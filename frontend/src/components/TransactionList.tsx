@@ -273,6 +273,7 @@ export default function TransactionList() {
 
     let totalImported = 0;
     let totalDuplicates = 0;
+    let totalSkipped = 0;
     const failedFiles: string[] = [];
 
     for (const file of ofxFiles) {
@@ -311,8 +312,11 @@ export default function TransactionList() {
           result?.data?.duplicateCount ??
           0;
 
+        const skipped = result?.data?.ErrorCount ?? result?.data?.error_count ?? 0;
+
         totalImported += typeof imported === 'number' ? imported : 0;
         totalDuplicates += typeof duplicates === 'number' ? duplicates : 0;
+        totalSkipped += typeof skipped === 'number' ? skipped : 0;
       } catch (err) {
         failedFiles.push(`${file.name}: ${err instanceof Error ? err.message : 'Erro desconhecido'}`);
       }
@@ -323,9 +327,10 @@ export default function TransactionList() {
       setError(`Falha em ${failedFiles.length} arquivo(s): ${failedFiles.join('; ')}`);
     }
 
-    if (totalImported > 0 || totalDuplicates > 0) {
+    if (totalImported > 0 || totalDuplicates > 0 || totalSkipped > 0) {
       const filesText = ofxFiles.length > 1 ? `${ofxFiles.length} arquivos` : '1 arquivo';
-      setUploadSuccess(`✅ ${filesText}: ${totalImported} transações importadas (${totalDuplicates} duplicadas).`);
+      const skippedText = totalSkipped > 0 ? `, ${totalSkipped} ignoradas por erro de leitura` : '';
+      setUploadSuccess(`✅ ${filesText}: ${totalImported} transações importadas (${totalDuplicates} duplicadas${skippedText}).`);
       setTimeout(() => setUploadSuccess(null), 5000);
     }
 